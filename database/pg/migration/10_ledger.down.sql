@@ -0,0 +1,14 @@
+DROP TRIGGER IF EXISTS trg_ledger_postings_append_only ON ledger_postings;
+DROP TRIGGER IF EXISTS trg_ledger_entries_append_only ON ledger_entries;
+DROP FUNCTION IF EXISTS ledger_reject_change();
+
+DROP INDEX IF EXISTS idx_ledger_postings_account_id;
+DROP INDEX IF EXISTS idx_ledger_postings_entry_id;
+DROP TABLE IF EXISTS ledger_postings;
+
+DROP INDEX IF EXISTS idx_ledger_entries_entry_type_reference_id;
+DROP TABLE IF EXISTS ledger_entries;
+
+DROP INDEX IF EXISTS idx_ledger_accounts_account_type_owner_id;
+DROP INDEX IF EXISTS idx_ledger_accounts_account_code;
+DROP TABLE IF EXISTS ledger_accounts;
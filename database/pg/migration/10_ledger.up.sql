@@ -0,0 +1,54 @@
+CREATE TABLE ledger_accounts (
+                                 id SERIAL PRIMARY KEY,                              -- Ledger account ID
+                                 account_code VARCHAR(100) NOT NULL,                 -- Unique account code, e.g. lender_wallet:12 or platform_escrow
+                                 account_type VARCHAR(50) NOT NULL,                  -- Account type (lender_wallet, loan_receivable, platform_escrow, platform_cash, platform_fee)
+                                 owner_id INT DEFAULT NULL,                          -- Lender ID or loan ID owning the account, NULL for platform accounts
+                                 normal_balance VARCHAR(10) NOT NULL,                -- Side that increases the balance (debit, credit)
+                                 created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,     -- Date of account record creation
+                                 updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,     -- Date of account record update
+                                 deleted_at TIMESTAMP DEFAULT NULL                   -- Date of account record deletion (if applicable)
+);
+
+CREATE UNIQUE INDEX idx_ledger_accounts_account_code ON ledger_accounts (account_code);
+
+CREATE INDEX idx_ledger_accounts_account_type_owner_id ON ledger_accounts (account_type, owner_id);
+
+CREATE TABLE ledger_entries (
+                                id SERIAL PRIMARY KEY,                              -- Journal entry ID
+                                entry_code VARCHAR(50) NOT NULL,                    -- Entry code (generated by the system)
+                                entry_type VARCHAR(50) NOT NULL,                    -- Business event (funding_invested, funding_refunded, loan_disbursed, loan_repaid)
+                                reference_id INT NOT NULL,                          -- ID of the record that caused the entry (funding, loan, repayment)
+                                description VARCHAR(255) NOT NULL DEFAULT '',        -- Human readable description
+                                created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP      -- Date the entry was posted
+);
+
+-- an event is posted at most once
+CREATE UNIQUE INDEX idx_ledger_entries_entry_type_reference_id ON ledger_entries (entry_type, reference_id);
+
+CREATE TABLE ledger_postings (
+                                 id SERIAL PRIMARY KEY,                              -- Posting ID
+                                 entry_id INT NOT NULL REFERENCES ledger_entries (id), -- Journal entry the posting belongs to
+                                 account_id INT NOT NULL REFERENCES ledger_accounts (id), -- Account debited or credited
+                                 direction VARCHAR(10) NOT NULL,                     -- debit or credit
+                                 amount DECIMAL(15, 2) NOT NULL CHECK (amount > 0),  -- Posted amount, always positive
+                                 created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP      -- Date the posting was written
+);
+
+CREATE INDEX idx_ledger_postings_entry_id ON ledger_postings (entry_id);
+
+CREATE INDEX idx_ledger_postings_account_id ON ledger_postings (account_id);
+
+-- the journal is append only, corrections are posted as new entries
+CREATE FUNCTION ledger_reject_change() RETURNS TRIGGER AS $$
+BEGIN
+    RAISE EXCEPTION 'ledger is append only, % on % is not allowed', TG_OP, TG_TABLE_NAME;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER trg_ledger_entries_append_only
+    BEFORE UPDATE OR DELETE ON ledger_entries
+    FOR EACH ROW EXECUTE FUNCTION ledger_reject_change();
+
+CREATE TRIGGER trg_ledger_postings_append_only
+    BEFORE UPDATE OR DELETE ON ledger_postings
+    FOR EACH ROW EXECUTE FUNCTION ledger_reject_change();
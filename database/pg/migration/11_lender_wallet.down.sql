@@ -0,0 +1,10 @@
+DROP INDEX IF EXISTS idx_wallet_withdrawals_status;
+DROP INDEX IF EXISTS idx_wallet_withdrawals_lender_id;
+DROP TABLE IF EXISTS wallet_withdrawals;
+
+DROP INDEX IF EXISTS idx_wallet_transactions_top_up_reference_number;
+DROP INDEX IF EXISTS idx_wallet_transactions_wallet_id;
+DROP TABLE IF EXISTS wallet_transactions;
+
+DROP INDEX IF EXISTS idx_lender_wallets_lender_id;
+DROP TABLE IF EXISTS lender_wallets;
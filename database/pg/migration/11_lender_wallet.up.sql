@@ -0,0 +1,52 @@
+CREATE TABLE lender_wallets (
+                                id SERIAL PRIMARY KEY,                                        -- Wallet ID
+                                lender_id INT NOT NULL,                                       -- Lender owning the wallet
+                                available_balance DECIMAL(15, 2) DEFAULT 0 CHECK (available_balance >= 0), -- Money the lender can still invest or withdraw
+                                held_balance DECIMAL(15, 2) DEFAULT 0 CHECK (held_balance >= 0), -- Money reserved for pending fundings and withdrawals
+                                created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,               -- Date of wallet record creation
+                                updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,               -- Date of wallet record update
+                                deleted_at TIMESTAMP DEFAULT NULL                             -- Date of wallet record deletion (if applicable)
+);
+
+CREATE UNIQUE INDEX idx_lender_wallets_lender_id ON lender_wallets (lender_id);
+
+CREATE TABLE wallet_transactions (
+                                     id SERIAL PRIMARY KEY,                                   -- Wallet transaction ID
+                                     wallet_id INT NOT NULL,                                  -- Wallet ID, linked to the lender_wallets table
+                                     lender_id INT NOT NULL,                                  -- Lender owning the wallet
+                                     transaction_code VARCHAR(50) NOT NULL,                   -- Transaction code (generated by the system)
+                                     transaction_type VARCHAR(50) NOT NULL,                   -- top_up, funding_hold, funding_capture, funding_release, funding_refund, repayment, withdrawal_hold, withdrawal_release, withdrawal
+                                     amount DECIMAL(15, 2) NOT NULL,                          -- Transaction amount
+                                     reference_id INT DEFAULT NULL,                           -- Funding, repayment allocation or withdrawal ID
+                                     reference_number VARCHAR(100) NOT NULL DEFAULT '',       -- Payment reference of a top up
+                                     available_balance_after DECIMAL(15, 2) NOT NULL,         -- Available balance after the transaction
+                                     held_balance_after DECIMAL(15, 2) NOT NULL,              -- Held balance after the transaction
+                                     created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP           -- Date of transaction
+);
+
+CREATE INDEX idx_wallet_transactions_wallet_id ON wallet_transactions (wallet_id);
+
+-- a payment reference can only be topped up once
+CREATE UNIQUE INDEX idx_wallet_transactions_top_up_reference_number ON wallet_transactions (reference_number)
+    WHERE transaction_type = 'top_up';
+
+CREATE TABLE wallet_withdrawals (
+                                    id SERIAL PRIMARY KEY,                                    -- Withdrawal ID
+                                    withdrawal_code VARCHAR(50) NOT NULL,                     -- Withdrawal code (generated by the system)
+                                    lender_id INT NOT NULL,                                   -- Lender requesting the withdrawal
+                                    amount DECIMAL(15, 2) NOT NULL,                           -- Amount to withdraw
+                                    bank_name VARCHAR(100) NOT NULL,                          -- Destination bank
+                                    bank_account_number VARCHAR(50) NOT NULL,                 -- Destination bank account number
+                                    bank_account_name VARCHAR(255) NOT NULL,                  -- Destination bank account holder
+                                    status VARCHAR(50) NOT NULL,                              -- Withdrawal status (pending, approved, rejected)
+                                    reviewed_by VARCHAR(100) NOT NULL DEFAULT '',             -- Staff who approved or rejected the withdrawal
+                                    review_note VARCHAR(255) NOT NULL DEFAULT '',             -- Note of the reviewer
+                                    reviewed_at TIMESTAMP DEFAULT NULL,                       -- Date of review
+                                    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,           -- Date of withdrawal record creation
+                                    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,           -- Date of withdrawal record update
+                                    deleted_at TIMESTAMP DEFAULT NULL                         -- Date of withdrawal record deletion (if applicable)
+);
+
+CREATE INDEX idx_wallet_withdrawals_lender_id ON wallet_withdrawals (lender_id);
+
+CREATE INDEX idx_wallet_withdrawals_status ON wallet_withdrawals (status);
@@ -0,0 +1,11 @@
+DROP INDEX IF EXISTS idx_credit_score_factors_credit_score_id;
+DROP TABLE IF EXISTS credit_score_factors;
+
+DROP INDEX IF EXISTS idx_credit_scores_loan_id;
+DROP TABLE IF EXISTS credit_scores;
+
+DROP INDEX IF EXISTS idx_credit_grades_grade;
+DROP TABLE IF EXISTS credit_grades;
+
+DROP INDEX IF EXISTS idx_credit_scoring_rules_factor;
+DROP TABLE IF EXISTS credit_scoring_rules;
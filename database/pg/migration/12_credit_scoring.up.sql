@@ -0,0 +1,86 @@
+CREATE TABLE credit_scoring_rules (
+                                      id SERIAL PRIMARY KEY,                               -- Rule ID
+                                      factor VARCHAR(50) NOT NULL,                         -- profit_margin, loan_to_revenue, annual_revenue, business_age, business_sector, tenure
+                                      min_value DECIMAL(20, 2) DEFAULT NULL,               -- Inclusive lower bound of the factor value, NULL for no lower bound
+                                      max_value DECIMAL(20, 2) DEFAULT NULL,               -- Exclusive upper bound of the factor value, NULL for no upper bound
+                                      match_value VARCHAR(100) NOT NULL DEFAULT '',        -- Business sector matched by the rule, empty matches every other sector
+                                      points INT NOT NULL,                                 -- Points added to the score when the rule matches
+                                      description VARCHAR(255) NOT NULL DEFAULT '',        -- Description of the rule
+                                      created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,      -- Date of rule record creation
+                                      updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,      -- Date of rule record update
+                                      deleted_at TIMESTAMP DEFAULT NULL                    -- Date of rule record deletion (if applicable)
+);
+
+CREATE INDEX idx_credit_scoring_rules_factor ON credit_scoring_rules (factor);
+
+CREATE TABLE credit_grades (
+                               id SERIAL PRIMARY KEY,                                   -- Grade ID
+                               grade VARCHAR(2) NOT NULL,                               -- Loan grade (A, B, C, D, E)
+                               min_score INT NOT NULL,                                  -- Minimum score to get the grade
+                               created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,          -- Date of grade record creation
+                               updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,          -- Date of grade record update
+                               deleted_at TIMESTAMP DEFAULT NULL                        -- Date of grade record deletion (if applicable)
+);
+
+CREATE UNIQUE INDEX idx_credit_grades_grade ON credit_grades (grade);
+
+CREATE TABLE credit_scores (
+                               id SERIAL PRIMARY KEY,                                   -- Credit score ID
+                               loan_id INT NOT NULL,                                    -- Loan ID, linked to the loans table
+                               score INT NOT NULL,                                      -- Total score of every factor
+                               grade VARCHAR(2) NOT NULL,                               -- Loan grade derived from the score
+                               created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP           -- Date of scoring
+);
+
+CREATE UNIQUE INDEX idx_credit_scores_loan_id ON credit_scores (loan_id);
+
+CREATE TABLE credit_score_factors (
+                                      id SERIAL PRIMARY KEY,                               -- Credit score factor ID
+                                      credit_score_id INT NOT NULL,                        -- Credit score ID, linked to the credit_scores table
+                                      factor VARCHAR(50) NOT NULL,                         -- Scored factor
+                                      value VARCHAR(100) NOT NULL,                         -- Factor value computed from the loan request
+                                      rule_id INT DEFAULT NULL,                            -- Matched rule, NULL when no rule matched
+                                      rule_description VARCHAR(255) NOT NULL DEFAULT '',   -- Description of the matched rule at scoring time
+                                      points INT NOT NULL,                                 -- Points contributed by the factor
+                                      created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP       -- Date of scoring
+);
+
+CREATE INDEX idx_credit_score_factors_credit_score_id ON credit_score_factors (credit_score_id);
+
+-- default rules, a perfect borrower scores 100
+INSERT INTO credit_scoring_rules (factor, min_value, max_value, match_value, points, description)
+VALUES ('profit_margin', NULL, 0, '', 0, 'Business runs at a loss'),
+       ('profit_margin', 0, 10, '', 8, 'Profit margin below 10%'),
+       ('profit_margin', 10, 20, '', 16, 'Profit margin 10% to 20%'),
+       ('profit_margin', 20, 30, '', 21, 'Profit margin 20% to 30%'),
+       ('profit_margin', 30, NULL, '', 25, 'Profit margin 30% or more'),
+       ('loan_to_revenue', NULL, 10, '', 20, 'Loan below 10% of annual revenue'),
+       ('loan_to_revenue', 10, 25, '', 15, 'Loan 10% to 25% of annual revenue'),
+       ('loan_to_revenue', 25, 50, '', 8, 'Loan 25% to 50% of annual revenue'),
+       ('loan_to_revenue', 50, 100, '', 3, 'Loan 50% to 100% of annual revenue'),
+       ('loan_to_revenue', 100, NULL, '', 0, 'Loan above annual revenue'),
+       ('annual_revenue', NULL, 100000000, '', 2, 'Annual revenue below 100 million'),
+       ('annual_revenue', 100000000, 500000000, '', 5, 'Annual revenue 100 to 500 million'),
+       ('annual_revenue', 500000000, 2000000000, '', 8, 'Annual revenue 500 million to 2 billion'),
+       ('annual_revenue', 2000000000, NULL, '', 10, 'Annual revenue 2 billion or more'),
+       ('business_age', NULL, 1, '', 0, 'Business younger than 1 year'),
+       ('business_age', 1, 3, '', 8, 'Business 1 to 3 years old'),
+       ('business_age', 3, 5, '', 14, 'Business 3 to 5 years old'),
+       ('business_age', 5, NULL, '', 20, 'Business 5 years or older'),
+       ('business_sector', NULL, NULL, 'manufacturing', 15, 'Manufacturing sector'),
+       ('business_sector', NULL, NULL, 'trade', 12, 'Trade sector'),
+       ('business_sector', NULL, NULL, 'technology', 12, 'Technology sector'),
+       ('business_sector', NULL, NULL, 'agriculture', 10, 'Agriculture sector'),
+       ('business_sector', NULL, NULL, 'retail', 10, 'Retail sector'),
+       ('business_sector', NULL, NULL, '', 8, 'Other sectors'),
+       ('tenure', NULL, 7, '', 10, 'Tenure up to 6 months'),
+       ('tenure', 7, 13, '', 8, 'Tenure 7 to 12 months'),
+       ('tenure', 13, 25, '', 5, 'Tenure 13 to 24 months'),
+       ('tenure', 25, NULL, '', 2, 'Tenure above 24 months');
+
+INSERT INTO credit_grades (grade, min_score)
+VALUES ('A', 80),
+       ('B', 65),
+       ('C', 50),
+       ('D', 35),
+       ('E', 0);
@@ -0,0 +1,5 @@
+ALTER TABLE loans
+    DROP COLUMN IF EXISTS pricing_id;
+
+DROP INDEX IF EXISTS idx_loan_pricings_grade_type;
+DROP TABLE IF EXISTS loan_pricings;
@@ -0,0 +1,36 @@
+CREATE TABLE loan_pricings (
+                               id SERIAL PRIMARY KEY,                              -- Pricing ID, the pricing version applied to a loan
+                               loan_grade VARCHAR(2) NOT NULL,                     -- Loan grade the pricing applies to (A, B, C, D, E)
+                               loan_type VARCHAR(50) NOT NULL,                     -- Loan type the pricing applies to (productive, consumptive)
+                               min_tenure INT NOT NULL,                            -- Inclusive lower bound of the tenure band in months
+                               max_tenure INT NOT NULL,                            -- Inclusive upper bound of the tenure band in months
+                               borrower_rate DECIMAL(5, 2) NOT NULL,               -- Annual interest rate charged to the borrower
+                               lender_yield DECIMAL(5, 2) NOT NULL,                -- Annual yield paid to lenders, the difference is the platform spread
+                               valid_from TIMESTAMP NOT NULL,                      -- Date the pricing takes effect
+                               valid_until TIMESTAMP DEFAULT NULL,                 -- Date the pricing stops being applied, null when open ended
+                               created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,     -- Date of pricing record creation
+                               updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,     -- Date of pricing record update
+                               deleted_at TIMESTAMP DEFAULT NULL                   -- Date of pricing record deletion (if applicable)
+);
+
+CREATE INDEX idx_loan_pricings_grade_type ON loan_pricings (loan_grade, loan_type);
+
+ALTER TABLE loans
+    ADD COLUMN pricing_id INT DEFAULT NULL; -- Loan pricing applied when the loan was created
+
+-- default pricing, long tenures pay a higher rate and consumptive loans carry a higher risk premium
+INSERT INTO loan_pricings (loan_grade, loan_type, min_tenure, max_tenure, borrower_rate, lender_yield, valid_from)
+SELECT g.grade, t.loan_type, b.min_tenure, b.max_tenure,
+       g.borrower_rate + t.premium + b.premium,
+       g.lender_yield + t.premium + b.premium,
+       CURRENT_TIMESTAMP
+FROM (VALUES ('A', 10.00, 8.00),
+             ('B', 12.00, 9.50),
+             ('C', 14.00, 11.00),
+             ('D', 16.00, 12.50),
+             ('E', 18.00, 14.00)) AS g (grade, borrower_rate, lender_yield)
+         CROSS JOIN (VALUES ('productive', 0.00),
+                            ('consumptive', 2.00)) AS t (loan_type, premium)
+         CROSS JOIN (VALUES (1, 12, 0.00),
+                            (13, 60, 1.00)) AS b (min_tenure, max_tenure, premium)
+ORDER BY g.grade, t.loan_type, b.min_tenure;
@@ -0,0 +1,14 @@
+ALTER TABLE repayment_allocations
+    DROP COLUMN IF EXISTS service_fee;
+
+ALTER TABLE loan_funding
+    DROP COLUMN IF EXISTS service_fee_percentage,
+    DROP COLUMN IF EXISTS service_fee,
+    DROP COLUMN IF EXISTS service_fee_paid;
+
+ALTER TABLE loans_disbursement
+    DROP COLUMN IF EXISTS origination_fee,
+    DROP COLUMN IF EXISTS net_disburse_amount;
+
+DROP INDEX IF EXISTS idx_fee_policies_loan_type;
+DROP TABLE IF EXISTS fee_policies;
@@ -0,0 +1,29 @@
+CREATE TABLE fee_policies (
+                              id SERIAL PRIMARY KEY,                              -- Fee policy ID
+                              loan_type VARCHAR(50) NOT NULL,                     -- Loan type the policy applies to (productive, consumptive)
+                              origination_fee_percentage DECIMAL(5, 2) DEFAULT 0, -- Percentage of the disbursed principal deducted from the borrower payout
+                              service_fee_percentage DECIMAL(5, 2) DEFAULT 0,     -- Percentage of the lender interest taken before it is credited
+                              created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,     -- Date of policy record creation
+                              updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,     -- Date of policy record update
+                              deleted_at TIMESTAMP DEFAULT NULL                   -- Date of policy record deletion (if applicable)
+);
+
+CREATE UNIQUE INDEX idx_fee_policies_loan_type ON fee_policies (loan_type);
+
+INSERT INTO fee_policies (loan_type, origination_fee_percentage, service_fee_percentage)
+VALUES ('productive', 2, 10),
+       ('consumptive', 3, 10);
+
+ALTER TABLE loans_disbursement
+    ADD COLUMN origination_fee DECIMAL(15, 2) DEFAULT 0,      -- Origination fee deducted from the disbursed amount
+    ADD COLUMN net_disburse_amount DECIMAL(15, 2) DEFAULT 0;  -- Amount paid out to the borrower, disburse amount minus origination fee
+
+UPDATE loans_disbursement SET net_disburse_amount = disburse_amount;
+
+ALTER TABLE loan_funding
+    ADD COLUMN service_fee_percentage DECIMAL(5, 2) DEFAULT 0, -- Service fee percentage of the interest, fixed when the funding is invested
+    ADD COLUMN service_fee DECIMAL(15, 2) DEFAULT 0,           -- Service fee expected over the loan tenure
+    ADD COLUMN service_fee_paid DECIMAL(15, 2) DEFAULT 0;      -- Service fee taken from the interest paid so far
+
+ALTER TABLE repayment_allocations
+    ADD COLUMN service_fee DECIMAL(15, 2) DEFAULT 0; -- Service fee taken from the interest allocated to the lender
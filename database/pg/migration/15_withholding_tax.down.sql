@@ -0,0 +1,16 @@
+ALTER TABLE repayment_allocations
+    DROP COLUMN IF EXISTS withholding_tax;
+
+ALTER TABLE loan_funding
+    DROP COLUMN IF EXISTS withholding_tax,
+    DROP COLUMN IF EXISTS withholding_tax_paid;
+
+DROP INDEX IF EXISTS idx_tax_withholdings_lender_withheld_at;
+DROP INDEX IF EXISTS idx_tax_withholdings_allocation_id;
+DROP TABLE IF EXISTS tax_withholdings;
+
+DROP INDEX IF EXISTS idx_withholding_tax_rates_lender_type;
+DROP TABLE IF EXISTS withholding_tax_rates;
+
+DROP INDEX IF EXISTS idx_lender_tax_profiles_lender_id;
+DROP TABLE IF EXISTS lender_tax_profiles;
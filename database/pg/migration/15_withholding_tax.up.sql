@@ -0,0 +1,55 @@
+CREATE TABLE lender_tax_profiles (
+                                     id SERIAL PRIMARY KEY,                          -- Tax profile ID
+                                     lender_id INT NOT NULL,                         -- Lender the profile belongs to
+                                     lender_type VARCHAR(50) NOT NULL,               -- Lender type (individual, institutional)
+                                     tax_id VARCHAR(50) DEFAULT NULL,                -- Tax ID of the lender, NULL when the lender has none
+                                     created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP, -- Date of profile record creation
+                                     updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP, -- Date of profile record update
+                                     deleted_at TIMESTAMP DEFAULT NULL               -- Date of profile record deletion (if applicable)
+);
+
+CREATE UNIQUE INDEX idx_lender_tax_profiles_lender_id ON lender_tax_profiles (lender_id);
+
+CREATE TABLE withholding_tax_rates (
+                                       id SERIAL PRIMARY KEY,                          -- Withholding tax rate ID
+                                       lender_type VARCHAR(50) NOT NULL,               -- Lender type the rate applies to (individual, institutional)
+                                       rate DECIMAL(5, 2) DEFAULT 0,                   -- Percentage withheld from the interest of a lender with a tax ID
+                                       no_tax_id_rate DECIMAL(5, 2) DEFAULT 0,         -- Percentage withheld from the interest of a lender without a tax ID
+                                       created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP, -- Date of rate record creation
+                                       updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP, -- Date of rate record update
+                                       deleted_at TIMESTAMP DEFAULT NULL               -- Date of rate record deletion (if applicable)
+);
+
+CREATE UNIQUE INDEX idx_withholding_tax_rates_lender_type ON withholding_tax_rates (lender_type);
+
+INSERT INTO withholding_tax_rates (lender_type, rate, no_tax_id_rate)
+VALUES ('individual', 15, 30),
+       ('institutional', 15, 30);
+
+CREATE TABLE tax_withholdings (
+                                  id SERIAL PRIMARY KEY,                          -- Withholding ID
+                                  repayment_allocation_id INT NOT NULL,           -- Repayment allocation the tax was withheld from
+                                  repayment_id INT NOT NULL,                      -- Repayment the allocation belongs to
+                                  loan_id INT NOT NULL,                           -- Loan that paid the interest
+                                  loan_funding_id INT NOT NULL,                   -- Funding that earned the interest
+                                  lender_id INT NOT NULL,                         -- Lender that earned the interest
+                                  lender_type VARCHAR(50) NOT NULL,               -- Lender type when the tax was withheld
+                                  tax_id VARCHAR(50) DEFAULT NULL,                -- Tax ID of the lender when the tax was withheld
+                                  taxable_amount DECIMAL(15, 2) NOT NULL,         -- Gross interest the tax is calculated on
+                                  rate DECIMAL(5, 2) NOT NULL,                    -- Percentage withheld
+                                  tax_amount DECIMAL(15, 2) NOT NULL,             -- Tax withheld
+                                  withheld_at TIMESTAMP NOT NULL,                 -- Payment date of the repayment, decides the tax year
+                                  created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP, -- Date of withholding record creation
+                                  updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP, -- Date of withholding record update
+                                  deleted_at TIMESTAMP DEFAULT NULL               -- Date of withholding record deletion (if applicable)
+);
+
+CREATE UNIQUE INDEX idx_tax_withholdings_allocation_id ON tax_withholdings (repayment_allocation_id);
+CREATE INDEX idx_tax_withholdings_lender_withheld_at ON tax_withholdings (lender_id, withheld_at);
+
+ALTER TABLE loan_funding
+    ADD COLUMN withholding_tax DECIMAL(15, 2) DEFAULT 0,      -- Withholding tax expected over the loan tenure
+    ADD COLUMN withholding_tax_paid DECIMAL(15, 2) DEFAULT 0; -- Withholding tax taken from the interest paid so far
+
+ALTER TABLE repayment_allocations
+    ADD COLUMN withholding_tax DECIMAL(15, 2) DEFAULT 0; -- Tax withheld from the interest allocated to the lender
@@ -0,0 +1,13 @@
+CREATE TABLE investment_limits (
+                                   id SERIAL PRIMARY KEY,                               -- Investment limit ID
+                                   min_ticket_amount DECIMAL(15, 2) DEFAULT 0,          -- Minimum amount of a single funding
+                                   ticket_increment DECIMAL(15, 2) DEFAULT 0,           -- Funding amounts must be a multiple of this step, 0 for any amount
+                                   max_loan_share_percentage DECIMAL(5, 2) DEFAULT 100, -- Maximum percentage of the requested amount of a loan a single lender may fund
+                                   max_lender_exposure DECIMAL(15, 2) DEFAULT 0,        -- Maximum outstanding principal of a lender over all loans, 0 for no limit
+                                   created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,      -- Date of limit record creation
+                                   updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,      -- Date of limit record update
+                                   deleted_at TIMESTAMP DEFAULT NULL                    -- Date of limit record deletion (if applicable)
+);
+
+INSERT INTO investment_limits (min_ticket_amount, ticket_increment, max_loan_share_percentage, max_lender_exposure)
+VALUES (100000, 50000, 25, 2000000000);
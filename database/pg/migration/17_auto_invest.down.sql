@@ -0,0 +1,9 @@
+DROP INDEX IF EXISTS idx_auto_invest_run_items_rule_id;
+DROP INDEX IF EXISTS idx_auto_invest_run_items_run_id;
+DROP TABLE IF EXISTS auto_invest_run_items;
+
+DROP INDEX IF EXISTS idx_auto_invest_runs_loan_id;
+DROP TABLE IF EXISTS auto_invest_runs;
+
+DROP INDEX IF EXISTS idx_auto_invest_rules_lender_id;
+DROP TABLE IF EXISTS auto_invest_rules;
@@ -0,0 +1,49 @@
+CREATE TABLE auto_invest_rules (
+                                   id SERIAL PRIMARY KEY,                          -- Auto-invest rule ID
+                                   lender_id INT NOT NULL,                         -- Lender the rule invests for
+                                   lender_email VARCHAR(255) NOT NULL,             -- Email the funding orders of the rule are sent to
+                                   loan_grades TEXT[] DEFAULT NULL,                -- Loan grades the rule invests in, NULL for every grade
+                                   loan_types TEXT[] DEFAULT NULL,                 -- Loan types the rule invests in, NULL for every type
+                                   business_sectors TEXT[] DEFAULT NULL,           -- Business sectors the rule invests in, NULL for every sector
+                                   min_tenure INT DEFAULT 0,                       -- Shortest tenure in months, 0 for no lower bound
+                                   max_tenure INT DEFAULT 0,                       -- Longest tenure in months, 0 for no upper bound
+                                   min_rate DECIMAL(5, 2) DEFAULT 0,               -- Lowest lender yield the rule accepts
+                                   amount_per_loan DECIMAL(15, 2) NOT NULL,        -- Amount ordered in every matching loan
+                                   monthly_budget DECIMAL(15, 2) NOT NULL,         -- Maximum amount ordered by the rule in a calendar month
+                                   is_active BOOLEAN DEFAULT TRUE,                 -- Whether the rule takes part in runs
+                                   last_invested_at TIMESTAMP DEFAULT NULL,        -- Date of the last order of the rule, decides its turn in a run
+                                   created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP, -- Date of rule record creation
+                                   updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP, -- Date of rule record update
+                                   deleted_at TIMESTAMP DEFAULT NULL               -- Date of rule record deletion (if applicable)
+);
+
+CREATE INDEX idx_auto_invest_rules_lender_id ON auto_invest_rules (lender_id);
+
+CREATE TABLE auto_invest_runs (
+                                  id SERIAL PRIMARY KEY,                          -- Auto-invest run ID
+                                  loan_id INT NOT NULL,                           -- Approved loan the rules were run for
+                                  rules_evaluated INT DEFAULT 0,                  -- Number of active rules evaluated
+                                  orders_created INT DEFAULT 0,                   -- Number of funding orders created
+                                  total_ordered DECIMAL(15, 2) DEFAULT 0,         -- Total amount of the funding orders created
+                                  started_at TIMESTAMP NOT NULL,                  -- Date the run started
+                                  finished_at TIMESTAMP DEFAULT NULL,             -- Date the run finished, NULL while running
+                                  created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP, -- Date of run record creation
+                                  updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP  -- Date of run record update
+);
+
+CREATE INDEX idx_auto_invest_runs_loan_id ON auto_invest_runs (loan_id);
+
+CREATE TABLE auto_invest_run_items (
+                                       id SERIAL PRIMARY KEY,                         -- Run item ID
+                                       run_id INT NOT NULL,                           -- Run the rule was evaluated in
+                                       rule_id INT NOT NULL,                          -- Rule evaluated
+                                       lender_id INT NOT NULL,                        -- Lender of the rule
+                                       outcome VARCHAR(50) NOT NULL,                  -- ordered, skipped, failed
+                                       reason VARCHAR(255) DEFAULT '',                -- Why the rule was skipped or failed
+                                       amount DECIMAL(15, 2) DEFAULT 0,               -- Amount ordered, or that would have been ordered
+                                       order_number VARCHAR(50) DEFAULT NULL,         -- Order number of the funding when ordered
+                                       created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP -- Date of item record creation
+);
+
+CREATE INDEX idx_auto_invest_run_items_run_id ON auto_invest_run_items (run_id);
+CREATE INDEX idx_auto_invest_run_items_rule_id ON auto_invest_run_items (rule_id, created_at);
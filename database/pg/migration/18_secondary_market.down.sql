@@ -0,0 +1,13 @@
+DROP INDEX IF EXISTS idx_funding_transfers_buyer_id;
+DROP INDEX IF EXISTS idx_funding_transfers_seller_id;
+DROP INDEX IF EXISTS idx_funding_transfers_listing_id;
+DROP TABLE IF EXISTS funding_transfers;
+
+DROP INDEX IF EXISTS idx_funding_listings_status;
+DROP INDEX IF EXISTS idx_funding_listings_loan_funding_id;
+DROP INDEX IF EXISTS idx_funding_listings_listing_code;
+DROP TABLE IF EXISTS funding_listings;
+
+ALTER TABLE loan_funding
+    DROP COLUMN IF EXISTS transferred_amount,
+    DROP COLUMN IF EXISTS transferred_from_id;
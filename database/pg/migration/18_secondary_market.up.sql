@@ -0,0 +1,43 @@
+ALTER TABLE loan_funding
+    ADD COLUMN transferred_amount DECIMAL(15, 2) DEFAULT 0,  -- Principal sold to other lenders on the secondary market
+    ADD COLUMN transferred_from_id INT DEFAULT NULL;         -- Funding the principal was bought from, NULL for a primary funding
+
+CREATE TABLE funding_listings (
+                                  id SERIAL PRIMARY KEY,                          -- Listing ID
+                                  listing_code VARCHAR(50) NOT NULL,              -- Listing code
+                                  loan_funding_id INT NOT NULL,                   -- Funding offered for sale
+                                  loan_id INT NOT NULL,                           -- Loan of the funding
+                                  seller_id INT NOT NULL,                         -- Lender selling the funding
+                                  principal_amount DECIMAL(15, 2) NOT NULL,       -- Outstanding principal offered for sale
+                                  price DECIMAL(15, 2) NOT NULL,                  -- Price asked for the principal and its remaining interest
+                                  status VARCHAR(50) NOT NULL,                    -- open, sold, cancelled
+                                  created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP, -- Date of listing record creation
+                                  updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP, -- Date of listing record update
+                                  deleted_at TIMESTAMP DEFAULT NULL               -- Date of listing record deletion (if applicable)
+);
+
+CREATE UNIQUE INDEX idx_funding_listings_listing_code ON funding_listings (listing_code);
+CREATE INDEX idx_funding_listings_loan_funding_id ON funding_listings (loan_funding_id);
+CREATE INDEX idx_funding_listings_status ON funding_listings (status);
+
+CREATE TABLE funding_transfers (
+                                   id SERIAL PRIMARY KEY,                          -- Transfer ID
+                                   transfer_code VARCHAR(50) NOT NULL,             -- Transfer code
+                                   listing_id INT NOT NULL,                        -- Listing that was bought
+                                   loan_id INT NOT NULL,                           -- Loan of the fundings
+                                   seller_funding_id INT NOT NULL,                 -- Funding the principal was sold from
+                                   buyer_funding_id INT NOT NULL,                  -- Funding created for the buyer
+                                   seller_id INT NOT NULL,                         -- Lender selling the principal
+                                   buyer_id INT NOT NULL,                          -- Lender buying the principal
+                                   principal_amount DECIMAL(15, 2) NOT NULL,       -- Outstanding principal transferred
+                                   interest_amount DECIMAL(15, 2) NOT NULL,        -- Remaining gross interest transferred with the principal
+                                   price DECIMAL(15, 2) NOT NULL,                  -- Price paid by the buyer to the seller
+                                   premium DECIMAL(15, 2) NOT NULL,                -- Price minus principal, negative for a discount
+                                   transferred_at TIMESTAMP NOT NULL,              -- Date ownership changed
+                                   created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP, -- Date of transfer record creation
+                                   updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP  -- Date of transfer record update
+);
+
+CREATE UNIQUE INDEX idx_funding_transfers_listing_id ON funding_transfers (listing_id);
+CREATE INDEX idx_funding_transfers_seller_id ON funding_transfers (seller_id);
+CREATE INDEX idx_funding_transfers_buyer_id ON funding_transfers (buyer_id);
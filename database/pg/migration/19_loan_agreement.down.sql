@@ -0,0 +1,9 @@
+ALTER TABLE loans
+    DROP COLUMN IF EXISTS agreement_letter_link;
+
+DROP INDEX IF EXISTS idx_loan_agreements_loan_id;
+DROP INDEX IF EXISTS idx_loan_agreements_agreement_number;
+DROP TABLE IF EXISTS loan_agreements;
+
+DROP INDEX IF EXISTS idx_agreement_templates_type_version;
+DROP TABLE IF EXISTS agreement_templates;
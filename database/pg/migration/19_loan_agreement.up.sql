@@ -0,0 +1,60 @@
+CREATE TABLE agreement_templates (
+                                     id SERIAL PRIMARY KEY,                          -- Template ID
+                                     agreement_type VARCHAR(50) NOT NULL,            -- Party the agreement is made with (borrower)
+                                     version INT NOT NULL,                           -- Version of the template within its agreement type
+                                     title VARCHAR(255) NOT NULL,                    -- Title printed on top of the agreement
+                                     body TEXT NOT NULL,                             -- Go text/template body filled with loan, detail and pricing data
+                                     created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP, -- Date of template record creation
+                                     updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP, -- Date of template record update
+                                     deleted_at TIMESTAMP DEFAULT NULL               -- Date of template record deletion (if applicable)
+);
+
+CREATE UNIQUE INDEX idx_agreement_templates_type_version ON agreement_templates (agreement_type, version);
+
+CREATE TABLE loan_agreements (
+                                 id SERIAL PRIMARY KEY,                          -- Agreement ID
+                                 agreement_number VARCHAR(50) NOT NULL,          -- Agreement number printed on the document
+                                 loan_id INT NOT NULL,                           -- Loan the agreement is made for
+                                 template_id INT NOT NULL,                       -- Template the agreement was rendered from
+                                 template_version INT NOT NULL,                  -- Version of the template
+                                 document_key VARCHAR(255) NOT NULL,             -- Key of the document in the document store
+                                 document_url VARCHAR(255) NOT NULL,             -- URL the document can be downloaded from
+                                 generated_at TIMESTAMP NOT NULL,                -- Date the document was generated
+                                 created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP, -- Date of agreement record creation
+                                 updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP  -- Date of agreement record update
+);
+
+CREATE UNIQUE INDEX idx_loan_agreements_agreement_number ON loan_agreements (agreement_number);
+CREATE INDEX idx_loan_agreements_loan_id ON loan_agreements (loan_id);
+
+ALTER TABLE loans
+    ADD COLUMN agreement_letter_link VARCHAR(255) DEFAULT NULL; -- Link to the generated loan agreement letter
+
+-- first borrower agreement, a disbursement can not be created without a template
+INSERT INTO agreement_templates (agreement_type, version, title, body)
+VALUES ('borrower', 1, 'Loan Agreement',
+'# Agreement
+Agreement number: {{.AgreementNumber}}
+Date: {{.AgreementDate}}
+Template version: {{.TemplateVersion}}
+
+# Parties
+This agreement is made between the platform, acting for the lenders funding loan {{.LoanCode}}, and {{.BorrowerName}} (borrower ID {{.BorrowerID}}), owner of {{.BusinessName}}, registration number {{.BusinessRegistrationNumber}}, {{.BusinessAddress}}.
+
+# Loan
+Loan type: {{.LoanType}}
+Loan grade: {{.LoanGrade}}
+Purpose: {{.LoanPurpose}}
+Principal: {{.PrincipalAmount}}
+Origination fee: {{.OriginationFee}}
+Amount received by the borrower: {{.NetDisburseAmount}}
+Annual interest rate: {{.Rate}}%
+Tenure: {{.Tenures}} months
+Total interest: {{.TotalInterest}}
+Total repayment: {{.TotalRepaymentAmount}}
+
+# Repayment
+The borrower repays the total repayment in monthly installments following the repayment schedule issued at disbursement. The origination fee is deducted from the principal paid out, the borrower owes the whole principal.
+
+# Signature
+The loan is disbursed only after the borrower has signed this agreement.');
@@ -0,0 +1,6 @@
+DELETE FROM agreement_templates WHERE agreement_type = 'lender';
+
+DROP INDEX IF EXISTS idx_funding_agreements_lender_id;
+DROP INDEX IF EXISTS idx_funding_agreements_loan_funding_id;
+DROP INDEX IF EXISTS idx_funding_agreements_agreement_number;
+DROP TABLE IF EXISTS funding_agreements;
@@ -0,0 +1,50 @@
+CREATE TABLE funding_agreements (
+                                    id SERIAL PRIMARY KEY,                          -- Agreement ID
+                                    agreement_number VARCHAR(50) NOT NULL,          -- Agreement number printed on the document
+                                    loan_funding_id INT NOT NULL,                   -- Funding the agreement is made for
+                                    loan_id INT NOT NULL,                           -- Loan of the funding
+                                    lender_id INT NOT NULL,                         -- Lender of the funding
+                                    template_id INT NOT NULL,                       -- Template the agreement was rendered from
+                                    template_version INT NOT NULL,                  -- Version of the template
+                                    document_key VARCHAR(255) NOT NULL,             -- Key of the document in the document store
+                                    document_url VARCHAR(255) NOT NULL,             -- URL the document can be downloaded from
+                                    generated_at TIMESTAMP NOT NULL,                -- Date the document was generated
+                                    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP, -- Date of agreement record creation
+                                    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP  -- Date of agreement record update
+);
+
+CREATE UNIQUE INDEX idx_funding_agreements_agreement_number ON funding_agreements (agreement_number);
+CREATE INDEX idx_funding_agreements_loan_funding_id ON funding_agreements (loan_funding_id);
+CREATE INDEX idx_funding_agreements_lender_id ON funding_agreements (lender_id);
+
+-- first lender agreement, a funding can not be invested without a template
+INSERT INTO agreement_templates (agreement_type, version, title, body)
+VALUES ('lender', 1, 'Investment Agreement',
+'# Agreement
+Agreement number: {{.AgreementNumber}}
+Date: {{.AgreementDate}}
+Template version: {{.TemplateVersion}}
+
+# Parties
+This agreement is made between the platform and lender {{.LenderID}} ({{.LenderEmail}}) for the funding {{.LoanOrderNumber}} of loan {{.LoanCode}}.
+
+# Investment
+Amount invested: {{.InvestmentAmount}}
+Annual return rate: {{.Rate}}%
+Tenure: {{.Tenures}} months
+Gross interest: {{.Interest}}
+Service fee ({{.ServiceFeePercentage}}% of the interest): {{.ServiceFee}}
+Estimated withholding tax: {{.WithholdingTax}}
+Expected return: {{.ROI}}
+
+# Borrower
+Business: {{.BusinessName}}
+Sector: {{.BusinessSector}}
+Business age: {{.BusinessAge}} years
+Loan type: {{.LoanType}}
+Loan grade: {{.LoanGrade}}
+Purpose: {{.LoanPurpose}}
+Loan amount: {{.LoanAmount}}
+
+# Risk
+The return depends on the borrower repaying the loan. The principal and the interest are not guaranteed by the platform. The withholding tax is an estimate, the actual tax is withheld on every repayment.');
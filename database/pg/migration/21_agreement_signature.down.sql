@@ -0,0 +1,6 @@
+DROP INDEX IF EXISTS idx_agreement_signatures_loan_disbursement_id;
+DROP INDEX IF EXISTS idx_agreement_signatures_provider_reference;
+DROP TABLE IF EXISTS agreement_signatures;
+
+ALTER TABLE loan_agreements
+    DROP COLUMN IF EXISTS document_hash;
@@ -0,0 +1,23 @@
+ALTER TABLE loan_agreements
+    ADD COLUMN document_hash VARCHAR(64) DEFAULT NULL; -- SHA-256 of the generated document, hex encoded
+
+CREATE TABLE agreement_signatures (
+                                      id SERIAL PRIMARY KEY,                          -- Signature request ID
+                                      loan_agreement_id INT NOT NULL,                 -- Agreement to be signed
+                                      loan_disbursement_id INT NOT NULL,              -- Disbursement waiting for the signature
+                                      loan_id INT NOT NULL,                           -- Loan of the agreement
+                                      signer_id INT NOT NULL,                         -- Borrower expected to sign
+                                      provider VARCHAR(50) NOT NULL,                  -- E-sign provider handling the request
+                                      provider_reference VARCHAR(100) NOT NULL,       -- Reference of the request at the provider
+                                      signing_url VARCHAR(255) NOT NULL,              -- URL the borrower signs the agreement at
+                                      status VARCHAR(50) NOT NULL,                    -- requested, signed, rejected
+                                      signed_by VARCHAR(100) DEFAULT NULL,            -- Signer identity reported by the provider
+                                      signed_at TIMESTAMP DEFAULT NULL,               -- Date the agreement was signed
+                                      signed_document_hash VARCHAR(64) DEFAULT NULL,  -- SHA-256 of the document that was signed, hex encoded
+                                      rejection_reason VARCHAR(255) DEFAULT NULL,     -- Why the signature was not accepted
+                                      created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP, -- Date of signature record creation
+                                      updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP  -- Date of signature record update
+);
+
+CREATE UNIQUE INDEX idx_agreement_signatures_provider_reference ON agreement_signatures (provider, provider_reference);
+CREATE INDEX idx_agreement_signatures_loan_disbursement_id ON agreement_signatures (loan_disbursement_id);
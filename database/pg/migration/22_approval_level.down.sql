@@ -0,0 +1,7 @@
+DROP TABLE IF EXISTS approval_steps;
+
+ALTER TABLE loans_approval
+    DROP COLUMN IF EXISTS approved_level,
+    DROP COLUMN IF EXISTS required_level;
+
+DROP TABLE IF EXISTS approval_levels;
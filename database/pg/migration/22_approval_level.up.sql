@@ -0,0 +1,37 @@
+CREATE TABLE approval_levels (
+                                 id SERIAL PRIMARY KEY,                          -- Approval level ID
+                                 level INT NOT NULL,                             -- Position of the level in the approval chain, starting at 1
+                                 name VARCHAR(100) NOT NULL,                     -- Name of the approvers of the level (analyst, credit manager, committee)
+                                 min_amount DECIMAL(15, 2) DEFAULT 0,            -- The level is required for loans requesting at least this amount
+                                 min_grade VARCHAR(1) DEFAULT NULL,              -- The level is also required for loans graded this grade or worse, NULL for amount only
+                                 created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP, -- Date of level record creation
+                                 updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP, -- Date of level record update
+                                 deleted_at TIMESTAMP DEFAULT NULL               -- Date of level record deletion (if applicable)
+);
+
+CREATE UNIQUE INDEX idx_approval_levels_level ON approval_levels (level);
+
+INSERT INTO approval_levels (level, name, min_amount, min_grade)
+VALUES (1, 'Analyst', 0, NULL),
+       (2, 'Credit Manager', 50000000, 'D'),
+       (3, 'Credit Committee', 500000000, 'E');
+
+ALTER TABLE loans_approval
+    ADD COLUMN required_level INT DEFAULT 1, -- Number of levels that must approve, fixed when the approval is created
+    ADD COLUMN approved_level INT DEFAULT 0; -- Highest level that approved so far
+
+CREATE TABLE approval_steps (
+                                id SERIAL PRIMARY KEY,                          -- Approval step ID
+                                loan_approval_id INT NOT NULL,                  -- Loan approval the step belongs to
+                                level INT NOT NULL,                             -- Approval level decided by the step
+                                staff_id INT NOT NULL,                          -- Staff ID who decided the step
+                                decision VARCHAR(50) NOT NULL,                  -- Decision of the staff (approved, rejected)
+                                decided_at TIMESTAMP NOT NULL,                  -- Date of the decision
+                                created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP, -- Date of step record creation
+                                updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP, -- Date of step record update
+                                deleted_at TIMESTAMP DEFAULT NULL               -- Date of step record deletion (if applicable)
+);
+
+CREATE UNIQUE INDEX idx_approval_steps_approval_level ON approval_steps (loan_approval_id, level);
+
+CREATE UNIQUE INDEX idx_approval_steps_approval_staff ON approval_steps (loan_approval_id, staff_id);
@@ -0,0 +1,22 @@
+CREATE TABLE document_requirements (
+                                       id SERIAL PRIMARY KEY,                          -- Document requirement ID
+                                       loan_type VARCHAR(50) NOT NULL,                 -- Loan type the requirement applies to (productive, consumptive)
+                                       document_type VARCHAR(50) NOT NULL,             -- Document type attached to the approval (approval_documents.document_type)
+                                       name VARCHAR(100) NOT NULL,                     -- Name of the document reported when it is missing
+                                       mandatory BOOLEAN DEFAULT TRUE,                 -- Loans of the type can not be approved without the document
+                                       created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP, -- Date of requirement record creation
+                                       updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP, -- Date of requirement record update
+                                       deleted_at TIMESTAMP DEFAULT NULL               -- Date of requirement record deletion (if applicable)
+);
+
+CREATE UNIQUE INDEX idx_document_requirements_loan_type_document_type ON document_requirements (loan_type, document_type) WHERE deleted_at IS NULL;
+
+INSERT INTO document_requirements (loan_type, document_type, name, mandatory)
+VALUES ('productive', 'business_photo', 'Business Photo', TRUE),
+       ('productive', 'tax_id', 'Tax ID (NPWP)', TRUE),
+       ('productive', 'bank_statement', 'Bank Statements', TRUE),
+       ('productive', 'store_document', 'Store Document', FALSE),
+       ('productive', 'home_visited', 'Home Visit Report', FALSE),
+       ('consumptive', 'id_card', 'ID Card (KTP)', TRUE),
+       ('consumptive', 'payslip', 'Payslip', TRUE),
+       ('consumptive', 'bank_statement', 'Bank Statements', TRUE);
@@ -0,0 +1,4 @@
+ALTER TABLE approval_documents
+    DROP COLUMN IF EXISTS document_id;
+
+DROP TABLE IF EXISTS documents;
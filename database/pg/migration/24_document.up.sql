@@ -0,0 +1,22 @@
+CREATE TABLE documents (
+                           id SERIAL PRIMARY KEY,                          -- Document ID
+                           storage_key VARCHAR(255) NOT NULL,              -- Key of the file in the document store
+                           file_name VARCHAR(255) NOT NULL,                -- File name given by the uploader
+                           content_type VARCHAR(100) NOT NULL,             -- Content type detected from the file content
+                           size BIGINT NOT NULL,                           -- File size in bytes
+                           checksum VARCHAR(64) NOT NULL,                  -- SHA-256 of the file content, hex encoded
+                           entity_type VARCHAR(50) NOT NULL,               -- Record the document belongs to (loan, loan_approval, loan_funding, loan_disbursement)
+                           entity_id INT NOT NULL,                         -- ID of the record the document belongs to
+                           document_type VARCHAR(50) NOT NULL,             -- Kind of document (tax_id, bank_statement, loan_agreement, ...)
+                           uploaded_by INT DEFAULT NULL,                   -- Staff ID who uploaded the document, NULL for generated documents
+                           created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP, -- Date of document record creation
+                           updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP, -- Date of document record update
+                           deleted_at TIMESTAMP DEFAULT NULL               -- Date of document record deletion (if applicable)
+);
+
+CREATE UNIQUE INDEX idx_documents_storage_key ON documents (storage_key);
+
+CREATE INDEX idx_documents_entity ON documents (entity_type, entity_id);
+
+ALTER TABLE approval_documents
+    ADD COLUMN document_id INT DEFAULT NULL; -- Uploaded document the file URL points to, NULL for external URLs
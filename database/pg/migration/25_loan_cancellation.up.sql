@@ -0,0 +1,3 @@
+ALTER TABLE loans
+    ADD COLUMN cancellation_reason TEXT DEFAULT NULL,    -- Reason given by the borrower when the loan was cancelled
+    ADD COLUMN cancelled_at TIMESTAMP DEFAULT NULL;      -- Date of loan cancellation (if applicable)
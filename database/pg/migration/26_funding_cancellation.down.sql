@@ -0,0 +1,2 @@
+ALTER TABLE funding_policies
+    DROP COLUMN IF EXISTS cooling_off_hours;
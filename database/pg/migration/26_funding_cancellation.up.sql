@@ -0,0 +1,2 @@
+ALTER TABLE funding_policies
+    ADD COLUMN cooling_off_hours INT DEFAULT 24; -- Hours after investing during which the lender can still cancel the funding
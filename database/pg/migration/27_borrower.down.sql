@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS borrower_kyc_histories;
+DROP TABLE IF EXISTS borrowers;
@@ -0,0 +1,40 @@
+CREATE TABLE borrowers (
+                           id SERIAL PRIMARY KEY,                          -- Borrower ID
+                           full_name VARCHAR(255) NOT NULL,                -- Full name of the borrower as on the national ID
+                           national_id VARCHAR(50) NOT NULL,               -- National ID number (NIK)
+                           date_of_birth DATE NOT NULL,                    -- Date of birth
+                           phone_number VARCHAR(20) NOT NULL,              -- Phone number of the borrower
+                           email VARCHAR(255) NOT NULL,                    -- Email address of the borrower
+                           address TEXT NOT NULL,                          -- Residential address
+                           business_name VARCHAR(255),                     -- Business name of the borrower
+                           business_type VARCHAR(100),                     -- Type of business (e.g., retail, manufacturing)
+                           business_address TEXT,                          -- Business address
+                           business_phone_number VARCHAR(20),              -- Business phone number
+                           business_email VARCHAR(255),                    -- Business email address
+                           business_registration_number VARCHAR(50),       -- Business registration number
+                           business_owner_name VARCHAR(255),               -- Business owner's name
+                           business_description TEXT,                      -- Business description
+                           business_sector VARCHAR(100),                   -- Business sector (e.g., agriculture, technology)
+                           business_established_year INT,                  -- Year the business was established
+                           kyc_status VARCHAR(50) DEFAULT 'unverified',    -- KYC status (unverified, pending, verified, rejected)
+                           kyc_verified_at TIMESTAMP DEFAULT NULL,         -- Date the KYC was verified (if applicable)
+                           created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP, -- Date of borrower record creation
+                           updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP, -- Date of borrower record update
+                           deleted_at TIMESTAMP DEFAULT NULL               -- Date of borrower record deletion (if applicable)
+);
+
+CREATE UNIQUE INDEX idx_borrowers_national_id ON borrowers (national_id) WHERE deleted_at IS NULL;
+
+CREATE TABLE borrower_kyc_histories (
+                                        id SERIAL PRIMARY KEY,                          -- KYC history ID
+                                        borrower_id INT NOT NULL,                       -- Borrower ID, linking to the borrowers table
+                                        from_status VARCHAR(50) NOT NULL,               -- KYC status before the change
+                                        to_status VARCHAR(50) NOT NULL,                 -- KYC status after the change
+                                        staff_id INT DEFAULT NULL,                      -- Staff ID who reviewed the KYC, NULL for changes made by the borrower
+                                        note TEXT,                                      -- Reason of the change
+                                        created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP, -- Date of history record creation
+                                        updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP, -- Date of history record update
+                                        deleted_at TIMESTAMP DEFAULT NULL               -- Date of history record deletion (if applicable)
+);
+
+CREATE INDEX idx_borrower_kyc_histories_borrower_id ON borrower_kyc_histories (borrower_id);
@@ -0,0 +1,5 @@
+ALTER TABLE auto_invest_rules
+    ADD COLUMN lender_email VARCHAR(255);                -- Email the funding orders of the rule are sent to
+
+DROP TABLE IF EXISTS lender_kyc_histories;
+DROP TABLE IF EXISTS lenders;
@@ -0,0 +1,40 @@
+CREATE TABLE lenders (
+                         id SERIAL PRIMARY KEY,                           -- Lender ID
+                         full_name VARCHAR(255) NOT NULL,                 -- Full name of the lender, or the company name of an institution
+                         national_id VARCHAR(50) NOT NULL,                -- National ID number (NIK), or the registration number of an institution
+                         date_of_birth DATE DEFAULT NULL,                 -- Date of birth, NULL for institutions
+                         phone_number VARCHAR(20) NOT NULL,               -- Phone number of the lender
+                         email VARCHAR(255) NOT NULL,                     -- Preferred email, used for every funding of the lender
+                         address TEXT NOT NULL,                           -- Address of the lender
+                         investor_class VARCHAR(50) DEFAULT 'retail',     -- Investor classification (retail, accredited)
+                         classified_by INT DEFAULT NULL,                  -- Staff ID who set the investor classification
+                         classified_at TIMESTAMP DEFAULT NULL,            -- Date the investor classification was set
+                         risk_profile VARCHAR(50) DEFAULT NULL,           -- Risk profile from the suitability questionnaire (conservative, moderate, aggressive)
+                         suitability_score INT DEFAULT NULL,              -- Score of the suitability questionnaire
+                         suitability_assessed_at TIMESTAMP DEFAULT NULL,  -- Date the suitability questionnaire was answered
+                         kyc_status VARCHAR(50) DEFAULT 'unverified',     -- KYC status (unverified, pending, verified, rejected)
+                         kyc_verified_at TIMESTAMP DEFAULT NULL,          -- Date the KYC was verified (if applicable)
+                         created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,  -- Date of lender record creation
+                         updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,  -- Date of lender record update
+                         deleted_at TIMESTAMP DEFAULT NULL                -- Date of lender record deletion (if applicable)
+);
+
+CREATE UNIQUE INDEX idx_lenders_national_id ON lenders (national_id) WHERE deleted_at IS NULL;
+
+CREATE TABLE lender_kyc_histories (
+                                      id SERIAL PRIMARY KEY,                          -- KYC history ID
+                                      lender_id INT NOT NULL,                         -- Lender ID, linking to the lenders table
+                                      from_status VARCHAR(50) NOT NULL,               -- KYC status before the change
+                                      to_status VARCHAR(50) NOT NULL,                 -- KYC status after the change
+                                      staff_id INT DEFAULT NULL,                      -- Staff ID who reviewed the KYC, NULL for changes made by the lender
+                                      note TEXT,                                      -- Reason of the change
+                                      created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP, -- Date of history record creation
+                                      updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP, -- Date of history record update
+                                      deleted_at TIMESTAMP DEFAULT NULL               -- Date of history record deletion (if applicable)
+);
+
+CREATE INDEX idx_lender_kyc_histories_lender_id ON lender_kyc_histories (lender_id);
+
+-- fundings ordered by auto-invest take the preferred email of the lender
+ALTER TABLE auto_invest_rules
+    DROP COLUMN IF EXISTS lender_email;
@@ -0,0 +1,2 @@
+ALTER TABLE loan_funding
+    DROP COLUMN IF EXISTS invested_at;
@@ -0,0 +1,7 @@
+ALTER TABLE loan_funding
+    ADD COLUMN invested_at TIMESTAMP DEFAULT NULL; -- Date the funding process invested the funding, the cooling-off period starts here
+
+-- an invested funding is last updated by the funding process
+UPDATE loan_funding
+SET invested_at = updated_at
+WHERE status = 'invested';
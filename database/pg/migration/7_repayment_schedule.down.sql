@@ -0,0 +1,5 @@
+DROP INDEX IF EXISTS idx_repayment_schedules_loan_id;
+DROP INDEX IF EXISTS idx_repayment_schedules_status;
+DROP INDEX IF EXISTS idx_repayment_schedules_loan_id_installment_number;
+
+DROP TABLE IF EXISTS repayment_schedules;
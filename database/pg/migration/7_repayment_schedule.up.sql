@@ -0,0 +1,20 @@
+CREATE TABLE repayment_schedules (
+                                     id SERIAL PRIMARY KEY,                              -- Repayment schedule ID
+                                     loan_id INT NOT NULL,                               -- Loan ID, linked to the loans table
+                                     installment_number INT NOT NULL,                    -- Installment sequence (1..tenures)
+                                     due_date DATE NOT NULL,                             -- Installment due date
+                                     principal_amount DECIMAL(15, 2) DEFAULT 0,          -- Principal portion of the installment
+                                     interest_amount DECIMAL(15, 2) DEFAULT 0,           -- Interest portion of the installment
+                                     total_amount DECIMAL(15, 2) DEFAULT 0,              -- Principal + interest of the installment
+                                     outstanding_balance DECIMAL(15, 2) DEFAULT 0,       -- Remaining principal after this installment is paid
+                                     status VARCHAR(50) NOT NULL,                        -- Installment status (pending, paid)
+                                     created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,     -- Date of schedule record creation
+                                     updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,     -- Date of schedule record update
+                                     deleted_at TIMESTAMP DEFAULT NULL                   -- Date of schedule record deletion (if applicable)
+);
+
+CREATE INDEX idx_repayment_schedules_loan_id ON repayment_schedules (loan_id);
+
+CREATE INDEX idx_repayment_schedules_status ON repayment_schedules (status);
+
+CREATE UNIQUE INDEX idx_repayment_schedules_loan_id_installment_number ON repayment_schedules (loan_id, installment_number);
@@ -0,0 +1,13 @@
+DROP INDEX IF EXISTS idx_repayment_allocations_repayment_id;
+DROP INDEX IF EXISTS idx_repayment_allocations_loan_funding_id;
+DROP INDEX IF EXISTS idx_repayment_allocations_lender_id;
+DROP TABLE IF EXISTS repayment_allocations;
+
+DROP INDEX IF EXISTS idx_loan_repayments_loan_id;
+DROP INDEX IF EXISTS idx_loan_repayments_repayment_code;
+DROP TABLE IF EXISTS loan_repayments;
+
+ALTER TABLE repayment_schedules
+    DROP COLUMN IF EXISTS principal_paid,
+    DROP COLUMN IF EXISTS interest_paid,
+    DROP COLUMN IF EXISTS paid_at;
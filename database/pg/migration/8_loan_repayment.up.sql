@@ -0,0 +1,41 @@
+ALTER TABLE repayment_schedules
+    ADD COLUMN principal_paid DECIMAL(15, 2) DEFAULT 0,  -- Principal already paid for this installment
+    ADD COLUMN interest_paid DECIMAL(15, 2) DEFAULT 0,   -- Interest already paid for this installment
+    ADD COLUMN paid_at TIMESTAMP DEFAULT NULL;           -- Date the installment was fully paid
+
+CREATE TABLE loan_repayments (
+                                 id SERIAL PRIMARY KEY,                              -- Repayment ID
+                                 loan_id INT NOT NULL,                               -- Loan ID, linked to the loans table
+                                 repayment_code VARCHAR(50) NOT NULL,                -- Repayment code (generated by the system)
+                                 reference_number VARCHAR(100) NOT NULL,             -- Payment reference from the payment channel
+                                 amount DECIMAL(15, 2) NOT NULL,                     -- Amount paid by the borrower
+                                 principal_amount DECIMAL(15, 2) DEFAULT 0,          -- Portion of the payment allocated to principal
+                                 interest_amount DECIMAL(15, 2) DEFAULT 0,           -- Portion of the payment allocated to interest
+                                 payment_date TIMESTAMP NOT NULL,                    -- Date the borrower paid
+                                 created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,     -- Date of repayment record creation
+                                 updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,     -- Date of repayment record update
+                                 deleted_at TIMESTAMP DEFAULT NULL                   -- Date of repayment record deletion (if applicable)
+);
+
+CREATE INDEX idx_loan_repayments_loan_id ON loan_repayments (loan_id);
+
+CREATE INDEX idx_loan_repayments_repayment_code ON loan_repayments (repayment_code);
+
+CREATE TABLE repayment_allocations (
+                                       id SERIAL PRIMARY KEY,                          -- Allocation ID
+                                       repayment_id INT NOT NULL,                      -- Repayment ID, linked to the loan_repayments table
+                                       loan_funding_id INT NOT NULL,                   -- Funding ID, linked to the loan_funding table
+                                       lender_id INT NOT NULL,                         -- Lender receiving the allocation
+                                       principal_amount DECIMAL(15, 2) DEFAULT 0,      -- Principal allocated to the lender
+                                       interest_amount DECIMAL(15, 2) DEFAULT 0,       -- Interest allocated to the lender
+                                       total_amount DECIMAL(15, 2) DEFAULT 0,          -- Principal + interest allocated to the lender
+                                       created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP, -- Date of allocation record creation
+                                       updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP, -- Date of allocation record update
+                                       deleted_at TIMESTAMP DEFAULT NULL               -- Date of allocation record deletion (if applicable)
+);
+
+CREATE INDEX idx_repayment_allocations_repayment_id ON repayment_allocations (repayment_id);
+
+CREATE INDEX idx_repayment_allocations_loan_funding_id ON repayment_allocations (loan_funding_id);
+
+CREATE INDEX idx_repayment_allocations_lender_id ON repayment_allocations (lender_id);
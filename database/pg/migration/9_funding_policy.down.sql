@@ -0,0 +1,5 @@
+DROP INDEX IF EXISTS idx_funding_policies_loan_type;
+DROP TABLE IF EXISTS funding_policies;
+
+ALTER TABLE loans
+    DROP COLUMN IF EXISTS partial_funding_consent;
@@ -0,0 +1,18 @@
+ALTER TABLE loans
+    ADD COLUMN partial_funding_consent BOOLEAN DEFAULT FALSE; -- Borrower agrees to disburse a partially funded loan
+
+CREATE TABLE funding_policies (
+                                  id SERIAL PRIMARY KEY,                              -- Funding policy ID
+                                  loan_type VARCHAR(50) NOT NULL,                     -- Loan type the policy applies to (productive, consumptive)
+                                  min_funded_percentage DECIMAL(5, 2) DEFAULT 100,    -- Minimum funded percentage at deadline to still disburse
+                                  require_borrower_consent BOOLEAN DEFAULT FALSE,     -- Partial disbursement needs borrower consent
+                                  created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,     -- Date of policy record creation
+                                  updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,     -- Date of policy record update
+                                  deleted_at TIMESTAMP DEFAULT NULL                   -- Date of policy record deletion (if applicable)
+);
+
+CREATE UNIQUE INDEX idx_funding_policies_loan_type ON funding_policies (loan_type);
+
+INSERT INTO funding_policies (loan_type, min_funded_percentage, require_borrower_consent)
+VALUES ('productive', 80, FALSE),
+       ('consumptive', 80, TRUE);
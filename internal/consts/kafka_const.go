@@ -6,4 +6,5 @@ const (
 	ApprovalLoanTopic   KafkaTopic = "loan-approval-topic"
 	LoanDisburseTopic   KafkaTopic = "loan-disburse-topic"
 	FundingProcessTopic KafkaTopic = "funding-process-topic"
+	LoanCompletedTopic  KafkaTopic = "loan-completed-topic"
 )
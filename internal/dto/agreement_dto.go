@@ -0,0 +1,46 @@
+package dto
+
+import (
+	"github.com/test/loan-service/internal/enum"
+	"time"
+)
+
+type AgreementTemplateRequestDTO struct {
+	AgreementType enum.AgreementType `json:"agreement_type" valid:"required"` // Party the agreement is made with (borrower, lender)
+	Title         string             `json:"title" valid:"required"`          // Title printed on top of the agreement
+	Body          string             `json:"body" valid:"required"`           // Go text/template body, see the documentation for the placeholders
+}
+
+type AgreementTemplateResponseDTO struct {
+	ID            int64              `json:"id"`             // Template ID
+	AgreementType enum.AgreementType `json:"agreement_type"` // Party the agreement is made with
+	Version       int64              `json:"version"`        // Version of the template within its agreement type
+	Title         string             `json:"title"`          // Title printed on top of the agreement
+	Body          string             `json:"body"`           // Go text/template body
+	CreatedAt     time.Time          `json:"created_at"`     // Date of creation
+	UpdatedAt     time.Time          `json:"updated_at"`     // Date of last update
+	DeletedAt     *time.Time         `json:"deleted_at"`     // Date of deletion if applicable
+}
+
+type LoanAgreementResponseDTO struct {
+	ID              int64     `json:"id"`               // Agreement ID
+	AgreementNumber string    `json:"agreement_number"` // Agreement number printed on the document
+	LoanID          int64     `json:"loan_id"`          // Loan the agreement is made for
+	TemplateID      int64     `json:"template_id"`      // Template the agreement was rendered from
+	TemplateVersion int64     `json:"template_version"` // Version of the template
+	DocumentURL     string    `json:"document_url"`     // URL the document can be downloaded from
+	DocumentHash    *string   `json:"document_hash"`    // SHA-256 of the document, signatures are verified against it
+	GeneratedAt     time.Time `json:"generated_at"`     // Date the document was generated
+}
+
+type FundingAgreementResponseDTO struct {
+	ID              int64     `json:"id"`               // Agreement ID
+	AgreementNumber string    `json:"agreement_number"` // Agreement number printed on the document
+	LoanFundingID   int64     `json:"loan_funding_id"`  // Funding the agreement is made for
+	LoanID          int64     `json:"loan_id"`          // Loan of the funding
+	LenderID        int64     `json:"lender_id"`        // Lender of the funding
+	TemplateID      int64     `json:"template_id"`      // Template the agreement was rendered from
+	TemplateVersion int64     `json:"template_version"` // Version of the template
+	DocumentURL     string    `json:"document_url"`     // URL the document can be downloaded from
+	GeneratedAt     time.Time `json:"generated_at"`     // Date the document was generated
+}
@@ -6,6 +6,7 @@ type ApprovalDocumentRequestDTO struct {
 	DocumentType string  `json:"document_type" validate:"required"`
 	FileURL      string  `json:"file_url" validate:"required"`
 	Description  *string `json:"description,omitempty"`
+	DocumentID   *int64  `json:"document_id,omitempty"`
 }
 
 type ApprovalDocumentResponseDTO struct {
@@ -14,6 +15,7 @@ type ApprovalDocumentResponseDTO struct {
 	DocumentType   string     `json:"document_type"`
 	FileURL        string     `json:"file_url"`
 	Description    *string    `json:"description,omitempty"`
+	DocumentID     *int64     `json:"document_id,omitempty"`
 	CreatedAt      time.Time  `json:"created_at"`
 	UpdatedAt      time.Time  `json:"updated_at"`
 	DeletedAt      *time.Time `json:"deleted_at,omitempty"`
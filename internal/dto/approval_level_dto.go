@@ -0,0 +1,33 @@
+package dto
+
+import (
+	"github.com/test/loan-service/internal/enum"
+	"github.com/test/loan-service/internal/money"
+	"time"
+)
+
+type ApprovalLevelRequestDTO struct {
+	Name      string          `json:"name" valid:"required"` // Name of the approvers of the level
+	MinAmount money.Amount    `json:"min_amount"`            // The level is required for loans requesting at least this amount
+	MinGrade  *enum.LoanGrade `json:"min_grade"`             // The level is also required for loans graded this grade or worse, empty for amount only
+}
+
+type ApprovalLevelResponseDTO struct {
+	ID        int64           `json:"id"`                   // Approval level ID
+	Level     int64           `json:"level"`                // Position of the level in the approval chain, starting at 1
+	Name      string          `json:"name"`                 // Name of the approvers of the level
+	MinAmount money.Amount    `json:"min_amount"`           // The level is required for loans requesting at least this amount
+	MinGrade  *enum.LoanGrade `json:"min_grade,omitempty"`  // The level is also required for loans graded this grade or worse
+	CreatedAt time.Time       `json:"created_at"`           // Date of creation
+	UpdatedAt time.Time       `json:"updated_at"`           // Date of last update
+	DeletedAt *time.Time      `json:"deleted_at,omitempty"` // Date of deletion if applicable
+}
+
+type ApprovalStepResponseDTO struct {
+	ID             int64               `json:"id"`               // Approval step ID
+	LoanApprovalID int64               `json:"loan_approval_id"` // Loan approval the step belongs to
+	Level          int64               `json:"level"`            // Approval level decided by the step
+	StaffID        int64               `json:"staff_id"`         // Staff who decided the step
+	Decision       enum.ApprovalStatus `json:"decision"`         // approved, rejected
+	DecidedAt      time.Time           `json:"decided_at"`       // Date of the decision
+}
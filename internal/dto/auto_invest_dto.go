@@ -0,0 +1,59 @@
+package dto
+
+import (
+	"github.com/test/loan-service/internal/enum"
+	"github.com/test/loan-service/internal/money"
+	"time"
+)
+
+type AutoInvestRuleRequestDTO struct {
+	LenderID        int64            `json:"-"`                                // Lender ID, taken from the path on creation
+	LoanGrades      []enum.LoanGrade `json:"loan_grades"`                      // Loan grades the rule invests in, empty for every grade
+	LoanTypes       []enum.LoanType  `json:"loan_types"`                       // Loan types the rule invests in, empty for every type
+	BusinessSectors []string         `json:"business_sectors"`                 // Business sectors the rule invests in, empty for every sector
+	MinTenure       int64            `json:"min_tenure"`                       // Shortest tenure in months, zero for no lower bound
+	MaxTenure       int64            `json:"max_tenure"`                       // Longest tenure in months, zero for no upper bound
+	MinRate         float64          `json:"min_rate"`                         // Lowest lender yield the rule accepts
+	AmountPerLoan   money.Amount     `json:"amount_per_loan" valid:"required"` // Amount ordered in every matching loan
+	MonthlyBudget   money.Amount     `json:"monthly_budget" valid:"required"`  // Maximum amount ordered by the rule in a calendar month
+	IsActive        *bool            `json:"is_active"`                        // Whether the rule takes part in runs, active when empty on creation
+}
+
+type AutoInvestRuleResponseDTO struct {
+	ID               int64            `json:"id"`                         // Auto-invest rule ID
+	LenderID         int64            `json:"lender_id"`                  // Lender the rule invests for
+	LoanGrades       []enum.LoanGrade `json:"loan_grades"`                // Loan grades the rule invests in, empty for every grade
+	LoanTypes        []enum.LoanType  `json:"loan_types"`                 // Loan types the rule invests in, empty for every type
+	BusinessSectors  []string         `json:"business_sectors"`           // Business sectors the rule invests in, empty for every sector
+	MinTenure        int64            `json:"min_tenure"`                 // Shortest tenure in months, zero for no lower bound
+	MaxTenure        int64            `json:"max_tenure"`                 // Longest tenure in months, zero for no upper bound
+	MinRate          float64          `json:"min_rate"`                   // Lowest lender yield the rule accepts
+	AmountPerLoan    money.Amount     `json:"amount_per_loan"`            // Amount ordered in every matching loan
+	MonthlyBudget    money.Amount     `json:"monthly_budget"`             // Maximum amount ordered by the rule in a calendar month
+	OrderedThisMonth money.Amount     `json:"ordered_this_month"`         // Amount ordered by the rule in the current calendar month
+	IsActive         bool             `json:"is_active"`                  // Whether the rule takes part in runs
+	LastInvestedAt   *time.Time       `json:"last_invested_at,omitempty"` // Date of the last order of the rule
+	CreatedAt        time.Time        `json:"created_at"`                 // Date of creation
+	UpdatedAt        time.Time        `json:"updated_at"`                 // Date of last update
+}
+
+type AutoInvestRunResponseDTO struct {
+	ID             int64                          `json:"id"`                    // Auto-invest run ID
+	LoanID         int64                          `json:"loan_id"`               // Approved loan the rules were run for
+	RulesEvaluated int64                          `json:"rules_evaluated"`       // Number of active rules evaluated
+	OrdersCreated  int64                          `json:"orders_created"`        // Number of funding orders created
+	TotalOrdered   money.Amount                   `json:"total_ordered"`         // Total amount of the funding orders created
+	StartedAt      time.Time                      `json:"started_at"`            // Date the run started
+	FinishedAt     *time.Time                     `json:"finished_at,omitempty"` // Date the run finished
+	Items          []AutoInvestRunItemResponseDTO `json:"items"`                 // Outcome of every rule evaluated
+}
+
+type AutoInvestRunItemResponseDTO struct {
+	RuleID      int64                  `json:"rule_id"`                // Rule evaluated
+	LenderID    int64                  `json:"lender_id"`              // Lender of the rule
+	Outcome     enum.AutoInvestOutcome `json:"outcome"`                // ordered, skipped, failed
+	Reason      string                 `json:"reason,omitempty"`       // Why the rule was skipped or failed
+	Amount      money.Amount           `json:"amount"`                 // Amount ordered, or that would have been ordered
+	OrderNumber *string                `json:"order_number,omitempty"` // Order number of the funding when ordered
+	CreatedAt   time.Time              `json:"created_at"`             // Date of evaluation
+}
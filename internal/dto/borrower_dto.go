@@ -0,0 +1,65 @@
+package dto
+
+import (
+	"github.com/test/loan-service/internal/enum"
+	"time"
+)
+
+type BorrowerRequestDTO struct {
+	BorrowerID                 int64     `json:"-"`                                    // Borrower ID, taken from the path on update
+	FullName                   string    `json:"full_name" valid:"required"`           // Full name of the borrower as on the national ID
+	NationalID                 string    `json:"national_id" valid:"required,numeric"` // National ID number (NIK)
+	DateOfBirth                time.Time `json:"date_of_birth" valid:"required"`       // Date of birth
+	PhoneNumber                string    `json:"phone_number" valid:"required"`        // Phone number of the borrower
+	Email                      string    `json:"email" valid:"required,email"`         // Email address of the borrower
+	Address                    string    `json:"address" valid:"required"`             // Residential address
+	BusinessName               string    `json:"business_name"`                        // Business name of the borrower
+	BusinessType               string    `json:"business_type"`                        // Type of business (e.g., retail, manufacturing)
+	BusinessAddress            string    `json:"business_address"`                     // Business address
+	BusinessPhoneNumber        string    `json:"business_phone_number"`                // Business phone number
+	BusinessEmail              string    `json:"business_email" valid:"email"`         // Business email address
+	BusinessRegistrationNumber string    `json:"business_registration_number"`         // Business registration number
+	BusinessOwnerName          string    `json:"business_owner_name"`                  // Business owner's name
+	BusinessDescription        string    `json:"business_description"`                 // Business description
+	BusinessSector             string    `json:"business_sector"`                      // Business sector (e.g., agriculture, technology)
+	BusinessEstablishedYear    int64     `json:"business_established_year"`            // Year the business was established
+}
+
+type BorrowerResponseDTO struct {
+	ID                         int64          `json:"id"`                                     // Borrower ID
+	FullName                   string         `json:"full_name"`                              // Full name of the borrower as on the national ID
+	NationalID                 string         `json:"national_id"`                            // National ID number (NIK)
+	DateOfBirth                time.Time      `json:"date_of_birth"`                          // Date of birth
+	PhoneNumber                string         `json:"phone_number"`                           // Phone number of the borrower
+	Email                      string         `json:"email"`                                  // Email address of the borrower
+	Address                    string         `json:"address"`                                // Residential address
+	BusinessName               *string        `json:"business_name,omitempty"`                // Business name of the borrower
+	BusinessType               *string        `json:"business_type,omitempty"`                // Type of business (e.g., retail, manufacturing)
+	BusinessAddress            *string        `json:"business_address,omitempty"`             // Business address
+	BusinessPhoneNumber        *string        `json:"business_phone_number,omitempty"`        // Business phone number
+	BusinessEmail              *string        `json:"business_email,omitempty"`               // Business email address
+	BusinessRegistrationNumber *string        `json:"business_registration_number,omitempty"` // Business registration number
+	BusinessOwnerName          *string        `json:"business_owner_name,omitempty"`          // Business owner's name
+	BusinessDescription        *string        `json:"business_description,omitempty"`         // Business description
+	BusinessSector             *string        `json:"business_sector,omitempty"`              // Business sector (e.g., agriculture, technology)
+	BusinessEstablishedYear    *int64         `json:"business_established_year,omitempty"`    // Year the business was established
+	KycStatus                  enum.KycStatus `json:"kyc_status"`                             // KYC status
+	KycVerifiedAt              *time.Time     `json:"kyc_verified_at,omitempty"`              // Date the KYC was verified
+	CreatedAt                  time.Time      `json:"created_at"`                             // Date of creation
+	UpdatedAt                  time.Time      `json:"updated_at"`                             // Date of last update
+}
+
+type KycReviewRequestDTO struct {
+	StaffID   int64          `json:"staff_id" valid:"required"`   // Staff who reviews the KYC
+	KycStatus enum.KycStatus `json:"kyc_status" valid:"required"` // Decision of the review (verified, rejected)
+	Note      *string        `json:"note"`                        // Reason of the decision, required when rejected
+}
+
+type KycHistoryResponseDTO struct {
+	ID         int64          `json:"id"`                 // KYC history ID
+	FromStatus enum.KycStatus `json:"from_status"`        // KYC status before the change
+	ToStatus   enum.KycStatus `json:"to_status"`          // KYC status after the change
+	StaffID    *int64         `json:"staff_id,omitempty"` // Staff who reviewed the KYC, empty for changes made by the borrower
+	Note       *string        `json:"note,omitempty"`     // Reason of the change
+	CreatedAt  time.Time      `json:"created_at"`         // Date of the change
+}
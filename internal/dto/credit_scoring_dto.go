@@ -0,0 +1,54 @@
+package dto
+
+import (
+	"github.com/test/loan-service/internal/enum"
+	"time"
+)
+
+type CreditScoringRuleRequestDTO struct {
+	Factor      enum.CreditScoringFactor `json:"factor" valid:"required"` // Scored factor
+	MinValue    *float64                 `json:"min_value"`               // Inclusive lower bound of the factor value, null for no lower bound
+	MaxValue    *float64                 `json:"max_value"`               // Exclusive upper bound of the factor value, null for no upper bound
+	MatchValue  string                   `json:"match_value"`             // Business sector matched by the rule, empty matches every other sector
+	Points      int64                    `json:"points"`                  // Points added to the score when the rule matches
+	Description string                   `json:"description"`             // Description of the rule
+}
+
+type CreditScoringRuleResponseDTO struct {
+	ID          int64                    `json:"id"`          // Rule ID
+	Factor      enum.CreditScoringFactor `json:"factor"`      // Scored factor
+	MinValue    *float64                 `json:"min_value"`   // Inclusive lower bound of the factor value
+	MaxValue    *float64                 `json:"max_value"`   // Exclusive upper bound of the factor value
+	MatchValue  string                   `json:"match_value"` // Business sector matched by the rule
+	Points      int64                    `json:"points"`      // Points added to the score when the rule matches
+	Description string                   `json:"description"` // Description of the rule
+	CreatedAt   time.Time                `json:"created_at"`  // Date of creation
+	UpdatedAt   time.Time                `json:"updated_at"`  // Date of last update
+}
+
+type CreditGradeRequestDTO struct {
+	MinScore int64 `json:"min_score"` // Minimum score to get the grade
+}
+
+type CreditGradeResponseDTO struct {
+	ID        int64          `json:"id"`         // Grade ID
+	Grade     enum.LoanGrade `json:"grade"`      // Loan grade
+	MinScore  int64          `json:"min_score"`  // Minimum score to get the grade
+	CreatedAt time.Time      `json:"created_at"` // Date of creation
+	UpdatedAt time.Time      `json:"updated_at"` // Date of last update
+}
+
+type CreditScoreResponseDTO struct {
+	Score     int64                          `json:"score"`      // Total score of every factor
+	Grade     enum.LoanGrade                 `json:"grade"`      // Loan grade derived from the score
+	Factors   []CreditScoreFactorResponseDTO `json:"factors"`    // Contribution of every factor
+	CreatedAt time.Time                      `json:"created_at"` // Date of scoring
+}
+
+type CreditScoreFactorResponseDTO struct {
+	Factor          enum.CreditScoringFactor `json:"factor"`            // Scored factor
+	Value           string                   `json:"value"`             // Factor value computed from the loan request
+	RuleID          *int64                   `json:"rule_id,omitempty"` // Matched rule, empty when no rule matched
+	RuleDescription string                   `json:"rule_description"`  // Description of the matched rule at scoring time
+	Points          int64                    `json:"points"`            // Points contributed by the factor
+}
@@ -0,0 +1,38 @@
+package dto
+
+import (
+	"github.com/test/loan-service/internal/enum"
+	"time"
+)
+
+type DocumentUploadRequestDTO struct {
+	EntityType   enum.DocumentEntity `form:"entity_type" valid:"required"`   // Record the document belongs to (loan, loan_approval, loan_funding, loan_disbursement, borrower, lender)
+	EntityID     int64               `form:"entity_id" valid:"required"`     // ID of the record the document belongs to
+	DocumentType string              `form:"document_type" valid:"required"` // Kind of document
+	UploadedBy   int64               `form:"uploaded_by"`                    // Staff ID who uploads the document
+}
+
+type DocumentResponseDTO struct {
+	ID                   int64               `json:"id"`                      // Document ID
+	FileName             string              `json:"file_name"`               // File name given by the uploader
+	ContentType          string              `json:"content_type"`            // Content type detected from the file content
+	Size                 int64               `json:"size"`                    // File size in bytes
+	Checksum             string              `json:"checksum"`                // SHA-256 of the file content, hex encoded
+	EntityType           enum.DocumentEntity `json:"entity_type"`             // Record the document belongs to
+	EntityID             int64               `json:"entity_id"`               // ID of the record the document belongs to
+	DocumentType         string              `json:"document_type"`           // Kind of document
+	UploadedBy           *int64              `json:"uploaded_by,omitempty"`   // Staff who uploaded the document, empty for generated documents
+	URL                  string              `json:"url"`                     // URL of the document record, stable
+	DownloadURL          string              `json:"download_url"`            // Signed URL the file can be downloaded from until it expires
+	DownloadURLExpiresAt time.Time           `json:"download_url_expires_at"` // Date the download URL expires
+	CreatedAt            time.Time           `json:"created_at"`              // Date of creation
+}
+
+type DocumentVerificationResponseDTO struct {
+	ID             int64     `json:"id"`              // Document ID
+	Checksum       string    `json:"checksum"`        // SHA-256 recorded when the document was stored
+	ActualChecksum string    `json:"actual_checksum"` // SHA-256 of the file currently in the store, empty when it is missing
+	Exists         bool      `json:"exists"`          // The file is in the document store
+	Unchanged      bool      `json:"unchanged"`       // The file is in the store and matches the recorded checksum
+	VerifiedAt     time.Time `json:"verified_at"`     // Date of the verification
+}
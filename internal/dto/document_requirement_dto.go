@@ -0,0 +1,23 @@
+package dto
+
+import (
+	"github.com/test/loan-service/internal/enum"
+	"time"
+)
+
+type DocumentRequirementRequestDTO struct {
+	LoanType     enum.LoanType `json:"loan_type" valid:"required"`     // Loan type the requirement applies to
+	DocumentType string        `json:"document_type" valid:"required"` // Document type attached to the approval
+	Name         string        `json:"name" valid:"required"`          // Name of the document reported when it is missing
+	Mandatory    bool          `json:"mandatory"`                      // Loans of the type can not be approved without the document
+}
+
+type DocumentRequirementResponseDTO struct {
+	ID           int64         `json:"id"`            // Document requirement ID
+	LoanType     enum.LoanType `json:"loan_type"`     // Loan type the requirement applies to
+	DocumentType string        `json:"document_type"` // Document type attached to the approval
+	Name         string        `json:"name"`          // Name of the document reported when it is missing
+	Mandatory    bool          `json:"mandatory"`     // Loans of the type can not be approved without the document
+	CreatedAt    time.Time     `json:"created_at"`    // Date of creation
+	UpdatedAt    time.Time     `json:"updated_at"`    // Date of last update
+}
@@ -0,0 +1,21 @@
+package dto
+
+import (
+	"github.com/test/loan-service/internal/enum"
+	"time"
+)
+
+type FeePolicyRequestDTO struct {
+	OriginationFeePercentage float64 `json:"origination_fee_percentage"` // Percentage of the disbursed principal deducted from the borrower payout
+	ServiceFeePercentage     float64 `json:"service_fee_percentage"`     // Percentage of the lender interest taken before it is credited
+}
+
+type FeePolicyResponseDTO struct {
+	ID                       int64         `json:"id"`                         // Fee policy ID
+	LoanType                 enum.LoanType `json:"loan_type"`                  // Loan type the policy applies to
+	OriginationFeePercentage float64       `json:"origination_fee_percentage"` // Percentage of the disbursed principal deducted from the borrower payout
+	ServiceFeePercentage     float64       `json:"service_fee_percentage"`     // Percentage of the lender interest taken before it is credited
+	CreatedAt                time.Time     `json:"created_at"`                 // Date of creation
+	UpdatedAt                time.Time     `json:"updated_at"`                 // Date of last update
+	DeletedAt                *time.Time    `json:"deleted_at,omitempty"`       // Date of deletion if applicable
+}
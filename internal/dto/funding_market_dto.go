@@ -0,0 +1,52 @@
+package dto
+
+import (
+	"github.com/test/loan-service/internal/enum"
+	"github.com/test/loan-service/internal/money"
+	"time"
+)
+
+type FundingListingRequestDTO struct {
+	LoanFundingID   int64        `json:"loan_funding_id" valid:"required"`  // Funding offered for sale
+	LenderID        int64        `json:"lender_id" valid:"required"`        // Lender selling the funding, must own it
+	PrincipalAmount money.Amount `json:"principal_amount" valid:"required"` // Outstanding principal offered for sale
+	Price           money.Amount `json:"price" valid:"required"`            // Price asked for the principal and its remaining interest
+}
+
+type FundingListingCancelRequestDTO struct {
+	LenderID int64 `json:"lender_id" valid:"required"` // Lender cancelling the listing, has to be its seller
+}
+
+type FundingPurchaseRequestDTO struct {
+	LenderID int64 `json:"lender_id" valid:"required"` // Lender buying the listing, the new funding takes the email of its profile
+}
+
+type FundingListingResponseDTO struct {
+	ID              int64                     `json:"id"`               // Listing ID
+	ListingCode     string                    `json:"listing_code"`     // Listing code
+	LoanFundingID   int64                     `json:"loan_funding_id"`  // Funding offered for sale
+	LoanID          int64                     `json:"loan_id"`          // Loan of the funding
+	SellerID        int64                     `json:"seller_id"`        // Lender selling the funding
+	PrincipalAmount money.Amount              `json:"principal_amount"` // Outstanding principal offered for sale
+	Price           money.Amount              `json:"price"`            // Price asked for the principal and its remaining interest
+	Premium         money.Amount              `json:"premium"`          // Price minus principal, negative for a discount
+	Status          enum.FundingListingStatus `json:"status"`           // open, sold, cancelled
+	CreatedAt       time.Time                 `json:"created_at"`       // Date of creation
+	UpdatedAt       time.Time                 `json:"updated_at"`       // Date of last update
+}
+
+type FundingTransferResponseDTO struct {
+	ID              int64        `json:"id"`                // Transfer ID
+	TransferCode    string       `json:"transfer_code"`     // Transfer code
+	ListingID       int64        `json:"listing_id"`        // Listing that was bought
+	LoanID          int64        `json:"loan_id"`           // Loan of the fundings
+	SellerFundingID int64        `json:"seller_funding_id"` // Funding the principal was sold from
+	BuyerFundingID  int64        `json:"buyer_funding_id"`  // Funding created for the buyer
+	SellerID        int64        `json:"seller_id"`         // Lender selling the principal
+	BuyerID         int64        `json:"buyer_id"`          // Lender buying the principal
+	PrincipalAmount money.Amount `json:"principal_amount"`  // Outstanding principal transferred
+	InterestAmount  money.Amount `json:"interest_amount"`   // Remaining gross interest transferred with the principal
+	Price           money.Amount `json:"price"`             // Price paid by the buyer to the seller
+	Premium         money.Amount `json:"premium"`           // Price minus principal, negative for a discount
+	TransferredAt   time.Time    `json:"transferred_at"`    // Date ownership changed
+}
@@ -0,0 +1,23 @@
+package dto
+
+import (
+	"github.com/test/loan-service/internal/enum"
+	"time"
+)
+
+type FundingPolicyRequestDTO struct {
+	MinFundedPercentage    float64 `json:"min_funded_percentage" valid:"required"` // Minimum funded percentage at deadline to still disburse
+	RequireBorrowerConsent bool    `json:"require_borrower_consent"`               // Partial disbursement needs borrower consent
+	CoolingOffHours        int64   `json:"cooling_off_hours"`                      // Hours after investing during which the lender can still cancel
+}
+
+type FundingPolicyResponseDTO struct {
+	ID                     int64         `json:"id"`                       // Funding policy ID
+	LoanType               enum.LoanType `json:"loan_type"`                // Loan type the policy applies to
+	MinFundedPercentage    float64       `json:"min_funded_percentage"`    // Minimum funded percentage at deadline to still disburse
+	RequireBorrowerConsent bool          `json:"require_borrower_consent"` // Partial disbursement needs borrower consent
+	CoolingOffHours        int64         `json:"cooling_off_hours"`        // Hours after investing during which the lender can still cancel
+	CreatedAt              time.Time     `json:"created_at"`               // Date of creation
+	UpdatedAt              time.Time     `json:"updated_at"`               // Date of last update
+	DeletedAt              *time.Time    `json:"deleted_at,omitempty"`     // Date of deletion if applicable
+}
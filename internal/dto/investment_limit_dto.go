@@ -0,0 +1,23 @@
+package dto
+
+import (
+	"github.com/test/loan-service/internal/money"
+	"time"
+)
+
+type InvestmentLimitRequestDTO struct {
+	MinTicketAmount        money.Amount `json:"min_ticket_amount"`         // Minimum amount of a single funding
+	TicketIncrement        money.Amount `json:"ticket_increment"`          // Funding amounts must be a multiple of this step, zero for any amount
+	MaxLoanSharePercentage float64      `json:"max_loan_share_percentage"` // Maximum percentage of the requested amount of a loan a single lender may fund
+	MaxLenderExposure      money.Amount `json:"max_lender_exposure"`       // Maximum outstanding principal of a lender over all loans, zero for no limit
+}
+
+type InvestmentLimitResponseDTO struct {
+	ID                     int64        `json:"id"`                        // Investment limit ID
+	MinTicketAmount        money.Amount `json:"min_ticket_amount"`         // Minimum amount of a single funding
+	TicketIncrement        money.Amount `json:"ticket_increment"`          // Funding amounts must be a multiple of this step, zero for any amount
+	MaxLoanSharePercentage float64      `json:"max_loan_share_percentage"` // Maximum percentage of the requested amount of a loan a single lender may fund
+	MaxLenderExposure      money.Amount `json:"max_lender_exposure"`       // Maximum outstanding principal of a lender over all loans, zero for no limit
+	CreatedAt              time.Time    `json:"created_at"`                // Date of creation
+	UpdatedAt              time.Time    `json:"updated_at"`                // Date of last update
+}
@@ -0,0 +1,33 @@
+package dto
+
+import (
+	"github.com/test/loan-service/internal/enum"
+	"github.com/test/loan-service/internal/money"
+	"time"
+)
+
+type LedgerAccountResponseDTO struct {
+	ID            int64                  `json:"id"`             // Ledger account ID
+	AccountCode   string                 `json:"account_code"`   // Unique account code
+	AccountType   enum.LedgerAccountType `json:"account_type"`   // Account type
+	OwnerID       *int64                 `json:"owner_id"`       // Lender ID or loan ID, null for platform accounts
+	NormalBalance enum.LedgerDirection   `json:"normal_balance"` // Side that increases the balance
+	TotalDebit    money.Amount           `json:"total_debit"`    // Sum of every debit posted to the account
+	TotalCredit   money.Amount           `json:"total_credit"`   // Sum of every credit posted to the account
+	Balance       money.Amount           `json:"balance"`        // Balance on the normal side of the account
+	CreatedAt     time.Time              `json:"created_at"`     // Date of creation
+	UpdatedAt     time.Time              `json:"updated_at"`     // Date of last update
+}
+
+type LedgerStatementLineDTO struct {
+	PostingID   int64                `json:"posting_id"`   // Posting ID
+	EntryID     int64                `json:"entry_id"`     // Journal entry ID
+	EntryCode   string               `json:"entry_code"`   // Journal entry code
+	EntryType   enum.LedgerEntryType `json:"entry_type"`   // Business event that caused the entry
+	ReferenceID int64                `json:"reference_id"` // ID of the funding, loan or repayment
+	Description string               `json:"description"`  // Human readable description
+	Direction   enum.LedgerDirection `json:"direction"`    // debit or credit
+	Amount      money.Amount         `json:"amount"`       // Posted amount
+	Balance     money.Amount         `json:"balance"`      // Account balance right after the posting
+	CreatedAt   time.Time            `json:"created_at"`   // Date the posting was written
+}
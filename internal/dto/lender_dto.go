@@ -0,0 +1,54 @@
+package dto
+
+import (
+	"github.com/test/loan-service/internal/enum"
+	"time"
+)
+
+type LenderRequestDTO struct {
+	LenderID    int64           `json:"-"`                             // Lender ID, taken from the path on update
+	FullName    string          `json:"full_name" valid:"required"`    // Full name of the lender, or the company name of an institution
+	NationalID  string          `json:"national_id" valid:"required"`  // National ID number (NIK), or the registration number of an institution
+	DateOfBirth *time.Time      `json:"date_of_birth"`                 // Date of birth, required for individual lenders
+	PhoneNumber string          `json:"phone_number" valid:"required"` // Phone number of the lender
+	Email       string          `json:"email" valid:"required,email"`  // Preferred email, used for every funding of the lender
+	Address     string          `json:"address" valid:"required"`      // Address of the lender
+	LenderType  enum.LenderType `json:"lender_type" valid:"required"`  // Lender type (individual, institutional), kept in the tax profile
+	TaxID       *string         `json:"tax_id"`                        // Tax ID of the lender, kept in the tax profile
+}
+
+type LenderResponseDTO struct {
+	ID                    int64              `json:"id"`                                // Lender ID
+	FullName              string             `json:"full_name"`                         // Full name of the lender, or the company name of an institution
+	NationalID            string             `json:"national_id"`                       // National ID number (NIK), or the registration number of an institution
+	DateOfBirth           *time.Time         `json:"date_of_birth,omitempty"`           // Date of birth
+	PhoneNumber           string             `json:"phone_number"`                      // Phone number of the lender
+	Email                 string             `json:"email"`                             // Preferred email, used for every funding of the lender
+	Address               string             `json:"address"`                           // Address of the lender
+	LenderType            enum.LenderType    `json:"lender_type"`                       // Lender type, from the tax profile
+	TaxID                 *string            `json:"tax_id,omitempty"`                  // Tax ID of the lender, from the tax profile
+	InvestorClass         enum.InvestorClass `json:"investor_class"`                    // Investor classification (retail, accredited)
+	ClassifiedBy          *int64             `json:"classified_by,omitempty"`           // Staff who set the investor classification
+	ClassifiedAt          *time.Time         `json:"classified_at,omitempty"`           // Date the investor classification was set
+	RiskProfile           *enum.RiskProfile  `json:"risk_profile,omitempty"`            // Risk profile from the suitability questionnaire
+	SuitabilityScore      *int64             `json:"suitability_score,omitempty"`       // Score of the suitability questionnaire
+	SuitabilityAssessedAt *time.Time         `json:"suitability_assessed_at,omitempty"` // Date the suitability questionnaire was answered
+	MaxLoanGrade          *enum.LoanGrade    `json:"max_loan_grade,omitempty"`          // Worst loan grade the lender may invest in, empty until the lender can invest
+	KycStatus             enum.KycStatus     `json:"kyc_status"`                        // KYC status
+	KycVerifiedAt         *time.Time         `json:"kyc_verified_at,omitempty"`         // Date the KYC was verified
+	CreatedAt             time.Time          `json:"created_at"`                        // Date of creation
+	UpdatedAt             time.Time          `json:"updated_at"`                        // Date of last update
+}
+
+// SuitabilityRequestDTO every answer scores from 1, the most cautious, to 4, the most risk-taking
+type SuitabilityRequestDTO struct {
+	InvestmentExperience int64 `json:"investment_experience" valid:"required"` // Experience with investments other than deposits
+	LossTolerance        int64 `json:"loss_tolerance" valid:"required"`        // Loss of principal the lender can accept
+	InvestmentHorizon    int64 `json:"investment_horizon" valid:"required"`    // How long the lender can leave the money invested
+	IncomeStability      int64 `json:"income_stability" valid:"required"`      // How stable the income of the lender is
+}
+
+type InvestorClassRequestDTO struct {
+	StaffID       int64              `json:"staff_id" valid:"required"`       // Staff who sets the classification
+	InvestorClass enum.InvestorClass `json:"investor_class" valid:"required"` // Investor classification (retail, accredited)
+}
@@ -0,0 +1,35 @@
+package dto
+
+import (
+	"github.com/test/loan-service/internal/enum"
+	"github.com/test/loan-service/internal/money"
+	"time"
+)
+
+type WalletTopUpRequestDTO struct {
+	LenderID        int64        `json:"-"`                                 // Lender ID, taken from the path
+	Amount          money.Amount `json:"amount" valid:"required"`           // Amount paid in
+	ReferenceNumber string       `json:"reference_number" valid:"required"` // Payment reference, a reference is only credited once
+}
+
+type LenderWalletResponseDTO struct {
+	ID               int64        `json:"id"`                // Wallet ID
+	LenderID         int64        `json:"lender_id"`         // Lender owning the wallet
+	AvailableBalance money.Amount `json:"available_balance"` // Money the lender can still invest or withdraw
+	HeldBalance      money.Amount `json:"held_balance"`      // Money reserved for pending fundings and withdrawals
+	TotalBalance     money.Amount `json:"total_balance"`     // Available and held balance
+	CreatedAt        time.Time    `json:"created_at"`        // Date of creation
+	UpdatedAt        time.Time    `json:"updated_at"`        // Date of last update
+}
+
+type WalletTransactionResponseDTO struct {
+	ID                    int64                      `json:"id"`                         // Wallet transaction ID
+	TransactionCode       string                     `json:"transaction_code"`           // Transaction code
+	TransactionType       enum.WalletTransactionType `json:"transaction_type"`           // Transaction type
+	Amount                money.Amount               `json:"amount"`                     // Transaction amount
+	ReferenceID           *int64                     `json:"reference_id,omitempty"`     // Funding, repayment allocation or withdrawal ID
+	ReferenceNumber       string                     `json:"reference_number,omitempty"` // Payment reference of a top up
+	AvailableBalanceAfter money.Amount               `json:"available_balance_after"`    // Available balance after the transaction
+	HeldBalanceAfter      money.Amount               `json:"held_balance_after"`         // Held balance after the transaction
+	CreatedAt             time.Time                  `json:"created_at"`                 // Date of transaction
+}
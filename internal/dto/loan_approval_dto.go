@@ -22,6 +22,9 @@ type LoanApprovalResponseDTO struct {
 	StaffID          *int64                         `json:"staff_id,omitempty"`
 	ApprovalDate     *time.Time                     `json:"approval_date,omitempty"`
 	ApprovalStatus   enum.ApprovalStatus            `json:"approval_status"`
+	RequiredLevel    int64                          `json:"required_level"`
+	ApprovedLevel    int64                          `json:"approved_level"`
+	ApprovalSteps    []ApprovalStepResponseDTO      `json:"approval_steps"`
 	CreatedAt        time.Time                      `json:"created_at"`
 	UpdatedAt        time.Time                      `json:"updated_at"`
 	DeletedAt        *time.Time                     `json:"deleted_at,omitempty"`
@@ -1,34 +1,47 @@
 package dto
 
-import "time"
+import (
+	"github.com/test/loan-service/internal/money"
+	"time"
+)
 
 type LoanFundingRequestDTO struct {
-	OrderNumber        string  `json:"order_number" validate:"required"`
-	LoanID             int64   `json:"loan_id," validate:"required"`
-	LenderID           int64   `json:"lender_id" validate:"required"`
-	LenderEmail        string  `json:"lender_email" validate:"required,email"`
-	InvestmentAmount   float64 `json:"investment_amount" validate:"required"`
-	LenderAgreementURL string  `json:"lender_agreement_url" validate:"required"`
+	OrderNumber      string       `json:"order_number" validate:"required"`
+	LoanID           int64        `json:"loan_id," validate:"required"`
+	LenderID         int64        `json:"lender_id" validate:"required"`
+	InvestmentAmount money.Amount `json:"investment_amount" validate:"required"`
+}
+
+type LoanFundingCancelRequestDTO struct {
+	LenderID int64 `json:"lender_id" valid:"required"` // Lender cancelling the funding, has to own the funding
 }
 
 type LoanFundingResponseDTO struct {
-	ID                 int64      `json:"id"`
-	LoanOrderNumber    string     `json:"loan_order_number"`
-	OrderNumber        string     `json:"order_number"`
-	LoanID             int64      `json:"loan_id"`
-	LenderID           int64      `json:"lender_id"`
-	LenderEmail        string     `json:"lender_email"`
-	InvestmentAmount   float64    `json:"investment_amount"`
-	Rate               float64    `json:"rate"`
-	Interest           float64    `json:"interest"`
-	ROI                float64    `json:"roi"`
-	InterestPaid       float64    `json:"interest_paid"`
-	CapitalAmountPaid  float64    `json:"capital_amount_paid"`
-	TotalAmountPaid    float64    `json:"total_amount_paid"`
-	InvestmentDate     time.Time  `json:"investment_date"`
-	Status             string     `json:"status"`
-	LenderAgreementURL string     `json:"lender_agreement_url"`
-	CreatedAt          time.Time  `json:"created_at"`
-	UpdatedAt          time.Time  `json:"updated_at"`
-	DeletedAt          *time.Time `json:"deleted_at,omitempty"`
+	ID                   int64        `json:"id"`
+	LoanOrderNumber      string       `json:"loan_order_number"`
+	OrderNumber          string       `json:"order_number"`
+	LoanID               int64        `json:"loan_id"`
+	LenderID             int64        `json:"lender_id"`
+	LenderEmail          string       `json:"lender_email"`
+	InvestmentAmount     money.Amount `json:"investment_amount"`
+	Rate                 float64      `json:"rate"`
+	Interest             money.Amount `json:"interest"`
+	ROI                  money.Amount `json:"roi"`
+	InterestPaid         money.Amount `json:"interest_paid"`
+	CapitalAmountPaid    money.Amount `json:"capital_amount_paid"`
+	TotalAmountPaid      money.Amount `json:"total_amount_paid"`
+	ServiceFeePercentage float64      `json:"service_fee_percentage"`
+	ServiceFee           money.Amount `json:"service_fee"`
+	ServiceFeePaid       money.Amount `json:"service_fee_paid"`
+	WithholdingTax       money.Amount `json:"withholding_tax"`
+	WithholdingTaxPaid   money.Amount `json:"withholding_tax_paid"`
+	TransferredAmount    money.Amount `json:"transferred_amount"`
+	TransferredFromID    *int64       `json:"transferred_from_id,omitempty"`
+	InvestmentDate       time.Time    `json:"investment_date"`
+	InvestedAt           *time.Time   `json:"invested_at,omitempty"`
+	Status               string       `json:"status"`
+	LenderAgreementURL   string       `json:"lender_agreement_url"`
+	CreatedAt            time.Time    `json:"created_at"`
+	UpdatedAt            time.Time    `json:"updated_at"`
+	DeletedAt            *time.Time   `json:"deleted_at,omitempty"`
 }
@@ -0,0 +1,32 @@
+package dto
+
+import (
+	"github.com/test/loan-service/internal/enum"
+	"time"
+)
+
+type LoanPricingRequestDTO struct {
+	LoanGrade    enum.LoanGrade `json:"loan_grade" valid:"required"`    // Loan grade the pricing applies to
+	LoanType     enum.LoanType  `json:"loan_type" valid:"required"`     // Loan type the pricing applies to
+	MinTenure    int64          `json:"min_tenure" valid:"required"`    // Inclusive lower bound of the tenure band in months
+	MaxTenure    int64          `json:"max_tenure" valid:"required"`    // Inclusive upper bound of the tenure band in months
+	BorrowerRate float64        `json:"borrower_rate" valid:"required"` // Annual interest rate charged to the borrower
+	LenderYield  float64        `json:"lender_yield" valid:"required"`  // Annual yield paid to lenders
+	ValidFrom    *time.Time     `json:"valid_from"`                     // Date the pricing takes effect, now when empty
+	ValidUntil   *time.Time     `json:"valid_until"`                    // Date the pricing stops being applied, empty when open ended
+}
+
+type LoanPricingResponseDTO struct {
+	ID           int64          `json:"id"`            // Pricing ID, the pricing version applied to a loan
+	LoanGrade    enum.LoanGrade `json:"loan_grade"`    // Loan grade the pricing applies to
+	LoanType     enum.LoanType  `json:"loan_type"`     // Loan type the pricing applies to
+	MinTenure    int64          `json:"min_tenure"`    // Inclusive lower bound of the tenure band in months
+	MaxTenure    int64          `json:"max_tenure"`    // Inclusive upper bound of the tenure band in months
+	BorrowerRate float64        `json:"borrower_rate"` // Annual interest rate charged to the borrower
+	LenderYield  float64        `json:"lender_yield"`  // Annual yield paid to lenders
+	Spread       float64        `json:"spread"`        // Platform spread, borrower rate minus lender yield
+	ValidFrom    time.Time      `json:"valid_from"`    // Date the pricing takes effect
+	ValidUntil   *time.Time     `json:"valid_until"`   // Date the pricing stops being applied
+	CreatedAt    time.Time      `json:"created_at"`    // Date of creation
+	UpdatedAt    time.Time      `json:"updated_at"`    // Date of last update
+}
@@ -0,0 +1,40 @@
+package dto
+
+import (
+	"github.com/test/loan-service/internal/money"
+	"time"
+)
+
+type LoanRepaymentRequestDTO struct {
+	ReferenceNumber string       `json:"reference_number" valid:"required"` // Payment reference from the payment channel
+	Amount          money.Amount `json:"amount" valid:"required"`           // Amount paid by the borrower
+	PaymentDate     *time.Time   `json:"payment_date,omitempty"`            // Date the borrower paid, default to now
+}
+
+type LoanRepaymentResponseDTO struct {
+	ID              int64                            `json:"id"`                    // Repayment ID
+	LoanID          int64                            `json:"loan_id"`               // Loan ID
+	RepaymentCode   string                           `json:"repayment_code"`        // Repayment code
+	ReferenceNumber string                           `json:"reference_number"`      // Payment reference from the payment channel
+	Amount          money.Amount                     `json:"amount"`                // Amount paid by the borrower
+	PrincipalAmount money.Amount                     `json:"principal_amount"`      // Portion allocated to principal
+	InterestAmount  money.Amount                     `json:"interest_amount"`       // Portion allocated to interest
+	PaymentDate     time.Time                        `json:"payment_date"`          // Date the borrower paid
+	CreatedAt       time.Time                        `json:"created_at"`            // Date of creation
+	UpdatedAt       time.Time                        `json:"updated_at"`            // Date of last update
+	DeletedAt       *time.Time                       `json:"deleted_at,omitempty"`  // Date of deletion if applicable
+	Allocations     []RepaymentAllocationResponseDTO `json:"allocations,omitempty"` // Split of the payment across lenders
+}
+
+type RepaymentAllocationResponseDTO struct {
+	ID              int64        `json:"id"`               // Allocation ID
+	RepaymentID     int64        `json:"repayment_id"`     // Repayment ID
+	LoanFundingID   int64        `json:"loan_funding_id"`  // Funding receiving the allocation
+	LenderID        int64        `json:"lender_id"`        // Lender receiving the allocation
+	PrincipalAmount money.Amount `json:"principal_amount"` // Principal allocated to the lender
+	InterestAmount  money.Amount `json:"interest_amount"`  // Interest allocated to the lender
+	ServiceFee      money.Amount `json:"service_fee"`      // Service fee taken from the interest allocated to the lender
+	WithholdingTax  money.Amount `json:"withholding_tax"`  // Tax withheld from the interest allocated to the lender
+	TotalAmount     money.Amount `json:"total_amount"`     // Principal + interest credited to the lender, net of the service fee and withholding tax
+	CreatedAt       time.Time    `json:"created_at"`       // Date of creation
+}
@@ -1,8 +1,21 @@
 package message
 
-import "github.com/test/loan-service/internal/enum"
+import (
+	"github.com/test/loan-service/internal/enum"
+	"github.com/test/loan-service/internal/money"
+	"time"
+)
 
 type UpdateLoanMessage struct {
 	LoanID     int64
 	LoanStatus enum.LoanStatus
 }
+
+type LoanCompletedMessage struct {
+	LoanID               int64        `json:"loan_id"`
+	LoanCode             string       `json:"loan_code"`
+	BorrowerID           int64        `json:"borrower_id"`
+	TotalRepaymentAmount money.Amount `json:"total_repayment_amount"`
+	TotalRepaidAmount    money.Amount `json:"total_repaid_amount"`
+	CompletedAt          time.Time    `json:"completed_at"`
+}
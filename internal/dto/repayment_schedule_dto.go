@@ -0,0 +1,25 @@
+package dto
+
+import (
+	"github.com/test/loan-service/internal/enum"
+	"github.com/test/loan-service/internal/money"
+	"time"
+)
+
+type RepaymentScheduleResponseDTO struct {
+	ID                 int64                        `json:"id"`                   // Repayment schedule ID
+	LoanID             int64                        `json:"loan_id"`              // Loan ID
+	InstallmentNumber  int64                        `json:"installment_number"`   // Installment sequence (1..tenures)
+	DueDate            time.Time                    `json:"due_date"`             // Installment due date
+	PrincipalAmount    money.Amount                 `json:"principal_amount"`     // Principal portion of the installment
+	InterestAmount     money.Amount                 `json:"interest_amount"`      // Interest portion of the installment
+	TotalAmount        money.Amount                 `json:"total_amount"`         // Principal + interest of the installment
+	OutstandingBalance money.Amount                 `json:"outstanding_balance"`  // Remaining principal after this installment
+	PrincipalPaid      money.Amount                 `json:"principal_paid"`       // Principal already paid for this installment
+	InterestPaid       money.Amount                 `json:"interest_paid"`        // Interest already paid for this installment
+	PaidAt             *time.Time                   `json:"paid_at,omitempty"`    // Date the installment was fully paid
+	Status             enum.RepaymentScheduleStatus `json:"status"`               // Installment status (pending, partially_paid, paid)
+	CreatedAt          time.Time                    `json:"created_at"`           // Date of creation
+	UpdatedAt          time.Time                    `json:"updated_at"`           // Date of last update
+	DeletedAt          *time.Time                   `json:"deleted_at,omitempty"` // Date of deletion if applicable
+}
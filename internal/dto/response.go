@@ -30,3 +30,14 @@ func SendError(c echo.Context, code int, message string) error {
 	}
 	return c.JSON(http.StatusOK, response)
 }
+
+// DetailError is an error code sent with the details the client needs to correct the request, Error
+// returns the code so it is localized like any other error
+type DetailError struct {
+	Code    string
+	Details []string
+}
+
+func (e *DetailError) Error() string {
+	return e.Code
+}
@@ -0,0 +1,32 @@
+package dto
+
+import (
+	"github.com/test/loan-service/internal/enum"
+	"time"
+)
+
+type SignatureCallbackRequestDTO struct {
+	ProviderReference string    `json:"provider_reference" valid:"required"` // Reference of the signature request at the provider
+	SignerID          int64     `json:"signer_id" valid:"required"`          // Identity of the signer, the borrower ID
+	SignedAt          time.Time `json:"signed_at"`                           // Date the document was signed
+	DocumentHash      string    `json:"document_hash" valid:"required"`      // SHA-256 of the signed document, hex encoded
+	Signature         string    `json:"signature"`                           // Proof the callback comes from the provider, provider specific
+}
+
+type AgreementSignatureResponseDTO struct {
+	ID                 int64                `json:"id"`                             // Signature request ID
+	LoanAgreementID    int64                `json:"loan_agreement_id"`              // Agreement to be signed
+	LoanDisbursementID int64                `json:"loan_disbursement_id"`           // Disbursement waiting for the signature
+	LoanID             int64                `json:"loan_id"`                        // Loan of the agreement
+	SignerID           int64                `json:"signer_id"`                      // Borrower expected to sign
+	Provider           string               `json:"provider"`                       // E-sign provider handling the request
+	ProviderReference  string               `json:"provider_reference"`             // Reference of the request at the provider
+	SigningURL         string               `json:"signing_url"`                    // URL the borrower signs the agreement at
+	Status             enum.SignatureStatus `json:"status"`                         // requested, signed, rejected
+	SignedBy           *string              `json:"signed_by,omitempty"`            // Signer identity reported by the provider
+	SignedAt           *time.Time           `json:"signed_at,omitempty"`            // Date the agreement was signed
+	SignedDocumentHash *string              `json:"signed_document_hash,omitempty"` // SHA-256 of the document that was signed
+	RejectionReason    *string              `json:"rejection_reason,omitempty"`     // Why the signature was not accepted
+	CreatedAt          time.Time            `json:"created_at"`                     // Date of creation
+	UpdatedAt          time.Time            `json:"updated_at"`                     // Date of last update
+}
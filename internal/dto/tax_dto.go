@@ -0,0 +1,36 @@
+package dto
+
+import (
+	"github.com/test/loan-service/internal/enum"
+	"time"
+)
+
+type LenderTaxProfileRequestDTO struct {
+	LenderID   int64           `json:"-"`                            // Lender ID, taken from the path
+	LenderType enum.LenderType `json:"lender_type" valid:"required"` // Lender type (individual, institutional)
+	TaxID      *string         `json:"tax_id"`                       // Tax ID of the lender, empty when the lender has none
+}
+
+type LenderTaxProfileResponseDTO struct {
+	LenderID           int64           `json:"lender_id"`            // Lender the profile belongs to
+	LenderType         enum.LenderType `json:"lender_type"`          // Lender type
+	TaxID              *string         `json:"tax_id,omitempty"`     // Tax ID of the lender
+	WithholdingTaxRate float64         `json:"withholding_tax_rate"` // Percentage withheld from the interest of the lender
+	CreatedAt          *time.Time      `json:"created_at,omitempty"` // Date of creation, empty while the lender has the default profile
+	UpdatedAt          *time.Time      `json:"updated_at,omitempty"` // Date of last update
+}
+
+type WithholdingTaxRateRequestDTO struct {
+	Rate        float64 `json:"rate"`           // Percentage withheld from the interest of a lender with a tax ID
+	NoTaxIDRate float64 `json:"no_tax_id_rate"` // Percentage withheld from the interest of a lender without a tax ID
+}
+
+type WithholdingTaxRateResponseDTO struct {
+	ID          int64           `json:"id"`                   // Withholding tax rate ID
+	LenderType  enum.LenderType `json:"lender_type"`          // Lender type the rate applies to
+	Rate        float64         `json:"rate"`                 // Percentage withheld from the interest of a lender with a tax ID
+	NoTaxIDRate float64         `json:"no_tax_id_rate"`       // Percentage withheld from the interest of a lender without a tax ID
+	CreatedAt   time.Time       `json:"created_at"`           // Date of creation
+	UpdatedAt   time.Time       `json:"updated_at"`           // Date of last update
+	DeletedAt   *time.Time      `json:"deleted_at,omitempty"` // Date of deletion if applicable
+}
@@ -0,0 +1,37 @@
+package dto
+
+import (
+	"github.com/test/loan-service/internal/enum"
+	"github.com/test/loan-service/internal/money"
+	"time"
+)
+
+type WalletWithdrawalRequestDTO struct {
+	LenderID          int64        `json:"-"`                                    // Lender ID, taken from the path
+	Amount            money.Amount `json:"amount" valid:"required"`              // Amount to withdraw
+	BankName          string       `json:"bank_name" valid:"required"`           // Destination bank
+	BankAccountNumber string       `json:"bank_account_number" valid:"required"` // Destination bank account number
+	BankAccountName   string       `json:"bank_account_name" valid:"required"`   // Destination bank account holder
+}
+
+type WalletWithdrawalReviewRequestDTO struct {
+	Status     enum.WalletWithdrawalStatus `json:"status" valid:"required"`      // approved or rejected
+	ReviewedBy string                      `json:"reviewed_by" valid:"required"` // Staff reviewing the withdrawal
+	ReviewNote string                      `json:"review_note"`                  // Note of the reviewer
+}
+
+type WalletWithdrawalResponseDTO struct {
+	ID                int64                       `json:"id"`                    // Withdrawal ID
+	WithdrawalCode    string                      `json:"withdrawal_code"`       // Withdrawal code
+	LenderID          int64                       `json:"lender_id"`             // Lender requesting the withdrawal
+	Amount            money.Amount                `json:"amount"`                // Amount to withdraw
+	BankName          string                      `json:"bank_name"`             // Destination bank
+	BankAccountNumber string                      `json:"bank_account_number"`   // Destination bank account number
+	BankAccountName   string                      `json:"bank_account_name"`     // Destination bank account holder
+	Status            enum.WalletWithdrawalStatus `json:"status"`                // Withdrawal status
+	ReviewedBy        string                      `json:"reviewed_by,omitempty"` // Staff who approved or rejected the withdrawal
+	ReviewNote        string                      `json:"review_note,omitempty"` // Note of the reviewer
+	ReviewedAt        *time.Time                  `json:"reviewed_at,omitempty"` // Date of review
+	CreatedAt         time.Time                   `json:"created_at"`            // Date of creation
+	UpdatedAt         time.Time                   `json:"updated_at"`            // Date of last update
+}
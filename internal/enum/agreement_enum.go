@@ -0,0 +1,16 @@
+package enum
+
+type AgreementType string
+
+const (
+	AgreementBorrower AgreementType = "borrower"
+	AgreementLender   AgreementType = "lender"
+)
+
+func (s AgreementType) IsValid() bool {
+	switch s {
+	case AgreementBorrower, AgreementLender:
+		return true
+	}
+	return false
+}
@@ -0,0 +1,17 @@
+package enum
+
+type AutoInvestOutcome string
+
+const (
+	AutoInvestOrdered AutoInvestOutcome = "ordered"
+	AutoInvestSkipped AutoInvestOutcome = "skipped"
+	AutoInvestFailed  AutoInvestOutcome = "failed"
+)
+
+func (s AutoInvestOutcome) IsValid() bool {
+	switch s {
+	case AutoInvestOrdered, AutoInvestSkipped, AutoInvestFailed:
+		return true
+	}
+	return false
+}
@@ -0,0 +1,50 @@
+package enum
+
+type LoanGrade string
+
+const (
+	LoanGradeA LoanGrade = "A"
+	LoanGradeB LoanGrade = "B"
+	LoanGradeC LoanGrade = "C"
+	LoanGradeD LoanGrade = "D"
+	LoanGradeE LoanGrade = "E"
+)
+
+func (s LoanGrade) IsValid() bool {
+	switch s {
+	case LoanGradeA, LoanGradeB, LoanGradeC, LoanGradeD, LoanGradeE:
+		return true
+	}
+	return false
+}
+
+type CreditScoringFactor string
+
+const (
+	// CreditFactorProfitMargin (annual revenue - expense) / annual revenue, in percent
+	CreditFactorProfitMargin CreditScoringFactor = "profit_margin"
+	// CreditFactorLoanToRevenue request amount / annual revenue, in percent
+	CreditFactorLoanToRevenue CreditScoringFactor = "loan_to_revenue"
+	// CreditFactorAnnualRevenue annual revenue, in currency units
+	CreditFactorAnnualRevenue CreditScoringFactor = "annual_revenue"
+	// CreditFactorBusinessAge business age, in years
+	CreditFactorBusinessAge CreditScoringFactor = "business_age"
+	// CreditFactorBusinessSector business sector, matched by name
+	CreditFactorBusinessSector CreditScoringFactor = "business_sector"
+	// CreditFactorTenure loan tenure, in months
+	CreditFactorTenure CreditScoringFactor = "tenure"
+)
+
+func (s CreditScoringFactor) IsValid() bool {
+	switch s {
+	case CreditFactorProfitMargin, CreditFactorLoanToRevenue, CreditFactorAnnualRevenue, CreditFactorBusinessAge,
+		CreditFactorBusinessSector, CreditFactorTenure:
+		return true
+	}
+	return false
+}
+
+// IsNumeric reports whether the rules of the factor match a value range instead of a name
+func (s CreditScoringFactor) IsNumeric() bool {
+	return s != CreditFactorBusinessSector
+}
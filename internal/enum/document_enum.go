@@ -0,0 +1,21 @@
+package enum
+
+type DocumentEntity string
+
+const (
+	DocumentEntityLoan             DocumentEntity = "loan"
+	DocumentEntityLoanApproval     DocumentEntity = "loan_approval"
+	DocumentEntityLoanFunding      DocumentEntity = "loan_funding"
+	DocumentEntityLoanDisbursement DocumentEntity = "loan_disbursement"
+	DocumentEntityBorrower         DocumentEntity = "borrower"
+	DocumentEntityLender           DocumentEntity = "lender"
+)
+
+func (s DocumentEntity) IsValid() bool {
+	switch s {
+	case DocumentEntityLoan, DocumentEntityLoanApproval, DocumentEntityLoanFunding, DocumentEntityLoanDisbursement,
+		DocumentEntityBorrower, DocumentEntityLender:
+		return true
+	}
+	return false
+}
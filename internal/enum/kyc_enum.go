@@ -0,0 +1,18 @@
+package enum
+
+type KycStatus string
+
+const (
+	KycUnverified KycStatus = "unverified"
+	KycPending    KycStatus = "pending"
+	KycVerified   KycStatus = "verified"
+	KycRejected   KycStatus = "rejected"
+)
+
+func (s KycStatus) IsValid() bool {
+	switch s {
+	case KycUnverified, KycPending, KycVerified, KycRejected:
+		return true
+	}
+	return false
+}
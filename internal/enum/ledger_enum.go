@@ -0,0 +1,82 @@
+package enum
+
+type LedgerAccountType string
+
+const (
+	// LedgerLenderWallet money the platform holds for a lender and the lender can still invest or withdraw
+	LedgerLenderWallet LedgerAccountType = "lender_wallet"
+	// LedgerLoanReceivable principal a borrower still owes for a disbursed loan
+	LedgerLoanReceivable LedgerAccountType = "loan_receivable"
+	// LedgerPlatformEscrow lender money committed to loans, until the principal is repaid
+	LedgerPlatformEscrow LedgerAccountType = "platform_escrow"
+	// LedgerPlatformCash the platform bank account, money entering or leaving the platform
+	LedgerPlatformCash LedgerAccountType = "platform_cash"
+	// LedgerPlatformFee fees earned by the platform
+	LedgerPlatformFee LedgerAccountType = "platform_fee"
+	// LedgerTaxPayable tax withheld from lenders, until the platform pays it to the tax office
+	LedgerTaxPayable LedgerAccountType = "tax_payable"
+)
+
+func (s LedgerAccountType) IsValid() bool {
+	switch s {
+	case LedgerLenderWallet, LedgerLoanReceivable, LedgerPlatformEscrow, LedgerPlatformCash, LedgerPlatformFee,
+		LedgerTaxPayable:
+		return true
+	}
+	return false
+}
+
+// NormalBalance returns the side that increases the balance of the account type. Assets are debit
+// accounts, liabilities and revenue are credit accounts.
+func (s LedgerAccountType) NormalBalance() LedgerDirection {
+	switch s {
+	case LedgerLoanReceivable, LedgerPlatformCash:
+		return LedgerDebit
+	}
+	return LedgerCredit
+}
+
+// IsPlatform reports whether the account type has a single platform wide account instead of one per owner
+func (s LedgerAccountType) IsPlatform() bool {
+	switch s {
+	case LedgerPlatformEscrow, LedgerPlatformCash, LedgerPlatformFee, LedgerTaxPayable:
+		return true
+	}
+	return false
+}
+
+type LedgerDirection string
+
+const (
+	LedgerDebit  LedgerDirection = "debit"
+	LedgerCredit LedgerDirection = "credit"
+)
+
+func (s LedgerDirection) IsValid() bool {
+	switch s {
+	case LedgerDebit, LedgerCredit:
+		return true
+	}
+	return false
+}
+
+type LedgerEntryType string
+
+const (
+	LedgerFundingInvested    LedgerEntryType = "funding_invested"
+	LedgerFundingRefunded    LedgerEntryType = "funding_refunded"
+	LedgerLoanDisbursed      LedgerEntryType = "loan_disbursed"
+	LedgerLoanRepaid         LedgerEntryType = "loan_repaid"
+	LedgerWalletTopUp        LedgerEntryType = "wallet_top_up"
+	LedgerWalletWithdrawn    LedgerEntryType = "wallet_withdrawn"
+	LedgerFundingTransferred LedgerEntryType = "funding_transferred"
+)
+
+func (s LedgerEntryType) IsValid() bool {
+	switch s {
+	case LedgerFundingInvested, LedgerFundingRefunded, LedgerLoanDisbursed, LedgerLoanRepaid, LedgerWalletTopUp, LedgerWalletWithdrawn,
+		LedgerFundingTransferred:
+		return true
+	}
+	return false
+}
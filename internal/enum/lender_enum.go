@@ -0,0 +1,44 @@
+package enum
+
+type InvestorClass string
+
+const (
+	InvestorRetail     InvestorClass = "retail"
+	InvestorAccredited InvestorClass = "accredited"
+)
+
+func (s InvestorClass) IsValid() bool {
+	switch s {
+	case InvestorRetail, InvestorAccredited:
+		return true
+	}
+	return false
+}
+
+type RiskProfile string
+
+const (
+	RiskConservative RiskProfile = "conservative"
+	RiskModerate     RiskProfile = "moderate"
+	RiskAggressive   RiskProfile = "aggressive"
+)
+
+func (s RiskProfile) IsValid() bool {
+	switch s {
+	case RiskConservative, RiskModerate, RiskAggressive:
+		return true
+	}
+	return false
+}
+
+// MaxGrade is the worst loan grade a retail lender with the risk profile may invest in
+func (s RiskProfile) MaxGrade() LoanGrade {
+	switch s {
+	case RiskConservative:
+		return LoanGradeB
+	case RiskModerate:
+		return LoanGradeC
+	default:
+		return LoanGradeE
+	}
+}
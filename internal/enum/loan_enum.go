@@ -9,11 +9,13 @@ const (
 	Invested  LoanStatus = "invested"
 	Disbursed LoanStatus = "disbursed"
 	Completed LoanStatus = "completed"
+	Expired   LoanStatus = "expired"
+	Cancelled LoanStatus = "cancelled"
 )
 
 func (s LoanStatus) IsValid() bool {
 	switch s {
-	case Proposed, Rejected, Approved, Invested, Disbursed, Completed:
+	case Proposed, Rejected, Approved, Invested, Disbursed, Completed, Expired, Cancelled:
 		return true
 	}
 	return false
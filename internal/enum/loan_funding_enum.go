@@ -3,16 +3,36 @@ package enum
 type LoanFundingStatus string
 
 const (
-	LoanFundingPending   LoanFundingStatus = "pending"
-	LoanFundingInvested  LoanFundingStatus = "invested"
-	LoanFundingFailed    LoanFundingStatus = "failed"
-	LoanFundingOngoing   LoanFundingStatus = "on_going"
-	LoanFundingCompleted LoanFundingStatus = "completed"
+	LoanFundingPending     LoanFundingStatus = "pending"
+	LoanFundingInvested    LoanFundingStatus = "invested"
+	LoanFundingFailed      LoanFundingStatus = "failed"
+	LoanFundingOngoing     LoanFundingStatus = "on_going"
+	LoanFundingCompleted   LoanFundingStatus = "completed"
+	LoanFundingRefunded    LoanFundingStatus = "refunded"
+	LoanFundingTransferred LoanFundingStatus = "transferred"
+	LoanFundingCancelled   LoanFundingStatus = "cancelled"
 )
 
 func (s LoanFundingStatus) IsValid() bool {
 	switch s {
-	case LoanFundingPending, LoanFundingInvested, LoanFundingFailed, LoanFundingOngoing, LoanFundingCompleted:
+	case LoanFundingPending, LoanFundingInvested, LoanFundingFailed, LoanFundingOngoing, LoanFundingCompleted, LoanFundingRefunded,
+		LoanFundingTransferred, LoanFundingCancelled:
+		return true
+	}
+	return false
+}
+
+type FundingListingStatus string
+
+const (
+	FundingListingOpen      FundingListingStatus = "open"
+	FundingListingSold      FundingListingStatus = "sold"
+	FundingListingCancelled FundingListingStatus = "cancelled"
+)
+
+func (s FundingListingStatus) IsValid() bool {
+	switch s {
+	case FundingListingOpen, FundingListingSold, FundingListingCancelled:
 		return true
 	}
 	return false
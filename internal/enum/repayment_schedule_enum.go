@@ -0,0 +1,17 @@
+package enum
+
+type RepaymentScheduleStatus string
+
+const (
+	RepaymentSchedulePending       RepaymentScheduleStatus = "pending"
+	RepaymentSchedulePartiallyPaid RepaymentScheduleStatus = "partially_paid"
+	RepaymentSchedulePaid          RepaymentScheduleStatus = "paid"
+)
+
+func (s RepaymentScheduleStatus) IsValid() bool {
+	switch s {
+	case RepaymentSchedulePending, RepaymentSchedulePartiallyPaid, RepaymentSchedulePaid:
+		return true
+	}
+	return false
+}
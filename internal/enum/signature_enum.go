@@ -0,0 +1,17 @@
+package enum
+
+type SignatureStatus string
+
+const (
+	SignatureRequested SignatureStatus = "requested"
+	SignatureSigned    SignatureStatus = "signed"
+	SignatureRejected  SignatureStatus = "rejected"
+)
+
+func (s SignatureStatus) IsValid() bool {
+	switch s {
+	case SignatureRequested, SignatureSigned, SignatureRejected:
+		return true
+	}
+	return false
+}
@@ -0,0 +1,31 @@
+package enum
+
+type LenderType string
+
+const (
+	LenderIndividual    LenderType = "individual"
+	LenderInstitutional LenderType = "institutional"
+)
+
+func (s LenderType) IsValid() bool {
+	switch s {
+	case LenderIndividual, LenderInstitutional:
+		return true
+	}
+	return false
+}
+
+type TaxCertificateFormat string
+
+const (
+	TaxCertificateCSV TaxCertificateFormat = "csv"
+	TaxCertificatePDF TaxCertificateFormat = "pdf"
+)
+
+func (s TaxCertificateFormat) IsValid() bool {
+	switch s {
+	case TaxCertificateCSV, TaxCertificatePDF:
+		return true
+	}
+	return false
+}
@@ -0,0 +1,43 @@
+package enum
+
+type WalletTransactionType string
+
+const (
+	WalletTopUp             WalletTransactionType = "top_up"
+	WalletFundingHold       WalletTransactionType = "funding_hold"
+	WalletFundingCapture    WalletTransactionType = "funding_capture"
+	WalletFundingRelease    WalletTransactionType = "funding_release"
+	WalletFundingRefund     WalletTransactionType = "funding_refund"
+	WalletRepayment         WalletTransactionType = "repayment"
+	WalletWithdrawalHold    WalletTransactionType = "withdrawal_hold"
+	WalletWithdrawalRelease WalletTransactionType = "withdrawal_release"
+	WalletWithdrawal        WalletTransactionType = "withdrawal"
+	WalletTransferPurchase  WalletTransactionType = "transfer_purchase"
+	WalletTransferSale      WalletTransactionType = "transfer_sale"
+)
+
+func (s WalletTransactionType) IsValid() bool {
+	switch s {
+	case WalletTopUp, WalletFundingHold, WalletFundingCapture, WalletFundingRelease, WalletFundingRefund,
+		WalletRepayment, WalletWithdrawalHold, WalletWithdrawalRelease, WalletWithdrawal, WalletTransferPurchase,
+		WalletTransferSale:
+		return true
+	}
+	return false
+}
+
+type WalletWithdrawalStatus string
+
+const (
+	WalletWithdrawalPending  WalletWithdrawalStatus = "pending"
+	WalletWithdrawalApproved WalletWithdrawalStatus = "approved"
+	WalletWithdrawalRejected WalletWithdrawalStatus = "rejected"
+)
+
+func (s WalletWithdrawalStatus) IsValid() bool {
+	switch s {
+	case WalletWithdrawalPending, WalletWithdrawalApproved, WalletWithdrawalRejected:
+		return true
+	}
+	return false
+}
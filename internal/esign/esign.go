@@ -0,0 +1,42 @@
+package esign
+
+import (
+	"context"
+	"time"
+)
+
+//go:generate mockgen -source=$GOFILE -destination=$PROJ/internal/generated/mock/mock_$GOPACKAGE/$GOFILE
+
+type (
+	// SignatureRequest asks a signer to sign a stored document
+	SignatureRequest struct {
+		Reference    string // our reference of the request, echoed back by the provider
+		SignerID     int64  // borrower expected to sign
+		DocumentURL  string // URL the provider downloads the document from
+		DocumentHash string // SHA-256 of the document, hex encoded
+	}
+
+	// SignatureSession is the request as opened at the provider
+	SignatureSession struct {
+		ProviderReference string // reference of the request at the provider
+		SigningURL        string // URL the signer signs the document at
+	}
+
+	// Callback is sent by the provider once the signer has signed
+	Callback struct {
+		ProviderReference string
+		SignerID          int64
+		SignedAt          time.Time
+		DocumentHash      string
+		Signature         string // proof the callback comes from the provider, provider specific
+	}
+
+	// Provider is an e-sign provider. The provider collects the signature and calls back with the
+	// signer identity and the hash of the document that was signed, checking that the signed document
+	// is the one we generated is left to the caller.
+	Provider interface {
+		Name() string
+		RequestSignature(ctx context.Context, request SignatureRequest) (*SignatureSession, error)
+		VerifyCallback(ctx context.Context, callback Callback) error
+	}
+)
@@ -0,0 +1,42 @@
+package esign
+
+import (
+	"context"
+	"errors"
+	"github.com/test/loan-service/internal/utils"
+	"strings"
+)
+
+const localReferencePrefix = "LOCAL-"
+
+// LocalProvider is a stub provider for development and testing. It opens every request without
+// contacting anyone and trusts every callback carrying one of its references, the callback has to be
+// sent by hand.
+type LocalProvider struct {
+	signingBaseURL string
+}
+
+func NewLocalProvider(signingBaseURL string) *LocalProvider {
+	return &LocalProvider{
+		signingBaseURL: strings.TrimRight(signingBaseURL, "/"),
+	}
+}
+
+func (p *LocalProvider) Name() string {
+	return "local"
+}
+
+func (p *LocalProvider) RequestSignature(ctx context.Context, request SignatureRequest) (*SignatureSession, error) {
+	reference := localReferencePrefix + utils.GenerateAlphanumericCode(12)
+	return &SignatureSession{
+		ProviderReference: reference,
+		SigningURL:        p.signingBaseURL + "/" + reference,
+	}, nil
+}
+
+func (p *LocalProvider) VerifyCallback(ctx context.Context, callback Callback) error {
+	if !strings.HasPrefix(callback.ProviderReference, localReferencePrefix) {
+		return errors.New("unknown provider reference")
+	}
+	return nil
+}
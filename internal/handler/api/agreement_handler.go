@@ -0,0 +1,144 @@
+package api
+
+import (
+	"errors"
+	"fmt"
+	"github.com/labstack/echo"
+	"github.com/test/loan-service/internal/dto"
+	"github.com/test/loan-service/internal/enum"
+	"github.com/test/loan-service/internal/service"
+	"go.uber.org/dig"
+	"net/http"
+	"strconv"
+)
+
+type (
+	AgreementHandler struct {
+		dig.In
+		agreementSvc service.AgreementSvc
+		documentSvc  service.DocumentSvc
+	}
+)
+
+func NewAgreementHandler(e *echo.Echo, agreementSvc service.AgreementSvc, documentSvc service.DocumentSvc) *AgreementHandler {
+	handler := &AgreementHandler{
+		agreementSvc: agreementSvc,
+		documentSvc:  documentSvc,
+	}
+
+	e.GET("/agreement-templates", handler.GetTemplates)
+	e.POST("/agreement-templates", handler.CreateTemplate)
+	e.GET("/agreement-templates/:id", handler.GetTemplate)
+	e.GET("/loans/:id/agreements", handler.GetByLoanID)
+	e.GET("/loan-fundings/:id/agreements", handler.GetByLoanFundingID)
+	e.GET("/documents/*", handler.GetDocument)
+
+	return handler
+}
+
+// GetTemplates - Handler to list every version of the agreement templates of a type, borrower by default
+func (ah *AgreementHandler) GetTemplates(c echo.Context) error {
+	agreementType := enum.AgreementBorrower
+	if typeStr := c.QueryParam("agreement_type"); typeStr != "" {
+		agreementType = enum.AgreementType(typeStr)
+	}
+
+	ctx := c.Request().Context()
+
+	templates, err := ah.agreementSvc.GetTemplates(ctx, agreementType)
+	if err != nil {
+		return err
+	}
+
+	return dto.SendSuccess(c, templates)
+}
+
+// CreateTemplate - Handler to add the next version of an agreement template
+func (ah *AgreementHandler) CreateTemplate(c echo.Context) error {
+	var request dto.AgreementTemplateRequestDTO
+	err := c.Bind(&request)
+	if err != nil {
+		return errors.New("10002")
+	}
+
+	ctx := c.Request().Context()
+
+	template, err := ah.agreementSvc.CreateTemplate(ctx, &request)
+	if err != nil {
+		return err
+	}
+
+	return dto.SendSuccess(c, template)
+}
+
+// GetTemplate - Handler to get an agreement template
+func (ah *AgreementHandler) GetTemplate(c echo.Context) error {
+	idStr := c.Param("id")
+	id, err := strconv.ParseInt(idStr, 10, 64)
+	if err != nil {
+		return errors.New("10002")
+	}
+
+	ctx := c.Request().Context()
+
+	template, err := ah.agreementSvc.GetTemplate(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	return dto.SendSuccess(c, template)
+}
+
+// GetByLoanID - Handler to list the agreements generated for a loan
+func (ah *AgreementHandler) GetByLoanID(c echo.Context) error {
+	loanIDStr := c.Param("id")
+	loanID, err := strconv.ParseInt(loanIDStr, 10, 64)
+	if err != nil {
+		return errors.New("10002")
+	}
+
+	ctx := c.Request().Context()
+
+	agreements, err := ah.agreementSvc.GetByLoanID(ctx, loanID)
+	if err != nil {
+		return err
+	}
+
+	return dto.SendSuccess(c, agreements)
+}
+
+// GetByLoanFundingID - Handler to list the investment agreements generated for a loan funding
+func (ah *AgreementHandler) GetByLoanFundingID(c echo.Context) error {
+	loanFundingIDStr := c.Param("id")
+	loanFundingID, err := strconv.ParseInt(loanFundingIDStr, 10, 64)
+	if err != nil {
+		return errors.New("10002")
+	}
+
+	ctx := c.Request().Context()
+
+	agreements, err := ah.agreementSvc.GetByLoanFundingID(ctx, loanFundingID)
+	if err != nil {
+		return err
+	}
+
+	return dto.SendSuccess(c, agreements)
+}
+
+// GetDocument - Handler to download a document from the document store
+func (ah *AgreementHandler) GetDocument(c echo.Context) error {
+	key := c.Param("*")
+	if key == "" {
+		return errors.New("10002")
+	}
+
+	ctx := c.Request().Context()
+
+	file, err := ah.documentSvc.Get(ctx, key)
+	if err != nil {
+		return err
+	}
+
+	c.Response().Header().Set(echo.HeaderContentDisposition, fmt.Sprintf("inline; filename=%q", file.FileName))
+	return c.Blob(http.StatusOK, file.ContentType, file.Content)
+}
@@ -0,0 +1,63 @@
+package api
+
+import (
+	"errors"
+	"github.com/labstack/echo"
+	"github.com/test/loan-service/internal/dto"
+	"github.com/test/loan-service/internal/service"
+	"go.uber.org/dig"
+	"strconv"
+)
+
+type (
+	ApprovalLevelHandler struct {
+		dig.In
+		approvalLevelSvc service.ApprovalLevelSvc
+	}
+)
+
+func NewApprovalLevelHandler(e *echo.Echo, approvalLevelSvc service.ApprovalLevelSvc) *ApprovalLevelHandler {
+	handler := &ApprovalLevelHandler{
+		approvalLevelSvc: approvalLevelSvc,
+	}
+
+	e.GET("/approval-levels", handler.GetAll)
+	e.PUT("/approval-levels/:level", handler.Update)
+
+	return handler
+}
+
+// GetAll - Handler to get the levels of the loan approval chain
+func (ah *ApprovalLevelHandler) GetAll(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	levels, err := ah.approvalLevelSvc.GetAll(ctx)
+	if err != nil {
+		return err
+	}
+
+	return dto.SendSuccess(c, levels)
+}
+
+// Update - Handler to update the thresholds of a level of the loan approval chain
+func (ah *ApprovalLevelHandler) Update(c echo.Context) error {
+	level, err := strconv.ParseInt(c.Param("level"), 10, 64)
+	if err != nil {
+		return errors.New("10002")
+	}
+
+	var request dto.ApprovalLevelRequestDTO
+	err = c.Bind(&request)
+	if err != nil {
+		return errors.New("10002")
+	}
+
+	ctx := c.Request().Context()
+
+	err = ah.approvalLevelSvc.Update(ctx, level, &request)
+	if err != nil {
+		return err
+	}
+
+	return dto.SendSuccess(c, "Approval level updated")
+}
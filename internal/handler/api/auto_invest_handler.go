@@ -0,0 +1,134 @@
+package api
+
+import (
+	"errors"
+	"github.com/labstack/echo"
+	"github.com/test/loan-service/internal/dto"
+	"github.com/test/loan-service/internal/service"
+	"go.uber.org/dig"
+	"strconv"
+)
+
+type (
+	AutoInvestHandler struct {
+		dig.In
+		autoInvestSvc service.AutoInvestSvc
+	}
+)
+
+func NewAutoInvestHandler(e *echo.Echo, autoInvestSvc service.AutoInvestSvc) *AutoInvestHandler {
+	handler := &AutoInvestHandler{
+		autoInvestSvc: autoInvestSvc,
+	}
+
+	e.GET("/lenders/:id/auto-invest-rules", handler.GetByLenderID)
+	e.POST("/lenders/:id/auto-invest-rules", handler.Create)
+	e.PUT("/auto-invest-rules/:id", handler.Update)
+	e.DELETE("/auto-invest-rules/:id", handler.Delete)
+	e.GET("/loans/:id/auto-invest-runs", handler.GetRunsByLoanID)
+
+	return handler
+}
+
+// GetByLenderID - Handler to list the auto-invest rules of a lender
+func (ah *AutoInvestHandler) GetByLenderID(c echo.Context) error {
+	lenderIDStr := c.Param("id")
+	lenderID, err := strconv.ParseInt(lenderIDStr, 10, 64)
+	if err != nil {
+		return errors.New("10002")
+	}
+
+	ctx := c.Request().Context()
+
+	rules, err := ah.autoInvestSvc.GetByLenderID(ctx, lenderID)
+	if err != nil {
+		return err
+	}
+
+	return dto.SendSuccess(c, rules)
+}
+
+// Create - Handler to save an auto-invest rule for a lender
+func (ah *AutoInvestHandler) Create(c echo.Context) error {
+	lenderIDStr := c.Param("id")
+	lenderID, err := strconv.ParseInt(lenderIDStr, 10, 64)
+	if err != nil {
+		return errors.New("10002")
+	}
+
+	var request dto.AutoInvestRuleRequestDTO
+	err = c.Bind(&request)
+	if err != nil {
+		return errors.New("10002")
+	}
+	request.LenderID = lenderID
+
+	ctx := c.Request().Context()
+
+	rule, err := ah.autoInvestSvc.Create(ctx, &request)
+	if err != nil {
+		return err
+	}
+
+	return dto.SendSuccess(c, rule)
+}
+
+// Update - Handler to change the criteria, amounts or activation of an auto-invest rule
+func (ah *AutoInvestHandler) Update(c echo.Context) error {
+	ruleIDStr := c.Param("id")
+	ruleID, err := strconv.ParseInt(ruleIDStr, 10, 64)
+	if err != nil {
+		return errors.New("10002")
+	}
+
+	var request dto.AutoInvestRuleRequestDTO
+	err = c.Bind(&request)
+	if err != nil {
+		return errors.New("10002")
+	}
+
+	ctx := c.Request().Context()
+
+	rule, err := ah.autoInvestSvc.Update(ctx, ruleID, &request)
+	if err != nil {
+		return err
+	}
+
+	return dto.SendSuccess(c, rule)
+}
+
+// Delete - Handler to remove an auto-invest rule
+func (ah *AutoInvestHandler) Delete(c echo.Context) error {
+	ruleIDStr := c.Param("id")
+	ruleID, err := strconv.ParseInt(ruleIDStr, 10, 64)
+	if err != nil {
+		return errors.New("10002")
+	}
+
+	ctx := c.Request().Context()
+
+	err = ah.autoInvestSvc.Delete(ctx, ruleID)
+	if err != nil {
+		return err
+	}
+
+	return dto.SendSuccess(c, "Auto-invest rule deleted")
+}
+
+// GetRunsByLoanID - Handler to get the auto-invest runs of a loan with the outcome of every rule
+func (ah *AutoInvestHandler) GetRunsByLoanID(c echo.Context) error {
+	loanIDStr := c.Param("id")
+	loanID, err := strconv.ParseInt(loanIDStr, 10, 64)
+	if err != nil {
+		return errors.New("10002")
+	}
+
+	ctx := c.Request().Context()
+
+	runs, err := ah.autoInvestSvc.GetRunsByLoanID(ctx, loanID)
+	if err != nil {
+		return err
+	}
+
+	return dto.SendSuccess(c, runs)
+}
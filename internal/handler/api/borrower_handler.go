@@ -0,0 +1,148 @@
+package api
+
+import (
+	"errors"
+	"github.com/labstack/echo"
+	"github.com/test/loan-service/internal/dto"
+	"github.com/test/loan-service/internal/service"
+	"go.uber.org/dig"
+	"strconv"
+)
+
+type (
+	BorrowerHandler struct {
+		dig.In
+		borrowerSvc service.BorrowerSvc
+	}
+)
+
+func NewBorrowerHandler(e *echo.Echo, borrowerSvc service.BorrowerSvc) *BorrowerHandler {
+	handler := &BorrowerHandler{
+		borrowerSvc: borrowerSvc,
+	}
+
+	e.POST("/borrowers", handler.Create)
+	e.GET("/borrowers/:id", handler.GetByID)
+	e.PUT("/borrowers/:id", handler.Update)
+	e.POST("/borrowers/:id/kyc/submit", handler.SubmitKyc)
+	e.PUT("/borrowers/:id/kyc", handler.ReviewKyc)
+	e.GET("/borrowers/:id/kyc-history", handler.GetKycHistory)
+
+	return handler
+}
+
+// Create - Handler to register a borrower with an unverified KYC
+func (bh *BorrowerHandler) Create(c echo.Context) error {
+	var request dto.BorrowerRequestDTO
+	err := c.Bind(&request)
+	if err != nil {
+		return errors.New("10002")
+	}
+
+	ctx := c.Request().Context()
+
+	borrower, err := bh.borrowerSvc.Create(ctx, &request)
+	if err != nil {
+		return err
+	}
+
+	return dto.SendSuccess(c, borrower)
+}
+
+// GetByID - Handler to get the profile and KYC status of a borrower
+func (bh *BorrowerHandler) GetByID(c echo.Context) error {
+	borrowerID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		return errors.New("10002")
+	}
+
+	ctx := c.Request().Context()
+
+	borrower, err := bh.borrowerSvc.GetByID(ctx, borrowerID)
+	if err != nil {
+		return err
+	}
+
+	return dto.SendSuccess(c, borrower)
+}
+
+// Update - Handler to update the profile of a borrower
+func (bh *BorrowerHandler) Update(c echo.Context) error {
+	borrowerID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		return errors.New("10002")
+	}
+
+	var request dto.BorrowerRequestDTO
+	err = c.Bind(&request)
+	if err != nil {
+		return errors.New("10002")
+	}
+	request.BorrowerID = borrowerID
+
+	ctx := c.Request().Context()
+
+	borrower, err := bh.borrowerSvc.Update(ctx, &request)
+	if err != nil {
+		return err
+	}
+
+	return dto.SendSuccess(c, borrower)
+}
+
+// SubmitKyc - Handler to send the KYC of a borrower for review once the verification documents are uploaded
+func (bh *BorrowerHandler) SubmitKyc(c echo.Context) error {
+	borrowerID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		return errors.New("10002")
+	}
+
+	ctx := c.Request().Context()
+
+	borrower, err := bh.borrowerSvc.SubmitKyc(ctx, borrowerID)
+	if err != nil {
+		return err
+	}
+
+	return dto.SendSuccess(c, borrower)
+}
+
+// ReviewKyc - Handler to verify or reject the submitted KYC of a borrower
+func (bh *BorrowerHandler) ReviewKyc(c echo.Context) error {
+	borrowerID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		return errors.New("10002")
+	}
+
+	var request dto.KycReviewRequestDTO
+	err = c.Bind(&request)
+	if err != nil {
+		return errors.New("10002")
+	}
+
+	ctx := c.Request().Context()
+
+	borrower, err := bh.borrowerSvc.ReviewKyc(ctx, borrowerID, &request)
+	if err != nil {
+		return err
+	}
+
+	return dto.SendSuccess(c, borrower)
+}
+
+// GetKycHistory - Handler to list every KYC status change of a borrower
+func (bh *BorrowerHandler) GetKycHistory(c echo.Context) error {
+	borrowerID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		return errors.New("10002")
+	}
+
+	ctx := c.Request().Context()
+
+	histories, err := bh.borrowerSvc.GetKycHistory(ctx, borrowerID)
+	if err != nil {
+		return err
+	}
+
+	return dto.SendSuccess(c, histories)
+}
@@ -0,0 +1,137 @@
+package api
+
+import (
+	"errors"
+	"github.com/labstack/echo"
+	"github.com/test/loan-service/internal/dto"
+	"github.com/test/loan-service/internal/enum"
+	"github.com/test/loan-service/internal/service"
+	"go.uber.org/dig"
+	"strconv"
+)
+
+type (
+	CreditScoringHandler struct {
+		dig.In
+		creditScoringSvc service.CreditScoringSvc
+	}
+)
+
+func NewCreditScoringHandler(e *echo.Echo, creditScoringSvc service.CreditScoringSvc) *CreditScoringHandler {
+	handler := &CreditScoringHandler{
+		creditScoringSvc: creditScoringSvc,
+	}
+
+	e.GET("/credit-scoring/rules", handler.GetRules)
+	e.POST("/credit-scoring/rules", handler.CreateRule)
+	e.PUT("/credit-scoring/rules/:id", handler.UpdateRule)
+	e.DELETE("/credit-scoring/rules/:id", handler.DeleteRule)
+	e.GET("/credit-scoring/grades", handler.GetGrades)
+	e.PUT("/credit-scoring/grades/:grade", handler.UpdateGrade)
+
+	return handler
+}
+
+// GetRules - Handler to get every credit scoring rule
+func (ch *CreditScoringHandler) GetRules(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	rules, err := ch.creditScoringSvc.GetRules(ctx)
+	if err != nil {
+		return err
+	}
+
+	return dto.SendSuccess(c, rules)
+}
+
+// CreateRule - Handler to add a credit scoring rule
+func (ch *CreditScoringHandler) CreateRule(c echo.Context) error {
+	var request dto.CreditScoringRuleRequestDTO
+	err := c.Bind(&request)
+	if err != nil {
+		return errors.New("10002")
+	}
+
+	ctx := c.Request().Context()
+
+	rule, err := ch.creditScoringSvc.CreateRule(ctx, &request)
+	if err != nil {
+		return err
+	}
+
+	return dto.SendSuccess(c, rule)
+}
+
+// UpdateRule - Handler to change a credit scoring rule
+func (ch *CreditScoringHandler) UpdateRule(c echo.Context) error {
+	ruleIDStr := c.Param("id")
+	ruleID, err := strconv.ParseInt(ruleIDStr, 10, 64)
+	if err != nil {
+		return errors.New("10002")
+	}
+
+	var request dto.CreditScoringRuleRequestDTO
+	err = c.Bind(&request)
+	if err != nil {
+		return errors.New("10002")
+	}
+
+	ctx := c.Request().Context()
+
+	rule, err := ch.creditScoringSvc.UpdateRule(ctx, ruleID, &request)
+	if err != nil {
+		return err
+	}
+
+	return dto.SendSuccess(c, rule)
+}
+
+// DeleteRule - Handler to remove a credit scoring rule
+func (ch *CreditScoringHandler) DeleteRule(c echo.Context) error {
+	ruleIDStr := c.Param("id")
+	ruleID, err := strconv.ParseInt(ruleIDStr, 10, 64)
+	if err != nil {
+		return errors.New("10002")
+	}
+
+	ctx := c.Request().Context()
+
+	err = ch.creditScoringSvc.DeleteRule(ctx, ruleID)
+	if err != nil {
+		return err
+	}
+
+	return dto.SendSuccess(c, "Credit scoring rule deleted")
+}
+
+// GetGrades - Handler to get the minimum score of every loan grade
+func (ch *CreditScoringHandler) GetGrades(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	grades, err := ch.creditScoringSvc.GetGrades(ctx)
+	if err != nil {
+		return err
+	}
+
+	return dto.SendSuccess(c, grades)
+}
+
+// UpdateGrade - Handler to update the minimum score of a loan grade
+func (ch *CreditScoringHandler) UpdateGrade(c echo.Context) error {
+	grade := enum.LoanGrade(c.Param("grade"))
+
+	var request dto.CreditGradeRequestDTO
+	err := c.Bind(&request)
+	if err != nil {
+		return errors.New("10002")
+	}
+
+	ctx := c.Request().Context()
+
+	err = ch.creditScoringSvc.UpdateGrade(ctx, grade, &request)
+	if err != nil {
+		return err
+	}
+
+	return dto.SendSuccess(c, "Credit grade updated")
+}
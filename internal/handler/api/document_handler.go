@@ -0,0 +1,147 @@
+package api
+
+import (
+	"errors"
+	"fmt"
+	"github.com/labstack/echo"
+	"github.com/test/loan-service/internal/dto"
+	"github.com/test/loan-service/internal/enum"
+	"github.com/test/loan-service/internal/service"
+	"github.com/test/loan-service/internal/service/models"
+	"go.uber.org/dig"
+	"net/http"
+	"strconv"
+)
+
+type (
+	DocumentHandler struct {
+		dig.In
+		documentSvc service.DocumentSvc
+	}
+)
+
+func NewDocumentHandler(e *echo.Echo, documentSvc service.DocumentSvc) *DocumentHandler {
+	handler := &DocumentHandler{
+		documentSvc: documentSvc,
+	}
+
+	e.POST("/files", handler.Upload)
+	e.GET("/files", handler.GetByEntity)
+	e.GET("/files/:id", handler.GetByID)
+	e.GET("/files/:id/download", handler.Download)
+	e.GET("/files/:id/verify", handler.Verify)
+
+	return handler
+}
+
+// Upload - Handler to upload a file of a loan, approval, funding or disbursement as multipart form data
+func (dh *DocumentHandler) Upload(c echo.Context) error {
+	var request dto.DocumentUploadRequestDTO
+	err := c.Bind(&request)
+	if err != nil {
+		return errors.New("10002")
+	}
+
+	fileHeader, err := c.FormFile("file")
+	if err != nil {
+		return errors.New("10002")
+	}
+
+	file, err := fileHeader.Open()
+	if err != nil {
+		return errors.New("10002")
+	}
+	defer file.Close()
+
+	ctx := c.Request().Context()
+
+	document, err := dh.documentSvc.Upload(ctx, &request, models.UploadFile{
+		FileName: fileHeader.Filename,
+		Size:     fileHeader.Size,
+		Content:  file,
+	})
+	if err != nil {
+		return err
+	}
+
+	return dto.SendSuccess(c, document)
+}
+
+// GetByEntity - Handler to list the documents of a record
+func (dh *DocumentHandler) GetByEntity(c echo.Context) error {
+	entityType := enum.DocumentEntity(c.QueryParam("entity_type"))
+	if !entityType.IsValid() {
+		return errors.New("10002")
+	}
+
+	entityID, err := strconv.ParseInt(c.QueryParam("entity_id"), 10, 64)
+	if err != nil {
+		return errors.New("10002")
+	}
+
+	ctx := c.Request().Context()
+
+	documents, err := dh.documentSvc.GetByEntity(ctx, entityType, entityID)
+	if err != nil {
+		return err
+	}
+
+	return dto.SendSuccess(c, documents)
+}
+
+// GetByID - Handler to get a document with a fresh download URL
+func (dh *DocumentHandler) GetByID(c echo.Context) error {
+	documentID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		return errors.New("10002")
+	}
+
+	ctx := c.Request().Context()
+
+	document, err := dh.documentSvc.GetByID(ctx, documentID)
+	if err != nil {
+		return err
+	}
+
+	return dto.SendSuccess(c, document)
+}
+
+// Download - Handler to download the file of a signed download URL
+func (dh *DocumentHandler) Download(c echo.Context) error {
+	documentID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		return errors.New("10002")
+	}
+
+	expires, err := strconv.ParseInt(c.QueryParam("expires"), 10, 64)
+	if err != nil {
+		return errors.New("10018")
+	}
+
+	ctx := c.Request().Context()
+
+	file, err := dh.documentSvc.Download(ctx, documentID, expires, c.QueryParam("signature"))
+	if err != nil {
+		return err
+	}
+
+	c.Response().Header().Set(echo.HeaderContentDisposition, fmt.Sprintf("attachment; filename=%q", file.FileName))
+	return c.Blob(http.StatusOK, file.ContentType, file.Content)
+}
+
+// Verify - Handler to check that the file of a document is unchanged since it was stored
+func (dh *DocumentHandler) Verify(c echo.Context) error {
+	documentID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		return errors.New("10002")
+	}
+
+	ctx := c.Request().Context()
+
+	verification, err := dh.documentSvc.Verify(ctx, documentID)
+	if err != nil {
+		return err
+	}
+
+	return dto.SendSuccess(c, verification)
+}
@@ -0,0 +1,106 @@
+package api
+
+import (
+	"errors"
+	"github.com/labstack/echo"
+	"github.com/test/loan-service/internal/dto"
+	"github.com/test/loan-service/internal/enum"
+	"github.com/test/loan-service/internal/service"
+	"go.uber.org/dig"
+	"strconv"
+)
+
+type (
+	DocumentRequirementHandler struct {
+		dig.In
+		documentRequirementSvc service.DocumentRequirementSvc
+	}
+)
+
+func NewDocumentRequirementHandler(e *echo.Echo, documentRequirementSvc service.DocumentRequirementSvc) *DocumentRequirementHandler {
+	handler := &DocumentRequirementHandler{
+		documentRequirementSvc: documentRequirementSvc,
+	}
+
+	e.GET("/document-requirements", handler.GetAll)
+	e.POST("/document-requirements", handler.Create)
+	e.PUT("/document-requirements/:id", handler.Update)
+	e.DELETE("/document-requirements/:id", handler.Delete)
+
+	return handler
+}
+
+// GetAll - Handler to get the required documents, of a single loan type when loan_type is given
+func (dh *DocumentRequirementHandler) GetAll(c echo.Context) error {
+	loanType := enum.LoanType(c.QueryParam("loan_type"))
+	if loanType != "" && !loanType.IsValid() {
+		return errors.New("10002")
+	}
+
+	ctx := c.Request().Context()
+
+	requirements, err := dh.documentRequirementSvc.GetAll(ctx, loanType)
+	if err != nil {
+		return err
+	}
+
+	return dto.SendSuccess(c, requirements)
+}
+
+// Create - Handler to add a document to the catalog of a loan type
+func (dh *DocumentRequirementHandler) Create(c echo.Context) error {
+	var request dto.DocumentRequirementRequestDTO
+	err := c.Bind(&request)
+	if err != nil {
+		return errors.New("10002")
+	}
+
+	ctx := c.Request().Context()
+
+	requirement, err := dh.documentRequirementSvc.Create(ctx, &request)
+	if err != nil {
+		return err
+	}
+
+	return dto.SendSuccess(c, requirement)
+}
+
+// Update - Handler to change a document of the catalog
+func (dh *DocumentRequirementHandler) Update(c echo.Context) error {
+	requirementID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		return errors.New("10002")
+	}
+
+	var request dto.DocumentRequirementRequestDTO
+	err = c.Bind(&request)
+	if err != nil {
+		return errors.New("10002")
+	}
+
+	ctx := c.Request().Context()
+
+	requirement, err := dh.documentRequirementSvc.Update(ctx, requirementID, &request)
+	if err != nil {
+		return err
+	}
+
+	return dto.SendSuccess(c, requirement)
+}
+
+// Delete - Handler to remove a document from the catalog
+func (dh *DocumentRequirementHandler) Delete(c echo.Context) error {
+	requirementID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		return errors.New("10002")
+	}
+
+	ctx := c.Request().Context()
+
+	err = dh.documentRequirementSvc.Delete(ctx, requirementID)
+	if err != nil {
+		return err
+	}
+
+	return dto.SendSuccess(c, "Document requirement deleted")
+}
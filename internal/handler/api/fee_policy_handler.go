@@ -0,0 +1,60 @@
+package api
+
+import (
+	"errors"
+	"github.com/labstack/echo"
+	"github.com/test/loan-service/internal/dto"
+	"github.com/test/loan-service/internal/enum"
+	"github.com/test/loan-service/internal/service"
+	"go.uber.org/dig"
+)
+
+type (
+	FeePolicyHandler struct {
+		dig.In
+		feePolicySvc service.FeePolicySvc
+	}
+)
+
+func NewFeePolicyHandler(e *echo.Echo, feePolicySvc service.FeePolicySvc) *FeePolicyHandler {
+	handler := &FeePolicyHandler{
+		feePolicySvc: feePolicySvc,
+	}
+
+	e.GET("/fee-policies", handler.GetAll)
+	e.PUT("/fee-policies/:loan_type", handler.Update)
+
+	return handler
+}
+
+// GetAll - Handler to get the platform fees of every loan type
+func (fh *FeePolicyHandler) GetAll(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	policies, err := fh.feePolicySvc.GetAll(ctx)
+	if err != nil {
+		return err
+	}
+
+	return dto.SendSuccess(c, policies)
+}
+
+// Update - Handler to update the platform fees of a loan type
+func (fh *FeePolicyHandler) Update(c echo.Context) error {
+	loanType := enum.LoanType(c.Param("loan_type"))
+
+	var request dto.FeePolicyRequestDTO
+	err := c.Bind(&request)
+	if err != nil {
+		return errors.New("10002")
+	}
+
+	ctx := c.Request().Context()
+
+	err = fh.feePolicySvc.Update(ctx, loanType, &request)
+	if err != nil {
+		return err
+	}
+
+	return dto.SendSuccess(c, "Fee policy updated")
+}
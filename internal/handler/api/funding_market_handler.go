@@ -0,0 +1,167 @@
+package api
+
+import (
+	"errors"
+	"github.com/labstack/echo"
+	"github.com/test/loan-service/internal/dto"
+	"github.com/test/loan-service/internal/enum"
+	repo "github.com/test/loan-service/internal/repository"
+	"github.com/test/loan-service/internal/service"
+	"go.uber.org/dig"
+	"strconv"
+)
+
+type (
+	FundingMarketHandler struct {
+		dig.In
+		fundingMarketSvc service.FundingMarketSvc
+	}
+)
+
+func NewFundingMarketHandler(e *echo.Echo, fundingMarketSvc service.FundingMarketSvc) *FundingMarketHandler {
+	handler := &FundingMarketHandler{
+		fundingMarketSvc: fundingMarketSvc,
+	}
+
+	e.GET("/funding-listings", handler.GetListings)
+	e.POST("/funding-listings", handler.CreateListing)
+	e.GET("/funding-listings/:id", handler.GetListing)
+	e.DELETE("/funding-listings/:id", handler.CancelListing)
+	e.POST("/funding-listings/:id/buy", handler.Buy)
+	e.GET("/lenders/:id/funding-transfers", handler.GetTransfersByLenderID)
+
+	return handler
+}
+
+// GetListings - Handler to list funding listings, filtered by loan and status
+func (fh *FundingMarketHandler) GetListings(c echo.Context) error {
+	var request repo.FundingListingRequest
+
+	loanIDStr := c.QueryParam("loan_id")
+	if loanIDStr != "" {
+		loanID, err := strconv.ParseInt(loanIDStr, 10, 64)
+		if err != nil {
+			return errors.New("10002")
+		}
+		request.LoanID = &loanID
+	}
+
+	status := c.QueryParam("status")
+	if status != "" {
+		request.Status = enum.FundingListingStatus(status)
+		if !request.Status.IsValid() {
+			return errors.New("10002")
+		}
+	}
+
+	ctx := c.Request().Context()
+
+	listings, err := fh.fundingMarketSvc.GetListings(ctx, request)
+	if err != nil {
+		return err
+	}
+
+	return dto.SendSuccess(c, listings)
+}
+
+// CreateListing - Handler for a lender to offer the outstanding principal of an on going funding for sale
+func (fh *FundingMarketHandler) CreateListing(c echo.Context) error {
+	var request dto.FundingListingRequestDTO
+	err := c.Bind(&request)
+	if err != nil {
+		return errors.New("10002")
+	}
+
+	ctx := c.Request().Context()
+
+	listing, err := fh.fundingMarketSvc.CreateListing(ctx, &request)
+	if err != nil {
+		return err
+	}
+
+	return dto.SendSuccess(c, listing)
+}
+
+// GetListing - Handler to get a funding listing
+func (fh *FundingMarketHandler) GetListing(c echo.Context) error {
+	listingIDStr := c.Param("id")
+	listingID, err := strconv.ParseInt(listingIDStr, 10, 64)
+	if err != nil {
+		return errors.New("10002")
+	}
+
+	ctx := c.Request().Context()
+
+	listing, err := fh.fundingMarketSvc.GetListing(ctx, listingID)
+	if err != nil {
+		return err
+	}
+
+	return dto.SendSuccess(c, listing)
+}
+
+// CancelListing - Handler to take an open funding listing off the market
+func (fh *FundingMarketHandler) CancelListing(c echo.Context) error {
+	listingIDStr := c.Param("id")
+	listingID, err := strconv.ParseInt(listingIDStr, 10, 64)
+	if err != nil {
+		return errors.New("10002")
+	}
+
+	var request dto.FundingListingCancelRequestDTO
+	err = c.Bind(&request)
+	if err != nil {
+		return errors.New("10002")
+	}
+
+	ctx := c.Request().Context()
+
+	err = fh.fundingMarketSvc.CancelListing(ctx, listingID, &request)
+	if err != nil {
+		return err
+	}
+
+	return dto.SendSuccess(c, "Funding listing cancelled")
+}
+
+// Buy - Handler for a lender to buy a funding listing
+func (fh *FundingMarketHandler) Buy(c echo.Context) error {
+	listingIDStr := c.Param("id")
+	listingID, err := strconv.ParseInt(listingIDStr, 10, 64)
+	if err != nil {
+		return errors.New("10002")
+	}
+
+	var request dto.FundingPurchaseRequestDTO
+	err = c.Bind(&request)
+	if err != nil {
+		return errors.New("10002")
+	}
+
+	ctx := c.Request().Context()
+
+	transfer, err := fh.fundingMarketSvc.Buy(ctx, listingID, &request)
+	if err != nil {
+		return err
+	}
+
+	return dto.SendSuccess(c, transfer)
+}
+
+// GetTransfersByLenderID - Handler to list the funding transfers a lender sold or bought
+func (fh *FundingMarketHandler) GetTransfersByLenderID(c echo.Context) error {
+	lenderIDStr := c.Param("id")
+	lenderID, err := strconv.ParseInt(lenderIDStr, 10, 64)
+	if err != nil {
+		return errors.New("10002")
+	}
+
+	ctx := c.Request().Context()
+
+	transfers, err := fh.fundingMarketSvc.GetTransfersByLenderID(ctx, lenderID)
+	if err != nil {
+		return err
+	}
+
+	return dto.SendSuccess(c, transfers)
+}
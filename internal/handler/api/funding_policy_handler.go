@@ -0,0 +1,60 @@
+package api
+
+import (
+	"errors"
+	"github.com/labstack/echo"
+	"github.com/test/loan-service/internal/dto"
+	"github.com/test/loan-service/internal/enum"
+	"github.com/test/loan-service/internal/service"
+	"go.uber.org/dig"
+)
+
+type (
+	FundingPolicyHandler struct {
+		dig.In
+		fundingPolicySvc service.FundingPolicySvc
+	}
+)
+
+func NewFundingPolicyHandler(e *echo.Echo, fundingPolicySvc service.FundingPolicySvc) *FundingPolicyHandler {
+	handler := &FundingPolicyHandler{
+		fundingPolicySvc: fundingPolicySvc,
+	}
+
+	e.GET("/funding-policies", handler.GetAll)
+	e.PUT("/funding-policies/:loan_type", handler.Update)
+
+	return handler
+}
+
+// GetAll - Handler to get the partial funding policy of every loan type
+func (fh *FundingPolicyHandler) GetAll(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	policies, err := fh.fundingPolicySvc.GetAll(ctx)
+	if err != nil {
+		return err
+	}
+
+	return dto.SendSuccess(c, policies)
+}
+
+// Update - Handler to update the partial funding policy of a loan type
+func (fh *FundingPolicyHandler) Update(c echo.Context) error {
+	loanType := enum.LoanType(c.Param("loan_type"))
+
+	var request dto.FundingPolicyRequestDTO
+	err := c.Bind(&request)
+	if err != nil {
+		return errors.New("10002")
+	}
+
+	ctx := c.Request().Context()
+
+	err = fh.fundingPolicySvc.Update(ctx, loanType, &request)
+	if err != nil {
+		return err
+	}
+
+	return dto.SendSuccess(c, "Funding policy updated")
+}
@@ -0,0 +1,57 @@
+package api
+
+import (
+	"errors"
+	"github.com/labstack/echo"
+	"github.com/test/loan-service/internal/dto"
+	"github.com/test/loan-service/internal/service"
+	"go.uber.org/dig"
+)
+
+type (
+	InvestmentLimitHandler struct {
+		dig.In
+		limitSvc service.InvestmentLimitSvc
+	}
+)
+
+func NewInvestmentLimitHandler(e *echo.Echo, limitSvc service.InvestmentLimitSvc) *InvestmentLimitHandler {
+	handler := &InvestmentLimitHandler{
+		limitSvc: limitSvc,
+	}
+
+	e.GET("/investment-limits", handler.Get)
+	e.PUT("/investment-limits", handler.Update)
+
+	return handler
+}
+
+// Get - Handler to get the ticket and concentration limits of the platform
+func (ih *InvestmentLimitHandler) Get(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	limit, err := ih.limitSvc.Get(ctx)
+	if err != nil {
+		return err
+	}
+
+	return dto.SendSuccess(c, limit)
+}
+
+// Update - Handler to update the ticket and concentration limits of the platform
+func (ih *InvestmentLimitHandler) Update(c echo.Context) error {
+	var request dto.InvestmentLimitRequestDTO
+	err := c.Bind(&request)
+	if err != nil {
+		return errors.New("10002")
+	}
+
+	ctx := c.Request().Context()
+
+	limit, err := ih.limitSvc.Update(ctx, &request)
+	if err != nil {
+		return err
+	}
+
+	return dto.SendSuccess(c, limit)
+}
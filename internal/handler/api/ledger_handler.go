@@ -0,0 +1,115 @@
+package api
+
+import (
+	"errors"
+	"github.com/labstack/echo"
+	"github.com/test/loan-service/internal/dto"
+	"github.com/test/loan-service/internal/enum"
+	repo "github.com/test/loan-service/internal/repository"
+	"github.com/test/loan-service/internal/service"
+	"github.com/test/loan-service/internal/service/models"
+	"go.uber.org/dig"
+	"strconv"
+)
+
+type (
+	LedgerHandler struct {
+		dig.In
+		ledgerSvc service.LedgerSvc
+	}
+)
+
+func NewLedgerHandler(e *echo.Echo, ledgerSvc service.LedgerSvc) *LedgerHandler {
+	handler := &LedgerHandler{
+		ledgerSvc: ledgerSvc,
+	}
+
+	e.GET("/ledger/accounts", handler.GetAccounts)
+	e.GET("/ledger/accounts/:id", handler.GetAccount)
+	e.GET("/ledger/accounts/:id/statement", handler.GetStatement)
+
+	return handler
+}
+
+// GetAccounts - Handler to list ledger accounts with their balance, filtered by account type and owner
+func (lh *LedgerHandler) GetAccounts(c echo.Context) error {
+	var request repo.LedgerAccountRequest
+
+	accountType := c.QueryParam("account_type")
+	if accountType != "" {
+		request.AccountType = enum.LedgerAccountType(accountType)
+		if !request.AccountType.IsValid() {
+			return errors.New("10002")
+		}
+	}
+
+	ownerIDStr := c.QueryParam("owner_id")
+	if ownerIDStr != "" {
+		ownerID, err := strconv.ParseInt(ownerIDStr, 10, 64)
+		if err != nil {
+			return errors.New("10002")
+		}
+		request.OwnerID = &ownerID
+	}
+
+	ctx := c.Request().Context()
+
+	accounts, err := lh.ledgerSvc.GetAccounts(ctx, request)
+	if err != nil {
+		return err
+	}
+
+	return dto.SendSuccess(c, accounts)
+}
+
+// GetAccount - Handler to get the balance of a ledger account
+func (lh *LedgerHandler) GetAccount(c echo.Context) error {
+	accountIDStr := c.Param("id")
+	accountID, err := strconv.ParseInt(accountIDStr, 10, 64)
+	if err != nil {
+		return errors.New("10002")
+	}
+
+	ctx := c.Request().Context()
+
+	account, err := lh.ledgerSvc.GetAccount(ctx, accountID)
+	if err != nil {
+		return err
+	}
+
+	return dto.SendSuccess(c, account)
+}
+
+// GetStatement - Handler to get the paginated postings of a ledger account with the running balance
+func (lh *LedgerHandler) GetStatement(c echo.Context) error {
+	accountIDStr := c.Param("id")
+	accountID, err := strconv.ParseInt(accountIDStr, 10, 64)
+	if err != nil {
+		return errors.New("10002")
+	}
+
+	page, err := strconv.Atoi(c.QueryParam("page"))
+	if err != nil || page <= 0 {
+		page = 1 // Default to page 1
+	}
+
+	size, err := strconv.Atoi(c.QueryParam("size"))
+	if err != nil || size <= 0 {
+		size = 10 // Default to 10 items per page
+	}
+
+	ctx := c.Request().Context()
+
+	request := models.LedgerStatementRequest{
+		AccountID: accountID,
+		Page:      uint64(page),
+		Size:      uint64(size),
+	}
+
+	lines, totalRecords, err := lh.ledgerSvc.GetStatementPage(ctx, request)
+	if err != nil {
+		return err
+	}
+
+	return dto.SendSuccess(c, dto.PaginationHelper(lines, totalRecords, page, size))
+}
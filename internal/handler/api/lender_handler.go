@@ -0,0 +1,196 @@
+package api
+
+import (
+	"errors"
+	"github.com/labstack/echo"
+	"github.com/test/loan-service/internal/dto"
+	"github.com/test/loan-service/internal/service"
+	"go.uber.org/dig"
+	"strconv"
+)
+
+type (
+	LenderHandler struct {
+		dig.In
+		lenderSvc service.LenderSvc
+	}
+)
+
+func NewLenderHandler(e *echo.Echo, lenderSvc service.LenderSvc) *LenderHandler {
+	handler := &LenderHandler{
+		lenderSvc: lenderSvc,
+	}
+
+	e.POST("/lenders", handler.Create)
+	e.GET("/lenders/:id", handler.GetByID)
+	e.PUT("/lenders/:id", handler.Update)
+	e.POST("/lenders/:id/kyc/submit", handler.SubmitKyc)
+	e.PUT("/lenders/:id/kyc", handler.ReviewKyc)
+	e.GET("/lenders/:id/kyc-history", handler.GetKycHistory)
+	e.POST("/lenders/:id/suitability", handler.SubmitSuitability)
+	e.PUT("/lenders/:id/classification", handler.Classify)
+
+	return handler
+}
+
+// Create - Handler to register a retail lender with an unverified KYC and its tax profile
+func (lh *LenderHandler) Create(c echo.Context) error {
+	var request dto.LenderRequestDTO
+	err := c.Bind(&request)
+	if err != nil {
+		return errors.New("10002")
+	}
+
+	ctx := c.Request().Context()
+
+	lender, err := lh.lenderSvc.Create(ctx, &request)
+	if err != nil {
+		return err
+	}
+
+	return dto.SendSuccess(c, lender)
+}
+
+// GetByID - Handler to get the profile, KYC status, risk profile and classification of a lender
+func (lh *LenderHandler) GetByID(c echo.Context) error {
+	lenderID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		return errors.New("10002")
+	}
+
+	ctx := c.Request().Context()
+
+	lender, err := lh.lenderSvc.GetByID(ctx, lenderID)
+	if err != nil {
+		return err
+	}
+
+	return dto.SendSuccess(c, lender)
+}
+
+// Update - Handler to update the profile of a lender
+func (lh *LenderHandler) Update(c echo.Context) error {
+	lenderID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		return errors.New("10002")
+	}
+
+	var request dto.LenderRequestDTO
+	err = c.Bind(&request)
+	if err != nil {
+		return errors.New("10002")
+	}
+	request.LenderID = lenderID
+
+	ctx := c.Request().Context()
+
+	lender, err := lh.lenderSvc.Update(ctx, &request)
+	if err != nil {
+		return err
+	}
+
+	return dto.SendSuccess(c, lender)
+}
+
+// SubmitKyc - Handler to send the KYC of a lender for review once the verification documents are uploaded
+func (lh *LenderHandler) SubmitKyc(c echo.Context) error {
+	lenderID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		return errors.New("10002")
+	}
+
+	ctx := c.Request().Context()
+
+	lender, err := lh.lenderSvc.SubmitKyc(ctx, lenderID)
+	if err != nil {
+		return err
+	}
+
+	return dto.SendSuccess(c, lender)
+}
+
+// ReviewKyc - Handler to verify or reject the submitted KYC of a lender
+func (lh *LenderHandler) ReviewKyc(c echo.Context) error {
+	lenderID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		return errors.New("10002")
+	}
+
+	var request dto.KycReviewRequestDTO
+	err = c.Bind(&request)
+	if err != nil {
+		return errors.New("10002")
+	}
+
+	ctx := c.Request().Context()
+
+	lender, err := lh.lenderSvc.ReviewKyc(ctx, lenderID, &request)
+	if err != nil {
+		return err
+	}
+
+	return dto.SendSuccess(c, lender)
+}
+
+// GetKycHistory - Handler to list every KYC status change of a lender
+func (lh *LenderHandler) GetKycHistory(c echo.Context) error {
+	lenderID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		return errors.New("10002")
+	}
+
+	ctx := c.Request().Context()
+
+	histories, err := lh.lenderSvc.GetKycHistory(ctx, lenderID)
+	if err != nil {
+		return err
+	}
+
+	return dto.SendSuccess(c, histories)
+}
+
+// SubmitSuitability - Handler to score the suitability questionnaire of a lender and set its risk profile
+func (lh *LenderHandler) SubmitSuitability(c echo.Context) error {
+	lenderID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		return errors.New("10002")
+	}
+
+	var request dto.SuitabilityRequestDTO
+	err = c.Bind(&request)
+	if err != nil {
+		return errors.New("10002")
+	}
+
+	ctx := c.Request().Context()
+
+	lender, err := lh.lenderSvc.SubmitSuitability(ctx, lenderID, &request)
+	if err != nil {
+		return err
+	}
+
+	return dto.SendSuccess(c, lender)
+}
+
+// Classify - Handler to classify a lender as a retail or accredited investor
+func (lh *LenderHandler) Classify(c echo.Context) error {
+	lenderID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		return errors.New("10002")
+	}
+
+	var request dto.InvestorClassRequestDTO
+	err = c.Bind(&request)
+	if err != nil {
+		return errors.New("10002")
+	}
+
+	ctx := c.Request().Context()
+
+	lender, err := lh.lenderSvc.Classify(ctx, lenderID, &request)
+	if err != nil {
+		return err
+	}
+
+	return dto.SendSuccess(c, lender)
+}
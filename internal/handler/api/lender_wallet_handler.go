@@ -0,0 +1,187 @@
+package api
+
+import (
+	"errors"
+	"github.com/labstack/echo"
+	"github.com/test/loan-service/internal/dto"
+	"github.com/test/loan-service/internal/enum"
+	repo "github.com/test/loan-service/internal/repository"
+	"github.com/test/loan-service/internal/service"
+	"go.uber.org/dig"
+	"strconv"
+)
+
+type (
+	LenderWalletHandler struct {
+		dig.In
+		walletSvc     service.LenderWalletSvc
+		withdrawalSvc service.WalletWithdrawalSvc
+	}
+)
+
+func NewLenderWalletHandler(e *echo.Echo, walletSvc service.LenderWalletSvc, withdrawalSvc service.WalletWithdrawalSvc) *LenderWalletHandler {
+	handler := &LenderWalletHandler{
+		walletSvc:     walletSvc,
+		withdrawalSvc: withdrawalSvc,
+	}
+
+	e.GET("/lenders/:id/wallet", handler.GetWallet)
+	e.POST("/lenders/:id/wallet/top-ups", handler.TopUp)
+	e.GET("/lenders/:id/wallet/transactions", handler.GetTransactions)
+	e.POST("/lenders/:id/wallet/withdrawals", handler.CreateWithdrawal)
+	e.GET("/lenders/:id/wallet/withdrawals", handler.GetWithdrawalsByLenderID)
+	e.GET("/wallet-withdrawals", handler.GetWithdrawals)
+	e.PUT("/wallet-withdrawals/:id/review", handler.ReviewWithdrawal)
+
+	return handler
+}
+
+// GetWallet - Handler to get the available and held balance of a lender wallet
+func (wh *LenderWalletHandler) GetWallet(c echo.Context) error {
+	lenderIDStr := c.Param("id")
+	lenderID, err := strconv.ParseInt(lenderIDStr, 10, 64)
+	if err != nil {
+		return errors.New("10002")
+	}
+
+	ctx := c.Request().Context()
+
+	wallet, err := wh.walletSvc.GetByLenderID(ctx, lenderID)
+	if err != nil {
+		return err
+	}
+
+	return dto.SendSuccess(c, wallet)
+}
+
+// TopUp - Handler to credit money paid in by a lender
+func (wh *LenderWalletHandler) TopUp(c echo.Context) error {
+	lenderIDStr := c.Param("id")
+	lenderID, err := strconv.ParseInt(lenderIDStr, 10, 64)
+	if err != nil {
+		return errors.New("10002")
+	}
+
+	var request dto.WalletTopUpRequestDTO
+	err = c.Bind(&request)
+	if err != nil {
+		return errors.New("10002")
+	}
+	request.LenderID = lenderID
+
+	ctx := c.Request().Context()
+
+	transaction, err := wh.walletSvc.TopUp(ctx, &request)
+	if err != nil {
+		return err
+	}
+
+	return dto.SendSuccess(c, transaction)
+}
+
+// GetTransactions - Handler to get every movement of a lender wallet
+func (wh *LenderWalletHandler) GetTransactions(c echo.Context) error {
+	lenderIDStr := c.Param("id")
+	lenderID, err := strconv.ParseInt(lenderIDStr, 10, 64)
+	if err != nil {
+		return errors.New("10002")
+	}
+
+	ctx := c.Request().Context()
+
+	transactions, err := wh.walletSvc.GetTransactions(ctx, lenderID)
+	if err != nil {
+		return err
+	}
+
+	return dto.SendSuccess(c, transactions)
+}
+
+// CreateWithdrawal - Handler to request a withdrawal from a lender wallet
+func (wh *LenderWalletHandler) CreateWithdrawal(c echo.Context) error {
+	lenderIDStr := c.Param("id")
+	lenderID, err := strconv.ParseInt(lenderIDStr, 10, 64)
+	if err != nil {
+		return errors.New("10002")
+	}
+
+	var request dto.WalletWithdrawalRequestDTO
+	err = c.Bind(&request)
+	if err != nil {
+		return errors.New("10002")
+	}
+	request.LenderID = lenderID
+
+	ctx := c.Request().Context()
+
+	withdrawal, err := wh.withdrawalSvc.Create(ctx, &request)
+	if err != nil {
+		return err
+	}
+
+	return dto.SendSuccess(c, withdrawal)
+}
+
+// GetWithdrawalsByLenderID - Handler to get the withdrawals of a lender
+func (wh *LenderWalletHandler) GetWithdrawalsByLenderID(c echo.Context) error {
+	lenderIDStr := c.Param("id")
+	lenderID, err := strconv.ParseInt(lenderIDStr, 10, 64)
+	if err != nil {
+		return errors.New("10002")
+	}
+
+	ctx := c.Request().Context()
+
+	withdrawals, err := wh.withdrawalSvc.GetAll(ctx, repo.WalletWithdrawalRequest{LenderID: &lenderID})
+	if err != nil {
+		return err
+	}
+
+	return dto.SendSuccess(c, withdrawals)
+}
+
+// GetWithdrawals - Handler to list withdrawals for staff review, filtered by status
+func (wh *LenderWalletHandler) GetWithdrawals(c echo.Context) error {
+	var request repo.WalletWithdrawalRequest
+
+	status := c.QueryParam("status")
+	if status != "" {
+		request.Status = enum.WalletWithdrawalStatus(status)
+		if !request.Status.IsValid() {
+			return errors.New("10002")
+		}
+	}
+
+	ctx := c.Request().Context()
+
+	withdrawals, err := wh.withdrawalSvc.GetAll(ctx, request)
+	if err != nil {
+		return err
+	}
+
+	return dto.SendSuccess(c, withdrawals)
+}
+
+// ReviewWithdrawal - Handler for staff to approve or reject a pending withdrawal
+func (wh *LenderWalletHandler) ReviewWithdrawal(c echo.Context) error {
+	withdrawalIDStr := c.Param("id")
+	withdrawalID, err := strconv.ParseInt(withdrawalIDStr, 10, 64)
+	if err != nil {
+		return errors.New("10002")
+	}
+
+	var request dto.WalletWithdrawalReviewRequestDTO
+	err = c.Bind(&request)
+	if err != nil {
+		return errors.New("10002")
+	}
+
+	ctx := c.Request().Context()
+
+	withdrawal, err := wh.withdrawalSvc.Review(ctx, withdrawalID, &request)
+	if err != nil {
+		return err
+	}
+
+	return dto.SendSuccess(c, withdrawal)
+}
@@ -25,6 +25,7 @@ func NewLoanFundingHandler(e *echo.Echo, loanFundingSvc service.LoanFundingSvc)
 	e.POST("/loan-fundings", handler.Create)
 	e.GET("/loan-fundings/:id", handler.GetByID)
 	e.GET("/loan-fundings/lender/:lender_id", handler.GetByLenderID)
+	e.POST("/loan-fundings/:id/cancel", handler.Cancel)
 
 	return handler
 }
@@ -85,3 +86,26 @@ func (lh *LoanFundingHandler) GetByLenderID(c echo.Context) error {
 
 	return dto.SendSuccess(c, loanFundings)
 }
+
+// Cancel - Handler to cancel a loan funding on request of its lender
+func (lh *LoanFundingHandler) Cancel(c echo.Context) error {
+	loanFundingID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		return errors.New("10002")
+	}
+
+	var request dto.LoanFundingCancelRequestDTO
+	err = c.Bind(&request)
+	if err != nil {
+		return errors.New("10002")
+	}
+
+	ctx := c.Request().Context()
+
+	err = lh.loanFundingSvc.Cancel(ctx, loanFundingID, &request)
+	if err != nil {
+		return err
+	}
+
+	return dto.SendSuccess(c, "Loan funding cancelled")
+}
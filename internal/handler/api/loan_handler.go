@@ -27,6 +27,8 @@ func NewLoanHandler(e *echo.Echo, loanSvc service.LoanSvc) *LoanCtrlImpl {
 	e.POST("/loans", handler.Create)
 	e.GET("/loans", handler.GetAll)
 	e.GET("/loans/:id", handler.GetByID)
+	e.PUT("/loans/:id/partial-funding-consent", handler.UpdatePartialFundingConsent)
+	e.POST("/loans/:id/cancel", handler.Cancel)
 
 	return handler
 }
@@ -109,3 +111,50 @@ func (ic LoanCtrlImpl) GetByID(c echo.Context) (err error) {
 	// Return the loan details
 	return dto.SendSuccess(c, loan)
 }
+
+func (ic LoanCtrlImpl) UpdatePartialFundingConsent(c echo.Context) (err error) {
+	loanIDStr := c.Param("id")
+	loanID, err := strconv.ParseInt(loanIDStr, 10, 64)
+	if err != nil {
+		return errors.New("10002")
+	}
+
+	var request dto.LoanPartialFundingConsentRequestDTO
+	err = c.Bind(&request)
+	if err != nil {
+		return errors.New("10002")
+	}
+
+	ctx := c.Request().Context()
+
+	err = ic.loanSvc.UpdatePartialFundingConsent(ctx, loanID, request.Consent)
+	if err != nil {
+		return err
+	}
+
+	return dto.SendSuccess(c, "Partial funding consent updated")
+}
+
+// Cancel - Handler to withdraw a proposed loan or cancel an approved loan during funding on request of its borrower
+func (ic LoanCtrlImpl) Cancel(c echo.Context) (err error) {
+	loanIDStr := c.Param("id")
+	loanID, err := strconv.ParseInt(loanIDStr, 10, 64)
+	if err != nil {
+		return errors.New("10002")
+	}
+
+	var request dto.LoanCancelRequestDTO
+	err = c.Bind(&request)
+	if err != nil {
+		return errors.New("10002")
+	}
+
+	ctx := c.Request().Context()
+
+	err = ic.loanSvc.Cancel(ctx, loanID, &request)
+	if err != nil {
+		return err
+	}
+
+	return dto.SendSuccess(c, "Loan cancelled")
+}
@@ -0,0 +1,128 @@
+package api
+
+import (
+	"errors"
+	"github.com/labstack/echo"
+	"github.com/test/loan-service/internal/dto"
+	"github.com/test/loan-service/internal/enum"
+	repo "github.com/test/loan-service/internal/repository"
+	"github.com/test/loan-service/internal/service"
+	"go.uber.org/dig"
+	"strconv"
+)
+
+type (
+	LoanPricingHandler struct {
+		dig.In
+		loanPricingSvc service.LoanPricingSvc
+	}
+)
+
+func NewLoanPricingHandler(e *echo.Echo, loanPricingSvc service.LoanPricingSvc) *LoanPricingHandler {
+	handler := &LoanPricingHandler{
+		loanPricingSvc: loanPricingSvc,
+	}
+
+	e.GET("/loan-pricings", handler.GetAll)
+	e.GET("/loan-pricings/:id", handler.GetByID)
+	e.POST("/loan-pricings", handler.Create)
+	e.PUT("/loan-pricings/:id", handler.Update)
+	e.DELETE("/loan-pricings/:id", handler.Delete)
+
+	return handler
+}
+
+// GetAll - Handler to list loan pricings, filtered by loan grade and loan type
+func (lh *LoanPricingHandler) GetAll(c echo.Context) error {
+	request := repo.LoanPricingRequest{
+		LoanGrade: enum.LoanGrade(c.QueryParam("loan_grade")),
+		LoanType:  enum.LoanType(c.QueryParam("loan_type")),
+	}
+
+	ctx := c.Request().Context()
+
+	pricings, err := lh.loanPricingSvc.GetAll(ctx, request)
+	if err != nil {
+		return err
+	}
+
+	return dto.SendSuccess(c, pricings)
+}
+
+// GetByID - Handler to get a loan pricing
+func (lh *LoanPricingHandler) GetByID(c echo.Context) error {
+	pricingIDStr := c.Param("id")
+	pricingID, err := strconv.ParseInt(pricingIDStr, 10, 64)
+	if err != nil {
+		return errors.New("10002")
+	}
+
+	ctx := c.Request().Context()
+
+	pricing, err := lh.loanPricingSvc.GetByID(ctx, pricingID)
+	if err != nil {
+		return err
+	}
+
+	return dto.SendSuccess(c, pricing)
+}
+
+// Create - Handler to add a loan pricing version
+func (lh *LoanPricingHandler) Create(c echo.Context) error {
+	var request dto.LoanPricingRequestDTO
+	err := c.Bind(&request)
+	if err != nil {
+		return errors.New("10002")
+	}
+
+	ctx := c.Request().Context()
+
+	pricing, err := lh.loanPricingSvc.Create(ctx, &request)
+	if err != nil {
+		return err
+	}
+
+	return dto.SendSuccess(c, pricing)
+}
+
+// Update - Handler to change a loan pricing, or end one already in effect
+func (lh *LoanPricingHandler) Update(c echo.Context) error {
+	pricingIDStr := c.Param("id")
+	pricingID, err := strconv.ParseInt(pricingIDStr, 10, 64)
+	if err != nil {
+		return errors.New("10002")
+	}
+
+	var request dto.LoanPricingRequestDTO
+	err = c.Bind(&request)
+	if err != nil {
+		return errors.New("10002")
+	}
+
+	ctx := c.Request().Context()
+
+	pricing, err := lh.loanPricingSvc.Update(ctx, pricingID, &request)
+	if err != nil {
+		return err
+	}
+
+	return dto.SendSuccess(c, pricing)
+}
+
+// Delete - Handler to remove a loan pricing that has not taken effect yet
+func (lh *LoanPricingHandler) Delete(c echo.Context) error {
+	pricingIDStr := c.Param("id")
+	pricingID, err := strconv.ParseInt(pricingIDStr, 10, 64)
+	if err != nil {
+		return errors.New("10002")
+	}
+
+	ctx := c.Request().Context()
+
+	err = lh.loanPricingSvc.Delete(ctx, pricingID)
+	if err != nil {
+		return err
+	}
+
+	return dto.SendSuccess(c, "Loan pricing deleted")
+}
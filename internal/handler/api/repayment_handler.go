@@ -0,0 +1,91 @@
+package api
+
+import (
+	"errors"
+	"github.com/labstack/echo"
+	"github.com/test/loan-service/internal/dto"
+	"github.com/test/loan-service/internal/service"
+	"go.uber.org/dig"
+	"strconv"
+)
+
+type (
+	RepaymentHandler struct {
+		dig.In
+		repaymentScheduleSvc service.RepaymentScheduleSvc
+		loanRepaymentSvc     service.LoanRepaymentSvc
+	}
+)
+
+func NewRepaymentHandler(e *echo.Echo, repaymentScheduleSvc service.RepaymentScheduleSvc, loanRepaymentSvc service.LoanRepaymentSvc) *RepaymentHandler {
+	handler := &RepaymentHandler{
+		repaymentScheduleSvc: repaymentScheduleSvc,
+		loanRepaymentSvc:     loanRepaymentSvc,
+	}
+
+	e.GET("/loans/:id/repayment-schedule", handler.GetSchedule)
+	e.POST("/loans/:id/repayments", handler.Create)
+	e.GET("/loans/:id/repayments", handler.GetByLoanID)
+
+	return handler
+}
+
+// GetSchedule - Handler to get the borrower repayment schedule of a loan
+func (rh *RepaymentHandler) GetSchedule(c echo.Context) error {
+	loanIDStr := c.Param("id")
+	loanID, err := strconv.ParseInt(loanIDStr, 10, 64)
+	if err != nil {
+		return errors.New("10002")
+	}
+
+	ctx := c.Request().Context()
+
+	schedules, err := rh.repaymentScheduleSvc.GetByLoanID(ctx, loanID)
+	if err != nil {
+		return err
+	}
+
+	return dto.SendSuccess(c, schedules)
+}
+
+// Create - Handler to record a borrower repayment
+func (rh *RepaymentHandler) Create(c echo.Context) error {
+	loanIDStr := c.Param("id")
+	loanID, err := strconv.ParseInt(loanIDStr, 10, 64)
+	if err != nil {
+		return errors.New("10002")
+	}
+
+	var request dto.LoanRepaymentRequestDTO
+	err = c.Bind(&request)
+	if err != nil {
+		return errors.New("10002")
+	}
+
+	ctx := c.Request().Context()
+
+	repayment, err := rh.loanRepaymentSvc.Create(ctx, loanID, &request)
+	if err != nil {
+		return err
+	}
+
+	return dto.SendSuccess(c, repayment)
+}
+
+// GetByLoanID - Handler to get every repayment of a loan with its lender allocations
+func (rh *RepaymentHandler) GetByLoanID(c echo.Context) error {
+	loanIDStr := c.Param("id")
+	loanID, err := strconv.ParseInt(loanIDStr, 10, 64)
+	if err != nil {
+		return errors.New("10002")
+	}
+
+	ctx := c.Request().Context()
+
+	repayments, err := rh.loanRepaymentSvc.GetByLoanID(ctx, loanID)
+	if err != nil {
+		return err
+	}
+
+	return dto.SendSuccess(c, repayments)
+}
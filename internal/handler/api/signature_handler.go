@@ -0,0 +1,83 @@
+package api
+
+import (
+	"errors"
+	"github.com/labstack/echo"
+	"github.com/test/loan-service/internal/dto"
+	"github.com/test/loan-service/internal/service"
+	"go.uber.org/dig"
+	"strconv"
+)
+
+type (
+	SignatureHandler struct {
+		dig.In
+		signatureSvc service.SignatureSvc
+	}
+)
+
+func NewSignatureHandler(e *echo.Echo, signatureSvc service.SignatureSvc) *SignatureHandler {
+	handler := &SignatureHandler{
+		signatureSvc: signatureSvc,
+	}
+
+	e.GET("/loan-disbursements/:id/signatures", handler.GetByDisbursementID)
+	e.POST("/loan-disbursements/:id/signatures", handler.Reissue)
+	e.POST("/esign/callback", handler.Callback)
+
+	return handler
+}
+
+// GetByDisbursementID - Handler to list the signature requests of a disbursement
+func (sh *SignatureHandler) GetByDisbursementID(c echo.Context) error {
+	disbursementIDStr := c.Param("id")
+	disbursementID, err := strconv.ParseInt(disbursementIDStr, 10, 64)
+	if err != nil {
+		return errors.New("10002")
+	}
+
+	ctx := c.Request().Context()
+
+	signatures, err := sh.signatureSvc.GetByDisbursementID(ctx, disbursementID)
+	if err != nil {
+		return err
+	}
+
+	return dto.SendSuccess(c, signatures)
+}
+
+// Reissue - Handler to send the borrower a new signing request for the agreement of a pending disbursement
+func (sh *SignatureHandler) Reissue(c echo.Context) error {
+	disbursementIDStr := c.Param("id")
+	disbursementID, err := strconv.ParseInt(disbursementIDStr, 10, 64)
+	if err != nil {
+		return errors.New("10002")
+	}
+
+	ctx := c.Request().Context()
+
+	signature, err := sh.signatureSvc.Reissue(ctx, disbursementID)
+	if err != nil {
+		return err
+	}
+
+	return dto.SendSuccess(c, signature)
+}
+
+// Callback - Handler for the e-sign provider to report a signed agreement
+func (sh *SignatureHandler) Callback(c echo.Context) error {
+	var request dto.SignatureCallbackRequestDTO
+	err := c.Bind(&request)
+	if err != nil {
+		return errors.New("10002")
+	}
+
+	ctx := c.Request().Context()
+
+	signature, err := sh.signatureSvc.Callback(ctx, &request)
+	if err != nil {
+		return err
+	}
+
+	return dto.SendSuccess(c, signature)
+}
@@ -0,0 +1,143 @@
+package api
+
+import (
+	"errors"
+	"fmt"
+	"github.com/labstack/echo"
+	"github.com/test/loan-service/internal/dto"
+	"github.com/test/loan-service/internal/enum"
+	"github.com/test/loan-service/internal/service"
+	"github.com/test/loan-service/internal/service/models"
+	"go.uber.org/dig"
+	"net/http"
+	"strconv"
+)
+
+type (
+	TaxHandler struct {
+		dig.In
+		taxSvc service.TaxSvc
+	}
+)
+
+func NewTaxHandler(e *echo.Echo, taxSvc service.TaxSvc) *TaxHandler {
+	handler := &TaxHandler{
+		taxSvc: taxSvc,
+	}
+
+	e.GET("/lenders/:id/tax-profile", handler.GetProfile)
+	e.PUT("/lenders/:id/tax-profile", handler.UpdateProfile)
+	e.GET("/lenders/:id/tax-certificates/:year", handler.GetCertificate)
+	e.GET("/withholding-tax-rates", handler.GetRates)
+	e.PUT("/withholding-tax-rates/:lender_type", handler.UpdateRate)
+
+	return handler
+}
+
+// GetProfile - Handler to get the lender type, tax ID and withholding tax rate of a lender
+func (th *TaxHandler) GetProfile(c echo.Context) error {
+	lenderIDStr := c.Param("id")
+	lenderID, err := strconv.ParseInt(lenderIDStr, 10, 64)
+	if err != nil {
+		return errors.New("10002")
+	}
+
+	ctx := c.Request().Context()
+
+	profile, err := th.taxSvc.GetProfile(ctx, lenderID)
+	if err != nil {
+		return err
+	}
+
+	return dto.SendSuccess(c, profile)
+}
+
+// UpdateProfile - Handler to set the lender type and tax ID of a lender
+func (th *TaxHandler) UpdateProfile(c echo.Context) error {
+	lenderIDStr := c.Param("id")
+	lenderID, err := strconv.ParseInt(lenderIDStr, 10, 64)
+	if err != nil {
+		return errors.New("10002")
+	}
+
+	var request dto.LenderTaxProfileRequestDTO
+	err = c.Bind(&request)
+	if err != nil {
+		return errors.New("10002")
+	}
+	request.LenderID = lenderID
+
+	ctx := c.Request().Context()
+
+	profile, err := th.taxSvc.UpdateProfile(ctx, &request)
+	if err != nil {
+		return err
+	}
+
+	return dto.SendSuccess(c, profile)
+}
+
+// GetCertificate - Handler to download the yearly tax certificate of a lender as csv (default) or pdf
+func (th *TaxHandler) GetCertificate(c echo.Context) error {
+	lenderIDStr := c.Param("id")
+	lenderID, err := strconv.ParseInt(lenderIDStr, 10, 64)
+	if err != nil {
+		return errors.New("10002")
+	}
+
+	year, err := strconv.Atoi(c.Param("year"))
+	if err != nil {
+		return errors.New("10002")
+	}
+
+	format := enum.TaxCertificateCSV
+	if formatStr := c.QueryParam("format"); formatStr != "" {
+		format = enum.TaxCertificateFormat(formatStr)
+	}
+
+	ctx := c.Request().Context()
+
+	file, err := th.taxSvc.GetCertificate(ctx, models.TaxCertificateRequest{
+		LenderID: lenderID,
+		Year:     year,
+		Format:   format,
+	})
+	if err != nil {
+		return err
+	}
+
+	c.Response().Header().Set(echo.HeaderContentDisposition, fmt.Sprintf("attachment; filename=%q", file.FileName))
+	return c.Blob(http.StatusOK, file.ContentType, file.Content)
+}
+
+// GetRates - Handler to get the withholding tax rates of every lender type
+func (th *TaxHandler) GetRates(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	rates, err := th.taxSvc.GetRates(ctx)
+	if err != nil {
+		return err
+	}
+
+	return dto.SendSuccess(c, rates)
+}
+
+// UpdateRate - Handler to update the withholding tax rates of a lender type
+func (th *TaxHandler) UpdateRate(c echo.Context) error {
+	lenderType := enum.LenderType(c.Param("lender_type"))
+
+	var request dto.WithholdingTaxRateRequestDTO
+	err := c.Bind(&request)
+	if err != nil {
+		return errors.New("10002")
+	}
+
+	ctx := c.Request().Context()
+
+	err = th.taxSvc.UpdateRate(ctx, lenderType, &request)
+	if err != nil {
+		return err
+	}
+
+	return dto.SendSuccess(c, "Withholding tax rate updated")
+}
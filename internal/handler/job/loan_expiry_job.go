@@ -0,0 +1,50 @@
+package job
+
+import (
+	"context"
+	"github.com/sirupsen/logrus"
+	"github.com/test/loan-service/internal/infra"
+	"github.com/test/loan-service/internal/service"
+	"time"
+)
+
+type loanExpiryJob struct {
+	interval      time.Duration
+	loanExpirySvc service.LoanExpirySvc
+}
+
+// NewLoanExpiryJob membuat job untuk meng-expire loan yang melewati funding deadline dan memulai job
+func NewLoanExpiryJob(cfg *infra.JobCfg, loanExpirySvc service.LoanExpirySvc) error {
+	job := loanExpiryJob{
+		interval:      cfg.LoanExpiryInterval,
+		loanExpirySvc: loanExpirySvc,
+	}
+
+	// start job
+	go job.start()
+
+	return nil
+}
+
+func (job *loanExpiryJob) start() {
+	ticker := time.NewTicker(job.interval)
+	defer ticker.Stop()
+
+	// run once on startup so loans that expired while the service was down are handled right away
+	job.run()
+	for range ticker.C {
+		job.run()
+	}
+}
+
+func (job *loanExpiryJob) run() {
+	logrus.Info("Running loan expiry job")
+
+	expired, err := job.loanExpirySvc.ExpireOverdueLoans(context.Background())
+	if err != nil {
+		logrus.Errorf("Error running loan expiry job: %v", err)
+		return
+	}
+
+	logrus.Infof("Loan expiry job finished, %d loan expired", expired)
+}
@@ -1,9 +1,11 @@
 package middleware
 
 import (
+	"errors"
 	"github.com/labstack/echo"
 	"github.com/test/loan-service/internal/dto"
 	"strconv"
+	"strings"
 )
 
 func ErrorHandlerMiddleware(next echo.HandlerFunc) echo.HandlerFunc {
@@ -28,6 +30,11 @@ func SuccessHandlerMiddleware(next echo.HandlerFunc) echo.HandlerFunc {
 				return dto.SendError(c, 500, "Internal error")
 			}
 
+			var detailErr *dto.DetailError
+			if errors.As(err, &detailErr) && len(detailErr.Details) > 0 {
+				msg = msg + ": " + strings.Join(detailErr.Details, ", ")
+			}
+
 			var code int
 			code, err = strconv.Atoi(err.Error())
 			// Tangani error yang terjadi di handler
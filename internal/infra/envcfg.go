@@ -43,3 +43,30 @@ func LoadEchoCfg() (*EchoCfg, error) {
 	}
 	return &cfg, nil
 }
+
+func LoadJobCfg() (*JobCfg, error) {
+	var cfg JobCfg
+	prefix := "JOB"
+	if err := envconfig.Process(prefix, &cfg); err != nil {
+		return nil, fmt.Errorf("%s: %w", prefix, err)
+	}
+	return &cfg, nil
+}
+
+func LoadStorageCfg() (*StorageCfg, error) {
+	var cfg StorageCfg
+	prefix := "STORAGE"
+	if err := envconfig.Process(prefix, &cfg); err != nil {
+		return nil, fmt.Errorf("%s: %w", prefix, err)
+	}
+	return &cfg, nil
+}
+
+func LoadESignCfg() (*ESignCfg, error) {
+	var cfg ESignCfg
+	prefix := "ESIGN"
+	if err := envconfig.Process(prefix, &cfg); err != nil {
+		return nil, fmt.Errorf("%s: %w", prefix, err)
+	}
+	return &cfg, nil
+}
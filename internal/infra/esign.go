@@ -0,0 +1,16 @@
+package infra
+
+import "github.com/test/loan-service/internal/esign"
+
+type (
+	// ESignCfg e-sign provider configuration
+	// @envconfig (prefix:"ESIGN")
+	ESignCfg struct {
+		SigningBaseURL string `envconfig:"SIGNING_BASE_URL" default:"http://localhost:8089/esign/sign"`
+	}
+)
+
+// NewESignProvider returns the provider borrowers sign their agreements with
+func NewESignProvider(cfg *ESignCfg) esign.Provider {
+	return esign.NewLocalProvider(cfg.SigningBaseURL)
+}
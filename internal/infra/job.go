@@ -0,0 +1,11 @@
+package infra
+
+import "time"
+
+type (
+	// JobCfg background job configuration
+	// @envconfig (prefix:"JOB")
+	JobCfg struct {
+		LoanExpiryInterval time.Duration `envconfig:"LOAN_EXPIRY_INTERVAL" default:"1h"`
+	}
+)
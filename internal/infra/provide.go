@@ -20,20 +20,63 @@ func init() {
 	typapp.Provide("", LoadKafkaCfg)
 	typapp.Provide("", LoadEchoCfg)
 	typapp.Provide("", LoadSMTPConfig)
+	typapp.Provide("", LoadJobCfg)
+	typapp.Provide("", LoadStorageCfg)
+	typapp.Provide("", LoadESignCfg)
 
 	// config
 	typapp.Provide("", NewDatabases)
 	typapp.Provide("", NewKafkaClients)
 	typapp.Provide("", NewEcho)
 	typapp.Provide("", NewSMTPs)
+	typapp.Provide("", NewDocumentStore)
+	typapp.Provide("", NewURLSigner)
+	typapp.Provide("", NewUploadPolicy)
+	typapp.Provide("", NewESignProvider)
 
 	// repo dependency injection
 	typapp.Provide("", repo.NewLoanRepo)
 	typapp.Provide("", repo.NewLoanDetailRepo)
 	typapp.Provide("", repo.NewLoanApprovalRepo)
+	typapp.Provide("", repo.NewApprovalLevelRepo)
+	typapp.Provide("", repo.NewApprovalStepRepo)
+	typapp.Provide("", repo.NewDocumentRequirementRepo)
 	typapp.Provide("", repo.NewApprovalDocumentRepo)
+	typapp.Provide("", repo.NewDocumentRepo)
 	typapp.Provide("", repo.NewLoanFundingRepo)
 	typapp.Provide("", repo.NewLoanDisbursementRepo)
+	typapp.Provide("", repo.NewRepaymentScheduleRepo)
+	typapp.Provide("", repo.NewLoanRepaymentRepo)
+	typapp.Provide("", repo.NewRepaymentAllocationRepo)
+	typapp.Provide("", repo.NewFundingPolicyRepo)
+	typapp.Provide("", repo.NewLedgerAccountRepo)
+	typapp.Provide("", repo.NewLedgerEntryRepo)
+	typapp.Provide("", repo.NewLedgerPostingRepo)
+	typapp.Provide("", repo.NewLenderWalletRepo)
+	typapp.Provide("", repo.NewWalletTransactionRepo)
+	typapp.Provide("", repo.NewWalletWithdrawalRepo)
+	typapp.Provide("", repo.NewCreditScoringRuleRepo)
+	typapp.Provide("", repo.NewCreditGradeRepo)
+	typapp.Provide("", repo.NewCreditScoreRepo)
+	typapp.Provide("", repo.NewCreditScoreFactorRepo)
+	typapp.Provide("", repo.NewLoanPricingRepo)
+	typapp.Provide("", repo.NewFeePolicyRepo)
+	typapp.Provide("", repo.NewLenderTaxProfileRepo)
+	typapp.Provide("", repo.NewWithholdingTaxRateRepo)
+	typapp.Provide("", repo.NewTaxWithholdingRepo)
+	typapp.Provide("", repo.NewInvestmentLimitRepo)
+	typapp.Provide("", repo.NewAutoInvestRuleRepo)
+	typapp.Provide("", repo.NewAutoInvestRunRepo)
+	typapp.Provide("", repo.NewAutoInvestRunItemRepo)
+	typapp.Provide("", repo.NewFundingListingRepo)
+	typapp.Provide("", repo.NewFundingTransferRepo)
+	typapp.Provide("", repo.NewAgreementTemplateRepo)
+	typapp.Provide("", repo.NewLoanAgreementRepo)
+	typapp.Provide("", repo.NewFundingAgreementRepo)
+	typapp.Provide("", repo.NewAgreementSignatureRepo)
+	typapp.Provide("", repo.NewBorrowerRepo)
+	typapp.Provide("", repo.NewKycHistoryRepo)
+	typapp.Provide("", repo.NewLenderRepo)
 
 	// validator dependency injection
 	typapp.Provide("loan_validator", validator.NewLoanValidator)
@@ -41,13 +84,61 @@ func init() {
 	typapp.Provide("loan_funding_validator", validator.NewLoanFundingValidator)
 	typapp.Provide("loan_detail_validator", validator.NewLoanDetailValidator)
 	typapp.Provide("loan_disbursement_validator", validator.NewLoanDisbursementValidator)
+	typapp.Provide("loan_repayment_validator", validator.NewLoanRepaymentValidator)
+	typapp.Provide("funding_policy_validator", validator.NewFundingPolicyValidator)
+	typapp.Provide("ledger_validator", validator.NewLedgerValidator)
+	typapp.Provide("lender_wallet_validator", validator.NewLenderWalletValidator)
+	typapp.Provide("wallet_withdrawal_validator", validator.NewWalletWithdrawalValidator)
+	typapp.Provide("credit_scoring_validator", validator.NewCreditScoringValidator)
+	typapp.Provide("loan_pricing_validator", validator.NewLoanPricingValidator)
+	typapp.Provide("fee_policy_validator", validator.NewFeePolicyValidator)
+	typapp.Provide("lender_tax_profile_validator", validator.NewLenderTaxProfileValidator)
+	typapp.Provide("withholding_tax_rate_validator", validator.NewWithholdingTaxRateValidator)
+	typapp.Provide("investment_limit_validator", validator.NewInvestmentLimitValidator)
+	typapp.Provide("auto_invest_rule_validator", validator.NewAutoInvestRuleValidator)
+	typapp.Provide("funding_listing_validator", validator.NewFundingListingValidator)
+	typapp.Provide("agreement_template_validator", validator.NewAgreementTemplateValidator)
+	typapp.Provide("signature_callback_validator", validator.NewSignatureCallbackValidator)
+	typapp.Provide("approval_level_validator", validator.NewApprovalLevelValidator)
+	typapp.Provide("document_requirement_validator", validator.NewDocumentRequirementValidator)
+	typapp.Provide("document_upload_validator", validator.NewDocumentUploadValidator)
+	typapp.Provide("loan_cancel_validator", validator.NewLoanCancelValidator)
+	typapp.Provide("loan_funding_cancel_validator", validator.NewLoanFundingCancelValidator)
+	typapp.Provide("funding_listing_cancel_validator", validator.NewFundingListingCancelValidator)
+	typapp.Provide("borrower_validator", validator.NewBorrowerValidator)
+	typapp.Provide("kyc_review_validator", validator.NewKycReviewValidator)
+	typapp.Provide("lender_validator", validator.NewLenderValidator)
+	typapp.Provide("suitability_validator", validator.NewSuitabilityValidator)
+	typapp.Provide("investor_class_validator", validator.NewInvestorClassValidator)
 
 	// service dependency injection
 	typapp.Provide("", service.NewLoanSvc)
 	typapp.Provide("", service.NewEmailSvc)
 	typapp.Provide("", service.NewLoanDisbursementSvc)
 	typapp.Provide("", service.NewLoanApprovalSvc)
+	typapp.Provide("", service.NewApprovalLevelSvc)
+	typapp.Provide("", service.NewDocumentRequirementSvc)
 	typapp.Provide("", service.NewLoanDetailSvc)
 	typapp.Provide("", service.NewLoanFundingSvc)
+	typapp.Provide("", service.NewRepaymentScheduleSvc)
+	typapp.Provide("", service.NewLoanRepaymentSvc)
+	typapp.Provide("", service.NewLoanExpirySvc)
+	typapp.Provide("", service.NewFundingPolicySvc)
+	typapp.Provide("", service.NewLedgerSvc)
+	typapp.Provide("", service.NewLenderWalletSvc)
+	typapp.Provide("", service.NewWalletWithdrawalSvc)
+	typapp.Provide("", service.NewCreditScoringSvc)
+	typapp.Provide("", service.NewLoanPricingSvc)
+	typapp.Provide("", service.NewFeePolicySvc)
+	typapp.Provide("", service.NewTaxSvc)
+	typapp.Provide("", service.NewInvestmentLimitSvc)
+	typapp.Provide("", service.NewAutoInvestSvc)
+	typapp.Provide("", service.NewFundingMarketSvc)
+	typapp.Provide("", service.NewAgreementSvc)
+	typapp.Provide("", service.NewDocumentSvc)
+	typapp.Provide("", service.NewSignatureSvc)
+	typapp.Provide("", service.NewKycSvc)
+	typapp.Provide("", service.NewBorrowerSvc)
+	typapp.Provide("", service.NewLenderSvc)
 
 }
@@ -0,0 +1,58 @@
+package infra
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"github.com/sirupsen/logrus"
+	"github.com/test/loan-service/internal/storage"
+	"time"
+)
+
+type (
+	// StorageCfg document storage configuration
+	// @envconfig (prefix:"STORAGE")
+	StorageCfg struct {
+		Dir                 string        `envconfig:"DIR" default:"./storage"`
+		BaseURL             string        `envconfig:"BASE_URL" default:"http://localhost:8089/documents"`
+		FileBaseURL         string        `envconfig:"FILE_BASE_URL" default:"http://localhost:8089/files"`
+		URLSecret           string        `envconfig:"URL_SECRET"`
+		URLExpiry           time.Duration `envconfig:"URL_EXPIRY" default:"15m"`
+		MaxUploadSize       int64         `envconfig:"MAX_UPLOAD_SIZE" default:"10485760"`
+		AllowedContentTypes []string      `envconfig:"ALLOWED_CONTENT_TYPES" default:"application/pdf,image/jpeg,image/png"`
+	}
+)
+
+// NewDocumentStore returns the store generated documents are kept in
+func NewDocumentStore(cfg *StorageCfg) storage.DocumentStore {
+	return storage.NewLocalStore(cfg.Dir, cfg.BaseURL)
+}
+
+// placeholderURLSecret is the secret the env files used to ship with, anyone could sign download URLs with it
+const placeholderURLSecret = "change-me"
+
+// NewURLSigner returns the signer of the download URLs of uploaded documents. Without a configured
+// secret a random one is used, download URLs then stop working when the service restarts.
+func NewURLSigner(cfg *StorageCfg) *storage.URLSigner {
+	secret := cfg.URLSecret
+	if secret == placeholderURLSecret {
+		logrus.Fatal("STORAGE_URL_SECRET is set to the placeholder value, configure a secret of your own")
+	}
+	if secret == "" {
+		logrus.Warn("STORAGE_URL_SECRET is not set, download URLs are only valid until the service restarts")
+		key := make([]byte, 32)
+		if _, err := rand.Read(key); err != nil {
+			logrus.Fatalf("failed to generate download url secret: %v", err)
+		}
+		secret = hex.EncodeToString(key)
+	}
+
+	return storage.NewURLSigner(secret, cfg.FileBaseURL, cfg.URLExpiry)
+}
+
+// NewUploadPolicy returns the limits of the files uploaded to the document store
+func NewUploadPolicy(cfg *StorageCfg) *storage.UploadPolicy {
+	return &storage.UploadPolicy{
+		MaxSize:      cfg.MaxUploadSize,
+		ContentTypes: cfg.AllowedContentTypes,
+	}
+}
@@ -0,0 +1,307 @@
+package money
+
+import (
+	"bytes"
+	"database/sql/driver"
+	"fmt"
+	"math/big"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Amount is an exact monetary amount stored as an integer number of sen (1/100 of the currency
+// unit), matching the DECIMAL(15, 2) columns. Adding and subtracting amounts is always exact, every
+// operation that can produce a fraction of a sen takes an explicit RoundingMode.
+type Amount int64
+
+const (
+	// Scale number of decimal places of an Amount
+	Scale = 2
+
+	Zero Amount = 0
+	Sen  Amount = 1
+	Unit Amount = 100
+)
+
+// RoundingMode decides what happens with a fraction of a sen
+type RoundingMode int
+
+const (
+	// HalfUp rounds to the nearest sen, halves away from zero
+	HalfUp RoundingMode = iota
+	// HalfEven rounds to the nearest sen, halves to the even sen (banker's rounding)
+	HalfEven
+	// Down truncates toward zero
+	Down
+	// Up rounds away from zero
+	Up
+)
+
+// FromSen returns the amount of the given number of sen
+func FromSen(sen int64) Amount {
+	return Amount(sen)
+}
+
+// FromUnits returns the amount of the given number of whole currency units
+func FromUnits(units int64) Amount {
+	return Amount(units) * Unit
+}
+
+// Parse parses a decimal string such as "1000", "1000.5" or "-12.34". More than two decimal places
+// are rejected unless they are zeros, so parsing never rounds.
+func Parse(s string) (Amount, error) {
+	str := strings.TrimSpace(s)
+	if str == "" {
+		return 0, fmt.Errorf("money: empty amount")
+	}
+
+	negative := false
+	switch str[0] {
+	case '-':
+		negative = true
+		str = str[1:]
+	case '+':
+		str = str[1:]
+	}
+
+	intPart, fracPart, _ := strings.Cut(str, ".")
+	if intPart == "" && fracPart == "" {
+		return 0, fmt.Errorf("money: invalid amount %q", s)
+	}
+	if strings.TrimRight(fracPart[min(len(fracPart), Scale):], "0") != "" {
+		return 0, fmt.Errorf("money: amount %q has more than %d decimal places", s, Scale)
+	}
+	fracPart = (fracPart + "00")[:Scale]
+
+	digits := intPart + fracPart
+	for _, c := range digits {
+		if c < '0' || c > '9' {
+			return 0, fmt.Errorf("money: invalid amount %q", s)
+		}
+	}
+
+	sen, err := strconv.ParseInt(digits, 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("money: invalid amount %q: %w", s, err)
+	}
+	if negative {
+		sen = -sen
+	}
+	return Amount(sen), nil
+}
+
+// MustParse is like Parse but panics on error, meant for constants
+func MustParse(s string) Amount {
+	a, err := Parse(s)
+	if err != nil {
+		panic(err)
+	}
+	return a
+}
+
+// Sen returns the amount as a number of sen
+func (a Amount) Sen() int64 {
+	return int64(a)
+}
+
+// String returns the amount with exactly two decimal places, e.g. "1000.50"
+func (a Amount) String() string {
+	sign := ""
+	sen := int64(a)
+	if sen < 0 {
+		sign = "-"
+	}
+	abs := new(big.Int).Abs(big.NewInt(sen))
+	units, rest := new(big.Int).QuoRem(abs, big.NewInt(int64(Unit)), new(big.Int))
+	return fmt.Sprintf("%s%s.%02d", sign, units.String(), rest.Int64())
+}
+
+// Sign returns -1, 0 or +1
+func (a Amount) Sign() int {
+	switch {
+	case a < 0:
+		return -1
+	case a > 0:
+		return 1
+	}
+	return 0
+}
+
+// IsZero reports whether the amount is zero
+func (a Amount) IsZero() bool {
+	return a == 0
+}
+
+// IsPositive reports whether the amount is greater than zero
+func (a Amount) IsPositive() bool {
+	return a > 0
+}
+
+// Mul multiplies the amount by an integer factor
+func (a Amount) Mul(factor int64) Amount {
+	return a * Amount(factor)
+}
+
+// MulRat multiplies the amount by num/den and rounds the result to a sen with the given mode. The
+// intermediate product is computed with arbitrary precision so it never overflows.
+func (a Amount) MulRat(num, den int64, mode RoundingMode) Amount {
+	if den == 0 {
+		panic("money: division by zero")
+	}
+	product := new(big.Int).Mul(big.NewInt(int64(a)), big.NewInt(num))
+	return Amount(divRound(product, big.NewInt(den), mode).Int64())
+}
+
+// Div divides the amount by n and rounds the result to a sen with the given mode
+func (a Amount) Div(n int64, mode RoundingMode) Amount {
+	return a.MulRat(1, n, mode)
+}
+
+// Allocate splits the amount across weights proportionally. Every share is rounded down and the
+// remaining sen are handed out one by one to the shares with the largest remainder (ties go to the
+// lower index), so the shares always sum up to the amount. It returns zero shares when every weight
+// is zero.
+func (a Amount) Allocate(weights []Amount) []Amount {
+	shares := make([]Amount, len(weights))
+
+	weightSum := big.NewInt(0)
+	for _, weight := range weights {
+		weightSum.Add(weightSum, big.NewInt(int64(weight)))
+	}
+	if weightSum.Sign() == 0 {
+		return shares
+	}
+
+	type remainder struct {
+		index int
+		value *big.Int
+	}
+	total := big.NewInt(int64(a))
+	allocated := Zero
+	remainders := make([]remainder, len(weights))
+	for i, weight := range weights {
+		quotient, rest := new(big.Int).QuoRem(new(big.Int).Mul(total, big.NewInt(int64(weight))), weightSum, new(big.Int))
+		shares[i] = Amount(quotient.Int64())
+		allocated += shares[i]
+		remainders[i] = remainder{index: i, value: rest.Abs(rest)}
+	}
+
+	sort.SliceStable(remainders, func(x, y int) bool {
+		return remainders[x].value.Cmp(remainders[y].value) > 0
+	})
+	leftover := a - allocated
+	step := Sen
+	if leftover < 0 {
+		step = -Sen
+	}
+	for i := 0; leftover != 0; i++ {
+		shares[remainders[i%len(remainders)].index] += step
+		leftover -= step
+	}
+
+	return shares
+}
+
+// Min returns the smaller of two amounts
+func Min(a, b Amount) Amount {
+	if a < b {
+		return a
+	}
+	return b
+}
+
+// Sum returns the total of the given amounts
+func Sum(amounts ...Amount) Amount {
+	total := Zero
+	for _, amount := range amounts {
+		total += amount
+	}
+	return total
+}
+
+// MarshalJSON encodes the amount as a JSON number with two decimal places
+func (a Amount) MarshalJSON() ([]byte, error) {
+	return []byte(a.String()), nil
+}
+
+// UnmarshalJSON accepts a JSON number or a quoted decimal string
+func (a *Amount) UnmarshalJSON(data []byte) error {
+	data = bytes.TrimSpace(data)
+	if bytes.Equal(data, []byte("null")) {
+		return nil
+	}
+	str := string(data)
+	if len(str) >= 2 && str[0] == '"' && str[len(str)-1] == '"' {
+		str = str[1 : len(str)-1]
+	}
+	if strings.ContainsAny(str, "eE") {
+		return fmt.Errorf("money: exponent notation is not supported: %s", str)
+	}
+
+	parsed, err := Parse(str)
+	if err != nil {
+		return err
+	}
+	*a = parsed
+	return nil
+}
+
+// Scan implements sql.Scanner for DECIMAL columns
+func (a *Amount) Scan(src interface{}) error {
+	switch v := src.(type) {
+	case nil:
+		*a = Zero
+		return nil
+	case []byte:
+		return a.scanString(string(v))
+	case string:
+		return a.scanString(v)
+	case int64:
+		*a = FromUnits(v)
+		return nil
+	default:
+		return fmt.Errorf("money: can not scan %T into Amount", src)
+	}
+}
+
+func (a *Amount) scanString(s string) error {
+	parsed, err := Parse(s)
+	if err != nil {
+		return err
+	}
+	*a = parsed
+	return nil
+}
+
+// Value implements driver.Valuer, the amount is sent as a decimal string so no precision is lost
+func (a Amount) Value() (driver.Value, error) {
+	return a.String(), nil
+}
+
+// divRound divides n by d and rounds the quotient with the given mode
+func divRound(n, d *big.Int, mode RoundingMode) *big.Int {
+	quotient, rest := new(big.Int).QuoRem(n, d, new(big.Int))
+	if rest.Sign() == 0 {
+		return quotient
+	}
+
+	// direction of the result, truncation always moves toward zero
+	direction := int64(n.Sign() * d.Sign())
+	awayFromZero := false
+	switch mode {
+	case Down:
+		awayFromZero = false
+	case Up:
+		awayFromZero = true
+	case HalfUp, HalfEven:
+		twice := new(big.Int).Mul(new(big.Int).Abs(rest), big.NewInt(2))
+		cmp := twice.Cmp(new(big.Int).Abs(d))
+		awayFromZero = cmp > 0 || (cmp == 0 && (mode == HalfUp || quotient.Bit(0) == 1))
+	}
+
+	if awayFromZero {
+		quotient.Add(quotient, big.NewInt(direction))
+	}
+	return quotient
+}
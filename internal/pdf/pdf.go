@@ -0,0 +1,164 @@
+package pdf
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+)
+
+// Document is a plain text PDF document laid out top to bottom on A4 pages with the standard
+// Helvetica fonts, enough for statements, certificates and agreements without an external library.
+// A new page is started automatically when a line no longer fits.
+type Document struct {
+	title string
+	pages []*bytes.Buffer
+	y     float64
+}
+
+const (
+	pageWidth    = 595.28 // A4 width in points
+	pageHeight   = 841.89 // A4 height in points
+	margin       = 56.0
+	fontRegular  = "F1"
+	fontBold     = "F2"
+	fontSize     = 10.0
+	headingSize  = 14.0
+	lineSpacing  = 1.4
+	maxLineChars = 95 // characters of regular text that fit between the margins
+)
+
+// New returns an empty document, the title is stored in the document information
+func New(title string) *Document {
+	doc := &Document{title: title}
+	doc.newPage()
+	return doc
+}
+
+// Heading writes a bold line in a larger font
+func (d *Document) Heading(text string) {
+	d.write(fontBold, headingSize, text)
+}
+
+// Bold writes a bold line
+func (d *Document) Bold(text string) {
+	d.write(fontBold, fontSize, text)
+}
+
+// Text writes a paragraph, long text is wrapped on words
+func (d *Document) Text(text string) {
+	for _, line := range wrap(text, maxLineChars) {
+		d.write(fontRegular, fontSize, line)
+	}
+}
+
+// Row writes label and value columns on one line, the value starts at the given offset from the margin
+func (d *Document) Row(label string, value string, offset float64) {
+	d.ensureSpace(fontSize)
+	d.y -= fontSize * lineSpacing
+	fmt.Fprintf(d.pages[len(d.pages)-1], "BT /%s %.1f Tf %.2f %.2f Td (%s) Tj ET\n", fontRegular, fontSize, margin, d.y, escape(label))
+	fmt.Fprintf(d.pages[len(d.pages)-1], "BT /%s %.1f Tf %.2f %.2f Td (%s) Tj ET\n", fontRegular, fontSize, margin+offset, d.y, escape(value))
+}
+
+// Space leaves an empty line
+func (d *Document) Space() {
+	d.ensureSpace(fontSize)
+	d.y -= fontSize * lineSpacing
+}
+
+// Bytes renders the document
+func (d *Document) Bytes() []byte {
+	var out bytes.Buffer
+	var offsets []int
+
+	writeObject := func(body string) {
+		offsets = append(offsets, out.Len())
+		fmt.Fprintf(&out, "%d 0 obj\n%s\nendobj\n", len(offsets), body)
+	}
+
+	out.WriteString("%PDF-1.4\n")
+
+	// objects 1 to 4 are fixed, every page then takes a page object followed by its content stream
+	pageIDs := make([]string, len(d.pages))
+	for i := range d.pages {
+		pageIDs[i] = fmt.Sprintf("%d 0 R", 5+2*i)
+	}
+	writeObject("<< /Type /Catalog /Pages 2 0 R >>")
+	writeObject(fmt.Sprintf("<< /Type /Pages /Kids [%s] /Count %d >>", strings.Join(pageIDs, " "), len(d.pages)))
+	writeObject("<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica /Encoding /WinAnsiEncoding >>")
+	writeObject("<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica-Bold /Encoding /WinAnsiEncoding >>")
+	for i, page := range d.pages {
+		writeObject(fmt.Sprintf("<< /Type /Page /Parent 2 0 R /MediaBox [0 0 %.2f %.2f] "+
+			"/Resources << /Font << /%s 3 0 R /%s 4 0 R >> >> /Contents %d 0 R >>",
+			pageWidth, pageHeight, fontRegular, fontBold, 6+2*i))
+		writeObject(fmt.Sprintf("<< /Length %d >>\nstream\n%sendstream", page.Len(), page.String()))
+	}
+	writeObject(fmt.Sprintf("<< /Title (%s) /Producer (loan-service) >>", escape(d.title)))
+
+	xref := out.Len()
+	fmt.Fprintf(&out, "xref\n0 %d\n0000000000 65535 f \n", len(offsets)+1)
+	for _, offset := range offsets {
+		fmt.Fprintf(&out, "%010d 00000 n \n", offset)
+	}
+	fmt.Fprintf(&out, "trailer\n<< /Size %d /Root 1 0 R /Info %d 0 R >>\nstartxref\n%d\n%%%%EOF\n",
+		len(offsets)+1, len(offsets), xref)
+
+	return out.Bytes()
+}
+
+func (d *Document) write(font string, size float64, text string) {
+	d.ensureSpace(size)
+	d.y -= size * lineSpacing
+	fmt.Fprintf(d.pages[len(d.pages)-1], "BT /%s %.1f Tf %.2f %.2f Td (%s) Tj ET\n", font, size, margin, d.y, escape(text))
+}
+
+func (d *Document) ensureSpace(size float64) {
+	if d.y-size*lineSpacing < margin {
+		d.newPage()
+	}
+}
+
+func (d *Document) newPage() {
+	d.pages = append(d.pages, &bytes.Buffer{})
+	d.y = pageHeight - margin
+}
+
+// escape makes text safe inside a PDF string, characters outside Latin-1 are replaced because the
+// standard fonts can not show them
+func escape(text string) string {
+	var b strings.Builder
+	for _, r := range text {
+		switch {
+		case r == '(' || r == ')' || r == '\\':
+			b.WriteByte('\\')
+			b.WriteRune(r)
+		case r == '\t':
+			b.WriteString("    ")
+		case r < 0x20 || r > 0xff:
+			b.WriteByte('?')
+		case r > 0x7e:
+			fmt.Fprintf(&b, "\\%03o", r)
+		default:
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
+
+func wrap(text string, width int) []string {
+	var lines []string
+	for _, paragraph := range strings.Split(text, "\n") {
+		line := ""
+		for _, word := range strings.Fields(paragraph) {
+			if line != "" && len(line)+1+len(word) > width {
+				lines = append(lines, line)
+				line = ""
+			}
+			if line != "" {
+				line += " "
+			}
+			line += word
+		}
+		lines = append(lines, line)
+	}
+	return lines
+}
@@ -0,0 +1,283 @@
+package repo
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	sq "github.com/Masterminds/squirrel"
+	"github.com/test/loan-service/internal/enum"
+	"github.com/typical-go/typical-rest-server/pkg/dbtxn"
+	"go.uber.org/dig"
+	"time"
+)
+
+//go:generate mockgen -source=$GOFILE -destination=$PROJ/internal/generated/mock/mock_$GOPACKAGE/$GOFILE
+
+type (
+	AgreementSignature struct {
+		ID                 int64                `db:"id"`                   // Signature request ID
+		LoanAgreementID    int64                `db:"loan_agreement_id"`    // Agreement to be signed
+		LoanDisbursementID int64                `db:"loan_disbursement_id"` // Disbursement waiting for the signature
+		LoanID             int64                `db:"loan_id"`              // Loan of the agreement
+		SignerID           int64                `db:"signer_id"`            // Borrower expected to sign
+		Provider           string               `db:"provider"`             // E-sign provider handling the request
+		ProviderReference  string               `db:"provider_reference"`   // Reference of the request at the provider
+		SigningURL         string               `db:"signing_url"`          // URL the borrower signs the agreement at
+		Status             enum.SignatureStatus `db:"status"`               // requested, signed, rejected
+		SignedBy           *string              `db:"signed_by"`            // Signer identity reported by the provider
+		SignedAt           *time.Time           `db:"signed_at"`            // Date the agreement was signed
+		SignedDocumentHash *string              `db:"signed_document_hash"` // SHA-256 of the document that was signed
+		RejectionReason    *string              `db:"rejection_reason"`     // Why the signature was not accepted
+		CreatedAt          time.Time            `db:"created_at"`           // Date of creation
+		UpdatedAt          time.Time            `db:"updated_at"`           // Date of last update
+	}
+
+	AgreementSignatureRepo interface {
+		Create(ctx context.Context, signature *AgreementSignature) (int64, error)
+		Update(ctx context.Context, signature *AgreementSignature) error
+		GetByID(ctx context.Context, id int64) (*AgreementSignature, error)
+		GetByProviderReferenceForUpdate(ctx context.Context, provider string, providerReference string) (*AgreementSignature, error)
+		GetByDisbursementID(ctx context.Context, disbursementID int64) ([]AgreementSignature, error)
+	}
+
+	AgreementSignatureRepoImpl struct {
+		dig.In
+		*sql.DB
+	}
+)
+
+var (
+	AgreementSignatureTableName = "agreement_signatures"
+	AgreementSignatureTable     = struct {
+		ID                 string
+		LoanAgreementID    string
+		LoanDisbursementID string
+		LoanID             string
+		SignerID           string
+		Provider           string
+		ProviderReference  string
+		SigningURL         string
+		Status             string
+		SignedBy           string
+		SignedAt           string
+		SignedDocumentHash string
+		RejectionReason    string
+		CreatedAt          string
+		UpdatedAt          string
+	}{
+		ID:                 "id",
+		LoanAgreementID:    "loan_agreement_id",
+		LoanDisbursementID: "loan_disbursement_id",
+		LoanID:             "loan_id",
+		SignerID:           "signer_id",
+		Provider:           "provider",
+		ProviderReference:  "provider_reference",
+		SigningURL:         "signing_url",
+		Status:             "status",
+		SignedBy:           "signed_by",
+		SignedAt:           "signed_at",
+		SignedDocumentHash: "signed_document_hash",
+		RejectionReason:    "rejection_reason",
+		CreatedAt:          "created_at",
+		UpdatedAt:          "updated_at",
+	}
+)
+
+func NewAgreementSignatureRepo(impl AgreementSignatureRepoImpl) AgreementSignatureRepo {
+	return &impl
+}
+
+// Create AgreementSignature and return last inserted id
+func (r *AgreementSignatureRepoImpl) Create(ctx context.Context, signature *AgreementSignature) (int64, error) {
+	txn, err := dbtxn.Use(ctx, r.DB)
+	if err != nil {
+		return -1, err
+	}
+
+	builder := sq.
+		Insert(AgreementSignatureTableName).
+		Columns(
+			AgreementSignatureTable.LoanAgreementID,
+			AgreementSignatureTable.LoanDisbursementID,
+			AgreementSignatureTable.LoanID,
+			AgreementSignatureTable.SignerID,
+			AgreementSignatureTable.Provider,
+			AgreementSignatureTable.ProviderReference,
+			AgreementSignatureTable.SigningURL,
+			AgreementSignatureTable.Status,
+			AgreementSignatureTable.SignedBy,
+			AgreementSignatureTable.SignedAt,
+			AgreementSignatureTable.SignedDocumentHash,
+			AgreementSignatureTable.RejectionReason,
+			AgreementSignatureTable.CreatedAt,
+			AgreementSignatureTable.UpdatedAt,
+		).
+		Suffix("RETURNING id").
+		PlaceholderFormat(sq.Dollar).
+		Values(
+			signature.LoanAgreementID,
+			signature.LoanDisbursementID,
+			signature.LoanID,
+			signature.SignerID,
+			signature.Provider,
+			signature.ProviderReference,
+			signature.SigningURL,
+			signature.Status,
+			signature.SignedBy,
+			signature.SignedAt,
+			signature.SignedDocumentHash,
+			signature.RejectionReason,
+			time.Now(),
+			time.Now(),
+		)
+
+	scanner := builder.RunWith(txn).QueryRowContext(ctx)
+
+	var id int64
+	if err := scanner.Scan(&id); err != nil {
+		return -1, fmt.Errorf("failed to scan id: %v", err)
+	}
+
+	return id, nil
+}
+
+// Update records the outcome of a signature request, what was requested never changes
+func (r *AgreementSignatureRepoImpl) Update(ctx context.Context, signature *AgreementSignature) error {
+	txn, err := dbtxn.Use(ctx, r.DB)
+	if err != nil {
+		return err
+	}
+
+	builder := sq.Update(AgreementSignatureTableName).
+		Set(AgreementSignatureTable.Status, signature.Status).
+		Set(AgreementSignatureTable.SignedBy, signature.SignedBy).
+		Set(AgreementSignatureTable.SignedAt, signature.SignedAt).
+		Set(AgreementSignatureTable.SignedDocumentHash, signature.SignedDocumentHash).
+		Set(AgreementSignatureTable.RejectionReason, signature.RejectionReason).
+		Set(AgreementSignatureTable.UpdatedAt, time.Now()).
+		Where(sq.Eq{AgreementSignatureTable.ID: signature.ID}).
+		PlaceholderFormat(sq.Dollar)
+
+	result, err := builder.RunWith(txn).ExecContext(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to update agreement signature: %v", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %v", err)
+	}
+	if rowsAffected == 0 {
+		return fmt.Errorf("no agreement signature found with ID: %d", signature.ID)
+	}
+
+	return nil
+}
+
+// GetByID returns the signature request, nil when it does not exist
+func (r *AgreementSignatureRepoImpl) GetByID(ctx context.Context, id int64) (*AgreementSignature, error) {
+	return r.get(ctx, r.selectBuilder().Where(sq.Eq{AgreementSignatureTable.ID: id}))
+}
+
+// GetByProviderReferenceForUpdate returns the signature request a provider callback is about and
+// locks the row until the transaction ends, nil when it does not exist
+func (r *AgreementSignatureRepoImpl) GetByProviderReferenceForUpdate(ctx context.Context, provider string, providerReference string) (*AgreementSignature, error) {
+	return r.get(ctx, r.selectBuilder().Where(sq.Eq{
+		AgreementSignatureTable.Provider:          provider,
+		AgreementSignatureTable.ProviderReference: providerReference,
+	}).Suffix("FOR UPDATE"))
+}
+
+// GetByDisbursementID returns the signature requests of a disbursement, most recent first
+func (r *AgreementSignatureRepoImpl) GetByDisbursementID(ctx context.Context, disbursementID int64) ([]AgreementSignature, error) {
+	txn, err := dbtxn.Use(ctx, r.DB)
+	if err != nil {
+		return nil, err
+	}
+
+	builder := r.selectBuilder().
+		Where(sq.Eq{AgreementSignatureTable.LoanDisbursementID: disbursementID}).
+		OrderBy(AgreementSignatureTable.CreatedAt+" DESC", AgreementSignatureTable.ID+" DESC")
+
+	rows, err := builder.RunWith(txn).QueryContext(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute query: %v", err)
+	}
+	defer rows.Close()
+
+	var signatures []AgreementSignature
+	for rows.Next() {
+		var signature AgreementSignature
+		if err := rows.Scan(r.scanDest(&signature)...); err != nil {
+			return nil, fmt.Errorf("failed to scan row: %v", err)
+		}
+		signatures = append(signatures, signature)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate over rows: %v", err)
+	}
+
+	return signatures, nil
+}
+
+func (r *AgreementSignatureRepoImpl) get(ctx context.Context, builder sq.SelectBuilder) (*AgreementSignature, error) {
+	txn, err := dbtxn.Use(ctx, r.DB)
+	if err != nil {
+		return nil, err
+	}
+
+	var signature AgreementSignature
+	scanner := builder.RunWith(txn).QueryRowContext(ctx)
+	if err := scanner.Scan(r.scanDest(&signature)...); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to scan agreement signature: %v", err)
+	}
+
+	return &signature, nil
+}
+
+func (r *AgreementSignatureRepoImpl) selectBuilder() sq.SelectBuilder {
+	return sq.
+		Select(
+			AgreementSignatureTable.ID,
+			AgreementSignatureTable.LoanAgreementID,
+			AgreementSignatureTable.LoanDisbursementID,
+			AgreementSignatureTable.LoanID,
+			AgreementSignatureTable.SignerID,
+			AgreementSignatureTable.Provider,
+			AgreementSignatureTable.ProviderReference,
+			AgreementSignatureTable.SigningURL,
+			AgreementSignatureTable.Status,
+			AgreementSignatureTable.SignedBy,
+			AgreementSignatureTable.SignedAt,
+			AgreementSignatureTable.SignedDocumentHash,
+			AgreementSignatureTable.RejectionReason,
+			AgreementSignatureTable.CreatedAt,
+			AgreementSignatureTable.UpdatedAt,
+		).
+		From(AgreementSignatureTableName).
+		PlaceholderFormat(sq.Dollar)
+}
+
+func (r *AgreementSignatureRepoImpl) scanDest(signature *AgreementSignature) []interface{} {
+	return []interface{}{
+		&signature.ID,
+		&signature.LoanAgreementID,
+		&signature.LoanDisbursementID,
+		&signature.LoanID,
+		&signature.SignerID,
+		&signature.Provider,
+		&signature.ProviderReference,
+		&signature.SigningURL,
+		&signature.Status,
+		&signature.SignedBy,
+		&signature.SignedAt,
+		&signature.SignedDocumentHash,
+		&signature.RejectionReason,
+		&signature.CreatedAt,
+		&signature.UpdatedAt,
+	}
+}
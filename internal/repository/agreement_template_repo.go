@@ -0,0 +1,208 @@
+package repo
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	sq "github.com/Masterminds/squirrel"
+	"github.com/test/loan-service/internal/enum"
+	"github.com/typical-go/typical-rest-server/pkg/dbtxn"
+	"go.uber.org/dig"
+	"time"
+)
+
+//go:generate mockgen -source=$GOFILE -destination=$PROJ/internal/generated/mock/mock_$GOPACKAGE/$GOFILE
+
+type (
+	AgreementTemplate struct {
+		ID            int64              `db:"id"`             // Template ID
+		AgreementType enum.AgreementType `db:"agreement_type"` // Party the agreement is made with
+		Version       int64              `db:"version"`        // Version of the template within its agreement type
+		Title         string             `db:"title"`          // Title printed on top of the agreement
+		Body          string             `db:"body"`           // Go text/template body
+		CreatedAt     time.Time          `db:"created_at"`     // Date of creation
+		UpdatedAt     time.Time          `db:"updated_at"`     // Date of last update
+		DeletedAt     *time.Time         `db:"deleted_at"`     // Date of deletion if applicable
+	}
+
+	AgreementTemplateRepo interface {
+		Create(ctx context.Context, template *AgreementTemplate) (int64, error)
+		GetByID(ctx context.Context, id int64) (*AgreementTemplate, error)
+		GetByAgreementType(ctx context.Context, agreementType enum.AgreementType) ([]AgreementTemplate, error)
+		GetLatest(ctx context.Context, agreementType enum.AgreementType) (*AgreementTemplate, error)
+	}
+
+	AgreementTemplateRepoImpl struct {
+		dig.In
+		*sql.DB
+	}
+)
+
+var (
+	AgreementTemplateTableName = "agreement_templates"
+	AgreementTemplateTable     = struct {
+		ID            string
+		AgreementType string
+		Version       string
+		Title         string
+		Body          string
+		CreatedAt     string
+		UpdatedAt     string
+		DeletedAt     string
+	}{
+		ID:            "id",
+		AgreementType: "agreement_type",
+		Version:       "version",
+		Title:         "title",
+		Body:          "body",
+		CreatedAt:     "created_at",
+		UpdatedAt:     "updated_at",
+		DeletedAt:     "deleted_at",
+	}
+)
+
+func NewAgreementTemplateRepo(impl AgreementTemplateRepoImpl) AgreementTemplateRepo {
+	return &impl
+}
+
+// Create AgreementTemplate and return last inserted id, templates are never changed once created
+func (r *AgreementTemplateRepoImpl) Create(ctx context.Context, template *AgreementTemplate) (int64, error) {
+	txn, err := dbtxn.Use(ctx, r.DB)
+	if err != nil {
+		return -1, err
+	}
+
+	builder := sq.
+		Insert(AgreementTemplateTableName).
+		Columns(
+			AgreementTemplateTable.AgreementType,
+			AgreementTemplateTable.Version,
+			AgreementTemplateTable.Title,
+			AgreementTemplateTable.Body,
+			AgreementTemplateTable.CreatedAt,
+			AgreementTemplateTable.UpdatedAt,
+			AgreementTemplateTable.DeletedAt,
+		).
+		Suffix("RETURNING id").
+		PlaceholderFormat(sq.Dollar).
+		Values(
+			template.AgreementType,
+			template.Version,
+			template.Title,
+			template.Body,
+			time.Now(),
+			time.Now(),
+			nil,
+		)
+
+	scanner := builder.RunWith(txn).QueryRowContext(ctx)
+
+	var id int64
+	if err := scanner.Scan(&id); err != nil {
+		return -1, fmt.Errorf("failed to scan id: %v", err)
+	}
+
+	return id, nil
+}
+
+// GetByID returns the template, nil when it does not exist
+func (r *AgreementTemplateRepoImpl) GetByID(ctx context.Context, id int64) (*AgreementTemplate, error) {
+	return r.get(ctx, r.selectBuilder().Where(sq.Eq{
+		AgreementTemplateTable.ID:        id,
+		AgreementTemplateTable.DeletedAt: nil,
+	}))
+}
+
+// GetByAgreementType returns every version of the templates of an agreement type, latest first
+func (r *AgreementTemplateRepoImpl) GetByAgreementType(ctx context.Context, agreementType enum.AgreementType) ([]AgreementTemplate, error) {
+	txn, err := dbtxn.Use(ctx, r.DB)
+	if err != nil {
+		return nil, err
+	}
+
+	builder := r.selectBuilder().
+		Where(sq.Eq{
+			AgreementTemplateTable.AgreementType: agreementType,
+			AgreementTemplateTable.DeletedAt:     nil,
+		}).
+		OrderBy(AgreementTemplateTable.Version + " DESC")
+
+	rows, err := builder.RunWith(txn).QueryContext(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute query: %v", err)
+	}
+	defer rows.Close()
+
+	var templates []AgreementTemplate
+	for rows.Next() {
+		var template AgreementTemplate
+		if err := rows.Scan(r.scanDest(&template)...); err != nil {
+			return nil, fmt.Errorf("failed to scan row: %v", err)
+		}
+		templates = append(templates, template)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate over rows: %v", err)
+	}
+
+	return templates, nil
+}
+
+// GetLatest returns the highest version of the templates of an agreement type, nil when there is none
+func (r *AgreementTemplateRepoImpl) GetLatest(ctx context.Context, agreementType enum.AgreementType) (*AgreementTemplate, error) {
+	return r.get(ctx, r.selectBuilder().
+		Where(sq.Eq{
+			AgreementTemplateTable.AgreementType: agreementType,
+			AgreementTemplateTable.DeletedAt:     nil,
+		}).
+		OrderBy(AgreementTemplateTable.Version+" DESC").
+		Limit(1))
+}
+
+func (r *AgreementTemplateRepoImpl) get(ctx context.Context, builder sq.SelectBuilder) (*AgreementTemplate, error) {
+	txn, err := dbtxn.Use(ctx, r.DB)
+	if err != nil {
+		return nil, err
+	}
+
+	var template AgreementTemplate
+	scanner := builder.RunWith(txn).QueryRowContext(ctx)
+	if err := scanner.Scan(r.scanDest(&template)...); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to scan agreement template: %v", err)
+	}
+
+	return &template, nil
+}
+
+func (r *AgreementTemplateRepoImpl) selectBuilder() sq.SelectBuilder {
+	return sq.
+		Select(
+			AgreementTemplateTable.ID,
+			AgreementTemplateTable.AgreementType,
+			AgreementTemplateTable.Version,
+			AgreementTemplateTable.Title,
+			AgreementTemplateTable.Body,
+			AgreementTemplateTable.CreatedAt,
+			AgreementTemplateTable.UpdatedAt,
+			AgreementTemplateTable.DeletedAt,
+		).
+		From(AgreementTemplateTableName).
+		PlaceholderFormat(sq.Dollar)
+}
+
+func (r *AgreementTemplateRepoImpl) scanDest(template *AgreementTemplate) []interface{} {
+	return []interface{}{
+		&template.ID,
+		&template.AgreementType,
+		&template.Version,
+		&template.Title,
+		&template.Body,
+		&template.CreatedAt,
+		&template.UpdatedAt,
+		&template.DeletedAt,
+	}
+}
@@ -20,6 +20,7 @@ type (
 		DocumentType   string     `db:"document_type"`
 		FileURL        string     `db:"file_url"`
 		Description    *string    `db:"description"`
+		DocumentID     *int64     `db:"document_id"`
 		CreatedAt      time.Time  `db:"created_at"`
 		UpdatedAt      time.Time  `db:"updated_at"`
 		DeletedAt      *time.Time `db:"deleted_at"`
@@ -49,6 +50,7 @@ var (
 		DocumentType   string
 		FileURL        string
 		Description    string
+		DocumentID     string
 		CreatedAt      string
 		UpdatedAt      string
 		DeletedAt      string
@@ -58,6 +60,7 @@ var (
 		DocumentType:   "document_type",
 		FileURL:        "file_url",
 		Description:    "description",
+		DocumentID:     "document_id",
 		CreatedAt:      "created_at",
 		UpdatedAt:      "updated_at",
 		DeletedAt:      "deleted_at",
@@ -78,6 +81,7 @@ func (r *ApprovalDocumentRepoImpl) Create(ctx context.Context, approvalDocument
 			ApprovalDocumentTable.DocumentType,
 			ApprovalDocumentTable.FileURL,
 			ApprovalDocumentTable.Description,
+			ApprovalDocumentTable.DocumentID,
 			ApprovalDocumentTable.CreatedAt,
 			ApprovalDocumentTable.UpdatedAt,
 			ApprovalDocumentTable.DeletedAt,
@@ -89,6 +93,7 @@ func (r *ApprovalDocumentRepoImpl) Create(ctx context.Context, approvalDocument
 			approvalDocument.DocumentType,
 			approvalDocument.FileURL,
 			approvalDocument.Description,
+			approvalDocument.DocumentID,
 			approvalDocument.CreatedAt,
 			approvalDocument.UpdatedAt,
 			nil,
@@ -118,6 +123,7 @@ func (r *ApprovalDocumentRepoImpl) GetByApprovalID(ctx context.Context, approval
 			ApprovalDocumentTable.DocumentType,
 			ApprovalDocumentTable.FileURL,
 			ApprovalDocumentTable.Description,
+			ApprovalDocumentTable.DocumentID,
 			ApprovalDocumentTable.CreatedAt,
 			ApprovalDocumentTable.UpdatedAt,
 			ApprovalDocumentTable.DeletedAt,
@@ -145,6 +151,7 @@ func (r *ApprovalDocumentRepoImpl) GetByApprovalID(ctx context.Context, approval
 			&approvalDocument.DocumentType,
 			&approvalDocument.FileURL,
 			&approvalDocument.Description,
+			&approvalDocument.DocumentID,
 			&approvalDocument.CreatedAt,
 			&approvalDocument.UpdatedAt,
 			&approvalDocument.DeletedAt,
@@ -0,0 +1,183 @@
+package repo
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	sq "github.com/Masterminds/squirrel"
+	"github.com/test/loan-service/internal/enum"
+	"github.com/test/loan-service/internal/money"
+	"github.com/typical-go/typical-rest-server/pkg/dbtxn"
+	"go.uber.org/dig"
+	"time"
+)
+
+//go:generate mockgen -source=$GOFILE -destination=$PROJ/internal/generated/mock/mock_$GOPACKAGE/$GOFILE
+
+type (
+	ApprovalLevel struct {
+		ID        int64           `db:"id"`         // Approval level ID
+		Level     int64           `db:"level"`      // Position of the level in the approval chain, starting at 1
+		Name      string          `db:"name"`       // Name of the approvers of the level
+		MinAmount money.Amount    `db:"min_amount"` // The level is required for loans requesting at least this amount
+		MinGrade  *enum.LoanGrade `db:"min_grade"`  // The level is also required for loans graded this grade or worse
+		CreatedAt time.Time       `db:"created_at"` // Date of creation
+		UpdatedAt time.Time       `db:"updated_at"` // Date of last update
+		DeletedAt *time.Time      `db:"deleted_at"` // Date of deletion if applicable
+	}
+
+	ApprovalLevelRepo interface {
+		Update(ctx context.Context, level *ApprovalLevel) error
+		GetByLevel(ctx context.Context, level int64) (*ApprovalLevel, error)
+		GetAll(ctx context.Context) ([]ApprovalLevel, error)
+	}
+
+	ApprovalLevelRepoImpl struct {
+		dig.In
+		*sql.DB
+	}
+)
+
+var (
+	ApprovalLevelTableName = "approval_levels"
+	ApprovalLevelTable     = struct {
+		ID        string
+		Level     string
+		Name      string
+		MinAmount string
+		MinGrade  string
+		CreatedAt string
+		UpdatedAt string
+		DeletedAt string
+	}{
+		ID:        "id",
+		Level:     "level",
+		Name:      "name",
+		MinAmount: "min_amount",
+		MinGrade:  "min_grade",
+		CreatedAt: "created_at",
+		UpdatedAt: "updated_at",
+		DeletedAt: "deleted_at",
+	}
+)
+
+func NewApprovalLevelRepo(impl ApprovalLevelRepoImpl) ApprovalLevelRepo {
+	return &impl
+}
+
+// Update ApprovalLevel thresholds, the position of a level in the chain never changes
+func (r *ApprovalLevelRepoImpl) Update(ctx context.Context, level *ApprovalLevel) error {
+	txn, err := dbtxn.Use(ctx, r.DB)
+	if err != nil {
+		return err
+	}
+
+	builder := sq.Update(ApprovalLevelTableName).
+		Set(ApprovalLevelTable.Name, level.Name).
+		Set(ApprovalLevelTable.MinAmount, level.MinAmount).
+		Set(ApprovalLevelTable.MinGrade, level.MinGrade).
+		Set(ApprovalLevelTable.UpdatedAt, time.Now()).
+		Where(sq.Eq{ApprovalLevelTable.Level: level.Level}).
+		PlaceholderFormat(sq.Dollar)
+
+	result, err := builder.RunWith(txn).ExecContext(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to update approval level: %v", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %v", err)
+	}
+	if rowsAffected == 0 {
+		return fmt.Errorf("no approval level found: %d", level.Level)
+	}
+
+	return nil
+}
+
+// GetByLevel returns the approval level at a position of the chain, nil when it does not exist
+func (r *ApprovalLevelRepoImpl) GetByLevel(ctx context.Context, level int64) (*ApprovalLevel, error) {
+	txn, err := dbtxn.Use(ctx, r.DB)
+	if err != nil {
+		return nil, err
+	}
+
+	builder := r.selectBuilder().Where(sq.Eq{
+		ApprovalLevelTable.Level:     level,
+		ApprovalLevelTable.DeletedAt: nil,
+	})
+
+	var approvalLevel ApprovalLevel
+	scanner := builder.RunWith(txn).QueryRowContext(ctx)
+	if err := scanner.Scan(r.scanDest(&approvalLevel)...); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to scan approval level: %v", err)
+	}
+
+	return &approvalLevel, nil
+}
+
+// GetAll returns every approval level ordered by its position in the chain
+func (r *ApprovalLevelRepoImpl) GetAll(ctx context.Context) ([]ApprovalLevel, error) {
+	txn, err := dbtxn.Use(ctx, r.DB)
+	if err != nil {
+		return nil, err
+	}
+
+	builder := r.selectBuilder().
+		Where(sq.Eq{ApprovalLevelTable.DeletedAt: nil}).
+		OrderBy(ApprovalLevelTable.Level + " ASC")
+
+	rows, err := builder.RunWith(txn).QueryContext(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute query: %v", err)
+	}
+	defer rows.Close()
+
+	var levels []ApprovalLevel
+	for rows.Next() {
+		var level ApprovalLevel
+		if err := rows.Scan(r.scanDest(&level)...); err != nil {
+			return nil, fmt.Errorf("failed to scan row: %v", err)
+		}
+		levels = append(levels, level)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate over rows: %v", err)
+	}
+
+	return levels, nil
+}
+
+func (r *ApprovalLevelRepoImpl) selectBuilder() sq.SelectBuilder {
+	return sq.
+		Select(
+			ApprovalLevelTable.ID,
+			ApprovalLevelTable.Level,
+			ApprovalLevelTable.Name,
+			ApprovalLevelTable.MinAmount,
+			ApprovalLevelTable.MinGrade,
+			ApprovalLevelTable.CreatedAt,
+			ApprovalLevelTable.UpdatedAt,
+			ApprovalLevelTable.DeletedAt,
+		).
+		From(ApprovalLevelTableName).
+		PlaceholderFormat(sq.Dollar)
+}
+
+func (r *ApprovalLevelRepoImpl) scanDest(level *ApprovalLevel) []interface{} {
+	return []interface{}{
+		&level.ID,
+		&level.Level,
+		&level.Name,
+		&level.MinAmount,
+		&level.MinGrade,
+		&level.CreatedAt,
+		&level.UpdatedAt,
+		&level.DeletedAt,
+	}
+}
@@ -0,0 +1,166 @@
+package repo
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	sq "github.com/Masterminds/squirrel"
+	"github.com/test/loan-service/internal/enum"
+	"github.com/typical-go/typical-rest-server/pkg/dbtxn"
+	"go.uber.org/dig"
+	"time"
+)
+
+//go:generate mockgen -source=$GOFILE -destination=$PROJ/internal/generated/mock/mock_$GOPACKAGE/$GOFILE
+
+type (
+	ApprovalStep struct {
+		ID             int64               `db:"id"`               // Approval step ID
+		LoanApprovalID int64               `db:"loan_approval_id"` // Loan approval the step belongs to
+		Level          int64               `db:"level"`            // Approval level decided by the step
+		StaffID        int64               `db:"staff_id"`         // Staff who decided the step
+		Decision       enum.ApprovalStatus `db:"decision"`         // approved, rejected
+		DecidedAt      time.Time           `db:"decided_at"`       // Date of the decision
+		CreatedAt      time.Time           `db:"created_at"`       // Date of creation
+		UpdatedAt      time.Time           `db:"updated_at"`       // Date of last update
+		DeletedAt      *time.Time          `db:"deleted_at"`       // Date of deletion if applicable
+	}
+
+	ApprovalStepRepo interface {
+		Create(ctx context.Context, step *ApprovalStep) (int64, error)
+		GetByApprovalID(ctx context.Context, approvalID int64) ([]ApprovalStep, error)
+	}
+
+	ApprovalStepRepoImpl struct {
+		dig.In
+		*sql.DB
+	}
+)
+
+var (
+	ApprovalStepTableName = "approval_steps"
+	ApprovalStepTable     = struct {
+		ID             string
+		LoanApprovalID string
+		Level          string
+		StaffID        string
+		Decision       string
+		DecidedAt      string
+		CreatedAt      string
+		UpdatedAt      string
+		DeletedAt      string
+	}{
+		ID:             "id",
+		LoanApprovalID: "loan_approval_id",
+		Level:          "level",
+		StaffID:        "staff_id",
+		Decision:       "decision",
+		DecidedAt:      "decided_at",
+		CreatedAt:      "created_at",
+		UpdatedAt:      "updated_at",
+		DeletedAt:      "deleted_at",
+	}
+)
+
+func NewApprovalStepRepo(impl ApprovalStepRepoImpl) ApprovalStepRepo {
+	return &impl
+}
+
+// Create ApprovalStep and return last inserted id
+func (r *ApprovalStepRepoImpl) Create(ctx context.Context, step *ApprovalStep) (int64, error) {
+	txn, err := dbtxn.Use(ctx, r.DB)
+	if err != nil {
+		return -1, err
+	}
+
+	builder := sq.
+		Insert(ApprovalStepTableName).
+		Columns(
+			ApprovalStepTable.LoanApprovalID,
+			ApprovalStepTable.Level,
+			ApprovalStepTable.StaffID,
+			ApprovalStepTable.Decision,
+			ApprovalStepTable.DecidedAt,
+			ApprovalStepTable.CreatedAt,
+			ApprovalStepTable.UpdatedAt,
+		).
+		Suffix("RETURNING id").
+		PlaceholderFormat(sq.Dollar).
+		Values(
+			step.LoanApprovalID,
+			step.Level,
+			step.StaffID,
+			step.Decision,
+			step.DecidedAt,
+			time.Now(),
+			time.Now(),
+		)
+
+	scanner := builder.RunWith(txn).QueryRowContext(ctx)
+
+	var id int64
+	if err := scanner.Scan(&id); err != nil {
+		return -1, fmt.Errorf("failed to scan id: %v", err)
+	}
+
+	return id, nil
+}
+
+// GetByApprovalID returns the steps of a loan approval in the order of the chain
+func (r *ApprovalStepRepoImpl) GetByApprovalID(ctx context.Context, approvalID int64) ([]ApprovalStep, error) {
+	txn, err := dbtxn.Use(ctx, r.DB)
+	if err != nil {
+		return nil, err
+	}
+
+	builder := sq.
+		Select(
+			ApprovalStepTable.ID,
+			ApprovalStepTable.LoanApprovalID,
+			ApprovalStepTable.Level,
+			ApprovalStepTable.StaffID,
+			ApprovalStepTable.Decision,
+			ApprovalStepTable.DecidedAt,
+			ApprovalStepTable.CreatedAt,
+			ApprovalStepTable.UpdatedAt,
+			ApprovalStepTable.DeletedAt,
+		).
+		From(ApprovalStepTableName).
+		Where(sq.Eq{
+			ApprovalStepTable.LoanApprovalID: approvalID,
+			ApprovalStepTable.DeletedAt:      nil,
+		}).
+		OrderBy(ApprovalStepTable.Level + " ASC").
+		PlaceholderFormat(sq.Dollar)
+
+	rows, err := builder.RunWith(txn).QueryContext(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute query: %v", err)
+	}
+	defer rows.Close()
+
+	var steps []ApprovalStep
+	for rows.Next() {
+		var step ApprovalStep
+		if err := rows.Scan(
+			&step.ID,
+			&step.LoanApprovalID,
+			&step.Level,
+			&step.StaffID,
+			&step.Decision,
+			&step.DecidedAt,
+			&step.CreatedAt,
+			&step.UpdatedAt,
+			&step.DeletedAt,
+		); err != nil {
+			return nil, fmt.Errorf("failed to scan row: %v", err)
+		}
+		steps = append(steps, step)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate over rows: %v", err)
+	}
+
+	return steps, nil
+}
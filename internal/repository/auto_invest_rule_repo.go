@@ -0,0 +1,341 @@
+package repo
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	sq "github.com/Masterminds/squirrel"
+	"github.com/lib/pq"
+	"github.com/test/loan-service/internal/money"
+	"github.com/typical-go/typical-rest-server/pkg/dbtxn"
+	"go.uber.org/dig"
+	"time"
+)
+
+//go:generate mockgen -source=$GOFILE -destination=$PROJ/internal/generated/mock/mock_$GOPACKAGE/$GOFILE
+
+type (
+	AutoInvestRule struct {
+		ID              int64        `db:"id"`               // Auto-invest rule ID
+		LenderID        int64        `db:"lender_id"`        // Lender the rule invests for
+		LoanGrades      []string     `db:"loan_grades"`      // Loan grades the rule invests in, empty for every grade
+		LoanTypes       []string     `db:"loan_types"`       // Loan types the rule invests in, empty for every type
+		BusinessSectors []string     `db:"business_sectors"` // Business sectors the rule invests in, empty for every sector
+		MinTenure       int64        `db:"min_tenure"`       // Shortest tenure in months, 0 for no lower bound
+		MaxTenure       int64        `db:"max_tenure"`       // Longest tenure in months, 0 for no upper bound
+		MinRate         float64      `db:"min_rate"`         // Lowest lender yield the rule accepts
+		AmountPerLoan   money.Amount `db:"amount_per_loan"`  // Amount ordered in every matching loan
+		MonthlyBudget   money.Amount `db:"monthly_budget"`   // Maximum amount ordered by the rule in a calendar month
+		IsActive        bool         `db:"is_active"`        // Whether the rule takes part in runs
+		LastInvestedAt  *time.Time   `db:"last_invested_at"` // Date of the last order of the rule, decides its turn in a run
+		CreatedAt       time.Time    `db:"created_at"`       // Date of creation
+		UpdatedAt       time.Time    `db:"updated_at"`       // Date of last update
+		DeletedAt       *time.Time   `db:"deleted_at"`       // Date of deletion if applicable
+	}
+
+	AutoInvestRuleRepo interface {
+		Create(ctx context.Context, rule *AutoInvestRule) (int64, error)
+		Update(ctx context.Context, rule *AutoInvestRule) error
+		Delete(ctx context.Context, id int64) error
+		GetByID(ctx context.Context, id int64) (*AutoInvestRule, error)
+		GetByLenderID(ctx context.Context, lenderID int64) ([]AutoInvestRule, error)
+		GetActive(ctx context.Context) ([]AutoInvestRule, error)
+	}
+
+	AutoInvestRuleRepoImpl struct {
+		dig.In
+		*sql.DB
+	}
+)
+
+var (
+	AutoInvestRuleTableName = "auto_invest_rules"
+	AutoInvestRuleTable     = struct {
+		ID              string
+		LenderID        string
+		LoanGrades      string
+		LoanTypes       string
+		BusinessSectors string
+		MinTenure       string
+		MaxTenure       string
+		MinRate         string
+		AmountPerLoan   string
+		MonthlyBudget   string
+		IsActive        string
+		LastInvestedAt  string
+		CreatedAt       string
+		UpdatedAt       string
+		DeletedAt       string
+	}{
+		ID:              "id",
+		LenderID:        "lender_id",
+		LoanGrades:      "loan_grades",
+		LoanTypes:       "loan_types",
+		BusinessSectors: "business_sectors",
+		MinTenure:       "min_tenure",
+		MaxTenure:       "max_tenure",
+		MinRate:         "min_rate",
+		AmountPerLoan:   "amount_per_loan",
+		MonthlyBudget:   "monthly_budget",
+		IsActive:        "is_active",
+		LastInvestedAt:  "last_invested_at",
+		CreatedAt:       "created_at",
+		UpdatedAt:       "updated_at",
+		DeletedAt:       "deleted_at",
+	}
+)
+
+func NewAutoInvestRuleRepo(impl AutoInvestRuleRepoImpl) AutoInvestRuleRepo {
+	return &impl
+}
+
+// Create AutoInvestRule and return last inserted id
+func (r *AutoInvestRuleRepoImpl) Create(ctx context.Context, rule *AutoInvestRule) (int64, error) {
+	txn, err := dbtxn.Use(ctx, r.DB)
+	if err != nil {
+		return -1, err
+	}
+
+	builder := sq.
+		Insert(AutoInvestRuleTableName).
+		Columns(
+			AutoInvestRuleTable.LenderID,
+			AutoInvestRuleTable.LoanGrades,
+			AutoInvestRuleTable.LoanTypes,
+			AutoInvestRuleTable.BusinessSectors,
+			AutoInvestRuleTable.MinTenure,
+			AutoInvestRuleTable.MaxTenure,
+			AutoInvestRuleTable.MinRate,
+			AutoInvestRuleTable.AmountPerLoan,
+			AutoInvestRuleTable.MonthlyBudget,
+			AutoInvestRuleTable.IsActive,
+			AutoInvestRuleTable.LastInvestedAt,
+			AutoInvestRuleTable.CreatedAt,
+			AutoInvestRuleTable.UpdatedAt,
+			AutoInvestRuleTable.DeletedAt,
+		).
+		Suffix("RETURNING id").
+		PlaceholderFormat(sq.Dollar).
+		Values(
+			rule.LenderID,
+			pq.Array(rule.LoanGrades),
+			pq.Array(rule.LoanTypes),
+			pq.Array(rule.BusinessSectors),
+			rule.MinTenure,
+			rule.MaxTenure,
+			rule.MinRate,
+			rule.AmountPerLoan,
+			rule.MonthlyBudget,
+			rule.IsActive,
+			rule.LastInvestedAt,
+			time.Now(),
+			time.Now(),
+			nil,
+		)
+
+	scanner := builder.RunWith(txn).QueryRowContext(ctx)
+
+	var id int64
+	if err := scanner.Scan(&id); err != nil {
+		return -1, fmt.Errorf("failed to scan id: %v", err)
+	}
+
+	return id, nil
+}
+
+// Update AutoInvestRule
+func (r *AutoInvestRuleRepoImpl) Update(ctx context.Context, rule *AutoInvestRule) error {
+	txn, err := dbtxn.Use(ctx, r.DB)
+	if err != nil {
+		return err
+	}
+
+	builder := sq.Update(AutoInvestRuleTableName).
+		Set(AutoInvestRuleTable.LoanGrades, pq.Array(rule.LoanGrades)).
+		Set(AutoInvestRuleTable.LoanTypes, pq.Array(rule.LoanTypes)).
+		Set(AutoInvestRuleTable.BusinessSectors, pq.Array(rule.BusinessSectors)).
+		Set(AutoInvestRuleTable.MinTenure, rule.MinTenure).
+		Set(AutoInvestRuleTable.MaxTenure, rule.MaxTenure).
+		Set(AutoInvestRuleTable.MinRate, rule.MinRate).
+		Set(AutoInvestRuleTable.AmountPerLoan, rule.AmountPerLoan).
+		Set(AutoInvestRuleTable.MonthlyBudget, rule.MonthlyBudget).
+		Set(AutoInvestRuleTable.IsActive, rule.IsActive).
+		Set(AutoInvestRuleTable.LastInvestedAt, rule.LastInvestedAt).
+		Set(AutoInvestRuleTable.UpdatedAt, time.Now()).
+		Where(sq.Eq{
+			AutoInvestRuleTable.ID:        rule.ID,
+			AutoInvestRuleTable.DeletedAt: nil,
+		}).
+		PlaceholderFormat(sq.Dollar)
+
+	result, err := builder.RunWith(txn).ExecContext(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to update auto-invest rule: %v", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %v", err)
+	}
+	if rowsAffected == 0 {
+		return fmt.Errorf("no auto-invest rule found with ID: %d", rule.ID)
+	}
+
+	return nil
+}
+
+// Delete soft deletes an AutoInvestRule
+func (r *AutoInvestRuleRepoImpl) Delete(ctx context.Context, id int64) error {
+	txn, err := dbtxn.Use(ctx, r.DB)
+	if err != nil {
+		return err
+	}
+
+	builder := sq.Update(AutoInvestRuleTableName).
+		Set(AutoInvestRuleTable.UpdatedAt, time.Now()).
+		Set(AutoInvestRuleTable.DeletedAt, time.Now()).
+		Where(sq.Eq{
+			AutoInvestRuleTable.ID:        id,
+			AutoInvestRuleTable.DeletedAt: nil,
+		}).
+		PlaceholderFormat(sq.Dollar)
+
+	result, err := builder.RunWith(txn).ExecContext(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to delete auto-invest rule: %v", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %v", err)
+	}
+	if rowsAffected == 0 {
+		return fmt.Errorf("no auto-invest rule found with ID: %d", id)
+	}
+
+	return nil
+}
+
+// GetByID returns the rule, nil when it does not exist
+func (r *AutoInvestRuleRepoImpl) GetByID(ctx context.Context, id int64) (*AutoInvestRule, error) {
+	txn, err := dbtxn.Use(ctx, r.DB)
+	if err != nil {
+		return nil, err
+	}
+
+	builder := r.selectBuilder().
+		Where(sq.Eq{
+			AutoInvestRuleTable.ID:        id,
+			AutoInvestRuleTable.DeletedAt: nil,
+		})
+
+	var rule AutoInvestRule
+	scanner := builder.RunWith(txn).QueryRowContext(ctx)
+	if err := scanner.Scan(r.scanDest(&rule)...); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to scan auto-invest rule: %v", err)
+	}
+
+	return &rule, nil
+}
+
+// GetByLenderID returns the rules of a lender, oldest first
+func (r *AutoInvestRuleRepoImpl) GetByLenderID(ctx context.Context, lenderID int64) ([]AutoInvestRule, error) {
+	builder := r.selectBuilder().
+		Where(sq.Eq{
+			AutoInvestRuleTable.LenderID:  lenderID,
+			AutoInvestRuleTable.DeletedAt: nil,
+		}).
+		OrderBy(AutoInvestRuleTable.ID + " ASC")
+
+	return r.query(ctx, builder)
+}
+
+// GetActive returns the active rules of every lender in the order they get their turn in a run, the
+// rules that waited longest for an order come first
+func (r *AutoInvestRuleRepoImpl) GetActive(ctx context.Context) ([]AutoInvestRule, error) {
+	builder := r.selectBuilder().
+		Where(sq.Eq{
+			AutoInvestRuleTable.IsActive:  true,
+			AutoInvestRuleTable.DeletedAt: nil,
+		}).
+		OrderBy(
+			AutoInvestRuleTable.LastInvestedAt+" ASC NULLS FIRST",
+			AutoInvestRuleTable.ID+" ASC",
+		)
+
+	return r.query(ctx, builder)
+}
+
+func (r *AutoInvestRuleRepoImpl) query(ctx context.Context, builder sq.SelectBuilder) ([]AutoInvestRule, error) {
+	txn, err := dbtxn.Use(ctx, r.DB)
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := builder.RunWith(txn).QueryContext(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute query: %v", err)
+	}
+	defer rows.Close()
+
+	var rules []AutoInvestRule
+	for rows.Next() {
+		var rule AutoInvestRule
+		if err := rows.Scan(r.scanDest(&rule)...); err != nil {
+			return nil, fmt.Errorf("failed to scan row: %v", err)
+		}
+		rules = append(rules, rule)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate over rows: %v", err)
+	}
+
+	return rules, nil
+}
+
+func (r *AutoInvestRuleRepoImpl) selectBuilder() sq.SelectBuilder {
+	return sq.
+		Select(
+			AutoInvestRuleTable.ID,
+			AutoInvestRuleTable.LenderID,
+			AutoInvestRuleTable.LoanGrades,
+			AutoInvestRuleTable.LoanTypes,
+			AutoInvestRuleTable.BusinessSectors,
+			AutoInvestRuleTable.MinTenure,
+			AutoInvestRuleTable.MaxTenure,
+			AutoInvestRuleTable.MinRate,
+			AutoInvestRuleTable.AmountPerLoan,
+			AutoInvestRuleTable.MonthlyBudget,
+			AutoInvestRuleTable.IsActive,
+			AutoInvestRuleTable.LastInvestedAt,
+			AutoInvestRuleTable.CreatedAt,
+			AutoInvestRuleTable.UpdatedAt,
+			AutoInvestRuleTable.DeletedAt,
+		).
+		From(AutoInvestRuleTableName).
+		PlaceholderFormat(sq.Dollar)
+}
+
+func (r *AutoInvestRuleRepoImpl) scanDest(rule *AutoInvestRule) []interface{} {
+	return []interface{}{
+		&rule.ID,
+		&rule.LenderID,
+		pq.Array(&rule.LoanGrades),
+		pq.Array(&rule.LoanTypes),
+		pq.Array(&rule.BusinessSectors),
+		&rule.MinTenure,
+		&rule.MaxTenure,
+		&rule.MinRate,
+		&rule.AmountPerLoan,
+		&rule.MonthlyBudget,
+		&rule.IsActive,
+		&rule.LastInvestedAt,
+		&rule.CreatedAt,
+		&rule.UpdatedAt,
+		&rule.DeletedAt,
+	}
+}
@@ -0,0 +1,193 @@
+package repo
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	sq "github.com/Masterminds/squirrel"
+	"github.com/test/loan-service/internal/enum"
+	"github.com/test/loan-service/internal/money"
+	"github.com/typical-go/typical-rest-server/pkg/dbtxn"
+	"go.uber.org/dig"
+	"time"
+)
+
+//go:generate mockgen -source=$GOFILE -destination=$PROJ/internal/generated/mock/mock_$GOPACKAGE/$GOFILE
+
+type (
+	AutoInvestRunItem struct {
+		ID          int64                  `db:"id"`           // Run item ID
+		RunID       int64                  `db:"run_id"`       // Run the rule was evaluated in
+		RuleID      int64                  `db:"rule_id"`      // Rule evaluated
+		LenderID    int64                  `db:"lender_id"`    // Lender of the rule
+		Outcome     enum.AutoInvestOutcome `db:"outcome"`      // ordered, skipped, failed
+		Reason      string                 `db:"reason"`       // Why the rule was skipped or failed
+		Amount      money.Amount           `db:"amount"`       // Amount ordered, or that would have been ordered
+		OrderNumber *string                `db:"order_number"` // Order number of the funding when ordered
+		CreatedAt   time.Time              `db:"created_at"`   // Date of creation
+	}
+
+	AutoInvestRunItemRepo interface {
+		Create(ctx context.Context, item *AutoInvestRunItem) (int64, error)
+		GetByRunID(ctx context.Context, runID int64) ([]AutoInvestRunItem, error)
+		GetOrderedAmountByRuleID(ctx context.Context, ruleID int64, from time.Time) (money.Amount, error)
+	}
+
+	AutoInvestRunItemRepoImpl struct {
+		dig.In
+		*sql.DB
+	}
+)
+
+var (
+	AutoInvestRunItemTableName = "auto_invest_run_items"
+	AutoInvestRunItemTable     = struct {
+		ID          string
+		RunID       string
+		RuleID      string
+		LenderID    string
+		Outcome     string
+		Reason      string
+		Amount      string
+		OrderNumber string
+		CreatedAt   string
+	}{
+		ID:          "id",
+		RunID:       "run_id",
+		RuleID:      "rule_id",
+		LenderID:    "lender_id",
+		Outcome:     "outcome",
+		Reason:      "reason",
+		Amount:      "amount",
+		OrderNumber: "order_number",
+		CreatedAt:   "created_at",
+	}
+)
+
+func NewAutoInvestRunItemRepo(impl AutoInvestRunItemRepoImpl) AutoInvestRunItemRepo {
+	return &impl
+}
+
+// Create AutoInvestRunItem and return last inserted id
+func (r *AutoInvestRunItemRepoImpl) Create(ctx context.Context, item *AutoInvestRunItem) (int64, error) {
+	txn, err := dbtxn.Use(ctx, r.DB)
+	if err != nil {
+		return -1, err
+	}
+
+	builder := sq.
+		Insert(AutoInvestRunItemTableName).
+		Columns(
+			AutoInvestRunItemTable.RunID,
+			AutoInvestRunItemTable.RuleID,
+			AutoInvestRunItemTable.LenderID,
+			AutoInvestRunItemTable.Outcome,
+			AutoInvestRunItemTable.Reason,
+			AutoInvestRunItemTable.Amount,
+			AutoInvestRunItemTable.OrderNumber,
+			AutoInvestRunItemTable.CreatedAt,
+		).
+		Suffix("RETURNING id").
+		PlaceholderFormat(sq.Dollar).
+		Values(
+			item.RunID,
+			item.RuleID,
+			item.LenderID,
+			item.Outcome,
+			item.Reason,
+			item.Amount,
+			item.OrderNumber,
+			time.Now(),
+		)
+
+	scanner := builder.RunWith(txn).QueryRowContext(ctx)
+
+	var id int64
+	if err := scanner.Scan(&id); err != nil {
+		return -1, fmt.Errorf("failed to scan id: %v", err)
+	}
+
+	return id, nil
+}
+
+// GetByRunID returns the items of a run in the order the rules were evaluated
+func (r *AutoInvestRunItemRepoImpl) GetByRunID(ctx context.Context, runID int64) ([]AutoInvestRunItem, error) {
+	txn, err := dbtxn.Use(ctx, r.DB)
+	if err != nil {
+		return nil, err
+	}
+
+	builder := sq.
+		Select(
+			AutoInvestRunItemTable.ID,
+			AutoInvestRunItemTable.RunID,
+			AutoInvestRunItemTable.RuleID,
+			AutoInvestRunItemTable.LenderID,
+			AutoInvestRunItemTable.Outcome,
+			AutoInvestRunItemTable.Reason,
+			AutoInvestRunItemTable.Amount,
+			AutoInvestRunItemTable.OrderNumber,
+			AutoInvestRunItemTable.CreatedAt,
+		).
+		From(AutoInvestRunItemTableName).
+		Where(sq.Eq{AutoInvestRunItemTable.RunID: runID}).
+		OrderBy(AutoInvestRunItemTable.ID + " ASC").
+		PlaceholderFormat(sq.Dollar)
+
+	rows, err := builder.RunWith(txn).QueryContext(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute query: %v", err)
+	}
+	defer rows.Close()
+
+	var items []AutoInvestRunItem
+	for rows.Next() {
+		var item AutoInvestRunItem
+		if err := rows.Scan(
+			&item.ID,
+			&item.RunID,
+			&item.RuleID,
+			&item.LenderID,
+			&item.Outcome,
+			&item.Reason,
+			&item.Amount,
+			&item.OrderNumber,
+			&item.CreatedAt,
+		); err != nil {
+			return nil, fmt.Errorf("failed to scan row: %v", err)
+		}
+		items = append(items, item)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate over rows: %v", err)
+	}
+
+	return items, nil
+}
+
+// GetOrderedAmountByRuleID returns the total amount the rule ordered since the given time
+func (r *AutoInvestRunItemRepoImpl) GetOrderedAmountByRuleID(ctx context.Context, ruleID int64, from time.Time) (money.Amount, error) {
+	txn, err := dbtxn.Use(ctx, r.DB)
+	if err != nil {
+		return 0, err
+	}
+
+	builder := sq.
+		Select("COALESCE(SUM(" + AutoInvestRunItemTable.Amount + "), 0)").
+		From(AutoInvestRunItemTableName).
+		Where(sq.Eq{
+			AutoInvestRunItemTable.RuleID:  ruleID,
+			AutoInvestRunItemTable.Outcome: enum.AutoInvestOrdered,
+		}).
+		Where(sq.GtOrEq{AutoInvestRunItemTable.CreatedAt: from}).
+		PlaceholderFormat(sq.Dollar)
+
+	var amount money.Amount
+	scanner := builder.RunWith(txn).QueryRowContext(ctx)
+	if err := scanner.Scan(&amount); err != nil {
+		return 0, fmt.Errorf("failed to scan ordered amount: %v", err)
+	}
+
+	return amount, nil
+}
@@ -0,0 +1,198 @@
+package repo
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	sq "github.com/Masterminds/squirrel"
+	"github.com/test/loan-service/internal/money"
+	"github.com/typical-go/typical-rest-server/pkg/dbtxn"
+	"go.uber.org/dig"
+	"time"
+)
+
+//go:generate mockgen -source=$GOFILE -destination=$PROJ/internal/generated/mock/mock_$GOPACKAGE/$GOFILE
+
+type (
+	AutoInvestRun struct {
+		ID             int64        `db:"id"`              // Auto-invest run ID
+		LoanID         int64        `db:"loan_id"`         // Approved loan the rules were run for
+		RulesEvaluated int64        `db:"rules_evaluated"` // Number of active rules evaluated
+		OrdersCreated  int64        `db:"orders_created"`  // Number of funding orders created
+		TotalOrdered   money.Amount `db:"total_ordered"`   // Total amount of the funding orders created
+		StartedAt      time.Time    `db:"started_at"`      // Date the run started
+		FinishedAt     *time.Time   `db:"finished_at"`     // Date the run finished, nil while running
+		CreatedAt      time.Time    `db:"created_at"`      // Date of creation
+		UpdatedAt      time.Time    `db:"updated_at"`      // Date of last update
+	}
+
+	AutoInvestRunRepo interface {
+		Create(ctx context.Context, run *AutoInvestRun) (int64, error)
+		Update(ctx context.Context, run *AutoInvestRun) error
+		GetByLoanID(ctx context.Context, loanID int64) ([]AutoInvestRun, error)
+	}
+
+	AutoInvestRunRepoImpl struct {
+		dig.In
+		*sql.DB
+	}
+)
+
+var (
+	AutoInvestRunTableName = "auto_invest_runs"
+	AutoInvestRunTable     = struct {
+		ID             string
+		LoanID         string
+		RulesEvaluated string
+		OrdersCreated  string
+		TotalOrdered   string
+		StartedAt      string
+		FinishedAt     string
+		CreatedAt      string
+		UpdatedAt      string
+	}{
+		ID:             "id",
+		LoanID:         "loan_id",
+		RulesEvaluated: "rules_evaluated",
+		OrdersCreated:  "orders_created",
+		TotalOrdered:   "total_ordered",
+		StartedAt:      "started_at",
+		FinishedAt:     "finished_at",
+		CreatedAt:      "created_at",
+		UpdatedAt:      "updated_at",
+	}
+)
+
+func NewAutoInvestRunRepo(impl AutoInvestRunRepoImpl) AutoInvestRunRepo {
+	return &impl
+}
+
+// Create AutoInvestRun and return last inserted id
+func (r *AutoInvestRunRepoImpl) Create(ctx context.Context, run *AutoInvestRun) (int64, error) {
+	txn, err := dbtxn.Use(ctx, r.DB)
+	if err != nil {
+		return -1, err
+	}
+
+	builder := sq.
+		Insert(AutoInvestRunTableName).
+		Columns(
+			AutoInvestRunTable.LoanID,
+			AutoInvestRunTable.RulesEvaluated,
+			AutoInvestRunTable.OrdersCreated,
+			AutoInvestRunTable.TotalOrdered,
+			AutoInvestRunTable.StartedAt,
+			AutoInvestRunTable.FinishedAt,
+			AutoInvestRunTable.CreatedAt,
+			AutoInvestRunTable.UpdatedAt,
+		).
+		Suffix("RETURNING id").
+		PlaceholderFormat(sq.Dollar).
+		Values(
+			run.LoanID,
+			run.RulesEvaluated,
+			run.OrdersCreated,
+			run.TotalOrdered,
+			run.StartedAt,
+			run.FinishedAt,
+			time.Now(),
+			time.Now(),
+		)
+
+	scanner := builder.RunWith(txn).QueryRowContext(ctx)
+
+	var id int64
+	if err := scanner.Scan(&id); err != nil {
+		return -1, fmt.Errorf("failed to scan id: %v", err)
+	}
+
+	return id, nil
+}
+
+// Update AutoInvestRun
+func (r *AutoInvestRunRepoImpl) Update(ctx context.Context, run *AutoInvestRun) error {
+	txn, err := dbtxn.Use(ctx, r.DB)
+	if err != nil {
+		return err
+	}
+
+	builder := sq.Update(AutoInvestRunTableName).
+		Set(AutoInvestRunTable.RulesEvaluated, run.RulesEvaluated).
+		Set(AutoInvestRunTable.OrdersCreated, run.OrdersCreated).
+		Set(AutoInvestRunTable.TotalOrdered, run.TotalOrdered).
+		Set(AutoInvestRunTable.FinishedAt, run.FinishedAt).
+		Set(AutoInvestRunTable.UpdatedAt, time.Now()).
+		Where(sq.Eq{AutoInvestRunTable.ID: run.ID}).
+		PlaceholderFormat(sq.Dollar)
+
+	result, err := builder.RunWith(txn).ExecContext(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to update auto-invest run: %v", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %v", err)
+	}
+	if rowsAffected == 0 {
+		return fmt.Errorf("no auto-invest run found with ID: %d", run.ID)
+	}
+
+	return nil
+}
+
+// GetByLoanID returns the runs of a loan, most recent first
+func (r *AutoInvestRunRepoImpl) GetByLoanID(ctx context.Context, loanID int64) ([]AutoInvestRun, error) {
+	txn, err := dbtxn.Use(ctx, r.DB)
+	if err != nil {
+		return nil, err
+	}
+
+	builder := sq.
+		Select(
+			AutoInvestRunTable.ID,
+			AutoInvestRunTable.LoanID,
+			AutoInvestRunTable.RulesEvaluated,
+			AutoInvestRunTable.OrdersCreated,
+			AutoInvestRunTable.TotalOrdered,
+			AutoInvestRunTable.StartedAt,
+			AutoInvestRunTable.FinishedAt,
+			AutoInvestRunTable.CreatedAt,
+			AutoInvestRunTable.UpdatedAt,
+		).
+		From(AutoInvestRunTableName).
+		Where(sq.Eq{AutoInvestRunTable.LoanID: loanID}).
+		OrderBy(AutoInvestRunTable.StartedAt+" DESC", AutoInvestRunTable.ID+" DESC").
+		PlaceholderFormat(sq.Dollar)
+
+	rows, err := builder.RunWith(txn).QueryContext(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute query: %v", err)
+	}
+	defer rows.Close()
+
+	var runs []AutoInvestRun
+	for rows.Next() {
+		var run AutoInvestRun
+		if err := rows.Scan(
+			&run.ID,
+			&run.LoanID,
+			&run.RulesEvaluated,
+			&run.OrdersCreated,
+			&run.TotalOrdered,
+			&run.StartedAt,
+			&run.FinishedAt,
+			&run.CreatedAt,
+			&run.UpdatedAt,
+		); err != nil {
+			return nil, fmt.Errorf("failed to scan row: %v", err)
+		}
+		runs = append(runs, run)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate over rows: %v", err)
+	}
+
+	return runs, nil
+}
@@ -0,0 +1,360 @@
+package repo
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	sq "github.com/Masterminds/squirrel"
+	"github.com/typical-go/typical-rest-server/pkg/dbtxn"
+	"go.uber.org/dig"
+	"time"
+)
+
+//go:generate mockgen -source=$GOFILE -destination=$PROJ/internal/generated/mock/mock_$GOPACKAGE/$GOFILE
+
+type (
+	Borrower struct {
+		Kyc // KYC status and the date it was verified
+
+		ID                         int64      `db:"id"`                           // Borrower ID
+		FullName                   string     `db:"full_name"`                    // Full name of the borrower as on the national ID
+		NationalID                 string     `db:"national_id"`                  // National ID number (NIK)
+		DateOfBirth                time.Time  `db:"date_of_birth"`                // Date of birth
+		PhoneNumber                string     `db:"phone_number"`                 // Phone number of the borrower
+		Email                      string     `db:"email"`                        // Email address of the borrower
+		Address                    string     `db:"address"`                      // Residential address
+		BusinessName               *string    `db:"business_name"`                // Business name of the borrower
+		BusinessType               *string    `db:"business_type"`                // Type of business (e.g., retail, manufacturing)
+		BusinessAddress            *string    `db:"business_address"`             // Business address
+		BusinessPhoneNumber        *string    `db:"business_phone_number"`        // Business phone number
+		BusinessEmail              *string    `db:"business_email"`               // Business email address
+		BusinessRegistrationNumber *string    `db:"business_registration_number"` // Business registration number
+		BusinessOwnerName          *string    `db:"business_owner_name"`          // Business owner's name
+		BusinessDescription        *string    `db:"business_description"`         // Business description
+		BusinessSector             *string    `db:"business_sector"`              // Business sector (e.g., agriculture, technology)
+		BusinessEstablishedYear    *int64     `db:"business_established_year"`    // Year the business was established
+		CreatedAt                  time.Time  `db:"created_at"`                   // Date of creation
+		UpdatedAt                  time.Time  `db:"updated_at"`                   // Date of last update
+		DeletedAt                  *time.Time `db:"deleted_at"`                   // Date of deletion if applicable
+	}
+
+	BorrowerRepo interface {
+		Create(ctx context.Context, borrower *Borrower) (int64, error)
+		Update(ctx context.Context, borrower *Borrower) error
+		GetByID(ctx context.Context, id int64) (*Borrower, error)
+		GetByIDForUpdate(ctx context.Context, id int64) (*Borrower, error)
+		GetByNationalID(ctx context.Context, nationalID string) (*Borrower, error)
+	}
+
+	BorrowerRepoImpl struct {
+		dig.In
+		*sql.DB
+	}
+)
+
+var (
+	BorrowerTableName = "borrowers"
+	BorrowerTable     = struct {
+		ID                         string
+		FullName                   string
+		NationalID                 string
+		DateOfBirth                string
+		PhoneNumber                string
+		Email                      string
+		Address                    string
+		BusinessName               string
+		BusinessType               string
+		BusinessAddress            string
+		BusinessPhoneNumber        string
+		BusinessEmail              string
+		BusinessRegistrationNumber string
+		BusinessOwnerName          string
+		BusinessDescription        string
+		BusinessSector             string
+		BusinessEstablishedYear    string
+		KycStatus                  string
+		KycVerifiedAt              string
+		CreatedAt                  string
+		UpdatedAt                  string
+		DeletedAt                  string
+	}{
+		ID:                         "id",
+		FullName:                   "full_name",
+		NationalID:                 "national_id",
+		DateOfBirth:                "date_of_birth",
+		PhoneNumber:                "phone_number",
+		Email:                      "email",
+		Address:                    "address",
+		BusinessName:               "business_name",
+		BusinessType:               "business_type",
+		BusinessAddress:            "business_address",
+		BusinessPhoneNumber:        "business_phone_number",
+		BusinessEmail:              "business_email",
+		BusinessRegistrationNumber: "business_registration_number",
+		BusinessOwnerName:          "business_owner_name",
+		BusinessDescription:        "business_description",
+		BusinessSector:             "business_sector",
+		BusinessEstablishedYear:    "business_established_year",
+		KycStatus:                  "kyc_status",
+		KycVerifiedAt:              "kyc_verified_at",
+		CreatedAt:                  "created_at",
+		UpdatedAt:                  "updated_at",
+		DeletedAt:                  "deleted_at",
+	}
+)
+
+func NewBorrowerRepo(impl BorrowerRepoImpl) BorrowerRepo {
+	return &impl
+}
+
+// Create Borrower and return last inserted id
+func (r *BorrowerRepoImpl) Create(ctx context.Context, borrower *Borrower) (int64, error) {
+	txn, err := dbtxn.Use(ctx, r.DB)
+	if err != nil {
+		return -1, err
+	}
+
+	builder := sq.
+		Insert(BorrowerTableName).
+		Columns(
+			BorrowerTable.FullName,
+			BorrowerTable.NationalID,
+			BorrowerTable.DateOfBirth,
+			BorrowerTable.PhoneNumber,
+			BorrowerTable.Email,
+			BorrowerTable.Address,
+			BorrowerTable.BusinessName,
+			BorrowerTable.BusinessType,
+			BorrowerTable.BusinessAddress,
+			BorrowerTable.BusinessPhoneNumber,
+			BorrowerTable.BusinessEmail,
+			BorrowerTable.BusinessRegistrationNumber,
+			BorrowerTable.BusinessOwnerName,
+			BorrowerTable.BusinessDescription,
+			BorrowerTable.BusinessSector,
+			BorrowerTable.BusinessEstablishedYear,
+			BorrowerTable.KycStatus,
+			BorrowerTable.KycVerifiedAt,
+			BorrowerTable.CreatedAt,
+			BorrowerTable.UpdatedAt,
+			BorrowerTable.DeletedAt,
+		).
+		Suffix("RETURNING id").
+		PlaceholderFormat(sq.Dollar).
+		Values(
+			borrower.FullName,
+			borrower.NationalID,
+			borrower.DateOfBirth,
+			borrower.PhoneNumber,
+			borrower.Email,
+			borrower.Address,
+			borrower.BusinessName,
+			borrower.BusinessType,
+			borrower.BusinessAddress,
+			borrower.BusinessPhoneNumber,
+			borrower.BusinessEmail,
+			borrower.BusinessRegistrationNumber,
+			borrower.BusinessOwnerName,
+			borrower.BusinessDescription,
+			borrower.BusinessSector,
+			borrower.BusinessEstablishedYear,
+			borrower.KycStatus,
+			borrower.KycVerifiedAt,
+			time.Now(),
+			time.Now(),
+			nil,
+		)
+
+	scanner := builder.RunWith(txn).QueryRowContext(ctx)
+
+	var id int64
+	if err := scanner.Scan(&id); err != nil {
+		return -1, fmt.Errorf("failed to scan id: %v", err)
+	}
+
+	return id, nil
+}
+
+// Update Borrower profile and KYC status
+func (r *BorrowerRepoImpl) Update(ctx context.Context, borrower *Borrower) error {
+	txn, err := dbtxn.Use(ctx, r.DB)
+	if err != nil {
+		return err
+	}
+
+	builder := sq.Update(BorrowerTableName).
+		Set(BorrowerTable.FullName, borrower.FullName).
+		Set(BorrowerTable.NationalID, borrower.NationalID).
+		Set(BorrowerTable.DateOfBirth, borrower.DateOfBirth).
+		Set(BorrowerTable.PhoneNumber, borrower.PhoneNumber).
+		Set(BorrowerTable.Email, borrower.Email).
+		Set(BorrowerTable.Address, borrower.Address).
+		Set(BorrowerTable.BusinessName, borrower.BusinessName).
+		Set(BorrowerTable.BusinessType, borrower.BusinessType).
+		Set(BorrowerTable.BusinessAddress, borrower.BusinessAddress).
+		Set(BorrowerTable.BusinessPhoneNumber, borrower.BusinessPhoneNumber).
+		Set(BorrowerTable.BusinessEmail, borrower.BusinessEmail).
+		Set(BorrowerTable.BusinessRegistrationNumber, borrower.BusinessRegistrationNumber).
+		Set(BorrowerTable.BusinessOwnerName, borrower.BusinessOwnerName).
+		Set(BorrowerTable.BusinessDescription, borrower.BusinessDescription).
+		Set(BorrowerTable.BusinessSector, borrower.BusinessSector).
+		Set(BorrowerTable.BusinessEstablishedYear, borrower.BusinessEstablishedYear).
+		Set(BorrowerTable.KycStatus, borrower.KycStatus).
+		Set(BorrowerTable.KycVerifiedAt, borrower.KycVerifiedAt).
+		Set(BorrowerTable.UpdatedAt, time.Now()).
+		Where(sq.Eq{
+			BorrowerTable.ID:        borrower.ID,
+			BorrowerTable.DeletedAt: nil,
+		}).
+		PlaceholderFormat(sq.Dollar)
+
+	result, err := builder.RunWith(txn).ExecContext(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to update borrower: %v", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %v", err)
+	}
+	if rowsAffected == 0 {
+		return fmt.Errorf("no borrower found: %d", borrower.ID)
+	}
+
+	return nil
+}
+
+// GetByID returns the borrower, nil when it does not exist
+func (r *BorrowerRepoImpl) GetByID(ctx context.Context, id int64) (*Borrower, error) {
+	txn, err := dbtxn.Use(ctx, r.DB)
+	if err != nil {
+		return nil, err
+	}
+
+	builder := r.selectBuilder().
+		Where(sq.Eq{
+			BorrowerTable.ID:        id,
+			BorrowerTable.DeletedAt: nil,
+		})
+
+	var borrower Borrower
+	scanner := builder.RunWith(txn).QueryRowContext(ctx)
+	if err := scanner.Scan(r.scanDest(&borrower)...); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to scan borrower: %v", err)
+	}
+
+	return &borrower, nil
+}
+
+// GetByIDForUpdate returns the borrower and locks the row until the transaction ends, nil when it does
+// not exist
+func (r *BorrowerRepoImpl) GetByIDForUpdate(ctx context.Context, id int64) (*Borrower, error) {
+	// row lock only holds when ctx carries a transaction
+	txn, err := dbtxn.Use(ctx, r.DB)
+	if err != nil {
+		return nil, err
+	}
+
+	builder := r.selectBuilder().
+		Where(sq.Eq{
+			BorrowerTable.ID:        id,
+			BorrowerTable.DeletedAt: nil,
+		}).
+		Suffix("FOR UPDATE")
+
+	var borrower Borrower
+	scanner := builder.RunWith(txn).QueryRowContext(ctx)
+	if err := scanner.Scan(r.scanDest(&borrower)...); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to scan borrower: %v", err)
+	}
+
+	return &borrower, nil
+}
+
+// GetByNationalID returns the borrower registered with a national ID, nil when there is none
+func (r *BorrowerRepoImpl) GetByNationalID(ctx context.Context, nationalID string) (*Borrower, error) {
+	txn, err := dbtxn.Use(ctx, r.DB)
+	if err != nil {
+		return nil, err
+	}
+
+	builder := r.selectBuilder().
+		Where(sq.Eq{
+			BorrowerTable.NationalID: nationalID,
+			BorrowerTable.DeletedAt:  nil,
+		})
+
+	var borrower Borrower
+	scanner := builder.RunWith(txn).QueryRowContext(ctx)
+	if err := scanner.Scan(r.scanDest(&borrower)...); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to scan borrower: %v", err)
+	}
+
+	return &borrower, nil
+}
+
+func (r *BorrowerRepoImpl) selectBuilder() sq.SelectBuilder {
+	return sq.
+		Select(
+			BorrowerTable.ID,
+			BorrowerTable.FullName,
+			BorrowerTable.NationalID,
+			BorrowerTable.DateOfBirth,
+			BorrowerTable.PhoneNumber,
+			BorrowerTable.Email,
+			BorrowerTable.Address,
+			BorrowerTable.BusinessName,
+			BorrowerTable.BusinessType,
+			BorrowerTable.BusinessAddress,
+			BorrowerTable.BusinessPhoneNumber,
+			BorrowerTable.BusinessEmail,
+			BorrowerTable.BusinessRegistrationNumber,
+			BorrowerTable.BusinessOwnerName,
+			BorrowerTable.BusinessDescription,
+			BorrowerTable.BusinessSector,
+			BorrowerTable.BusinessEstablishedYear,
+			BorrowerTable.KycStatus,
+			BorrowerTable.KycVerifiedAt,
+			BorrowerTable.CreatedAt,
+			BorrowerTable.UpdatedAt,
+			BorrowerTable.DeletedAt,
+		).
+		From(BorrowerTableName).
+		PlaceholderFormat(sq.Dollar)
+}
+
+func (r *BorrowerRepoImpl) scanDest(borrower *Borrower) []interface{} {
+	return []interface{}{
+		&borrower.ID,
+		&borrower.FullName,
+		&borrower.NationalID,
+		&borrower.DateOfBirth,
+		&borrower.PhoneNumber,
+		&borrower.Email,
+		&borrower.Address,
+		&borrower.BusinessName,
+		&borrower.BusinessType,
+		&borrower.BusinessAddress,
+		&borrower.BusinessPhoneNumber,
+		&borrower.BusinessEmail,
+		&borrower.BusinessRegistrationNumber,
+		&borrower.BusinessOwnerName,
+		&borrower.BusinessDescription,
+		&borrower.BusinessSector,
+		&borrower.BusinessEstablishedYear,
+		&borrower.KycStatus,
+		&borrower.KycVerifiedAt,
+		&borrower.CreatedAt,
+		&borrower.UpdatedAt,
+		&borrower.DeletedAt,
+	}
+}
@@ -0,0 +1,180 @@
+package repo
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	sq "github.com/Masterminds/squirrel"
+	"github.com/test/loan-service/internal/enum"
+	"github.com/typical-go/typical-rest-server/pkg/dbtxn"
+	"go.uber.org/dig"
+	"time"
+)
+
+//go:generate mockgen -source=$GOFILE -destination=$PROJ/internal/generated/mock/mock_$GOPACKAGE/$GOFILE
+
+type (
+	CreditGrade struct {
+		ID        int64          `db:"id"`         // Grade ID
+		Grade     enum.LoanGrade `db:"grade"`      // Loan grade
+		MinScore  int64          `db:"min_score"`  // Minimum score to get the grade
+		CreatedAt time.Time      `db:"created_at"` // Date of creation
+		UpdatedAt time.Time      `db:"updated_at"` // Date of last update
+		DeletedAt *time.Time     `db:"deleted_at"` // Date of deletion if applicable
+	}
+
+	CreditGradeRepo interface {
+		Update(ctx context.Context, grade *CreditGrade) error
+		GetByGrade(ctx context.Context, grade enum.LoanGrade) (*CreditGrade, error)
+		GetAll(ctx context.Context) ([]CreditGrade, error)
+	}
+
+	CreditGradeRepoImpl struct {
+		dig.In
+		*sql.DB
+	}
+)
+
+var (
+	CreditGradeTableName = "credit_grades"
+	CreditGradeTable     = struct {
+		ID        string
+		Grade     string
+		MinScore  string
+		CreatedAt string
+		UpdatedAt string
+		DeletedAt string
+	}{
+		ID:        "id",
+		Grade:     "grade",
+		MinScore:  "min_score",
+		CreatedAt: "created_at",
+		UpdatedAt: "updated_at",
+		DeletedAt: "deleted_at",
+	}
+)
+
+func NewCreditGradeRepo(impl CreditGradeRepoImpl) CreditGradeRepo {
+	return &impl
+}
+
+// Update the minimum score of a CreditGrade
+func (r *CreditGradeRepoImpl) Update(ctx context.Context, grade *CreditGrade) error {
+	txn, err := dbtxn.Use(ctx, r.DB)
+	if err != nil {
+		return err
+	}
+
+	builder := sq.Update(CreditGradeTableName).
+		Set(CreditGradeTable.MinScore, grade.MinScore).
+		Set(CreditGradeTable.UpdatedAt, time.Now()).
+		Where(sq.Eq{CreditGradeTable.Grade: grade.Grade}).
+		PlaceholderFormat(sq.Dollar)
+
+	result, err := builder.RunWith(txn).ExecContext(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to update credit grade: %v", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %v", err)
+	}
+	if rowsAffected == 0 {
+		return fmt.Errorf("no credit grade found for grade: %s", grade.Grade)
+	}
+
+	return nil
+}
+
+// GetByGrade returns the credit grade, nil when the grade is not configured
+func (r *CreditGradeRepoImpl) GetByGrade(ctx context.Context, grade enum.LoanGrade) (*CreditGrade, error) {
+	txn, err := dbtxn.Use(ctx, r.DB)
+	if err != nil {
+		return nil, err
+	}
+
+	builder := sq.
+		Select(
+			CreditGradeTable.ID,
+			CreditGradeTable.Grade,
+			CreditGradeTable.MinScore,
+			CreditGradeTable.CreatedAt,
+			CreditGradeTable.UpdatedAt,
+			CreditGradeTable.DeletedAt,
+		).
+		From(CreditGradeTableName).
+		Where(sq.Eq{
+			CreditGradeTable.Grade:     grade,
+			CreditGradeTable.DeletedAt: nil,
+		}).
+		PlaceholderFormat(sq.Dollar)
+
+	var creditGrade CreditGrade
+	scanner := builder.RunWith(txn).QueryRowContext(ctx)
+	if err := scanner.Scan(
+		&creditGrade.ID,
+		&creditGrade.Grade,
+		&creditGrade.MinScore,
+		&creditGrade.CreatedAt,
+		&creditGrade.UpdatedAt,
+		&creditGrade.DeletedAt,
+	); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to scan credit grade: %v", err)
+	}
+
+	return &creditGrade, nil
+}
+
+// GetAll returns every credit grade ordered from the highest minimum score
+func (r *CreditGradeRepoImpl) GetAll(ctx context.Context) ([]CreditGrade, error) {
+	txn, err := dbtxn.Use(ctx, r.DB)
+	if err != nil {
+		return nil, err
+	}
+
+	builder := sq.
+		Select(
+			CreditGradeTable.ID,
+			CreditGradeTable.Grade,
+			CreditGradeTable.MinScore,
+			CreditGradeTable.CreatedAt,
+			CreditGradeTable.UpdatedAt,
+			CreditGradeTable.DeletedAt,
+		).
+		From(CreditGradeTableName).
+		Where(sq.Eq{CreditGradeTable.DeletedAt: nil}).
+		OrderBy(CreditGradeTable.MinScore+" DESC", CreditGradeTable.Grade+" ASC").
+		PlaceholderFormat(sq.Dollar)
+
+	rows, err := builder.RunWith(txn).QueryContext(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute query: %v", err)
+	}
+	defer rows.Close()
+
+	var grades []CreditGrade
+	for rows.Next() {
+		var creditGrade CreditGrade
+		if err := rows.Scan(
+			&creditGrade.ID,
+			&creditGrade.Grade,
+			&creditGrade.MinScore,
+			&creditGrade.CreatedAt,
+			&creditGrade.UpdatedAt,
+			&creditGrade.DeletedAt,
+		); err != nil {
+			return nil, fmt.Errorf("failed to scan row: %v", err)
+		}
+		grades = append(grades, creditGrade)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate over rows: %v", err)
+	}
+
+	return grades, nil
+}
@@ -0,0 +1,158 @@
+package repo
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	sq "github.com/Masterminds/squirrel"
+	"github.com/test/loan-service/internal/enum"
+	"github.com/typical-go/typical-rest-server/pkg/dbtxn"
+	"go.uber.org/dig"
+	"time"
+)
+
+//go:generate mockgen -source=$GOFILE -destination=$PROJ/internal/generated/mock/mock_$GOPACKAGE/$GOFILE
+
+type (
+	CreditScoreFactor struct {
+		ID              int64                    `db:"id"`               // Credit score factor ID
+		CreditScoreID   int64                    `db:"credit_score_id"`  // Credit score ID
+		Factor          enum.CreditScoringFactor `db:"factor"`           // Scored factor
+		Value           string                   `db:"value"`            // Factor value computed from the loan request
+		RuleID          *int64                   `db:"rule_id"`          // Matched rule, nil when no rule matched
+		RuleDescription string                   `db:"rule_description"` // Description of the matched rule at scoring time
+		Points          int64                    `db:"points"`           // Points contributed by the factor
+		CreatedAt       time.Time                `db:"created_at"`       // Date of scoring
+	}
+
+	CreditScoreFactorRepo interface {
+		Create(ctx context.Context, factor *CreditScoreFactor) (int64, error)
+		GetByCreditScoreID(ctx context.Context, creditScoreID int64) ([]CreditScoreFactor, error)
+	}
+
+	CreditScoreFactorRepoImpl struct {
+		dig.In
+		*sql.DB
+	}
+)
+
+var (
+	CreditScoreFactorTableName = "credit_score_factors"
+	CreditScoreFactorTable     = struct {
+		ID              string
+		CreditScoreID   string
+		Factor          string
+		Value           string
+		RuleID          string
+		RuleDescription string
+		Points          string
+		CreatedAt       string
+	}{
+		ID:              "id",
+		CreditScoreID:   "credit_score_id",
+		Factor:          "factor",
+		Value:           "value",
+		RuleID:          "rule_id",
+		RuleDescription: "rule_description",
+		Points:          "points",
+		CreatedAt:       "created_at",
+	}
+)
+
+func NewCreditScoreFactorRepo(impl CreditScoreFactorRepoImpl) CreditScoreFactorRepo {
+	return &impl
+}
+
+// Create CreditScoreFactor and return last inserted id
+func (r *CreditScoreFactorRepoImpl) Create(ctx context.Context, factor *CreditScoreFactor) (int64, error) {
+	txn, err := dbtxn.Use(ctx, r.DB)
+	if err != nil {
+		return -1, err
+	}
+
+	builder := sq.
+		Insert(CreditScoreFactorTableName).
+		Columns(
+			CreditScoreFactorTable.CreditScoreID,
+			CreditScoreFactorTable.Factor,
+			CreditScoreFactorTable.Value,
+			CreditScoreFactorTable.RuleID,
+			CreditScoreFactorTable.RuleDescription,
+			CreditScoreFactorTable.Points,
+			CreditScoreFactorTable.CreatedAt,
+		).
+		Suffix("RETURNING id").
+		PlaceholderFormat(sq.Dollar).
+		Values(
+			factor.CreditScoreID,
+			factor.Factor,
+			factor.Value,
+			factor.RuleID,
+			factor.RuleDescription,
+			factor.Points,
+			factor.CreatedAt,
+		)
+
+	scanner := builder.RunWith(txn).QueryRowContext(ctx)
+
+	var id int64
+	if err := scanner.Scan(&id); err != nil {
+		return -1, fmt.Errorf("failed to scan id: %v", err)
+	}
+
+	return id, nil
+}
+
+// GetByCreditScoreID returns every factor of a credit score in scoring order
+func (r *CreditScoreFactorRepoImpl) GetByCreditScoreID(ctx context.Context, creditScoreID int64) ([]CreditScoreFactor, error) {
+	txn, err := dbtxn.Use(ctx, r.DB)
+	if err != nil {
+		return nil, err
+	}
+
+	builder := sq.
+		Select(
+			CreditScoreFactorTable.ID,
+			CreditScoreFactorTable.CreditScoreID,
+			CreditScoreFactorTable.Factor,
+			CreditScoreFactorTable.Value,
+			CreditScoreFactorTable.RuleID,
+			CreditScoreFactorTable.RuleDescription,
+			CreditScoreFactorTable.Points,
+			CreditScoreFactorTable.CreatedAt,
+		).
+		From(CreditScoreFactorTableName).
+		Where(sq.Eq{CreditScoreFactorTable.CreditScoreID: creditScoreID}).
+		OrderBy(CreditScoreFactorTable.ID + " ASC").
+		PlaceholderFormat(sq.Dollar)
+
+	rows, err := builder.RunWith(txn).QueryContext(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute query: %v", err)
+	}
+	defer rows.Close()
+
+	var factors []CreditScoreFactor
+	for rows.Next() {
+		var factor CreditScoreFactor
+		if err := rows.Scan(
+			&factor.ID,
+			&factor.CreditScoreID,
+			&factor.Factor,
+			&factor.Value,
+			&factor.RuleID,
+			&factor.RuleDescription,
+			&factor.Points,
+			&factor.CreatedAt,
+		); err != nil {
+			return nil, fmt.Errorf("failed to scan row: %v", err)
+		}
+		factors = append(factors, factor)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate over rows: %v", err)
+	}
+
+	return factors, nil
+}
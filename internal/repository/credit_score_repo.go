@@ -0,0 +1,126 @@
+package repo
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	sq "github.com/Masterminds/squirrel"
+	"github.com/test/loan-service/internal/enum"
+	"github.com/typical-go/typical-rest-server/pkg/dbtxn"
+	"go.uber.org/dig"
+	"time"
+)
+
+//go:generate mockgen -source=$GOFILE -destination=$PROJ/internal/generated/mock/mock_$GOPACKAGE/$GOFILE
+
+type (
+	CreditScore struct {
+		ID        int64          `db:"id"`         // Credit score ID
+		LoanID    int64          `db:"loan_id"`    // Scored loan
+		Score     int64          `db:"score"`      // Total score of every factor
+		Grade     enum.LoanGrade `db:"grade"`      // Loan grade derived from the score
+		CreatedAt time.Time      `db:"created_at"` // Date of scoring
+	}
+
+	CreditScoreRepo interface {
+		Create(ctx context.Context, score *CreditScore) (int64, error)
+		GetByLoanID(ctx context.Context, loanID int64) (*CreditScore, error)
+	}
+
+	CreditScoreRepoImpl struct {
+		dig.In
+		*sql.DB
+	}
+)
+
+var (
+	CreditScoreTableName = "credit_scores"
+	CreditScoreTable     = struct {
+		ID        string
+		LoanID    string
+		Score     string
+		Grade     string
+		CreatedAt string
+	}{
+		ID:        "id",
+		LoanID:    "loan_id",
+		Score:     "score",
+		Grade:     "grade",
+		CreatedAt: "created_at",
+	}
+)
+
+func NewCreditScoreRepo(impl CreditScoreRepoImpl) CreditScoreRepo {
+	return &impl
+}
+
+// Create CreditScore and return last inserted id
+func (r *CreditScoreRepoImpl) Create(ctx context.Context, score *CreditScore) (int64, error) {
+	txn, err := dbtxn.Use(ctx, r.DB)
+	if err != nil {
+		return -1, err
+	}
+
+	builder := sq.
+		Insert(CreditScoreTableName).
+		Columns(
+			CreditScoreTable.LoanID,
+			CreditScoreTable.Score,
+			CreditScoreTable.Grade,
+			CreditScoreTable.CreatedAt,
+		).
+		Suffix("RETURNING id").
+		PlaceholderFormat(sq.Dollar).
+		Values(
+			score.LoanID,
+			score.Score,
+			score.Grade,
+			score.CreatedAt,
+		)
+
+	scanner := builder.RunWith(txn).QueryRowContext(ctx)
+
+	var id int64
+	if err := scanner.Scan(&id); err != nil {
+		return -1, fmt.Errorf("failed to scan id: %v", err)
+	}
+
+	return id, nil
+}
+
+// GetByLoanID returns the credit score of a loan, nil when the loan has not been scored
+func (r *CreditScoreRepoImpl) GetByLoanID(ctx context.Context, loanID int64) (*CreditScore, error) {
+	txn, err := dbtxn.Use(ctx, r.DB)
+	if err != nil {
+		return nil, err
+	}
+
+	builder := sq.
+		Select(
+			CreditScoreTable.ID,
+			CreditScoreTable.LoanID,
+			CreditScoreTable.Score,
+			CreditScoreTable.Grade,
+			CreditScoreTable.CreatedAt,
+		).
+		From(CreditScoreTableName).
+		Where(sq.Eq{CreditScoreTable.LoanID: loanID}).
+		PlaceholderFormat(sq.Dollar)
+
+	var score CreditScore
+	scanner := builder.RunWith(txn).QueryRowContext(ctx)
+	if err := scanner.Scan(
+		&score.ID,
+		&score.LoanID,
+		&score.Score,
+		&score.Grade,
+		&score.CreatedAt,
+	); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to scan credit score: %v", err)
+	}
+
+	return &score, nil
+}
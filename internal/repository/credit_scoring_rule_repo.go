@@ -0,0 +1,277 @@
+package repo
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	sq "github.com/Masterminds/squirrel"
+	"github.com/test/loan-service/internal/enum"
+	"github.com/typical-go/typical-rest-server/pkg/dbtxn"
+	"go.uber.org/dig"
+	"time"
+)
+
+//go:generate mockgen -source=$GOFILE -destination=$PROJ/internal/generated/mock/mock_$GOPACKAGE/$GOFILE
+
+type (
+	CreditScoringRule struct {
+		ID          int64                    `db:"id"`          // Rule ID
+		Factor      enum.CreditScoringFactor `db:"factor"`      // Scored factor
+		MinValue    *float64                 `db:"min_value"`   // Inclusive lower bound of the factor value, nil for no lower bound
+		MaxValue    *float64                 `db:"max_value"`   // Exclusive upper bound of the factor value, nil for no upper bound
+		MatchValue  string                   `db:"match_value"` // Business sector matched by the rule, empty matches every other sector
+		Points      int64                    `db:"points"`      // Points added to the score when the rule matches
+		Description string                   `db:"description"` // Description of the rule
+		CreatedAt   time.Time                `db:"created_at"`  // Date of creation
+		UpdatedAt   time.Time                `db:"updated_at"`  // Date of last update
+		DeletedAt   *time.Time               `db:"deleted_at"`  // Date of deletion if applicable
+	}
+
+	CreditScoringRuleRepo interface {
+		Create(ctx context.Context, rule *CreditScoringRule) (int64, error)
+		Update(ctx context.Context, rule *CreditScoringRule) error
+		Delete(ctx context.Context, id int64) error
+		GetByID(ctx context.Context, id int64) (*CreditScoringRule, error)
+		GetAll(ctx context.Context) ([]CreditScoringRule, error)
+	}
+
+	CreditScoringRuleRepoImpl struct {
+		dig.In
+		*sql.DB
+	}
+)
+
+var (
+	CreditScoringRuleTableName = "credit_scoring_rules"
+	CreditScoringRuleTable     = struct {
+		ID          string
+		Factor      string
+		MinValue    string
+		MaxValue    string
+		MatchValue  string
+		Points      string
+		Description string
+		CreatedAt   string
+		UpdatedAt   string
+		DeletedAt   string
+	}{
+		ID:          "id",
+		Factor:      "factor",
+		MinValue:    "min_value",
+		MaxValue:    "max_value",
+		MatchValue:  "match_value",
+		Points:      "points",
+		Description: "description",
+		CreatedAt:   "created_at",
+		UpdatedAt:   "updated_at",
+		DeletedAt:   "deleted_at",
+	}
+)
+
+func NewCreditScoringRuleRepo(impl CreditScoringRuleRepoImpl) CreditScoringRuleRepo {
+	return &impl
+}
+
+// Create CreditScoringRule and return last inserted id
+func (r *CreditScoringRuleRepoImpl) Create(ctx context.Context, rule *CreditScoringRule) (int64, error) {
+	txn, err := dbtxn.Use(ctx, r.DB)
+	if err != nil {
+		return -1, err
+	}
+
+	builder := sq.
+		Insert(CreditScoringRuleTableName).
+		Columns(
+			CreditScoringRuleTable.Factor,
+			CreditScoringRuleTable.MinValue,
+			CreditScoringRuleTable.MaxValue,
+			CreditScoringRuleTable.MatchValue,
+			CreditScoringRuleTable.Points,
+			CreditScoringRuleTable.Description,
+			CreditScoringRuleTable.CreatedAt,
+			CreditScoringRuleTable.UpdatedAt,
+			CreditScoringRuleTable.DeletedAt,
+		).
+		Suffix("RETURNING id").
+		PlaceholderFormat(sq.Dollar).
+		Values(
+			rule.Factor,
+			rule.MinValue,
+			rule.MaxValue,
+			rule.MatchValue,
+			rule.Points,
+			rule.Description,
+			time.Now(),
+			time.Now(),
+			nil,
+		)
+
+	scanner := builder.RunWith(txn).QueryRowContext(ctx)
+
+	var id int64
+	if err := scanner.Scan(&id); err != nil {
+		return -1, fmt.Errorf("failed to scan id: %v", err)
+	}
+
+	return id, nil
+}
+
+// Update CreditScoringRule
+func (r *CreditScoringRuleRepoImpl) Update(ctx context.Context, rule *CreditScoringRule) error {
+	txn, err := dbtxn.Use(ctx, r.DB)
+	if err != nil {
+		return err
+	}
+
+	builder := sq.Update(CreditScoringRuleTableName).
+		Set(CreditScoringRuleTable.Factor, rule.Factor).
+		Set(CreditScoringRuleTable.MinValue, rule.MinValue).
+		Set(CreditScoringRuleTable.MaxValue, rule.MaxValue).
+		Set(CreditScoringRuleTable.MatchValue, rule.MatchValue).
+		Set(CreditScoringRuleTable.Points, rule.Points).
+		Set(CreditScoringRuleTable.Description, rule.Description).
+		Set(CreditScoringRuleTable.UpdatedAt, time.Now()).
+		Where(sq.Eq{
+			CreditScoringRuleTable.ID:        rule.ID,
+			CreditScoringRuleTable.DeletedAt: nil,
+		}).
+		PlaceholderFormat(sq.Dollar)
+
+	result, err := builder.RunWith(txn).ExecContext(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to update credit scoring rule: %v", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %v", err)
+	}
+	if rowsAffected == 0 {
+		return fmt.Errorf("no credit scoring rule found with ID: %d", rule.ID)
+	}
+
+	return nil
+}
+
+// Delete soft deletes a CreditScoringRule, scores already computed keep the rule description
+func (r *CreditScoringRuleRepoImpl) Delete(ctx context.Context, id int64) error {
+	txn, err := dbtxn.Use(ctx, r.DB)
+	if err != nil {
+		return err
+	}
+
+	builder := sq.Update(CreditScoringRuleTableName).
+		Set(CreditScoringRuleTable.UpdatedAt, time.Now()).
+		Set(CreditScoringRuleTable.DeletedAt, time.Now()).
+		Where(sq.Eq{
+			CreditScoringRuleTable.ID:        id,
+			CreditScoringRuleTable.DeletedAt: nil,
+		}).
+		PlaceholderFormat(sq.Dollar)
+
+	result, err := builder.RunWith(txn).ExecContext(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to delete credit scoring rule: %v", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %v", err)
+	}
+	if rowsAffected == 0 {
+		return fmt.Errorf("no credit scoring rule found with ID: %d", id)
+	}
+
+	return nil
+}
+
+// GetByID returns the rule, nil when it does not exist
+func (r *CreditScoringRuleRepoImpl) GetByID(ctx context.Context, id int64) (*CreditScoringRule, error) {
+	txn, err := dbtxn.Use(ctx, r.DB)
+	if err != nil {
+		return nil, err
+	}
+
+	builder := r.selectBuilder().
+		Where(sq.Eq{
+			CreditScoringRuleTable.ID:        id,
+			CreditScoringRuleTable.DeletedAt: nil,
+		})
+
+	var rule CreditScoringRule
+	scanner := builder.RunWith(txn).QueryRowContext(ctx)
+	if err := scanner.Scan(r.scanDest(&rule)...); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to scan credit scoring rule: %v", err)
+	}
+
+	return &rule, nil
+}
+
+// GetAll returns every rule ordered by factor then ID, the order in which rules are matched
+func (r *CreditScoringRuleRepoImpl) GetAll(ctx context.Context) ([]CreditScoringRule, error) {
+	txn, err := dbtxn.Use(ctx, r.DB)
+	if err != nil {
+		return nil, err
+	}
+
+	builder := r.selectBuilder().
+		Where(sq.Eq{CreditScoringRuleTable.DeletedAt: nil}).
+		OrderBy(CreditScoringRuleTable.Factor+" ASC", CreditScoringRuleTable.ID+" ASC")
+
+	rows, err := builder.RunWith(txn).QueryContext(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute query: %v", err)
+	}
+	defer rows.Close()
+
+	var rules []CreditScoringRule
+	for rows.Next() {
+		var rule CreditScoringRule
+		if err := rows.Scan(r.scanDest(&rule)...); err != nil {
+			return nil, fmt.Errorf("failed to scan row: %v", err)
+		}
+		rules = append(rules, rule)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate over rows: %v", err)
+	}
+
+	return rules, nil
+}
+
+func (r *CreditScoringRuleRepoImpl) selectBuilder() sq.SelectBuilder {
+	return sq.
+		Select(
+			CreditScoringRuleTable.ID,
+			CreditScoringRuleTable.Factor,
+			CreditScoringRuleTable.MinValue,
+			CreditScoringRuleTable.MaxValue,
+			CreditScoringRuleTable.MatchValue,
+			CreditScoringRuleTable.Points,
+			CreditScoringRuleTable.Description,
+			CreditScoringRuleTable.CreatedAt,
+			CreditScoringRuleTable.UpdatedAt,
+			CreditScoringRuleTable.DeletedAt,
+		).
+		From(CreditScoringRuleTableName).
+		PlaceholderFormat(sq.Dollar)
+}
+
+func (r *CreditScoringRuleRepoImpl) scanDest(rule *CreditScoringRule) []interface{} {
+	return []interface{}{
+		&rule.ID,
+		&rule.Factor,
+		&rule.MinValue,
+		&rule.MaxValue,
+		&rule.MatchValue,
+		&rule.Points,
+		&rule.Description,
+		&rule.CreatedAt,
+		&rule.UpdatedAt,
+		&rule.DeletedAt,
+	}
+}
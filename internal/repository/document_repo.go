@@ -0,0 +1,257 @@
+package repo
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	sq "github.com/Masterminds/squirrel"
+	"github.com/test/loan-service/internal/enum"
+	"github.com/typical-go/typical-rest-server/pkg/dbtxn"
+	"go.uber.org/dig"
+	"time"
+)
+
+//go:generate mockgen -source=$GOFILE -destination=$PROJ/internal/generated/mock/mock_$GOPACKAGE/$GOFILE
+
+type (
+	Document struct {
+		ID           int64               `db:"id"`            // Document ID
+		StorageKey   string              `db:"storage_key"`   // Key of the file in the document store
+		FileName     string              `db:"file_name"`     // File name given by the uploader
+		ContentType  string              `db:"content_type"`  // Content type detected from the file content
+		Size         int64               `db:"size"`          // File size in bytes
+		Checksum     string              `db:"checksum"`      // SHA-256 of the file content, hex encoded
+		EntityType   enum.DocumentEntity `db:"entity_type"`   // Record the document belongs to
+		EntityID     int64               `db:"entity_id"`     // ID of the record the document belongs to
+		DocumentType string              `db:"document_type"` // Kind of document
+		UploadedBy   *int64              `db:"uploaded_by"`   // Staff who uploaded the document, nil for generated documents
+		CreatedAt    time.Time           `db:"created_at"`    // Date of creation
+		UpdatedAt    time.Time           `db:"updated_at"`    // Date of last update
+		DeletedAt    *time.Time          `db:"deleted_at"`    // Date of deletion if applicable
+	}
+
+	DocumentRepo interface {
+		Create(ctx context.Context, document *Document) (int64, error)
+		GetByID(ctx context.Context, id int64) (*Document, error)
+		GetByStorageKey(ctx context.Context, key string) (*Document, error)
+		GetByEntity(ctx context.Context, entityType enum.DocumentEntity, entityID int64) ([]Document, error)
+	}
+
+	DocumentRepoImpl struct {
+		dig.In
+		*sql.DB
+	}
+)
+
+var (
+	DocumentTableName = "documents"
+	DocumentTable     = struct {
+		ID           string
+		StorageKey   string
+		FileName     string
+		ContentType  string
+		Size         string
+		Checksum     string
+		EntityType   string
+		EntityID     string
+		DocumentType string
+		UploadedBy   string
+		CreatedAt    string
+		UpdatedAt    string
+		DeletedAt    string
+	}{
+		ID:           "id",
+		StorageKey:   "storage_key",
+		FileName:     "file_name",
+		ContentType:  "content_type",
+		Size:         "size",
+		Checksum:     "checksum",
+		EntityType:   "entity_type",
+		EntityID:     "entity_id",
+		DocumentType: "document_type",
+		UploadedBy:   "uploaded_by",
+		CreatedAt:    "created_at",
+		UpdatedAt:    "updated_at",
+		DeletedAt:    "deleted_at",
+	}
+)
+
+func NewDocumentRepo(impl DocumentRepoImpl) DocumentRepo {
+	return &impl
+}
+
+// Create Document and return last inserted id
+func (r *DocumentRepoImpl) Create(ctx context.Context, document *Document) (int64, error) {
+	txn, err := dbtxn.Use(ctx, r.DB)
+	if err != nil {
+		return -1, err
+	}
+
+	builder := sq.
+		Insert(DocumentTableName).
+		Columns(
+			DocumentTable.StorageKey,
+			DocumentTable.FileName,
+			DocumentTable.ContentType,
+			DocumentTable.Size,
+			DocumentTable.Checksum,
+			DocumentTable.EntityType,
+			DocumentTable.EntityID,
+			DocumentTable.DocumentType,
+			DocumentTable.UploadedBy,
+			DocumentTable.CreatedAt,
+			DocumentTable.UpdatedAt,
+			DocumentTable.DeletedAt,
+		).
+		Suffix("RETURNING id").
+		PlaceholderFormat(sq.Dollar).
+		Values(
+			document.StorageKey,
+			document.FileName,
+			document.ContentType,
+			document.Size,
+			document.Checksum,
+			document.EntityType,
+			document.EntityID,
+			document.DocumentType,
+			document.UploadedBy,
+			time.Now(),
+			time.Now(),
+			nil,
+		)
+
+	scanner := builder.RunWith(txn).QueryRowContext(ctx)
+
+	var id int64
+	if err := scanner.Scan(&id); err != nil {
+		return -1, fmt.Errorf("failed to scan id: %v", err)
+	}
+
+	return id, nil
+}
+
+// GetByID returns the document, nil when it does not exist
+func (r *DocumentRepoImpl) GetByID(ctx context.Context, id int64) (*Document, error) {
+	txn, err := dbtxn.Use(ctx, r.DB)
+	if err != nil {
+		return nil, err
+	}
+
+	builder := r.selectBuilder().
+		Where(sq.Eq{
+			DocumentTable.ID:        id,
+			DocumentTable.DeletedAt: nil,
+		})
+
+	var document Document
+	scanner := builder.RunWith(txn).QueryRowContext(ctx)
+	if err := scanner.Scan(r.scanDest(&document)...); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to scan document: %v", err)
+	}
+
+	return &document, nil
+}
+
+// GetByStorageKey returns the document record of a file in the store
+func (r *DocumentRepoImpl) GetByStorageKey(ctx context.Context, key string) (*Document, error) {
+	txn, err := dbtxn.Use(ctx, r.DB)
+	if err != nil {
+		return nil, err
+	}
+
+	builder := r.selectBuilder().
+		Where(sq.Eq{
+			DocumentTable.StorageKey: key,
+			DocumentTable.DeletedAt:  nil,
+		})
+
+	var document Document
+	scanner := builder.RunWith(txn).QueryRowContext(ctx)
+	if err := scanner.Scan(r.scanDest(&document)...); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to scan document: %v", err)
+	}
+
+	return &document, nil
+}
+
+// GetByEntity returns the documents of a record, oldest first
+func (r *DocumentRepoImpl) GetByEntity(ctx context.Context, entityType enum.DocumentEntity, entityID int64) ([]Document, error) {
+	txn, err := dbtxn.Use(ctx, r.DB)
+	if err != nil {
+		return nil, err
+	}
+
+	builder := r.selectBuilder().
+		Where(sq.Eq{
+			DocumentTable.EntityType: entityType,
+			DocumentTable.EntityID:   entityID,
+			DocumentTable.DeletedAt:  nil,
+		}).
+		OrderBy(DocumentTable.ID + " ASC")
+
+	rows, err := builder.RunWith(txn).QueryContext(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute query: %v", err)
+	}
+	defer rows.Close()
+
+	var documents []Document
+	for rows.Next() {
+		var document Document
+		if err := rows.Scan(r.scanDest(&document)...); err != nil {
+			return nil, fmt.Errorf("failed to scan row: %v", err)
+		}
+		documents = append(documents, document)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate over rows: %v", err)
+	}
+
+	return documents, nil
+}
+
+func (r *DocumentRepoImpl) selectBuilder() sq.SelectBuilder {
+	return sq.
+		Select(
+			DocumentTable.ID,
+			DocumentTable.StorageKey,
+			DocumentTable.FileName,
+			DocumentTable.ContentType,
+			DocumentTable.Size,
+			DocumentTable.Checksum,
+			DocumentTable.EntityType,
+			DocumentTable.EntityID,
+			DocumentTable.DocumentType,
+			DocumentTable.UploadedBy,
+			DocumentTable.CreatedAt,
+			DocumentTable.UpdatedAt,
+			DocumentTable.DeletedAt,
+		).
+		From(DocumentTableName).
+		PlaceholderFormat(sq.Dollar)
+}
+
+func (r *DocumentRepoImpl) scanDest(document *Document) []interface{} {
+	return []interface{}{
+		&document.ID,
+		&document.StorageKey,
+		&document.FileName,
+		&document.ContentType,
+		&document.Size,
+		&document.Checksum,
+		&document.EntityType,
+		&document.EntityID,
+		&document.DocumentType,
+		&document.UploadedBy,
+		&document.CreatedAt,
+		&document.UpdatedAt,
+		&document.DeletedAt,
+	}
+}
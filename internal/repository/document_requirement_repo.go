@@ -0,0 +1,269 @@
+package repo
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	sq "github.com/Masterminds/squirrel"
+	"github.com/test/loan-service/internal/enum"
+	"github.com/typical-go/typical-rest-server/pkg/dbtxn"
+	"go.uber.org/dig"
+	"time"
+)
+
+//go:generate mockgen -source=$GOFILE -destination=$PROJ/internal/generated/mock/mock_$GOPACKAGE/$GOFILE
+
+type (
+	DocumentRequirementRequest struct {
+		LoanType enum.LoanType
+	}
+
+	DocumentRequirement struct {
+		ID           int64         `db:"id"`            // Document requirement ID
+		LoanType     enum.LoanType `db:"loan_type"`     // Loan type the requirement applies to
+		DocumentType string        `db:"document_type"` // Document type attached to the approval
+		Name         string        `db:"name"`          // Name of the document reported when it is missing
+		Mandatory    bool          `db:"mandatory"`     // Loans of the type can not be approved without the document
+		CreatedAt    time.Time     `db:"created_at"`    // Date of creation
+		UpdatedAt    time.Time     `db:"updated_at"`    // Date of last update
+		DeletedAt    *time.Time    `db:"deleted_at"`    // Date of deletion if applicable
+	}
+
+	DocumentRequirementRepo interface {
+		Create(ctx context.Context, requirement *DocumentRequirement) (int64, error)
+		Update(ctx context.Context, requirement *DocumentRequirement) error
+		Delete(ctx context.Context, id int64) error
+		GetByID(ctx context.Context, id int64) (*DocumentRequirement, error)
+		GetAll(ctx context.Context, request DocumentRequirementRequest) ([]DocumentRequirement, error)
+	}
+
+	DocumentRequirementRepoImpl struct {
+		dig.In
+		*sql.DB
+	}
+)
+
+var (
+	DocumentRequirementTableName = "document_requirements"
+	DocumentRequirementTable     = struct {
+		ID           string
+		LoanType     string
+		DocumentType string
+		Name         string
+		Mandatory    string
+		CreatedAt    string
+		UpdatedAt    string
+		DeletedAt    string
+	}{
+		ID:           "id",
+		LoanType:     "loan_type",
+		DocumentType: "document_type",
+		Name:         "name",
+		Mandatory:    "mandatory",
+		CreatedAt:    "created_at",
+		UpdatedAt:    "updated_at",
+		DeletedAt:    "deleted_at",
+	}
+)
+
+func NewDocumentRequirementRepo(impl DocumentRequirementRepoImpl) DocumentRequirementRepo {
+	return &impl
+}
+
+// Create DocumentRequirement and return last inserted id
+func (r *DocumentRequirementRepoImpl) Create(ctx context.Context, requirement *DocumentRequirement) (int64, error) {
+	txn, err := dbtxn.Use(ctx, r.DB)
+	if err != nil {
+		return -1, err
+	}
+
+	builder := sq.
+		Insert(DocumentRequirementTableName).
+		Columns(
+			DocumentRequirementTable.LoanType,
+			DocumentRequirementTable.DocumentType,
+			DocumentRequirementTable.Name,
+			DocumentRequirementTable.Mandatory,
+			DocumentRequirementTable.CreatedAt,
+			DocumentRequirementTable.UpdatedAt,
+			DocumentRequirementTable.DeletedAt,
+		).
+		Suffix("RETURNING id").
+		PlaceholderFormat(sq.Dollar).
+		Values(
+			requirement.LoanType,
+			requirement.DocumentType,
+			requirement.Name,
+			requirement.Mandatory,
+			time.Now(),
+			time.Now(),
+			nil,
+		)
+
+	scanner := builder.RunWith(txn).QueryRowContext(ctx)
+
+	var id int64
+	if err := scanner.Scan(&id); err != nil {
+		return -1, fmt.Errorf("failed to scan id: %v", err)
+	}
+
+	return id, nil
+}
+
+// Update DocumentRequirement
+func (r *DocumentRequirementRepoImpl) Update(ctx context.Context, requirement *DocumentRequirement) error {
+	txn, err := dbtxn.Use(ctx, r.DB)
+	if err != nil {
+		return err
+	}
+
+	builder := sq.Update(DocumentRequirementTableName).
+		Set(DocumentRequirementTable.LoanType, requirement.LoanType).
+		Set(DocumentRequirementTable.DocumentType, requirement.DocumentType).
+		Set(DocumentRequirementTable.Name, requirement.Name).
+		Set(DocumentRequirementTable.Mandatory, requirement.Mandatory).
+		Set(DocumentRequirementTable.UpdatedAt, time.Now()).
+		Where(sq.Eq{
+			DocumentRequirementTable.ID:        requirement.ID,
+			DocumentRequirementTable.DeletedAt: nil,
+		}).
+		PlaceholderFormat(sq.Dollar)
+
+	result, err := builder.RunWith(txn).ExecContext(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to update document requirement: %v", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %v", err)
+	}
+	if rowsAffected == 0 {
+		return fmt.Errorf("no document requirement found with ID: %d", requirement.ID)
+	}
+
+	return nil
+}
+
+// Delete soft deletes a DocumentRequirement, documents already attached to approvals are kept
+func (r *DocumentRequirementRepoImpl) Delete(ctx context.Context, id int64) error {
+	txn, err := dbtxn.Use(ctx, r.DB)
+	if err != nil {
+		return err
+	}
+
+	builder := sq.Update(DocumentRequirementTableName).
+		Set(DocumentRequirementTable.UpdatedAt, time.Now()).
+		Set(DocumentRequirementTable.DeletedAt, time.Now()).
+		Where(sq.Eq{
+			DocumentRequirementTable.ID:        id,
+			DocumentRequirementTable.DeletedAt: nil,
+		}).
+		PlaceholderFormat(sq.Dollar)
+
+	result, err := builder.RunWith(txn).ExecContext(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to delete document requirement: %v", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %v", err)
+	}
+	if rowsAffected == 0 {
+		return fmt.Errorf("no document requirement found with ID: %d", id)
+	}
+
+	return nil
+}
+
+// GetByID returns the requirement, nil when it does not exist
+func (r *DocumentRequirementRepoImpl) GetByID(ctx context.Context, id int64) (*DocumentRequirement, error) {
+	txn, err := dbtxn.Use(ctx, r.DB)
+	if err != nil {
+		return nil, err
+	}
+
+	builder := r.selectBuilder().
+		Where(sq.Eq{
+			DocumentRequirementTable.ID:        id,
+			DocumentRequirementTable.DeletedAt: nil,
+		})
+
+	var requirement DocumentRequirement
+	scanner := builder.RunWith(txn).QueryRowContext(ctx)
+	if err := scanner.Scan(r.scanDest(&requirement)...); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to scan document requirement: %v", err)
+	}
+
+	return &requirement, nil
+}
+
+// GetAll returns the requirements, of a single loan type when it is given, ordered by loan type then ID
+func (r *DocumentRequirementRepoImpl) GetAll(ctx context.Context, request DocumentRequirementRequest) ([]DocumentRequirement, error) {
+	txn, err := dbtxn.Use(ctx, r.DB)
+	if err != nil {
+		return nil, err
+	}
+
+	builder := r.selectBuilder().
+		Where(sq.Eq{DocumentRequirementTable.DeletedAt: nil}).
+		OrderBy(DocumentRequirementTable.LoanType+" ASC", DocumentRequirementTable.ID+" ASC")
+
+	if request.LoanType != "" {
+		builder = builder.Where(sq.Eq{DocumentRequirementTable.LoanType: request.LoanType})
+	}
+
+	rows, err := builder.RunWith(txn).QueryContext(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute query: %v", err)
+	}
+	defer rows.Close()
+
+	var requirements []DocumentRequirement
+	for rows.Next() {
+		var requirement DocumentRequirement
+		if err := rows.Scan(r.scanDest(&requirement)...); err != nil {
+			return nil, fmt.Errorf("failed to scan row: %v", err)
+		}
+		requirements = append(requirements, requirement)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate over rows: %v", err)
+	}
+
+	return requirements, nil
+}
+
+func (r *DocumentRequirementRepoImpl) selectBuilder() sq.SelectBuilder {
+	return sq.
+		Select(
+			DocumentRequirementTable.ID,
+			DocumentRequirementTable.LoanType,
+			DocumentRequirementTable.DocumentType,
+			DocumentRequirementTable.Name,
+			DocumentRequirementTable.Mandatory,
+			DocumentRequirementTable.CreatedAt,
+			DocumentRequirementTable.UpdatedAt,
+			DocumentRequirementTable.DeletedAt,
+		).
+		From(DocumentRequirementTableName).
+		PlaceholderFormat(sq.Dollar)
+}
+
+func (r *DocumentRequirementRepoImpl) scanDest(requirement *DocumentRequirement) []interface{} {
+	return []interface{}{
+		&requirement.ID,
+		&requirement.LoanType,
+		&requirement.DocumentType,
+		&requirement.Name,
+		&requirement.Mandatory,
+		&requirement.CreatedAt,
+		&requirement.UpdatedAt,
+		&requirement.DeletedAt,
+	}
+}
@@ -0,0 +1,188 @@
+package repo
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	sq "github.com/Masterminds/squirrel"
+	"github.com/test/loan-service/internal/enum"
+	"github.com/typical-go/typical-rest-server/pkg/dbtxn"
+	"go.uber.org/dig"
+	"time"
+)
+
+//go:generate mockgen -source=$GOFILE -destination=$PROJ/internal/generated/mock/mock_$GOPACKAGE/$GOFILE
+
+type (
+	FeePolicy struct {
+		ID                       int64         `db:"id"`                         // Fee policy ID
+		LoanType                 enum.LoanType `db:"loan_type"`                  // Loan type the policy applies to
+		OriginationFeePercentage float64       `db:"origination_fee_percentage"` // Percentage of the disbursed principal deducted from the borrower payout
+		ServiceFeePercentage     float64       `db:"service_fee_percentage"`     // Percentage of the lender interest taken before it is credited
+		CreatedAt                time.Time     `db:"created_at"`                 // Date of creation
+		UpdatedAt                time.Time     `db:"updated_at"`                 // Date of last update
+		DeletedAt                *time.Time    `db:"deleted_at"`                 // Date of deletion if applicable
+	}
+
+	FeePolicyRepo interface {
+		Update(ctx context.Context, policy *FeePolicy) error
+		GetByLoanType(ctx context.Context, loanType enum.LoanType) (*FeePolicy, error)
+		GetAll(ctx context.Context) ([]FeePolicy, error)
+	}
+
+	FeePolicyRepoImpl struct {
+		dig.In
+		*sql.DB
+	}
+)
+
+var (
+	FeePolicyTableName = "fee_policies"
+	FeePolicyTable     = struct {
+		ID                       string
+		LoanType                 string
+		OriginationFeePercentage string
+		ServiceFeePercentage     string
+		CreatedAt                string
+		UpdatedAt                string
+		DeletedAt                string
+	}{
+		ID:                       "id",
+		LoanType:                 "loan_type",
+		OriginationFeePercentage: "origination_fee_percentage",
+		ServiceFeePercentage:     "service_fee_percentage",
+		CreatedAt:                "created_at",
+		UpdatedAt:                "updated_at",
+		DeletedAt:                "deleted_at",
+	}
+)
+
+func NewFeePolicyRepo(impl FeePolicyRepoImpl) FeePolicyRepo {
+	return &impl
+}
+
+// Update FeePolicy of a loan type
+func (r *FeePolicyRepoImpl) Update(ctx context.Context, policy *FeePolicy) error {
+	txn, err := dbtxn.Use(ctx, r.DB)
+	if err != nil {
+		return err
+	}
+
+	builder := sq.Update(FeePolicyTableName).
+		Set(FeePolicyTable.OriginationFeePercentage, policy.OriginationFeePercentage).
+		Set(FeePolicyTable.ServiceFeePercentage, policy.ServiceFeePercentage).
+		Set(FeePolicyTable.UpdatedAt, time.Now()).
+		Where(sq.Eq{FeePolicyTable.LoanType: policy.LoanType}).
+		PlaceholderFormat(sq.Dollar)
+
+	result, err := builder.RunWith(txn).ExecContext(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to update fee policy: %v", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %v", err)
+	}
+	if rowsAffected == 0 {
+		return fmt.Errorf("no fee policy found for loan type: %s", policy.LoanType)
+	}
+
+	return nil
+}
+
+// GetByLoanType returns the fee policy of a loan type, nil when the loan type has no policy
+func (r *FeePolicyRepoImpl) GetByLoanType(ctx context.Context, loanType enum.LoanType) (*FeePolicy, error) {
+	txn, err := dbtxn.Use(ctx, r.DB)
+	if err != nil {
+		return nil, err
+	}
+
+	builder := sq.
+		Select(
+			FeePolicyTable.ID,
+			FeePolicyTable.LoanType,
+			FeePolicyTable.OriginationFeePercentage,
+			FeePolicyTable.ServiceFeePercentage,
+			FeePolicyTable.CreatedAt,
+			FeePolicyTable.UpdatedAt,
+			FeePolicyTable.DeletedAt,
+		).
+		From(FeePolicyTableName).
+		Where(sq.Eq{
+			FeePolicyTable.LoanType:  loanType,
+			FeePolicyTable.DeletedAt: nil,
+		}).
+		PlaceholderFormat(sq.Dollar)
+
+	var policy FeePolicy
+	scanner := builder.RunWith(txn).QueryRowContext(ctx)
+	if err := scanner.Scan(
+		&policy.ID,
+		&policy.LoanType,
+		&policy.OriginationFeePercentage,
+		&policy.ServiceFeePercentage,
+		&policy.CreatedAt,
+		&policy.UpdatedAt,
+		&policy.DeletedAt,
+	); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to scan fee policy: %v", err)
+	}
+
+	return &policy, nil
+}
+
+// GetAll returns every fee policy ordered by loan type
+func (r *FeePolicyRepoImpl) GetAll(ctx context.Context) ([]FeePolicy, error) {
+	txn, err := dbtxn.Use(ctx, r.DB)
+	if err != nil {
+		return nil, err
+	}
+
+	builder := sq.
+		Select(
+			FeePolicyTable.ID,
+			FeePolicyTable.LoanType,
+			FeePolicyTable.OriginationFeePercentage,
+			FeePolicyTable.ServiceFeePercentage,
+			FeePolicyTable.CreatedAt,
+			FeePolicyTable.UpdatedAt,
+			FeePolicyTable.DeletedAt,
+		).
+		From(FeePolicyTableName).
+		Where(sq.Eq{FeePolicyTable.DeletedAt: nil}).
+		OrderBy(FeePolicyTable.LoanType + " ASC").
+		PlaceholderFormat(sq.Dollar)
+
+	rows, err := builder.RunWith(txn).QueryContext(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute query: %v", err)
+	}
+	defer rows.Close()
+
+	var policies []FeePolicy
+	for rows.Next() {
+		var policy FeePolicy
+		if err := rows.Scan(
+			&policy.ID,
+			&policy.LoanType,
+			&policy.OriginationFeePercentage,
+			&policy.ServiceFeePercentage,
+			&policy.CreatedAt,
+			&policy.UpdatedAt,
+			&policy.DeletedAt,
+		); err != nil {
+			return nil, fmt.Errorf("failed to scan row: %v", err)
+		}
+		policies = append(policies, policy)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate over rows: %v", err)
+	}
+
+	return policies, nil
+}
@@ -0,0 +1,185 @@
+package repo
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	sq "github.com/Masterminds/squirrel"
+	"github.com/typical-go/typical-rest-server/pkg/dbtxn"
+	"go.uber.org/dig"
+	"time"
+)
+
+//go:generate mockgen -source=$GOFILE -destination=$PROJ/internal/generated/mock/mock_$GOPACKAGE/$GOFILE
+
+type (
+	FundingAgreement struct {
+		ID              int64     `db:"id"`               // Agreement ID
+		AgreementNumber string    `db:"agreement_number"` // Agreement number printed on the document
+		LoanFundingID   int64     `db:"loan_funding_id"`  // Funding the agreement is made for
+		LoanID          int64     `db:"loan_id"`          // Loan of the funding
+		LenderID        int64     `db:"lender_id"`        // Lender of the funding
+		TemplateID      int64     `db:"template_id"`      // Template the agreement was rendered from
+		TemplateVersion int64     `db:"template_version"` // Version of the template
+		DocumentKey     string    `db:"document_key"`     // Key of the document in the document store
+		DocumentURL     string    `db:"document_url"`     // URL the document can be downloaded from
+		GeneratedAt     time.Time `db:"generated_at"`     // Date the document was generated
+		CreatedAt       time.Time `db:"created_at"`       // Date of creation
+		UpdatedAt       time.Time `db:"updated_at"`       // Date of last update
+	}
+
+	FundingAgreementRepo interface {
+		Create(ctx context.Context, agreement *FundingAgreement) (int64, error)
+		GetByLoanFundingID(ctx context.Context, loanFundingID int64) ([]FundingAgreement, error)
+	}
+
+	FundingAgreementRepoImpl struct {
+		dig.In
+		*sql.DB
+	}
+)
+
+var (
+	FundingAgreementTableName = "funding_agreements"
+	FundingAgreementTable     = struct {
+		ID              string
+		AgreementNumber string
+		LoanFundingID   string
+		LoanID          string
+		LenderID        string
+		TemplateID      string
+		TemplateVersion string
+		DocumentKey     string
+		DocumentURL     string
+		GeneratedAt     string
+		CreatedAt       string
+		UpdatedAt       string
+	}{
+		ID:              "id",
+		AgreementNumber: "agreement_number",
+		LoanFundingID:   "loan_funding_id",
+		LoanID:          "loan_id",
+		LenderID:        "lender_id",
+		TemplateID:      "template_id",
+		TemplateVersion: "template_version",
+		DocumentKey:     "document_key",
+		DocumentURL:     "document_url",
+		GeneratedAt:     "generated_at",
+		CreatedAt:       "created_at",
+		UpdatedAt:       "updated_at",
+	}
+)
+
+func NewFundingAgreementRepo(impl FundingAgreementRepoImpl) FundingAgreementRepo {
+	return &impl
+}
+
+// Create FundingAgreement and return last inserted id
+func (r *FundingAgreementRepoImpl) Create(ctx context.Context, agreement *FundingAgreement) (int64, error) {
+	txn, err := dbtxn.Use(ctx, r.DB)
+	if err != nil {
+		return -1, err
+	}
+
+	builder := sq.
+		Insert(FundingAgreementTableName).
+		Columns(
+			FundingAgreementTable.AgreementNumber,
+			FundingAgreementTable.LoanFundingID,
+			FundingAgreementTable.LoanID,
+			FundingAgreementTable.LenderID,
+			FundingAgreementTable.TemplateID,
+			FundingAgreementTable.TemplateVersion,
+			FundingAgreementTable.DocumentKey,
+			FundingAgreementTable.DocumentURL,
+			FundingAgreementTable.GeneratedAt,
+			FundingAgreementTable.CreatedAt,
+			FundingAgreementTable.UpdatedAt,
+		).
+		Suffix("RETURNING id").
+		PlaceholderFormat(sq.Dollar).
+		Values(
+			agreement.AgreementNumber,
+			agreement.LoanFundingID,
+			agreement.LoanID,
+			agreement.LenderID,
+			agreement.TemplateID,
+			agreement.TemplateVersion,
+			agreement.DocumentKey,
+			agreement.DocumentURL,
+			agreement.GeneratedAt,
+			time.Now(),
+			time.Now(),
+		)
+
+	scanner := builder.RunWith(txn).QueryRowContext(ctx)
+
+	var id int64
+	if err := scanner.Scan(&id); err != nil {
+		return -1, fmt.Errorf("failed to scan id: %v", err)
+	}
+
+	return id, nil
+}
+
+// GetByLoanFundingID returns the agreements generated for a funding, latest first
+func (r *FundingAgreementRepoImpl) GetByLoanFundingID(ctx context.Context, loanFundingID int64) ([]FundingAgreement, error) {
+	txn, err := dbtxn.Use(ctx, r.DB)
+	if err != nil {
+		return nil, err
+	}
+
+	builder := sq.
+		Select(
+			FundingAgreementTable.ID,
+			FundingAgreementTable.AgreementNumber,
+			FundingAgreementTable.LoanFundingID,
+			FundingAgreementTable.LoanID,
+			FundingAgreementTable.LenderID,
+			FundingAgreementTable.TemplateID,
+			FundingAgreementTable.TemplateVersion,
+			FundingAgreementTable.DocumentKey,
+			FundingAgreementTable.DocumentURL,
+			FundingAgreementTable.GeneratedAt,
+			FundingAgreementTable.CreatedAt,
+			FundingAgreementTable.UpdatedAt,
+		).
+		From(FundingAgreementTableName).
+		Where(sq.Eq{FundingAgreementTable.LoanFundingID: loanFundingID}).
+		OrderBy(FundingAgreementTable.GeneratedAt+" DESC", FundingAgreementTable.ID+" DESC").
+		PlaceholderFormat(sq.Dollar)
+
+	rows, err := builder.RunWith(txn).QueryContext(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute query: %v", err)
+	}
+	defer rows.Close()
+
+	var agreements []FundingAgreement
+	for rows.Next() {
+		var agreement FundingAgreement
+		if err := rows.Scan(
+			&agreement.ID,
+			&agreement.AgreementNumber,
+			&agreement.LoanFundingID,
+			&agreement.LoanID,
+			&agreement.LenderID,
+			&agreement.TemplateID,
+			&agreement.TemplateVersion,
+			&agreement.DocumentKey,
+			&agreement.DocumentURL,
+			&agreement.GeneratedAt,
+			&agreement.CreatedAt,
+			&agreement.UpdatedAt,
+		); err != nil {
+			return nil, fmt.Errorf("failed to scan row: %v", err)
+		}
+		agreements = append(agreements, agreement)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate over rows: %v", err)
+	}
+
+	return agreements, nil
+}
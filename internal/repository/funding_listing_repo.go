@@ -0,0 +1,288 @@
+package repo
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	sq "github.com/Masterminds/squirrel"
+	"github.com/test/loan-service/internal/enum"
+	"github.com/test/loan-service/internal/money"
+	"github.com/typical-go/typical-rest-server/pkg/dbtxn"
+	"go.uber.org/dig"
+	"time"
+)
+
+//go:generate mockgen -source=$GOFILE -destination=$PROJ/internal/generated/mock/mock_$GOPACKAGE/$GOFILE
+
+type (
+	FundingListingRequest struct {
+		LoanID *int64
+		Status enum.FundingListingStatus
+	}
+
+	FundingListing struct {
+		ID              int64                     `db:"id"`               // Listing ID
+		ListingCode     string                    `db:"listing_code"`     // Listing code
+		LoanFundingID   int64                     `db:"loan_funding_id"`  // Funding offered for sale
+		LoanID          int64                     `db:"loan_id"`          // Loan of the funding
+		SellerID        int64                     `db:"seller_id"`        // Lender selling the funding
+		PrincipalAmount money.Amount              `db:"principal_amount"` // Outstanding principal offered for sale
+		Price           money.Amount              `db:"price"`            // Price asked for the principal and its remaining interest
+		Status          enum.FundingListingStatus `db:"status"`           // open, sold, cancelled
+		CreatedAt       time.Time                 `db:"created_at"`       // Date of creation
+		UpdatedAt       time.Time                 `db:"updated_at"`       // Date of last update
+		DeletedAt       *time.Time                `db:"deleted_at"`       // Date of deletion if applicable
+	}
+
+	FundingListingRepo interface {
+		Create(ctx context.Context, listing *FundingListing) (int64, error)
+		Update(ctx context.Context, listing *FundingListing) error
+		GetByID(ctx context.Context, id int64) (*FundingListing, error)
+		GetByIDForUpdate(ctx context.Context, id int64) (*FundingListing, error)
+		GetAll(ctx context.Context, request FundingListingRequest) ([]FundingListing, error)
+		GetOpenByLoanFundingID(ctx context.Context, loanFundingID int64) ([]FundingListing, error)
+	}
+
+	FundingListingRepoImpl struct {
+		dig.In
+		*sql.DB
+	}
+)
+
+var (
+	FundingListingTableName = "funding_listings"
+	FundingListingTable     = struct {
+		ID              string
+		ListingCode     string
+		LoanFundingID   string
+		LoanID          string
+		SellerID        string
+		PrincipalAmount string
+		Price           string
+		Status          string
+		CreatedAt       string
+		UpdatedAt       string
+		DeletedAt       string
+	}{
+		ID:              "id",
+		ListingCode:     "listing_code",
+		LoanFundingID:   "loan_funding_id",
+		LoanID:          "loan_id",
+		SellerID:        "seller_id",
+		PrincipalAmount: "principal_amount",
+		Price:           "price",
+		Status:          "status",
+		CreatedAt:       "created_at",
+		UpdatedAt:       "updated_at",
+		DeletedAt:       "deleted_at",
+	}
+)
+
+func NewFundingListingRepo(impl FundingListingRepoImpl) FundingListingRepo {
+	return &impl
+}
+
+// Create FundingListing and return last inserted id
+func (r *FundingListingRepoImpl) Create(ctx context.Context, listing *FundingListing) (int64, error) {
+	txn, err := dbtxn.Use(ctx, r.DB)
+	if err != nil {
+		return -1, err
+	}
+
+	builder := sq.
+		Insert(FundingListingTableName).
+		Columns(
+			FundingListingTable.ListingCode,
+			FundingListingTable.LoanFundingID,
+			FundingListingTable.LoanID,
+			FundingListingTable.SellerID,
+			FundingListingTable.PrincipalAmount,
+			FundingListingTable.Price,
+			FundingListingTable.Status,
+			FundingListingTable.CreatedAt,
+			FundingListingTable.UpdatedAt,
+			FundingListingTable.DeletedAt,
+		).
+		Suffix("RETURNING id").
+		PlaceholderFormat(sq.Dollar).
+		Values(
+			listing.ListingCode,
+			listing.LoanFundingID,
+			listing.LoanID,
+			listing.SellerID,
+			listing.PrincipalAmount,
+			listing.Price,
+			listing.Status,
+			time.Now(),
+			time.Now(),
+			nil,
+		)
+
+	scanner := builder.RunWith(txn).QueryRowContext(ctx)
+
+	var id int64
+	if err := scanner.Scan(&id); err != nil {
+		return -1, fmt.Errorf("failed to scan id: %v", err)
+	}
+
+	return id, nil
+}
+
+// Update changes the status of a FundingListing, the terms of a listing never change
+func (r *FundingListingRepoImpl) Update(ctx context.Context, listing *FundingListing) error {
+	txn, err := dbtxn.Use(ctx, r.DB)
+	if err != nil {
+		return err
+	}
+
+	builder := sq.Update(FundingListingTableName).
+		Set(FundingListingTable.Status, listing.Status).
+		Set(FundingListingTable.UpdatedAt, time.Now()).
+		Where(sq.Eq{
+			FundingListingTable.ID:        listing.ID,
+			FundingListingTable.DeletedAt: nil,
+		}).
+		PlaceholderFormat(sq.Dollar)
+
+	result, err := builder.RunWith(txn).ExecContext(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to update funding listing: %v", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %v", err)
+	}
+	if rowsAffected == 0 {
+		return fmt.Errorf("no funding listing found with ID: %d", listing.ID)
+	}
+
+	return nil
+}
+
+// GetByID returns the listing, nil when it does not exist
+func (r *FundingListingRepoImpl) GetByID(ctx context.Context, id int64) (*FundingListing, error) {
+	return r.get(ctx, r.selectBuilder().Where(sq.Eq{
+		FundingListingTable.ID:        id,
+		FundingListingTable.DeletedAt: nil,
+	}))
+}
+
+// GetByIDForUpdate returns the listing and locks the row until the transaction ends, nil when it
+// does not exist
+func (r *FundingListingRepoImpl) GetByIDForUpdate(ctx context.Context, id int64) (*FundingListing, error) {
+	return r.get(ctx, r.selectBuilder().Where(sq.Eq{
+		FundingListingTable.ID:        id,
+		FundingListingTable.DeletedAt: nil,
+	}).Suffix("FOR UPDATE"))
+}
+
+// GetAll returns the listings matching the request, most recent first
+func (r *FundingListingRepoImpl) GetAll(ctx context.Context, request FundingListingRequest) ([]FundingListing, error) {
+	builder := r.selectBuilder().
+		Where(sq.Eq{FundingListingTable.DeletedAt: nil}).
+		OrderBy(FundingListingTable.CreatedAt+" DESC", FundingListingTable.ID+" DESC")
+
+	if request.LoanID != nil {
+		builder = builder.Where(sq.Eq{FundingListingTable.LoanID: *request.LoanID})
+	}
+	if request.Status != "" {
+		builder = builder.Where(sq.Eq{FundingListingTable.Status: request.Status})
+	}
+
+	return r.query(ctx, builder)
+}
+
+// GetOpenByLoanFundingID returns the listings of a funding still open for sale
+func (r *FundingListingRepoImpl) GetOpenByLoanFundingID(ctx context.Context, loanFundingID int64) ([]FundingListing, error) {
+	builder := r.selectBuilder().
+		Where(sq.Eq{
+			FundingListingTable.LoanFundingID: loanFundingID,
+			FundingListingTable.Status:        enum.FundingListingOpen,
+			FundingListingTable.DeletedAt:     nil,
+		}).
+		OrderBy(FundingListingTable.ID + " ASC")
+
+	return r.query(ctx, builder)
+}
+
+func (r *FundingListingRepoImpl) get(ctx context.Context, builder sq.SelectBuilder) (*FundingListing, error) {
+	txn, err := dbtxn.Use(ctx, r.DB)
+	if err != nil {
+		return nil, err
+	}
+
+	var listing FundingListing
+	scanner := builder.RunWith(txn).QueryRowContext(ctx)
+	if err := scanner.Scan(r.scanDest(&listing)...); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to scan funding listing: %v", err)
+	}
+
+	return &listing, nil
+}
+
+func (r *FundingListingRepoImpl) query(ctx context.Context, builder sq.SelectBuilder) ([]FundingListing, error) {
+	txn, err := dbtxn.Use(ctx, r.DB)
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := builder.RunWith(txn).QueryContext(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute query: %v", err)
+	}
+	defer rows.Close()
+
+	var listings []FundingListing
+	for rows.Next() {
+		var listing FundingListing
+		if err := rows.Scan(r.scanDest(&listing)...); err != nil {
+			return nil, fmt.Errorf("failed to scan row: %v", err)
+		}
+		listings = append(listings, listing)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate over rows: %v", err)
+	}
+
+	return listings, nil
+}
+
+func (r *FundingListingRepoImpl) selectBuilder() sq.SelectBuilder {
+	return sq.
+		Select(
+			FundingListingTable.ID,
+			FundingListingTable.ListingCode,
+			FundingListingTable.LoanFundingID,
+			FundingListingTable.LoanID,
+			FundingListingTable.SellerID,
+			FundingListingTable.PrincipalAmount,
+			FundingListingTable.Price,
+			FundingListingTable.Status,
+			FundingListingTable.CreatedAt,
+			FundingListingTable.UpdatedAt,
+			FundingListingTable.DeletedAt,
+		).
+		From(FundingListingTableName).
+		PlaceholderFormat(sq.Dollar)
+}
+
+func (r *FundingListingRepoImpl) scanDest(listing *FundingListing) []interface{} {
+	return []interface{}{
+		&listing.ID,
+		&listing.ListingCode,
+		&listing.LoanFundingID,
+		&listing.LoanID,
+		&listing.SellerID,
+		&listing.PrincipalAmount,
+		&listing.Price,
+		&listing.Status,
+		&listing.CreatedAt,
+		&listing.UpdatedAt,
+		&listing.DeletedAt,
+	}
+}
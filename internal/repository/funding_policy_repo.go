@@ -0,0 +1,196 @@
+package repo
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	sq "github.com/Masterminds/squirrel"
+	"github.com/test/loan-service/internal/enum"
+	"github.com/typical-go/typical-rest-server/pkg/dbtxn"
+	"go.uber.org/dig"
+	"time"
+)
+
+//go:generate mockgen -source=$GOFILE -destination=$PROJ/internal/generated/mock/mock_$GOPACKAGE/$GOFILE
+
+type (
+	FundingPolicy struct {
+		ID                     int64         `db:"id"`                       // Funding policy ID
+		LoanType               enum.LoanType `db:"loan_type"`                // Loan type the policy applies to
+		MinFundedPercentage    float64       `db:"min_funded_percentage"`    // Minimum funded percentage at deadline to still disburse
+		RequireBorrowerConsent bool          `db:"require_borrower_consent"` // Partial disbursement needs borrower consent
+		CoolingOffHours        int64         `db:"cooling_off_hours"`        // Hours after investing during which the lender can still cancel
+		CreatedAt              time.Time     `db:"created_at"`               // Date of creation
+		UpdatedAt              time.Time     `db:"updated_at"`               // Date of last update
+		DeletedAt              *time.Time    `db:"deleted_at"`               // Date of deletion if applicable
+	}
+
+	FundingPolicyRepo interface {
+		Update(ctx context.Context, policy *FundingPolicy) error
+		GetByLoanType(ctx context.Context, loanType enum.LoanType) (*FundingPolicy, error)
+		GetAll(ctx context.Context) ([]FundingPolicy, error)
+	}
+
+	FundingPolicyRepoImpl struct {
+		dig.In
+		*sql.DB
+	}
+)
+
+var (
+	FundingPolicyTableName = "funding_policies"
+	FundingPolicyTable     = struct {
+		ID                     string
+		LoanType               string
+		MinFundedPercentage    string
+		RequireBorrowerConsent string
+		CoolingOffHours        string
+		CreatedAt              string
+		UpdatedAt              string
+		DeletedAt              string
+	}{
+		ID:                     "id",
+		LoanType:               "loan_type",
+		MinFundedPercentage:    "min_funded_percentage",
+		RequireBorrowerConsent: "require_borrower_consent",
+		CoolingOffHours:        "cooling_off_hours",
+		CreatedAt:              "created_at",
+		UpdatedAt:              "updated_at",
+		DeletedAt:              "deleted_at",
+	}
+)
+
+func NewFundingPolicyRepo(impl FundingPolicyRepoImpl) FundingPolicyRepo {
+	return &impl
+}
+
+// Update FundingPolicy of a loan type
+func (r *FundingPolicyRepoImpl) Update(ctx context.Context, policy *FundingPolicy) error {
+	txn, err := dbtxn.Use(ctx, r.DB)
+	if err != nil {
+		return err
+	}
+
+	builder := sq.Update(FundingPolicyTableName).
+		Set(FundingPolicyTable.MinFundedPercentage, policy.MinFundedPercentage).
+		Set(FundingPolicyTable.RequireBorrowerConsent, policy.RequireBorrowerConsent).
+		Set(FundingPolicyTable.CoolingOffHours, policy.CoolingOffHours).
+		Set(FundingPolicyTable.UpdatedAt, time.Now()).
+		Where(sq.Eq{FundingPolicyTable.LoanType: policy.LoanType}).
+		PlaceholderFormat(sq.Dollar)
+
+	result, err := builder.RunWith(txn).ExecContext(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to update funding policy: %v", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %v", err)
+	}
+	if rowsAffected == 0 {
+		return fmt.Errorf("no funding policy found for loan type: %s", policy.LoanType)
+	}
+
+	return nil
+}
+
+// GetByLoanType returns the funding policy of a loan type, nil when the loan type has no policy
+func (r *FundingPolicyRepoImpl) GetByLoanType(ctx context.Context, loanType enum.LoanType) (*FundingPolicy, error) {
+	txn, err := dbtxn.Use(ctx, r.DB)
+	if err != nil {
+		return nil, err
+	}
+
+	builder := sq.
+		Select(
+			FundingPolicyTable.ID,
+			FundingPolicyTable.LoanType,
+			FundingPolicyTable.MinFundedPercentage,
+			FundingPolicyTable.RequireBorrowerConsent,
+			FundingPolicyTable.CoolingOffHours,
+			FundingPolicyTable.CreatedAt,
+			FundingPolicyTable.UpdatedAt,
+			FundingPolicyTable.DeletedAt,
+		).
+		From(FundingPolicyTableName).
+		Where(sq.Eq{
+			FundingPolicyTable.LoanType:  loanType,
+			FundingPolicyTable.DeletedAt: nil,
+		}).
+		PlaceholderFormat(sq.Dollar)
+
+	var policy FundingPolicy
+	scanner := builder.RunWith(txn).QueryRowContext(ctx)
+	if err := scanner.Scan(
+		&policy.ID,
+		&policy.LoanType,
+		&policy.MinFundedPercentage,
+		&policy.RequireBorrowerConsent,
+		&policy.CoolingOffHours,
+		&policy.CreatedAt,
+		&policy.UpdatedAt,
+		&policy.DeletedAt,
+	); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to scan funding policy: %v", err)
+	}
+
+	return &policy, nil
+}
+
+// GetAll returns every funding policy ordered by loan type
+func (r *FundingPolicyRepoImpl) GetAll(ctx context.Context) ([]FundingPolicy, error) {
+	txn, err := dbtxn.Use(ctx, r.DB)
+	if err != nil {
+		return nil, err
+	}
+
+	builder := sq.
+		Select(
+			FundingPolicyTable.ID,
+			FundingPolicyTable.LoanType,
+			FundingPolicyTable.MinFundedPercentage,
+			FundingPolicyTable.RequireBorrowerConsent,
+			FundingPolicyTable.CoolingOffHours,
+			FundingPolicyTable.CreatedAt,
+			FundingPolicyTable.UpdatedAt,
+			FundingPolicyTable.DeletedAt,
+		).
+		From(FundingPolicyTableName).
+		Where(sq.Eq{FundingPolicyTable.DeletedAt: nil}).
+		OrderBy(FundingPolicyTable.LoanType + " ASC").
+		PlaceholderFormat(sq.Dollar)
+
+	rows, err := builder.RunWith(txn).QueryContext(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute query: %v", err)
+	}
+	defer rows.Close()
+
+	var policies []FundingPolicy
+	for rows.Next() {
+		var policy FundingPolicy
+		if err := rows.Scan(
+			&policy.ID,
+			&policy.LoanType,
+			&policy.MinFundedPercentage,
+			&policy.RequireBorrowerConsent,
+			&policy.CoolingOffHours,
+			&policy.CreatedAt,
+			&policy.UpdatedAt,
+			&policy.DeletedAt,
+		); err != nil {
+			return nil, fmt.Errorf("failed to scan row: %v", err)
+		}
+		policies = append(policies, policy)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate over rows: %v", err)
+	}
+
+	return policies, nil
+}
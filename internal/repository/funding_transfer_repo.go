@@ -0,0 +1,210 @@
+package repo
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	sq "github.com/Masterminds/squirrel"
+	"github.com/test/loan-service/internal/money"
+	"github.com/typical-go/typical-rest-server/pkg/dbtxn"
+	"go.uber.org/dig"
+	"time"
+)
+
+//go:generate mockgen -source=$GOFILE -destination=$PROJ/internal/generated/mock/mock_$GOPACKAGE/$GOFILE
+
+type (
+	FundingTransfer struct {
+		ID              int64        `db:"id"`                // Transfer ID
+		TransferCode    string       `db:"transfer_code"`     // Transfer code
+		ListingID       int64        `db:"listing_id"`        // Listing that was bought
+		LoanID          int64        `db:"loan_id"`           // Loan of the fundings
+		SellerFundingID int64        `db:"seller_funding_id"` // Funding the principal was sold from
+		BuyerFundingID  int64        `db:"buyer_funding_id"`  // Funding created for the buyer
+		SellerID        int64        `db:"seller_id"`         // Lender selling the principal
+		BuyerID         int64        `db:"buyer_id"`          // Lender buying the principal
+		PrincipalAmount money.Amount `db:"principal_amount"`  // Outstanding principal transferred
+		InterestAmount  money.Amount `db:"interest_amount"`   // Remaining gross interest transferred with the principal
+		Price           money.Amount `db:"price"`             // Price paid by the buyer to the seller
+		Premium         money.Amount `db:"premium"`           // Price minus principal, negative for a discount
+		TransferredAt   time.Time    `db:"transferred_at"`    // Date ownership changed
+		CreatedAt       time.Time    `db:"created_at"`        // Date of creation
+		UpdatedAt       time.Time    `db:"updated_at"`        // Date of last update
+	}
+
+	FundingTransferRepo interface {
+		Create(ctx context.Context, transfer *FundingTransfer) (int64, error)
+		GetByLenderID(ctx context.Context, lenderID int64) ([]FundingTransfer, error)
+	}
+
+	FundingTransferRepoImpl struct {
+		dig.In
+		*sql.DB
+	}
+)
+
+var (
+	FundingTransferTableName = "funding_transfers"
+	FundingTransferTable     = struct {
+		ID              string
+		TransferCode    string
+		ListingID       string
+		LoanID          string
+		SellerFundingID string
+		BuyerFundingID  string
+		SellerID        string
+		BuyerID         string
+		PrincipalAmount string
+		InterestAmount  string
+		Price           string
+		Premium         string
+		TransferredAt   string
+		CreatedAt       string
+		UpdatedAt       string
+	}{
+		ID:              "id",
+		TransferCode:    "transfer_code",
+		ListingID:       "listing_id",
+		LoanID:          "loan_id",
+		SellerFundingID: "seller_funding_id",
+		BuyerFundingID:  "buyer_funding_id",
+		SellerID:        "seller_id",
+		BuyerID:         "buyer_id",
+		PrincipalAmount: "principal_amount",
+		InterestAmount:  "interest_amount",
+		Price:           "price",
+		Premium:         "premium",
+		TransferredAt:   "transferred_at",
+		CreatedAt:       "created_at",
+		UpdatedAt:       "updated_at",
+	}
+)
+
+func NewFundingTransferRepo(impl FundingTransferRepoImpl) FundingTransferRepo {
+	return &impl
+}
+
+// Create FundingTransfer and return last inserted id
+func (r *FundingTransferRepoImpl) Create(ctx context.Context, transfer *FundingTransfer) (int64, error) {
+	txn, err := dbtxn.Use(ctx, r.DB)
+	if err != nil {
+		return -1, err
+	}
+
+	builder := sq.
+		Insert(FundingTransferTableName).
+		Columns(
+			FundingTransferTable.TransferCode,
+			FundingTransferTable.ListingID,
+			FundingTransferTable.LoanID,
+			FundingTransferTable.SellerFundingID,
+			FundingTransferTable.BuyerFundingID,
+			FundingTransferTable.SellerID,
+			FundingTransferTable.BuyerID,
+			FundingTransferTable.PrincipalAmount,
+			FundingTransferTable.InterestAmount,
+			FundingTransferTable.Price,
+			FundingTransferTable.Premium,
+			FundingTransferTable.TransferredAt,
+			FundingTransferTable.CreatedAt,
+			FundingTransferTable.UpdatedAt,
+		).
+		Suffix("RETURNING id").
+		PlaceholderFormat(sq.Dollar).
+		Values(
+			transfer.TransferCode,
+			transfer.ListingID,
+			transfer.LoanID,
+			transfer.SellerFundingID,
+			transfer.BuyerFundingID,
+			transfer.SellerID,
+			transfer.BuyerID,
+			transfer.PrincipalAmount,
+			transfer.InterestAmount,
+			transfer.Price,
+			transfer.Premium,
+			transfer.TransferredAt,
+			time.Now(),
+			time.Now(),
+		)
+
+	scanner := builder.RunWith(txn).QueryRowContext(ctx)
+
+	var id int64
+	if err := scanner.Scan(&id); err != nil {
+		return -1, fmt.Errorf("failed to scan id: %v", err)
+	}
+
+	return id, nil
+}
+
+// GetByLenderID returns the transfers the lender sold or bought, most recent first
+func (r *FundingTransferRepoImpl) GetByLenderID(ctx context.Context, lenderID int64) ([]FundingTransfer, error) {
+	txn, err := dbtxn.Use(ctx, r.DB)
+	if err != nil {
+		return nil, err
+	}
+
+	builder := sq.
+		Select(
+			FundingTransferTable.ID,
+			FundingTransferTable.TransferCode,
+			FundingTransferTable.ListingID,
+			FundingTransferTable.LoanID,
+			FundingTransferTable.SellerFundingID,
+			FundingTransferTable.BuyerFundingID,
+			FundingTransferTable.SellerID,
+			FundingTransferTable.BuyerID,
+			FundingTransferTable.PrincipalAmount,
+			FundingTransferTable.InterestAmount,
+			FundingTransferTable.Price,
+			FundingTransferTable.Premium,
+			FundingTransferTable.TransferredAt,
+			FundingTransferTable.CreatedAt,
+			FundingTransferTable.UpdatedAt,
+		).
+		From(FundingTransferTableName).
+		Where(sq.Or{
+			sq.Eq{FundingTransferTable.SellerID: lenderID},
+			sq.Eq{FundingTransferTable.BuyerID: lenderID},
+		}).
+		OrderBy(FundingTransferTable.TransferredAt+" DESC", FundingTransferTable.ID+" DESC").
+		PlaceholderFormat(sq.Dollar)
+
+	rows, err := builder.RunWith(txn).QueryContext(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute query: %v", err)
+	}
+	defer rows.Close()
+
+	var transfers []FundingTransfer
+	for rows.Next() {
+		var transfer FundingTransfer
+		if err := rows.Scan(
+			&transfer.ID,
+			&transfer.TransferCode,
+			&transfer.ListingID,
+			&transfer.LoanID,
+			&transfer.SellerFundingID,
+			&transfer.BuyerFundingID,
+			&transfer.SellerID,
+			&transfer.BuyerID,
+			&transfer.PrincipalAmount,
+			&transfer.InterestAmount,
+			&transfer.Price,
+			&transfer.Premium,
+			&transfer.TransferredAt,
+			&transfer.CreatedAt,
+			&transfer.UpdatedAt,
+		); err != nil {
+			return nil, fmt.Errorf("failed to scan row: %v", err)
+		}
+		transfers = append(transfers, transfer)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate over rows: %v", err)
+	}
+
+	return transfers, nil
+}
@@ -0,0 +1,141 @@
+package repo
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	sq "github.com/Masterminds/squirrel"
+	"github.com/test/loan-service/internal/money"
+	"github.com/typical-go/typical-rest-server/pkg/dbtxn"
+	"go.uber.org/dig"
+	"time"
+)
+
+//go:generate mockgen -source=$GOFILE -destination=$PROJ/internal/generated/mock/mock_$GOPACKAGE/$GOFILE
+
+type (
+	InvestmentLimit struct {
+		ID                     int64        `db:"id"`                        // Investment limit ID
+		MinTicketAmount        money.Amount `db:"min_ticket_amount"`         // Minimum amount of a single funding
+		TicketIncrement        money.Amount `db:"ticket_increment"`          // Funding amounts must be a multiple of this step, zero for any amount
+		MaxLoanSharePercentage float64      `db:"max_loan_share_percentage"` // Maximum percentage of the requested amount of a loan a single lender may fund
+		MaxLenderExposure      money.Amount `db:"max_lender_exposure"`       // Maximum outstanding principal of a lender over all loans, zero for no limit
+		CreatedAt              time.Time    `db:"created_at"`                // Date of creation
+		UpdatedAt              time.Time    `db:"updated_at"`                // Date of last update
+		DeletedAt              *time.Time   `db:"deleted_at"`                // Date of deletion if applicable
+	}
+
+	InvestmentLimitRepo interface {
+		Update(ctx context.Context, limit *InvestmentLimit) error
+		Get(ctx context.Context) (*InvestmentLimit, error)
+	}
+
+	InvestmentLimitRepoImpl struct {
+		dig.In
+		*sql.DB
+	}
+)
+
+var (
+	InvestmentLimitTableName = "investment_limits"
+	InvestmentLimitTable     = struct {
+		ID                     string
+		MinTicketAmount        string
+		TicketIncrement        string
+		MaxLoanSharePercentage string
+		MaxLenderExposure      string
+		CreatedAt              string
+		UpdatedAt              string
+		DeletedAt              string
+	}{
+		ID:                     "id",
+		MinTicketAmount:        "min_ticket_amount",
+		TicketIncrement:        "ticket_increment",
+		MaxLoanSharePercentage: "max_loan_share_percentage",
+		MaxLenderExposure:      "max_lender_exposure",
+		CreatedAt:              "created_at",
+		UpdatedAt:              "updated_at",
+		DeletedAt:              "deleted_at",
+	}
+)
+
+func NewInvestmentLimitRepo(impl InvestmentLimitRepoImpl) InvestmentLimitRepo {
+	return &impl
+}
+
+// Update the platform InvestmentLimit
+func (r *InvestmentLimitRepoImpl) Update(ctx context.Context, limit *InvestmentLimit) error {
+	txn, err := dbtxn.Use(ctx, r.DB)
+	if err != nil {
+		return err
+	}
+
+	builder := sq.Update(InvestmentLimitTableName).
+		Set(InvestmentLimitTable.MinTicketAmount, limit.MinTicketAmount).
+		Set(InvestmentLimitTable.TicketIncrement, limit.TicketIncrement).
+		Set(InvestmentLimitTable.MaxLoanSharePercentage, limit.MaxLoanSharePercentage).
+		Set(InvestmentLimitTable.MaxLenderExposure, limit.MaxLenderExposure).
+		Set(InvestmentLimitTable.UpdatedAt, time.Now()).
+		Where(sq.Eq{InvestmentLimitTable.ID: limit.ID}).
+		PlaceholderFormat(sq.Dollar)
+
+	result, err := builder.RunWith(txn).ExecContext(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to update investment limit: %v", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %v", err)
+	}
+	if rowsAffected == 0 {
+		return fmt.Errorf("no investment limit found with ID: %d", limit.ID)
+	}
+
+	return nil
+}
+
+// Get returns the investment limit of the platform, nil when no limit is configured
+func (r *InvestmentLimitRepoImpl) Get(ctx context.Context) (*InvestmentLimit, error) {
+	txn, err := dbtxn.Use(ctx, r.DB)
+	if err != nil {
+		return nil, err
+	}
+
+	builder := sq.
+		Select(
+			InvestmentLimitTable.ID,
+			InvestmentLimitTable.MinTicketAmount,
+			InvestmentLimitTable.TicketIncrement,
+			InvestmentLimitTable.MaxLoanSharePercentage,
+			InvestmentLimitTable.MaxLenderExposure,
+			InvestmentLimitTable.CreatedAt,
+			InvestmentLimitTable.UpdatedAt,
+			InvestmentLimitTable.DeletedAt,
+		).
+		From(InvestmentLimitTableName).
+		Where(sq.Eq{InvestmentLimitTable.DeletedAt: nil}).
+		OrderBy(InvestmentLimitTable.ID + " ASC").
+		Limit(1).
+		PlaceholderFormat(sq.Dollar)
+
+	var limit InvestmentLimit
+	scanner := builder.RunWith(txn).QueryRowContext(ctx)
+	if err := scanner.Scan(
+		&limit.ID,
+		&limit.MinTicketAmount,
+		&limit.TicketIncrement,
+		&limit.MaxLoanSharePercentage,
+		&limit.MaxLenderExposure,
+		&limit.CreatedAt,
+		&limit.UpdatedAt,
+		&limit.DeletedAt,
+	); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to scan investment limit: %v", err)
+	}
+
+	return &limit, nil
+}
@@ -0,0 +1,202 @@
+package repo
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	sq "github.com/Masterminds/squirrel"
+	"github.com/test/loan-service/internal/enum"
+	"github.com/typical-go/typical-rest-server/pkg/dbtxn"
+	"go.uber.org/dig"
+	"time"
+)
+
+//go:generate mockgen -source=$GOFILE -destination=$PROJ/internal/generated/mock/mock_$GOPACKAGE/$GOFILE
+
+type (
+	// Kyc is the KYC state kept on every borrower and lender
+	Kyc struct {
+		KycStatus     enum.KycStatus `db:"kyc_status"`      // KYC status
+		KycVerifiedAt *time.Time     `db:"kyc_verified_at"` // Date the KYC was verified (if applicable)
+	}
+
+	KycHistory struct {
+		ID         int64          `db:"id"`          // KYC history ID
+		OwnerID    int64          `db:"owner_id"`    // Borrower or lender ID, stored as borrower_id or lender_id
+		FromStatus enum.KycStatus `db:"from_status"` // KYC status before the change
+		ToStatus   enum.KycStatus `db:"to_status"`   // KYC status after the change
+		StaffID    *int64         `db:"staff_id"`    // Staff who reviewed the KYC, nil for changes made by the owner
+		Note       *string        `db:"note"`        // Reason of the change
+		CreatedAt  time.Time      `db:"created_at"`  // Date of creation
+		UpdatedAt  time.Time      `db:"updated_at"`  // Date of last update
+		DeletedAt  *time.Time     `db:"deleted_at"`  // Date of deletion if applicable
+	}
+
+	// KycHistoryRepo keeps the KYC changes of borrowers and lenders, each owner type in its own table
+	KycHistoryRepo interface {
+		Create(ctx context.Context, ownerType enum.DocumentEntity, history *KycHistory) (int64, error)
+		GetByOwnerID(ctx context.Context, ownerType enum.DocumentEntity, ownerID int64) ([]KycHistory, error)
+	}
+
+	KycHistoryRepoImpl struct {
+		dig.In
+		*sql.DB
+	}
+)
+
+var (
+	// KycHistoryTableNames maps every owner type to its KYC history table
+	KycHistoryTableNames = map[enum.DocumentEntity]string{
+		enum.DocumentEntityBorrower: "borrower_kyc_histories",
+		enum.DocumentEntityLender:   "lender_kyc_histories",
+	}
+	// KycHistoryOwnerColumns maps every owner type to the column of OwnerID
+	KycHistoryOwnerColumns = map[enum.DocumentEntity]string{
+		enum.DocumentEntityBorrower: "borrower_id",
+		enum.DocumentEntityLender:   "lender_id",
+	}
+	KycHistoryTable = struct {
+		ID         string
+		FromStatus string
+		ToStatus   string
+		StaffID    string
+		Note       string
+		CreatedAt  string
+		UpdatedAt  string
+		DeletedAt  string
+	}{
+		ID:         "id",
+		FromStatus: "from_status",
+		ToStatus:   "to_status",
+		StaffID:    "staff_id",
+		Note:       "note",
+		CreatedAt:  "created_at",
+		UpdatedAt:  "updated_at",
+		DeletedAt:  "deleted_at",
+	}
+)
+
+func NewKycHistoryRepo(impl KycHistoryRepoImpl) KycHistoryRepo {
+	return &impl
+}
+
+// Create KycHistory for the owner type and return last inserted id
+func (r *KycHistoryRepoImpl) Create(ctx context.Context, ownerType enum.DocumentEntity, history *KycHistory) (int64, error) {
+	tableName, ownerColumn, err := kycHistoryTableOf(ownerType)
+	if err != nil {
+		return -1, err
+	}
+
+	txn, err := dbtxn.Use(ctx, r.DB)
+	if err != nil {
+		return -1, err
+	}
+
+	builder := sq.
+		Insert(tableName).
+		Columns(
+			ownerColumn,
+			KycHistoryTable.FromStatus,
+			KycHistoryTable.ToStatus,
+			KycHistoryTable.StaffID,
+			KycHistoryTable.Note,
+			KycHistoryTable.CreatedAt,
+			KycHistoryTable.UpdatedAt,
+			KycHistoryTable.DeletedAt,
+		).
+		Suffix("RETURNING id").
+		PlaceholderFormat(sq.Dollar).
+		Values(
+			history.OwnerID,
+			history.FromStatus,
+			history.ToStatus,
+			history.StaffID,
+			history.Note,
+			time.Now(),
+			time.Now(),
+			nil,
+		)
+
+	scanner := builder.RunWith(txn).QueryRowContext(ctx)
+
+	var id int64
+	if err := scanner.Scan(&id); err != nil {
+		return -1, fmt.Errorf("failed to scan id: %v", err)
+	}
+
+	return id, nil
+}
+
+// GetByOwnerID returns the KYC changes of a borrower or lender, oldest first
+func (r *KycHistoryRepoImpl) GetByOwnerID(ctx context.Context, ownerType enum.DocumentEntity, ownerID int64) ([]KycHistory, error) {
+	tableName, ownerColumn, err := kycHistoryTableOf(ownerType)
+	if err != nil {
+		return nil, err
+	}
+
+	txn, err := dbtxn.Use(ctx, r.DB)
+	if err != nil {
+		return nil, err
+	}
+
+	builder := sq.
+		Select(
+			KycHistoryTable.ID,
+			ownerColumn,
+			KycHistoryTable.FromStatus,
+			KycHistoryTable.ToStatus,
+			KycHistoryTable.StaffID,
+			KycHistoryTable.Note,
+			KycHistoryTable.CreatedAt,
+			KycHistoryTable.UpdatedAt,
+			KycHistoryTable.DeletedAt,
+		).
+		From(tableName).
+		Where(sq.Eq{
+			ownerColumn:               ownerID,
+			KycHistoryTable.DeletedAt: nil,
+		}).
+		OrderBy(KycHistoryTable.ID + " ASC").
+		PlaceholderFormat(sq.Dollar)
+
+	rows, err := builder.RunWith(txn).QueryContext(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute query: %v", err)
+	}
+	defer rows.Close()
+
+	var histories []KycHistory
+	for rows.Next() {
+		var history KycHistory
+		if err := rows.Scan(
+			&history.ID,
+			&history.OwnerID,
+			&history.FromStatus,
+			&history.ToStatus,
+			&history.StaffID,
+			&history.Note,
+			&history.CreatedAt,
+			&history.UpdatedAt,
+			&history.DeletedAt,
+		); err != nil {
+			return nil, fmt.Errorf("failed to scan row: %v", err)
+		}
+		histories = append(histories, history)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate over rows: %v", err)
+	}
+
+	return histories, nil
+}
+
+// kycHistoryTableOf returns the table and owner column of the KYC history of the owner type
+func kycHistoryTableOf(ownerType enum.DocumentEntity) (string, string, error) {
+	tableName, ok := KycHistoryTableNames[ownerType]
+	if !ok {
+		return "", "", fmt.Errorf("no KYC history for owner type %q", ownerType)
+	}
+
+	return tableName, KycHistoryOwnerColumns[ownerType], nil
+}
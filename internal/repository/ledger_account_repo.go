@@ -0,0 +1,220 @@
+package repo
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	sq "github.com/Masterminds/squirrel"
+	"github.com/test/loan-service/internal/enum"
+	"github.com/typical-go/typical-rest-server/pkg/dbtxn"
+	"go.uber.org/dig"
+	"time"
+)
+
+//go:generate mockgen -source=$GOFILE -destination=$PROJ/internal/generated/mock/mock_$GOPACKAGE/$GOFILE
+
+type (
+	LedgerAccountRequest struct {
+		AccountType enum.LedgerAccountType
+		OwnerID     *int64
+	}
+
+	LedgerAccount struct {
+		ID            int64                  `db:"id"`             // Ledger account ID
+		AccountCode   string                 `db:"account_code"`   // Unique account code
+		AccountType   enum.LedgerAccountType `db:"account_type"`   // Account type
+		OwnerID       *int64                 `db:"owner_id"`       // Lender ID or loan ID, nil for platform accounts
+		NormalBalance enum.LedgerDirection   `db:"normal_balance"` // Side that increases the balance
+		CreatedAt     time.Time              `db:"created_at"`     // Date of creation
+		UpdatedAt     time.Time              `db:"updated_at"`     // Date of last update
+		DeletedAt     *time.Time             `db:"deleted_at"`     // Date of deletion if applicable
+	}
+
+	LedgerAccountRepo interface {
+		CreateIfNotExists(ctx context.Context, account *LedgerAccount) (int64, error)
+		GetByID(ctx context.Context, id int64) (*LedgerAccount, error)
+		GetAll(ctx context.Context, request LedgerAccountRequest) ([]LedgerAccount, error)
+	}
+
+	LedgerAccountRepoImpl struct {
+		dig.In
+		*sql.DB
+	}
+)
+
+var (
+	LedgerAccountTableName = "ledger_accounts"
+	LedgerAccountTable     = struct {
+		ID            string
+		AccountCode   string
+		AccountType   string
+		OwnerID       string
+		NormalBalance string
+		CreatedAt     string
+		UpdatedAt     string
+		DeletedAt     string
+	}{
+		ID:            "id",
+		AccountCode:   "account_code",
+		AccountType:   "account_type",
+		OwnerID:       "owner_id",
+		NormalBalance: "normal_balance",
+		CreatedAt:     "created_at",
+		UpdatedAt:     "updated_at",
+		DeletedAt:     "deleted_at",
+	}
+)
+
+func NewLedgerAccountRepo(impl LedgerAccountRepoImpl) LedgerAccountRepo {
+	return &impl
+}
+
+// CreateIfNotExists creates the LedgerAccount and returns its id, or returns the id of the account
+// already registered with the same account code
+func (r *LedgerAccountRepoImpl) CreateIfNotExists(ctx context.Context, account *LedgerAccount) (int64, error) {
+	txn, err := dbtxn.Use(ctx, r.DB)
+	if err != nil {
+		return -1, err
+	}
+
+	builder := sq.
+		Insert(LedgerAccountTableName).
+		Columns(
+			LedgerAccountTable.AccountCode,
+			LedgerAccountTable.AccountType,
+			LedgerAccountTable.OwnerID,
+			LedgerAccountTable.NormalBalance,
+			LedgerAccountTable.CreatedAt,
+			LedgerAccountTable.UpdatedAt,
+			LedgerAccountTable.DeletedAt,
+		).
+		// the no-op update makes RETURNING also yield the id of an existing account
+		Suffix("ON CONFLICT ("+LedgerAccountTable.AccountCode+") DO UPDATE SET "+
+			LedgerAccountTable.AccountCode+" = EXCLUDED."+LedgerAccountTable.AccountCode+" RETURNING id").
+		PlaceholderFormat(sq.Dollar).
+		Values(
+			account.AccountCode,
+			account.AccountType,
+			account.OwnerID,
+			account.NormalBalance,
+			time.Now(),
+			time.Now(),
+			nil,
+		)
+
+	scanner := builder.RunWith(txn).QueryRowContext(ctx)
+
+	var id int64
+	if err := scanner.Scan(&id); err != nil {
+		return -1, fmt.Errorf("failed to scan id: %v", err)
+	}
+
+	return id, nil
+}
+
+// GetByID returns the ledger account, nil when it does not exist
+func (r *LedgerAccountRepoImpl) GetByID(ctx context.Context, id int64) (*LedgerAccount, error) {
+	txn, err := dbtxn.Use(ctx, r.DB)
+	if err != nil {
+		return nil, err
+	}
+
+	builder := sq.
+		Select(
+			LedgerAccountTable.ID,
+			LedgerAccountTable.AccountCode,
+			LedgerAccountTable.AccountType,
+			LedgerAccountTable.OwnerID,
+			LedgerAccountTable.NormalBalance,
+			LedgerAccountTable.CreatedAt,
+			LedgerAccountTable.UpdatedAt,
+			LedgerAccountTable.DeletedAt,
+		).
+		From(LedgerAccountTableName).
+		Where(sq.Eq{
+			LedgerAccountTable.ID:        id,
+			LedgerAccountTable.DeletedAt: nil,
+		}).
+		PlaceholderFormat(sq.Dollar)
+
+	var account LedgerAccount
+	scanner := builder.RunWith(txn).QueryRowContext(ctx)
+	if err := scanner.Scan(
+		&account.ID,
+		&account.AccountCode,
+		&account.AccountType,
+		&account.OwnerID,
+		&account.NormalBalance,
+		&account.CreatedAt,
+		&account.UpdatedAt,
+		&account.DeletedAt,
+	); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to scan ledger account: %v", err)
+	}
+
+	return &account, nil
+}
+
+// GetAll returns the ledger accounts matching the request ordered by ID
+func (r *LedgerAccountRepoImpl) GetAll(ctx context.Context, request LedgerAccountRequest) ([]LedgerAccount, error) {
+	txn, err := dbtxn.Use(ctx, r.DB)
+	if err != nil {
+		return nil, err
+	}
+
+	builder := sq.
+		Select(
+			LedgerAccountTable.ID,
+			LedgerAccountTable.AccountCode,
+			LedgerAccountTable.AccountType,
+			LedgerAccountTable.OwnerID,
+			LedgerAccountTable.NormalBalance,
+			LedgerAccountTable.CreatedAt,
+			LedgerAccountTable.UpdatedAt,
+			LedgerAccountTable.DeletedAt,
+		).
+		From(LedgerAccountTableName).
+		Where(sq.Eq{LedgerAccountTable.DeletedAt: nil}).
+		OrderBy(LedgerAccountTable.ID + " ASC").
+		PlaceholderFormat(sq.Dollar)
+
+	if request.AccountType != "" {
+		builder = builder.Where(sq.Eq{LedgerAccountTable.AccountType: request.AccountType})
+	}
+	if request.OwnerID != nil {
+		builder = builder.Where(sq.Eq{LedgerAccountTable.OwnerID: *request.OwnerID})
+	}
+
+	rows, err := builder.RunWith(txn).QueryContext(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute query: %v", err)
+	}
+	defer rows.Close()
+
+	var accounts []LedgerAccount
+	for rows.Next() {
+		var account LedgerAccount
+		if err := rows.Scan(
+			&account.ID,
+			&account.AccountCode,
+			&account.AccountType,
+			&account.OwnerID,
+			&account.NormalBalance,
+			&account.CreatedAt,
+			&account.UpdatedAt,
+			&account.DeletedAt,
+		); err != nil {
+			return nil, fmt.Errorf("failed to scan row: %v", err)
+		}
+		accounts = append(accounts, account)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate over rows: %v", err)
+	}
+
+	return accounts, nil
+}
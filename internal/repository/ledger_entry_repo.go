@@ -0,0 +1,137 @@
+package repo
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	sq "github.com/Masterminds/squirrel"
+	"github.com/test/loan-service/internal/enum"
+	"github.com/typical-go/typical-rest-server/pkg/dbtxn"
+	"go.uber.org/dig"
+	"time"
+)
+
+//go:generate mockgen -source=$GOFILE -destination=$PROJ/internal/generated/mock/mock_$GOPACKAGE/$GOFILE
+
+type (
+	LedgerEntry struct {
+		ID          int64                `db:"id"`           // Journal entry ID
+		EntryCode   string               `db:"entry_code"`   // Entry code
+		EntryType   enum.LedgerEntryType `db:"entry_type"`   // Business event that caused the entry
+		ReferenceID int64                `db:"reference_id"` // ID of the funding, loan or repayment
+		Description string               `db:"description"`  // Human readable description
+		CreatedAt   time.Time            `db:"created_at"`   // Date the entry was posted
+	}
+
+	// LedgerEntryRepo has no update or delete, the journal is append only
+	LedgerEntryRepo interface {
+		Create(ctx context.Context, entry *LedgerEntry) (int64, error)
+		GetByReference(ctx context.Context, entryType enum.LedgerEntryType, referenceID int64) (*LedgerEntry, error)
+	}
+
+	LedgerEntryRepoImpl struct {
+		dig.In
+		*sql.DB
+	}
+)
+
+var (
+	LedgerEntryTableName = "ledger_entries"
+	LedgerEntryTable     = struct {
+		ID          string
+		EntryCode   string
+		EntryType   string
+		ReferenceID string
+		Description string
+		CreatedAt   string
+	}{
+		ID:          "id",
+		EntryCode:   "entry_code",
+		EntryType:   "entry_type",
+		ReferenceID: "reference_id",
+		Description: "description",
+		CreatedAt:   "created_at",
+	}
+)
+
+func NewLedgerEntryRepo(impl LedgerEntryRepoImpl) LedgerEntryRepo {
+	return &impl
+}
+
+// Create LedgerEntry and return last inserted id
+func (r *LedgerEntryRepoImpl) Create(ctx context.Context, entry *LedgerEntry) (int64, error) {
+	txn, err := dbtxn.Use(ctx, r.DB)
+	if err != nil {
+		return -1, err
+	}
+
+	builder := sq.
+		Insert(LedgerEntryTableName).
+		Columns(
+			LedgerEntryTable.EntryCode,
+			LedgerEntryTable.EntryType,
+			LedgerEntryTable.ReferenceID,
+			LedgerEntryTable.Description,
+			LedgerEntryTable.CreatedAt,
+		).
+		Suffix("RETURNING id").
+		PlaceholderFormat(sq.Dollar).
+		Values(
+			entry.EntryCode,
+			entry.EntryType,
+			entry.ReferenceID,
+			entry.Description,
+			entry.CreatedAt,
+		)
+
+	scanner := builder.RunWith(txn).QueryRowContext(ctx)
+
+	var id int64
+	if err := scanner.Scan(&id); err != nil {
+		return -1, fmt.Errorf("failed to scan id: %v", err)
+	}
+
+	return id, nil
+}
+
+// GetByReference returns the entry posted for a business event, nil when it has not been posted yet
+func (r *LedgerEntryRepoImpl) GetByReference(ctx context.Context, entryType enum.LedgerEntryType, referenceID int64) (*LedgerEntry, error) {
+	txn, err := dbtxn.Use(ctx, r.DB)
+	if err != nil {
+		return nil, err
+	}
+
+	builder := sq.
+		Select(
+			LedgerEntryTable.ID,
+			LedgerEntryTable.EntryCode,
+			LedgerEntryTable.EntryType,
+			LedgerEntryTable.ReferenceID,
+			LedgerEntryTable.Description,
+			LedgerEntryTable.CreatedAt,
+		).
+		From(LedgerEntryTableName).
+		Where(sq.Eq{
+			LedgerEntryTable.EntryType:   entryType,
+			LedgerEntryTable.ReferenceID: referenceID,
+		}).
+		PlaceholderFormat(sq.Dollar)
+
+	var entry LedgerEntry
+	scanner := builder.RunWith(txn).QueryRowContext(ctx)
+	if err := scanner.Scan(
+		&entry.ID,
+		&entry.EntryCode,
+		&entry.EntryType,
+		&entry.ReferenceID,
+		&entry.Description,
+		&entry.CreatedAt,
+	); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to scan ledger entry: %v", err)
+	}
+
+	return &entry, nil
+}
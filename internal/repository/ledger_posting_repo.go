@@ -0,0 +1,219 @@
+package repo
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	sq "github.com/Masterminds/squirrel"
+	"github.com/test/loan-service/internal/enum"
+	"github.com/test/loan-service/internal/money"
+	"github.com/typical-go/typical-rest-server/pkg/dbtxn"
+	"go.uber.org/dig"
+	"time"
+)
+
+//go:generate mockgen -source=$GOFILE -destination=$PROJ/internal/generated/mock/mock_$GOPACKAGE/$GOFILE
+
+type (
+	LedgerStatementRequest struct {
+		AccountID     int64
+		NormalBalance enum.LedgerDirection
+		Offset        uint64
+		Size          uint64
+	}
+
+	LedgerPosting struct {
+		ID        int64                `db:"id"`         // Posting ID
+		EntryID   int64                `db:"entry_id"`   // Journal entry ID
+		AccountID int64                `db:"account_id"` // Account debited or credited
+		Direction enum.LedgerDirection `db:"direction"`  // debit or credit
+		Amount    money.Amount         `db:"amount"`     // Posted amount, always positive
+		CreatedAt time.Time            `db:"created_at"` // Date the posting was written
+	}
+
+	// LedgerStatementLine is a posting of an account with its journal entry and the account balance
+	// right after the posting
+	LedgerStatementLine struct {
+		LedgerPosting
+		EntryCode   string
+		EntryType   enum.LedgerEntryType
+		ReferenceID int64
+		Description string
+		Balance     money.Amount
+	}
+
+	// LedgerPostingRepo has no update or delete, the journal is append only
+	LedgerPostingRepo interface {
+		Create(ctx context.Context, posting *LedgerPosting) (int64, error)
+		GetTotalsByAccountID(ctx context.Context, accountID int64) (debit money.Amount, credit money.Amount, err error)
+		GetStatementPage(ctx context.Context, request LedgerStatementRequest) ([]LedgerStatementLine, int64, error)
+	}
+
+	LedgerPostingRepoImpl struct {
+		dig.In
+		*sql.DB
+	}
+)
+
+var (
+	LedgerPostingTableName = "ledger_postings"
+	LedgerPostingTable     = struct {
+		ID        string
+		EntryID   string
+		AccountID string
+		Direction string
+		Amount    string
+		CreatedAt string
+	}{
+		ID:        "id",
+		EntryID:   "entry_id",
+		AccountID: "account_id",
+		Direction: "direction",
+		Amount:    "amount",
+		CreatedAt: "created_at",
+	}
+)
+
+func NewLedgerPostingRepo(impl LedgerPostingRepoImpl) LedgerPostingRepo {
+	return &impl
+}
+
+// Create LedgerPosting and return last inserted id
+func (r *LedgerPostingRepoImpl) Create(ctx context.Context, posting *LedgerPosting) (int64, error) {
+	txn, err := dbtxn.Use(ctx, r.DB)
+	if err != nil {
+		return -1, err
+	}
+
+	builder := sq.
+		Insert(LedgerPostingTableName).
+		Columns(
+			LedgerPostingTable.EntryID,
+			LedgerPostingTable.AccountID,
+			LedgerPostingTable.Direction,
+			LedgerPostingTable.Amount,
+			LedgerPostingTable.CreatedAt,
+		).
+		Suffix("RETURNING id").
+		PlaceholderFormat(sq.Dollar).
+		Values(
+			posting.EntryID,
+			posting.AccountID,
+			posting.Direction,
+			posting.Amount,
+			posting.CreatedAt,
+		)
+
+	scanner := builder.RunWith(txn).QueryRowContext(ctx)
+
+	var id int64
+	if err := scanner.Scan(&id); err != nil {
+		return -1, fmt.Errorf("failed to scan id: %v", err)
+	}
+
+	return id, nil
+}
+
+// GetTotalsByAccountID returns the sum of every debit and every credit posted to an account
+func (r *LedgerPostingRepoImpl) GetTotalsByAccountID(ctx context.Context, accountID int64) (money.Amount, money.Amount, error) {
+	txn, err := dbtxn.Use(ctx, r.DB)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	builder := sq.
+		Select().
+		Column("COALESCE(SUM(CASE WHEN "+LedgerPostingTable.Direction+" = ? THEN "+LedgerPostingTable.Amount+" END), 0)", enum.LedgerDebit).
+		Column("COALESCE(SUM(CASE WHEN "+LedgerPostingTable.Direction+" = ? THEN "+LedgerPostingTable.Amount+" END), 0)", enum.LedgerCredit).
+		From(LedgerPostingTableName).
+		Where(sq.Eq{LedgerPostingTable.AccountID: accountID}).
+		PlaceholderFormat(sq.Dollar)
+
+	var debit, credit money.Amount
+	scanner := builder.RunWith(txn).QueryRowContext(ctx)
+	if err := scanner.Scan(&debit, &credit); err != nil {
+		return 0, 0, fmt.Errorf("failed to scan account totals: %v", err)
+	}
+
+	return debit, credit, nil
+}
+
+// GetStatementPage returns the postings of an account in posting order with the running balance
+// of the account, together with the total number of postings
+func (r *LedgerPostingRepoImpl) GetStatementPage(ctx context.Context, request LedgerStatementRequest) ([]LedgerStatementLine, int64, error) {
+	txn, err := dbtxn.Use(ctx, r.DB)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	posting := func(column string) string { return "p." + column }
+	entry := func(column string) string { return "e." + column }
+
+	// the window is evaluated before LIMIT and OFFSET so the running balance covers every earlier posting
+	builder := sq.
+		Select(
+			posting(LedgerPostingTable.ID),
+			posting(LedgerPostingTable.EntryID),
+			posting(LedgerPostingTable.AccountID),
+			posting(LedgerPostingTable.Direction),
+			posting(LedgerPostingTable.Amount),
+			posting(LedgerPostingTable.CreatedAt),
+			entry(LedgerEntryTable.EntryCode),
+			entry(LedgerEntryTable.EntryType),
+			entry(LedgerEntryTable.ReferenceID),
+			entry(LedgerEntryTable.Description),
+		).
+		Column("SUM(CASE WHEN "+posting(LedgerPostingTable.Direction)+" = ? THEN "+posting(LedgerPostingTable.Amount)+
+			" ELSE -"+posting(LedgerPostingTable.Amount)+" END) OVER (ORDER BY "+posting(LedgerPostingTable.ID)+")", request.NormalBalance).
+		From(LedgerPostingTableName + " p").
+		Join(LedgerEntryTableName + " e ON " + entry(LedgerEntryTable.ID) + " = " + posting(LedgerPostingTable.EntryID)).
+		Where(sq.Eq{posting(LedgerPostingTable.AccountID): request.AccountID}).
+		OrderBy(posting(LedgerPostingTable.ID) + " ASC").
+		Limit(request.Size).
+		Offset(request.Offset).
+		PlaceholderFormat(sq.Dollar)
+
+	rows, err := builder.RunWith(txn).QueryContext(ctx)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to execute query: %v", err)
+	}
+	defer rows.Close()
+
+	var lines []LedgerStatementLine
+	for rows.Next() {
+		var line LedgerStatementLine
+		if err := rows.Scan(
+			&line.ID,
+			&line.EntryID,
+			&line.AccountID,
+			&line.Direction,
+			&line.Amount,
+			&line.CreatedAt,
+			&line.EntryCode,
+			&line.EntryType,
+			&line.ReferenceID,
+			&line.Description,
+			&line.Balance,
+		); err != nil {
+			return nil, 0, fmt.Errorf("failed to scan row: %v", err)
+		}
+		lines = append(lines, line)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, 0, fmt.Errorf("failed to iterate over rows: %v", err)
+	}
+
+	countQuery := sq.Select("COUNT(*)").
+		From(LedgerPostingTableName).
+		Where(sq.Eq{LedgerPostingTable.AccountID: request.AccountID}).
+		PlaceholderFormat(sq.Dollar)
+
+	var totalRecords int64
+	countScanner := countQuery.RunWith(txn).QueryRowContext(ctx)
+	if err := countScanner.Scan(&totalRecords); err != nil {
+		return nil, 0, fmt.Errorf("failed to count postings: %v", err)
+	}
+
+	return lines, totalRecords, nil
+}
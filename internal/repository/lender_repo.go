@@ -0,0 +1,329 @@
+package repo
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	sq "github.com/Masterminds/squirrel"
+	"github.com/test/loan-service/internal/enum"
+	"github.com/typical-go/typical-rest-server/pkg/dbtxn"
+	"go.uber.org/dig"
+	"time"
+)
+
+//go:generate mockgen -source=$GOFILE -destination=$PROJ/internal/generated/mock/mock_$GOPACKAGE/$GOFILE
+
+type (
+	Lender struct {
+		Kyc // KYC status and the date it was verified
+
+		ID                    int64              `db:"id"`                      // Lender ID
+		FullName              string             `db:"full_name"`               // Full name of the lender, or the company name of an institution
+		NationalID            string             `db:"national_id"`             // National ID number (NIK), or the registration number of an institution
+		DateOfBirth           *time.Time         `db:"date_of_birth"`           // Date of birth, nil for institutions
+		PhoneNumber           string             `db:"phone_number"`            // Phone number of the lender
+		Email                 string             `db:"email"`                   // Preferred email, used for every funding of the lender
+		Address               string             `db:"address"`                 // Address of the lender
+		InvestorClass         enum.InvestorClass `db:"investor_class"`          // Investor classification (retail, accredited)
+		ClassifiedBy          *int64             `db:"classified_by"`           // Staff who set the investor classification
+		ClassifiedAt          *time.Time         `db:"classified_at"`           // Date the investor classification was set
+		RiskProfile           *enum.RiskProfile  `db:"risk_profile"`            // Risk profile from the suitability questionnaire, nil when not answered
+		SuitabilityScore      *int64             `db:"suitability_score"`       // Score of the suitability questionnaire
+		SuitabilityAssessedAt *time.Time         `db:"suitability_assessed_at"` // Date the suitability questionnaire was answered
+		CreatedAt             time.Time          `db:"created_at"`              // Date of creation
+		UpdatedAt             time.Time          `db:"updated_at"`              // Date of last update
+		DeletedAt             *time.Time         `db:"deleted_at"`              // Date of deletion if applicable
+	}
+
+	LenderRepo interface {
+		Create(ctx context.Context, lender *Lender) (int64, error)
+		Update(ctx context.Context, lender *Lender) error
+		GetByID(ctx context.Context, id int64) (*Lender, error)
+		GetByIDForUpdate(ctx context.Context, id int64) (*Lender, error)
+		GetByNationalID(ctx context.Context, nationalID string) (*Lender, error)
+	}
+
+	LenderRepoImpl struct {
+		dig.In
+		*sql.DB
+	}
+)
+
+var (
+	LenderTableName = "lenders"
+	LenderTable     = struct {
+		ID                    string
+		FullName              string
+		NationalID            string
+		DateOfBirth           string
+		PhoneNumber           string
+		Email                 string
+		Address               string
+		InvestorClass         string
+		ClassifiedBy          string
+		ClassifiedAt          string
+		RiskProfile           string
+		SuitabilityScore      string
+		SuitabilityAssessedAt string
+		KycStatus             string
+		KycVerifiedAt         string
+		CreatedAt             string
+		UpdatedAt             string
+		DeletedAt             string
+	}{
+		ID:                    "id",
+		FullName:              "full_name",
+		NationalID:            "national_id",
+		DateOfBirth:           "date_of_birth",
+		PhoneNumber:           "phone_number",
+		Email:                 "email",
+		Address:               "address",
+		InvestorClass:         "investor_class",
+		ClassifiedBy:          "classified_by",
+		ClassifiedAt:          "classified_at",
+		RiskProfile:           "risk_profile",
+		SuitabilityScore:      "suitability_score",
+		SuitabilityAssessedAt: "suitability_assessed_at",
+		KycStatus:             "kyc_status",
+		KycVerifiedAt:         "kyc_verified_at",
+		CreatedAt:             "created_at",
+		UpdatedAt:             "updated_at",
+		DeletedAt:             "deleted_at",
+	}
+)
+
+func NewLenderRepo(impl LenderRepoImpl) LenderRepo {
+	return &impl
+}
+
+// Create Lender and return last inserted id
+func (r *LenderRepoImpl) Create(ctx context.Context, lender *Lender) (int64, error) {
+	txn, err := dbtxn.Use(ctx, r.DB)
+	if err != nil {
+		return -1, err
+	}
+
+	builder := sq.
+		Insert(LenderTableName).
+		Columns(
+			LenderTable.FullName,
+			LenderTable.NationalID,
+			LenderTable.DateOfBirth,
+			LenderTable.PhoneNumber,
+			LenderTable.Email,
+			LenderTable.Address,
+			LenderTable.InvestorClass,
+			LenderTable.ClassifiedBy,
+			LenderTable.ClassifiedAt,
+			LenderTable.RiskProfile,
+			LenderTable.SuitabilityScore,
+			LenderTable.SuitabilityAssessedAt,
+			LenderTable.KycStatus,
+			LenderTable.KycVerifiedAt,
+			LenderTable.CreatedAt,
+			LenderTable.UpdatedAt,
+			LenderTable.DeletedAt,
+		).
+		Suffix("RETURNING id").
+		PlaceholderFormat(sq.Dollar).
+		Values(
+			lender.FullName,
+			lender.NationalID,
+			lender.DateOfBirth,
+			lender.PhoneNumber,
+			lender.Email,
+			lender.Address,
+			lender.InvestorClass,
+			lender.ClassifiedBy,
+			lender.ClassifiedAt,
+			lender.RiskProfile,
+			lender.SuitabilityScore,
+			lender.SuitabilityAssessedAt,
+			lender.KycStatus,
+			lender.KycVerifiedAt,
+			time.Now(),
+			time.Now(),
+			nil,
+		)
+
+	scanner := builder.RunWith(txn).QueryRowContext(ctx)
+
+	var id int64
+	if err := scanner.Scan(&id); err != nil {
+		return -1, fmt.Errorf("failed to scan id: %v", err)
+	}
+
+	return id, nil
+}
+
+// Update Lender profile, classification, suitability and KYC status
+func (r *LenderRepoImpl) Update(ctx context.Context, lender *Lender) error {
+	txn, err := dbtxn.Use(ctx, r.DB)
+	if err != nil {
+		return err
+	}
+
+	builder := sq.Update(LenderTableName).
+		Set(LenderTable.FullName, lender.FullName).
+		Set(LenderTable.NationalID, lender.NationalID).
+		Set(LenderTable.DateOfBirth, lender.DateOfBirth).
+		Set(LenderTable.PhoneNumber, lender.PhoneNumber).
+		Set(LenderTable.Email, lender.Email).
+		Set(LenderTable.Address, lender.Address).
+		Set(LenderTable.InvestorClass, lender.InvestorClass).
+		Set(LenderTable.ClassifiedBy, lender.ClassifiedBy).
+		Set(LenderTable.ClassifiedAt, lender.ClassifiedAt).
+		Set(LenderTable.RiskProfile, lender.RiskProfile).
+		Set(LenderTable.SuitabilityScore, lender.SuitabilityScore).
+		Set(LenderTable.SuitabilityAssessedAt, lender.SuitabilityAssessedAt).
+		Set(LenderTable.KycStatus, lender.KycStatus).
+		Set(LenderTable.KycVerifiedAt, lender.KycVerifiedAt).
+		Set(LenderTable.UpdatedAt, time.Now()).
+		Where(sq.Eq{
+			LenderTable.ID:        lender.ID,
+			LenderTable.DeletedAt: nil,
+		}).
+		PlaceholderFormat(sq.Dollar)
+
+	result, err := builder.RunWith(txn).ExecContext(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to update lender: %v", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %v", err)
+	}
+	if rowsAffected == 0 {
+		return fmt.Errorf("no lender found: %d", lender.ID)
+	}
+
+	return nil
+}
+
+// GetByID returns the lender, nil when it does not exist
+func (r *LenderRepoImpl) GetByID(ctx context.Context, id int64) (*Lender, error) {
+	txn, err := dbtxn.Use(ctx, r.DB)
+	if err != nil {
+		return nil, err
+	}
+
+	builder := r.selectBuilder().
+		Where(sq.Eq{
+			LenderTable.ID:        id,
+			LenderTable.DeletedAt: nil,
+		})
+
+	var lender Lender
+	scanner := builder.RunWith(txn).QueryRowContext(ctx)
+	if err := scanner.Scan(r.scanDest(&lender)...); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to scan lender: %v", err)
+	}
+
+	return &lender, nil
+}
+
+// GetByIDForUpdate returns the lender and locks the row until the transaction ends, nil when it does
+// not exist
+func (r *LenderRepoImpl) GetByIDForUpdate(ctx context.Context, id int64) (*Lender, error) {
+	// row lock only holds when ctx carries a transaction
+	txn, err := dbtxn.Use(ctx, r.DB)
+	if err != nil {
+		return nil, err
+	}
+
+	builder := r.selectBuilder().
+		Where(sq.Eq{
+			LenderTable.ID:        id,
+			LenderTable.DeletedAt: nil,
+		}).
+		Suffix("FOR UPDATE")
+
+	var lender Lender
+	scanner := builder.RunWith(txn).QueryRowContext(ctx)
+	if err := scanner.Scan(r.scanDest(&lender)...); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to scan lender: %v", err)
+	}
+
+	return &lender, nil
+}
+
+// GetByNationalID returns the lender registered with a national ID, nil when there is none
+func (r *LenderRepoImpl) GetByNationalID(ctx context.Context, nationalID string) (*Lender, error) {
+	txn, err := dbtxn.Use(ctx, r.DB)
+	if err != nil {
+		return nil, err
+	}
+
+	builder := r.selectBuilder().
+		Where(sq.Eq{
+			LenderTable.NationalID: nationalID,
+			LenderTable.DeletedAt:  nil,
+		})
+
+	var lender Lender
+	scanner := builder.RunWith(txn).QueryRowContext(ctx)
+	if err := scanner.Scan(r.scanDest(&lender)...); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to scan lender: %v", err)
+	}
+
+	return &lender, nil
+}
+
+func (r *LenderRepoImpl) selectBuilder() sq.SelectBuilder {
+	return sq.
+		Select(
+			LenderTable.ID,
+			LenderTable.FullName,
+			LenderTable.NationalID,
+			LenderTable.DateOfBirth,
+			LenderTable.PhoneNumber,
+			LenderTable.Email,
+			LenderTable.Address,
+			LenderTable.InvestorClass,
+			LenderTable.ClassifiedBy,
+			LenderTable.ClassifiedAt,
+			LenderTable.RiskProfile,
+			LenderTable.SuitabilityScore,
+			LenderTable.SuitabilityAssessedAt,
+			LenderTable.KycStatus,
+			LenderTable.KycVerifiedAt,
+			LenderTable.CreatedAt,
+			LenderTable.UpdatedAt,
+			LenderTable.DeletedAt,
+		).
+		From(LenderTableName).
+		PlaceholderFormat(sq.Dollar)
+}
+
+func (r *LenderRepoImpl) scanDest(lender *Lender) []interface{} {
+	return []interface{}{
+		&lender.ID,
+		&lender.FullName,
+		&lender.NationalID,
+		&lender.DateOfBirth,
+		&lender.PhoneNumber,
+		&lender.Email,
+		&lender.Address,
+		&lender.InvestorClass,
+		&lender.ClassifiedBy,
+		&lender.ClassifiedAt,
+		&lender.RiskProfile,
+		&lender.SuitabilityScore,
+		&lender.SuitabilityAssessedAt,
+		&lender.KycStatus,
+		&lender.KycVerifiedAt,
+		&lender.CreatedAt,
+		&lender.UpdatedAt,
+		&lender.DeletedAt,
+	}
+}
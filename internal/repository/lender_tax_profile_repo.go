@@ -0,0 +1,148 @@
+package repo
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	sq "github.com/Masterminds/squirrel"
+	"github.com/test/loan-service/internal/enum"
+	"github.com/typical-go/typical-rest-server/pkg/dbtxn"
+	"go.uber.org/dig"
+	"time"
+)
+
+//go:generate mockgen -source=$GOFILE -destination=$PROJ/internal/generated/mock/mock_$GOPACKAGE/$GOFILE
+
+type (
+	LenderTaxProfile struct {
+		ID         int64           `db:"id"`          // Tax profile ID
+		LenderID   int64           `db:"lender_id"`   // Lender the profile belongs to
+		LenderType enum.LenderType `db:"lender_type"` // Lender type (individual, institutional)
+		TaxID      *string         `db:"tax_id"`      // Tax ID of the lender, nil when the lender has none
+		CreatedAt  time.Time       `db:"created_at"`  // Date of creation
+		UpdatedAt  time.Time       `db:"updated_at"`  // Date of last update
+		DeletedAt  *time.Time      `db:"deleted_at"`  // Date of deletion if applicable
+	}
+
+	LenderTaxProfileRepo interface {
+		Upsert(ctx context.Context, profile *LenderTaxProfile) (int64, error)
+		GetByLenderID(ctx context.Context, lenderID int64) (*LenderTaxProfile, error)
+	}
+
+	LenderTaxProfileRepoImpl struct {
+		dig.In
+		*sql.DB
+	}
+)
+
+var (
+	LenderTaxProfileTableName = "lender_tax_profiles"
+	LenderTaxProfileTable     = struct {
+		ID         string
+		LenderID   string
+		LenderType string
+		TaxID      string
+		CreatedAt  string
+		UpdatedAt  string
+		DeletedAt  string
+	}{
+		ID:         "id",
+		LenderID:   "lender_id",
+		LenderType: "lender_type",
+		TaxID:      "tax_id",
+		CreatedAt:  "created_at",
+		UpdatedAt:  "updated_at",
+		DeletedAt:  "deleted_at",
+	}
+)
+
+func NewLenderTaxProfileRepo(impl LenderTaxProfileRepoImpl) LenderTaxProfileRepo {
+	return &impl
+}
+
+// Upsert creates the tax profile of the lender or replaces the one the lender already has, and
+// returns its id
+func (r *LenderTaxProfileRepoImpl) Upsert(ctx context.Context, profile *LenderTaxProfile) (int64, error) {
+	txn, err := dbtxn.Use(ctx, r.DB)
+	if err != nil {
+		return -1, err
+	}
+
+	builder := sq.
+		Insert(LenderTaxProfileTableName).
+		Columns(
+			LenderTaxProfileTable.LenderID,
+			LenderTaxProfileTable.LenderType,
+			LenderTaxProfileTable.TaxID,
+			LenderTaxProfileTable.CreatedAt,
+			LenderTaxProfileTable.UpdatedAt,
+			LenderTaxProfileTable.DeletedAt,
+		).
+		Suffix("ON CONFLICT ("+LenderTaxProfileTable.LenderID+") DO UPDATE SET "+
+			LenderTaxProfileTable.LenderType+" = EXCLUDED."+LenderTaxProfileTable.LenderType+", "+
+			LenderTaxProfileTable.TaxID+" = EXCLUDED."+LenderTaxProfileTable.TaxID+", "+
+			LenderTaxProfileTable.UpdatedAt+" = EXCLUDED."+LenderTaxProfileTable.UpdatedAt+", "+
+			LenderTaxProfileTable.DeletedAt+" = NULL RETURNING id").
+		PlaceholderFormat(sq.Dollar).
+		Values(
+			profile.LenderID,
+			profile.LenderType,
+			profile.TaxID,
+			time.Now(),
+			time.Now(),
+			nil,
+		)
+
+	scanner := builder.RunWith(txn).QueryRowContext(ctx)
+
+	var id int64
+	if err := scanner.Scan(&id); err != nil {
+		return -1, fmt.Errorf("failed to scan id: %v", err)
+	}
+
+	return id, nil
+}
+
+// GetByLenderID returns the tax profile of a lender, nil when the lender has no profile
+func (r *LenderTaxProfileRepoImpl) GetByLenderID(ctx context.Context, lenderID int64) (*LenderTaxProfile, error) {
+	txn, err := dbtxn.Use(ctx, r.DB)
+	if err != nil {
+		return nil, err
+	}
+
+	builder := sq.
+		Select(
+			LenderTaxProfileTable.ID,
+			LenderTaxProfileTable.LenderID,
+			LenderTaxProfileTable.LenderType,
+			LenderTaxProfileTable.TaxID,
+			LenderTaxProfileTable.CreatedAt,
+			LenderTaxProfileTable.UpdatedAt,
+			LenderTaxProfileTable.DeletedAt,
+		).
+		From(LenderTaxProfileTableName).
+		Where(sq.Eq{
+			LenderTaxProfileTable.LenderID:  lenderID,
+			LenderTaxProfileTable.DeletedAt: nil,
+		}).
+		PlaceholderFormat(sq.Dollar)
+
+	var profile LenderTaxProfile
+	scanner := builder.RunWith(txn).QueryRowContext(ctx)
+	if err := scanner.Scan(
+		&profile.ID,
+		&profile.LenderID,
+		&profile.LenderType,
+		&profile.TaxID,
+		&profile.CreatedAt,
+		&profile.UpdatedAt,
+		&profile.DeletedAt,
+	); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to scan lender tax profile: %v", err)
+	}
+
+	return &profile, nil
+}
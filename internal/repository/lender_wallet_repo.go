@@ -0,0 +1,190 @@
+package repo
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	sq "github.com/Masterminds/squirrel"
+	"github.com/test/loan-service/internal/money"
+	"github.com/typical-go/typical-rest-server/pkg/dbtxn"
+	"go.uber.org/dig"
+	"time"
+)
+
+//go:generate mockgen -source=$GOFILE -destination=$PROJ/internal/generated/mock/mock_$GOPACKAGE/$GOFILE
+
+type (
+	LenderWallet struct {
+		ID               int64        `db:"id"`                // Wallet ID
+		LenderID         int64        `db:"lender_id"`         // Lender owning the wallet
+		AvailableBalance money.Amount `db:"available_balance"` // Money the lender can still invest or withdraw
+		HeldBalance      money.Amount `db:"held_balance"`      // Money reserved for pending fundings and withdrawals
+		CreatedAt        time.Time    `db:"created_at"`        // Date of creation
+		UpdatedAt        time.Time    `db:"updated_at"`        // Date of last update
+		DeletedAt        *time.Time   `db:"deleted_at"`        // Date of deletion if applicable
+	}
+
+	LenderWalletRepo interface {
+		CreateIfNotExists(ctx context.Context, lenderID int64) (int64, error)
+		Update(ctx context.Context, wallet *LenderWallet) error
+		GetByLenderID(ctx context.Context, lenderID int64) (*LenderWallet, error)
+		GetByLenderIDForUpdate(ctx context.Context, lenderID int64) (*LenderWallet, error)
+	}
+
+	LenderWalletRepoImpl struct {
+		dig.In
+		*sql.DB
+	}
+)
+
+var (
+	LenderWalletTableName = "lender_wallets"
+	LenderWalletTable     = struct {
+		ID               string
+		LenderID         string
+		AvailableBalance string
+		HeldBalance      string
+		CreatedAt        string
+		UpdatedAt        string
+		DeletedAt        string
+	}{
+		ID:               "id",
+		LenderID:         "lender_id",
+		AvailableBalance: "available_balance",
+		HeldBalance:      "held_balance",
+		CreatedAt:        "created_at",
+		UpdatedAt:        "updated_at",
+		DeletedAt:        "deleted_at",
+	}
+)
+
+func NewLenderWalletRepo(impl LenderWalletRepoImpl) LenderWalletRepo {
+	return &impl
+}
+
+// CreateIfNotExists opens an empty wallet for the lender and returns its id, or returns the id of the
+// wallet the lender already has
+func (r *LenderWalletRepoImpl) CreateIfNotExists(ctx context.Context, lenderID int64) (int64, error) {
+	txn, err := dbtxn.Use(ctx, r.DB)
+	if err != nil {
+		return -1, err
+	}
+
+	builder := sq.
+		Insert(LenderWalletTableName).
+		Columns(
+			LenderWalletTable.LenderID,
+			LenderWalletTable.AvailableBalance,
+			LenderWalletTable.HeldBalance,
+			LenderWalletTable.CreatedAt,
+			LenderWalletTable.UpdatedAt,
+			LenderWalletTable.DeletedAt,
+		).
+		// the no-op update makes RETURNING also yield the id of an existing wallet
+		Suffix("ON CONFLICT ("+LenderWalletTable.LenderID+") DO UPDATE SET "+
+			LenderWalletTable.LenderID+" = EXCLUDED."+LenderWalletTable.LenderID+" RETURNING id").
+		PlaceholderFormat(sq.Dollar).
+		Values(
+			lenderID,
+			money.Zero,
+			money.Zero,
+			time.Now(),
+			time.Now(),
+			nil,
+		)
+
+	scanner := builder.RunWith(txn).QueryRowContext(ctx)
+
+	var id int64
+	if err := scanner.Scan(&id); err != nil {
+		return -1, fmt.Errorf("failed to scan id: %v", err)
+	}
+
+	return id, nil
+}
+
+// Update the balances of a LenderWallet
+func (r *LenderWalletRepoImpl) Update(ctx context.Context, wallet *LenderWallet) error {
+	txn, err := dbtxn.Use(ctx, r.DB)
+	if err != nil {
+		return err
+	}
+
+	builder := sq.Update(LenderWalletTableName).
+		Set(LenderWalletTable.AvailableBalance, wallet.AvailableBalance).
+		Set(LenderWalletTable.HeldBalance, wallet.HeldBalance).
+		Set(LenderWalletTable.UpdatedAt, time.Now()).
+		Where(sq.Eq{LenderWalletTable.ID: wallet.ID}).
+		PlaceholderFormat(sq.Dollar)
+
+	result, err := builder.RunWith(txn).ExecContext(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to update lender wallet: %v", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %v", err)
+	}
+	if rowsAffected == 0 {
+		return fmt.Errorf("no lender wallet found with ID: %d", wallet.ID)
+	}
+
+	return nil
+}
+
+// GetByLenderID returns the wallet of a lender, nil when the lender has no wallet yet
+func (r *LenderWalletRepoImpl) GetByLenderID(ctx context.Context, lenderID int64) (*LenderWallet, error) {
+	return r.getByLenderID(ctx, lenderID, false)
+}
+
+// GetByLenderIDForUpdate is like GetByLenderID but locks the wallet row until the transaction ends
+func (r *LenderWalletRepoImpl) GetByLenderIDForUpdate(ctx context.Context, lenderID int64) (*LenderWallet, error) {
+	return r.getByLenderID(ctx, lenderID, true)
+}
+
+func (r *LenderWalletRepoImpl) getByLenderID(ctx context.Context, lenderID int64, forUpdate bool) (*LenderWallet, error) {
+	txn, err := dbtxn.Use(ctx, r.DB)
+	if err != nil {
+		return nil, err
+	}
+
+	builder := sq.
+		Select(
+			LenderWalletTable.ID,
+			LenderWalletTable.LenderID,
+			LenderWalletTable.AvailableBalance,
+			LenderWalletTable.HeldBalance,
+			LenderWalletTable.CreatedAt,
+			LenderWalletTable.UpdatedAt,
+			LenderWalletTable.DeletedAt,
+		).
+		From(LenderWalletTableName).
+		Where(sq.Eq{
+			LenderWalletTable.LenderID:  lenderID,
+			LenderWalletTable.DeletedAt: nil,
+		}).
+		PlaceholderFormat(sq.Dollar)
+	if forUpdate {
+		builder = builder.Suffix("FOR UPDATE")
+	}
+
+	var wallet LenderWallet
+	scanner := builder.RunWith(txn).QueryRowContext(ctx)
+	if err := scanner.Scan(
+		&wallet.ID,
+		&wallet.LenderID,
+		&wallet.AvailableBalance,
+		&wallet.HeldBalance,
+		&wallet.CreatedAt,
+		&wallet.UpdatedAt,
+		&wallet.DeletedAt,
+	); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to scan lender wallet: %v", err)
+	}
+
+	return &wallet, nil
+}
@@ -0,0 +1,208 @@
+package repo
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	sq "github.com/Masterminds/squirrel"
+	"github.com/typical-go/typical-rest-server/pkg/dbtxn"
+	"go.uber.org/dig"
+	"time"
+)
+
+//go:generate mockgen -source=$GOFILE -destination=$PROJ/internal/generated/mock/mock_$GOPACKAGE/$GOFILE
+
+type (
+	LoanAgreement struct {
+		ID              int64     `db:"id"`               // Agreement ID
+		AgreementNumber string    `db:"agreement_number"` // Agreement number printed on the document
+		LoanID          int64     `db:"loan_id"`          // Loan the agreement is made for
+		TemplateID      int64     `db:"template_id"`      // Template the agreement was rendered from
+		TemplateVersion int64     `db:"template_version"` // Version of the template
+		DocumentKey     string    `db:"document_key"`     // Key of the document in the document store
+		DocumentURL     string    `db:"document_url"`     // URL the document can be downloaded from
+		DocumentHash    *string   `db:"document_hash"`    // SHA-256 of the document, nil for agreements generated before hashing
+		GeneratedAt     time.Time `db:"generated_at"`     // Date the document was generated
+		CreatedAt       time.Time `db:"created_at"`       // Date of creation
+		UpdatedAt       time.Time `db:"updated_at"`       // Date of last update
+	}
+
+	LoanAgreementRepo interface {
+		Create(ctx context.Context, agreement *LoanAgreement) (int64, error)
+		GetByID(ctx context.Context, id int64) (*LoanAgreement, error)
+		GetByLoanID(ctx context.Context, loanID int64) ([]LoanAgreement, error)
+	}
+
+	LoanAgreementRepoImpl struct {
+		dig.In
+		*sql.DB
+	}
+)
+
+var (
+	LoanAgreementTableName = "loan_agreements"
+	LoanAgreementTable     = struct {
+		ID              string
+		AgreementNumber string
+		LoanID          string
+		TemplateID      string
+		TemplateVersion string
+		DocumentKey     string
+		DocumentURL     string
+		DocumentHash    string
+		GeneratedAt     string
+		CreatedAt       string
+		UpdatedAt       string
+	}{
+		ID:              "id",
+		AgreementNumber: "agreement_number",
+		LoanID:          "loan_id",
+		TemplateID:      "template_id",
+		TemplateVersion: "template_version",
+		DocumentKey:     "document_key",
+		DocumentURL:     "document_url",
+		DocumentHash:    "document_hash",
+		GeneratedAt:     "generated_at",
+		CreatedAt:       "created_at",
+		UpdatedAt:       "updated_at",
+	}
+)
+
+func NewLoanAgreementRepo(impl LoanAgreementRepoImpl) LoanAgreementRepo {
+	return &impl
+}
+
+// Create LoanAgreement and return last inserted id
+func (r *LoanAgreementRepoImpl) Create(ctx context.Context, agreement *LoanAgreement) (int64, error) {
+	txn, err := dbtxn.Use(ctx, r.DB)
+	if err != nil {
+		return -1, err
+	}
+
+	builder := sq.
+		Insert(LoanAgreementTableName).
+		Columns(
+			LoanAgreementTable.AgreementNumber,
+			LoanAgreementTable.LoanID,
+			LoanAgreementTable.TemplateID,
+			LoanAgreementTable.TemplateVersion,
+			LoanAgreementTable.DocumentKey,
+			LoanAgreementTable.DocumentURL,
+			LoanAgreementTable.DocumentHash,
+			LoanAgreementTable.GeneratedAt,
+			LoanAgreementTable.CreatedAt,
+			LoanAgreementTable.UpdatedAt,
+		).
+		Suffix("RETURNING id").
+		PlaceholderFormat(sq.Dollar).
+		Values(
+			agreement.AgreementNumber,
+			agreement.LoanID,
+			agreement.TemplateID,
+			agreement.TemplateVersion,
+			agreement.DocumentKey,
+			agreement.DocumentURL,
+			agreement.DocumentHash,
+			agreement.GeneratedAt,
+			time.Now(),
+			time.Now(),
+		)
+
+	scanner := builder.RunWith(txn).QueryRowContext(ctx)
+
+	var id int64
+	if err := scanner.Scan(&id); err != nil {
+		return -1, fmt.Errorf("failed to scan id: %v", err)
+	}
+
+	return id, nil
+}
+
+// GetByID returns the agreement, nil when it does not exist
+func (r *LoanAgreementRepoImpl) GetByID(ctx context.Context, id int64) (*LoanAgreement, error) {
+	txn, err := dbtxn.Use(ctx, r.DB)
+	if err != nil {
+		return nil, err
+	}
+
+	var agreement LoanAgreement
+	scanner := r.selectBuilder().
+		Where(sq.Eq{LoanAgreementTable.ID: id}).
+		RunWith(txn).QueryRowContext(ctx)
+	if err := scanner.Scan(r.scanDest(&agreement)...); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to scan loan agreement: %v", err)
+	}
+
+	return &agreement, nil
+}
+
+// GetByLoanID returns the agreements generated for a loan, latest first
+func (r *LoanAgreementRepoImpl) GetByLoanID(ctx context.Context, loanID int64) ([]LoanAgreement, error) {
+	txn, err := dbtxn.Use(ctx, r.DB)
+	if err != nil {
+		return nil, err
+	}
+
+	builder := r.selectBuilder().
+		Where(sq.Eq{LoanAgreementTable.LoanID: loanID}).
+		OrderBy(LoanAgreementTable.GeneratedAt+" DESC", LoanAgreementTable.ID+" DESC")
+
+	rows, err := builder.RunWith(txn).QueryContext(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute query: %v", err)
+	}
+	defer rows.Close()
+
+	var agreements []LoanAgreement
+	for rows.Next() {
+		var agreement LoanAgreement
+		if err := rows.Scan(r.scanDest(&agreement)...); err != nil {
+			return nil, fmt.Errorf("failed to scan row: %v", err)
+		}
+		agreements = append(agreements, agreement)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate over rows: %v", err)
+	}
+
+	return agreements, nil
+}
+
+func (r *LoanAgreementRepoImpl) selectBuilder() sq.SelectBuilder {
+	return sq.
+		Select(
+			LoanAgreementTable.ID,
+			LoanAgreementTable.AgreementNumber,
+			LoanAgreementTable.LoanID,
+			LoanAgreementTable.TemplateID,
+			LoanAgreementTable.TemplateVersion,
+			LoanAgreementTable.DocumentKey,
+			LoanAgreementTable.DocumentURL,
+			LoanAgreementTable.DocumentHash,
+			LoanAgreementTable.GeneratedAt,
+			LoanAgreementTable.CreatedAt,
+			LoanAgreementTable.UpdatedAt,
+		).
+		From(LoanAgreementTableName).
+		PlaceholderFormat(sq.Dollar)
+}
+
+func (r *LoanAgreementRepoImpl) scanDest(agreement *LoanAgreement) []interface{} {
+	return []interface{}{
+		&agreement.ID,
+		&agreement.AgreementNumber,
+		&agreement.LoanID,
+		&agreement.TemplateID,
+		&agreement.TemplateVersion,
+		&agreement.DocumentKey,
+		&agreement.DocumentURL,
+		&agreement.DocumentHash,
+		&agreement.GeneratedAt,
+		&agreement.CreatedAt,
+		&agreement.UpdatedAt,
+	}
+}
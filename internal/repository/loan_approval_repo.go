@@ -27,6 +27,8 @@ type (
 		StaffID        *int64              `db:"staff_id"`
 		ApprovalDate   *time.Time          `db:"approval_date"`
 		ApprovalStatus enum.ApprovalStatus `db:"approval_status"`
+		RequiredLevel  int64               `db:"required_level"`
+		ApprovedLevel  int64               `db:"approved_level"`
 		CreatedAt      time.Time           `db:"created_at"`
 		UpdatedAt      time.Time           `db:"updated_at"`
 		DeletedAt      *time.Time          `db:"deleted_at"`
@@ -37,6 +39,7 @@ type LoanApprovalRepo interface {
 	Create(ctx context.Context, loanApproval *LoanApproval) (int64, error)
 	Update(ctx context.Context, loanApproval *LoanApproval) error
 	GetByID(ctx context.Context, approvalID int64) (*LoanApproval, error)
+	GetByIDForUpdate(ctx context.Context, approvalID int64) (*LoanApproval, error)
 	GetAll(ctx context.Context) ([]LoanApproval, error)
 	GetAllPage(ctx context.Context, loanRequest LoanApprovalRequest) ([]LoanApproval, int64, error)
 }
@@ -60,6 +63,8 @@ var (
 		StaffID        string
 		ApprovalDate   string
 		ApprovalStatus string
+		RequiredLevel  string
+		ApprovedLevel  string
 		CreatedAt      string
 		UpdatedAt      string
 		DeletedAt      string
@@ -70,6 +75,8 @@ var (
 		StaffID:        "staff_id",
 		ApprovalDate:   "approval_date",
 		ApprovalStatus: "approval_status",
+		RequiredLevel:  "required_level",
+		ApprovedLevel:  "approved_level",
 		CreatedAt:      "created_at",
 		UpdatedAt:      "updated_at",
 		DeletedAt:      "deleted_at",
@@ -91,6 +98,8 @@ func (r *LoanApprovalRepoImpl) Create(ctx context.Context, loanApproval *LoanApp
 			LoanApprovalTable.StaffID,
 			LoanApprovalTable.ApprovalDate,
 			LoanApprovalTable.ApprovalStatus,
+			LoanApprovalTable.RequiredLevel,
+			LoanApprovalTable.ApprovedLevel,
 			LoanApprovalTable.CreatedAt,
 			LoanApprovalTable.UpdatedAt,
 			LoanApprovalTable.DeletedAt,
@@ -103,6 +112,8 @@ func (r *LoanApprovalRepoImpl) Create(ctx context.Context, loanApproval *LoanApp
 			loanApproval.StaffID,
 			loanApproval.ApprovalDate,
 			loanApproval.ApprovalStatus,
+			loanApproval.RequiredLevel,
+			loanApproval.ApprovedLevel,
 			loanApproval.CreatedAt,
 			loanApproval.UpdatedAt,
 			nil,
@@ -133,6 +144,8 @@ func (r *LoanApprovalRepoImpl) GetByID(ctx context.Context, approvalID int64) (*
 			LoanApprovalTable.StaffID,
 			LoanApprovalTable.ApprovalDate,
 			LoanApprovalTable.ApprovalStatus,
+			LoanApprovalTable.RequiredLevel,
+			LoanApprovalTable.ApprovedLevel,
 			LoanApprovalTable.CreatedAt,
 			LoanApprovalTable.UpdatedAt,
 			LoanApprovalTable.DeletedAt,
@@ -151,6 +164,8 @@ func (r *LoanApprovalRepoImpl) GetByID(ctx context.Context, approvalID int64) (*
 		&loanApproval.StaffID,
 		&loanApproval.ApprovalDate,
 		&loanApproval.ApprovalStatus,
+		&loanApproval.RequiredLevel,
+		&loanApproval.ApprovedLevel,
 		&loanApproval.CreatedAt,
 		&loanApproval.UpdatedAt,
 		&loanApproval.DeletedAt,
@@ -161,6 +176,58 @@ func (r *LoanApprovalRepoImpl) GetByID(ctx context.Context, approvalID int64) (*
 	return &loanApproval, nil
 }
 
+// GetByIDForUpdate returns the loan approval and locks the row until the transaction ends, nil when
+// it does not exist
+func (r *LoanApprovalRepoImpl) GetByIDForUpdate(ctx context.Context, approvalID int64) (*LoanApproval, error) {
+	txn, err := dbtxn.Use(ctx, r.DB)
+	if err != nil {
+		return nil, err
+	}
+
+	builder := sq.
+		Select(
+			LoanApprovalTable.ID,
+			LoanApprovalTable.LoanID,
+			LoanApprovalTable.ApprovalNumber,
+			LoanApprovalTable.StaffID,
+			LoanApprovalTable.ApprovalDate,
+			LoanApprovalTable.ApprovalStatus,
+			LoanApprovalTable.RequiredLevel,
+			LoanApprovalTable.ApprovedLevel,
+			LoanApprovalTable.CreatedAt,
+			LoanApprovalTable.UpdatedAt,
+			LoanApprovalTable.DeletedAt,
+		).
+		From(LoanApprovalTableName).
+		Where(sq.Eq{LoanApprovalTable.ID: approvalID}).
+		Suffix("FOR UPDATE").
+		PlaceholderFormat(sq.Dollar)
+
+	var loanApproval LoanApproval
+	scanner := builder.RunWith(txn).QueryRowContext(ctx)
+
+	if err := scanner.Scan(
+		&loanApproval.ID,
+		&loanApproval.LoanID,
+		&loanApproval.ApprovalNumber,
+		&loanApproval.StaffID,
+		&loanApproval.ApprovalDate,
+		&loanApproval.ApprovalStatus,
+		&loanApproval.RequiredLevel,
+		&loanApproval.ApprovedLevel,
+		&loanApproval.CreatedAt,
+		&loanApproval.UpdatedAt,
+		&loanApproval.DeletedAt,
+	); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to scan loan approval: %v", err)
+	}
+
+	return &loanApproval, nil
+}
+
 func (r *LoanApprovalRepoImpl) GetAll(ctx context.Context) ([]LoanApproval, error) {
 	txn, err := dbtxn.Use(ctx, r.DB)
 	if err != nil {
@@ -176,6 +243,8 @@ func (r *LoanApprovalRepoImpl) GetAll(ctx context.Context) ([]LoanApproval, erro
 			LoanApprovalTable.StaffID,
 			LoanApprovalTable.ApprovalDate,
 			LoanApprovalTable.ApprovalStatus,
+			LoanApprovalTable.RequiredLevel,
+			LoanApprovalTable.ApprovedLevel,
 			LoanApprovalTable.CreatedAt,
 			LoanApprovalTable.UpdatedAt,
 			LoanApprovalTable.DeletedAt,
@@ -205,6 +274,8 @@ func (r *LoanApprovalRepoImpl) GetAll(ctx context.Context) ([]LoanApproval, erro
 			&loanApproval.StaffID,
 			&loanApproval.ApprovalDate,
 			&loanApproval.ApprovalStatus,
+			&loanApproval.RequiredLevel,
+			&loanApproval.ApprovedLevel,
 			&loanApproval.CreatedAt,
 			&loanApproval.UpdatedAt,
 			&loanApproval.DeletedAt,
@@ -236,6 +307,8 @@ func (r *LoanApprovalRepoImpl) GetAllPage(ctx context.Context, approvalRequest L
 			LoanApprovalTable.StaffID,
 			LoanApprovalTable.ApprovalDate,
 			LoanApprovalTable.ApprovalStatus,
+			LoanApprovalTable.RequiredLevel,
+			LoanApprovalTable.ApprovedLevel,
 			LoanApprovalTable.CreatedAt,
 			LoanApprovalTable.UpdatedAt,
 			LoanApprovalTable.DeletedAt,
@@ -271,6 +344,8 @@ func (r *LoanApprovalRepoImpl) GetAllPage(ctx context.Context, approvalRequest L
 			&loanApproval.StaffID,
 			&loanApproval.ApprovalDate,
 			&loanApproval.ApprovalStatus,
+			&loanApproval.RequiredLevel,
+			&loanApproval.ApprovedLevel,
 			&loanApproval.CreatedAt,
 			&loanApproval.UpdatedAt,
 			&loanApproval.DeletedAt,
@@ -307,6 +382,8 @@ func (r *LoanApprovalRepoImpl) Update(ctx context.Context, loanApproval *LoanApp
 		Set(LoanApprovalTable.ApprovalStatus, loanApproval.ApprovalStatus).
 		Set(LoanApprovalTable.ApprovalDate, loanApproval.ApprovalDate).
 		Set(LoanApprovalTable.StaffID, loanApproval.StaffID).
+		Set(LoanApprovalTable.ApprovedLevel, loanApproval.ApprovedLevel).
+		Set(LoanApprovalTable.UpdatedAt, time.Now()).
 		Where(sq.Eq{LoanApprovalTable.ID: loanApproval.ID}).
 		PlaceholderFormat(sq.Dollar)
 
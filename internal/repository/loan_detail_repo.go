@@ -5,6 +5,7 @@ import (
 	"database/sql"
 	"fmt"
 	sq "github.com/Masterminds/squirrel"
+	"github.com/test/loan-service/internal/money"
 	"github.com/typical-go/typical-rest-server/pkg/dbtxn"
 	"go.uber.org/dig"
 	"time"
@@ -14,25 +15,25 @@ import (
 
 type (
 	LoanDetail struct {
-		ID                         int64      `db:"id"`                           // Loan detail ID (Auto Increment)
-		LoanID                     int64      `db:"loan_id"`                      // Loan ID, linking to the loans table
-		BorrowerID                 int64      `db:"borrower_id"`                  // Borrower ID
-		BusinessName               string     `db:"business_name"`                // Business name of the borrower
-		BusinessType               string     `db:"business_type"`                // Type of business (e.g., retail, manufacturing)
-		BusinessAddress            string     `db:"business_address"`             // Business address
-		BusinessPhoneNumber        string     `db:"business_phone_number"`        // Business phone number
-		BusinessEmail              string     `db:"business_email"`               // Business email address
-		BusinessRegistrationNumber string     `db:"business_registration_number"` // Business registration number
-		BusinessAnnualRevenue      float64    `db:"business_annual_revenue"`      // Annual revenue of the business
-		BusinessExpense            float64    `db:"business_expense"`             // Annual expenses of the business
-		BusinessOwnerName          string     `db:"business_owner_name"`          // Business owner's name
-		BusinessDescription        string     `db:"business_description"`         // Business description
-		LoanPurpose                string     `db:"loan_purpose"`                 // Purpose of the loan
-		BusinessAge                int64      `db:"business_age"`                 // Business age (in years)
-		BusinessSector             string     `db:"business_sector"`              // Business sector (e.g., agriculture, technology)
-		CreatedAt                  time.Time  `db:"created_at"`                   // Date of loan detail creation
-		UpdatedAt                  time.Time  `db:"updated_at"`                   // Loan detail update date
-		DeletedAt                  *time.Time `db:"deleted_at"`                   // Date of loan detail deletion (if applicable)
+		ID                         int64        `db:"id"`                           // Loan detail ID (Auto Increment)
+		LoanID                     int64        `db:"loan_id"`                      // Loan ID, linking to the loans table
+		BorrowerID                 int64        `db:"borrower_id"`                  // Borrower ID
+		BusinessName               string       `db:"business_name"`                // Business name of the borrower
+		BusinessType               string       `db:"business_type"`                // Type of business (e.g., retail, manufacturing)
+		BusinessAddress            string       `db:"business_address"`             // Business address
+		BusinessPhoneNumber        string       `db:"business_phone_number"`        // Business phone number
+		BusinessEmail              string       `db:"business_email"`               // Business email address
+		BusinessRegistrationNumber string       `db:"business_registration_number"` // Business registration number
+		BusinessAnnualRevenue      money.Amount `db:"business_annual_revenue"`      // Annual revenue of the business
+		BusinessExpense            money.Amount `db:"business_expense"`             // Annual expenses of the business
+		BusinessOwnerName          string       `db:"business_owner_name"`          // Business owner's name
+		BusinessDescription        string       `db:"business_description"`         // Business description
+		LoanPurpose                string       `db:"loan_purpose"`                 // Purpose of the loan
+		BusinessAge                int64        `db:"business_age"`                 // Business age (in years)
+		BusinessSector             string       `db:"business_sector"`              // Business sector (e.g., agriculture, technology)
+		CreatedAt                  time.Time    `db:"created_at"`                   // Date of loan detail creation
+		UpdatedAt                  time.Time    `db:"updated_at"`                   // Loan detail update date
+		DeletedAt                  *time.Time   `db:"deleted_at"`                   // Date of loan detail deletion (if applicable)
 	}
 )
 
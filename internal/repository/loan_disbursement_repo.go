@@ -6,6 +6,7 @@ import (
 	"fmt"
 	sq "github.com/Masterminds/squirrel"
 	"github.com/test/loan-service/internal/enum"
+	"github.com/test/loan-service/internal/money"
 	"github.com/typical-go/typical-rest-server/pkg/dbtxn"
 	"go.uber.org/dig"
 	"time"
@@ -24,7 +25,9 @@ type (
 		ID                 int64                       `db:"id"`                   // Disbursement ID
 		LoanID             int64                       `db:"loan_id"`              // Loan ID
 		DisburseCode       string                      `db:"disburse_code"`        // Disbursement code
-		DisburseAmount     float64                     `db:"disburse_amount"`      // Disbursed amount
+		DisburseAmount     money.Amount                `db:"disburse_amount"`      // Disbursed amount
+		OriginationFee     money.Amount                `db:"origination_fee"`      // Origination fee deducted from the disbursed amount
+		NetDisburseAmount  money.Amount                `db:"net_disburse_amount"`  // Amount paid out to the borrower
 		DisbursementStatus enum.LoanDisbursementStatus `db:"disbursement_status"`  // Status (Pending, Completed, etc.)
 		DisburseDate       *time.Time                  `db:"disburse_date"`        // Disbursement date
 		StaffID            *int64                      `db:"staff_id"`             // Staff ID handling the disbursement
@@ -39,6 +42,7 @@ type (
 		Create(context.Context, *LoanDisbursement) (int64, error)
 		Update(ctx context.Context, disbursement *LoanDisbursement) error
 		GetByID(ctx context.Context, disbursementID int64) (*LoanDisbursement, error)
+		GetByLoanID(ctx context.Context, loanID int64) (*LoanDisbursement, error)
 		GetAll(ctx context.Context) ([]LoanDisbursement, error)
 		GetAllPage(ctx context.Context, request LoanDisbursementRequest) ([]LoanDisbursement, int64, error)
 	}
@@ -56,6 +60,8 @@ var (
 		LoanID             string
 		DisburseCode       string
 		DisburseAmount     string
+		OriginationFee     string
+		NetDisburseAmount  string
 		DisbursementStatus string
 		DisburseDate       string
 		StaffID            string
@@ -69,6 +75,8 @@ var (
 		LoanID:             "loan_id",
 		DisburseCode:       "disburse_code",
 		DisburseAmount:     "disburse_amount",
+		OriginationFee:     "origination_fee",
+		NetDisburseAmount:  "net_disburse_amount",
 		DisbursementStatus: "disbursement_status",
 		DisburseDate:       "disburse_date",
 		StaffID:            "staff_id",
@@ -99,6 +107,8 @@ func (r *LoanDisbursementRepoImpl) Create(ctx context.Context, disbursement *Loa
 			LoanDisbursementTable.LoanID,
 			LoanDisbursementTable.DisburseCode,
 			LoanDisbursementTable.DisburseAmount,
+			LoanDisbursementTable.OriginationFee,
+			LoanDisbursementTable.NetDisburseAmount,
 			LoanDisbursementTable.DisbursementStatus,
 			LoanDisbursementTable.DisburseDate,
 			LoanDisbursementTable.StaffID,
@@ -114,6 +124,8 @@ func (r *LoanDisbursementRepoImpl) Create(ctx context.Context, disbursement *Loa
 			disbursement.LoanID,
 			disbursement.DisburseCode,
 			disbursement.DisburseAmount,
+			disbursement.OriginationFee,
+			disbursement.NetDisburseAmount,
 			disbursement.DisbursementStatus,
 			disbursement.DisburseDate,
 			disbursement.StaffID,
@@ -145,6 +157,8 @@ func (r *LoanDisbursementRepoImpl) Update(ctx context.Context, disbursement *Loa
 	// Construct update query
 	builder := sq.Update(LoanDisbursementTableName).
 		Set(LoanDisbursementTable.DisburseAmount, disbursement.DisburseAmount).
+		Set(LoanDisbursementTable.OriginationFee, disbursement.OriginationFee).
+		Set(LoanDisbursementTable.NetDisburseAmount, disbursement.NetDisburseAmount).
 		Set(LoanDisbursementTable.DisbursementStatus, disbursement.DisbursementStatus).
 		Set(LoanDisbursementTable.DisburseDate, disbursement.DisburseDate).
 		Set(LoanDisbursementTable.StaffID, disbursement.StaffID).
@@ -185,6 +199,8 @@ func (r *LoanDisbursementRepoImpl) GetByID(ctx context.Context, disbursementID i
 		LoanDisbursementTable.LoanID,
 		LoanDisbursementTable.DisburseCode,
 		LoanDisbursementTable.DisburseAmount,
+		LoanDisbursementTable.OriginationFee,
+		LoanDisbursementTable.NetDisburseAmount,
 		LoanDisbursementTable.DisbursementStatus,
 		LoanDisbursementTable.DisburseDate,
 		LoanDisbursementTable.StaffID,
@@ -207,6 +223,8 @@ func (r *LoanDisbursementRepoImpl) GetByID(ctx context.Context, disbursementID i
 		&disbursement.LoanID,
 		&disbursement.DisburseCode,
 		&disbursement.DisburseAmount,
+		&disbursement.OriginationFee,
+		&disbursement.NetDisburseAmount,
 		&disbursement.DisbursementStatus,
 		&disbursement.DisburseDate,
 		&disbursement.StaffID,
@@ -222,6 +240,66 @@ func (r *LoanDisbursementRepoImpl) GetByID(ctx context.Context, disbursementID i
 	return &disbursement, nil
 }
 
+// GetByLoanID returns the latest disbursement of a loan, nil when the loan has none
+func (r *LoanDisbursementRepoImpl) GetByLoanID(ctx context.Context, loanID int64) (*LoanDisbursement, error) {
+	txn, err := dbtxn.Use(ctx, r.DB)
+	if err != nil {
+		return nil, err
+	}
+
+	builder := sq.Select(
+		LoanDisbursementTable.ID,
+		LoanDisbursementTable.LoanID,
+		LoanDisbursementTable.DisburseCode,
+		LoanDisbursementTable.DisburseAmount,
+		LoanDisbursementTable.OriginationFee,
+		LoanDisbursementTable.NetDisburseAmount,
+		LoanDisbursementTable.DisbursementStatus,
+		LoanDisbursementTable.DisburseDate,
+		LoanDisbursementTable.StaffID,
+		LoanDisbursementTable.AgreementURL,
+		LoanDisbursementTable.SignedAgreementURL,
+		LoanDisbursementTable.CreatedAt,
+		LoanDisbursementTable.UpdatedAt,
+		LoanDisbursementTable.DeletedAt,
+	).
+		From(LoanDisbursementTableName).
+		Where(sq.Eq{
+			LoanDisbursementTable.LoanID:    loanID,
+			LoanDisbursementTable.DeletedAt: nil,
+		}).
+		OrderBy(LoanDisbursementTable.ID + " DESC").
+		Limit(1).
+		PlaceholderFormat(sq.Dollar)
+
+	var disbursement LoanDisbursement
+	scanner := builder.RunWith(txn).QueryRowContext(ctx)
+
+	if err := scanner.Scan(
+		&disbursement.ID,
+		&disbursement.LoanID,
+		&disbursement.DisburseCode,
+		&disbursement.DisburseAmount,
+		&disbursement.OriginationFee,
+		&disbursement.NetDisburseAmount,
+		&disbursement.DisbursementStatus,
+		&disbursement.DisburseDate,
+		&disbursement.StaffID,
+		&disbursement.AgreementURL,
+		&disbursement.SignedAgreementURL,
+		&disbursement.CreatedAt,
+		&disbursement.UpdatedAt,
+		&disbursement.DeletedAt,
+	); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to scan disbursement: %v", err)
+	}
+
+	return &disbursement, nil
+}
+
 // Get all LoanDisbursements
 func (r *LoanDisbursementRepoImpl) GetAll(ctx context.Context) ([]LoanDisbursement, error) {
 	txn, err := dbtxn.Use(ctx, r.DB)
@@ -235,6 +313,8 @@ func (r *LoanDisbursementRepoImpl) GetAll(ctx context.Context) ([]LoanDisburseme
 		LoanDisbursementTable.LoanID,
 		LoanDisbursementTable.DisburseCode,
 		LoanDisbursementTable.DisburseAmount,
+		LoanDisbursementTable.OriginationFee,
+		LoanDisbursementTable.NetDisburseAmount,
 		LoanDisbursementTable.DisbursementStatus,
 		LoanDisbursementTable.DisburseDate,
 		LoanDisbursementTable.StaffID,
@@ -262,6 +342,8 @@ func (r *LoanDisbursementRepoImpl) GetAll(ctx context.Context) ([]LoanDisburseme
 			&disbursement.LoanID,
 			&disbursement.DisburseCode,
 			&disbursement.DisburseAmount,
+			&disbursement.OriginationFee,
+			&disbursement.NetDisburseAmount,
 			&disbursement.DisbursementStatus,
 			&disbursement.DisburseDate,
 			&disbursement.StaffID,
@@ -305,6 +387,8 @@ func (r *LoanDisbursementRepoImpl) GetAllPage(ctx context.Context, request LoanD
 		LoanDisbursementTable.LoanID,
 		LoanDisbursementTable.DisburseCode,
 		LoanDisbursementTable.DisburseAmount,
+		LoanDisbursementTable.OriginationFee,
+		LoanDisbursementTable.NetDisburseAmount,
 		LoanDisbursementTable.DisbursementStatus,
 		LoanDisbursementTable.DisburseDate,
 		LoanDisbursementTable.StaffID,
@@ -339,6 +423,8 @@ func (r *LoanDisbursementRepoImpl) GetAllPage(ctx context.Context, request LoanD
 			&disbursement.LoanID,
 			&disbursement.DisburseCode,
 			&disbursement.DisburseAmount,
+			&disbursement.OriginationFee,
+			&disbursement.NetDisburseAmount,
 			&disbursement.DisbursementStatus,
 			&disbursement.DisburseDate,
 			&disbursement.StaffID,
@@ -6,6 +6,7 @@ import (
 	"fmt"
 	sq "github.com/Masterminds/squirrel"
 	"github.com/test/loan-service/internal/enum"
+	"github.com/test/loan-service/internal/money"
 	"github.com/typical-go/typical-rest-server/pkg/dbtxn"
 	"go.uber.org/dig"
 	"time"
@@ -16,25 +17,33 @@ import (
 // LoanFunding represents the structure of the loan funding records
 type (
 	LoanFunding struct {
-		ID                 int64                  `db:"id"`
-		LoanOrderNumber    string                 `db:"loan_order_number"`
-		OrderNumber        string                 `db:"order_number"`
-		LoanID             int64                  `db:"loan_id"`
-		LenderID           int64                  `db:"lender_id"`
-		LenderEmail        string                 `db:"lender_email"`
-		InvestmentAmount   float64                `db:"investment_amount"`
-		Rate               float64                `db:"rate"`
-		Interest           float64                `db:"interest"`
-		ROI                float64                `db:"roi"`
-		InterestPaid       float64                `db:"interest_paid"`
-		CapitalAmountPaid  float64                `db:"capital_amount_paid"`
-		TotalAmountPaid    float64                `db:"total_amount_paid"`
-		InvestmentDate     time.Time              `db:"investment_date"`
-		Status             enum.LoanFundingStatus `db:"status"`
-		LenderAgreementURL string                 `db:"lender_agreement_url"`
-		CreatedAt          time.Time              `db:"created_at"`
-		UpdatedAt          time.Time              `db:"updated_at"`
-		DeletedAt          *time.Time             `db:"deleted_at"`
+		ID                   int64                  `db:"id"`
+		LoanOrderNumber      string                 `db:"loan_order_number"`
+		OrderNumber          string                 `db:"order_number"`
+		LoanID               int64                  `db:"loan_id"`
+		LenderID             int64                  `db:"lender_id"`
+		LenderEmail          string                 `db:"lender_email"`
+		InvestmentAmount     money.Amount           `db:"investment_amount"`
+		Rate                 float64                `db:"rate"`
+		Interest             money.Amount           `db:"interest"`
+		ROI                  money.Amount           `db:"roi"`
+		InterestPaid         money.Amount           `db:"interest_paid"`
+		CapitalAmountPaid    money.Amount           `db:"capital_amount_paid"`
+		TotalAmountPaid      money.Amount           `db:"total_amount_paid"`
+		ServiceFeePercentage float64                `db:"service_fee_percentage"`
+		ServiceFee           money.Amount           `db:"service_fee"`
+		ServiceFeePaid       money.Amount           `db:"service_fee_paid"`
+		WithholdingTax       money.Amount           `db:"withholding_tax"`
+		WithholdingTaxPaid   money.Amount           `db:"withholding_tax_paid"`
+		TransferredAmount    money.Amount           `db:"transferred_amount"`
+		TransferredFromID    *int64                 `db:"transferred_from_id"`
+		InvestmentDate       time.Time              `db:"investment_date"`
+		InvestedAt           *time.Time             `db:"invested_at"`
+		Status               enum.LoanFundingStatus `db:"status"`
+		LenderAgreementURL   string                 `db:"lender_agreement_url"`
+		CreatedAt            time.Time              `db:"created_at"`
+		UpdatedAt            time.Time              `db:"updated_at"`
+		DeletedAt            *time.Time             `db:"deleted_at"`
 	}
 )
 
@@ -42,6 +51,7 @@ type LoanFundingRepo interface {
 	Create(ctx context.Context, loanFunding *LoanFunding) (int64, error)
 	Update(ctx context.Context, loanFunding *LoanFunding) error
 	GetByID(ctx context.Context, id int64) (*LoanFunding, error)
+	GetByIDForUpdate(ctx context.Context, id int64) (*LoanFunding, error)
 	GetByLoanID(ctx context.Context, loanID int64) ([]LoanFunding, error)
 	GetByLoanOrderNumber(ctx context.Context, loanOrderNumber string) (*LoanFunding, error)
 	GetByLenderID(ctx context.Context, lenderID int64) ([]LoanFunding, error)
@@ -60,45 +70,61 @@ func NewLoanFundingRepo(impl LoanFundingRepoImpl) LoanFundingRepo {
 var (
 	LoanFundingTableName = "loan_funding"
 	LoanFundingTable     = struct {
-		ID                 string
-		LoanOrderNumber    string
-		OrderNumber        string
-		LoanID             string
-		LenderID           string
-		LenderEmail        string
-		InvestmentAmount   string
-		Rate               string
-		Interest           string
-		ROI                string
-		InterestPaid       string
-		CapitalAmountPaid  string
-		TotalAmountPaid    string
-		InvestmentDate     string
-		Status             string
-		LenderAgreementURL string
-		CreatedAt          string
-		UpdatedAt          string
-		DeletedAt          string
+		ID                   string
+		LoanOrderNumber      string
+		OrderNumber          string
+		LoanID               string
+		LenderID             string
+		LenderEmail          string
+		InvestmentAmount     string
+		Rate                 string
+		Interest             string
+		ROI                  string
+		InterestPaid         string
+		CapitalAmountPaid    string
+		TotalAmountPaid      string
+		ServiceFeePercentage string
+		ServiceFee           string
+		ServiceFeePaid       string
+		WithholdingTax       string
+		WithholdingTaxPaid   string
+		TransferredAmount    string
+		TransferredFromID    string
+		InvestmentDate       string
+		InvestedAt           string
+		Status               string
+		LenderAgreementURL   string
+		CreatedAt            string
+		UpdatedAt            string
+		DeletedAt            string
 	}{
-		ID:                 "id",
-		LoanOrderNumber:    "loan_order_number",
-		OrderNumber:        "order_number",
-		LoanID:             "loan_id",
-		LenderID:           "lender_id",
-		LenderEmail:        "lender_email",
-		InvestmentAmount:   "investment_amount",
-		Rate:               "rate",
-		Interest:           "interest",
-		ROI:                "roi",
-		InterestPaid:       "interest_paid",
-		CapitalAmountPaid:  "capital_amount_paid",
-		TotalAmountPaid:    "total_amount_paid",
-		InvestmentDate:     "investment_date",
-		Status:             "status",
-		LenderAgreementURL: "lender_agreement_url",
-		CreatedAt:          "created_at",
-		UpdatedAt:          "updated_at",
-		DeletedAt:          "deleted_at",
+		ID:                   "id",
+		LoanOrderNumber:      "loan_order_number",
+		OrderNumber:          "order_number",
+		LoanID:               "loan_id",
+		LenderID:             "lender_id",
+		LenderEmail:          "lender_email",
+		InvestmentAmount:     "investment_amount",
+		Rate:                 "rate",
+		Interest:             "interest",
+		ROI:                  "roi",
+		InterestPaid:         "interest_paid",
+		CapitalAmountPaid:    "capital_amount_paid",
+		TotalAmountPaid:      "total_amount_paid",
+		ServiceFeePercentage: "service_fee_percentage",
+		ServiceFee:           "service_fee",
+		ServiceFeePaid:       "service_fee_paid",
+		WithholdingTax:       "withholding_tax",
+		WithholdingTaxPaid:   "withholding_tax_paid",
+		TransferredAmount:    "transferred_amount",
+		TransferredFromID:    "transferred_from_id",
+		InvestmentDate:       "investment_date",
+		InvestedAt:           "invested_at",
+		Status:               "status",
+		LenderAgreementURL:   "lender_agreement_url",
+		CreatedAt:            "created_at",
+		UpdatedAt:            "updated_at",
+		DeletedAt:            "deleted_at",
 	}
 )
 
@@ -124,7 +150,15 @@ func (r *LoanFundingRepoImpl) Create(ctx context.Context, loanFunding *LoanFundi
 			LoanFundingTable.InterestPaid,
 			LoanFundingTable.CapitalAmountPaid,
 			LoanFundingTable.TotalAmountPaid,
+			LoanFundingTable.ServiceFeePercentage,
+			LoanFundingTable.ServiceFee,
+			LoanFundingTable.ServiceFeePaid,
+			LoanFundingTable.WithholdingTax,
+			LoanFundingTable.WithholdingTaxPaid,
+			LoanFundingTable.TransferredAmount,
+			LoanFundingTable.TransferredFromID,
 			LoanFundingTable.InvestmentDate,
+			LoanFundingTable.InvestedAt,
 			LoanFundingTable.Status,
 			LoanFundingTable.LenderAgreementURL,
 			LoanFundingTable.CreatedAt,
@@ -146,7 +180,15 @@ func (r *LoanFundingRepoImpl) Create(ctx context.Context, loanFunding *LoanFundi
 			loanFunding.InterestPaid,
 			loanFunding.CapitalAmountPaid,
 			loanFunding.TotalAmountPaid,
+			loanFunding.ServiceFeePercentage,
+			loanFunding.ServiceFee,
+			loanFunding.ServiceFeePaid,
+			loanFunding.WithholdingTax,
+			loanFunding.WithholdingTaxPaid,
+			loanFunding.TransferredAmount,
+			loanFunding.TransferredFromID,
 			loanFunding.InvestmentDate,
+			loanFunding.InvestedAt,
 			loanFunding.Status,
 			loanFunding.LenderAgreementURL,
 			loanFunding.CreatedAt,
@@ -179,8 +221,16 @@ func (r *LoanFundingRepoImpl) Update(ctx context.Context, loanFunding *LoanFundi
 		Set(LoanFundingTable.InterestPaid, loanFunding.InterestPaid).
 		Set(LoanFundingTable.CapitalAmountPaid, loanFunding.CapitalAmountPaid).
 		Set(LoanFundingTable.TotalAmountPaid, loanFunding.TotalAmountPaid).
+		Set(LoanFundingTable.ServiceFeePercentage, loanFunding.ServiceFeePercentage).
+		Set(LoanFundingTable.ServiceFee, loanFunding.ServiceFee).
+		Set(LoanFundingTable.ServiceFeePaid, loanFunding.ServiceFeePaid).
+		Set(LoanFundingTable.WithholdingTax, loanFunding.WithholdingTax).
+		Set(LoanFundingTable.WithholdingTaxPaid, loanFunding.WithholdingTaxPaid).
+		Set(LoanFundingTable.TransferredAmount, loanFunding.TransferredAmount).
 		Set(LoanFundingTable.InvestmentDate, loanFunding.InvestmentDate).
+		Set(LoanFundingTable.InvestedAt, loanFunding.InvestedAt).
 		Set(LoanFundingTable.Status, loanFunding.Status).
+		Set(LoanFundingTable.LenderAgreementURL, loanFunding.LenderAgreementURL).
 		Set(LoanFundingTable.UpdatedAt, time.Now()). // Update the `UpdatedAt` field
 		Where(sq.Eq{LoanFundingTable.ID: loanFunding.ID}).
 		PlaceholderFormat(sq.Dollar)
@@ -225,7 +275,15 @@ func (r *LoanFundingRepoImpl) GetByLoanOrderNumber(ctx context.Context, loanOrde
 			LoanFundingTable.InterestPaid,
 			LoanFundingTable.CapitalAmountPaid,
 			LoanFundingTable.TotalAmountPaid,
+			LoanFundingTable.ServiceFeePercentage,
+			LoanFundingTable.ServiceFee,
+			LoanFundingTable.ServiceFeePaid,
+			LoanFundingTable.WithholdingTax,
+			LoanFundingTable.WithholdingTaxPaid,
+			LoanFundingTable.TransferredAmount,
+			LoanFundingTable.TransferredFromID,
 			LoanFundingTable.InvestmentDate,
+			LoanFundingTable.InvestedAt,
 			LoanFundingTable.Status,
 			LoanFundingTable.LenderAgreementURL,
 			LoanFundingTable.CreatedAt,
@@ -251,7 +309,15 @@ func (r *LoanFundingRepoImpl) GetByLoanOrderNumber(ctx context.Context, loanOrde
 		&loanFunding.InterestPaid,
 		&loanFunding.CapitalAmountPaid,
 		&loanFunding.TotalAmountPaid,
+		&loanFunding.ServiceFeePercentage,
+		&loanFunding.ServiceFee,
+		&loanFunding.ServiceFeePaid,
+		&loanFunding.WithholdingTax,
+		&loanFunding.WithholdingTaxPaid,
+		&loanFunding.TransferredAmount,
+		&loanFunding.TransferredFromID,
 		&loanFunding.InvestmentDate,
+		&loanFunding.InvestedAt,
 		&loanFunding.Status,
 		&loanFunding.LenderAgreementURL,
 		&loanFunding.CreatedAt,
@@ -287,7 +353,15 @@ func (r *LoanFundingRepoImpl) GetByID(ctx context.Context, id int64) (*LoanFundi
 			LoanFundingTable.InterestPaid,
 			LoanFundingTable.CapitalAmountPaid,
 			LoanFundingTable.TotalAmountPaid,
+			LoanFundingTable.ServiceFeePercentage,
+			LoanFundingTable.ServiceFee,
+			LoanFundingTable.ServiceFeePaid,
+			LoanFundingTable.WithholdingTax,
+			LoanFundingTable.WithholdingTaxPaid,
+			LoanFundingTable.TransferredAmount,
+			LoanFundingTable.TransferredFromID,
 			LoanFundingTable.InvestmentDate,
+			LoanFundingTable.InvestedAt,
 			LoanFundingTable.Status,
 			LoanFundingTable.LenderAgreementURL,
 			LoanFundingTable.CreatedAt,
@@ -313,7 +387,96 @@ func (r *LoanFundingRepoImpl) GetByID(ctx context.Context, id int64) (*LoanFundi
 		&loanFunding.InterestPaid,
 		&loanFunding.CapitalAmountPaid,
 		&loanFunding.TotalAmountPaid,
+		&loanFunding.ServiceFeePercentage,
+		&loanFunding.ServiceFee,
+		&loanFunding.ServiceFeePaid,
+		&loanFunding.WithholdingTax,
+		&loanFunding.WithholdingTaxPaid,
+		&loanFunding.TransferredAmount,
+		&loanFunding.TransferredFromID,
 		&loanFunding.InvestmentDate,
+		&loanFunding.InvestedAt,
+		&loanFunding.Status,
+		&loanFunding.LenderAgreementURL,
+		&loanFunding.CreatedAt,
+		&loanFunding.UpdatedAt,
+		&loanFunding.DeletedAt,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute query: %v", err)
+	}
+
+	return &loanFunding, nil
+}
+
+// GetByIDForUpdate get loan funding by ID and lock the row until the transaction ends
+func (r *LoanFundingRepoImpl) GetByIDForUpdate(ctx context.Context, id int64) (*LoanFunding, error) {
+	// row lock only holds when ctx carries a transaction
+	txn, err := dbtxn.Use(ctx, r.DB)
+	if err != nil {
+		return nil, err
+	}
+
+	// Build the query to get and lock loan funding by ID
+	builder := sq.
+		Select(
+			LoanFundingTable.ID,
+			LoanFundingTable.LoanOrderNumber,
+			LoanFundingTable.OrderNumber,
+			LoanFundingTable.LoanID,
+			LoanFundingTable.LenderID,
+			LoanFundingTable.LenderEmail,
+			LoanFundingTable.InvestmentAmount,
+			LoanFundingTable.Rate,
+			LoanFundingTable.Interest,
+			LoanFundingTable.ROI,
+			LoanFundingTable.InterestPaid,
+			LoanFundingTable.CapitalAmountPaid,
+			LoanFundingTable.TotalAmountPaid,
+			LoanFundingTable.ServiceFeePercentage,
+			LoanFundingTable.ServiceFee,
+			LoanFundingTable.ServiceFeePaid,
+			LoanFundingTable.WithholdingTax,
+			LoanFundingTable.WithholdingTaxPaid,
+			LoanFundingTable.TransferredAmount,
+			LoanFundingTable.TransferredFromID,
+			LoanFundingTable.InvestmentDate,
+			LoanFundingTable.InvestedAt,
+			LoanFundingTable.Status,
+			LoanFundingTable.LenderAgreementURL,
+			LoanFundingTable.CreatedAt,
+			LoanFundingTable.UpdatedAt,
+			LoanFundingTable.DeletedAt,
+		).
+		From(LoanFundingTableName).
+		Where(sq.Eq{LoanFundingTable.ID: id}).
+		Suffix("FOR UPDATE").
+		PlaceholderFormat(sq.Dollar)
+
+	var loanFunding LoanFunding
+	err = builder.RunWith(txn).QueryRowContext(ctx).Scan(
+		&loanFunding.ID,
+		&loanFunding.LoanOrderNumber,
+		&loanFunding.OrderNumber,
+		&loanFunding.LoanID,
+		&loanFunding.LenderID,
+		&loanFunding.LenderEmail,
+		&loanFunding.InvestmentAmount,
+		&loanFunding.Rate,
+		&loanFunding.Interest,
+		&loanFunding.ROI,
+		&loanFunding.InterestPaid,
+		&loanFunding.CapitalAmountPaid,
+		&loanFunding.TotalAmountPaid,
+		&loanFunding.ServiceFeePercentage,
+		&loanFunding.ServiceFee,
+		&loanFunding.ServiceFeePaid,
+		&loanFunding.WithholdingTax,
+		&loanFunding.WithholdingTaxPaid,
+		&loanFunding.TransferredAmount,
+		&loanFunding.TransferredFromID,
+		&loanFunding.InvestmentDate,
+		&loanFunding.InvestedAt,
 		&loanFunding.Status,
 		&loanFunding.LenderAgreementURL,
 		&loanFunding.CreatedAt,
@@ -349,7 +512,15 @@ func (r *LoanFundingRepoImpl) GetByLoanID(ctx context.Context, loanID int64) ([]
 			LoanFundingTable.InterestPaid,
 			LoanFundingTable.CapitalAmountPaid,
 			LoanFundingTable.TotalAmountPaid,
+			LoanFundingTable.ServiceFeePercentage,
+			LoanFundingTable.ServiceFee,
+			LoanFundingTable.ServiceFeePaid,
+			LoanFundingTable.WithholdingTax,
+			LoanFundingTable.WithholdingTaxPaid,
+			LoanFundingTable.TransferredAmount,
+			LoanFundingTable.TransferredFromID,
 			LoanFundingTable.InvestmentDate,
+			LoanFundingTable.InvestedAt,
 			LoanFundingTable.Status,
 			LoanFundingTable.LenderAgreementURL,
 			LoanFundingTable.CreatedAt,
@@ -383,7 +554,17 @@ func (r *LoanFundingRepoImpl) GetByLoanID(ctx context.Context, loanID int64) ([]
 			&loanFunding.InterestPaid,
 			&loanFunding.CapitalAmountPaid,
 			&loanFunding.TotalAmountPaid,
+			&loanFunding.ServiceFeePercentage,
+			&loanFunding.ServiceFee,
+			&loanFunding.ServiceFeePaid,
+			&loanFunding.WithholdingTax,
+			&loanFunding.WithholdingTaxPaid,
+			&loanFunding.TransferredAmount,
+			&loanFunding.TransferredFromID,
+			&loanFunding.TransferredAmount,
+			&loanFunding.TransferredFromID,
 			&loanFunding.InvestmentDate,
+			&loanFunding.InvestedAt,
 			&loanFunding.Status,
 			&loanFunding.LenderAgreementURL,
 			&loanFunding.CreatedAt,
@@ -424,7 +605,15 @@ func (r *LoanFundingRepoImpl) GetByLenderID(ctx context.Context, lenderID int64)
 			LoanFundingTable.InterestPaid,
 			LoanFundingTable.CapitalAmountPaid,
 			LoanFundingTable.TotalAmountPaid,
+			LoanFundingTable.ServiceFeePercentage,
+			LoanFundingTable.ServiceFee,
+			LoanFundingTable.ServiceFeePaid,
+			LoanFundingTable.WithholdingTax,
+			LoanFundingTable.WithholdingTaxPaid,
+			LoanFundingTable.TransferredAmount,
+			LoanFundingTable.TransferredFromID,
 			LoanFundingTable.InvestmentDate,
+			LoanFundingTable.InvestedAt,
 			LoanFundingTable.Status,
 			LoanFundingTable.LenderAgreementURL,
 			LoanFundingTable.CreatedAt,
@@ -458,7 +647,17 @@ func (r *LoanFundingRepoImpl) GetByLenderID(ctx context.Context, lenderID int64)
 			&loanFunding.InterestPaid,
 			&loanFunding.CapitalAmountPaid,
 			&loanFunding.TotalAmountPaid,
+			&loanFunding.ServiceFeePercentage,
+			&loanFunding.ServiceFee,
+			&loanFunding.ServiceFeePaid,
+			&loanFunding.WithholdingTax,
+			&loanFunding.WithholdingTaxPaid,
+			&loanFunding.TransferredAmount,
+			&loanFunding.TransferredFromID,
+			&loanFunding.TransferredAmount,
+			&loanFunding.TransferredFromID,
 			&loanFunding.InvestmentDate,
+			&loanFunding.InvestedAt,
 			&loanFunding.Status,
 			&loanFunding.LenderAgreementURL,
 			&loanFunding.CreatedAt,
@@ -0,0 +1,349 @@
+package repo
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	sq "github.com/Masterminds/squirrel"
+	"github.com/test/loan-service/internal/enum"
+	"github.com/typical-go/typical-rest-server/pkg/dbtxn"
+	"go.uber.org/dig"
+	"time"
+)
+
+//go:generate mockgen -source=$GOFILE -destination=$PROJ/internal/generated/mock/mock_$GOPACKAGE/$GOFILE
+
+type (
+	LoanPricingRequest struct {
+		LoanGrade enum.LoanGrade
+		LoanType  enum.LoanType
+	}
+
+	LoanPricing struct {
+		ID           int64          `db:"id"`            // Pricing ID, the pricing version applied to a loan
+		LoanGrade    enum.LoanGrade `db:"loan_grade"`    // Loan grade the pricing applies to
+		LoanType     enum.LoanType  `db:"loan_type"`     // Loan type the pricing applies to
+		MinTenure    int64          `db:"min_tenure"`    // Inclusive lower bound of the tenure band in months
+		MaxTenure    int64          `db:"max_tenure"`    // Inclusive upper bound of the tenure band in months
+		BorrowerRate float64        `db:"borrower_rate"` // Annual interest rate charged to the borrower
+		LenderYield  float64        `db:"lender_yield"`  // Annual yield paid to lenders
+		ValidFrom    time.Time      `db:"valid_from"`    // Date the pricing takes effect
+		ValidUntil   *time.Time     `db:"valid_until"`   // Date the pricing stops being applied, nil when open ended
+		CreatedAt    time.Time      `db:"created_at"`    // Date of creation
+		UpdatedAt    time.Time      `db:"updated_at"`    // Date of last update
+		DeletedAt    *time.Time     `db:"deleted_at"`    // Date of deletion if applicable
+	}
+
+	LoanPricingRepo interface {
+		Create(ctx context.Context, pricing *LoanPricing) (int64, error)
+		Update(ctx context.Context, pricing *LoanPricing) error
+		Delete(ctx context.Context, id int64) error
+		GetByID(ctx context.Context, id int64) (*LoanPricing, error)
+		GetAll(ctx context.Context, request LoanPricingRequest) ([]LoanPricing, error)
+		GetApplicable(ctx context.Context, grade enum.LoanGrade, loanType enum.LoanType, tenure int64, at time.Time) (*LoanPricing, error)
+	}
+
+	LoanPricingRepoImpl struct {
+		dig.In
+		*sql.DB
+	}
+)
+
+var (
+	LoanPricingTableName = "loan_pricings"
+	LoanPricingTable     = struct {
+		ID           string
+		LoanGrade    string
+		LoanType     string
+		MinTenure    string
+		MaxTenure    string
+		BorrowerRate string
+		LenderYield  string
+		ValidFrom    string
+		ValidUntil   string
+		CreatedAt    string
+		UpdatedAt    string
+		DeletedAt    string
+	}{
+		ID:           "id",
+		LoanGrade:    "loan_grade",
+		LoanType:     "loan_type",
+		MinTenure:    "min_tenure",
+		MaxTenure:    "max_tenure",
+		BorrowerRate: "borrower_rate",
+		LenderYield:  "lender_yield",
+		ValidFrom:    "valid_from",
+		ValidUntil:   "valid_until",
+		CreatedAt:    "created_at",
+		UpdatedAt:    "updated_at",
+		DeletedAt:    "deleted_at",
+	}
+)
+
+func NewLoanPricingRepo(impl LoanPricingRepoImpl) LoanPricingRepo {
+	return &impl
+}
+
+// Create LoanPricing and return last inserted id
+func (r *LoanPricingRepoImpl) Create(ctx context.Context, pricing *LoanPricing) (int64, error) {
+	txn, err := dbtxn.Use(ctx, r.DB)
+	if err != nil {
+		return -1, err
+	}
+
+	builder := sq.
+		Insert(LoanPricingTableName).
+		Columns(
+			LoanPricingTable.LoanGrade,
+			LoanPricingTable.LoanType,
+			LoanPricingTable.MinTenure,
+			LoanPricingTable.MaxTenure,
+			LoanPricingTable.BorrowerRate,
+			LoanPricingTable.LenderYield,
+			LoanPricingTable.ValidFrom,
+			LoanPricingTable.ValidUntil,
+			LoanPricingTable.CreatedAt,
+			LoanPricingTable.UpdatedAt,
+			LoanPricingTable.DeletedAt,
+		).
+		Suffix("RETURNING id").
+		PlaceholderFormat(sq.Dollar).
+		Values(
+			pricing.LoanGrade,
+			pricing.LoanType,
+			pricing.MinTenure,
+			pricing.MaxTenure,
+			pricing.BorrowerRate,
+			pricing.LenderYield,
+			pricing.ValidFrom,
+			pricing.ValidUntil,
+			time.Now(),
+			time.Now(),
+			nil,
+		)
+
+	scanner := builder.RunWith(txn).QueryRowContext(ctx)
+
+	var id int64
+	if err := scanner.Scan(&id); err != nil {
+		return -1, fmt.Errorf("failed to scan id: %v", err)
+	}
+
+	return id, nil
+}
+
+// Update LoanPricing
+func (r *LoanPricingRepoImpl) Update(ctx context.Context, pricing *LoanPricing) error {
+	txn, err := dbtxn.Use(ctx, r.DB)
+	if err != nil {
+		return err
+	}
+
+	builder := sq.Update(LoanPricingTableName).
+		Set(LoanPricingTable.LoanGrade, pricing.LoanGrade).
+		Set(LoanPricingTable.LoanType, pricing.LoanType).
+		Set(LoanPricingTable.MinTenure, pricing.MinTenure).
+		Set(LoanPricingTable.MaxTenure, pricing.MaxTenure).
+		Set(LoanPricingTable.BorrowerRate, pricing.BorrowerRate).
+		Set(LoanPricingTable.LenderYield, pricing.LenderYield).
+		Set(LoanPricingTable.ValidFrom, pricing.ValidFrom).
+		Set(LoanPricingTable.ValidUntil, pricing.ValidUntil).
+		Set(LoanPricingTable.UpdatedAt, time.Now()).
+		Where(sq.Eq{
+			LoanPricingTable.ID:        pricing.ID,
+			LoanPricingTable.DeletedAt: nil,
+		}).
+		PlaceholderFormat(sq.Dollar)
+
+	result, err := builder.RunWith(txn).ExecContext(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to update loan pricing: %v", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %v", err)
+	}
+	if rowsAffected == 0 {
+		return fmt.Errorf("no loan pricing found with ID: %d", pricing.ID)
+	}
+
+	return nil
+}
+
+// Delete soft deletes a LoanPricing
+func (r *LoanPricingRepoImpl) Delete(ctx context.Context, id int64) error {
+	txn, err := dbtxn.Use(ctx, r.DB)
+	if err != nil {
+		return err
+	}
+
+	builder := sq.Update(LoanPricingTableName).
+		Set(LoanPricingTable.UpdatedAt, time.Now()).
+		Set(LoanPricingTable.DeletedAt, time.Now()).
+		Where(sq.Eq{
+			LoanPricingTable.ID:        id,
+			LoanPricingTable.DeletedAt: nil,
+		}).
+		PlaceholderFormat(sq.Dollar)
+
+	result, err := builder.RunWith(txn).ExecContext(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to delete loan pricing: %v", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %v", err)
+	}
+	if rowsAffected == 0 {
+		return fmt.Errorf("no loan pricing found with ID: %d", id)
+	}
+
+	return nil
+}
+
+// GetByID returns the pricing, nil when it does not exist
+func (r *LoanPricingRepoImpl) GetByID(ctx context.Context, id int64) (*LoanPricing, error) {
+	txn, err := dbtxn.Use(ctx, r.DB)
+	if err != nil {
+		return nil, err
+	}
+
+	builder := r.selectBuilder().
+		Where(sq.Eq{
+			LoanPricingTable.ID:        id,
+			LoanPricingTable.DeletedAt: nil,
+		})
+
+	var pricing LoanPricing
+	scanner := builder.RunWith(txn).QueryRowContext(ctx)
+	if err := scanner.Scan(r.scanDest(&pricing)...); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to scan loan pricing: %v", err)
+	}
+
+	return &pricing, nil
+}
+
+// GetAll returns the pricings matching the request ordered by grade, loan type, tenure band then
+// the most recent version first
+func (r *LoanPricingRepoImpl) GetAll(ctx context.Context, request LoanPricingRequest) ([]LoanPricing, error) {
+	txn, err := dbtxn.Use(ctx, r.DB)
+	if err != nil {
+		return nil, err
+	}
+
+	builder := r.selectBuilder().
+		Where(sq.Eq{LoanPricingTable.DeletedAt: nil}).
+		OrderBy(
+			LoanPricingTable.LoanGrade+" ASC",
+			LoanPricingTable.LoanType+" ASC",
+			LoanPricingTable.MinTenure+" ASC",
+			LoanPricingTable.ValidFrom+" DESC",
+			LoanPricingTable.ID+" DESC",
+		)
+
+	if request.LoanGrade != "" {
+		builder = builder.Where(sq.Eq{LoanPricingTable.LoanGrade: request.LoanGrade})
+	}
+	if request.LoanType != "" {
+		builder = builder.Where(sq.Eq{LoanPricingTable.LoanType: request.LoanType})
+	}
+
+	rows, err := builder.RunWith(txn).QueryContext(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute query: %v", err)
+	}
+	defer rows.Close()
+
+	var pricings []LoanPricing
+	for rows.Next() {
+		var pricing LoanPricing
+		if err := rows.Scan(r.scanDest(&pricing)...); err != nil {
+			return nil, fmt.Errorf("failed to scan row: %v", err)
+		}
+		pricings = append(pricings, pricing)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate over rows: %v", err)
+	}
+
+	return pricings, nil
+}
+
+// GetApplicable returns the pricing of the grade, loan type and tenure in effect at the given time,
+// nil when none is. When versions overlap the one that took effect last wins.
+func (r *LoanPricingRepoImpl) GetApplicable(ctx context.Context, grade enum.LoanGrade, loanType enum.LoanType, tenure int64, at time.Time) (*LoanPricing, error) {
+	txn, err := dbtxn.Use(ctx, r.DB)
+	if err != nil {
+		return nil, err
+	}
+
+	builder := r.selectBuilder().
+		Where(sq.Eq{
+			LoanPricingTable.LoanGrade: grade,
+			LoanPricingTable.LoanType:  loanType,
+			LoanPricingTable.DeletedAt: nil,
+		}).
+		Where(sq.LtOrEq{LoanPricingTable.MinTenure: tenure}).
+		Where(sq.GtOrEq{LoanPricingTable.MaxTenure: tenure}).
+		Where(sq.LtOrEq{LoanPricingTable.ValidFrom: at}).
+		Where(sq.Or{
+			sq.Eq{LoanPricingTable.ValidUntil: nil},
+			sq.Gt{LoanPricingTable.ValidUntil: at},
+		}).
+		OrderBy(LoanPricingTable.ValidFrom+" DESC", LoanPricingTable.ID+" DESC").
+		Limit(1)
+
+	var pricing LoanPricing
+	scanner := builder.RunWith(txn).QueryRowContext(ctx)
+	if err := scanner.Scan(r.scanDest(&pricing)...); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to scan loan pricing: %v", err)
+	}
+
+	return &pricing, nil
+}
+
+func (r *LoanPricingRepoImpl) selectBuilder() sq.SelectBuilder {
+	return sq.
+		Select(
+			LoanPricingTable.ID,
+			LoanPricingTable.LoanGrade,
+			LoanPricingTable.LoanType,
+			LoanPricingTable.MinTenure,
+			LoanPricingTable.MaxTenure,
+			LoanPricingTable.BorrowerRate,
+			LoanPricingTable.LenderYield,
+			LoanPricingTable.ValidFrom,
+			LoanPricingTable.ValidUntil,
+			LoanPricingTable.CreatedAt,
+			LoanPricingTable.UpdatedAt,
+			LoanPricingTable.DeletedAt,
+		).
+		From(LoanPricingTableName).
+		PlaceholderFormat(sq.Dollar)
+}
+
+func (r *LoanPricingRepoImpl) scanDest(pricing *LoanPricing) []interface{} {
+	return []interface{}{
+		&pricing.ID,
+		&pricing.LoanGrade,
+		&pricing.LoanType,
+		&pricing.MinTenure,
+		&pricing.MaxTenure,
+		&pricing.BorrowerRate,
+		&pricing.LenderYield,
+		&pricing.ValidFrom,
+		&pricing.ValidUntil,
+		&pricing.CreatedAt,
+		&pricing.UpdatedAt,
+		&pricing.DeletedAt,
+	}
+}
@@ -0,0 +1,205 @@
+package repo
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	sq "github.com/Masterminds/squirrel"
+	"github.com/test/loan-service/internal/money"
+	"github.com/typical-go/typical-rest-server/pkg/dbtxn"
+	"go.uber.org/dig"
+	"time"
+)
+
+//go:generate mockgen -source=$GOFILE -destination=$PROJ/internal/generated/mock/mock_$GOPACKAGE/$GOFILE
+
+type (
+	LoanRepayment struct {
+		ID              int64        `db:"id"`               // Repayment ID
+		LoanID          int64        `db:"loan_id"`          // Loan ID
+		RepaymentCode   string       `db:"repayment_code"`   // Repayment code
+		ReferenceNumber string       `db:"reference_number"` // Payment reference from the payment channel
+		Amount          money.Amount `db:"amount"`           // Amount paid by the borrower
+		PrincipalAmount money.Amount `db:"principal_amount"` // Portion allocated to principal
+		InterestAmount  money.Amount `db:"interest_amount"`  // Portion allocated to interest
+		PaymentDate     time.Time    `db:"payment_date"`     // Date the borrower paid
+		CreatedAt       time.Time    `db:"created_at"`       // Date of creation
+		UpdatedAt       time.Time    `db:"updated_at"`       // Date of last update
+		DeletedAt       *time.Time   `db:"deleted_at"`       // Date of deletion if applicable
+	}
+
+	LoanRepaymentRepo interface {
+		Create(ctx context.Context, repayment *LoanRepayment) (int64, error)
+		GetByLoanID(ctx context.Context, loanID int64) ([]LoanRepayment, error)
+		GetTotalAmountByLoanID(ctx context.Context, loanID int64) (money.Amount, error)
+	}
+
+	LoanRepaymentRepoImpl struct {
+		dig.In
+		*sql.DB
+	}
+)
+
+var (
+	LoanRepaymentTableName = "loan_repayments"
+	LoanRepaymentTable     = struct {
+		ID              string
+		LoanID          string
+		RepaymentCode   string
+		ReferenceNumber string
+		Amount          string
+		PrincipalAmount string
+		InterestAmount  string
+		PaymentDate     string
+		CreatedAt       string
+		UpdatedAt       string
+		DeletedAt       string
+	}{
+		ID:              "id",
+		LoanID:          "loan_id",
+		RepaymentCode:   "repayment_code",
+		ReferenceNumber: "reference_number",
+		Amount:          "amount",
+		PrincipalAmount: "principal_amount",
+		InterestAmount:  "interest_amount",
+		PaymentDate:     "payment_date",
+		CreatedAt:       "created_at",
+		UpdatedAt:       "updated_at",
+		DeletedAt:       "deleted_at",
+	}
+)
+
+func NewLoanRepaymentRepo(impl LoanRepaymentRepoImpl) LoanRepaymentRepo {
+	return &impl
+}
+
+// Create LoanRepayment and return last inserted id
+func (r *LoanRepaymentRepoImpl) Create(ctx context.Context, repayment *LoanRepayment) (int64, error) {
+	txn, err := dbtxn.Use(ctx, r.DB)
+	if err != nil {
+		return -1, err
+	}
+
+	builder := sq.
+		Insert(LoanRepaymentTableName).
+		Columns(
+			LoanRepaymentTable.LoanID,
+			LoanRepaymentTable.RepaymentCode,
+			LoanRepaymentTable.ReferenceNumber,
+			LoanRepaymentTable.Amount,
+			LoanRepaymentTable.PrincipalAmount,
+			LoanRepaymentTable.InterestAmount,
+			LoanRepaymentTable.PaymentDate,
+			LoanRepaymentTable.CreatedAt,
+			LoanRepaymentTable.UpdatedAt,
+			LoanRepaymentTable.DeletedAt,
+		).
+		Suffix("RETURNING id").
+		PlaceholderFormat(sq.Dollar).
+		Values(
+			repayment.LoanID,
+			repayment.RepaymentCode,
+			repayment.ReferenceNumber,
+			repayment.Amount,
+			repayment.PrincipalAmount,
+			repayment.InterestAmount,
+			repayment.PaymentDate,
+			time.Now(),
+			time.Now(),
+			nil,
+		)
+
+	scanner := builder.RunWith(txn).QueryRowContext(ctx)
+
+	var id int64
+	if err := scanner.Scan(&id); err != nil {
+		return -1, fmt.Errorf("failed to scan id: %v", err)
+	}
+
+	return id, nil
+}
+
+// GetByLoanID returns every repayment of a loan ordered by payment date
+func (r *LoanRepaymentRepoImpl) GetByLoanID(ctx context.Context, loanID int64) ([]LoanRepayment, error) {
+	txn, err := dbtxn.Use(ctx, r.DB)
+	if err != nil {
+		return nil, err
+	}
+
+	builder := sq.
+		Select(
+			LoanRepaymentTable.ID,
+			LoanRepaymentTable.LoanID,
+			LoanRepaymentTable.RepaymentCode,
+			LoanRepaymentTable.ReferenceNumber,
+			LoanRepaymentTable.Amount,
+			LoanRepaymentTable.PrincipalAmount,
+			LoanRepaymentTable.InterestAmount,
+			LoanRepaymentTable.PaymentDate,
+			LoanRepaymentTable.CreatedAt,
+			LoanRepaymentTable.UpdatedAt,
+			LoanRepaymentTable.DeletedAt,
+		).
+		From(LoanRepaymentTableName).
+		Where(sq.Eq{LoanRepaymentTable.LoanID: loanID}).
+		OrderBy(LoanRepaymentTable.PaymentDate+" ASC", LoanRepaymentTable.ID+" ASC").
+		PlaceholderFormat(sq.Dollar)
+
+	rows, err := builder.RunWith(txn).QueryContext(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute query: %v", err)
+	}
+	defer rows.Close()
+
+	var repayments []LoanRepayment
+	for rows.Next() {
+		var repayment LoanRepayment
+		if err := rows.Scan(
+			&repayment.ID,
+			&repayment.LoanID,
+			&repayment.RepaymentCode,
+			&repayment.ReferenceNumber,
+			&repayment.Amount,
+			&repayment.PrincipalAmount,
+			&repayment.InterestAmount,
+			&repayment.PaymentDate,
+			&repayment.CreatedAt,
+			&repayment.UpdatedAt,
+			&repayment.DeletedAt,
+		); err != nil {
+			return nil, fmt.Errorf("failed to scan row: %v", err)
+		}
+		repayments = append(repayments, repayment)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate over rows: %v", err)
+	}
+
+	return repayments, nil
+}
+
+// GetTotalAmountByLoanID returns the cumulative amount paid by the borrower for a loan
+func (r *LoanRepaymentRepoImpl) GetTotalAmountByLoanID(ctx context.Context, loanID int64) (money.Amount, error) {
+	txn, err := dbtxn.Use(ctx, r.DB)
+	if err != nil {
+		return 0, err
+	}
+
+	builder := sq.
+		Select("COALESCE(SUM(" + LoanRepaymentTable.Amount + "), 0)").
+		From(LoanRepaymentTableName).
+		Where(sq.Eq{
+			LoanRepaymentTable.LoanID:    loanID,
+			LoanRepaymentTable.DeletedAt: nil,
+		}).
+		PlaceholderFormat(sq.Dollar)
+
+	var total money.Amount
+	scanner := builder.RunWith(txn).QueryRowContext(ctx)
+	if err := scanner.Scan(&total); err != nil {
+		return 0, fmt.Errorf("failed to scan total amount: %v", err)
+	}
+
+	return total, nil
+}
@@ -6,6 +6,7 @@ import (
 	"fmt"
 	sq "github.com/Masterminds/squirrel"
 	"github.com/test/loan-service/internal/enum"
+	"github.com/test/loan-service/internal/money"
 	"github.com/typical-go/typical-rest-server/pkg/dbtxn"
 	"go.uber.org/dig"
 	"time"
@@ -20,31 +21,38 @@ type (
 		Status enum.LoanStatus
 	}
 	Loan struct {
-		ID                   int64           `db:"id"`                     // Loan ID
-		LoanCode             string          `db:"loan_code"`              // Loan code
-		BorrowerID           int64           `db:"borrower_id"`            // Borrower ID
-		RequestAmount        float64         `db:"request_amount"`         // Loan request amount
-		LoanGrade            string          `db:"loan_grade"`             // Loan grade (A, B, C, D)
-		LoanType             enum.LoanType   `db:"loan_type"`              // Type of loan (productive, consumptive, etc.)
-		TotalInvestedAmount  float64         `db:"total_invested_amount"`  // Total amount invested
-		InvestorCount        int64           `db:"investor_count"`         // Number of investors participating
-		FundingDeadline      *time.Time      `db:"funding_deadline"`       // Funding deadline
-		LoanStatus           enum.LoanStatus `db:"loan_status"`            // Loan status (proposed, rejected, approved, invested)
-		Rate                 float64         `db:"rate"`                   // Interest rate
-		Tenures              int64           `db:"tenures"`                // Loan tenure
-		TotalInterest        float64         `db:"total_interest"`         // Total repayment amount needed
-		TotalRepaymentAmount float64         `db:"total_repayment_amount"` // Total repayment amount needed
-		InvestmentPercentage float64         `db:"investment_percentage"`  // Investor profit sharing percentage
-		CreatedAt            time.Time       `db:"created_at"`             // Loan creation date
-		UpdatedAt            time.Time       `db:"updated_at"`             // Loan status update date
-		DeletedAt            *time.Time      `db:"deleted_at"`             // Loan deletion date (if applicable)
+		ID                    int64           `db:"id"`                      // Loan ID
+		LoanCode              string          `db:"loan_code"`               // Loan code
+		BorrowerID            int64           `db:"borrower_id"`             // Borrower ID
+		RequestAmount         money.Amount    `db:"request_amount"`          // Loan request amount
+		LoanGrade             enum.LoanGrade  `db:"loan_grade"`              // Loan grade (A, B, C, D, E)
+		LoanType              enum.LoanType   `db:"loan_type"`               // Type of loan (productive, consumptive, etc.)
+		TotalInvestedAmount   money.Amount    `db:"total_invested_amount"`   // Total amount invested
+		InvestorCount         int64           `db:"investor_count"`          // Number of investors participating
+		FundingDeadline       *time.Time      `db:"funding_deadline"`        // Funding deadline
+		LoanStatus            enum.LoanStatus `db:"loan_status"`             // Loan status (proposed, rejected, approved, invested)
+		Rate                  float64         `db:"rate"`                    // Interest rate
+		Tenures               int64           `db:"tenures"`                 // Loan tenure
+		TotalInterest         money.Amount    `db:"total_interest"`          // Total repayment amount needed
+		TotalRepaymentAmount  money.Amount    `db:"total_repayment_amount"`  // Total repayment amount needed
+		InvestmentPercentage  float64         `db:"investment_percentage"`   // Investor profit sharing percentage
+		PartialFundingConsent bool            `db:"partial_funding_consent"` // Borrower agrees to disburse a partially funded loan
+		PricingID             *int64          `db:"pricing_id"`              // Loan pricing applied when the loan was created
+		AgreementLetterLink   *string         `db:"agreement_letter_link"`   // Link to the generated loan agreement letter
+		CancellationReason    *string         `db:"cancellation_reason"`     // Reason given by the borrower when the loan was cancelled
+		CancelledAt           *time.Time      `db:"cancelled_at"`            // Loan cancellation date (if applicable)
+		CreatedAt             time.Time       `db:"created_at"`              // Loan creation date
+		UpdatedAt             time.Time       `db:"updated_at"`              // Loan status update date
+		DeletedAt             *time.Time      `db:"deleted_at"`              // Loan deletion date (if applicable)
 	}
 
 	LoanRepo interface {
 		Create(context.Context, *Loan) (int64, error)
 		Update(ctx context.Context, loan *Loan) error
 		GetByID(ctx context.Context, loanID int64) (*Loan, error)
+		GetByIDForUpdate(ctx context.Context, loanID int64) (*Loan, error)
 		GetAll(ctx context.Context) ([]Loan, error)
+		GetApprovedPastDeadline(ctx context.Context, deadline time.Time) ([]Loan, error)
 		GetAllPage(ctx context.Context, loanRequest LoanRequest) ([]Loan, int64, error)
 	}
 
@@ -57,43 +65,53 @@ type (
 var (
 	LoanTableName = "loans"
 	LoanTable     = struct {
-		ID                   string
-		LoanCode             string
-		BorrowerID           string
-		RequestAmount        string
-		LoanGrade            string
-		LoanType             string
-		TotalInvestedAmount  string
-		InvestorCount        string
-		FundingDeadline      string
-		LoanStatus           string
-		Rate                 string
-		Tenures              string
-		TotalInterest        string
-		TotalRepaymentAmount string
-		InvestmentPercentage string
-		CreatedAt            string
-		UpdatedAt            string
-		DeletedAt            string
+		ID                    string
+		LoanCode              string
+		BorrowerID            string
+		RequestAmount         string
+		LoanGrade             string
+		LoanType              string
+		TotalInvestedAmount   string
+		InvestorCount         string
+		FundingDeadline       string
+		LoanStatus            string
+		Rate                  string
+		Tenures               string
+		TotalInterest         string
+		TotalRepaymentAmount  string
+		InvestmentPercentage  string
+		PartialFundingConsent string
+		PricingID             string
+		AgreementLetterLink   string
+		CancellationReason    string
+		CancelledAt           string
+		CreatedAt             string
+		UpdatedAt             string
+		DeletedAt             string
 	}{
-		ID:                   "id",
-		LoanCode:             "loan_code",
-		BorrowerID:           "borrower_id",
-		RequestAmount:        "request_amount",
-		LoanGrade:            "loan_grade",
-		LoanType:             "loan_type",
-		TotalInvestedAmount:  "total_invested_amount",
-		InvestorCount:        "investor_count",
-		FundingDeadline:      "funding_deadline",
-		LoanStatus:           "loan_status",
-		Rate:                 "rate",
-		Tenures:              "tenures",
-		TotalInterest:        "total_interest",
-		TotalRepaymentAmount: "total_repayment_amount",
-		InvestmentPercentage: "investment_percentage",
-		CreatedAt:            "created_at",
-		UpdatedAt:            "updated_at",
-		DeletedAt:            "deleted_at",
+		ID:                    "id",
+		LoanCode:              "loan_code",
+		BorrowerID:            "borrower_id",
+		RequestAmount:         "request_amount",
+		LoanGrade:             "loan_grade",
+		LoanType:              "loan_type",
+		TotalInvestedAmount:   "total_invested_amount",
+		InvestorCount:         "investor_count",
+		FundingDeadline:       "funding_deadline",
+		LoanStatus:            "loan_status",
+		Rate:                  "rate",
+		Tenures:               "tenures",
+		TotalInterest:         "total_interest",
+		TotalRepaymentAmount:  "total_repayment_amount",
+		InvestmentPercentage:  "investment_percentage",
+		PartialFundingConsent: "partial_funding_consent",
+		PricingID:             "pricing_id",
+		AgreementLetterLink:   "agreement_letter_link",
+		CancellationReason:    "cancellation_reason",
+		CancelledAt:           "cancelled_at",
+		CreatedAt:             "created_at",
+		UpdatedAt:             "updated_at",
+		DeletedAt:             "deleted_at",
 	}
 )
 
@@ -127,6 +145,11 @@ func (r *LoanRepoImpl) Create(ctx context.Context, loan *Loan) (int64, error) {
 			LoanTable.TotalInterest,
 			LoanTable.TotalRepaymentAmount,
 			LoanTable.InvestmentPercentage,
+			LoanTable.PartialFundingConsent,
+			LoanTable.PricingID,
+			LoanTable.AgreementLetterLink,
+			LoanTable.CancellationReason,
+			LoanTable.CancelledAt,
 			LoanTable.CreatedAt,
 			LoanTable.UpdatedAt,
 			LoanTable.DeletedAt,
@@ -148,6 +171,11 @@ func (r *LoanRepoImpl) Create(ctx context.Context, loan *Loan) (int64, error) {
 			loan.TotalInterest,
 			loan.TotalRepaymentAmount,
 			loan.InvestmentPercentage,
+			loan.PartialFundingConsent,
+			loan.PricingID,
+			loan.AgreementLetterLink,
+			loan.CancellationReason,
+			loan.CancelledAt,
 			time.Now(),
 			time.Now(),
 			nil,
@@ -181,6 +209,11 @@ func (r *LoanRepoImpl) Update(ctx context.Context, loan *Loan) error {
 		Set(LoanTable.TotalInterest, loan.TotalInterest).
 		Set(LoanTable.TotalRepaymentAmount, loan.TotalRepaymentAmount).
 		Set(LoanTable.InvestmentPercentage, loan.InvestmentPercentage).
+		Set(LoanTable.PartialFundingConsent, loan.PartialFundingConsent).
+		Set(LoanTable.PricingID, loan.PricingID).
+		Set(LoanTable.AgreementLetterLink, loan.AgreementLetterLink).
+		Set(LoanTable.CancellationReason, loan.CancellationReason).
+		Set(LoanTable.CancelledAt, loan.CancelledAt).
 		Set(LoanTable.UpdatedAt, time.Now()). // Update the `UpdatedAt` field
 		Where(sq.Eq{LoanTable.ID: loan.ID}).
 		PlaceholderFormat(sq.Dollar)
@@ -228,6 +261,11 @@ func (r *LoanRepoImpl) GetAllPage(ctx context.Context, loanRequest LoanRequest)
 			LoanTable.TotalInterest,
 			LoanTable.TotalRepaymentAmount,
 			LoanTable.InvestmentPercentage,
+			LoanTable.PartialFundingConsent,
+			LoanTable.PricingID,
+			LoanTable.AgreementLetterLink,
+			LoanTable.CancellationReason,
+			LoanTable.CancelledAt,
 			LoanTable.CreatedAt,
 			LoanTable.UpdatedAt,
 			LoanTable.DeletedAt,
@@ -273,6 +311,11 @@ func (r *LoanRepoImpl) GetAllPage(ctx context.Context, loanRequest LoanRequest)
 			&loan.TotalInterest,
 			&loan.TotalRepaymentAmount,
 			&loan.InvestmentPercentage,
+			&loan.PartialFundingConsent,
+			&loan.PricingID,
+			&loan.AgreementLetterLink,
+			&loan.CancellationReason,
+			&loan.CancelledAt,
 			&loan.CreatedAt,
 			&loan.UpdatedAt,
 			&loan.DeletedAt,
@@ -321,6 +364,11 @@ func (r *LoanRepoImpl) GetByID(ctx context.Context, loanID int64) (*Loan, error)
 			LoanTable.TotalInterest,
 			LoanTable.TotalRepaymentAmount,
 			LoanTable.InvestmentPercentage,
+			LoanTable.PartialFundingConsent,
+			LoanTable.PricingID,
+			LoanTable.AgreementLetterLink,
+			LoanTable.CancellationReason,
+			LoanTable.CancelledAt,
 			LoanTable.CreatedAt,
 			LoanTable.UpdatedAt,
 			LoanTable.DeletedAt,
@@ -350,6 +398,88 @@ func (r *LoanRepoImpl) GetByID(ctx context.Context, loanID int64) (*Loan, error)
 		&loan.TotalInterest,
 		&loan.TotalRepaymentAmount,
 		&loan.InvestmentPercentage,
+		&loan.PartialFundingConsent,
+		&loan.PricingID,
+		&loan.AgreementLetterLink,
+		&loan.CancellationReason,
+		&loan.CancelledAt,
+		&loan.CreatedAt,
+		&loan.UpdatedAt,
+		&loan.DeletedAt,
+	); err != nil {
+		return nil, fmt.Errorf("failed to scan loan: %v", err)
+	}
+
+	// Return the loan details
+	return &loan, nil
+}
+
+// GetByIDForUpdate get loan by ID and lock the row until the transaction ends
+func (r *LoanRepoImpl) GetByIDForUpdate(ctx context.Context, loanID int64) (*Loan, error) {
+	// row lock only holds when ctx carries a transaction
+	txn, err := dbtxn.Use(ctx, r.DB)
+	if err != nil {
+		return nil, err
+	}
+
+	// Construct the query to get and lock the loan by ID
+	builder := sq.
+		Select(
+			LoanTable.ID,
+			LoanTable.LoanCode,
+			LoanTable.BorrowerID,
+			LoanTable.RequestAmount,
+			LoanTable.LoanGrade,
+			LoanTable.LoanType,
+			LoanTable.TotalInvestedAmount,
+			LoanTable.InvestorCount,
+			LoanTable.FundingDeadline,
+			LoanTable.LoanStatus,
+			LoanTable.Rate,
+			LoanTable.Tenures,
+			LoanTable.TotalInterest,
+			LoanTable.TotalRepaymentAmount,
+			LoanTable.InvestmentPercentage,
+			LoanTable.PartialFundingConsent,
+			LoanTable.PricingID,
+			LoanTable.AgreementLetterLink,
+			LoanTable.CancellationReason,
+			LoanTable.CancelledAt,
+			LoanTable.CreatedAt,
+			LoanTable.UpdatedAt,
+			LoanTable.DeletedAt,
+		).
+		From(LoanTableName).
+		Where(sq.Eq{LoanTable.ID: loanID}).
+		Suffix("FOR UPDATE").
+		PlaceholderFormat(sq.Dollar)
+
+	// Execute the query and scan the result into the Loan struct
+	var loan Loan
+	scanner := builder.RunWith(txn).QueryRowContext(ctx)
+
+	// Scan the values into the loan struct
+	if err := scanner.Scan(
+		&loan.ID,
+		&loan.LoanCode,
+		&loan.BorrowerID,
+		&loan.RequestAmount,
+		&loan.LoanGrade,
+		&loan.LoanType,
+		&loan.TotalInvestedAmount,
+		&loan.InvestorCount,
+		&loan.FundingDeadline,
+		&loan.LoanStatus,
+		&loan.Rate,
+		&loan.Tenures,
+		&loan.TotalInterest,
+		&loan.TotalRepaymentAmount,
+		&loan.InvestmentPercentage,
+		&loan.PartialFundingConsent,
+		&loan.PricingID,
+		&loan.AgreementLetterLink,
+		&loan.CancellationReason,
+		&loan.CancelledAt,
 		&loan.CreatedAt,
 		&loan.UpdatedAt,
 		&loan.DeletedAt,
@@ -386,11 +516,113 @@ func (r *LoanRepoImpl) GetAll(ctx context.Context) ([]Loan, error) {
 			LoanTable.TotalInterest,
 			LoanTable.TotalRepaymentAmount,
 			LoanTable.InvestmentPercentage,
+			LoanTable.PartialFundingConsent,
+			LoanTable.PricingID,
+			LoanTable.AgreementLetterLink,
+			LoanTable.CancellationReason,
+			LoanTable.CancelledAt,
+			LoanTable.CreatedAt,
+			LoanTable.UpdatedAt,
+			LoanTable.DeletedAt,
+		).
+		From(LoanTableName).
+		PlaceholderFormat(sq.Dollar)
+
+	// Execute the query and scan the result into a slice of Loan structs
+	rows, err := builder.RunWith(txn).QueryContext(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query loans: %v", err)
+	}
+
+	defer func(rows *sql.Rows) {
+		err = rows.Close()
+		if err != nil {
+
+		}
+	}(rows)
+
+	// Iterate over the rows and scan the result into the Loan struct
+	var loans []Loan
+	for rows.Next() {
+		var loan Loan
+		if err = rows.Scan(
+			&loan.ID,
+			&loan.LoanCode,
+			&loan.BorrowerID,
+			&loan.RequestAmount,
+			&loan.LoanGrade,
+			&loan.LoanType,
+			&loan.TotalInvestedAmount,
+			&loan.InvestorCount,
+			&loan.FundingDeadline,
+			&loan.LoanStatus,
+			&loan.Rate,
+			&loan.Tenures,
+			&loan.TotalInterest,
+			&loan.TotalRepaymentAmount,
+			&loan.InvestmentPercentage,
+			&loan.PartialFundingConsent,
+			&loan.PricingID,
+			&loan.AgreementLetterLink,
+			&loan.CancellationReason,
+			&loan.CancelledAt,
+			&loan.CreatedAt,
+			&loan.UpdatedAt,
+			&loan.DeletedAt,
+		); err != nil {
+			return nil, fmt.Errorf("failed to scan loan row: %v", err)
+		}
+		loans = append(loans, loan)
+	}
+
+	// Check for errors that occurred during iteration
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed during row iteration: %v", err)
+	}
+
+	// Return the slice of loans
+	return loans, nil
+}
+
+// GetApprovedPastDeadline get approved loans whose funding deadline is before the given deadline
+func (r *LoanRepoImpl) GetApprovedPastDeadline(ctx context.Context, deadline time.Time) ([]Loan, error) {
+	// use transaction if any
+	txn, err := dbtxn.Use(ctx, r.DB)
+	if err != nil {
+		return nil, err
+	}
+
+	// Construct the query to get approved loans past their funding deadline
+	builder := sq.
+		Select(
+			LoanTable.ID,
+			LoanTable.LoanCode,
+			LoanTable.BorrowerID,
+			LoanTable.RequestAmount,
+			LoanTable.LoanGrade,
+			LoanTable.LoanType,
+			LoanTable.TotalInvestedAmount,
+			LoanTable.InvestorCount,
+			LoanTable.FundingDeadline,
+			LoanTable.LoanStatus,
+			LoanTable.Rate,
+			LoanTable.Tenures,
+			LoanTable.TotalInterest,
+			LoanTable.TotalRepaymentAmount,
+			LoanTable.InvestmentPercentage,
+			LoanTable.PartialFundingConsent,
+			LoanTable.PricingID,
+			LoanTable.AgreementLetterLink,
+			LoanTable.CancellationReason,
+			LoanTable.CancelledAt,
 			LoanTable.CreatedAt,
 			LoanTable.UpdatedAt,
 			LoanTable.DeletedAt,
 		).
 		From(LoanTableName).
+		Where(sq.Eq{LoanTable.LoanStatus: enum.Approved}).
+		Where(sq.Lt{LoanTable.FundingDeadline: deadline}).
+		OrderBy(LoanTable.FundingDeadline + " ASC").
 		PlaceholderFormat(sq.Dollar)
 
 	// Execute the query and scan the result into a slice of Loan structs
@@ -426,6 +658,11 @@ func (r *LoanRepoImpl) GetAll(ctx context.Context) ([]Loan, error) {
 			&loan.TotalInterest,
 			&loan.TotalRepaymentAmount,
 			&loan.InvestmentPercentage,
+			&loan.PartialFundingConsent,
+			&loan.PricingID,
+			&loan.AgreementLetterLink,
+			&loan.CancellationReason,
+			&loan.CancelledAt,
 			&loan.CreatedAt,
 			&loan.UpdatedAt,
 			&loan.DeletedAt,
@@ -0,0 +1,186 @@
+package repo
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	sq "github.com/Masterminds/squirrel"
+	"github.com/test/loan-service/internal/money"
+	"github.com/typical-go/typical-rest-server/pkg/dbtxn"
+	"go.uber.org/dig"
+	"time"
+)
+
+//go:generate mockgen -source=$GOFILE -destination=$PROJ/internal/generated/mock/mock_$GOPACKAGE/$GOFILE
+
+type (
+	RepaymentAllocation struct {
+		ID              int64        `db:"id"`               // Allocation ID
+		RepaymentID     int64        `db:"repayment_id"`     // Repayment ID
+		LoanFundingID   int64        `db:"loan_funding_id"`  // Funding receiving the allocation
+		LenderID        int64        `db:"lender_id"`        // Lender receiving the allocation
+		PrincipalAmount money.Amount `db:"principal_amount"` // Principal allocated to the lender
+		InterestAmount  money.Amount `db:"interest_amount"`  // Interest allocated to the lender
+		ServiceFee      money.Amount `db:"service_fee"`      // Service fee taken from the interest allocated to the lender
+		WithholdingTax  money.Amount `db:"withholding_tax"`  // Tax withheld from the interest allocated to the lender
+		TotalAmount     money.Amount `db:"total_amount"`     // Principal + interest allocated to the lender, net of the service fee and withholding tax
+		CreatedAt       time.Time    `db:"created_at"`       // Date of creation
+		UpdatedAt       time.Time    `db:"updated_at"`       // Date of last update
+		DeletedAt       *time.Time   `db:"deleted_at"`       // Date of deletion if applicable
+	}
+
+	RepaymentAllocationRepo interface {
+		Create(ctx context.Context, allocation *RepaymentAllocation) (int64, error)
+		GetByRepaymentID(ctx context.Context, repaymentID int64) ([]RepaymentAllocation, error)
+	}
+
+	RepaymentAllocationRepoImpl struct {
+		dig.In
+		*sql.DB
+	}
+)
+
+var (
+	RepaymentAllocationTableName = "repayment_allocations"
+	RepaymentAllocationTable     = struct {
+		ID              string
+		RepaymentID     string
+		LoanFundingID   string
+		LenderID        string
+		PrincipalAmount string
+		InterestAmount  string
+		ServiceFee      string
+		WithholdingTax  string
+		TotalAmount     string
+		CreatedAt       string
+		UpdatedAt       string
+		DeletedAt       string
+	}{
+		ID:              "id",
+		RepaymentID:     "repayment_id",
+		LoanFundingID:   "loan_funding_id",
+		LenderID:        "lender_id",
+		PrincipalAmount: "principal_amount",
+		InterestAmount:  "interest_amount",
+		ServiceFee:      "service_fee",
+		WithholdingTax:  "withholding_tax",
+		TotalAmount:     "total_amount",
+		CreatedAt:       "created_at",
+		UpdatedAt:       "updated_at",
+		DeletedAt:       "deleted_at",
+	}
+)
+
+func NewRepaymentAllocationRepo(impl RepaymentAllocationRepoImpl) RepaymentAllocationRepo {
+	return &impl
+}
+
+// Create RepaymentAllocation and return last inserted id
+func (r *RepaymentAllocationRepoImpl) Create(ctx context.Context, allocation *RepaymentAllocation) (int64, error) {
+	txn, err := dbtxn.Use(ctx, r.DB)
+	if err != nil {
+		return -1, err
+	}
+
+	builder := sq.
+		Insert(RepaymentAllocationTableName).
+		Columns(
+			RepaymentAllocationTable.RepaymentID,
+			RepaymentAllocationTable.LoanFundingID,
+			RepaymentAllocationTable.LenderID,
+			RepaymentAllocationTable.PrincipalAmount,
+			RepaymentAllocationTable.InterestAmount,
+			RepaymentAllocationTable.ServiceFee,
+			RepaymentAllocationTable.WithholdingTax,
+			RepaymentAllocationTable.TotalAmount,
+			RepaymentAllocationTable.CreatedAt,
+			RepaymentAllocationTable.UpdatedAt,
+			RepaymentAllocationTable.DeletedAt,
+		).
+		Suffix("RETURNING id").
+		PlaceholderFormat(sq.Dollar).
+		Values(
+			allocation.RepaymentID,
+			allocation.LoanFundingID,
+			allocation.LenderID,
+			allocation.PrincipalAmount,
+			allocation.InterestAmount,
+			allocation.ServiceFee,
+			allocation.WithholdingTax,
+			allocation.TotalAmount,
+			time.Now(),
+			time.Now(),
+			nil,
+		)
+
+	scanner := builder.RunWith(txn).QueryRowContext(ctx)
+
+	var id int64
+	if err := scanner.Scan(&id); err != nil {
+		return -1, fmt.Errorf("failed to scan id: %v", err)
+	}
+
+	return id, nil
+}
+
+// GetByRepaymentID returns every lender allocation of a repayment
+func (r *RepaymentAllocationRepoImpl) GetByRepaymentID(ctx context.Context, repaymentID int64) ([]RepaymentAllocation, error) {
+	txn, err := dbtxn.Use(ctx, r.DB)
+	if err != nil {
+		return nil, err
+	}
+
+	builder := sq.
+		Select(
+			RepaymentAllocationTable.ID,
+			RepaymentAllocationTable.RepaymentID,
+			RepaymentAllocationTable.LoanFundingID,
+			RepaymentAllocationTable.LenderID,
+			RepaymentAllocationTable.PrincipalAmount,
+			RepaymentAllocationTable.InterestAmount,
+			RepaymentAllocationTable.ServiceFee,
+			RepaymentAllocationTable.WithholdingTax,
+			RepaymentAllocationTable.TotalAmount,
+			RepaymentAllocationTable.CreatedAt,
+			RepaymentAllocationTable.UpdatedAt,
+			RepaymentAllocationTable.DeletedAt,
+		).
+		From(RepaymentAllocationTableName).
+		Where(sq.Eq{RepaymentAllocationTable.RepaymentID: repaymentID}).
+		OrderBy(RepaymentAllocationTable.LoanFundingID + " ASC").
+		PlaceholderFormat(sq.Dollar)
+
+	rows, err := builder.RunWith(txn).QueryContext(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute query: %v", err)
+	}
+	defer rows.Close()
+
+	var allocations []RepaymentAllocation
+	for rows.Next() {
+		var allocation RepaymentAllocation
+		if err := rows.Scan(
+			&allocation.ID,
+			&allocation.RepaymentID,
+			&allocation.LoanFundingID,
+			&allocation.LenderID,
+			&allocation.PrincipalAmount,
+			&allocation.InterestAmount,
+			&allocation.ServiceFee,
+			&allocation.WithholdingTax,
+			&allocation.TotalAmount,
+			&allocation.CreatedAt,
+			&allocation.UpdatedAt,
+			&allocation.DeletedAt,
+		); err != nil {
+			return nil, fmt.Errorf("failed to scan row: %v", err)
+		}
+		allocations = append(allocations, allocation)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate over rows: %v", err)
+	}
+
+	return allocations, nil
+}
@@ -0,0 +1,248 @@
+package repo
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	sq "github.com/Masterminds/squirrel"
+	"github.com/test/loan-service/internal/enum"
+	"github.com/test/loan-service/internal/money"
+	"github.com/typical-go/typical-rest-server/pkg/dbtxn"
+	"go.uber.org/dig"
+	"time"
+)
+
+//go:generate mockgen -source=$GOFILE -destination=$PROJ/internal/generated/mock/mock_$GOPACKAGE/$GOFILE
+
+type (
+	RepaymentSchedule struct {
+		ID                 int64                        `db:"id"`                  // Repayment schedule ID
+		LoanID             int64                        `db:"loan_id"`             // Loan ID
+		InstallmentNumber  int64                        `db:"installment_number"`  // Installment sequence (1..tenures)
+		DueDate            time.Time                    `db:"due_date"`            // Installment due date
+		PrincipalAmount    money.Amount                 `db:"principal_amount"`    // Principal portion of the installment
+		InterestAmount     money.Amount                 `db:"interest_amount"`     // Interest portion of the installment
+		TotalAmount        money.Amount                 `db:"total_amount"`        // Principal + interest of the installment
+		OutstandingBalance money.Amount                 `db:"outstanding_balance"` // Remaining principal after this installment
+		PrincipalPaid      money.Amount                 `db:"principal_paid"`      // Principal already paid for this installment
+		InterestPaid       money.Amount                 `db:"interest_paid"`       // Interest already paid for this installment
+		PaidAt             *time.Time                   `db:"paid_at"`             // Date the installment was fully paid
+		Status             enum.RepaymentScheduleStatus `db:"status"`              // Installment status (pending, partially_paid, paid)
+		CreatedAt          time.Time                    `db:"created_at"`          // Date of creation
+		UpdatedAt          time.Time                    `db:"updated_at"`          // Date of last update
+		DeletedAt          *time.Time                   `db:"deleted_at"`          // Date of deletion if applicable
+	}
+
+	RepaymentScheduleRepo interface {
+		Create(ctx context.Context, schedule *RepaymentSchedule) (int64, error)
+		Update(ctx context.Context, schedule *RepaymentSchedule) error
+		GetByLoanID(ctx context.Context, loanID int64) ([]RepaymentSchedule, error)
+	}
+
+	RepaymentScheduleRepoImpl struct {
+		dig.In
+		*sql.DB
+	}
+)
+
+var (
+	RepaymentScheduleTableName = "repayment_schedules"
+	RepaymentScheduleTable     = struct {
+		ID                 string
+		LoanID             string
+		InstallmentNumber  string
+		DueDate            string
+		PrincipalAmount    string
+		InterestAmount     string
+		TotalAmount        string
+		OutstandingBalance string
+		PrincipalPaid      string
+		InterestPaid       string
+		PaidAt             string
+		Status             string
+		CreatedAt          string
+		UpdatedAt          string
+		DeletedAt          string
+	}{
+		ID:                 "id",
+		LoanID:             "loan_id",
+		InstallmentNumber:  "installment_number",
+		DueDate:            "due_date",
+		PrincipalAmount:    "principal_amount",
+		InterestAmount:     "interest_amount",
+		TotalAmount:        "total_amount",
+		OutstandingBalance: "outstanding_balance",
+		PrincipalPaid:      "principal_paid",
+		InterestPaid:       "interest_paid",
+		PaidAt:             "paid_at",
+		Status:             "status",
+		CreatedAt:          "created_at",
+		UpdatedAt:          "updated_at",
+		DeletedAt:          "deleted_at",
+	}
+)
+
+func NewRepaymentScheduleRepo(impl RepaymentScheduleRepoImpl) RepaymentScheduleRepo {
+	return &impl
+}
+
+// Create RepaymentSchedule and return last inserted id
+func (r *RepaymentScheduleRepoImpl) Create(ctx context.Context, schedule *RepaymentSchedule) (int64, error) {
+	// Use transaction if any
+	txn, err := dbtxn.Use(ctx, r.DB)
+	if err != nil {
+		return -1, err
+	}
+
+	// Construct insert query
+	builder := sq.
+		Insert(RepaymentScheduleTableName).
+		Columns(
+			RepaymentScheduleTable.LoanID,
+			RepaymentScheduleTable.InstallmentNumber,
+			RepaymentScheduleTable.DueDate,
+			RepaymentScheduleTable.PrincipalAmount,
+			RepaymentScheduleTable.InterestAmount,
+			RepaymentScheduleTable.TotalAmount,
+			RepaymentScheduleTable.OutstandingBalance,
+			RepaymentScheduleTable.PrincipalPaid,
+			RepaymentScheduleTable.InterestPaid,
+			RepaymentScheduleTable.PaidAt,
+			RepaymentScheduleTable.Status,
+			RepaymentScheduleTable.CreatedAt,
+			RepaymentScheduleTable.UpdatedAt,
+			RepaymentScheduleTable.DeletedAt,
+		).
+		Suffix("RETURNING id").
+		PlaceholderFormat(sq.Dollar).
+		Values(
+			schedule.LoanID,
+			schedule.InstallmentNumber,
+			schedule.DueDate,
+			schedule.PrincipalAmount,
+			schedule.InterestAmount,
+			schedule.TotalAmount,
+			schedule.OutstandingBalance,
+			schedule.PrincipalPaid,
+			schedule.InterestPaid,
+			schedule.PaidAt,
+			schedule.Status,
+			time.Now(),
+			time.Now(),
+			nil,
+		)
+
+	scanner := builder.RunWith(txn).QueryRowContext(ctx)
+
+	var id int64
+	if err := scanner.Scan(&id); err != nil {
+		return -1, fmt.Errorf("failed to scan id: %v", err)
+	}
+
+	return id, nil
+}
+
+// Update RepaymentSchedule
+func (r *RepaymentScheduleRepoImpl) Update(ctx context.Context, schedule *RepaymentSchedule) error {
+	txn, err := dbtxn.Use(ctx, r.DB)
+	if err != nil {
+		return err
+	}
+
+	builder := sq.Update(RepaymentScheduleTableName).
+		Set(RepaymentScheduleTable.DueDate, schedule.DueDate).
+		Set(RepaymentScheduleTable.PrincipalAmount, schedule.PrincipalAmount).
+		Set(RepaymentScheduleTable.InterestAmount, schedule.InterestAmount).
+		Set(RepaymentScheduleTable.TotalAmount, schedule.TotalAmount).
+		Set(RepaymentScheduleTable.OutstandingBalance, schedule.OutstandingBalance).
+		Set(RepaymentScheduleTable.PrincipalPaid, schedule.PrincipalPaid).
+		Set(RepaymentScheduleTable.InterestPaid, schedule.InterestPaid).
+		Set(RepaymentScheduleTable.PaidAt, schedule.PaidAt).
+		Set(RepaymentScheduleTable.Status, schedule.Status).
+		Set(RepaymentScheduleTable.UpdatedAt, time.Now()).
+		Where(sq.Eq{RepaymentScheduleTable.ID: schedule.ID}).
+		PlaceholderFormat(sq.Dollar)
+
+	result, err := builder.RunWith(txn).ExecContext(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to update repayment schedule: %v", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %v", err)
+	}
+	if rowsAffected == 0 {
+		return fmt.Errorf("no repayment schedule found with ID: %d", schedule.ID)
+	}
+
+	return nil
+}
+
+// GetByLoanID returns every installment of a loan ordered by installment number
+func (r *RepaymentScheduleRepoImpl) GetByLoanID(ctx context.Context, loanID int64) ([]RepaymentSchedule, error) {
+	txn, err := dbtxn.Use(ctx, r.DB)
+	if err != nil {
+		return nil, err
+	}
+
+	builder := sq.
+		Select(
+			RepaymentScheduleTable.ID,
+			RepaymentScheduleTable.LoanID,
+			RepaymentScheduleTable.InstallmentNumber,
+			RepaymentScheduleTable.DueDate,
+			RepaymentScheduleTable.PrincipalAmount,
+			RepaymentScheduleTable.InterestAmount,
+			RepaymentScheduleTable.TotalAmount,
+			RepaymentScheduleTable.OutstandingBalance,
+			RepaymentScheduleTable.PrincipalPaid,
+			RepaymentScheduleTable.InterestPaid,
+			RepaymentScheduleTable.PaidAt,
+			RepaymentScheduleTable.Status,
+			RepaymentScheduleTable.CreatedAt,
+			RepaymentScheduleTable.UpdatedAt,
+			RepaymentScheduleTable.DeletedAt,
+		).
+		From(RepaymentScheduleTableName).
+		Where(sq.Eq{RepaymentScheduleTable.LoanID: loanID}).
+		OrderBy(RepaymentScheduleTable.InstallmentNumber + " ASC").
+		PlaceholderFormat(sq.Dollar)
+
+	rows, err := builder.RunWith(txn).QueryContext(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute query: %v", err)
+	}
+	defer rows.Close()
+
+	var schedules []RepaymentSchedule
+	for rows.Next() {
+		var schedule RepaymentSchedule
+		if err := rows.Scan(
+			&schedule.ID,
+			&schedule.LoanID,
+			&schedule.InstallmentNumber,
+			&schedule.DueDate,
+			&schedule.PrincipalAmount,
+			&schedule.InterestAmount,
+			&schedule.TotalAmount,
+			&schedule.OutstandingBalance,
+			&schedule.PrincipalPaid,
+			&schedule.InterestPaid,
+			&schedule.PaidAt,
+			&schedule.Status,
+			&schedule.CreatedAt,
+			&schedule.UpdatedAt,
+			&schedule.DeletedAt,
+		); err != nil {
+			return nil, fmt.Errorf("failed to scan row: %v", err)
+		}
+		schedules = append(schedules, schedule)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate over rows: %v", err)
+	}
+
+	return schedules, nil
+}
@@ -0,0 +1,214 @@
+package repo
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	sq "github.com/Masterminds/squirrel"
+	"github.com/test/loan-service/internal/enum"
+	"github.com/test/loan-service/internal/money"
+	"github.com/typical-go/typical-rest-server/pkg/dbtxn"
+	"go.uber.org/dig"
+	"time"
+)
+
+//go:generate mockgen -source=$GOFILE -destination=$PROJ/internal/generated/mock/mock_$GOPACKAGE/$GOFILE
+
+type (
+	TaxWithholding struct {
+		ID                    int64           `db:"id"`                      // Withholding ID
+		RepaymentAllocationID int64           `db:"repayment_allocation_id"` // Repayment allocation the tax was withheld from
+		RepaymentID           int64           `db:"repayment_id"`            // Repayment the allocation belongs to
+		LoanID                int64           `db:"loan_id"`                 // Loan that paid the interest
+		LoanFundingID         int64           `db:"loan_funding_id"`         // Funding that earned the interest
+		LenderID              int64           `db:"lender_id"`               // Lender that earned the interest
+		LenderType            enum.LenderType `db:"lender_type"`             // Lender type when the tax was withheld
+		TaxID                 *string         `db:"tax_id"`                  // Tax ID of the lender when the tax was withheld
+		TaxableAmount         money.Amount    `db:"taxable_amount"`          // Gross interest the tax is calculated on
+		Rate                  float64         `db:"rate"`                    // Percentage withheld
+		TaxAmount             money.Amount    `db:"tax_amount"`              // Tax withheld
+		WithheldAt            time.Time       `db:"withheld_at"`             // Payment date of the repayment, decides the tax year
+		CreatedAt             time.Time       `db:"created_at"`              // Date of creation
+		UpdatedAt             time.Time       `db:"updated_at"`              // Date of last update
+		DeletedAt             *time.Time      `db:"deleted_at"`              // Date of deletion if applicable
+	}
+
+	TaxWithholdingRepo interface {
+		Create(ctx context.Context, withholding *TaxWithholding) (int64, error)
+		GetByLenderID(ctx context.Context, lenderID int64, from time.Time, until time.Time) ([]TaxWithholding, error)
+	}
+
+	TaxWithholdingRepoImpl struct {
+		dig.In
+		*sql.DB
+	}
+)
+
+var (
+	TaxWithholdingTableName = "tax_withholdings"
+	TaxWithholdingTable     = struct {
+		ID                    string
+		RepaymentAllocationID string
+		RepaymentID           string
+		LoanID                string
+		LoanFundingID         string
+		LenderID              string
+		LenderType            string
+		TaxID                 string
+		TaxableAmount         string
+		Rate                  string
+		TaxAmount             string
+		WithheldAt            string
+		CreatedAt             string
+		UpdatedAt             string
+		DeletedAt             string
+	}{
+		ID:                    "id",
+		RepaymentAllocationID: "repayment_allocation_id",
+		RepaymentID:           "repayment_id",
+		LoanID:                "loan_id",
+		LoanFundingID:         "loan_funding_id",
+		LenderID:              "lender_id",
+		LenderType:            "lender_type",
+		TaxID:                 "tax_id",
+		TaxableAmount:         "taxable_amount",
+		Rate:                  "rate",
+		TaxAmount:             "tax_amount",
+		WithheldAt:            "withheld_at",
+		CreatedAt:             "created_at",
+		UpdatedAt:             "updated_at",
+		DeletedAt:             "deleted_at",
+	}
+)
+
+func NewTaxWithholdingRepo(impl TaxWithholdingRepoImpl) TaxWithholdingRepo {
+	return &impl
+}
+
+// Create TaxWithholding and return last inserted id
+func (r *TaxWithholdingRepoImpl) Create(ctx context.Context, withholding *TaxWithholding) (int64, error) {
+	txn, err := dbtxn.Use(ctx, r.DB)
+	if err != nil {
+		return -1, err
+	}
+
+	builder := sq.
+		Insert(TaxWithholdingTableName).
+		Columns(
+			TaxWithholdingTable.RepaymentAllocationID,
+			TaxWithholdingTable.RepaymentID,
+			TaxWithholdingTable.LoanID,
+			TaxWithholdingTable.LoanFundingID,
+			TaxWithholdingTable.LenderID,
+			TaxWithholdingTable.LenderType,
+			TaxWithholdingTable.TaxID,
+			TaxWithholdingTable.TaxableAmount,
+			TaxWithholdingTable.Rate,
+			TaxWithholdingTable.TaxAmount,
+			TaxWithholdingTable.WithheldAt,
+			TaxWithholdingTable.CreatedAt,
+			TaxWithholdingTable.UpdatedAt,
+			TaxWithholdingTable.DeletedAt,
+		).
+		Suffix("RETURNING id").
+		PlaceholderFormat(sq.Dollar).
+		Values(
+			withholding.RepaymentAllocationID,
+			withholding.RepaymentID,
+			withholding.LoanID,
+			withholding.LoanFundingID,
+			withholding.LenderID,
+			withholding.LenderType,
+			withholding.TaxID,
+			withholding.TaxableAmount,
+			withholding.Rate,
+			withholding.TaxAmount,
+			withholding.WithheldAt,
+			time.Now(),
+			time.Now(),
+			nil,
+		)
+
+	scanner := builder.RunWith(txn).QueryRowContext(ctx)
+
+	var id int64
+	if err := scanner.Scan(&id); err != nil {
+		return -1, fmt.Errorf("failed to scan id: %v", err)
+	}
+
+	return id, nil
+}
+
+// GetByLenderID returns the tax withheld from a lender from the start of from until before until,
+// oldest first
+func (r *TaxWithholdingRepoImpl) GetByLenderID(ctx context.Context, lenderID int64, from time.Time, until time.Time) ([]TaxWithholding, error) {
+	txn, err := dbtxn.Use(ctx, r.DB)
+	if err != nil {
+		return nil, err
+	}
+
+	builder := sq.
+		Select(
+			TaxWithholdingTable.ID,
+			TaxWithholdingTable.RepaymentAllocationID,
+			TaxWithholdingTable.RepaymentID,
+			TaxWithholdingTable.LoanID,
+			TaxWithholdingTable.LoanFundingID,
+			TaxWithholdingTable.LenderID,
+			TaxWithholdingTable.LenderType,
+			TaxWithholdingTable.TaxID,
+			TaxWithholdingTable.TaxableAmount,
+			TaxWithholdingTable.Rate,
+			TaxWithholdingTable.TaxAmount,
+			TaxWithholdingTable.WithheldAt,
+			TaxWithholdingTable.CreatedAt,
+			TaxWithholdingTable.UpdatedAt,
+			TaxWithholdingTable.DeletedAt,
+		).
+		From(TaxWithholdingTableName).
+		Where(sq.Eq{
+			TaxWithholdingTable.LenderID:  lenderID,
+			TaxWithholdingTable.DeletedAt: nil,
+		}).
+		Where(sq.GtOrEq{TaxWithholdingTable.WithheldAt: from}).
+		Where(sq.Lt{TaxWithholdingTable.WithheldAt: until}).
+		OrderBy(TaxWithholdingTable.WithheldAt+" ASC", TaxWithholdingTable.ID+" ASC").
+		PlaceholderFormat(sq.Dollar)
+
+	rows, err := builder.RunWith(txn).QueryContext(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute query: %v", err)
+	}
+	defer rows.Close()
+
+	var withholdings []TaxWithholding
+	for rows.Next() {
+		var withholding TaxWithholding
+		if err := rows.Scan(
+			&withholding.ID,
+			&withholding.RepaymentAllocationID,
+			&withholding.RepaymentID,
+			&withholding.LoanID,
+			&withholding.LoanFundingID,
+			&withholding.LenderID,
+			&withholding.LenderType,
+			&withholding.TaxID,
+			&withholding.TaxableAmount,
+			&withholding.Rate,
+			&withholding.TaxAmount,
+			&withholding.WithheldAt,
+			&withholding.CreatedAt,
+			&withholding.UpdatedAt,
+			&withholding.DeletedAt,
+		); err != nil {
+			return nil, fmt.Errorf("failed to scan row: %v", err)
+		}
+		withholdings = append(withholdings, withholding)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate over rows: %v", err)
+	}
+
+	return withholdings, nil
+}
@@ -0,0 +1,214 @@
+package repo
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	sq "github.com/Masterminds/squirrel"
+	"github.com/test/loan-service/internal/enum"
+	"github.com/test/loan-service/internal/money"
+	"github.com/typical-go/typical-rest-server/pkg/dbtxn"
+	"go.uber.org/dig"
+	"time"
+)
+
+//go:generate mockgen -source=$GOFILE -destination=$PROJ/internal/generated/mock/mock_$GOPACKAGE/$GOFILE
+
+type (
+	WalletTransaction struct {
+		ID                    int64                      `db:"id"`                      // Wallet transaction ID
+		WalletID              int64                      `db:"wallet_id"`               // Wallet ID
+		LenderID              int64                      `db:"lender_id"`               // Lender owning the wallet
+		TransactionCode       string                     `db:"transaction_code"`        // Transaction code
+		TransactionType       enum.WalletTransactionType `db:"transaction_type"`        // Transaction type
+		Amount                money.Amount               `db:"amount"`                  // Transaction amount
+		ReferenceID           *int64                     `db:"reference_id"`            // Funding, repayment allocation or withdrawal ID
+		ReferenceNumber       string                     `db:"reference_number"`        // Payment reference of a top up
+		AvailableBalanceAfter money.Amount               `db:"available_balance_after"` // Available balance after the transaction
+		HeldBalanceAfter      money.Amount               `db:"held_balance_after"`      // Held balance after the transaction
+		CreatedAt             time.Time                  `db:"created_at"`              // Date of transaction
+	}
+
+	WalletTransactionRepo interface {
+		Create(ctx context.Context, transaction *WalletTransaction) (int64, error)
+		GetByWalletID(ctx context.Context, walletID int64) ([]WalletTransaction, error)
+		GetTopUpByReferenceNumber(ctx context.Context, referenceNumber string) (*WalletTransaction, error)
+	}
+
+	WalletTransactionRepoImpl struct {
+		dig.In
+		*sql.DB
+	}
+)
+
+var (
+	WalletTransactionTableName = "wallet_transactions"
+	WalletTransactionTable     = struct {
+		ID                    string
+		WalletID              string
+		LenderID              string
+		TransactionCode       string
+		TransactionType       string
+		Amount                string
+		ReferenceID           string
+		ReferenceNumber       string
+		AvailableBalanceAfter string
+		HeldBalanceAfter      string
+		CreatedAt             string
+	}{
+		ID:                    "id",
+		WalletID:              "wallet_id",
+		LenderID:              "lender_id",
+		TransactionCode:       "transaction_code",
+		TransactionType:       "transaction_type",
+		Amount:                "amount",
+		ReferenceID:           "reference_id",
+		ReferenceNumber:       "reference_number",
+		AvailableBalanceAfter: "available_balance_after",
+		HeldBalanceAfter:      "held_balance_after",
+		CreatedAt:             "created_at",
+	}
+)
+
+func NewWalletTransactionRepo(impl WalletTransactionRepoImpl) WalletTransactionRepo {
+	return &impl
+}
+
+// Create WalletTransaction and return last inserted id
+func (r *WalletTransactionRepoImpl) Create(ctx context.Context, transaction *WalletTransaction) (int64, error) {
+	txn, err := dbtxn.Use(ctx, r.DB)
+	if err != nil {
+		return -1, err
+	}
+
+	builder := sq.
+		Insert(WalletTransactionTableName).
+		Columns(
+			WalletTransactionTable.WalletID,
+			WalletTransactionTable.LenderID,
+			WalletTransactionTable.TransactionCode,
+			WalletTransactionTable.TransactionType,
+			WalletTransactionTable.Amount,
+			WalletTransactionTable.ReferenceID,
+			WalletTransactionTable.ReferenceNumber,
+			WalletTransactionTable.AvailableBalanceAfter,
+			WalletTransactionTable.HeldBalanceAfter,
+			WalletTransactionTable.CreatedAt,
+		).
+		Suffix("RETURNING id").
+		PlaceholderFormat(sq.Dollar).
+		Values(
+			transaction.WalletID,
+			transaction.LenderID,
+			transaction.TransactionCode,
+			transaction.TransactionType,
+			transaction.Amount,
+			transaction.ReferenceID,
+			transaction.ReferenceNumber,
+			transaction.AvailableBalanceAfter,
+			transaction.HeldBalanceAfter,
+			transaction.CreatedAt,
+		)
+
+	scanner := builder.RunWith(txn).QueryRowContext(ctx)
+
+	var id int64
+	if err := scanner.Scan(&id); err != nil {
+		return -1, fmt.Errorf("failed to scan id: %v", err)
+	}
+
+	return id, nil
+}
+
+// GetByWalletID returns every transaction of a wallet, newest first
+func (r *WalletTransactionRepoImpl) GetByWalletID(ctx context.Context, walletID int64) ([]WalletTransaction, error) {
+	txn, err := dbtxn.Use(ctx, r.DB)
+	if err != nil {
+		return nil, err
+	}
+
+	builder := r.selectBuilder().
+		Where(sq.Eq{WalletTransactionTable.WalletID: walletID}).
+		OrderBy(WalletTransactionTable.ID + " DESC")
+
+	rows, err := builder.RunWith(txn).QueryContext(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute query: %v", err)
+	}
+	defer rows.Close()
+
+	var transactions []WalletTransaction
+	for rows.Next() {
+		var transaction WalletTransaction
+		if err := rows.Scan(r.scanDest(&transaction)...); err != nil {
+			return nil, fmt.Errorf("failed to scan row: %v", err)
+		}
+		transactions = append(transactions, transaction)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate over rows: %v", err)
+	}
+
+	return transactions, nil
+}
+
+// GetTopUpByReferenceNumber returns the top up paid with the payment reference, nil when there is none
+func (r *WalletTransactionRepoImpl) GetTopUpByReferenceNumber(ctx context.Context, referenceNumber string) (*WalletTransaction, error) {
+	txn, err := dbtxn.Use(ctx, r.DB)
+	if err != nil {
+		return nil, err
+	}
+
+	builder := r.selectBuilder().
+		Where(sq.Eq{
+			WalletTransactionTable.TransactionType: enum.WalletTopUp,
+			WalletTransactionTable.ReferenceNumber: referenceNumber,
+		})
+
+	var transaction WalletTransaction
+	scanner := builder.RunWith(txn).QueryRowContext(ctx)
+	if err := scanner.Scan(r.scanDest(&transaction)...); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to scan wallet transaction: %v", err)
+	}
+
+	return &transaction, nil
+}
+
+func (r *WalletTransactionRepoImpl) selectBuilder() sq.SelectBuilder {
+	return sq.
+		Select(
+			WalletTransactionTable.ID,
+			WalletTransactionTable.WalletID,
+			WalletTransactionTable.LenderID,
+			WalletTransactionTable.TransactionCode,
+			WalletTransactionTable.TransactionType,
+			WalletTransactionTable.Amount,
+			WalletTransactionTable.ReferenceID,
+			WalletTransactionTable.ReferenceNumber,
+			WalletTransactionTable.AvailableBalanceAfter,
+			WalletTransactionTable.HeldBalanceAfter,
+			WalletTransactionTable.CreatedAt,
+		).
+		From(WalletTransactionTableName).
+		PlaceholderFormat(sq.Dollar)
+}
+
+func (r *WalletTransactionRepoImpl) scanDest(transaction *WalletTransaction) []interface{} {
+	return []interface{}{
+		&transaction.ID,
+		&transaction.WalletID,
+		&transaction.LenderID,
+		&transaction.TransactionCode,
+		&transaction.TransactionType,
+		&transaction.Amount,
+		&transaction.ReferenceID,
+		&transaction.ReferenceNumber,
+		&transaction.AvailableBalanceAfter,
+		&transaction.HeldBalanceAfter,
+		&transaction.CreatedAt,
+	}
+}
@@ -0,0 +1,287 @@
+package repo
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	sq "github.com/Masterminds/squirrel"
+	"github.com/test/loan-service/internal/enum"
+	"github.com/test/loan-service/internal/money"
+	"github.com/typical-go/typical-rest-server/pkg/dbtxn"
+	"go.uber.org/dig"
+	"time"
+)
+
+//go:generate mockgen -source=$GOFILE -destination=$PROJ/internal/generated/mock/mock_$GOPACKAGE/$GOFILE
+
+type (
+	WalletWithdrawal struct {
+		ID                int64                       `db:"id"`                  // Withdrawal ID
+		WithdrawalCode    string                      `db:"withdrawal_code"`     // Withdrawal code
+		LenderID          int64                       `db:"lender_id"`           // Lender requesting the withdrawal
+		Amount            money.Amount                `db:"amount"`              // Amount to withdraw
+		BankName          string                      `db:"bank_name"`           // Destination bank
+		BankAccountNumber string                      `db:"bank_account_number"` // Destination bank account number
+		BankAccountName   string                      `db:"bank_account_name"`   // Destination bank account holder
+		Status            enum.WalletWithdrawalStatus `db:"status"`              // Withdrawal status
+		ReviewedBy        string                      `db:"reviewed_by"`         // Staff who approved or rejected the withdrawal
+		ReviewNote        string                      `db:"review_note"`         // Note of the reviewer
+		ReviewedAt        *time.Time                  `db:"reviewed_at"`         // Date of review
+		CreatedAt         time.Time                   `db:"created_at"`          // Date of creation
+		UpdatedAt         time.Time                   `db:"updated_at"`          // Date of last update
+		DeletedAt         *time.Time                  `db:"deleted_at"`          // Date of deletion if applicable
+	}
+
+	WalletWithdrawalRequest struct {
+		LenderID *int64
+		Status   enum.WalletWithdrawalStatus
+	}
+
+	WalletWithdrawalRepo interface {
+		Create(ctx context.Context, withdrawal *WalletWithdrawal) (int64, error)
+		Update(ctx context.Context, withdrawal *WalletWithdrawal) error
+		GetByID(ctx context.Context, id int64) (*WalletWithdrawal, error)
+		GetByIDForUpdate(ctx context.Context, id int64) (*WalletWithdrawal, error)
+		GetAll(ctx context.Context, request WalletWithdrawalRequest) ([]WalletWithdrawal, error)
+	}
+
+	WalletWithdrawalRepoImpl struct {
+		dig.In
+		*sql.DB
+	}
+)
+
+var (
+	WalletWithdrawalTableName = "wallet_withdrawals"
+	WalletWithdrawalTable     = struct {
+		ID                string
+		WithdrawalCode    string
+		LenderID          string
+		Amount            string
+		BankName          string
+		BankAccountNumber string
+		BankAccountName   string
+		Status            string
+		ReviewedBy        string
+		ReviewNote        string
+		ReviewedAt        string
+		CreatedAt         string
+		UpdatedAt         string
+		DeletedAt         string
+	}{
+		ID:                "id",
+		WithdrawalCode:    "withdrawal_code",
+		LenderID:          "lender_id",
+		Amount:            "amount",
+		BankName:          "bank_name",
+		BankAccountNumber: "bank_account_number",
+		BankAccountName:   "bank_account_name",
+		Status:            "status",
+		ReviewedBy:        "reviewed_by",
+		ReviewNote:        "review_note",
+		ReviewedAt:        "reviewed_at",
+		CreatedAt:         "created_at",
+		UpdatedAt:         "updated_at",
+		DeletedAt:         "deleted_at",
+	}
+)
+
+func NewWalletWithdrawalRepo(impl WalletWithdrawalRepoImpl) WalletWithdrawalRepo {
+	return &impl
+}
+
+// Create WalletWithdrawal and return last inserted id
+func (r *WalletWithdrawalRepoImpl) Create(ctx context.Context, withdrawal *WalletWithdrawal) (int64, error) {
+	txn, err := dbtxn.Use(ctx, r.DB)
+	if err != nil {
+		return -1, err
+	}
+
+	builder := sq.
+		Insert(WalletWithdrawalTableName).
+		Columns(
+			WalletWithdrawalTable.WithdrawalCode,
+			WalletWithdrawalTable.LenderID,
+			WalletWithdrawalTable.Amount,
+			WalletWithdrawalTable.BankName,
+			WalletWithdrawalTable.BankAccountNumber,
+			WalletWithdrawalTable.BankAccountName,
+			WalletWithdrawalTable.Status,
+			WalletWithdrawalTable.CreatedAt,
+			WalletWithdrawalTable.UpdatedAt,
+			WalletWithdrawalTable.DeletedAt,
+		).
+		Suffix("RETURNING id").
+		PlaceholderFormat(sq.Dollar).
+		Values(
+			withdrawal.WithdrawalCode,
+			withdrawal.LenderID,
+			withdrawal.Amount,
+			withdrawal.BankName,
+			withdrawal.BankAccountNumber,
+			withdrawal.BankAccountName,
+			withdrawal.Status,
+			time.Now(),
+			time.Now(),
+			nil,
+		)
+
+	scanner := builder.RunWith(txn).QueryRowContext(ctx)
+
+	var id int64
+	if err := scanner.Scan(&id); err != nil {
+		return -1, fmt.Errorf("failed to scan id: %v", err)
+	}
+
+	return id, nil
+}
+
+// Update the status and review of a WalletWithdrawal
+func (r *WalletWithdrawalRepoImpl) Update(ctx context.Context, withdrawal *WalletWithdrawal) error {
+	txn, err := dbtxn.Use(ctx, r.DB)
+	if err != nil {
+		return err
+	}
+
+	builder := sq.Update(WalletWithdrawalTableName).
+		Set(WalletWithdrawalTable.Status, withdrawal.Status).
+		Set(WalletWithdrawalTable.ReviewedBy, withdrawal.ReviewedBy).
+		Set(WalletWithdrawalTable.ReviewNote, withdrawal.ReviewNote).
+		Set(WalletWithdrawalTable.ReviewedAt, withdrawal.ReviewedAt).
+		Set(WalletWithdrawalTable.UpdatedAt, time.Now()).
+		Where(sq.Eq{WalletWithdrawalTable.ID: withdrawal.ID}).
+		PlaceholderFormat(sq.Dollar)
+
+	result, err := builder.RunWith(txn).ExecContext(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to update wallet withdrawal: %v", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %v", err)
+	}
+	if rowsAffected == 0 {
+		return fmt.Errorf("no wallet withdrawal found with ID: %d", withdrawal.ID)
+	}
+
+	return nil
+}
+
+// GetByID returns the withdrawal, nil when it does not exist
+func (r *WalletWithdrawalRepoImpl) GetByID(ctx context.Context, id int64) (*WalletWithdrawal, error) {
+	return r.getByID(ctx, id, false)
+}
+
+// GetByIDForUpdate is like GetByID but locks the withdrawal row until the transaction ends
+func (r *WalletWithdrawalRepoImpl) GetByIDForUpdate(ctx context.Context, id int64) (*WalletWithdrawal, error) {
+	return r.getByID(ctx, id, true)
+}
+
+// GetAll returns the withdrawals matching the request, newest first
+func (r *WalletWithdrawalRepoImpl) GetAll(ctx context.Context, request WalletWithdrawalRequest) ([]WalletWithdrawal, error) {
+	txn, err := dbtxn.Use(ctx, r.DB)
+	if err != nil {
+		return nil, err
+	}
+
+	builder := r.selectBuilder().
+		Where(sq.Eq{WalletWithdrawalTable.DeletedAt: nil}).
+		OrderBy(WalletWithdrawalTable.ID + " DESC")
+
+	if request.LenderID != nil {
+		builder = builder.Where(sq.Eq{WalletWithdrawalTable.LenderID: *request.LenderID})
+	}
+	if request.Status != "" {
+		builder = builder.Where(sq.Eq{WalletWithdrawalTable.Status: request.Status})
+	}
+
+	rows, err := builder.RunWith(txn).QueryContext(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute query: %v", err)
+	}
+	defer rows.Close()
+
+	var withdrawals []WalletWithdrawal
+	for rows.Next() {
+		var withdrawal WalletWithdrawal
+		if err := rows.Scan(r.scanDest(&withdrawal)...); err != nil {
+			return nil, fmt.Errorf("failed to scan row: %v", err)
+		}
+		withdrawals = append(withdrawals, withdrawal)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate over rows: %v", err)
+	}
+
+	return withdrawals, nil
+}
+
+func (r *WalletWithdrawalRepoImpl) getByID(ctx context.Context, id int64, forUpdate bool) (*WalletWithdrawal, error) {
+	txn, err := dbtxn.Use(ctx, r.DB)
+	if err != nil {
+		return nil, err
+	}
+
+	builder := r.selectBuilder().
+		Where(sq.Eq{
+			WalletWithdrawalTable.ID:        id,
+			WalletWithdrawalTable.DeletedAt: nil,
+		})
+	if forUpdate {
+		builder = builder.Suffix("FOR UPDATE")
+	}
+
+	var withdrawal WalletWithdrawal
+	scanner := builder.RunWith(txn).QueryRowContext(ctx)
+	if err := scanner.Scan(r.scanDest(&withdrawal)...); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to scan wallet withdrawal: %v", err)
+	}
+
+	return &withdrawal, nil
+}
+
+func (r *WalletWithdrawalRepoImpl) selectBuilder() sq.SelectBuilder {
+	return sq.
+		Select(
+			WalletWithdrawalTable.ID,
+			WalletWithdrawalTable.WithdrawalCode,
+			WalletWithdrawalTable.LenderID,
+			WalletWithdrawalTable.Amount,
+			WalletWithdrawalTable.BankName,
+			WalletWithdrawalTable.BankAccountNumber,
+			WalletWithdrawalTable.BankAccountName,
+			WalletWithdrawalTable.Status,
+			WalletWithdrawalTable.ReviewedBy,
+			WalletWithdrawalTable.ReviewNote,
+			WalletWithdrawalTable.ReviewedAt,
+			WalletWithdrawalTable.CreatedAt,
+			WalletWithdrawalTable.UpdatedAt,
+			WalletWithdrawalTable.DeletedAt,
+		).
+		From(WalletWithdrawalTableName).
+		PlaceholderFormat(sq.Dollar)
+}
+
+func (r *WalletWithdrawalRepoImpl) scanDest(withdrawal *WalletWithdrawal) []interface{} {
+	return []interface{}{
+		&withdrawal.ID,
+		&withdrawal.WithdrawalCode,
+		&withdrawal.LenderID,
+		&withdrawal.Amount,
+		&withdrawal.BankName,
+		&withdrawal.BankAccountNumber,
+		&withdrawal.BankAccountName,
+		&withdrawal.Status,
+		&withdrawal.ReviewedBy,
+		&withdrawal.ReviewNote,
+		&withdrawal.ReviewedAt,
+		&withdrawal.CreatedAt,
+		&withdrawal.UpdatedAt,
+		&withdrawal.DeletedAt,
+	}
+}
@@ -0,0 +1,188 @@
+package repo
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	sq "github.com/Masterminds/squirrel"
+	"github.com/test/loan-service/internal/enum"
+	"github.com/typical-go/typical-rest-server/pkg/dbtxn"
+	"go.uber.org/dig"
+	"time"
+)
+
+//go:generate mockgen -source=$GOFILE -destination=$PROJ/internal/generated/mock/mock_$GOPACKAGE/$GOFILE
+
+type (
+	WithholdingTaxRate struct {
+		ID          int64           `db:"id"`             // Withholding tax rate ID
+		LenderType  enum.LenderType `db:"lender_type"`    // Lender type the rate applies to
+		Rate        float64         `db:"rate"`           // Percentage withheld from the interest of a lender with a tax ID
+		NoTaxIDRate float64         `db:"no_tax_id_rate"` // Percentage withheld from the interest of a lender without a tax ID
+		CreatedAt   time.Time       `db:"created_at"`     // Date of creation
+		UpdatedAt   time.Time       `db:"updated_at"`     // Date of last update
+		DeletedAt   *time.Time      `db:"deleted_at"`     // Date of deletion if applicable
+	}
+
+	WithholdingTaxRateRepo interface {
+		Update(ctx context.Context, rate *WithholdingTaxRate) error
+		GetByLenderType(ctx context.Context, lenderType enum.LenderType) (*WithholdingTaxRate, error)
+		GetAll(ctx context.Context) ([]WithholdingTaxRate, error)
+	}
+
+	WithholdingTaxRateRepoImpl struct {
+		dig.In
+		*sql.DB
+	}
+)
+
+var (
+	WithholdingTaxRateTableName = "withholding_tax_rates"
+	WithholdingTaxRateTable     = struct {
+		ID          string
+		LenderType  string
+		Rate        string
+		NoTaxIDRate string
+		CreatedAt   string
+		UpdatedAt   string
+		DeletedAt   string
+	}{
+		ID:          "id",
+		LenderType:  "lender_type",
+		Rate:        "rate",
+		NoTaxIDRate: "no_tax_id_rate",
+		CreatedAt:   "created_at",
+		UpdatedAt:   "updated_at",
+		DeletedAt:   "deleted_at",
+	}
+)
+
+func NewWithholdingTaxRateRepo(impl WithholdingTaxRateRepoImpl) WithholdingTaxRateRepo {
+	return &impl
+}
+
+// Update WithholdingTaxRate of a lender type
+func (r *WithholdingTaxRateRepoImpl) Update(ctx context.Context, rate *WithholdingTaxRate) error {
+	txn, err := dbtxn.Use(ctx, r.DB)
+	if err != nil {
+		return err
+	}
+
+	builder := sq.Update(WithholdingTaxRateTableName).
+		Set(WithholdingTaxRateTable.Rate, rate.Rate).
+		Set(WithholdingTaxRateTable.NoTaxIDRate, rate.NoTaxIDRate).
+		Set(WithholdingTaxRateTable.UpdatedAt, time.Now()).
+		Where(sq.Eq{WithholdingTaxRateTable.LenderType: rate.LenderType}).
+		PlaceholderFormat(sq.Dollar)
+
+	result, err := builder.RunWith(txn).ExecContext(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to update withholding tax rate: %v", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %v", err)
+	}
+	if rowsAffected == 0 {
+		return fmt.Errorf("no withholding tax rate found for lender type: %s", rate.LenderType)
+	}
+
+	return nil
+}
+
+// GetByLenderType returns the withholding tax rate of a lender type, nil when the lender type has no rate
+func (r *WithholdingTaxRateRepoImpl) GetByLenderType(ctx context.Context, lenderType enum.LenderType) (*WithholdingTaxRate, error) {
+	txn, err := dbtxn.Use(ctx, r.DB)
+	if err != nil {
+		return nil, err
+	}
+
+	builder := sq.
+		Select(
+			WithholdingTaxRateTable.ID,
+			WithholdingTaxRateTable.LenderType,
+			WithholdingTaxRateTable.Rate,
+			WithholdingTaxRateTable.NoTaxIDRate,
+			WithholdingTaxRateTable.CreatedAt,
+			WithholdingTaxRateTable.UpdatedAt,
+			WithholdingTaxRateTable.DeletedAt,
+		).
+		From(WithholdingTaxRateTableName).
+		Where(sq.Eq{
+			WithholdingTaxRateTable.LenderType: lenderType,
+			WithholdingTaxRateTable.DeletedAt:  nil,
+		}).
+		PlaceholderFormat(sq.Dollar)
+
+	var rate WithholdingTaxRate
+	scanner := builder.RunWith(txn).QueryRowContext(ctx)
+	if err := scanner.Scan(
+		&rate.ID,
+		&rate.LenderType,
+		&rate.Rate,
+		&rate.NoTaxIDRate,
+		&rate.CreatedAt,
+		&rate.UpdatedAt,
+		&rate.DeletedAt,
+	); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to scan withholding tax rate: %v", err)
+	}
+
+	return &rate, nil
+}
+
+// GetAll returns every withholding tax rate ordered by lender type
+func (r *WithholdingTaxRateRepoImpl) GetAll(ctx context.Context) ([]WithholdingTaxRate, error) {
+	txn, err := dbtxn.Use(ctx, r.DB)
+	if err != nil {
+		return nil, err
+	}
+
+	builder := sq.
+		Select(
+			WithholdingTaxRateTable.ID,
+			WithholdingTaxRateTable.LenderType,
+			WithholdingTaxRateTable.Rate,
+			WithholdingTaxRateTable.NoTaxIDRate,
+			WithholdingTaxRateTable.CreatedAt,
+			WithholdingTaxRateTable.UpdatedAt,
+			WithholdingTaxRateTable.DeletedAt,
+		).
+		From(WithholdingTaxRateTableName).
+		Where(sq.Eq{WithholdingTaxRateTable.DeletedAt: nil}).
+		OrderBy(WithholdingTaxRateTable.LenderType + " ASC").
+		PlaceholderFormat(sq.Dollar)
+
+	rows, err := builder.RunWith(txn).QueryContext(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute query: %v", err)
+	}
+	defer rows.Close()
+
+	var rates []WithholdingTaxRate
+	for rows.Next() {
+		var rate WithholdingTaxRate
+		if err := rows.Scan(
+			&rate.ID,
+			&rate.LenderType,
+			&rate.Rate,
+			&rate.NoTaxIDRate,
+			&rate.CreatedAt,
+			&rate.UpdatedAt,
+			&rate.DeletedAt,
+		); err != nil {
+			return nil, fmt.Errorf("failed to scan row: %v", err)
+		}
+		rates = append(rates, rate)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate over rows: %v", err)
+	}
+
+	return rates, nil
+}
@@ -0,0 +1,446 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"github.com/mitchellh/mapstructure"
+	log "github.com/sirupsen/logrus"
+	"github.com/test/loan-service/internal/dto"
+	"github.com/test/loan-service/internal/enum"
+	"github.com/test/loan-service/internal/pdf"
+	repo "github.com/test/loan-service/internal/repository"
+	"github.com/test/loan-service/internal/service/models"
+	"github.com/test/loan-service/internal/service/validator"
+	"github.com/test/loan-service/internal/storage"
+	"github.com/test/loan-service/internal/utils"
+	"go.uber.org/dig"
+	"strings"
+	"text/template"
+	"time"
+)
+
+//go:generate mockgen -source=$GOFILE -destination=$PROJ/internal/generated/mock/mock_$GOPACKAGE/$GOFILE
+
+type (
+	// AgreementSvc renders agreements from versioned templates. A template is never changed once
+	// created, a new version is added instead and used for the agreements generated from then on, so
+	// every agreement can be traced back to the exact text it was rendered from.
+	AgreementSvc interface {
+		CreateTemplate(ctx context.Context, request *dto.AgreementTemplateRequestDTO) (*dto.AgreementTemplateResponseDTO, error)
+		GetTemplates(ctx context.Context, agreementType enum.AgreementType) ([]dto.AgreementTemplateResponseDTO, error)
+		GetTemplate(ctx context.Context, id int64) (*dto.AgreementTemplateResponseDTO, error)
+		GenerateLoanAgreement(ctx context.Context, loan *repo.Loan, disbursement *repo.LoanDisbursement) (*repo.LoanAgreement, error)
+		GetByLoanID(ctx context.Context, loanID int64) ([]dto.LoanAgreementResponseDTO, error)
+		GenerateFundingAgreement(ctx context.Context, loan *repo.Loan, funding *repo.LoanFunding) (*repo.FundingAgreement, *models.DocumentFile, error)
+		GetByLoanFundingID(ctx context.Context, loanFundingID int64) ([]dto.FundingAgreementResponseDTO, error)
+	}
+
+	AgreementSvcImpl struct {
+		dig.In
+		TemplateRepo         repo.AgreementTemplateRepo
+		LoanAgreementRepo    repo.LoanAgreementRepo
+		FundingAgreementRepo repo.FundingAgreementRepo
+		LoanDetailRepo       repo.LoanDetailRepo
+		DocumentStore        storage.DocumentStore
+		DocumentSvc          DocumentSvc
+		URLSigner            *storage.URLSigner
+		Validator            validator.AgreementTemplateValidatorImpl
+	}
+)
+
+func NewAgreementSvc(impl AgreementSvcImpl) AgreementSvc {
+	return &impl
+}
+
+// CreateTemplate adds the next version of the templates of an agreement type
+func (s *AgreementSvcImpl) CreateTemplate(ctx context.Context, request *dto.AgreementTemplateRequestDTO) (*dto.AgreementTemplateResponseDTO, error) {
+	log.WithFields(log.Fields{
+		"agreementType": request.AgreementType,
+		"title":         request.Title,
+	}).Info("Creating agreement template")
+
+	err := s.Validator.ValidateCreate(request)
+	if err != nil {
+		log.WithField("agreementType", request.AgreementType).Errorf("Validation failed: %s", err)
+		return nil, err
+	}
+
+	latest, err := s.TemplateRepo.GetLatest(ctx, request.AgreementType)
+	if err != nil {
+		log.WithField("agreementType", request.AgreementType).WithError(err).Error("Failed to get latest agreement template")
+		return nil, errors.New("99999")
+	}
+
+	template := repo.AgreementTemplate{
+		AgreementType: request.AgreementType,
+		Version:       1,
+		Title:         request.Title,
+		Body:          request.Body,
+	}
+	if latest != nil {
+		template.Version = latest.Version + 1
+	}
+
+	id, err := s.TemplateRepo.Create(ctx, &template)
+	if err != nil {
+		log.WithField("agreementType", request.AgreementType).WithError(err).Error("Failed to create agreement template")
+		return nil, errors.New("99999")
+	}
+
+	log.WithFields(log.Fields{
+		"templateID":    id,
+		"agreementType": template.AgreementType,
+		"version":       template.Version,
+	}).Info("Agreement template created successfully")
+	return s.GetTemplate(ctx, id)
+}
+
+// GetTemplates returns every version of the templates of an agreement type, latest first
+func (s *AgreementSvcImpl) GetTemplates(ctx context.Context, agreementType enum.AgreementType) ([]dto.AgreementTemplateResponseDTO, error) {
+	if !agreementType.IsValid() {
+		log.WithField("agreementType", agreementType).Error("Invalid AgreementType")
+		return nil, errors.New("10002")
+	}
+
+	templates, err := s.TemplateRepo.GetByAgreementType(ctx, agreementType)
+	if err != nil {
+		log.WithField("agreementType", agreementType).WithError(err).Error("Failed to get agreement templates")
+		return nil, errors.New("99999")
+	}
+
+	templateDTOs := []dto.AgreementTemplateResponseDTO{}
+	for _, template := range templates {
+		templateRes, err := s.toTemplateResponseDTO(&template)
+		if err != nil {
+			return nil, err
+		}
+		templateDTOs = append(templateDTOs, *templateRes)
+	}
+
+	return templateDTOs, nil
+}
+
+func (s *AgreementSvcImpl) GetTemplate(ctx context.Context, id int64) (*dto.AgreementTemplateResponseDTO, error) {
+	template, err := s.TemplateRepo.GetByID(ctx, id)
+	if err != nil {
+		log.WithField("templateID", id).WithError(err).Error("Failed to get agreement template")
+		return nil, errors.New("99999")
+	}
+	if template == nil {
+		log.WithField("templateID", id).Error("Agreement template not found")
+		return nil, errors.New("10001")
+	}
+
+	return s.toTemplateResponseDTO(template)
+}
+
+// GenerateLoanAgreement renders the borrower agreement of a loan with the latest borrower template,
+// stores the pdf in the document store and records it. The amounts are the ones of the disbursement
+// the agreement is generated for.
+func (s *AgreementSvcImpl) GenerateLoanAgreement(ctx context.Context, loan *repo.Loan, disbursement *repo.LoanDisbursement) (*repo.LoanAgreement, error) {
+	log.WithField("loanID", loan.ID).Info("Generating loan agreement")
+
+	template, err := s.TemplateRepo.GetLatest(ctx, enum.AgreementBorrower)
+	if err != nil {
+		log.WithField("loanID", loan.ID).WithError(err).Error("Failed to get borrower agreement template")
+		return nil, errors.New("99999")
+	}
+	if template == nil {
+		log.WithField("loanID", loan.ID).Error("No borrower agreement template available")
+		return nil, errors.New("10011")
+	}
+
+	detail, err := s.LoanDetailRepo.GetByLoanID(ctx, loan.ID)
+	if err != nil {
+		log.WithField("loanID", loan.ID).WithError(err).Error("Failed to get loan detail")
+		return nil, errors.New("99999")
+	}
+	if detail == nil {
+		// a loan without detail still gets an agreement, the business fields are left empty
+		detail = &repo.LoanDetail{BorrowerID: loan.BorrowerID}
+	}
+
+	now := time.Now()
+	agreement := repo.LoanAgreement{
+		AgreementNumber: "AGR-" + utils.GenerateAlphanumericCode(10),
+		LoanID:          loan.ID,
+		TemplateID:      template.ID,
+		TemplateVersion: template.Version,
+		GeneratedAt:     now,
+	}
+
+	data := models.LoanAgreementData{
+		AgreementNumber:            agreement.AgreementNumber,
+		AgreementDate:              now.Format("2006-01-02"),
+		TemplateVersion:            template.Version,
+		LoanCode:                   loan.LoanCode,
+		BorrowerName:               detail.BusinessOwnerName,
+		BorrowerID:                 loan.BorrowerID,
+		BusinessName:               detail.BusinessName,
+		BusinessRegistrationNumber: detail.BusinessRegistrationNumber,
+		BusinessAddress:            detail.BusinessAddress,
+		LoanType:                   loan.LoanType,
+		LoanGrade:                  loan.LoanGrade,
+		LoanPurpose:                detail.LoanPurpose,
+		PrincipalAmount:            disbursement.DisburseAmount,
+		OriginationFee:             disbursement.OriginationFee,
+		NetDisburseAmount:          disbursement.NetDisburseAmount,
+		Rate:                       loan.Rate,
+		Tenures:                    loan.Tenures,
+		TotalInterest:              loan.TotalInterest,
+		TotalRepaymentAmount:       loan.TotalRepaymentAmount,
+	}
+
+	content, err := renderAgreement(template, data)
+	if err != nil {
+		log.WithFields(log.Fields{
+			"loanID":     loan.ID,
+			"templateID": template.ID,
+		}).WithError(err).Error("Failed to render loan agreement")
+		return nil, errors.New("99999")
+	}
+
+	// the hash is what the borrower signature is verified against
+	documentHash := storage.Checksum(content)
+	agreement.DocumentHash = &documentHash
+	agreement.DocumentKey = fmt.Sprintf("agreements/loans/%s/%s.pdf", loan.LoanCode, agreement.AgreementNumber)
+	_, err = s.DocumentStore.Put(ctx, agreement.DocumentKey, content)
+	if err != nil {
+		log.WithField("loanID", loan.ID).WithError(err).Error("Failed to store loan agreement")
+		return nil, errors.New("99999")
+	}
+
+	document := repo.Document{
+		StorageKey:   agreement.DocumentKey,
+		FileName:     agreement.AgreementNumber + ".pdf",
+		ContentType:  "application/pdf",
+		EntityType:   enum.DocumentEntityLoan,
+		EntityID:     loan.ID,
+		DocumentType: "loan_agreement",
+	}
+	err = s.DocumentSvc.Register(ctx, &document, content)
+	if err != nil {
+		return nil, errors.New("99999")
+	}
+	// the agreement holds borrower data, it is only linked through the document record and its signed URLs
+	agreement.DocumentURL = s.URLSigner.DocumentURL(document.ID)
+
+	agreement.ID, err = s.LoanAgreementRepo.Create(ctx, &agreement)
+	if err != nil {
+		log.WithField("loanID", loan.ID).WithError(err).Error("Failed to create loan agreement")
+		return nil, errors.New("99999")
+	}
+
+	log.WithFields(log.Fields{
+		"loanID":          loan.ID,
+		"agreementNumber": agreement.AgreementNumber,
+		"templateVersion": agreement.TemplateVersion,
+	}).Info("Loan agreement generated successfully")
+	return &agreement, nil
+}
+
+// GetByLoanID returns the agreements generated for a loan, latest first
+func (s *AgreementSvcImpl) GetByLoanID(ctx context.Context, loanID int64) ([]dto.LoanAgreementResponseDTO, error) {
+	agreements, err := s.LoanAgreementRepo.GetByLoanID(ctx, loanID)
+	if err != nil {
+		log.WithField("loanID", loanID).WithError(err).Error("Failed to get loan agreements")
+		return nil, errors.New("99999")
+	}
+
+	agreementDTOs := []dto.LoanAgreementResponseDTO{}
+	for _, agreement := range agreements {
+		var agreementRes dto.LoanAgreementResponseDTO
+		err = mapstructure.Decode(agreement, &agreementRes)
+		if err != nil {
+			log.WithField("agreementID", agreement.ID).WithError(err).Error("Failed to map loan agreement to DTO")
+			return nil, errors.New("99999")
+		}
+		agreementRes.GeneratedAt = agreement.GeneratedAt
+
+		agreementDTOs = append(agreementDTOs, agreementRes)
+	}
+
+	return agreementDTOs, nil
+}
+
+// GenerateFundingAgreement renders the investment agreement of a lender with the latest lender
+// template, stores the pdf in the document store and records it. The amounts are the ones locked on
+// the funding when it was invested, the returned file is the rendered pdf.
+func (s *AgreementSvcImpl) GenerateFundingAgreement(ctx context.Context, loan *repo.Loan, funding *repo.LoanFunding) (*repo.FundingAgreement, *models.DocumentFile, error) {
+	log.WithField("loanFundingID", funding.ID).Info("Generating funding agreement")
+
+	template, err := s.TemplateRepo.GetLatest(ctx, enum.AgreementLender)
+	if err != nil {
+		log.WithField("loanFundingID", funding.ID).WithError(err).Error("Failed to get lender agreement template")
+		return nil, nil, errors.New("99999")
+	}
+	if template == nil {
+		log.WithField("loanFundingID", funding.ID).Error("No lender agreement template available")
+		return nil, nil, errors.New("10011")
+	}
+
+	detail, err := s.LoanDetailRepo.GetByLoanID(ctx, loan.ID)
+	if err != nil {
+		log.WithField("loanID", loan.ID).WithError(err).Error("Failed to get loan detail")
+		return nil, nil, errors.New("99999")
+	}
+	if detail == nil {
+		// a loan without detail still gets an agreement, the borrower summary is left empty
+		detail = &repo.LoanDetail{BorrowerID: loan.BorrowerID}
+	}
+
+	now := time.Now()
+	agreement := repo.FundingAgreement{
+		AgreementNumber: "INV-" + utils.GenerateAlphanumericCode(10),
+		LoanFundingID:   funding.ID,
+		LoanID:          loan.ID,
+		LenderID:        funding.LenderID,
+		TemplateID:      template.ID,
+		TemplateVersion: template.Version,
+		GeneratedAt:     now,
+	}
+
+	data := models.FundingAgreementData{
+		AgreementNumber:      agreement.AgreementNumber,
+		AgreementDate:        now.Format("2006-01-02"),
+		TemplateVersion:      template.Version,
+		LoanCode:             loan.LoanCode,
+		LoanOrderNumber:      funding.LoanOrderNumber,
+		LenderID:             funding.LenderID,
+		LenderEmail:          funding.LenderEmail,
+		InvestmentAmount:     funding.InvestmentAmount,
+		Rate:                 funding.Rate,
+		Tenures:              loan.Tenures,
+		Interest:             funding.Interest,
+		ServiceFeePercentage: funding.ServiceFeePercentage,
+		ServiceFee:           funding.ServiceFee,
+		WithholdingTax:       funding.WithholdingTax,
+		ROI:                  funding.ROI,
+		BusinessName:         detail.BusinessName,
+		BusinessSector:       detail.BusinessSector,
+		BusinessAge:          detail.BusinessAge,
+		LoanType:             loan.LoanType,
+		LoanGrade:            loan.LoanGrade,
+		LoanPurpose:          detail.LoanPurpose,
+		LoanAmount:           loan.RequestAmount,
+	}
+
+	content, err := renderAgreement(template, data)
+	if err != nil {
+		log.WithFields(log.Fields{
+			"loanFundingID": funding.ID,
+			"templateID":    template.ID,
+		}).WithError(err).Error("Failed to render funding agreement")
+		return nil, nil, errors.New("99999")
+	}
+
+	agreement.DocumentKey = fmt.Sprintf("agreements/fundings/%s/%s.pdf", funding.LoanOrderNumber, agreement.AgreementNumber)
+	_, err = s.DocumentStore.Put(ctx, agreement.DocumentKey, content)
+	if err != nil {
+		log.WithField("loanFundingID", funding.ID).WithError(err).Error("Failed to store funding agreement")
+		return nil, nil, errors.New("99999")
+	}
+
+	document := repo.Document{
+		StorageKey:   agreement.DocumentKey,
+		FileName:     agreement.AgreementNumber + ".pdf",
+		ContentType:  "application/pdf",
+		EntityType:   enum.DocumentEntityLoanFunding,
+		EntityID:     funding.ID,
+		DocumentType: "funding_agreement",
+	}
+	err = s.DocumentSvc.Register(ctx, &document, content)
+	if err != nil {
+		return nil, nil, errors.New("99999")
+	}
+	agreement.DocumentURL = s.URLSigner.DocumentURL(document.ID)
+
+	agreement.ID, err = s.FundingAgreementRepo.Create(ctx, &agreement)
+	if err != nil {
+		log.WithField("loanFundingID", funding.ID).WithError(err).Error("Failed to create funding agreement")
+		return nil, nil, errors.New("99999")
+	}
+
+	log.WithFields(log.Fields{
+		"loanFundingID":   funding.ID,
+		"agreementNumber": agreement.AgreementNumber,
+		"templateVersion": agreement.TemplateVersion,
+	}).Info("Funding agreement generated successfully")
+	return &agreement, &models.DocumentFile{
+		FileName:    agreement.AgreementNumber + ".pdf",
+		ContentType: "application/pdf",
+		Content:     content,
+	}, nil
+}
+
+// GetByLoanFundingID returns the agreements generated for a funding, latest first
+func (s *AgreementSvcImpl) GetByLoanFundingID(ctx context.Context, loanFundingID int64) ([]dto.FundingAgreementResponseDTO, error) {
+	agreements, err := s.FundingAgreementRepo.GetByLoanFundingID(ctx, loanFundingID)
+	if err != nil {
+		log.WithField("loanFundingID", loanFundingID).WithError(err).Error("Failed to get funding agreements")
+		return nil, errors.New("99999")
+	}
+
+	agreementDTOs := []dto.FundingAgreementResponseDTO{}
+	for _, agreement := range agreements {
+		var agreementRes dto.FundingAgreementResponseDTO
+		err = mapstructure.Decode(agreement, &agreementRes)
+		if err != nil {
+			log.WithField("agreementID", agreement.ID).WithError(err).Error("Failed to map funding agreement to DTO")
+			return nil, errors.New("99999")
+		}
+		agreementRes.GeneratedAt = agreement.GeneratedAt
+
+		agreementDTOs = append(agreementDTOs, agreementRes)
+	}
+
+	return agreementDTOs, nil
+}
+
+func (s *AgreementSvcImpl) toTemplateResponseDTO(template *repo.AgreementTemplate) (*dto.AgreementTemplateResponseDTO, error) {
+	var templateRes dto.AgreementTemplateResponseDTO
+	err := mapstructure.Decode(template, &templateRes)
+	if err != nil {
+		log.WithField("templateID", template.ID).WithError(err).Error("Failed to map agreement template to DTO")
+		return nil, errors.New("99999")
+	}
+	templateRes.CreatedAt = template.CreatedAt
+	templateRes.UpdatedAt = template.UpdatedAt
+	templateRes.DeletedAt = template.DeletedAt
+
+	return &templateRes, nil
+}
+
+// renderAgreement fills the template body with the data and lays it out as a pdf. A line starting
+// with "# " is a section heading and an empty line separates paragraphs.
+func renderAgreement(agreementTemplate *repo.AgreementTemplate, data interface{}) ([]byte, error) {
+	tmpl, err := template.New(string(agreementTemplate.AgreementType)).Option("missingkey=error").Parse(agreementTemplate.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse template %d: %v", agreementTemplate.ID, err)
+	}
+
+	var body bytes.Buffer
+	if err := tmpl.Execute(&body, data); err != nil {
+		return nil, fmt.Errorf("failed to execute template %d: %v", agreementTemplate.ID, err)
+	}
+
+	doc := pdf.New(agreementTemplate.Title)
+	doc.Heading(agreementTemplate.Title)
+	doc.Space()
+	for _, line := range strings.Split(body.String(), "\n") {
+		line = strings.TrimRight(line, " \r")
+		switch {
+		case strings.HasPrefix(line, "# "):
+			doc.Space()
+			doc.Bold(strings.TrimPrefix(line, "# "))
+		case line == "":
+			doc.Space()
+		default:
+			doc.Text(line)
+		}
+	}
+
+	return doc.Bytes(), nil
+}
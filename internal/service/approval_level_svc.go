@@ -0,0 +1,123 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"github.com/mitchellh/mapstructure"
+	log "github.com/sirupsen/logrus"
+	"github.com/test/loan-service/internal/dto"
+	"github.com/test/loan-service/internal/enum"
+	"github.com/test/loan-service/internal/money"
+	repo "github.com/test/loan-service/internal/repository"
+	"github.com/test/loan-service/internal/service/validator"
+	"go.uber.org/dig"
+)
+
+//go:generate mockgen -source=$GOFILE -destination=$PROJ/internal/generated/mock/mock_$GOPACKAGE/$GOFILE
+
+type (
+	// ApprovalLevelSvc holds the approval chain of the credit policy. Every level a loan reaches by its
+	// amount or its grade has to approve it, together with every level below it, in the order of the chain.
+	ApprovalLevelSvc interface {
+		Update(ctx context.Context, level int64, request *dto.ApprovalLevelRequestDTO) error
+		GetAll(ctx context.Context) ([]dto.ApprovalLevelResponseDTO, error)
+		RequiredLevel(ctx context.Context, amount money.Amount, grade enum.LoanGrade) (int64, error)
+	}
+
+	ApprovalLevelSvcImpl struct {
+		dig.In
+		Repo      repo.ApprovalLevelRepo
+		Validator validator.ApprovalLevelValidatorImpl
+	}
+)
+
+func NewApprovalLevelSvc(impl ApprovalLevelSvcImpl) ApprovalLevelSvc {
+	return &impl
+}
+
+func (s *ApprovalLevelSvcImpl) Update(ctx context.Context, level int64, request *dto.ApprovalLevelRequestDTO) error {
+	log.WithFields(log.Fields{
+		"level":     level,
+		"name":      request.Name,
+		"minAmount": request.MinAmount,
+		"minGrade":  request.MinGrade,
+	}).Info("Updating approval level")
+
+	err := s.Validator.ValidateUpdate(request)
+	if err != nil {
+		log.WithField("level", level).Errorf("Validation failed: %s", err)
+		return err
+	}
+
+	approvalLevel, err := s.Repo.GetByLevel(ctx, level)
+	if err != nil {
+		log.WithField("level", level).WithError(err).Error("Failed to get approval level")
+		return errors.New("99999")
+	}
+	if approvalLevel == nil {
+		log.WithField("level", level).Warn("Approval level not found")
+		return errors.New("10001")
+	}
+
+	approvalLevel.Name = request.Name
+	approvalLevel.MinAmount = request.MinAmount
+	approvalLevel.MinGrade = request.MinGrade
+	err = s.Repo.Update(ctx, approvalLevel)
+	if err != nil {
+		log.WithField("level", level).WithError(err).Error("Failed to update approval level")
+		return errors.New("99999")
+	}
+
+	log.WithField("level", level).Info("Approval level updated successfully")
+	return nil
+}
+
+func (s *ApprovalLevelSvcImpl) GetAll(ctx context.Context) ([]dto.ApprovalLevelResponseDTO, error) {
+	levels, err := s.Repo.GetAll(ctx)
+	if err != nil {
+		log.WithError(err).Error("Failed to get approval levels")
+		return nil, errors.New("99999")
+	}
+
+	levelDTOs := []dto.ApprovalLevelResponseDTO{}
+	for _, level := range levels {
+		var levelRes dto.ApprovalLevelResponseDTO
+		err = mapstructure.Decode(level, &levelRes)
+		if err != nil {
+			log.WithField("level", level.Level).WithError(err).Error("Failed to map approval level to DTO")
+			return nil, errors.New("99999")
+		}
+		levelRes.CreatedAt = level.CreatedAt
+		levelRes.UpdatedAt = level.UpdatedAt
+		levelRes.DeletedAt = level.DeletedAt
+
+		levelDTOs = append(levelDTOs, levelRes)
+	}
+
+	return levelDTOs, nil
+}
+
+// RequiredLevel returns the highest level of the chain a loan of the amount and grade reaches, grades
+// run from A to E so a later letter is a worse grade. A loan always needs at least one approval.
+func (s *ApprovalLevelSvcImpl) RequiredLevel(ctx context.Context, amount money.Amount, grade enum.LoanGrade) (int64, error) {
+	levels, err := s.Repo.GetAll(ctx)
+	if err != nil {
+		log.WithError(err).Error("Failed to get approval levels")
+		return 0, err
+	}
+
+	var required int64 = 1
+	for _, level := range levels {
+		reached := amount >= level.MinAmount || (level.MinGrade != nil && grade >= *level.MinGrade)
+		if reached && level.Level > required {
+			required = level.Level
+		}
+	}
+
+	log.WithFields(log.Fields{
+		"amount":        amount,
+		"grade":         grade,
+		"requiredLevel": required,
+	}).Info("Required approval level resolved")
+	return required, nil
+}
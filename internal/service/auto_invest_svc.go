@@ -0,0 +1,441 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	log "github.com/sirupsen/logrus"
+	"github.com/test/loan-service/internal/dto"
+	"github.com/test/loan-service/internal/enum"
+	"github.com/test/loan-service/internal/money"
+	repo "github.com/test/loan-service/internal/repository"
+	"github.com/test/loan-service/internal/service/validator"
+	"go.uber.org/dig"
+	"strings"
+	"time"
+)
+
+//go:generate mockgen -source=$GOFILE -destination=$PROJ/internal/generated/mock/mock_$GOPACKAGE/$GOFILE
+
+type (
+	// AutoInvestSvc invests for lenders by their saved rules. When a loan is approved every active rule
+	// is evaluated once, a matching rule orders a funding through LoanFundingSvc.Create like a lender
+	// would. Rules take turns by the date of their last order and a lender orders at most once per loan,
+	// so the loan is spread over lenders instead of going to whoever saved a rule first. Every run keeps
+	// the outcome of each rule for audit.
+	AutoInvestSvc interface {
+		Create(ctx context.Context, request *dto.AutoInvestRuleRequestDTO) (*dto.AutoInvestRuleResponseDTO, error)
+		Update(ctx context.Context, id int64, request *dto.AutoInvestRuleRequestDTO) (*dto.AutoInvestRuleResponseDTO, error)
+		Delete(ctx context.Context, id int64) error
+		GetByLenderID(ctx context.Context, lenderID int64) ([]dto.AutoInvestRuleResponseDTO, error)
+		GetRunsByLoanID(ctx context.Context, loanID int64) ([]dto.AutoInvestRunResponseDTO, error)
+		Run(ctx context.Context, loan *repo.Loan) error
+	}
+
+	AutoInvestSvcImpl struct {
+		dig.In
+		RuleRepo       repo.AutoInvestRuleRepo
+		RunRepo        repo.AutoInvestRunRepo
+		RunItemRepo    repo.AutoInvestRunItemRepo
+		LoanDetailRepo repo.LoanDetailRepo
+		LoanFundingSvc LoanFundingSvc
+		Validator      validator.AutoInvestRuleValidatorImpl
+	}
+)
+
+func NewAutoInvestSvc(impl AutoInvestSvcImpl) AutoInvestSvc {
+	return &impl
+}
+
+func (s *AutoInvestSvcImpl) Create(ctx context.Context, request *dto.AutoInvestRuleRequestDTO) (*dto.AutoInvestRuleResponseDTO, error) {
+	log.WithFields(log.Fields{
+		"lenderID":      request.LenderID,
+		"amountPerLoan": request.AmountPerLoan,
+		"monthlyBudget": request.MonthlyBudget,
+	}).Info("Creating auto-invest rule")
+
+	err := s.Validator.ValidateCreate(request)
+	if err != nil {
+		log.WithField("lenderID", request.LenderID).Errorf("Validation failed: %s", err)
+		return nil, err
+	}
+
+	rule := repo.AutoInvestRule{
+		LenderID: request.LenderID,
+		IsActive: true,
+	}
+	s.applyRequest(&rule, request)
+
+	rule.ID, err = s.RuleRepo.Create(ctx, &rule)
+	if err != nil {
+		log.WithField("lenderID", request.LenderID).WithError(err).Error("Failed to create auto-invest rule")
+		return nil, errors.New("99999")
+	}
+
+	log.WithFields(log.Fields{
+		"lenderID": request.LenderID,
+		"ruleID":   rule.ID,
+	}).Info("Auto-invest rule created successfully")
+	return s.getByID(ctx, rule.ID)
+}
+
+func (s *AutoInvestSvcImpl) Update(ctx context.Context, id int64, request *dto.AutoInvestRuleRequestDTO) (*dto.AutoInvestRuleResponseDTO, error) {
+	log.WithFields(log.Fields{
+		"ruleID":        id,
+		"amountPerLoan": request.AmountPerLoan,
+		"monthlyBudget": request.MonthlyBudget,
+	}).Info("Updating auto-invest rule")
+
+	err := s.Validator.ValidateUpdate(request)
+	if err != nil {
+		log.WithField("ruleID", id).Errorf("Validation failed: %s", err)
+		return nil, err
+	}
+
+	rule, err := s.RuleRepo.GetByID(ctx, id)
+	if err != nil {
+		log.WithField("ruleID", id).WithError(err).Error("Failed to get auto-invest rule")
+		return nil, errors.New("99999")
+	}
+	if rule == nil {
+		log.WithField("ruleID", id).Warn("Auto-invest rule not found")
+		return nil, errors.New("10001")
+	}
+
+	s.applyRequest(rule, request)
+	err = s.RuleRepo.Update(ctx, rule)
+	if err != nil {
+		log.WithField("ruleID", id).WithError(err).Error("Failed to update auto-invest rule")
+		return nil, errors.New("99999")
+	}
+
+	log.WithField("ruleID", id).Info("Auto-invest rule updated successfully")
+	return s.getByID(ctx, id)
+}
+
+// Delete removes a rule, the runs it took part in are kept
+func (s *AutoInvestSvcImpl) Delete(ctx context.Context, id int64) error {
+	rule, err := s.RuleRepo.GetByID(ctx, id)
+	if err != nil {
+		log.WithField("ruleID", id).WithError(err).Error("Failed to get auto-invest rule")
+		return errors.New("99999")
+	}
+	if rule == nil {
+		log.WithField("ruleID", id).Warn("Auto-invest rule not found")
+		return errors.New("10001")
+	}
+
+	err = s.RuleRepo.Delete(ctx, id)
+	if err != nil {
+		log.WithField("ruleID", id).WithError(err).Error("Failed to delete auto-invest rule")
+		return errors.New("99999")
+	}
+
+	log.WithField("ruleID", id).Info("Auto-invest rule deleted successfully")
+	return nil
+}
+
+func (s *AutoInvestSvcImpl) GetByLenderID(ctx context.Context, lenderID int64) ([]dto.AutoInvestRuleResponseDTO, error) {
+	rules, err := s.RuleRepo.GetByLenderID(ctx, lenderID)
+	if err != nil {
+		log.WithField("lenderID", lenderID).WithError(err).Error("Failed to get auto-invest rules")
+		return nil, errors.New("99999")
+	}
+
+	ruleDTOs := []dto.AutoInvestRuleResponseDTO{}
+	for _, rule := range rules {
+		ruleRes, err := s.toResponseDTO(ctx, rule)
+		if err != nil {
+			return nil, err
+		}
+		ruleDTOs = append(ruleDTOs, *ruleRes)
+	}
+
+	return ruleDTOs, nil
+}
+
+// GetRunsByLoanID returns the runs of a loan with the outcome of every rule evaluated
+func (s *AutoInvestSvcImpl) GetRunsByLoanID(ctx context.Context, loanID int64) ([]dto.AutoInvestRunResponseDTO, error) {
+	runs, err := s.RunRepo.GetByLoanID(ctx, loanID)
+	if err != nil {
+		log.WithField("loanID", loanID).WithError(err).Error("Failed to get auto-invest runs")
+		return nil, errors.New("99999")
+	}
+
+	runDTOs := []dto.AutoInvestRunResponseDTO{}
+	for _, run := range runs {
+		items, err := s.RunItemRepo.GetByRunID(ctx, run.ID)
+		if err != nil {
+			log.WithField("runID", run.ID).WithError(err).Error("Failed to get auto-invest run items")
+			return nil, errors.New("99999")
+		}
+
+		runRes := dto.AutoInvestRunResponseDTO{
+			ID:             run.ID,
+			LoanID:         run.LoanID,
+			RulesEvaluated: run.RulesEvaluated,
+			OrdersCreated:  run.OrdersCreated,
+			TotalOrdered:   run.TotalOrdered,
+			StartedAt:      run.StartedAt,
+			FinishedAt:     run.FinishedAt,
+			Items:          []dto.AutoInvestRunItemResponseDTO{},
+		}
+		for _, item := range items {
+			runRes.Items = append(runRes.Items, dto.AutoInvestRunItemResponseDTO{
+				RuleID:      item.RuleID,
+				LenderID:    item.LenderID,
+				Outcome:     item.Outcome,
+				Reason:      item.Reason,
+				Amount:      item.Amount,
+				OrderNumber: item.OrderNumber,
+				CreatedAt:   item.CreatedAt,
+			})
+		}
+		runDTOs = append(runDTOs, runRes)
+	}
+
+	return runDTOs, nil
+}
+
+// Run evaluates the active rules for an approved loan and orders a funding for every matching rule
+// until the loan is fully ordered. A rule orders its amount per loan, or what is left of the loan
+// when that is less, as long as its monthly budget allows. An order rejected by LoanFundingSvc, for
+// instance on the wallet balance or the investment limits, is recorded as failed and the next rule
+// gets its turn.
+func (s *AutoInvestSvcImpl) Run(ctx context.Context, loan *repo.Loan) error {
+	rules, err := s.RuleRepo.GetActive(ctx)
+	if err != nil {
+		log.WithField("loanID", loan.ID).WithError(err).Error("Failed to get active auto-invest rules")
+		return errors.New("99999")
+	}
+
+	detail, err := s.LoanDetailRepo.GetByLoanID(ctx, loan.ID)
+	if err != nil {
+		log.WithField("loanID", loan.ID).WithError(err).Error("Failed to get loan detail")
+		return errors.New("99999")
+	}
+	sector := ""
+	if detail != nil {
+		sector = detail.BusinessSector
+	}
+
+	now := time.Now()
+	run := repo.AutoInvestRun{
+		LoanID:    loan.ID,
+		StartedAt: now,
+	}
+	run.ID, err = s.RunRepo.Create(ctx, &run)
+	if err != nil {
+		log.WithField("loanID", loan.ID).WithError(err).Error("Failed to create auto-invest run")
+		return errors.New("99999")
+	}
+
+	log.WithFields(log.Fields{
+		"loanID": loan.ID,
+		"runID":  run.ID,
+		"rules":  len(rules),
+	}).Info("Running auto-invest rules")
+
+	monthStart := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, now.Location())
+	remaining := loan.RequestAmount - loan.TotalInvestedAmount
+	orderedLenders := make(map[int64]bool)
+	for _, rule := range rules {
+		run.RulesEvaluated++
+		item := repo.AutoInvestRunItem{
+			RunID:    run.ID,
+			RuleID:   rule.ID,
+			LenderID: rule.LenderID,
+			Outcome:  enum.AutoInvestSkipped,
+		}
+
+		if reason := s.mismatch(rule, loan, sector); reason != "" {
+			item.Reason = reason
+		} else if orderedLenders[rule.LenderID] {
+			item.Reason = "lender already ordered in this loan"
+		} else if !remaining.IsPositive() {
+			item.Reason = "loan fully ordered"
+		} else {
+			item.Amount = money.Min(rule.AmountPerLoan, remaining)
+			s.order(ctx, loan, rule, monthStart, &item)
+		}
+
+		if item.Outcome == enum.AutoInvestOrdered {
+			orderedLenders[rule.LenderID] = true
+			remaining -= item.Amount
+			run.OrdersCreated++
+			run.TotalOrdered += item.Amount
+
+			rule.LastInvestedAt = &now
+			err = s.RuleRepo.Update(ctx, &rule)
+			if err != nil {
+				log.WithField("ruleID", rule.ID).WithError(err).Error("Failed to update auto-invest rule")
+			}
+		}
+
+		_, err = s.RunItemRepo.Create(ctx, &item)
+		if err != nil {
+			log.WithFields(log.Fields{
+				"runID":  run.ID,
+				"ruleID": rule.ID,
+			}).WithError(err).Error("Failed to create auto-invest run item")
+		}
+	}
+
+	finishedAt := time.Now()
+	run.FinishedAt = &finishedAt
+	err = s.RunRepo.Update(ctx, &run)
+	if err != nil {
+		log.WithField("runID", run.ID).WithError(err).Error("Failed to finish auto-invest run")
+		return errors.New("99999")
+	}
+
+	log.WithFields(log.Fields{
+		"loanID":         loan.ID,
+		"runID":          run.ID,
+		"rulesEvaluated": run.RulesEvaluated,
+		"ordersCreated":  run.OrdersCreated,
+		"totalOrdered":   run.TotalOrdered,
+	}).Info("Auto-invest run finished")
+	return nil
+}
+
+// order checks the monthly budget of the rule and orders the funding, the outcome is set on the item
+func (s *AutoInvestSvcImpl) order(ctx context.Context, loan *repo.Loan, rule repo.AutoInvestRule, monthStart time.Time, item *repo.AutoInvestRunItem) {
+	ordered, err := s.RunItemRepo.GetOrderedAmountByRuleID(ctx, rule.ID, monthStart)
+	if err != nil {
+		log.WithField("ruleID", rule.ID).WithError(err).Error("Failed to get ordered amount of auto-invest rule")
+		item.Outcome = enum.AutoInvestFailed
+		item.Reason = "monthly budget could not be checked"
+		return
+	}
+	if ordered+item.Amount > rule.MonthlyBudget {
+		item.Reason = "monthly budget exhausted"
+		return
+	}
+
+	orderNumber := fmt.Sprintf("AI-%d-%d", item.RunID, rule.ID)
+	err = s.LoanFundingSvc.Create(ctx, &dto.LoanFundingRequestDTO{
+		OrderNumber:      orderNumber,
+		LoanID:           loan.ID,
+		LenderID:         rule.LenderID,
+		InvestmentAmount: item.Amount,
+	})
+	if err != nil {
+		log.WithFields(log.Fields{
+			"loanID": loan.ID,
+			"ruleID": rule.ID,
+		}).WithError(err).Warn("Auto-invest funding order rejected")
+		item.Outcome = enum.AutoInvestFailed
+		item.Reason = fmt.Sprintf("funding rejected with error %s", err.Error())
+		return
+	}
+
+	item.Outcome = enum.AutoInvestOrdered
+	item.OrderNumber = &orderNumber
+}
+
+// mismatch returns why the loan does not match the criteria of the rule, empty when it matches
+func (s *AutoInvestSvcImpl) mismatch(rule repo.AutoInvestRule, loan *repo.Loan, sector string) string {
+	if len(rule.LoanGrades) > 0 && !containsFold(rule.LoanGrades, string(loan.LoanGrade)) {
+		return "loan grade not allowed"
+	}
+	if len(rule.LoanTypes) > 0 && !containsFold(rule.LoanTypes, string(loan.LoanType)) {
+		return "loan type not allowed"
+	}
+	if len(rule.BusinessSectors) > 0 && !containsFold(rule.BusinessSectors, sector) {
+		return "business sector not allowed"
+	}
+	if rule.MinTenure > 0 && loan.Tenures < rule.MinTenure {
+		return "tenure below minimum"
+	}
+	if rule.MaxTenure > 0 && loan.Tenures > rule.MaxTenure {
+		return "tenure above maximum"
+	}
+	if loan.InvestmentPercentage < rule.MinRate {
+		return "lender yield below minimum rate"
+	}
+	return ""
+}
+
+func (s *AutoInvestSvcImpl) applyRequest(rule *repo.AutoInvestRule, request *dto.AutoInvestRuleRequestDTO) {
+	rule.LoanGrades = nil
+	for _, grade := range request.LoanGrades {
+		rule.LoanGrades = append(rule.LoanGrades, string(grade))
+	}
+	rule.LoanTypes = nil
+	for _, loanType := range request.LoanTypes {
+		rule.LoanTypes = append(rule.LoanTypes, string(loanType))
+	}
+	rule.BusinessSectors = nil
+	for _, sector := range request.BusinessSectors {
+		rule.BusinessSectors = append(rule.BusinessSectors, strings.TrimSpace(sector))
+	}
+	rule.MinTenure = request.MinTenure
+	rule.MaxTenure = request.MaxTenure
+	rule.MinRate = request.MinRate
+	rule.AmountPerLoan = request.AmountPerLoan
+	rule.MonthlyBudget = request.MonthlyBudget
+	if request.IsActive != nil {
+		rule.IsActive = *request.IsActive
+	}
+}
+
+func (s *AutoInvestSvcImpl) getByID(ctx context.Context, id int64) (*dto.AutoInvestRuleResponseDTO, error) {
+	rule, err := s.RuleRepo.GetByID(ctx, id)
+	if err != nil {
+		log.WithField("ruleID", id).WithError(err).Error("Failed to get auto-invest rule")
+		return nil, errors.New("99999")
+	}
+	if rule == nil {
+		log.WithField("ruleID", id).Warn("Auto-invest rule not found")
+		return nil, errors.New("10001")
+	}
+
+	return s.toResponseDTO(ctx, *rule)
+}
+
+func (s *AutoInvestSvcImpl) toResponseDTO(ctx context.Context, rule repo.AutoInvestRule) (*dto.AutoInvestRuleResponseDTO, error) {
+	now := time.Now()
+	ordered, err := s.RunItemRepo.GetOrderedAmountByRuleID(ctx, rule.ID, time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, now.Location()))
+	if err != nil {
+		log.WithField("ruleID", rule.ID).WithError(err).Error("Failed to get ordered amount of auto-invest rule")
+		return nil, errors.New("99999")
+	}
+
+	ruleRes := dto.AutoInvestRuleResponseDTO{
+		ID:               rule.ID,
+		LenderID:         rule.LenderID,
+		LoanGrades:       []enum.LoanGrade{},
+		LoanTypes:        []enum.LoanType{},
+		BusinessSectors:  []string{},
+		MinTenure:        rule.MinTenure,
+		MaxTenure:        rule.MaxTenure,
+		MinRate:          rule.MinRate,
+		AmountPerLoan:    rule.AmountPerLoan,
+		MonthlyBudget:    rule.MonthlyBudget,
+		OrderedThisMonth: ordered,
+		IsActive:         rule.IsActive,
+		LastInvestedAt:   rule.LastInvestedAt,
+		CreatedAt:        rule.CreatedAt,
+		UpdatedAt:        rule.UpdatedAt,
+	}
+	for _, grade := range rule.LoanGrades {
+		ruleRes.LoanGrades = append(ruleRes.LoanGrades, enum.LoanGrade(grade))
+	}
+	for _, loanType := range rule.LoanTypes {
+		ruleRes.LoanTypes = append(ruleRes.LoanTypes, enum.LoanType(loanType))
+	}
+	ruleRes.BusinessSectors = append(ruleRes.BusinessSectors, rule.BusinessSectors...)
+
+	return &ruleRes, nil
+}
+
+// containsFold reports whether the value is in the list, ignoring case
+func containsFold(list []string, value string) bool {
+	for _, item := range list {
+		if strings.EqualFold(item, value) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,358 @@
+package service
+
+import (
+	"context"
+	"errors"
+	log "github.com/sirupsen/logrus"
+	"github.com/test/loan-service/internal/dto"
+	"github.com/test/loan-service/internal/enum"
+	repo "github.com/test/loan-service/internal/repository"
+	"github.com/test/loan-service/internal/service/validator"
+	"github.com/typical-go/typical-rest-server/pkg/dbtxn"
+	"go.uber.org/dig"
+	"time"
+)
+
+//go:generate mockgen -source=$GOFILE -destination=$PROJ/internal/generated/mock/mock_$GOPACKAGE/$GOFILE
+
+type (
+	// BorrowerSvc keeps the identity and business profile of borrowers and their KYC. A borrower can
+	// only apply for a loan once the KYC is verified, every change of the KYC status is kept in the
+	// KYC history.
+	BorrowerSvc interface {
+		Create(ctx context.Context, request *dto.BorrowerRequestDTO) (*dto.BorrowerResponseDTO, error)
+		Update(ctx context.Context, request *dto.BorrowerRequestDTO) (*dto.BorrowerResponseDTO, error)
+		GetByID(ctx context.Context, id int64) (*dto.BorrowerResponseDTO, error)
+		GetVerified(ctx context.Context, id int64) (*repo.Borrower, error)
+		SubmitKyc(ctx context.Context, id int64) (*dto.BorrowerResponseDTO, error)
+		ReviewKyc(ctx context.Context, id int64, request *dto.KycReviewRequestDTO) (*dto.BorrowerResponseDTO, error)
+		GetKycHistory(ctx context.Context, id int64) ([]dto.KycHistoryResponseDTO, error)
+	}
+
+	BorrowerSvcImpl struct {
+		dig.In
+		Repo      repo.BorrowerRepo
+		KycSvc    KycSvc
+		Validator validator.BorrowerValidatorImpl
+	}
+)
+
+func NewBorrowerSvc(impl BorrowerSvcImpl) BorrowerSvc {
+	return &impl
+}
+
+func (s *BorrowerSvcImpl) Create(ctx context.Context, request *dto.BorrowerRequestDTO) (*dto.BorrowerResponseDTO, error) {
+	log.WithField("nationalID", request.NationalID).Info("Creating borrower")
+
+	err := s.Validator.ValidateCreate(request)
+	if err != nil {
+		log.WithField("nationalID", request.NationalID).Errorf("Validation failed: %s", err)
+		return nil, err
+	}
+
+	err = s.checkDuplicate(ctx, 0, request.NationalID)
+	if err != nil {
+		return nil, err
+	}
+
+	var borrower repo.Borrower
+	applyBorrowerRequest(&borrower, request)
+	borrower.KycStatus = enum.KycUnverified
+	borrower.CreatedAt = time.Now()
+	borrower.UpdatedAt = borrower.CreatedAt
+
+	borrower.ID, err = s.Repo.Create(ctx, &borrower)
+	if err != nil {
+		log.WithField("nationalID", request.NationalID).WithError(err).Error("Failed to create borrower")
+		return nil, errors.New("99999")
+	}
+
+	log.WithField("borrowerID", borrower.ID).Info("Borrower created successfully")
+	return s.toResponseDTO(borrower), nil
+}
+
+// Update replaces the profile of a borrower. A change of the name, national ID or date of birth puts a
+// submitted or verified KYC back to unverified, the borrower has to submit it again.
+func (s *BorrowerSvcImpl) Update(ctx context.Context, request *dto.BorrowerRequestDTO) (*dto.BorrowerResponseDTO, error) {
+	log.WithField("borrowerID", request.BorrowerID).Info("Updating borrower")
+
+	err := s.Validator.ValidateUpdate(request)
+	if err != nil {
+		log.WithField("borrowerID", request.BorrowerID).Errorf("Validation failed: %s", err)
+		return nil, err
+	}
+
+	err = s.checkDuplicate(ctx, request.BorrowerID, request.NationalID)
+	if err != nil {
+		return nil, err
+	}
+
+	txnCtx := dbtxn.Begin(&ctx)
+	defer func() {
+		if err := txnCtx.Commit(); err != nil {
+			log.WithError(err).Error("Transaction commit failed")
+			txnCtx.AppendError(err)
+		}
+	}()
+
+	borrower, err := s.getForUpdate(ctx, request.BorrowerID)
+	if err != nil {
+		txnCtx.AppendError(err)
+		return nil, err
+	}
+
+	identityChanged := borrower.FullName != request.FullName ||
+		borrower.NationalID != request.NationalID ||
+		!borrower.DateOfBirth.Equal(request.DateOfBirth)
+
+	applyBorrowerRequest(borrower, request)
+	if identityChanged {
+		err = s.KycSvc.ResetIdentity(ctx, enum.DocumentEntityBorrower, borrower.ID, &borrower.Kyc)
+		if err != nil {
+			txnCtx.AppendError(err)
+			return nil, err
+		}
+	}
+
+	borrower.UpdatedAt = time.Now()
+	err = s.Repo.Update(ctx, borrower)
+	if err != nil {
+		log.WithField("borrowerID", borrower.ID).WithError(err).Error("Failed to update borrower")
+		txnCtx.AppendError(err)
+		return nil, errors.New("99999")
+	}
+
+	log.WithFields(log.Fields{
+		"borrowerID": borrower.ID,
+		"kycStatus":  borrower.KycStatus,
+	}).Info("Borrower updated successfully")
+	return s.toResponseDTO(*borrower), nil
+}
+
+func (s *BorrowerSvcImpl) GetByID(ctx context.Context, id int64) (*dto.BorrowerResponseDTO, error) {
+	borrower, err := s.Repo.GetByID(ctx, id)
+	if err != nil {
+		log.WithField("borrowerID", id).WithError(err).Error("Failed to get borrower")
+		return nil, errors.New("99999")
+	}
+	if borrower == nil {
+		log.WithField("borrowerID", id).Warn("Borrower not found")
+		return nil, errors.New("10001")
+	}
+
+	return s.toResponseDTO(*borrower), nil
+}
+
+// GetVerified returns the borrower when the KYC is verified, 10001 when the borrower does not exist and
+// 10021 while the KYC is not verified
+func (s *BorrowerSvcImpl) GetVerified(ctx context.Context, id int64) (*repo.Borrower, error) {
+	borrower, err := s.Repo.GetByID(ctx, id)
+	if err != nil {
+		log.WithField("borrowerID", id).WithError(err).Error("Failed to get borrower")
+		return nil, errors.New("99999")
+	}
+	if borrower == nil {
+		log.WithField("borrowerID", id).Warn("Borrower not found")
+		return nil, errors.New("10001")
+	}
+	if borrower.KycStatus != enum.KycVerified {
+		log.WithFields(log.Fields{
+			"borrowerID": id,
+			"kycStatus":  borrower.KycStatus,
+		}).Warn("Borrower is not verified")
+		return nil, errors.New("10021")
+	}
+
+	return borrower, nil
+}
+
+// SubmitKyc sends the KYC of a borrower for review, the verification documents have to be uploaded
+// for the borrower first and the error names every missing one
+func (s *BorrowerSvcImpl) SubmitKyc(ctx context.Context, id int64) (*dto.BorrowerResponseDTO, error) {
+	log.WithField("borrowerID", id).Info("Submitting borrower KYC")
+
+	txnCtx := dbtxn.Begin(&ctx)
+	defer func() {
+		if err := txnCtx.Commit(); err != nil {
+			log.WithError(err).Error("Transaction commit failed")
+			txnCtx.AppendError(err)
+		}
+	}()
+
+	borrower, err := s.getForUpdate(ctx, id)
+	if err != nil {
+		txnCtx.AppendError(err)
+		return nil, err
+	}
+
+	err = s.KycSvc.Submit(ctx, enum.DocumentEntityBorrower, borrower.ID, &borrower.Kyc, s.kycDocuments(borrower))
+	if err != nil {
+		txnCtx.AppendError(err)
+		return nil, err
+	}
+
+	err = s.Repo.Update(ctx, borrower)
+	if err != nil {
+		log.WithField("borrowerID", id).WithError(err).Error("Failed to update borrower KYC status")
+		txnCtx.AppendError(err)
+		return nil, errors.New("99999")
+	}
+
+	log.WithField("borrowerID", id).Info("Borrower KYC submitted successfully")
+	return s.toResponseDTO(*borrower), nil
+}
+
+// ReviewKyc records the decision of the staff on a submitted KYC
+func (s *BorrowerSvcImpl) ReviewKyc(ctx context.Context, id int64, request *dto.KycReviewRequestDTO) (*dto.BorrowerResponseDTO, error) {
+	log.WithFields(log.Fields{
+		"borrowerID": id,
+		"staffID":    request.StaffID,
+		"kycStatus":  request.KycStatus,
+	}).Info("Reviewing borrower KYC")
+
+	txnCtx := dbtxn.Begin(&ctx)
+	defer func() {
+		if err := txnCtx.Commit(); err != nil {
+			log.WithError(err).Error("Transaction commit failed")
+			txnCtx.AppendError(err)
+		}
+	}()
+
+	borrower, err := s.getForUpdate(ctx, id)
+	if err != nil {
+		txnCtx.AppendError(err)
+		return nil, err
+	}
+
+	err = s.KycSvc.Review(ctx, enum.DocumentEntityBorrower, borrower.ID, &borrower.Kyc, request)
+	if err != nil {
+		txnCtx.AppendError(err)
+		return nil, err
+	}
+
+	err = s.Repo.Update(ctx, borrower)
+	if err != nil {
+		log.WithField("borrowerID", id).WithError(err).Error("Failed to update borrower KYC status")
+		txnCtx.AppendError(err)
+		return nil, errors.New("99999")
+	}
+
+	log.WithFields(log.Fields{
+		"borrowerID": id,
+		"kycStatus":  borrower.KycStatus,
+	}).Info("Borrower KYC reviewed successfully")
+	return s.toResponseDTO(*borrower), nil
+}
+
+func (s *BorrowerSvcImpl) GetKycHistory(ctx context.Context, id int64) ([]dto.KycHistoryResponseDTO, error) {
+	borrower, err := s.Repo.GetByID(ctx, id)
+	if err != nil {
+		log.WithField("borrowerID", id).WithError(err).Error("Failed to get borrower")
+		return nil, errors.New("99999")
+	}
+	if borrower == nil {
+		log.WithField("borrowerID", id).Warn("Borrower not found")
+		return nil, errors.New("10001")
+	}
+
+	return s.KycSvc.GetHistory(ctx, enum.DocumentEntityBorrower, id)
+}
+
+// getForUpdate locks the borrower until the transaction of ctx ends, 10001 when it does not exist
+func (s *BorrowerSvcImpl) getForUpdate(ctx context.Context, id int64) (*repo.Borrower, error) {
+	borrower, err := s.Repo.GetByIDForUpdate(ctx, id)
+	if err != nil {
+		log.WithField("borrowerID", id).WithError(err).Error("Failed to get borrower")
+		return nil, errors.New("99999")
+	}
+	if borrower == nil {
+		log.WithField("borrowerID", id).Warn("Borrower not found")
+		return nil, errors.New("10001")
+	}
+
+	return borrower, nil
+}
+
+// kycDocuments are the documents a borrower has to upload before the KYC is submitted, the business
+// registration only when the borrower registers a business
+func (s *BorrowerSvcImpl) kycDocuments(borrower *repo.Borrower) []string {
+	documents := []string{kycNationalIDDocument}
+	if borrower.BusinessRegistrationNumber != nil {
+		documents = append(documents, kycBusinessRegistrationDocument)
+	}
+
+	return documents
+}
+
+// checkDuplicate refuses a national ID registered for another borrower, id is the borrower being
+// updated, zero on create
+func (s *BorrowerSvcImpl) checkDuplicate(ctx context.Context, id int64, nationalID string) error {
+	existing, err := s.Repo.GetByNationalID(ctx, nationalID)
+	if err != nil {
+		log.WithError(err).Error("Failed to get borrower by national ID")
+		return errors.New("99999")
+	}
+	if existing != nil && existing.ID != id {
+		log.WithField("borrowerID", existing.ID).Warn("National ID already registered")
+		return errors.New("10003")
+	}
+
+	return nil
+}
+
+func (s *BorrowerSvcImpl) toResponseDTO(borrower repo.Borrower) *dto.BorrowerResponseDTO {
+	return &dto.BorrowerResponseDTO{
+		ID:                         borrower.ID,
+		FullName:                   borrower.FullName,
+		NationalID:                 borrower.NationalID,
+		DateOfBirth:                borrower.DateOfBirth,
+		PhoneNumber:                borrower.PhoneNumber,
+		Email:                      borrower.Email,
+		Address:                    borrower.Address,
+		BusinessName:               borrower.BusinessName,
+		BusinessType:               borrower.BusinessType,
+		BusinessAddress:            borrower.BusinessAddress,
+		BusinessPhoneNumber:        borrower.BusinessPhoneNumber,
+		BusinessEmail:              borrower.BusinessEmail,
+		BusinessRegistrationNumber: borrower.BusinessRegistrationNumber,
+		BusinessOwnerName:          borrower.BusinessOwnerName,
+		BusinessDescription:        borrower.BusinessDescription,
+		BusinessSector:             borrower.BusinessSector,
+		BusinessEstablishedYear:    borrower.BusinessEstablishedYear,
+		KycStatus:                  borrower.KycStatus,
+		KycVerifiedAt:              borrower.KycVerifiedAt,
+		CreatedAt:                  borrower.CreatedAt,
+		UpdatedAt:                  borrower.UpdatedAt,
+	}
+}
+
+// applyBorrowerRequest copies the profile of the request to the borrower, business fields left empty
+// are stored as NULL
+func applyBorrowerRequest(borrower *repo.Borrower, request *dto.BorrowerRequestDTO) {
+	borrower.FullName = request.FullName
+	borrower.NationalID = request.NationalID
+	borrower.DateOfBirth = request.DateOfBirth
+	borrower.PhoneNumber = request.PhoneNumber
+	borrower.Email = request.Email
+	borrower.Address = request.Address
+	borrower.BusinessName = nullableString(request.BusinessName)
+	borrower.BusinessType = nullableString(request.BusinessType)
+	borrower.BusinessAddress = nullableString(request.BusinessAddress)
+	borrower.BusinessPhoneNumber = nullableString(request.BusinessPhoneNumber)
+	borrower.BusinessEmail = nullableString(request.BusinessEmail)
+	borrower.BusinessRegistrationNumber = nullableString(request.BusinessRegistrationNumber)
+	borrower.BusinessOwnerName = nullableString(request.BusinessOwnerName)
+	borrower.BusinessDescription = nullableString(request.BusinessDescription)
+	borrower.BusinessSector = nullableString(request.BusinessSector)
+	borrower.BusinessEstablishedYear = nil
+	if request.BusinessEstablishedYear > 0 {
+		borrower.BusinessEstablishedYear = &request.BusinessEstablishedYear
+	}
+}
+
+func nullableString(value string) *string {
+	if value == "" {
+		return nil
+	}
+	return &value
+}
@@ -0,0 +1,465 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"github.com/mitchellh/mapstructure"
+	log "github.com/sirupsen/logrus"
+	"github.com/test/loan-service/internal/dto"
+	"github.com/test/loan-service/internal/enum"
+	"github.com/test/loan-service/internal/money"
+	repo "github.com/test/loan-service/internal/repository"
+	"github.com/test/loan-service/internal/service/models"
+	"github.com/test/loan-service/internal/service/validator"
+	"go.uber.org/dig"
+	"strconv"
+	"strings"
+	"time"
+)
+
+//go:generate mockgen -source=$GOFILE -destination=$PROJ/internal/generated/mock/mock_$GOPACKAGE/$GOFILE
+
+type (
+	// CreditScoringSvc grades a loan request. Every factor is computed from the loan detail and scored
+	// by the first configured rule that matches its value, the sum of the points is mapped to a grade
+	// by the configured minimum scores.
+	CreditScoringSvc interface {
+		Evaluate(ctx context.Context, request models.CreditScoringRequest) (*models.CreditScoreResult, error)
+		Save(ctx context.Context, loanID int64, result *models.CreditScoreResult) error
+		GetByLoanID(ctx context.Context, loanID int64) (*dto.CreditScoreResponseDTO, error)
+		GetRules(ctx context.Context) ([]dto.CreditScoringRuleResponseDTO, error)
+		CreateRule(ctx context.Context, request *dto.CreditScoringRuleRequestDTO) (*dto.CreditScoringRuleResponseDTO, error)
+		UpdateRule(ctx context.Context, id int64, request *dto.CreditScoringRuleRequestDTO) (*dto.CreditScoringRuleResponseDTO, error)
+		DeleteRule(ctx context.Context, id int64) error
+		GetGrades(ctx context.Context) ([]dto.CreditGradeResponseDTO, error)
+		UpdateGrade(ctx context.Context, grade enum.LoanGrade, request *dto.CreditGradeRequestDTO) error
+	}
+
+	CreditScoringSvcImpl struct {
+		dig.In
+		RuleRepo   repo.CreditScoringRuleRepo
+		GradeRepo  repo.CreditGradeRepo
+		ScoreRepo  repo.CreditScoreRepo
+		FactorRepo repo.CreditScoreFactorRepo
+		Validator  validator.CreditScoringValidatorImpl
+	}
+)
+
+// creditScoringFactors is the order in which factors are scored and stored
+var creditScoringFactors = []enum.CreditScoringFactor{
+	enum.CreditFactorProfitMargin,
+	enum.CreditFactorLoanToRevenue,
+	enum.CreditFactorAnnualRevenue,
+	enum.CreditFactorBusinessAge,
+	enum.CreditFactorBusinessSector,
+	enum.CreditFactorTenure,
+}
+
+func NewCreditScoringSvc(impl CreditScoringSvcImpl) CreditScoringSvc {
+	return &impl
+}
+
+// Evaluate scores a loan request against the current rules without storing anything
+func (s *CreditScoringSvcImpl) Evaluate(ctx context.Context, request models.CreditScoringRequest) (*models.CreditScoreResult, error) {
+	detail := request.Detail
+	if detail == nil || !detail.BusinessAnnualRevenue.IsPositive() {
+		log.Error("Credit scoring needs a positive business annual revenue")
+		return nil, errors.New("10003")
+	}
+
+	rules, err := s.RuleRepo.GetAll(ctx)
+	if err != nil {
+		log.WithError(err).Error("Failed to get credit scoring rules")
+		return nil, errors.New("99999")
+	}
+
+	grades, err := s.GradeRepo.GetAll(ctx)
+	if err != nil {
+		log.WithError(err).Error("Failed to get credit grades")
+		return nil, errors.New("99999")
+	}
+	if len(grades) == 0 {
+		log.Error("No credit grade configured")
+		return nil, errors.New("99999")
+	}
+
+	revenue := float64(detail.BusinessAnnualRevenue) / float64(money.Unit)
+	values := map[enum.CreditScoringFactor]float64{
+		enum.CreditFactorProfitMargin:  float64(detail.BusinessAnnualRevenue-detail.BusinessExpense) * 100 / float64(detail.BusinessAnnualRevenue),
+		enum.CreditFactorLoanToRevenue: float64(request.RequestAmount) * 100 / float64(detail.BusinessAnnualRevenue),
+		enum.CreditFactorAnnualRevenue: revenue,
+		enum.CreditFactorBusinessAge:   float64(detail.BusinessAge),
+		enum.CreditFactorTenure:        float64(request.Tenures),
+	}
+	sector := strings.TrimSpace(detail.BusinessSector)
+
+	result := models.CreditScoreResult{}
+	for _, factor := range creditScoringFactors {
+		factorResult := models.CreditScoreFactorResult{Factor: factor}
+
+		var rule *repo.CreditScoringRule
+		if factor.IsNumeric() {
+			factorResult.Value = strconv.FormatFloat(values[factor], 'f', 2, 64)
+			rule = matchRangeRule(rules, factor, values[factor])
+		} else {
+			factorResult.Value = sector
+			rule = matchNameRule(rules, factor, sector)
+		}
+
+		if rule != nil {
+			factorResult.RuleID = &rule.ID
+			factorResult.RuleDescription = rule.Description
+			factorResult.Points = rule.Points
+		} else {
+			log.WithFields(log.Fields{
+				"factor": factor,
+				"value":  factorResult.Value,
+			}).Warn("No credit scoring rule matched, factor scores zero")
+		}
+
+		result.Score += factorResult.Points
+		result.Factors = append(result.Factors, factorResult)
+	}
+
+	// grades are ordered from the highest minimum score, a score below every minimum gets the lowest grade
+	result.Grade = grades[len(grades)-1].Grade
+	for _, grade := range grades {
+		if result.Score >= grade.MinScore {
+			result.Grade = grade.Grade
+			break
+		}
+	}
+
+	log.WithFields(log.Fields{
+		"score": result.Score,
+		"grade": result.Grade,
+	}).Info("Loan request scored")
+	return &result, nil
+}
+
+// Save stores the score of a loan with the contribution of every factor for audit
+func (s *CreditScoringSvcImpl) Save(ctx context.Context, loanID int64, result *models.CreditScoreResult) error {
+	score := repo.CreditScore{
+		LoanID:    loanID,
+		Score:     result.Score,
+		Grade:     result.Grade,
+		CreatedAt: time.Now(),
+	}
+
+	var err error
+	score.ID, err = s.ScoreRepo.Create(ctx, &score)
+	if err != nil {
+		log.WithField("loanID", loanID).WithError(err).Error("Failed to create credit score")
+		return err
+	}
+
+	for _, factorResult := range result.Factors {
+		factor := repo.CreditScoreFactor{
+			CreditScoreID:   score.ID,
+			Factor:          factorResult.Factor,
+			Value:           factorResult.Value,
+			RuleID:          factorResult.RuleID,
+			RuleDescription: factorResult.RuleDescription,
+			Points:          factorResult.Points,
+			CreatedAt:       score.CreatedAt,
+		}
+		_, err = s.FactorRepo.Create(ctx, &factor)
+		if err != nil {
+			log.WithFields(log.Fields{
+				"loanID": loanID,
+				"factor": factorResult.Factor,
+			}).WithError(err).Error("Failed to create credit score factor")
+			return err
+		}
+	}
+
+	return nil
+}
+
+// GetByLoanID returns the stored score of a loan, nil when the loan was created before scoring
+func (s *CreditScoringSvcImpl) GetByLoanID(ctx context.Context, loanID int64) (*dto.CreditScoreResponseDTO, error) {
+	score, err := s.ScoreRepo.GetByLoanID(ctx, loanID)
+	if err != nil {
+		log.WithField("loanID", loanID).WithError(err).Error("Failed to get credit score")
+		return nil, err
+	}
+	if score == nil {
+		return nil, nil
+	}
+
+	factors, err := s.FactorRepo.GetByCreditScoreID(ctx, score.ID)
+	if err != nil {
+		log.WithField("loanID", loanID).WithError(err).Error("Failed to get credit score factors")
+		return nil, err
+	}
+
+	scoreRes := dto.CreditScoreResponseDTO{
+		Score:     score.Score,
+		Grade:     score.Grade,
+		Factors:   []dto.CreditScoreFactorResponseDTO{},
+		CreatedAt: score.CreatedAt,
+	}
+	for _, factor := range factors {
+		scoreRes.Factors = append(scoreRes.Factors, dto.CreditScoreFactorResponseDTO{
+			Factor:          factor.Factor,
+			Value:           factor.Value,
+			RuleID:          factor.RuleID,
+			RuleDescription: factor.RuleDescription,
+			Points:          factor.Points,
+		})
+	}
+
+	return &scoreRes, nil
+}
+
+func (s *CreditScoringSvcImpl) GetRules(ctx context.Context) ([]dto.CreditScoringRuleResponseDTO, error) {
+	rules, err := s.RuleRepo.GetAll(ctx)
+	if err != nil {
+		log.WithError(err).Error("Failed to get credit scoring rules")
+		return nil, errors.New("99999")
+	}
+
+	ruleDTOs := []dto.CreditScoringRuleResponseDTO{}
+	for _, rule := range rules {
+		ruleRes, err := s.toRuleResponseDTO(rule)
+		if err != nil {
+			return nil, errors.New("99999")
+		}
+		ruleDTOs = append(ruleDTOs, *ruleRes)
+	}
+
+	return ruleDTOs, nil
+}
+
+func (s *CreditScoringSvcImpl) CreateRule(ctx context.Context, request *dto.CreditScoringRuleRequestDTO) (*dto.CreditScoringRuleResponseDTO, error) {
+	log.WithFields(log.Fields{
+		"factor": request.Factor,
+		"points": request.Points,
+	}).Info("Creating credit scoring rule")
+
+	err := s.Validator.ValidateCreate(request)
+	if err != nil {
+		log.WithField("factor", request.Factor).Errorf("Validation failed: %s", err)
+		return nil, err
+	}
+
+	var rule repo.CreditScoringRule
+	err = mapstructure.Decode(request, &rule)
+	if err != nil {
+		log.WithError(err).Error("Failed to map request to credit scoring rule")
+		return nil, errors.New("99999")
+	}
+	rule.MatchValue = strings.TrimSpace(rule.MatchValue)
+	rule.CreatedAt = time.Now()
+	rule.UpdatedAt = rule.CreatedAt
+
+	rule.ID, err = s.RuleRepo.Create(ctx, &rule)
+	if err != nil {
+		log.WithField("factor", request.Factor).WithError(err).Error("Failed to create credit scoring rule")
+		return nil, errors.New("99999")
+	}
+
+	ruleRes, err := s.toRuleResponseDTO(rule)
+	if err != nil {
+		return nil, errors.New("99999")
+	}
+
+	log.WithField("ruleID", rule.ID).Info("Credit scoring rule created successfully")
+	return ruleRes, nil
+}
+
+func (s *CreditScoringSvcImpl) UpdateRule(ctx context.Context, id int64, request *dto.CreditScoringRuleRequestDTO) (*dto.CreditScoringRuleResponseDTO, error) {
+	log.WithFields(log.Fields{
+		"ruleID": id,
+		"factor": request.Factor,
+		"points": request.Points,
+	}).Info("Updating credit scoring rule")
+
+	err := s.Validator.ValidateUpdate(request)
+	if err != nil {
+		log.WithField("ruleID", id).Errorf("Validation failed: %s", err)
+		return nil, err
+	}
+
+	rule, err := s.RuleRepo.GetByID(ctx, id)
+	if err != nil {
+		log.WithField("ruleID", id).WithError(err).Error("Failed to get credit scoring rule")
+		return nil, errors.New("99999")
+	}
+	if rule == nil {
+		log.WithField("ruleID", id).Warn("Credit scoring rule not found")
+		return nil, errors.New("10001")
+	}
+
+	rule.Factor = request.Factor
+	rule.MinValue = request.MinValue
+	rule.MaxValue = request.MaxValue
+	rule.MatchValue = strings.TrimSpace(request.MatchValue)
+	rule.Points = request.Points
+	rule.Description = request.Description
+	rule.UpdatedAt = time.Now()
+	err = s.RuleRepo.Update(ctx, rule)
+	if err != nil {
+		log.WithField("ruleID", id).WithError(err).Error("Failed to update credit scoring rule")
+		return nil, errors.New("99999")
+	}
+
+	ruleRes, err := s.toRuleResponseDTO(*rule)
+	if err != nil {
+		return nil, errors.New("99999")
+	}
+
+	log.WithField("ruleID", id).Info("Credit scoring rule updated successfully")
+	return ruleRes, nil
+}
+
+func (s *CreditScoringSvcImpl) DeleteRule(ctx context.Context, id int64) error {
+	rule, err := s.RuleRepo.GetByID(ctx, id)
+	if err != nil {
+		log.WithField("ruleID", id).WithError(err).Error("Failed to get credit scoring rule")
+		return errors.New("99999")
+	}
+	if rule == nil {
+		log.WithField("ruleID", id).Warn("Credit scoring rule not found")
+		return errors.New("10001")
+	}
+
+	err = s.RuleRepo.Delete(ctx, id)
+	if err != nil {
+		log.WithField("ruleID", id).WithError(err).Error("Failed to delete credit scoring rule")
+		return errors.New("99999")
+	}
+
+	log.WithField("ruleID", id).Info("Credit scoring rule deleted successfully")
+	return nil
+}
+
+func (s *CreditScoringSvcImpl) GetGrades(ctx context.Context) ([]dto.CreditGradeResponseDTO, error) {
+	grades, err := s.GradeRepo.GetAll(ctx)
+	if err != nil {
+		log.WithError(err).Error("Failed to get credit grades")
+		return nil, errors.New("99999")
+	}
+
+	gradeDTOs := []dto.CreditGradeResponseDTO{}
+	for _, grade := range grades {
+		gradeDTOs = append(gradeDTOs, dto.CreditGradeResponseDTO{
+			ID:        grade.ID,
+			Grade:     grade.Grade,
+			MinScore:  grade.MinScore,
+			CreatedAt: grade.CreatedAt,
+			UpdatedAt: grade.UpdatedAt,
+		})
+	}
+
+	return gradeDTOs, nil
+}
+
+// UpdateGrade changes the minimum score of a grade, a better grade always needs a higher score
+func (s *CreditScoringSvcImpl) UpdateGrade(ctx context.Context, grade enum.LoanGrade, request *dto.CreditGradeRequestDTO) error {
+	log.WithFields(log.Fields{
+		"grade":    grade,
+		"minScore": request.MinScore,
+	}).Info("Updating credit grade")
+
+	if !grade.IsValid() {
+		log.WithField("grade", grade).Error("Invalid LoanGrade")
+		return errors.New("10002")
+	}
+
+	if request.MinScore < 0 {
+		log.WithField("grade", grade).Error("MinScore must be non-negative")
+		return errors.New("10003")
+	}
+
+	grades, err := s.GradeRepo.GetAll(ctx)
+	if err != nil {
+		log.WithError(err).Error("Failed to get credit grades")
+		return errors.New("99999")
+	}
+
+	var creditGrade *repo.CreditGrade
+	for i := range grades {
+		if grades[i].Grade == grade {
+			creditGrade = &grades[i]
+			continue
+		}
+		// grades are named from best (A) to worst (E)
+		if (grades[i].Grade < grade && grades[i].MinScore <= request.MinScore) ||
+			(grades[i].Grade > grade && grades[i].MinScore >= request.MinScore) {
+			log.WithFields(log.Fields{
+				"grade":      grade,
+				"minScore":   request.MinScore,
+				"otherGrade": grades[i].Grade,
+			}).Error("MinScore overlaps another grade")
+			return errors.New("10003")
+		}
+	}
+	if creditGrade == nil {
+		log.WithField("grade", grade).Warn("Credit grade not found")
+		return errors.New("10001")
+	}
+
+	creditGrade.MinScore = request.MinScore
+	err = s.GradeRepo.Update(ctx, creditGrade)
+	if err != nil {
+		log.WithField("grade", grade).WithError(err).Error("Failed to update credit grade")
+		return errors.New("99999")
+	}
+
+	log.WithField("grade", grade).Info("Credit grade updated successfully")
+	return nil
+}
+
+func (s *CreditScoringSvcImpl) toRuleResponseDTO(rule repo.CreditScoringRule) (*dto.CreditScoringRuleResponseDTO, error) {
+	var ruleRes dto.CreditScoringRuleResponseDTO
+	err := mapstructure.Decode(rule, &ruleRes)
+	if err != nil {
+		log.WithField("ruleID", rule.ID).WithError(err).Error("Failed to map credit scoring rule to DTO")
+		return nil, err
+	}
+	ruleRes.CreatedAt = rule.CreatedAt
+	ruleRes.UpdatedAt = rule.UpdatedAt
+
+	return &ruleRes, nil
+}
+
+// matchRangeRule returns the first rule of the factor whose range contains the value
+func matchRangeRule(rules []repo.CreditScoringRule, factor enum.CreditScoringFactor, value float64) *repo.CreditScoringRule {
+	for i := range rules {
+		rule := &rules[i]
+		if rule.Factor != factor {
+			continue
+		}
+		if rule.MinValue != nil && value < *rule.MinValue {
+			continue
+		}
+		if rule.MaxValue != nil && value >= *rule.MaxValue {
+			continue
+		}
+		return rule
+	}
+	return nil
+}
+
+// matchNameRule returns the rule of the factor matching the name, or the rule matching every other
+// name when there is none
+func matchNameRule(rules []repo.CreditScoringRule, factor enum.CreditScoringFactor, name string) *repo.CreditScoringRule {
+	var fallback *repo.CreditScoringRule
+	for i := range rules {
+		rule := &rules[i]
+		if rule.Factor != factor {
+			continue
+		}
+		if rule.MatchValue == "" {
+			if fallback == nil {
+				fallback = rule
+			}
+			continue
+		}
+		if strings.EqualFold(rule.MatchValue, name) {
+			return rule
+		}
+	}
+	return fallback
+}
@@ -0,0 +1,229 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"github.com/mitchellh/mapstructure"
+	log "github.com/sirupsen/logrus"
+	"github.com/test/loan-service/internal/dto"
+	"github.com/test/loan-service/internal/enum"
+	repo "github.com/test/loan-service/internal/repository"
+	"github.com/test/loan-service/internal/service/validator"
+	"go.uber.org/dig"
+	"time"
+)
+
+//go:generate mockgen -source=$GOFILE -destination=$PROJ/internal/generated/mock/mock_$GOPACKAGE/$GOFILE
+
+type (
+	// DocumentRequirementSvc holds the catalog of documents the approval team collects for every loan
+	// type. A loan can not be approved until a document of every mandatory type is attached to its approval.
+	DocumentRequirementSvc interface {
+		Create(ctx context.Context, request *dto.DocumentRequirementRequestDTO) (*dto.DocumentRequirementResponseDTO, error)
+		Update(ctx context.Context, id int64, request *dto.DocumentRequirementRequestDTO) (*dto.DocumentRequirementResponseDTO, error)
+		Delete(ctx context.Context, id int64) error
+		GetAll(ctx context.Context, loanType enum.LoanType) ([]dto.DocumentRequirementResponseDTO, error)
+		MissingDocuments(ctx context.Context, loanType enum.LoanType, documentTypes []string) ([]string, error)
+	}
+
+	DocumentRequirementSvcImpl struct {
+		dig.In
+		Repo      repo.DocumentRequirementRepo
+		Validator validator.DocumentRequirementValidatorImpl
+	}
+)
+
+func NewDocumentRequirementSvc(impl DocumentRequirementSvcImpl) DocumentRequirementSvc {
+	return &impl
+}
+
+func (s *DocumentRequirementSvcImpl) Create(ctx context.Context, request *dto.DocumentRequirementRequestDTO) (*dto.DocumentRequirementResponseDTO, error) {
+	log.WithFields(log.Fields{
+		"loanType":     request.LoanType,
+		"documentType": request.DocumentType,
+		"mandatory":    request.Mandatory,
+	}).Info("Creating document requirement")
+
+	err := s.Validator.ValidateCreate(request)
+	if err != nil {
+		log.WithField("documentType", request.DocumentType).Errorf("Validation failed: %s", err)
+		return nil, err
+	}
+
+	err = s.checkDuplicate(ctx, 0, request)
+	if err != nil {
+		return nil, err
+	}
+
+	var requirement repo.DocumentRequirement
+	err = mapstructure.Decode(request, &requirement)
+	if err != nil {
+		log.WithError(err).Error("Failed to map request to document requirement")
+		return nil, errors.New("99999")
+	}
+	requirement.CreatedAt = time.Now()
+	requirement.UpdatedAt = requirement.CreatedAt
+
+	requirement.ID, err = s.Repo.Create(ctx, &requirement)
+	if err != nil {
+		log.WithField("documentType", request.DocumentType).WithError(err).Error("Failed to create document requirement")
+		return nil, errors.New("99999")
+	}
+
+	requirementRes, err := s.toResponseDTO(requirement)
+	if err != nil {
+		return nil, errors.New("99999")
+	}
+
+	log.WithField("requirementID", requirement.ID).Info("Document requirement created successfully")
+	return requirementRes, nil
+}
+
+func (s *DocumentRequirementSvcImpl) Update(ctx context.Context, id int64, request *dto.DocumentRequirementRequestDTO) (*dto.DocumentRequirementResponseDTO, error) {
+	log.WithFields(log.Fields{
+		"requirementID": id,
+		"loanType":      request.LoanType,
+		"documentType":  request.DocumentType,
+		"mandatory":     request.Mandatory,
+	}).Info("Updating document requirement")
+
+	err := s.Validator.ValidateUpdate(request)
+	if err != nil {
+		log.WithField("requirementID", id).Errorf("Validation failed: %s", err)
+		return nil, err
+	}
+
+	requirement, err := s.Repo.GetByID(ctx, id)
+	if err != nil {
+		log.WithField("requirementID", id).WithError(err).Error("Failed to get document requirement")
+		return nil, errors.New("99999")
+	}
+	if requirement == nil {
+		log.WithField("requirementID", id).Warn("Document requirement not found")
+		return nil, errors.New("10001")
+	}
+
+	err = s.checkDuplicate(ctx, id, request)
+	if err != nil {
+		return nil, err
+	}
+
+	requirement.LoanType = request.LoanType
+	requirement.DocumentType = request.DocumentType
+	requirement.Name = request.Name
+	requirement.Mandatory = request.Mandatory
+	requirement.UpdatedAt = time.Now()
+	err = s.Repo.Update(ctx, requirement)
+	if err != nil {
+		log.WithField("requirementID", id).WithError(err).Error("Failed to update document requirement")
+		return nil, errors.New("99999")
+	}
+
+	requirementRes, err := s.toResponseDTO(*requirement)
+	if err != nil {
+		return nil, errors.New("99999")
+	}
+
+	log.WithField("requirementID", id).Info("Document requirement updated successfully")
+	return requirementRes, nil
+}
+
+func (s *DocumentRequirementSvcImpl) Delete(ctx context.Context, id int64) error {
+	requirement, err := s.Repo.GetByID(ctx, id)
+	if err != nil {
+		log.WithField("requirementID", id).WithError(err).Error("Failed to get document requirement")
+		return errors.New("99999")
+	}
+	if requirement == nil {
+		log.WithField("requirementID", id).Warn("Document requirement not found")
+		return errors.New("10001")
+	}
+
+	err = s.Repo.Delete(ctx, id)
+	if err != nil {
+		log.WithField("requirementID", id).WithError(err).Error("Failed to delete document requirement")
+		return errors.New("99999")
+	}
+
+	log.WithField("requirementID", id).Info("Document requirement deleted successfully")
+	return nil
+}
+
+func (s *DocumentRequirementSvcImpl) GetAll(ctx context.Context, loanType enum.LoanType) ([]dto.DocumentRequirementResponseDTO, error) {
+	requirements, err := s.Repo.GetAll(ctx, repo.DocumentRequirementRequest{LoanType: loanType})
+	if err != nil {
+		log.WithField("loanType", loanType).WithError(err).Error("Failed to get document requirements")
+		return nil, errors.New("99999")
+	}
+
+	requirementDTOs := []dto.DocumentRequirementResponseDTO{}
+	for _, requirement := range requirements {
+		requirementRes, err := s.toResponseDTO(requirement)
+		if err != nil {
+			return nil, errors.New("99999")
+		}
+		requirementDTOs = append(requirementDTOs, *requirementRes)
+	}
+
+	return requirementDTOs, nil
+}
+
+// MissingDocuments returns the names of the mandatory documents of the loan type that are not among
+// the document types, in the order of the catalog
+func (s *DocumentRequirementSvcImpl) MissingDocuments(ctx context.Context, loanType enum.LoanType, documentTypes []string) ([]string, error) {
+	requirements, err := s.Repo.GetAll(ctx, repo.DocumentRequirementRequest{LoanType: loanType})
+	if err != nil {
+		log.WithField("loanType", loanType).WithError(err).Error("Failed to get document requirements")
+		return nil, err
+	}
+
+	present := make(map[string]bool, len(documentTypes))
+	for _, documentType := range documentTypes {
+		present[documentType] = true
+	}
+
+	var missing []string
+	for _, requirement := range requirements {
+		if requirement.Mandatory && !present[requirement.DocumentType] {
+			missing = append(missing, requirement.Name)
+		}
+	}
+
+	return missing, nil
+}
+
+// checkDuplicate refuses a second requirement for the same document type of a loan type, id is the
+// requirement being updated, zero on create
+func (s *DocumentRequirementSvcImpl) checkDuplicate(ctx context.Context, id int64, request *dto.DocumentRequirementRequestDTO) error {
+	requirements, err := s.Repo.GetAll(ctx, repo.DocumentRequirementRequest{LoanType: request.LoanType})
+	if err != nil {
+		log.WithField("loanType", request.LoanType).WithError(err).Error("Failed to get document requirements")
+		return errors.New("99999")
+	}
+
+	for _, requirement := range requirements {
+		if requirement.ID != id && requirement.DocumentType == request.DocumentType {
+			log.WithFields(log.Fields{
+				"loanType":      request.LoanType,
+				"documentType":  request.DocumentType,
+				"requirementID": requirement.ID,
+			}).Warn("Document type already required for loan type")
+			return errors.New("10003")
+		}
+	}
+
+	return nil
+}
+
+func (s *DocumentRequirementSvcImpl) toResponseDTO(requirement repo.DocumentRequirement) (*dto.DocumentRequirementResponseDTO, error) {
+	var requirementRes dto.DocumentRequirementResponseDTO
+	err := mapstructure.Decode(requirement, &requirementRes)
+	if err != nil {
+		log.WithField("requirementID", requirement.ID).WithError(err).Error("Failed to map document requirement to DTO")
+		return nil, err
+	}
+	requirementRes.CreatedAt = requirement.CreatedAt
+	requirementRes.UpdatedAt = requirement.UpdatedAt
+
+	return &requirementRes, nil
+}
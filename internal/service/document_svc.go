@@ -0,0 +1,432 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"github.com/mitchellh/mapstructure"
+	log "github.com/sirupsen/logrus"
+	"github.com/test/loan-service/internal/dto"
+	"github.com/test/loan-service/internal/enum"
+	repo "github.com/test/loan-service/internal/repository"
+	"github.com/test/loan-service/internal/service/models"
+	"github.com/test/loan-service/internal/service/validator"
+	"github.com/test/loan-service/internal/storage"
+	"github.com/test/loan-service/internal/utils"
+	"go.uber.org/dig"
+	"io"
+	"mime"
+	"net/http"
+	"path"
+	"strconv"
+	"strings"
+	"time"
+)
+
+//go:generate mockgen -source=$GOFILE -destination=$PROJ/internal/generated/mock/mock_$GOPACKAGE/$GOFILE
+
+// uploadKeyPrefix is where uploaded files are kept in the document store, they are only served through
+// signed download URLs
+const uploadKeyPrefix = "uploads/"
+
+type (
+	// DocumentSvc keeps the documents of loans, approvals, fundings, disbursements, borrowers and
+	// lenders. Every stored file has a record with its SHA-256 so it can be shown to be unchanged,
+	// uploaded files are downloaded through signed URLs that expire.
+	DocumentSvc interface {
+		Get(ctx context.Context, key string) (*models.DocumentFile, error)
+		Upload(ctx context.Context, request *dto.DocumentUploadRequestDTO, file models.UploadFile) (*dto.DocumentResponseDTO, error)
+		Register(ctx context.Context, document *repo.Document, content []byte) error
+		GetByID(ctx context.Context, id int64) (*dto.DocumentResponseDTO, error)
+		GetByEntity(ctx context.Context, entityType enum.DocumentEntity, entityID int64) ([]dto.DocumentResponseDTO, error)
+		GetLinked(ctx context.Context, id int64, entityType enum.DocumentEntity, entityID int64) (*dto.DocumentResponseDTO, error)
+		Download(ctx context.Context, id int64, expires int64, signature string) (*models.DocumentFile, error)
+		Verify(ctx context.Context, id int64) (*dto.DocumentVerificationResponseDTO, error)
+	}
+
+	DocumentSvcImpl struct {
+		dig.In
+		Repo                 repo.DocumentRepo
+		DocumentStore        storage.DocumentStore
+		URLSigner            *storage.URLSigner
+		UploadPolicy         *storage.UploadPolicy
+		LoanRepo             repo.LoanRepo
+		LoanApprovalRepo     repo.LoanApprovalRepo
+		LoanFundingRepo      repo.LoanFundingRepo
+		LoanDisbursementRepo repo.LoanDisbursementRepo
+		BorrowerRepo         repo.BorrowerRepo
+		LenderRepo           repo.LenderRepo
+		Validator            validator.DocumentUploadValidatorImpl
+	}
+)
+
+func NewDocumentSvc(impl DocumentSvcImpl) DocumentSvc {
+	return &impl
+}
+
+// Get returns the document stored under the key, the content type is derived from its extension.
+// Uploaded files and every file with a document record are not served by key, only through signed URLs.
+func (s *DocumentSvcImpl) Get(ctx context.Context, key string) (*models.DocumentFile, error) {
+	if strings.HasPrefix(key, uploadKeyPrefix) {
+		log.WithField("key", key).Warn("Uploaded documents are only served through signed URLs")
+		return nil, errors.New("10001")
+	}
+
+	document, err := s.Repo.GetByStorageKey(ctx, key)
+	if err != nil {
+		log.WithField("key", key).WithError(err).Error("Failed to get document record")
+		return nil, errors.New("99999")
+	}
+	if document != nil {
+		log.WithField("key", key).Warn("Registered documents are only served through signed URLs")
+		return nil, errors.New("10001")
+	}
+
+	content, err := s.DocumentStore.Get(ctx, key)
+	if errors.Is(err, storage.ErrNotFound) {
+		log.WithField("key", key).Error("Document not found")
+		return nil, errors.New("10001")
+	}
+	if err != nil {
+		log.WithField("key", key).WithError(err).Error("Failed to get document")
+		return nil, errors.New("99999")
+	}
+
+	contentType := mime.TypeByExtension(path.Ext(key))
+	if contentType == "" {
+		contentType = "application/octet-stream"
+	}
+
+	return &models.DocumentFile{
+		FileName:    path.Base(key),
+		ContentType: contentType,
+		Content:     content,
+	}, nil
+}
+
+// Upload stores a file for a record. The content type is detected from the content, not taken from
+// the client, and has to be allowed by the upload policy.
+func (s *DocumentSvcImpl) Upload(ctx context.Context, request *dto.DocumentUploadRequestDTO, file models.UploadFile) (*dto.DocumentResponseDTO, error) {
+	log.WithFields(log.Fields{
+		"entityType":   request.EntityType,
+		"entityID":     request.EntityID,
+		"documentType": request.DocumentType,
+		"fileName":     file.FileName,
+		"size":         file.Size,
+	}).Info("Uploading document")
+
+	err := s.Validator.ValidateCreate(request)
+	if err != nil {
+		log.WithField("fileName", file.FileName).Errorf("Validation failed: %s", err)
+		return nil, err
+	}
+
+	if file.Size > s.UploadPolicy.MaxSize {
+		log.WithFields(log.Fields{
+			"size":    file.Size,
+			"maxSize": s.UploadPolicy.MaxSize,
+		}).Warn("Document exceeds the maximum upload size")
+		return nil, errors.New("10017")
+	}
+
+	// the declared size can not be trusted, read at most one byte past the limit
+	content, err := io.ReadAll(io.LimitReader(file.Content, s.UploadPolicy.MaxSize+1))
+	if err != nil {
+		log.WithField("fileName", file.FileName).WithError(err).Error("Failed to read uploaded document")
+		return nil, errors.New("99999")
+	}
+	if int64(len(content)) > s.UploadPolicy.MaxSize {
+		log.WithField("maxSize", s.UploadPolicy.MaxSize).Warn("Document exceeds the maximum upload size")
+		return nil, errors.New("10017")
+	}
+	if len(content) == 0 {
+		log.WithField("fileName", file.FileName).Warn("Uploaded document is empty")
+		return nil, errors.New("10003")
+	}
+
+	contentType := http.DetectContentType(content)
+	if !s.UploadPolicy.AllowsContentType(contentType) {
+		log.WithFields(log.Fields{
+			"fileName":    file.FileName,
+			"contentType": contentType,
+		}).Warn("Document content type is not allowed")
+		return nil, errors.New("10016")
+	}
+
+	err = s.checkEntity(ctx, request.EntityType, request.EntityID)
+	if err != nil {
+		return nil, err
+	}
+
+	fileName := path.Base(strings.ReplaceAll(file.FileName, "\\", "/"))
+	key := uploadKeyPrefix + string(request.EntityType) + "/" + strconv.FormatInt(request.EntityID, 10) + "/" +
+		utils.GenerateAlphanumericCode(16) + uploadExtension(fileName, contentType)
+
+	_, err = s.DocumentStore.Put(ctx, key, content)
+	if err != nil {
+		log.WithField("key", key).WithError(err).Error("Failed to store uploaded document")
+		return nil, errors.New("99999")
+	}
+
+	document := repo.Document{
+		StorageKey:   key,
+		FileName:     fileName,
+		ContentType:  contentType,
+		EntityType:   request.EntityType,
+		EntityID:     request.EntityID,
+		DocumentType: request.DocumentType,
+	}
+	if request.UploadedBy > 0 {
+		document.UploadedBy = &request.UploadedBy
+	}
+
+	err = s.Register(ctx, &document, content)
+	if err != nil {
+		return nil, errors.New("99999")
+	}
+
+	log.WithFields(log.Fields{
+		"documentID": document.ID,
+		"checksum":   document.Checksum,
+	}).Info("Document uploaded successfully")
+	return s.toResponseDTO(document)
+}
+
+// Register records a document already put in the store, the size and checksum are taken from the
+// content. The content type is kept when set, otherwise it is detected from the content.
+func (s *DocumentSvcImpl) Register(ctx context.Context, document *repo.Document, content []byte) error {
+	if document.ContentType == "" {
+		document.ContentType = http.DetectContentType(content)
+	}
+	document.Size = int64(len(content))
+	document.Checksum = storage.Checksum(content)
+	document.CreatedAt = time.Now()
+	document.UpdatedAt = document.CreatedAt
+
+	var err error
+	document.ID, err = s.Repo.Create(ctx, document)
+	if err != nil {
+		log.WithField("key", document.StorageKey).WithError(err).Error("Failed to create document record")
+		return err
+	}
+
+	return nil
+}
+
+// GetByID returns the document with a fresh download URL
+func (s *DocumentSvcImpl) GetByID(ctx context.Context, id int64) (*dto.DocumentResponseDTO, error) {
+	document, err := s.Repo.GetByID(ctx, id)
+	if err != nil {
+		log.WithField("documentID", id).WithError(err).Error("Failed to get document")
+		return nil, errors.New("99999")
+	}
+	if document == nil {
+		log.WithField("documentID", id).Warn("Document not found")
+		return nil, errors.New("10001")
+	}
+
+	return s.toResponseDTO(*document)
+}
+
+// GetByEntity returns the documents of a record, each with a fresh download URL
+func (s *DocumentSvcImpl) GetByEntity(ctx context.Context, entityType enum.DocumentEntity, entityID int64) ([]dto.DocumentResponseDTO, error) {
+	documents, err := s.Repo.GetByEntity(ctx, entityType, entityID)
+	if err != nil {
+		log.WithFields(log.Fields{
+			"entityType": entityType,
+			"entityID":   entityID,
+		}).WithError(err).Error("Failed to get documents")
+		return nil, errors.New("99999")
+	}
+
+	documentDTOs := []dto.DocumentResponseDTO{}
+	for _, document := range documents {
+		documentRes, err := s.toResponseDTO(document)
+		if err != nil {
+			return nil, err
+		}
+		documentDTOs = append(documentDTOs, *documentRes)
+	}
+
+	return documentDTOs, nil
+}
+
+// GetLinked returns the document when it belongs to the record, 10001 when it does not exist or
+// belongs to another record
+func (s *DocumentSvcImpl) GetLinked(ctx context.Context, id int64, entityType enum.DocumentEntity, entityID int64) (*dto.DocumentResponseDTO, error) {
+	document, err := s.Repo.GetByID(ctx, id)
+	if err != nil {
+		log.WithField("documentID", id).WithError(err).Error("Failed to get document")
+		return nil, errors.New("99999")
+	}
+	if document == nil || document.EntityType != entityType || document.EntityID != entityID {
+		log.WithFields(log.Fields{
+			"documentID": id,
+			"entityType": entityType,
+			"entityID":   entityID,
+		}).Warn("Document not found for record")
+		return nil, errors.New("10001")
+	}
+
+	return s.toResponseDTO(*document)
+}
+
+// Download returns the file of a signed download URL. The file is only served while it still matches
+// the checksum recorded when it was stored.
+func (s *DocumentSvcImpl) Download(ctx context.Context, id int64, expires int64, signature string) (*models.DocumentFile, error) {
+	err := s.URLSigner.Verify(id, expires, signature, time.Now())
+	if err != nil {
+		log.WithField("documentID", id).WithError(err).Warn("Download URL refused")
+		return nil, errors.New("10018")
+	}
+
+	document, err := s.Repo.GetByID(ctx, id)
+	if err != nil {
+		log.WithField("documentID", id).WithError(err).Error("Failed to get document")
+		return nil, errors.New("99999")
+	}
+	if document == nil {
+		log.WithField("documentID", id).Warn("Document not found")
+		return nil, errors.New("10001")
+	}
+
+	content, err := s.DocumentStore.Get(ctx, document.StorageKey)
+	if errors.Is(err, storage.ErrNotFound) {
+		log.WithField("key", document.StorageKey).Error("Document file is missing from the store")
+		return nil, errors.New("10001")
+	}
+	if err != nil {
+		log.WithField("key", document.StorageKey).WithError(err).Error("Failed to get document")
+		return nil, errors.New("99999")
+	}
+
+	if storage.Checksum(content) != document.Checksum {
+		log.WithField("documentID", id).Error("Document file does not match its recorded checksum")
+		return nil, errors.New("10019")
+	}
+
+	return &models.DocumentFile{
+		FileName:    document.FileName,
+		ContentType: document.ContentType,
+		Content:     content,
+	}, nil
+}
+
+// Verify checks that the file of the document is still in the store and unchanged since it was stored
+func (s *DocumentSvcImpl) Verify(ctx context.Context, id int64) (*dto.DocumentVerificationResponseDTO, error) {
+	document, err := s.Repo.GetByID(ctx, id)
+	if err != nil {
+		log.WithField("documentID", id).WithError(err).Error("Failed to get document")
+		return nil, errors.New("99999")
+	}
+	if document == nil {
+		log.WithField("documentID", id).Warn("Document not found")
+		return nil, errors.New("10001")
+	}
+
+	verification := dto.DocumentVerificationResponseDTO{
+		ID:         document.ID,
+		Checksum:   document.Checksum,
+		VerifiedAt: time.Now(),
+	}
+
+	content, err := s.DocumentStore.Get(ctx, document.StorageKey)
+	if err != nil && !errors.Is(err, storage.ErrNotFound) {
+		log.WithField("key", document.StorageKey).WithError(err).Error("Failed to get document")
+		return nil, errors.New("99999")
+	}
+	if err == nil {
+		verification.Exists = true
+		verification.ActualChecksum = storage.Checksum(content)
+		verification.Unchanged = verification.ActualChecksum == document.Checksum
+	}
+
+	log.WithFields(log.Fields{
+		"documentID": id,
+		"exists":     verification.Exists,
+		"unchanged":  verification.Unchanged,
+	}).Info("Document verified")
+	return &verification, nil
+}
+
+// checkEntity makes sure the record an upload is linked to exists
+func (s *DocumentSvcImpl) checkEntity(ctx context.Context, entityType enum.DocumentEntity, entityID int64) error {
+	var found bool
+	var err error
+	switch entityType {
+	case enum.DocumentEntityLoan:
+		var loan *repo.Loan
+		loan, err = s.LoanRepo.GetByID(ctx, entityID)
+		found = loan != nil
+	case enum.DocumentEntityLoanApproval:
+		var approval *repo.LoanApproval
+		approval, err = s.LoanApprovalRepo.GetByID(ctx, entityID)
+		found = approval != nil
+	case enum.DocumentEntityLoanFunding:
+		var funding *repo.LoanFunding
+		funding, err = s.LoanFundingRepo.GetByID(ctx, entityID)
+		found = funding != nil
+	case enum.DocumentEntityLoanDisbursement:
+		var disbursement *repo.LoanDisbursement
+		disbursement, err = s.LoanDisbursementRepo.GetByID(ctx, entityID)
+		found = disbursement != nil
+	case enum.DocumentEntityBorrower:
+		var borrower *repo.Borrower
+		borrower, err = s.BorrowerRepo.GetByID(ctx, entityID)
+		found = borrower != nil
+	case enum.DocumentEntityLender:
+		var lender *repo.Lender
+		lender, err = s.LenderRepo.GetByID(ctx, entityID)
+		found = lender != nil
+	}
+
+	if err != nil {
+		log.WithFields(log.Fields{
+			"entityType": entityType,
+			"entityID":   entityID,
+		}).WithError(err).Error("Failed to get document record owner")
+		return errors.New("99999")
+	}
+	if !found {
+		log.WithFields(log.Fields{
+			"entityType": entityType,
+			"entityID":   entityID,
+		}).Warn("Document record owner not found")
+		return errors.New("10001")
+	}
+
+	return nil
+}
+
+func (s *DocumentSvcImpl) toResponseDTO(document repo.Document) (*dto.DocumentResponseDTO, error) {
+	var documentRes dto.DocumentResponseDTO
+	err := mapstructure.Decode(document, &documentRes)
+	if err != nil {
+		log.WithField("documentID", document.ID).WithError(err).Error("Failed to map document to DTO")
+		return nil, errors.New("99999")
+	}
+	documentRes.CreatedAt = document.CreatedAt
+	documentRes.URL = s.URLSigner.DocumentURL(document.ID)
+	documentRes.DownloadURL, documentRes.DownloadURLExpiresAt = s.URLSigner.Sign(document.ID, time.Now())
+
+	return &documentRes, nil
+}
+
+// uploadExtension keeps the extension of the uploaded file name when it matches the detected content
+// type, the stored file otherwise has none
+func uploadExtension(fileName string, contentType string) string {
+	ext := strings.ToLower(path.Ext(fileName))
+	if ext == "" {
+		return ""
+	}
+
+	extType, _, err := mime.ParseMediaType(mime.TypeByExtension(ext))
+	if err != nil {
+		return ""
+	}
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil || extType != mediaType {
+		return ""
+	}
+
+	return ext
+}
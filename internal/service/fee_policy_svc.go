@@ -0,0 +1,135 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"github.com/mitchellh/mapstructure"
+	log "github.com/sirupsen/logrus"
+	"github.com/test/loan-service/internal/dto"
+	"github.com/test/loan-service/internal/enum"
+	"github.com/test/loan-service/internal/money"
+	repo "github.com/test/loan-service/internal/repository"
+	"github.com/test/loan-service/internal/service/validator"
+	"github.com/test/loan-service/internal/utils"
+	"go.uber.org/dig"
+)
+
+//go:generate mockgen -source=$GOFILE -destination=$PROJ/internal/generated/mock/mock_$GOPACKAGE/$GOFILE
+
+type (
+	// FeePolicySvc holds the platform fees of every loan type. The origination fee is deducted from the
+	// amount paid out to the borrower, the service fee is taken from the interest paid to lenders.
+	FeePolicySvc interface {
+		Update(ctx context.Context, loanType enum.LoanType, request *dto.FeePolicyRequestDTO) error
+		GetAll(ctx context.Context) ([]dto.FeePolicyResponseDTO, error)
+		OriginationFee(ctx context.Context, loanType enum.LoanType, amount money.Amount) (money.Amount, error)
+		ServiceFeePercentage(ctx context.Context, loanType enum.LoanType) (float64, error)
+	}
+
+	FeePolicySvcImpl struct {
+		dig.In
+		Repo      repo.FeePolicyRepo
+		Validator validator.FeePolicyValidatorImpl
+	}
+)
+
+func NewFeePolicySvc(impl FeePolicySvcImpl) FeePolicySvc {
+	return &impl
+}
+
+func (s *FeePolicySvcImpl) Update(ctx context.Context, loanType enum.LoanType, request *dto.FeePolicyRequestDTO) error {
+	log.WithFields(log.Fields{
+		"loanType":                 loanType,
+		"originationFeePercentage": request.OriginationFeePercentage,
+		"serviceFeePercentage":     request.ServiceFeePercentage,
+	}).Info("Updating fee policy")
+
+	if !loanType.IsValid() {
+		log.WithField("loanType", loanType).Error("Invalid LoanType")
+		return errors.New("10002")
+	}
+
+	err := s.Validator.ValidateUpdate(request)
+	if err != nil {
+		log.WithField("loanType", loanType).Errorf("Validation failed: %s", err)
+		return err
+	}
+
+	policy, err := s.Repo.GetByLoanType(ctx, loanType)
+	if err != nil {
+		log.WithField("loanType", loanType).WithError(err).Error("Failed to get fee policy")
+		return errors.New("99999")
+	}
+	if policy == nil {
+		log.WithField("loanType", loanType).Warn("Fee policy not found")
+		return errors.New("10001")
+	}
+
+	policy.OriginationFeePercentage = request.OriginationFeePercentage
+	policy.ServiceFeePercentage = request.ServiceFeePercentage
+	err = s.Repo.Update(ctx, policy)
+	if err != nil {
+		log.WithField("loanType", loanType).WithError(err).Error("Failed to update fee policy")
+		return errors.New("99999")
+	}
+
+	log.WithField("loanType", loanType).Info("Fee policy updated successfully")
+	return nil
+}
+
+func (s *FeePolicySvcImpl) GetAll(ctx context.Context) ([]dto.FeePolicyResponseDTO, error) {
+	policies, err := s.Repo.GetAll(ctx)
+	if err != nil {
+		log.WithError(err).Error("Failed to get fee policies")
+		return nil, errors.New("99999")
+	}
+
+	policyDTOs := []dto.FeePolicyResponseDTO{}
+	for _, policy := range policies {
+		var policyRes dto.FeePolicyResponseDTO
+		err = mapstructure.Decode(policy, &policyRes)
+		if err != nil {
+			log.WithField("loanType", policy.LoanType).WithError(err).Error("Failed to map fee policy to DTO")
+			return nil, errors.New("99999")
+		}
+		policyRes.CreatedAt = policy.CreatedAt
+		policyRes.UpdatedAt = policy.UpdatedAt
+		policyRes.DeletedAt = policy.DeletedAt
+
+		policyDTOs = append(policyDTOs, policyRes)
+	}
+
+	return policyDTOs, nil
+}
+
+// OriginationFee returns the fee deducted when the amount of a loan of the type is disbursed, zero
+// when the loan type has no fee policy
+func (s *FeePolicySvcImpl) OriginationFee(ctx context.Context, loanType enum.LoanType, amount money.Amount) (money.Amount, error) {
+	policy, err := s.Repo.GetByLoanType(ctx, loanType)
+	if err != nil {
+		log.WithField("loanType", loanType).WithError(err).Error("Failed to get fee policy")
+		return 0, err
+	}
+	if policy == nil {
+		log.WithField("loanType", loanType).Info("No fee policy for loan type, no origination fee")
+		return 0, nil
+	}
+
+	return utils.CalculatePercentage(amount, policy.OriginationFeePercentage), nil
+}
+
+// ServiceFeePercentage returns the percentage of the interest taken from lenders of a loan of the
+// type, zero when the loan type has no fee policy
+func (s *FeePolicySvcImpl) ServiceFeePercentage(ctx context.Context, loanType enum.LoanType) (float64, error) {
+	policy, err := s.Repo.GetByLoanType(ctx, loanType)
+	if err != nil {
+		log.WithField("loanType", loanType).WithError(err).Error("Failed to get fee policy")
+		return 0, err
+	}
+	if policy == nil {
+		log.WithField("loanType", loanType).Info("No fee policy for loan type, no service fee")
+		return 0, nil
+	}
+
+	return policy.ServiceFeePercentage, nil
+}
@@ -0,0 +1,476 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"github.com/mitchellh/mapstructure"
+	log "github.com/sirupsen/logrus"
+	"github.com/test/loan-service/internal/dto"
+	"github.com/test/loan-service/internal/enum"
+	"github.com/test/loan-service/internal/money"
+	repo "github.com/test/loan-service/internal/repository"
+	"github.com/test/loan-service/internal/service/validator"
+	"github.com/test/loan-service/internal/utils"
+	"github.com/typical-go/typical-rest-server/pkg/dbtxn"
+	"go.uber.org/dig"
+	"time"
+)
+
+//go:generate mockgen -source=$GOFILE -destination=$PROJ/internal/generated/mock/mock_$GOPACKAGE/$GOFILE
+
+type (
+	// FundingMarketSvc is the secondary market of on going fundings. A lender lists the outstanding
+	// principal of a funding, or part of it, at a price. Buying a listing moves that principal and its
+	// share of the remaining interest, service fee and tax into a new funding of the buyer and pays
+	// the price from the buyer wallet to the seller wallet, all in one transaction. Repayments are
+	// allocated by outstanding principal, so the buyer receives the share the seller gave up.
+	FundingMarketSvc interface {
+		CreateListing(ctx context.Context, request *dto.FundingListingRequestDTO) (*dto.FundingListingResponseDTO, error)
+		CancelListing(ctx context.Context, id int64, request *dto.FundingListingCancelRequestDTO) error
+		GetListing(ctx context.Context, id int64) (*dto.FundingListingResponseDTO, error)
+		GetListings(ctx context.Context, request repo.FundingListingRequest) ([]dto.FundingListingResponseDTO, error)
+		Buy(ctx context.Context, listingID int64, request *dto.FundingPurchaseRequestDTO) (*dto.FundingTransferResponseDTO, error)
+		GetTransfersByLenderID(ctx context.Context, lenderID int64) ([]dto.FundingTransferResponseDTO, error)
+	}
+
+	FundingMarketSvcImpl struct {
+		dig.In
+		ListingRepo     repo.FundingListingRepo
+		TransferRepo    repo.FundingTransferRepo
+		LoanFundingRepo repo.LoanFundingRepo
+		LoanRepo        repo.LoanRepo
+		WalletSvc       LenderWalletSvc
+		TaxSvc          TaxSvc
+		LimitSvc        InvestmentLimitSvc
+		LenderSvc       LenderSvc
+		Validator       validator.FundingListingValidatorImpl
+		CancelValidator validator.FundingListingCancelValidatorImpl
+	}
+)
+
+func NewFundingMarketSvc(impl FundingMarketSvcImpl) FundingMarketSvc {
+	return &impl
+}
+
+// CreateListing offers outstanding principal of an on going funding for sale. The principal of the
+// open listings of a funding together must not exceed its outstanding principal.
+func (s *FundingMarketSvcImpl) CreateListing(ctx context.Context, request *dto.FundingListingRequestDTO) (*dto.FundingListingResponseDTO, error) {
+	log.WithFields(log.Fields{
+		"loanFundingID":   request.LoanFundingID,
+		"lenderID":        request.LenderID,
+		"principalAmount": request.PrincipalAmount,
+		"price":           request.Price,
+	}).Info("Creating funding listing")
+
+	err := s.Validator.ValidateCreate(request)
+	if err != nil {
+		log.WithField("loanFundingID", request.LoanFundingID).Errorf("Validation failed: %s", err)
+		return nil, err
+	}
+
+	txnCtx := dbtxn.Begin(&ctx)
+	defer func() {
+		if err := txnCtx.Commit(); err != nil {
+			log.WithError(err).Error("Transaction commit failed")
+		}
+	}()
+
+	// the funding is locked so two listings can not oversell it
+	funding, err := s.LoanFundingRepo.GetByIDForUpdate(ctx, request.LoanFundingID)
+	if err != nil {
+		log.WithField("loanFundingID", request.LoanFundingID).WithError(err).Error("Failed to get loan funding")
+		txnCtx.AppendError(err)
+		return nil, errors.New("99999")
+	}
+	if funding == nil {
+		log.WithField("loanFundingID", request.LoanFundingID).Warn("Loan funding not found")
+		txnCtx.AppendError(errors.New("loan funding not found"))
+		return nil, errors.New("10001")
+	}
+
+	if funding.LenderID != request.LenderID || funding.Status != enum.LoanFundingOngoing {
+		log.WithFields(log.Fields{
+			"loanFundingID": funding.ID,
+			"lenderID":      request.LenderID,
+			"status":        funding.Status,
+		}).Warn("Loan funding can not be listed by the lender")
+		txnCtx.AppendError(errors.New("loan funding can not be listed"))
+		return nil, errors.New("10003")
+	}
+
+	openListings, err := s.ListingRepo.GetOpenByLoanFundingID(ctx, funding.ID)
+	if err != nil {
+		log.WithField("loanFundingID", funding.ID).WithError(err).Error("Failed to get open funding listings")
+		txnCtx.AppendError(err)
+		return nil, errors.New("99999")
+	}
+	listed := request.PrincipalAmount
+	for _, listing := range openListings {
+		listed += listing.PrincipalAmount
+	}
+	if listed > outstandingPrincipal(*funding) {
+		log.WithFields(log.Fields{
+			"loanFundingID": funding.ID,
+			"listed":        listed,
+			"outstanding":   outstandingPrincipal(*funding),
+		}).Warn("Listed principal exceeds the outstanding principal of the funding")
+		txnCtx.AppendError(errors.New("listed principal exceeds outstanding principal"))
+		return nil, errors.New("10003")
+	}
+
+	listing := repo.FundingListing{
+		ListingCode:     utils.GenerateAlphanumericCode(10),
+		LoanFundingID:   funding.ID,
+		LoanID:          funding.LoanID,
+		SellerID:        funding.LenderID,
+		PrincipalAmount: request.PrincipalAmount,
+		Price:           request.Price,
+		Status:          enum.FundingListingOpen,
+		CreatedAt:       time.Now(),
+		UpdatedAt:       time.Now(),
+	}
+	listing.ID, err = s.ListingRepo.Create(ctx, &listing)
+	if err != nil {
+		log.WithField("loanFundingID", funding.ID).WithError(err).Error("Failed to create funding listing")
+		txnCtx.AppendError(err)
+		return nil, errors.New("99999")
+	}
+
+	log.WithFields(log.Fields{
+		"loanFundingID": funding.ID,
+		"listingCode":   listing.ListingCode,
+	}).Info("Funding listing created successfully")
+	return s.toListingResponseDTO(listing)
+}
+
+// CancelListing takes an open listing off the market on request of its seller
+func (s *FundingMarketSvcImpl) CancelListing(ctx context.Context, id int64, request *dto.FundingListingCancelRequestDTO) error {
+	err := s.CancelValidator.ValidateCreate(request)
+	if err != nil {
+		log.WithField("listingID", id).Errorf("Validation failed: %s", err)
+		return err
+	}
+
+	txnCtx := dbtxn.Begin(&ctx)
+	defer func() {
+		if err := txnCtx.Commit(); err != nil {
+			log.WithError(err).Error("Transaction commit failed")
+		}
+	}()
+
+	listing, err := s.ListingRepo.GetByIDForUpdate(ctx, id)
+	if err != nil {
+		log.WithField("listingID", id).WithError(err).Error("Failed to get funding listing")
+		txnCtx.AppendError(err)
+		return errors.New("99999")
+	}
+	if listing == nil {
+		log.WithField("listingID", id).Warn("Funding listing not found")
+		txnCtx.AppendError(errors.New("funding listing not found"))
+		return errors.New("10001")
+	}
+	if listing.SellerID != request.LenderID {
+		log.WithFields(log.Fields{
+			"listingID": id,
+			"lenderID":  request.LenderID,
+		}).Warn("Funding listing does not belong to the lender")
+		txnCtx.AppendError(errors.New("funding listing does not belong to the lender"))
+		return errors.New("10003")
+	}
+	if listing.Status != enum.FundingListingOpen {
+		log.WithFields(log.Fields{
+			"listingID": id,
+			"status":    listing.Status,
+		}).Warn("Funding listing is not open")
+		txnCtx.AppendError(errors.New("funding listing is not open"))
+		return errors.New("10010")
+	}
+
+	listing.Status = enum.FundingListingCancelled
+	err = s.ListingRepo.Update(ctx, listing)
+	if err != nil {
+		log.WithField("listingID", id).WithError(err).Error("Failed to update funding listing")
+		txnCtx.AppendError(err)
+		return errors.New("99999")
+	}
+
+	log.WithField("listingID", id).Info("Funding listing cancelled successfully")
+	return nil
+}
+
+func (s *FundingMarketSvcImpl) GetListing(ctx context.Context, id int64) (*dto.FundingListingResponseDTO, error) {
+	listing, err := s.ListingRepo.GetByID(ctx, id)
+	if err != nil {
+		log.WithField("listingID", id).WithError(err).Error("Failed to get funding listing")
+		return nil, errors.New("99999")
+	}
+	if listing == nil {
+		log.WithField("listingID", id).Warn("Funding listing not found")
+		return nil, errors.New("10001")
+	}
+
+	return s.toListingResponseDTO(*listing)
+}
+
+func (s *FundingMarketSvcImpl) GetListings(ctx context.Context, request repo.FundingListingRequest) ([]dto.FundingListingResponseDTO, error) {
+	listings, err := s.ListingRepo.GetAll(ctx, request)
+	if err != nil {
+		log.WithError(err).Error("Failed to get funding listings")
+		return nil, errors.New("99999")
+	}
+
+	var listingDTOs []dto.FundingListingResponseDTO
+	for _, listing := range listings {
+		listingRes, err := s.toListingResponseDTO(listing)
+		if err != nil {
+			return nil, errors.New("99999")
+		}
+		listingDTOs = append(listingDTOs, *listingRes)
+	}
+
+	return listingDTOs, nil
+}
+
+// Buy transfers the listed principal to the buyer. The seller keeps what is left of the funding, a
+// funding with no outstanding principal left becomes transferred. The buyer funding takes the rate
+// and service fee percentage of the seller, the tax is estimated with the tax profile of the buyer.
+// It fails with 10010 when the listing is no longer open or the seller no longer has the principal,
+// and with 10004 when the buyer can not pay the price.
+func (s *FundingMarketSvcImpl) Buy(ctx context.Context, listingID int64, request *dto.FundingPurchaseRequestDTO) (*dto.FundingTransferResponseDTO, error) {
+	log.WithFields(log.Fields{
+		"listingID": listingID,
+		"lenderID":  request.LenderID,
+	}).Info("Buying funding listing")
+
+	err := s.Validator.ValidateUpdate(request)
+	if err != nil {
+		log.WithField("listingID", listingID).Errorf("Validation failed: %s", err)
+		return nil, err
+	}
+
+	txnCtx := dbtxn.Begin(&ctx)
+	defer func() {
+		if err := txnCtx.Commit(); err != nil {
+			log.WithError(err).Error("Transaction commit failed")
+		}
+	}()
+
+	listing, err := s.ListingRepo.GetByIDForUpdate(ctx, listingID)
+	if err != nil {
+		log.WithField("listingID", listingID).WithError(err).Error("Failed to get funding listing")
+		txnCtx.AppendError(err)
+		return nil, errors.New("99999")
+	}
+	if listing == nil {
+		log.WithField("listingID", listingID).Warn("Funding listing not found")
+		txnCtx.AppendError(errors.New("funding listing not found"))
+		return nil, errors.New("10001")
+	}
+	if listing.Status != enum.FundingListingOpen {
+		log.WithFields(log.Fields{
+			"listingID": listingID,
+			"status":    listing.Status,
+		}).Warn("Funding listing is not open")
+		txnCtx.AppendError(errors.New("funding listing is not open"))
+		return nil, errors.New("10010")
+	}
+	if listing.SellerID == request.LenderID {
+		log.WithField("listingID", listingID).Warn("Lender can not buy its own funding listing")
+		txnCtx.AppendError(errors.New("lender can not buy its own funding listing"))
+		return nil, errors.New("10003")
+	}
+
+	// lock the loan then the seller funding, the same order as a repayment, a cancellation and the expiry
+	// job, so a repayment allocation can not run on the seller funding until the purchase is committed
+	loan, err := s.LoanRepo.GetByIDForUpdate(ctx, listing.LoanID)
+	if err != nil || loan == nil {
+		log.WithField("loanID", listing.LoanID).WithError(err).Error("Failed to lock loan")
+		txnCtx.AppendError(errors.New("loan not found"))
+		return nil, errors.New("99999")
+	}
+	sellerFunding, err := s.LoanFundingRepo.GetByIDForUpdate(ctx, listing.LoanFundingID)
+	if err != nil || sellerFunding == nil {
+		log.WithField("loanFundingID", listing.LoanFundingID).WithError(err).Error("Failed to get loan funding")
+		txnCtx.AppendError(errors.New("loan funding not found"))
+		return nil, errors.New("99999")
+	}
+	outstanding := outstandingPrincipal(*sellerFunding)
+	if sellerFunding.Status != enum.LoanFundingOngoing || outstanding < listing.PrincipalAmount {
+		log.WithFields(log.Fields{
+			"listingID":   listingID,
+			"status":      sellerFunding.Status,
+			"outstanding": outstanding,
+		}).Warn("Listed principal is no longer available")
+		txnCtx.AppendError(errors.New("listed principal is no longer available"))
+		return nil, errors.New("10010")
+	}
+
+	// the buyer has to be allowed to fund the loan as much as a lender funding it directly
+	buyer, err := s.LenderSvc.GetEligible(ctx, request.LenderID, loan.LoanGrade)
+	if err != nil {
+		txnCtx.AppendError(err)
+		return nil, err
+	}
+
+	// the buyer takes the share of what is still to be paid on the funding, the seller keeps the rest
+	principal := listing.PrincipalAmount
+	interest := (sellerFunding.Interest - sellerFunding.InterestPaid).MulRat(principal.Sen(), outstanding.Sen(), money.HalfUp)
+	serviceFee := (sellerFunding.ServiceFee - sellerFunding.ServiceFeePaid).MulRat(principal.Sen(), outstanding.Sen(), money.HalfUp)
+	withholdingTax := (sellerFunding.WithholdingTax - sellerFunding.WithholdingTaxPaid).MulRat(principal.Sen(), outstanding.Sen(), money.HalfUp)
+
+	now := time.Now()
+	buyerFunding := repo.LoanFunding{
+		LoanOrderNumber:      utils.GenerateAlphanumericCode(10),
+		OrderNumber:          listing.ListingCode,
+		LoanID:               listing.LoanID,
+		LenderID:             request.LenderID,
+		LenderEmail:          buyer.Email,
+		InvestmentAmount:     principal,
+		Rate:                 sellerFunding.Rate,
+		Interest:             interest,
+		ServiceFeePercentage: sellerFunding.ServiceFeePercentage,
+		ServiceFee:           serviceFee,
+		TransferredFromID:    &sellerFunding.ID,
+		InvestmentDate:       now,
+		InvestedAt:           &now,
+		Status:               enum.LoanFundingOngoing,
+		CreatedAt:            now,
+		UpdatedAt:            now,
+	}
+
+	err = s.LimitSvc.ValidateConcentration(ctx, loan, &buyerFunding)
+	if err != nil {
+		txnCtx.AppendError(err)
+		return nil, err
+	}
+
+	withholding, err := s.TaxSvc.Calculate(ctx, buyerFunding.LenderID, buyerFunding.Interest)
+	if err != nil {
+		log.WithField("lenderID", buyerFunding.LenderID).WithError(err).Error("Failed to calculate withholding tax")
+		txnCtx.AppendError(err)
+		return nil, errors.New("99999")
+	}
+	buyerFunding.WithholdingTax = withholding.TaxAmount
+	buyerFunding.ROI = buyerFunding.InvestmentAmount + buyerFunding.Interest - buyerFunding.ServiceFee - buyerFunding.WithholdingTax
+
+	buyerFunding.ID, err = s.LoanFundingRepo.Create(ctx, &buyerFunding)
+	if err != nil {
+		log.WithField("listingID", listingID).WithError(err).Error("Failed to create buyer loan funding")
+		txnCtx.AppendError(err)
+		return nil, errors.New("99999")
+	}
+
+	sellerFunding.TransferredAmount += principal
+	sellerFunding.Interest -= interest
+	sellerFunding.ServiceFee -= serviceFee
+	sellerFunding.WithholdingTax -= withholdingTax
+	sellerFunding.ROI = sellerFunding.InvestmentAmount - sellerFunding.TransferredAmount + sellerFunding.Interest -
+		sellerFunding.ServiceFee - sellerFunding.WithholdingTax
+	if outstandingPrincipal(*sellerFunding).IsZero() {
+		sellerFunding.Status = enum.LoanFundingTransferred
+	}
+	sellerFunding.UpdatedAt = now
+	err = s.LoanFundingRepo.Update(ctx, sellerFunding)
+	if err != nil {
+		log.WithField("loanFundingID", sellerFunding.ID).WithError(err).Error("Failed to update seller loan funding")
+		txnCtx.AppendError(err)
+		return nil, errors.New("99999")
+	}
+
+	transfer := repo.FundingTransfer{
+		TransferCode:    utils.GenerateAlphanumericCode(10),
+		ListingID:       listing.ID,
+		LoanID:          listing.LoanID,
+		SellerFundingID: sellerFunding.ID,
+		BuyerFundingID:  buyerFunding.ID,
+		SellerID:        listing.SellerID,
+		BuyerID:         request.LenderID,
+		PrincipalAmount: principal,
+		InterestAmount:  interest,
+		Price:           listing.Price,
+		Premium:         listing.Price - principal,
+		TransferredAt:   now,
+	}
+	transfer.ID, err = s.TransferRepo.Create(ctx, &transfer)
+	if err != nil {
+		log.WithField("listingID", listingID).WithError(err).Error("Failed to create funding transfer")
+		txnCtx.AppendError(err)
+		return nil, errors.New("99999")
+	}
+
+	err = s.WalletSvc.SettleTransfer(ctx, &transfer)
+	if err != nil {
+		txnCtx.AppendError(err)
+		if err.Error() == "10004" {
+			return nil, err
+		}
+		return nil, errors.New("99999")
+	}
+
+	listing.Status = enum.FundingListingSold
+	err = s.ListingRepo.Update(ctx, listing)
+	if err != nil {
+		log.WithField("listingID", listingID).WithError(err).Error("Failed to update funding listing")
+		txnCtx.AppendError(err)
+		return nil, errors.New("99999")
+	}
+
+	transferRes, err := s.toTransferResponseDTO(transfer)
+	if err != nil {
+		txnCtx.AppendError(err)
+		return nil, errors.New("99999")
+	}
+
+	log.WithFields(log.Fields{
+		"listingID":    listingID,
+		"transferCode": transfer.TransferCode,
+		"premium":      transfer.Premium,
+	}).Info("Funding listing bought successfully")
+	return transferRes, nil
+}
+
+// GetTransfersByLenderID returns the transfers the lender sold or bought
+func (s *FundingMarketSvcImpl) GetTransfersByLenderID(ctx context.Context, lenderID int64) ([]dto.FundingTransferResponseDTO, error) {
+	transfers, err := s.TransferRepo.GetByLenderID(ctx, lenderID)
+	if err != nil {
+		log.WithField("lenderID", lenderID).WithError(err).Error("Failed to get funding transfers")
+		return nil, errors.New("99999")
+	}
+
+	var transferDTOs []dto.FundingTransferResponseDTO
+	for _, transfer := range transfers {
+		transferRes, err := s.toTransferResponseDTO(transfer)
+		if err != nil {
+			return nil, errors.New("99999")
+		}
+		transferDTOs = append(transferDTOs, *transferRes)
+	}
+
+	return transferDTOs, nil
+}
+
+func (s *FundingMarketSvcImpl) toListingResponseDTO(listing repo.FundingListing) (*dto.FundingListingResponseDTO, error) {
+	var listingRes dto.FundingListingResponseDTO
+	err := mapstructure.Decode(listing, &listingRes)
+	if err != nil {
+		log.WithField("listingID", listing.ID).WithError(err).Error("Failed to map funding listing to DTO")
+		return nil, err
+	}
+	listingRes.Premium = listing.Price - listing.PrincipalAmount
+	listingRes.CreatedAt = listing.CreatedAt
+	listingRes.UpdatedAt = listing.UpdatedAt
+
+	return &listingRes, nil
+}
+
+func (s *FundingMarketSvcImpl) toTransferResponseDTO(transfer repo.FundingTransfer) (*dto.FundingTransferResponseDTO, error) {
+	var transferRes dto.FundingTransferResponseDTO
+	err := mapstructure.Decode(transfer, &transferRes)
+	if err != nil {
+		log.WithField("fundingTransferID", transfer.ID).WithError(err).Error("Failed to map funding transfer to DTO")
+		return nil, err
+	}
+	transferRes.TransferredAt = transfer.TransferredAt
+
+	return &transferRes, nil
+}
@@ -0,0 +1,158 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"github.com/mitchellh/mapstructure"
+	log "github.com/sirupsen/logrus"
+	"github.com/test/loan-service/internal/dto"
+	"github.com/test/loan-service/internal/enum"
+	"github.com/test/loan-service/internal/money"
+	repo "github.com/test/loan-service/internal/repository"
+	"github.com/test/loan-service/internal/service/validator"
+	"go.uber.org/dig"
+	"math"
+	"time"
+)
+
+//go:generate mockgen -source=$GOFILE -destination=$PROJ/internal/generated/mock/mock_$GOPACKAGE/$GOFILE
+
+type (
+	FundingPolicySvc interface {
+		Update(ctx context.Context, loanType enum.LoanType, request *dto.FundingPolicyRequestDTO) error
+		GetAll(ctx context.Context) ([]dto.FundingPolicyResponseDTO, error)
+		IsPartialDisbursementAllowed(ctx context.Context, loan *repo.Loan) (bool, error)
+		CoolingOffPeriod(ctx context.Context, loanType enum.LoanType) (time.Duration, error)
+	}
+
+	FundingPolicySvcImpl struct {
+		dig.In
+		Repo      repo.FundingPolicyRepo
+		Validator validator.FundingPolicyValidatorImpl
+	}
+)
+
+func NewFundingPolicySvc(impl FundingPolicySvcImpl) FundingPolicySvc {
+	return &impl
+}
+
+func (s *FundingPolicySvcImpl) Update(ctx context.Context, loanType enum.LoanType, request *dto.FundingPolicyRequestDTO) error {
+	log.WithFields(log.Fields{
+		"loanType":               loanType,
+		"minFundedPercentage":    request.MinFundedPercentage,
+		"requireBorrowerConsent": request.RequireBorrowerConsent,
+		"coolingOffHours":        request.CoolingOffHours,
+	}).Info("Updating funding policy")
+
+	if !loanType.IsValid() {
+		log.WithField("loanType", loanType).Error("Invalid LoanType")
+		return errors.New("10002")
+	}
+
+	err := s.Validator.ValidateUpdate(request)
+	if err != nil {
+		log.WithField("loanType", loanType).Errorf("Validation failed: %s", err)
+		return err
+	}
+
+	policy, err := s.Repo.GetByLoanType(ctx, loanType)
+	if err != nil {
+		log.WithField("loanType", loanType).WithError(err).Error("Failed to get funding policy")
+		return errors.New("99999")
+	}
+	if policy == nil {
+		log.WithField("loanType", loanType).Warn("Funding policy not found")
+		return errors.New("10001")
+	}
+
+	policy.MinFundedPercentage = request.MinFundedPercentage
+	policy.RequireBorrowerConsent = request.RequireBorrowerConsent
+	policy.CoolingOffHours = request.CoolingOffHours
+	err = s.Repo.Update(ctx, policy)
+	if err != nil {
+		log.WithField("loanType", loanType).WithError(err).Error("Failed to update funding policy")
+		return errors.New("99999")
+	}
+
+	log.WithField("loanType", loanType).Info("Funding policy updated successfully")
+	return nil
+}
+
+func (s *FundingPolicySvcImpl) GetAll(ctx context.Context) ([]dto.FundingPolicyResponseDTO, error) {
+	policies, err := s.Repo.GetAll(ctx)
+	if err != nil {
+		log.WithError(err).Error("Failed to get funding policies")
+		return nil, errors.New("99999")
+	}
+
+	policyDTOs := []dto.FundingPolicyResponseDTO{}
+	for _, policy := range policies {
+		var policyRes dto.FundingPolicyResponseDTO
+		err = mapstructure.Decode(policy, &policyRes)
+		if err != nil {
+			log.WithField("loanType", policy.LoanType).WithError(err).Error("Failed to map funding policy to DTO")
+			return nil, errors.New("99999")
+		}
+		policyRes.CreatedAt = policy.CreatedAt
+		policyRes.UpdatedAt = policy.UpdatedAt
+		policyRes.DeletedAt = policy.DeletedAt
+
+		policyDTOs = append(policyDTOs, policyRes)
+	}
+
+	return policyDTOs, nil
+}
+
+// IsPartialDisbursementAllowed evaluates the funding policy of the loan type against a loan that
+// reached its funding deadline without being fully funded.
+func (s *FundingPolicySvcImpl) IsPartialDisbursementAllowed(ctx context.Context, loan *repo.Loan) (bool, error) {
+	if loan.TotalInvestedAmount <= 0 || loan.RequestAmount <= 0 {
+		return false, nil
+	}
+
+	policy, err := s.Repo.GetByLoanType(ctx, loan.LoanType)
+	if err != nil {
+		log.WithField("loanType", loan.LoanType).WithError(err).Error("Failed to get funding policy")
+		return false, err
+	}
+	if policy == nil {
+		log.WithField("loanType", loan.LoanType).Info("No funding policy for loan type, partial disbursement not allowed")
+		return false, nil
+	}
+
+	// the minimum is compared in basis points of the request amount so the check stays exact
+	minFundedBasisPoints := int64(math.Round(policy.MinFundedPercentage * 100))
+	minFundedAmount := loan.RequestAmount.MulRat(minFundedBasisPoints, 100*100, money.Up)
+	if loan.TotalInvestedAmount < minFundedAmount {
+		log.WithFields(log.Fields{
+			"loanID":              loan.ID,
+			"totalInvestedAmount": loan.TotalInvestedAmount,
+			"minFundedAmount":     minFundedAmount,
+			"minFundedPercentage": policy.MinFundedPercentage,
+		}).Info("Loan funded below policy minimum")
+		return false, nil
+	}
+
+	if policy.RequireBorrowerConsent && !loan.PartialFundingConsent {
+		log.WithField("loanID", loan.ID).Info("Partial disbursement needs borrower consent")
+		return false, nil
+	}
+
+	return true, nil
+}
+
+// CoolingOffPeriod returns how long after investing a lender can still cancel a funding of the loan
+// type, zero when the loan type has no policy
+func (s *FundingPolicySvcImpl) CoolingOffPeriod(ctx context.Context, loanType enum.LoanType) (time.Duration, error) {
+	policy, err := s.Repo.GetByLoanType(ctx, loanType)
+	if err != nil {
+		log.WithField("loanType", loanType).WithError(err).Error("Failed to get funding policy")
+		return 0, err
+	}
+	if policy == nil {
+		log.WithField("loanType", loanType).Info("No funding policy for loan type, no cooling-off period")
+		return 0, nil
+	}
+
+	return time.Duration(policy.CoolingOffHours) * time.Hour, nil
+}
@@ -0,0 +1,219 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"github.com/mitchellh/mapstructure"
+	log "github.com/sirupsen/logrus"
+	"github.com/test/loan-service/internal/dto"
+	"github.com/test/loan-service/internal/enum"
+	"github.com/test/loan-service/internal/money"
+	repo "github.com/test/loan-service/internal/repository"
+	"github.com/test/loan-service/internal/service/validator"
+	"github.com/test/loan-service/internal/utils"
+	"go.uber.org/dig"
+)
+
+//go:generate mockgen -source=$GOFILE -destination=$PROJ/internal/generated/mock/mock_$GOPACKAGE/$GOFILE
+
+type (
+	// InvestmentLimitSvc holds the platform rules on the amount a lender may invest. The ticket rules
+	// apply to a single funding, the concentration rules to the fundings a lender still has open, pending
+	// fundings included. Without a configured limit every amount is accepted.
+	InvestmentLimitSvc interface {
+		Get(ctx context.Context) (*dto.InvestmentLimitResponseDTO, error)
+		Update(ctx context.Context, request *dto.InvestmentLimitRequestDTO) (*dto.InvestmentLimitResponseDTO, error)
+		ValidateTicket(ctx context.Context, loan *repo.Loan, amount money.Amount) error
+		ValidateConcentration(ctx context.Context, loan *repo.Loan, funding *repo.LoanFunding) error
+	}
+
+	InvestmentLimitSvcImpl struct {
+		dig.In
+		Repo            repo.InvestmentLimitRepo
+		LoanFundingRepo repo.LoanFundingRepo
+		Validator       validator.InvestmentLimitValidatorImpl
+	}
+)
+
+func NewInvestmentLimitSvc(impl InvestmentLimitSvcImpl) InvestmentLimitSvc {
+	return &impl
+}
+
+func (s *InvestmentLimitSvcImpl) Get(ctx context.Context) (*dto.InvestmentLimitResponseDTO, error) {
+	limit, err := s.Repo.Get(ctx)
+	if err != nil {
+		log.WithError(err).Error("Failed to get investment limit")
+		return nil, errors.New("99999")
+	}
+	if limit == nil {
+		log.Warn("Investment limit not found")
+		return nil, errors.New("10001")
+	}
+
+	return s.toResponseDTO(*limit)
+}
+
+func (s *InvestmentLimitSvcImpl) Update(ctx context.Context, request *dto.InvestmentLimitRequestDTO) (*dto.InvestmentLimitResponseDTO, error) {
+	log.WithFields(log.Fields{
+		"minTicketAmount":        request.MinTicketAmount,
+		"ticketIncrement":        request.TicketIncrement,
+		"maxLoanSharePercentage": request.MaxLoanSharePercentage,
+		"maxLenderExposure":      request.MaxLenderExposure,
+	}).Info("Updating investment limit")
+
+	err := s.Validator.ValidateUpdate(request)
+	if err != nil {
+		log.Errorf("Validation failed: %s", err)
+		return nil, err
+	}
+
+	limit, err := s.Repo.Get(ctx)
+	if err != nil {
+		log.WithError(err).Error("Failed to get investment limit")
+		return nil, errors.New("99999")
+	}
+	if limit == nil {
+		log.Warn("Investment limit not found")
+		return nil, errors.New("10001")
+	}
+
+	limit.MinTicketAmount = request.MinTicketAmount
+	limit.TicketIncrement = request.TicketIncrement
+	limit.MaxLoanSharePercentage = request.MaxLoanSharePercentage
+	limit.MaxLenderExposure = request.MaxLenderExposure
+	err = s.Repo.Update(ctx, limit)
+	if err != nil {
+		log.WithError(err).Error("Failed to update investment limit")
+		return nil, errors.New("99999")
+	}
+
+	log.Info("Investment limit updated successfully")
+	return s.Get(ctx)
+}
+
+// ValidateTicket checks the amount of a new funding against the minimum ticket (10006) and the
+// ticket increment (10007). The amount that completes the loan is always accepted, otherwise a loan
+// with less than a ticket left could never be fully funded.
+func (s *InvestmentLimitSvcImpl) ValidateTicket(ctx context.Context, loan *repo.Loan, amount money.Amount) error {
+	limit, err := s.Repo.Get(ctx)
+	if err != nil {
+		log.WithField("loanID", loan.ID).WithError(err).Error("Failed to get investment limit")
+		return errors.New("99999")
+	}
+	if limit == nil {
+		log.WithField("loanID", loan.ID).Info("No investment limit configured, ticket not checked")
+		return nil
+	}
+
+	if amount == loan.RequestAmount-loan.TotalInvestedAmount {
+		return nil
+	}
+
+	if amount < limit.MinTicketAmount {
+		log.WithFields(log.Fields{
+			"loanID":          loan.ID,
+			"amount":          amount,
+			"minTicketAmount": limit.MinTicketAmount,
+		}).Warn("Investment amount below minimum ticket")
+		return errors.New("10006")
+	}
+
+	if limit.TicketIncrement.IsPositive() && amount.Sen()%limit.TicketIncrement.Sen() != 0 {
+		log.WithFields(log.Fields{
+			"loanID":          loan.ID,
+			"amount":          amount,
+			"ticketIncrement": limit.TicketIncrement,
+		}).Warn("Investment amount is not a multiple of the ticket increment")
+		return errors.New("10007")
+	}
+
+	return nil
+}
+
+// ValidateConcentration checks that the funding keeps the share of the lender in the loan (10008) and
+// the outstanding principal of the lender over all loans (10009) within the limits. The funding
+// itself is only counted once, whether or not it is stored already. Principal sold on the secondary
+// market counts for the buyer only.
+func (s *InvestmentLimitSvcImpl) ValidateConcentration(ctx context.Context, loan *repo.Loan, funding *repo.LoanFunding) error {
+	limit, err := s.Repo.Get(ctx)
+	if err != nil {
+		log.WithField("loanID", loan.ID).WithError(err).Error("Failed to get investment limit")
+		return errors.New("99999")
+	}
+	if limit == nil {
+		log.WithField("loanID", loan.ID).Info("No investment limit configured, concentration not checked")
+		return nil
+	}
+
+	loanFundings, err := s.LoanFundingRepo.GetByLoanID(ctx, loan.ID)
+	if err != nil {
+		log.WithField("loanID", loan.ID).WithError(err).Error("Failed to get loan fundings")
+		return errors.New("99999")
+	}
+
+	share := funding.InvestmentAmount
+	for _, loanFunding := range loanFundings {
+		if loanFunding.LenderID == funding.LenderID && loanFunding.ID != funding.ID && isOpenFunding(loanFunding.Status) {
+			share += loanFunding.InvestmentAmount - loanFunding.TransferredAmount
+		}
+	}
+	maxShare := utils.CalculatePercentage(loan.RequestAmount, limit.MaxLoanSharePercentage)
+	if share > maxShare {
+		log.WithFields(log.Fields{
+			"loanID":   loan.ID,
+			"lenderID": funding.LenderID,
+			"share":    share,
+			"maxShare": maxShare,
+		}).Warn("Lender share of the loan exceeds the limit")
+		return errors.New("10008")
+	}
+
+	if !limit.MaxLenderExposure.IsPositive() {
+		return nil
+	}
+
+	lenderFundings, err := s.LoanFundingRepo.GetByLenderID(ctx, funding.LenderID)
+	if err != nil {
+		log.WithField("lenderID", funding.LenderID).WithError(err).Error("Failed to get lender fundings")
+		return errors.New("99999")
+	}
+
+	exposure := funding.InvestmentAmount
+	for _, lenderFunding := range lenderFundings {
+		if lenderFunding.ID != funding.ID && isOpenFunding(lenderFunding.Status) {
+			exposure += lenderFunding.InvestmentAmount - lenderFunding.CapitalAmountPaid - lenderFunding.TransferredAmount
+		}
+	}
+	if exposure > limit.MaxLenderExposure {
+		log.WithFields(log.Fields{
+			"lenderID":          funding.LenderID,
+			"exposure":          exposure,
+			"maxLenderExposure": limit.MaxLenderExposure,
+		}).Warn("Lender exposure exceeds the limit")
+		return errors.New("10009")
+	}
+
+	return nil
+}
+
+func (s *InvestmentLimitSvcImpl) toResponseDTO(limit repo.InvestmentLimit) (*dto.InvestmentLimitResponseDTO, error) {
+	var limitRes dto.InvestmentLimitResponseDTO
+	err := mapstructure.Decode(limit, &limitRes)
+	if err != nil {
+		log.WithField("investmentLimitID", limit.ID).WithError(err).Error("Failed to map investment limit to DTO")
+		return nil, errors.New("99999")
+	}
+	limitRes.CreatedAt = limit.CreatedAt
+	limitRes.UpdatedAt = limit.UpdatedAt
+
+	return &limitRes, nil
+}
+
+// isOpenFunding reports whether the principal of a funding with the status is still at risk
+func isOpenFunding(status enum.LoanFundingStatus) bool {
+	switch status {
+	case enum.LoanFundingPending, enum.LoanFundingInvested, enum.LoanFundingOngoing:
+		return true
+	}
+	return false
+}
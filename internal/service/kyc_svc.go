@@ -0,0 +1,179 @@
+package service
+
+import (
+	"context"
+	"errors"
+	log "github.com/sirupsen/logrus"
+	"github.com/test/loan-service/internal/dto"
+	"github.com/test/loan-service/internal/enum"
+	repo "github.com/test/loan-service/internal/repository"
+	"github.com/test/loan-service/internal/service/validator"
+	"go.uber.org/dig"
+	"time"
+)
+
+//go:generate mockgen -source=$GOFILE -destination=$PROJ/internal/generated/mock/mock_$GOPACKAGE/$GOFILE
+
+const (
+	// kycNationalIDDocument is the identity document of an individual
+	kycNationalIDDocument = "national_id"
+	// kycBusinessRegistrationDocument is the identity document of a business or an institution
+	kycBusinessRegistrationDocument = "business_registration"
+)
+
+// kycDocumentNames are the names of the KYC documents in the error of a submission missing them
+var kycDocumentNames = map[string]string{
+	kycNationalIDDocument:           "National ID",
+	kycBusinessRegistrationDocument: "Business Registration",
+}
+
+type (
+	// KycSvc moves the KYC of a borrower or lender through its statuses and keeps every change in the
+	// KYC history. The owner is locked and saved by the caller in the same transaction, which also
+	// decides the documents the owner has to upload.
+	KycSvc interface {
+		Submit(ctx context.Context, ownerType enum.DocumentEntity, ownerID int64, kyc *repo.Kyc, requiredDocuments []string) error
+		Review(ctx context.Context, ownerType enum.DocumentEntity, ownerID int64, kyc *repo.Kyc, request *dto.KycReviewRequestDTO) error
+		ResetIdentity(ctx context.Context, ownerType enum.DocumentEntity, ownerID int64, kyc *repo.Kyc) error
+		GetHistory(ctx context.Context, ownerType enum.DocumentEntity, ownerID int64) ([]dto.KycHistoryResponseDTO, error)
+	}
+
+	KycSvcImpl struct {
+		dig.In
+		HistoryRepo  repo.KycHistoryRepo
+		DocumentRepo repo.DocumentRepo
+		Validator    validator.KycReviewValidatorImpl
+	}
+)
+
+func NewKycSvc(impl KycSvcImpl) KycSvc {
+	return &impl
+}
+
+// Submit sends the KYC for review, every required document has to be uploaded for the owner first and
+// the error names every missing one
+func (s *KycSvcImpl) Submit(ctx context.Context, ownerType enum.DocumentEntity, ownerID int64, kyc *repo.Kyc, requiredDocuments []string) error {
+	if !s.Validator.ValidateTransitionStatus(kyc.KycStatus, enum.KycPending) {
+		log.WithFields(log.Fields{
+			"ownerType": ownerType,
+			"ownerID":   ownerID,
+			"kycStatus": kyc.KycStatus,
+		}).Warn("KYC can not be submitted")
+		return errors.New("10003")
+	}
+
+	documents, err := s.DocumentRepo.GetByEntity(ctx, ownerType, ownerID)
+	if err != nil {
+		log.WithFields(log.Fields{
+			"ownerType": ownerType,
+			"ownerID":   ownerID,
+		}).WithError(err).Error("Failed to get KYC documents")
+		return errors.New("99999")
+	}
+
+	present := make(map[string]bool, len(documents))
+	for _, document := range documents {
+		present[document.DocumentType] = true
+	}
+
+	var missing []string
+	for _, documentType := range requiredDocuments {
+		if !present[documentType] {
+			missing = append(missing, kycDocumentNames[documentType])
+		}
+	}
+	if len(missing) > 0 {
+		log.WithFields(log.Fields{
+			"ownerType": ownerType,
+			"ownerID":   ownerID,
+		}).Warnf("KYC documents are missing: %v", missing)
+		return &dto.DetailError{Code: "10015", Details: missing}
+	}
+
+	return s.changeStatus(ctx, ownerType, ownerID, kyc, enum.KycPending, nil, nil)
+}
+
+// Review records the decision of the staff on a submitted KYC
+func (s *KycSvcImpl) Review(ctx context.Context, ownerType enum.DocumentEntity, ownerID int64, kyc *repo.Kyc, request *dto.KycReviewRequestDTO) error {
+	err := s.Validator.ValidateCreate(request)
+	if err != nil {
+		log.WithField("ownerID", ownerID).Errorf("Validation failed: %s", err)
+		return err
+	}
+
+	if !s.Validator.ValidateTransitionStatus(kyc.KycStatus, request.KycStatus) {
+		log.WithFields(log.Fields{
+			"ownerType":     ownerType,
+			"ownerID":       ownerID,
+			"currentStatus": kyc.KycStatus,
+			"newStatus":     request.KycStatus,
+		}).Warn("Invalid KYC status transition")
+		return errors.New("10003")
+	}
+
+	return s.changeStatus(ctx, ownerType, ownerID, kyc, request.KycStatus, &request.StaffID, request.Note)
+}
+
+// ResetIdentity sets the KYC back to unverified after the identity of the owner changed, the new
+// identity has to be submitted again
+func (s *KycSvcImpl) ResetIdentity(ctx context.Context, ownerType enum.DocumentEntity, ownerID int64, kyc *repo.Kyc) error {
+	if kyc.KycStatus == enum.KycUnverified {
+		return nil
+	}
+
+	note := "Identity changed"
+	return s.changeStatus(ctx, ownerType, ownerID, kyc, enum.KycUnverified, nil, &note)
+}
+
+// GetHistory lists every KYC status change of the owner, oldest first
+func (s *KycSvcImpl) GetHistory(ctx context.Context, ownerType enum.DocumentEntity, ownerID int64) ([]dto.KycHistoryResponseDTO, error) {
+	histories, err := s.HistoryRepo.GetByOwnerID(ctx, ownerType, ownerID)
+	if err != nil {
+		log.WithFields(log.Fields{
+			"ownerType": ownerType,
+			"ownerID":   ownerID,
+		}).WithError(err).Error("Failed to get KYC history")
+		return nil, errors.New("99999")
+	}
+
+	historyDTOs := []dto.KycHistoryResponseDTO{}
+	for _, history := range histories {
+		historyDTOs = append(historyDTOs, dto.KycHistoryResponseDTO{
+			ID:         history.ID,
+			FromStatus: history.FromStatus,
+			ToStatus:   history.ToStatus,
+			StaffID:    history.StaffID,
+			Note:       history.Note,
+			CreatedAt:  history.CreatedAt,
+		})
+	}
+
+	return historyDTOs, nil
+}
+
+// changeStatus sets the KYC status and records the change in the KYC history of the owner
+func (s *KycSvcImpl) changeStatus(ctx context.Context, ownerType enum.DocumentEntity, ownerID int64, kyc *repo.Kyc, status enum.KycStatus, staffID *int64, note *string) error {
+	_, err := s.HistoryRepo.Create(ctx, ownerType, &repo.KycHistory{
+		OwnerID:    ownerID,
+		FromStatus: kyc.KycStatus,
+		ToStatus:   status,
+		StaffID:    staffID,
+		Note:       note,
+	})
+	if err != nil {
+		log.WithFields(log.Fields{
+			"ownerType": ownerType,
+			"ownerID":   ownerID,
+		}).WithError(err).Error("Failed to record KYC history")
+		return errors.New("99999")
+	}
+
+	kyc.KycStatus = status
+	kyc.KycVerifiedAt = nil
+	if status == enum.KycVerified {
+		now := time.Now()
+		kyc.KycVerifiedAt = &now
+	}
+
+	return nil
+}
@@ -0,0 +1,409 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	log "github.com/sirupsen/logrus"
+	"github.com/test/loan-service/internal/dto"
+	"github.com/test/loan-service/internal/enum"
+	"github.com/test/loan-service/internal/money"
+	repo "github.com/test/loan-service/internal/repository"
+	"github.com/test/loan-service/internal/service/models"
+	"github.com/test/loan-service/internal/service/validator"
+	"github.com/test/loan-service/internal/utils"
+	"github.com/typical-go/typical-rest-server/pkg/dbtxn"
+	"go.uber.org/dig"
+	"time"
+)
+
+//go:generate mockgen -source=$GOFILE -destination=$PROJ/internal/generated/mock/mock_$GOPACKAGE/$GOFILE
+
+type (
+	// LedgerSvc records every money movement of the platform as a balanced journal entry. The Record
+	// methods must be called with the context of the transaction that changes the business records so
+	// the books never drift from them.
+	LedgerSvc interface {
+		Post(ctx context.Context, request models.LedgerEntryRequest) (int64, error)
+		RecordFundingInvested(ctx context.Context, funding *repo.LoanFunding) error
+		RecordFundingRefunded(ctx context.Context, funding *repo.LoanFunding) error
+		RecordDisbursement(ctx context.Context, loan *repo.Loan, disbursement *repo.LoanDisbursement) error
+		RecordRepayment(ctx context.Context, repayment *repo.LoanRepayment, allocations []repo.RepaymentAllocation) error
+		RecordWalletTopUp(ctx context.Context, transaction *repo.WalletTransaction) error
+		RecordWithdrawal(ctx context.Context, withdrawal *repo.WalletWithdrawal) error
+		RecordFundingTransferred(ctx context.Context, transfer *repo.FundingTransfer) error
+		GetAccounts(ctx context.Context, request repo.LedgerAccountRequest) ([]dto.LedgerAccountResponseDTO, error)
+		GetAccount(ctx context.Context, accountID int64) (*dto.LedgerAccountResponseDTO, error)
+		GetStatementPage(ctx context.Context, request models.LedgerStatementRequest) ([]dto.LedgerStatementLineDTO, int, error)
+	}
+
+	LedgerSvcImpl struct {
+		dig.In
+		AccountRepo repo.LedgerAccountRepo
+		EntryRepo   repo.LedgerEntryRepo
+		PostingRepo repo.LedgerPostingRepo
+		Validator   validator.LedgerValidatorImpl
+	}
+)
+
+func NewLedgerSvc(impl LedgerSvcImpl) LedgerSvc {
+	return &impl
+}
+
+// Post writes a balanced journal entry. An event that has already been posted is not posted twice,
+// the id of the existing entry is returned instead.
+func (s *LedgerSvcImpl) Post(ctx context.Context, request models.LedgerEntryRequest) (int64, error) {
+	// entry and postings must be committed or rolled back together with the business records
+	if dbtxn.Find(ctx) == nil {
+		log.WithField("entryType", request.EntryType).Error("Ledger entry posted outside a transaction")
+		return -1, errors.New("ledger entry must be posted inside a transaction")
+	}
+
+	err := s.Validator.ValidateCreate(request)
+	if err != nil {
+		log.WithFields(log.Fields{
+			"entryType":   request.EntryType,
+			"referenceID": request.ReferenceID,
+		}).Errorf("Validation failed: %s", err)
+		return -1, err
+	}
+
+	existing, err := s.EntryRepo.GetByReference(ctx, request.EntryType, request.ReferenceID)
+	if err != nil {
+		log.WithField("entryType", request.EntryType).WithError(err).Error("Failed to get ledger entry")
+		return -1, err
+	}
+	if existing != nil {
+		log.WithFields(log.Fields{
+			"entryType":   request.EntryType,
+			"referenceID": request.ReferenceID,
+		}).Warn("Ledger entry already posted, skipping")
+		return existing.ID, nil
+	}
+
+	entry := repo.LedgerEntry{
+		EntryCode:   utils.GenerateAlphanumericCode(10),
+		EntryType:   request.EntryType,
+		ReferenceID: request.ReferenceID,
+		Description: request.Description,
+		CreatedAt:   time.Now(),
+	}
+	entry.ID, err = s.EntryRepo.Create(ctx, &entry)
+	if err != nil {
+		log.WithField("entryType", request.EntryType).WithError(err).Error("Failed to create ledger entry")
+		return -1, err
+	}
+
+	for _, postingRequest := range request.Postings {
+		accountID, err := s.accountID(ctx, postingRequest.AccountType, postingRequest.OwnerID)
+		if err != nil {
+			log.WithField("accountType", postingRequest.AccountType).WithError(err).Error("Failed to get ledger account")
+			return -1, err
+		}
+
+		posting := repo.LedgerPosting{
+			EntryID:   entry.ID,
+			AccountID: accountID,
+			Direction: postingRequest.Direction,
+			Amount:    postingRequest.Amount,
+			CreatedAt: entry.CreatedAt,
+		}
+		_, err = s.PostingRepo.Create(ctx, &posting)
+		if err != nil {
+			log.WithField("entryID", entry.ID).WithError(err).Error("Failed to create ledger posting")
+			return -1, err
+		}
+	}
+
+	log.WithFields(log.Fields{
+		"entryID":     entry.ID,
+		"entryType":   entry.EntryType,
+		"referenceID": entry.ReferenceID,
+	}).Info("Ledger entry posted successfully")
+	return entry.ID, nil
+}
+
+// RecordFundingInvested moves the investment from the lender wallet into escrow
+func (s *LedgerSvcImpl) RecordFundingInvested(ctx context.Context, funding *repo.LoanFunding) error {
+	_, err := s.Post(ctx, models.LedgerEntryRequest{
+		EntryType:   enum.LedgerFundingInvested,
+		ReferenceID: funding.ID,
+		Description: fmt.Sprintf("Funding %s invested in loan %d", funding.LoanOrderNumber, funding.LoanID),
+		Postings: []models.LedgerPostingRequest{
+			{AccountType: enum.LedgerLenderWallet, OwnerID: funding.LenderID, Direction: enum.LedgerDebit, Amount: funding.InvestmentAmount},
+			{AccountType: enum.LedgerPlatformEscrow, Direction: enum.LedgerCredit, Amount: funding.InvestmentAmount},
+		},
+	})
+	return err
+}
+
+// RecordFundingRefunded gives an invested amount held in escrow back to the lender wallet
+func (s *LedgerSvcImpl) RecordFundingRefunded(ctx context.Context, funding *repo.LoanFunding) error {
+	_, err := s.Post(ctx, models.LedgerEntryRequest{
+		EntryType:   enum.LedgerFundingRefunded,
+		ReferenceID: funding.ID,
+		Description: fmt.Sprintf("Funding %s refunded from loan %d", funding.LoanOrderNumber, funding.LoanID),
+		Postings: []models.LedgerPostingRequest{
+			{AccountType: enum.LedgerPlatformEscrow, Direction: enum.LedgerDebit, Amount: funding.InvestmentAmount},
+			{AccountType: enum.LedgerLenderWallet, OwnerID: funding.LenderID, Direction: enum.LedgerCredit, Amount: funding.InvestmentAmount},
+		},
+	})
+	return err
+}
+
+// RecordDisbursement pays the funded principal out to the borrower, the borrower now owes it to the
+// platform. The origination fee is kept by the platform out of the payout. The lender money stays in
+// escrow until the principal is repaid.
+func (s *LedgerSvcImpl) RecordDisbursement(ctx context.Context, loan *repo.Loan, disbursement *repo.LoanDisbursement) error {
+	postings := []models.LedgerPostingRequest{
+		{AccountType: enum.LedgerLoanReceivable, OwnerID: loan.ID, Direction: enum.LedgerDebit, Amount: loan.TotalInvestedAmount},
+		{AccountType: enum.LedgerPlatformCash, Direction: enum.LedgerCredit, Amount: loan.TotalInvestedAmount - disbursement.OriginationFee},
+	}
+	if disbursement.OriginationFee.IsPositive() {
+		postings = append(postings, models.LedgerPostingRequest{
+			AccountType: enum.LedgerPlatformFee,
+			Direction:   enum.LedgerCredit,
+			Amount:      disbursement.OriginationFee,
+		})
+	}
+
+	_, err := s.Post(ctx, models.LedgerEntryRequest{
+		EntryType:   enum.LedgerLoanDisbursed,
+		ReferenceID: loan.ID,
+		Description: fmt.Sprintf("Loan %s disbursed", loan.LoanCode),
+		Postings:    postings,
+	})
+	return err
+}
+
+// RecordRepayment receives a borrower payment, settles the repaid principal against the loan
+// receivable and escrow, credits every lender wallet with its allocation, earns the service fees and
+// the interest spread above the lender yield, and owes the tax withheld from the lenders to the tax office
+func (s *LedgerSvcImpl) RecordRepayment(ctx context.Context, repayment *repo.LoanRepayment, allocations []repo.RepaymentAllocation) error {
+	postings := []models.LedgerPostingRequest{
+		{AccountType: enum.LedgerPlatformCash, Direction: enum.LedgerDebit, Amount: repayment.Amount},
+	}
+	if repayment.PrincipalAmount.IsPositive() {
+		postings = append(postings,
+			models.LedgerPostingRequest{AccountType: enum.LedgerLoanReceivable, OwnerID: repayment.LoanID, Direction: enum.LedgerCredit, Amount: repayment.PrincipalAmount},
+			models.LedgerPostingRequest{AccountType: enum.LedgerPlatformEscrow, Direction: enum.LedgerDebit, Amount: repayment.PrincipalAmount},
+		)
+	}
+	var serviceFee, withholdingTax money.Amount
+	// the interest not allocated to the lenders is the spread between the loan rate and the lender yield
+	spread := repayment.InterestAmount
+	for _, allocation := range allocations {
+		spread -= allocation.InterestAmount
+		serviceFee += allocation.ServiceFee
+		withholdingTax += allocation.WithholdingTax
+		if !allocation.TotalAmount.IsPositive() {
+			continue
+		}
+		postings = append(postings, models.LedgerPostingRequest{
+			AccountType: enum.LedgerLenderWallet,
+			OwnerID:     allocation.LenderID,
+			Direction:   enum.LedgerCredit,
+			Amount:      allocation.TotalAmount,
+		})
+	}
+	if serviceFee.IsPositive() {
+		postings = append(postings, models.LedgerPostingRequest{
+			AccountType: enum.LedgerPlatformFee,
+			Direction:   enum.LedgerCredit,
+			Amount:      serviceFee,
+		})
+	}
+	if spread.IsPositive() {
+		postings = append(postings, models.LedgerPostingRequest{
+			AccountType: enum.LedgerPlatformFee,
+			Direction:   enum.LedgerCredit,
+			Amount:      spread,
+		})
+	}
+	if withholdingTax.IsPositive() {
+		postings = append(postings, models.LedgerPostingRequest{
+			AccountType: enum.LedgerTaxPayable,
+			Direction:   enum.LedgerCredit,
+			Amount:      withholdingTax,
+		})
+	}
+
+	_, err := s.Post(ctx, models.LedgerEntryRequest{
+		EntryType:   enum.LedgerLoanRepaid,
+		ReferenceID: repayment.ID,
+		Description: fmt.Sprintf("Repayment %s of loan %d", repayment.RepaymentCode, repayment.LoanID),
+		Postings:    postings,
+	})
+	return err
+}
+
+// RecordWalletTopUp receives the money a lender paid into the wallet
+func (s *LedgerSvcImpl) RecordWalletTopUp(ctx context.Context, transaction *repo.WalletTransaction) error {
+	_, err := s.Post(ctx, models.LedgerEntryRequest{
+		EntryType:   enum.LedgerWalletTopUp,
+		ReferenceID: transaction.ID,
+		Description: fmt.Sprintf("Wallet top up %s of lender %d", transaction.ReferenceNumber, transaction.LenderID),
+		Postings: []models.LedgerPostingRequest{
+			{AccountType: enum.LedgerPlatformCash, Direction: enum.LedgerDebit, Amount: transaction.Amount},
+			{AccountType: enum.LedgerLenderWallet, OwnerID: transaction.LenderID, Direction: enum.LedgerCredit, Amount: transaction.Amount},
+		},
+	})
+	return err
+}
+
+// RecordWithdrawal pays an approved withdrawal out of the lender wallet
+func (s *LedgerSvcImpl) RecordWithdrawal(ctx context.Context, withdrawal *repo.WalletWithdrawal) error {
+	_, err := s.Post(ctx, models.LedgerEntryRequest{
+		EntryType:   enum.LedgerWalletWithdrawn,
+		ReferenceID: withdrawal.ID,
+		Description: fmt.Sprintf("Wallet withdrawal %s of lender %d", withdrawal.WithdrawalCode, withdrawal.LenderID),
+		Postings: []models.LedgerPostingRequest{
+			{AccountType: enum.LedgerLenderWallet, OwnerID: withdrawal.LenderID, Direction: enum.LedgerDebit, Amount: withdrawal.Amount},
+			{AccountType: enum.LedgerPlatformCash, Direction: enum.LedgerCredit, Amount: withdrawal.Amount},
+		},
+	})
+	return err
+}
+
+// RecordFundingTransferred pays the price of a funding sold on the secondary market from the buyer
+// wallet to the seller wallet. Escrow does not change, the platform still owes the principal, now
+// to the buyer.
+func (s *LedgerSvcImpl) RecordFundingTransferred(ctx context.Context, transfer *repo.FundingTransfer) error {
+	_, err := s.Post(ctx, models.LedgerEntryRequest{
+		EntryType:   enum.LedgerFundingTransferred,
+		ReferenceID: transfer.ID,
+		Description: fmt.Sprintf("Funding transfer %s of loan %d", transfer.TransferCode, transfer.LoanID),
+		Postings: []models.LedgerPostingRequest{
+			{AccountType: enum.LedgerLenderWallet, OwnerID: transfer.BuyerID, Direction: enum.LedgerDebit, Amount: transfer.Price},
+			{AccountType: enum.LedgerLenderWallet, OwnerID: transfer.SellerID, Direction: enum.LedgerCredit, Amount: transfer.Price},
+		},
+	})
+	return err
+}
+
+func (s *LedgerSvcImpl) GetAccounts(ctx context.Context, request repo.LedgerAccountRequest) ([]dto.LedgerAccountResponseDTO, error) {
+	accounts, err := s.AccountRepo.GetAll(ctx, request)
+	if err != nil {
+		log.WithField("accountType", request.AccountType).WithError(err).Error("Failed to get ledger accounts")
+		return nil, errors.New("99999")
+	}
+
+	accountDTOs := []dto.LedgerAccountResponseDTO{}
+	for _, account := range accounts {
+		accountRes, err := s.toAccountResponseDTO(ctx, account)
+		if err != nil {
+			return nil, errors.New("99999")
+		}
+		accountDTOs = append(accountDTOs, *accountRes)
+	}
+
+	return accountDTOs, nil
+}
+
+// GetAccount returns the ledger account with its balance computed from every posting
+func (s *LedgerSvcImpl) GetAccount(ctx context.Context, accountID int64) (*dto.LedgerAccountResponseDTO, error) {
+	account, err := s.AccountRepo.GetByID(ctx, accountID)
+	if err != nil {
+		log.WithField("accountID", accountID).WithError(err).Error("Failed to get ledger account")
+		return nil, errors.New("99999")
+	}
+	if account == nil {
+		log.WithField("accountID", accountID).Warn("Ledger account not found")
+		return nil, errors.New("10001")
+	}
+
+	accountRes, err := s.toAccountResponseDTO(ctx, *account)
+	if err != nil {
+		return nil, errors.New("99999")
+	}
+	return accountRes, nil
+}
+
+// GetStatementPage returns the postings of an account with the running balance, oldest first
+func (s *LedgerSvcImpl) GetStatementPage(ctx context.Context, request models.LedgerStatementRequest) ([]dto.LedgerStatementLineDTO, int, error) {
+	log.WithFields(log.Fields{
+		"accountID": request.AccountID,
+		"page":      request.Page,
+		"size":      request.Size,
+	}).Info("Fetching ledger account statement")
+
+	account, err := s.AccountRepo.GetByID(ctx, request.AccountID)
+	if err != nil {
+		log.WithField("accountID", request.AccountID).WithError(err).Error("Failed to get ledger account")
+		return nil, 0, errors.New("99999")
+	}
+	if account == nil {
+		log.WithField("accountID", request.AccountID).Warn("Ledger account not found")
+		return nil, 0, errors.New("10001")
+	}
+
+	lines, totalRecords, err := s.PostingRepo.GetStatementPage(ctx, repo.LedgerStatementRequest{
+		AccountID:     account.ID,
+		NormalBalance: account.NormalBalance,
+		Offset:        (request.Page - 1) * request.Size,
+		Size:          request.Size,
+	})
+	if err != nil {
+		log.WithField("accountID", request.AccountID).WithError(err).Error("Failed to get ledger statement")
+		return nil, 0, errors.New("99999")
+	}
+
+	lineDTOs := []dto.LedgerStatementLineDTO{}
+	for _, line := range lines {
+		lineDTOs = append(lineDTOs, dto.LedgerStatementLineDTO{
+			PostingID:   line.ID,
+			EntryID:     line.EntryID,
+			EntryCode:   line.EntryCode,
+			EntryType:   line.EntryType,
+			ReferenceID: line.ReferenceID,
+			Description: line.Description,
+			Direction:   line.Direction,
+			Amount:      line.Amount,
+			Balance:     line.Balance,
+			CreatedAt:   line.CreatedAt,
+		})
+	}
+
+	return lineDTOs, int(totalRecords), nil
+}
+
+// accountID returns the id of the account, the account is opened on its first posting
+func (s *LedgerSvcImpl) accountID(ctx context.Context, accountType enum.LedgerAccountType, ownerID int64) (int64, error) {
+	account := repo.LedgerAccount{
+		AccountCode:   string(accountType),
+		AccountType:   accountType,
+		NormalBalance: accountType.NormalBalance(),
+	}
+	if !accountType.IsPlatform() {
+		account.AccountCode = fmt.Sprintf("%s:%d", accountType, ownerID)
+		account.OwnerID = &ownerID
+	}
+
+	return s.AccountRepo.CreateIfNotExists(ctx, &account)
+}
+
+func (s *LedgerSvcImpl) toAccountResponseDTO(ctx context.Context, account repo.LedgerAccount) (*dto.LedgerAccountResponseDTO, error) {
+	debit, credit, err := s.PostingRepo.GetTotalsByAccountID(ctx, account.ID)
+	if err != nil {
+		log.WithField("accountID", account.ID).WithError(err).Error("Failed to get ledger account totals")
+		return nil, err
+	}
+
+	balance := credit - debit
+	if account.NormalBalance == enum.LedgerDebit {
+		balance = debit - credit
+	}
+
+	return &dto.LedgerAccountResponseDTO{
+		ID:            account.ID,
+		AccountCode:   account.AccountCode,
+		AccountType:   account.AccountType,
+		OwnerID:       account.OwnerID,
+		NormalBalance: account.NormalBalance,
+		TotalDebit:    debit,
+		TotalCredit:   credit,
+		Balance:       balance,
+		CreatedAt:     account.CreatedAt,
+		UpdatedAt:     account.UpdatedAt,
+	}, nil
+}
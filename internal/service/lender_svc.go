@@ -0,0 +1,549 @@
+package service
+
+import (
+	"context"
+	"errors"
+	log "github.com/sirupsen/logrus"
+	"github.com/test/loan-service/internal/dto"
+	"github.com/test/loan-service/internal/enum"
+	repo "github.com/test/loan-service/internal/repository"
+	"github.com/test/loan-service/internal/service/validator"
+	"github.com/typical-go/typical-rest-server/pkg/dbtxn"
+	"go.uber.org/dig"
+	"time"
+)
+
+//go:generate mockgen -source=$GOFILE -destination=$PROJ/internal/generated/mock/mock_$GOPACKAGE/$GOFILE
+
+type (
+	// LenderSvc keeps the identity of lenders, their KYC, their suitability questionnaire and their
+	// investor classification. A lender can only fund a loan once the KYC is verified, a retail lender
+	// only up to the grade its risk profile allows while an accredited lender may fund every grade. The
+	// lender type and tax ID are kept in the tax profile.
+	LenderSvc interface {
+		Create(ctx context.Context, request *dto.LenderRequestDTO) (*dto.LenderResponseDTO, error)
+		Update(ctx context.Context, request *dto.LenderRequestDTO) (*dto.LenderResponseDTO, error)
+		GetByID(ctx context.Context, id int64) (*dto.LenderResponseDTO, error)
+		GetEligible(ctx context.Context, id int64, grade enum.LoanGrade) (*repo.Lender, error)
+		SubmitKyc(ctx context.Context, id int64) (*dto.LenderResponseDTO, error)
+		ReviewKyc(ctx context.Context, id int64, request *dto.KycReviewRequestDTO) (*dto.LenderResponseDTO, error)
+		GetKycHistory(ctx context.Context, id int64) ([]dto.KycHistoryResponseDTO, error)
+		SubmitSuitability(ctx context.Context, id int64, request *dto.SuitabilityRequestDTO) (*dto.LenderResponseDTO, error)
+		Classify(ctx context.Context, id int64, request *dto.InvestorClassRequestDTO) (*dto.LenderResponseDTO, error)
+	}
+
+	LenderSvcImpl struct {
+		dig.In
+		Repo                   repo.LenderRepo
+		KycSvc                 KycSvc
+		TaxSvc                 TaxSvc
+		Validator              validator.LenderValidatorImpl
+		SuitabilityValidator   validator.SuitabilityValidatorImpl
+		InvestorClassValidator validator.InvestorClassValidatorImpl
+	}
+)
+
+func NewLenderSvc(impl LenderSvcImpl) LenderSvc {
+	return &impl
+}
+
+// Create registers a lender as a retail investor with an unverified KYC, the lender type and tax ID
+// are saved as its tax profile
+func (s *LenderSvcImpl) Create(ctx context.Context, request *dto.LenderRequestDTO) (*dto.LenderResponseDTO, error) {
+	log.WithFields(log.Fields{
+		"nationalID": request.NationalID,
+		"lenderType": request.LenderType,
+	}).Info("Creating lender")
+
+	err := s.Validator.ValidateCreate(request)
+	if err != nil {
+		log.WithField("nationalID", request.NationalID).Errorf("Validation failed: %s", err)
+		return nil, err
+	}
+
+	err = s.checkDuplicate(ctx, 0, request.NationalID)
+	if err != nil {
+		return nil, err
+	}
+
+	txnCtx := dbtxn.Begin(&ctx)
+	defer func() {
+		if err := txnCtx.Commit(); err != nil {
+			log.WithError(err).Error("Transaction commit failed")
+			txnCtx.AppendError(err)
+		}
+	}()
+
+	var lender repo.Lender
+	applyLenderRequest(&lender, request)
+	lender.InvestorClass = enum.InvestorRetail
+	lender.KycStatus = enum.KycUnverified
+	lender.CreatedAt = time.Now()
+	lender.UpdatedAt = lender.CreatedAt
+
+	lender.ID, err = s.Repo.Create(ctx, &lender)
+	if err != nil {
+		log.WithField("nationalID", request.NationalID).WithError(err).Error("Failed to create lender")
+		txnCtx.AppendError(err)
+		return nil, errors.New("99999")
+	}
+
+	profile, err := s.saveTaxProfile(ctx, lender.ID, request)
+	if err != nil {
+		txnCtx.AppendError(err)
+		return nil, err
+	}
+
+	log.WithField("lenderID", lender.ID).Info("Lender created successfully")
+	return s.toResponseDTO(lender, profile), nil
+}
+
+// Update replaces the profile of a lender. A change of the name, national ID or date of birth puts a
+// submitted or verified KYC back to unverified, the lender has to submit it again.
+func (s *LenderSvcImpl) Update(ctx context.Context, request *dto.LenderRequestDTO) (*dto.LenderResponseDTO, error) {
+	log.WithField("lenderID", request.LenderID).Info("Updating lender")
+
+	err := s.Validator.ValidateUpdate(request)
+	if err != nil {
+		log.WithField("lenderID", request.LenderID).Errorf("Validation failed: %s", err)
+		return nil, err
+	}
+
+	err = s.checkDuplicate(ctx, request.LenderID, request.NationalID)
+	if err != nil {
+		return nil, err
+	}
+
+	txnCtx := dbtxn.Begin(&ctx)
+	defer func() {
+		if err := txnCtx.Commit(); err != nil {
+			log.WithError(err).Error("Transaction commit failed")
+			txnCtx.AppendError(err)
+		}
+	}()
+
+	lender, err := s.getForUpdate(ctx, request.LenderID)
+	if err != nil {
+		txnCtx.AppendError(err)
+		return nil, err
+	}
+
+	identityChanged := lender.FullName != request.FullName ||
+		lender.NationalID != request.NationalID ||
+		!sameDate(lender.DateOfBirth, request.DateOfBirth)
+
+	applyLenderRequest(lender, request)
+	if identityChanged {
+		err = s.KycSvc.ResetIdentity(ctx, enum.DocumentEntityLender, lender.ID, &lender.Kyc)
+		if err != nil {
+			txnCtx.AppendError(err)
+			return nil, err
+		}
+	}
+
+	lender.UpdatedAt = time.Now()
+	err = s.Repo.Update(ctx, lender)
+	if err != nil {
+		log.WithField("lenderID", lender.ID).WithError(err).Error("Failed to update lender")
+		txnCtx.AppendError(err)
+		return nil, errors.New("99999")
+	}
+
+	profile, err := s.saveTaxProfile(ctx, lender.ID, request)
+	if err != nil {
+		txnCtx.AppendError(err)
+		return nil, err
+	}
+
+	log.WithFields(log.Fields{
+		"lenderID":  lender.ID,
+		"kycStatus": lender.KycStatus,
+	}).Info("Lender updated successfully")
+	return s.toResponseDTO(*lender, profile), nil
+}
+
+func (s *LenderSvcImpl) GetByID(ctx context.Context, id int64) (*dto.LenderResponseDTO, error) {
+	lender, err := s.Repo.GetByID(ctx, id)
+	if err != nil {
+		log.WithField("lenderID", id).WithError(err).Error("Failed to get lender")
+		return nil, errors.New("99999")
+	}
+	if lender == nil {
+		log.WithField("lenderID", id).Warn("Lender not found")
+		return nil, errors.New("10001")
+	}
+
+	return s.withTaxProfile(ctx, *lender)
+}
+
+// GetEligible returns the lender when it may fund a loan of the grade, 10001 when the lender does not
+// exist, 10022 while the KYC is not verified and 10023 when the grade is worse than the lender may
+// invest in
+func (s *LenderSvcImpl) GetEligible(ctx context.Context, id int64, grade enum.LoanGrade) (*repo.Lender, error) {
+	lender, err := s.Repo.GetByID(ctx, id)
+	if err != nil {
+		log.WithField("lenderID", id).WithError(err).Error("Failed to get lender")
+		return nil, errors.New("99999")
+	}
+	if lender == nil {
+		log.WithField("lenderID", id).Warn("Lender not found")
+		return nil, errors.New("10001")
+	}
+	if lender.KycStatus != enum.KycVerified {
+		log.WithFields(log.Fields{
+			"lenderID":  id,
+			"kycStatus": lender.KycStatus,
+		}).Warn("Lender is not verified")
+		return nil, errors.New("10022")
+	}
+
+	maxGrade := maxLoanGrade(lender)
+	if maxGrade == nil || grade > *maxGrade {
+		log.WithFields(log.Fields{
+			"lenderID":      id,
+			"investorClass": lender.InvestorClass,
+			"riskProfile":   lender.RiskProfile,
+			"loanGrade":     grade,
+		}).Warn("Loan grade is not suitable for lender")
+		return nil, errors.New("10023")
+	}
+
+	return lender, nil
+}
+
+// SubmitKyc sends the KYC of a lender for review, the verification documents have to be uploaded for
+// the lender first and the error names every missing one
+func (s *LenderSvcImpl) SubmitKyc(ctx context.Context, id int64) (*dto.LenderResponseDTO, error) {
+	log.WithField("lenderID", id).Info("Submitting lender KYC")
+
+	txnCtx := dbtxn.Begin(&ctx)
+	defer func() {
+		if err := txnCtx.Commit(); err != nil {
+			log.WithError(err).Error("Transaction commit failed")
+			txnCtx.AppendError(err)
+		}
+	}()
+
+	lender, err := s.getForUpdate(ctx, id)
+	if err != nil {
+		txnCtx.AppendError(err)
+		return nil, err
+	}
+
+	documents, err := s.kycDocuments(ctx, lender)
+	if err != nil {
+		txnCtx.AppendError(err)
+		return nil, err
+	}
+
+	err = s.KycSvc.Submit(ctx, enum.DocumentEntityLender, lender.ID, &lender.Kyc, documents)
+	if err != nil {
+		txnCtx.AppendError(err)
+		return nil, err
+	}
+
+	err = s.Repo.Update(ctx, lender)
+	if err != nil {
+		log.WithField("lenderID", id).WithError(err).Error("Failed to update lender KYC status")
+		txnCtx.AppendError(err)
+		return nil, errors.New("99999")
+	}
+
+	log.WithField("lenderID", id).Info("Lender KYC submitted successfully")
+	return s.withTaxProfile(ctx, *lender)
+}
+
+// ReviewKyc records the decision of the staff on a submitted KYC
+func (s *LenderSvcImpl) ReviewKyc(ctx context.Context, id int64, request *dto.KycReviewRequestDTO) (*dto.LenderResponseDTO, error) {
+	log.WithFields(log.Fields{
+		"lenderID":  id,
+		"staffID":   request.StaffID,
+		"kycStatus": request.KycStatus,
+	}).Info("Reviewing lender KYC")
+
+	txnCtx := dbtxn.Begin(&ctx)
+	defer func() {
+		if err := txnCtx.Commit(); err != nil {
+			log.WithError(err).Error("Transaction commit failed")
+			txnCtx.AppendError(err)
+		}
+	}()
+
+	lender, err := s.getForUpdate(ctx, id)
+	if err != nil {
+		txnCtx.AppendError(err)
+		return nil, err
+	}
+
+	err = s.KycSvc.Review(ctx, enum.DocumentEntityLender, lender.ID, &lender.Kyc, request)
+	if err != nil {
+		txnCtx.AppendError(err)
+		return nil, err
+	}
+
+	err = s.Repo.Update(ctx, lender)
+	if err != nil {
+		log.WithField("lenderID", id).WithError(err).Error("Failed to update lender KYC status")
+		txnCtx.AppendError(err)
+		return nil, errors.New("99999")
+	}
+
+	log.WithFields(log.Fields{
+		"lenderID":  id,
+		"kycStatus": lender.KycStatus,
+	}).Info("Lender KYC reviewed successfully")
+	return s.withTaxProfile(ctx, *lender)
+}
+
+func (s *LenderSvcImpl) GetKycHistory(ctx context.Context, id int64) ([]dto.KycHistoryResponseDTO, error) {
+	lender, err := s.Repo.GetByID(ctx, id)
+	if err != nil {
+		log.WithField("lenderID", id).WithError(err).Error("Failed to get lender")
+		return nil, errors.New("99999")
+	}
+	if lender == nil {
+		log.WithField("lenderID", id).Warn("Lender not found")
+		return nil, errors.New("10001")
+	}
+
+	return s.KycSvc.GetHistory(ctx, enum.DocumentEntityLender, id)
+}
+
+// SubmitSuitability scores the answers of the suitability questionnaire and sets the risk profile of
+// the lender, a new questionnaire replaces the previous result
+func (s *LenderSvcImpl) SubmitSuitability(ctx context.Context, id int64, request *dto.SuitabilityRequestDTO) (*dto.LenderResponseDTO, error) {
+	log.WithField("lenderID", id).Info("Submitting lender suitability questionnaire")
+
+	err := s.SuitabilityValidator.ValidateCreate(request)
+	if err != nil {
+		log.WithField("lenderID", id).Errorf("Validation failed: %s", err)
+		return nil, err
+	}
+
+	txnCtx := dbtxn.Begin(&ctx)
+	defer func() {
+		if err := txnCtx.Commit(); err != nil {
+			log.WithError(err).Error("Transaction commit failed")
+			txnCtx.AppendError(err)
+		}
+	}()
+
+	lender, err := s.getForUpdate(ctx, id)
+	if err != nil {
+		txnCtx.AppendError(err)
+		return nil, err
+	}
+
+	score := request.InvestmentExperience + request.LossTolerance + request.InvestmentHorizon + request.IncomeStability
+	riskProfile := riskProfileOf(score)
+	now := time.Now()
+	lender.SuitabilityScore = &score
+	lender.RiskProfile = &riskProfile
+	lender.SuitabilityAssessedAt = &now
+
+	err = s.Repo.Update(ctx, lender)
+	if err != nil {
+		log.WithField("lenderID", id).WithError(err).Error("Failed to update lender suitability")
+		txnCtx.AppendError(err)
+		return nil, errors.New("99999")
+	}
+
+	log.WithFields(log.Fields{
+		"lenderID":    id,
+		"score":       score,
+		"riskProfile": riskProfile,
+	}).Info("Lender suitability assessed successfully")
+	return s.withTaxProfile(ctx, *lender)
+}
+
+// Classify sets the investor classification of a lender, only a lender with a verified KYC can be
+// accredited
+func (s *LenderSvcImpl) Classify(ctx context.Context, id int64, request *dto.InvestorClassRequestDTO) (*dto.LenderResponseDTO, error) {
+	log.WithFields(log.Fields{
+		"lenderID":      id,
+		"staffID":       request.StaffID,
+		"investorClass": request.InvestorClass,
+	}).Info("Classifying lender")
+
+	err := s.InvestorClassValidator.ValidateUpdate(request)
+	if err != nil {
+		log.WithField("lenderID", id).Errorf("Validation failed: %s", err)
+		return nil, err
+	}
+
+	txnCtx := dbtxn.Begin(&ctx)
+	defer func() {
+		if err := txnCtx.Commit(); err != nil {
+			log.WithError(err).Error("Transaction commit failed")
+			txnCtx.AppendError(err)
+		}
+	}()
+
+	lender, err := s.getForUpdate(ctx, id)
+	if err != nil {
+		txnCtx.AppendError(err)
+		return nil, err
+	}
+
+	if request.InvestorClass == enum.InvestorAccredited && lender.KycStatus != enum.KycVerified {
+		log.WithFields(log.Fields{
+			"lenderID":  id,
+			"kycStatus": lender.KycStatus,
+		}).Warn("Lender can not be accredited before the KYC is verified")
+		txnCtx.AppendError(errors.New("10022"))
+		return nil, errors.New("10022")
+	}
+
+	now := time.Now()
+	lender.InvestorClass = request.InvestorClass
+	lender.ClassifiedBy = &request.StaffID
+	lender.ClassifiedAt = &now
+
+	err = s.Repo.Update(ctx, lender)
+	if err != nil {
+		log.WithField("lenderID", id).WithError(err).Error("Failed to update lender classification")
+		txnCtx.AppendError(err)
+		return nil, errors.New("99999")
+	}
+
+	log.WithFields(log.Fields{
+		"lenderID":      id,
+		"investorClass": lender.InvestorClass,
+	}).Info("Lender classified successfully")
+	return s.withTaxProfile(ctx, *lender)
+}
+
+// getForUpdate locks the lender until the transaction of ctx ends, 10001 when it does not exist
+func (s *LenderSvcImpl) getForUpdate(ctx context.Context, id int64) (*repo.Lender, error) {
+	lender, err := s.Repo.GetByIDForUpdate(ctx, id)
+	if err != nil {
+		log.WithField("lenderID", id).WithError(err).Error("Failed to get lender")
+		return nil, errors.New("99999")
+	}
+	if lender == nil {
+		log.WithField("lenderID", id).Warn("Lender not found")
+		return nil, errors.New("10001")
+	}
+
+	return lender, nil
+}
+
+// kycDocuments are the documents a lender has to upload before the KYC is submitted, the national ID
+// of an individual or the business registration of an institution
+func (s *LenderSvcImpl) kycDocuments(ctx context.Context, lender *repo.Lender) ([]string, error) {
+	profile, err := s.TaxSvc.GetProfile(ctx, lender.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	if profile.LenderType == enum.LenderInstitutional {
+		return []string{kycBusinessRegistrationDocument}, nil
+	}
+	return []string{kycNationalIDDocument}, nil
+}
+
+// checkDuplicate refuses a national ID registered for another lender, id is the lender being updated,
+// zero on create
+func (s *LenderSvcImpl) checkDuplicate(ctx context.Context, id int64, nationalID string) error {
+	existing, err := s.Repo.GetByNationalID(ctx, nationalID)
+	if err != nil {
+		log.WithError(err).Error("Failed to get lender by national ID")
+		return errors.New("99999")
+	}
+	if existing != nil && existing.ID != id {
+		log.WithField("lenderID", existing.ID).Warn("National ID already registered")
+		return errors.New("10003")
+	}
+
+	return nil
+}
+
+// saveTaxProfile keeps the lender type and tax ID of the request in the tax profile of the lender
+func (s *LenderSvcImpl) saveTaxProfile(ctx context.Context, lenderID int64, request *dto.LenderRequestDTO) (*dto.LenderTaxProfileResponseDTO, error) {
+	return s.TaxSvc.UpdateProfile(ctx, &dto.LenderTaxProfileRequestDTO{
+		LenderID:   lenderID,
+		LenderType: request.LenderType,
+		TaxID:      request.TaxID,
+	})
+}
+
+func (s *LenderSvcImpl) withTaxProfile(ctx context.Context, lender repo.Lender) (*dto.LenderResponseDTO, error) {
+	profile, err := s.TaxSvc.GetProfile(ctx, lender.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.toResponseDTO(lender, profile), nil
+}
+
+func (s *LenderSvcImpl) toResponseDTO(lender repo.Lender, profile *dto.LenderTaxProfileResponseDTO) *dto.LenderResponseDTO {
+	lenderRes := dto.LenderResponseDTO{
+		ID:                    lender.ID,
+		FullName:              lender.FullName,
+		NationalID:            lender.NationalID,
+		DateOfBirth:           lender.DateOfBirth,
+		PhoneNumber:           lender.PhoneNumber,
+		Email:                 lender.Email,
+		Address:               lender.Address,
+		LenderType:            profile.LenderType,
+		TaxID:                 profile.TaxID,
+		InvestorClass:         lender.InvestorClass,
+		ClassifiedBy:          lender.ClassifiedBy,
+		ClassifiedAt:          lender.ClassifiedAt,
+		RiskProfile:           lender.RiskProfile,
+		SuitabilityScore:      lender.SuitabilityScore,
+		SuitabilityAssessedAt: lender.SuitabilityAssessedAt,
+		KycStatus:             lender.KycStatus,
+		KycVerifiedAt:         lender.KycVerifiedAt,
+		CreatedAt:             lender.CreatedAt,
+		UpdatedAt:             lender.UpdatedAt,
+	}
+	if lender.KycStatus == enum.KycVerified {
+		lenderRes.MaxLoanGrade = maxLoanGrade(&lender)
+	}
+
+	return &lenderRes
+}
+
+// maxLoanGrade is the worst grade the lender may invest in, nil for a retail lender that has not
+// answered the suitability questionnaire
+func maxLoanGrade(lender *repo.Lender) *enum.LoanGrade {
+	var grade enum.LoanGrade
+	switch {
+	case lender.InvestorClass == enum.InvestorAccredited:
+		grade = enum.LoanGradeE
+	case lender.RiskProfile != nil:
+		grade = lender.RiskProfile.MaxGrade()
+	default:
+		return nil
+	}
+	return &grade
+}
+
+// riskProfileOf maps the score of the four questionnaire answers, 4 to 16, to a risk profile
+func riskProfileOf(score int64) enum.RiskProfile {
+	switch {
+	case score <= 8:
+		return enum.RiskConservative
+	case score <= 12:
+		return enum.RiskModerate
+	default:
+		return enum.RiskAggressive
+	}
+}
+
+func applyLenderRequest(lender *repo.Lender, request *dto.LenderRequestDTO) {
+	lender.FullName = request.FullName
+	lender.NationalID = request.NationalID
+	lender.DateOfBirth = request.DateOfBirth
+	lender.PhoneNumber = request.PhoneNumber
+	lender.Email = request.Email
+	lender.Address = request.Address
+}
+
+func sameDate(a *time.Time, b *time.Time) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return a.Equal(*b)
+}
@@ -0,0 +1,428 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/mitchellh/mapstructure"
+	log "github.com/sirupsen/logrus"
+	"github.com/test/loan-service/internal/dto"
+	"github.com/test/loan-service/internal/enum"
+	"github.com/test/loan-service/internal/money"
+	repo "github.com/test/loan-service/internal/repository"
+	"github.com/test/loan-service/internal/service/validator"
+	"github.com/test/loan-service/internal/utils"
+	"github.com/typical-go/typical-rest-server/pkg/dbtxn"
+	"go.uber.org/dig"
+	"time"
+)
+
+//go:generate mockgen -source=$GOFILE -destination=$PROJ/internal/generated/mock/mock_$GOPACKAGE/$GOFILE
+
+type (
+	// LenderWalletSvc keeps the money of every lender. The available balance can be invested or
+	// withdrawn, the held balance is reserved for pending fundings and withdrawals. The methods moving
+	// money for a funding, a repayment or a withdrawal must be called with the context of the
+	// transaction that changes that record.
+	LenderWalletSvc interface {
+		TopUp(ctx context.Context, request *dto.WalletTopUpRequestDTO) (*dto.WalletTransactionResponseDTO, error)
+		GetByLenderID(ctx context.Context, lenderID int64) (*dto.LenderWalletResponseDTO, error)
+		GetTransactions(ctx context.Context, lenderID int64) ([]dto.WalletTransactionResponseDTO, error)
+		HoldFunding(ctx context.Context, funding *repo.LoanFunding) error
+		CaptureFunding(ctx context.Context, funding *repo.LoanFunding) error
+		ReleaseFunding(ctx context.Context, funding *repo.LoanFunding) error
+		RefundFunding(ctx context.Context, funding *repo.LoanFunding) error
+		CreditRepayment(ctx context.Context, allocations []repo.RepaymentAllocation) error
+		HoldWithdrawal(ctx context.Context, withdrawal *repo.WalletWithdrawal) error
+		ReleaseWithdrawal(ctx context.Context, withdrawal *repo.WalletWithdrawal) error
+		CompleteWithdrawal(ctx context.Context, withdrawal *repo.WalletWithdrawal) error
+		SettleTransfer(ctx context.Context, transfer *repo.FundingTransfer) error
+	}
+
+	LenderWalletSvcImpl struct {
+		dig.In
+		Repo            repo.LenderWalletRepo
+		TransactionRepo repo.WalletTransactionRepo
+		LedgerSvc       LedgerSvc
+		Validator       validator.LenderWalletValidatorImpl
+	}
+
+	// walletChange describes one movement of a wallet, Available and Held are added to the balances
+	walletChange struct {
+		LenderID        int64
+		TransactionType enum.WalletTransactionType
+		Amount          money.Amount
+		Available       money.Amount
+		Held            money.Amount
+		ReferenceID     *int64
+		ReferenceNumber string
+	}
+)
+
+func NewLenderWalletSvc(impl LenderWalletSvcImpl) LenderWalletSvc {
+	return &impl
+}
+
+// TopUp credits money paid in by the lender. A payment reference is only credited once.
+func (s *LenderWalletSvcImpl) TopUp(ctx context.Context, request *dto.WalletTopUpRequestDTO) (response *dto.WalletTransactionResponseDTO, err error) {
+	log.WithFields(log.Fields{
+		"lenderID":        request.LenderID,
+		"amount":          request.Amount,
+		"referenceNumber": request.ReferenceNumber,
+	}).Info("Topping up lender wallet")
+
+	err = s.Validator.ValidateCreate(request)
+	if err != nil {
+		log.WithField("lenderID", request.LenderID).Errorf("Validation failed: %s", err)
+		return nil, err
+	}
+
+	txnCtx := dbtxn.Begin(&ctx)
+	defer func() {
+		if commitErr := txnCtx.Commit(); commitErr != nil {
+			log.WithError(commitErr).Error("Transaction commit failed")
+			response, err = nil, errors.New("99999")
+		}
+	}()
+
+	existing, err := s.TransactionRepo.GetTopUpByReferenceNumber(ctx, request.ReferenceNumber)
+	if err != nil {
+		log.WithField("referenceNumber", request.ReferenceNumber).WithError(err).Error("Failed to get wallet top up")
+		txnCtx.AppendError(err)
+		return nil, errors.New("99999")
+	}
+	if existing != nil {
+		log.WithField("referenceNumber", request.ReferenceNumber).Warn("Payment reference already topped up")
+		txnCtx.AppendError(errors.New("duplicate top up"))
+		return nil, errors.New("10003")
+	}
+
+	transaction, err := s.apply(ctx, walletChange{
+		LenderID:        request.LenderID,
+		TransactionType: enum.WalletTopUp,
+		Amount:          request.Amount,
+		Available:       request.Amount,
+		ReferenceNumber: request.ReferenceNumber,
+	})
+	if err != nil {
+		txnCtx.AppendError(err)
+		return nil, errors.New("99999")
+	}
+
+	err = s.LedgerSvc.RecordWalletTopUp(ctx, transaction)
+	if err != nil {
+		log.WithField("lenderID", request.LenderID).WithError(err).Error("Failed to record top up in ledger")
+		txnCtx.AppendError(err)
+		return nil, errors.New("99999")
+	}
+
+	transactionRes, err := s.toTransactionResponseDTO(*transaction)
+	if err != nil {
+		txnCtx.AppendError(err)
+		return nil, errors.New("99999")
+	}
+
+	log.WithFields(log.Fields{
+		"lenderID":        request.LenderID,
+		"transactionCode": transaction.TransactionCode,
+	}).Info("Lender wallet topped up successfully")
+	return transactionRes, nil
+}
+
+// GetByLenderID returns the wallet of the lender, a lender without a wallet has an empty one
+func (s *LenderWalletSvcImpl) GetByLenderID(ctx context.Context, lenderID int64) (*dto.LenderWalletResponseDTO, error) {
+	wallet, err := s.Repo.GetByLenderID(ctx, lenderID)
+	if err != nil {
+		log.WithField("lenderID", lenderID).WithError(err).Error("Failed to get lender wallet")
+		return nil, errors.New("99999")
+	}
+	if wallet == nil {
+		log.WithField("lenderID", lenderID).Warn("Lender wallet not found")
+		return nil, errors.New("10001")
+	}
+
+	return &dto.LenderWalletResponseDTO{
+		ID:               wallet.ID,
+		LenderID:         wallet.LenderID,
+		AvailableBalance: wallet.AvailableBalance,
+		HeldBalance:      wallet.HeldBalance,
+		TotalBalance:     wallet.AvailableBalance + wallet.HeldBalance,
+		CreatedAt:        wallet.CreatedAt,
+		UpdatedAt:        wallet.UpdatedAt,
+	}, nil
+}
+
+// GetTransactions returns every movement of the lender wallet, newest first
+func (s *LenderWalletSvcImpl) GetTransactions(ctx context.Context, lenderID int64) ([]dto.WalletTransactionResponseDTO, error) {
+	wallet, err := s.Repo.GetByLenderID(ctx, lenderID)
+	if err != nil {
+		log.WithField("lenderID", lenderID).WithError(err).Error("Failed to get lender wallet")
+		return nil, errors.New("99999")
+	}
+	if wallet == nil {
+		log.WithField("lenderID", lenderID).Warn("Lender wallet not found")
+		return nil, errors.New("10001")
+	}
+
+	transactions, err := s.TransactionRepo.GetByWalletID(ctx, wallet.ID)
+	if err != nil {
+		log.WithField("walletID", wallet.ID).WithError(err).Error("Failed to get wallet transactions")
+		return nil, errors.New("99999")
+	}
+
+	transactionDTOs := []dto.WalletTransactionResponseDTO{}
+	for _, transaction := range transactions {
+		transactionRes, err := s.toTransactionResponseDTO(transaction)
+		if err != nil {
+			return nil, errors.New("99999")
+		}
+		transactionDTOs = append(transactionDTOs, *transactionRes)
+	}
+
+	return transactionDTOs, nil
+}
+
+// HoldFunding reserves the investment of a pending funding. It fails with 10004 when the lender
+// does not have enough available balance.
+func (s *LenderWalletSvcImpl) HoldFunding(ctx context.Context, funding *repo.LoanFunding) error {
+	_, err := s.apply(ctx, walletChange{
+		LenderID:        funding.LenderID,
+		TransactionType: enum.WalletFundingHold,
+		Amount:          funding.InvestmentAmount,
+		Available:       -funding.InvestmentAmount,
+		Held:            funding.InvestmentAmount,
+		ReferenceID:     &funding.ID,
+	})
+	return err
+}
+
+// CaptureFunding takes the reserved investment of a funding that has been invested
+func (s *LenderWalletSvcImpl) CaptureFunding(ctx context.Context, funding *repo.LoanFunding) error {
+	_, err := s.apply(ctx, walletChange{
+		LenderID:        funding.LenderID,
+		TransactionType: enum.WalletFundingCapture,
+		Amount:          funding.InvestmentAmount,
+		Held:            -funding.InvestmentAmount,
+		ReferenceID:     &funding.ID,
+	})
+	return err
+}
+
+// ReleaseFunding makes the reserved investment of a failed funding available again
+func (s *LenderWalletSvcImpl) ReleaseFunding(ctx context.Context, funding *repo.LoanFunding) error {
+	_, err := s.apply(ctx, walletChange{
+		LenderID:        funding.LenderID,
+		TransactionType: enum.WalletFundingRelease,
+		Amount:          funding.InvestmentAmount,
+		Available:       funding.InvestmentAmount,
+		Held:            -funding.InvestmentAmount,
+		ReferenceID:     &funding.ID,
+	})
+	return err
+}
+
+// RefundFunding gives the captured investment of a refunded funding back to the lender
+func (s *LenderWalletSvcImpl) RefundFunding(ctx context.Context, funding *repo.LoanFunding) error {
+	_, err := s.apply(ctx, walletChange{
+		LenderID:        funding.LenderID,
+		TransactionType: enum.WalletFundingRefund,
+		Amount:          funding.InvestmentAmount,
+		Available:       funding.InvestmentAmount,
+		ReferenceID:     &funding.ID,
+	})
+	return err
+}
+
+// CreditRepayment credits every lender with its allocation of a repayment
+func (s *LenderWalletSvcImpl) CreditRepayment(ctx context.Context, allocations []repo.RepaymentAllocation) error {
+	for i := range allocations {
+		allocation := &allocations[i]
+		if !allocation.TotalAmount.IsPositive() {
+			continue
+		}
+
+		_, err := s.apply(ctx, walletChange{
+			LenderID:        allocation.LenderID,
+			TransactionType: enum.WalletRepayment,
+			Amount:          allocation.TotalAmount,
+			Available:       allocation.TotalAmount,
+			ReferenceID:     &allocation.ID,
+		})
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// HoldWithdrawal reserves the amount of a withdrawal waiting for review. It fails with 10004 when
+// the lender does not have enough available balance.
+func (s *LenderWalletSvcImpl) HoldWithdrawal(ctx context.Context, withdrawal *repo.WalletWithdrawal) error {
+	_, err := s.apply(ctx, walletChange{
+		LenderID:        withdrawal.LenderID,
+		TransactionType: enum.WalletWithdrawalHold,
+		Amount:          withdrawal.Amount,
+		Available:       -withdrawal.Amount,
+		Held:            withdrawal.Amount,
+		ReferenceID:     &withdrawal.ID,
+	})
+	return err
+}
+
+// ReleaseWithdrawal makes the reserved amount of a rejected withdrawal available again
+func (s *LenderWalletSvcImpl) ReleaseWithdrawal(ctx context.Context, withdrawal *repo.WalletWithdrawal) error {
+	_, err := s.apply(ctx, walletChange{
+		LenderID:        withdrawal.LenderID,
+		TransactionType: enum.WalletWithdrawalRelease,
+		Amount:          withdrawal.Amount,
+		Available:       withdrawal.Amount,
+		Held:            -withdrawal.Amount,
+		ReferenceID:     &withdrawal.ID,
+	})
+	return err
+}
+
+// CompleteWithdrawal pays out the reserved amount of an approved withdrawal
+func (s *LenderWalletSvcImpl) CompleteWithdrawal(ctx context.Context, withdrawal *repo.WalletWithdrawal) error {
+	_, err := s.apply(ctx, walletChange{
+		LenderID:        withdrawal.LenderID,
+		TransactionType: enum.WalletWithdrawal,
+		Amount:          withdrawal.Amount,
+		Held:            -withdrawal.Amount,
+		ReferenceID:     &withdrawal.ID,
+	})
+	if err != nil {
+		return err
+	}
+
+	err = s.LedgerSvc.RecordWithdrawal(ctx, withdrawal)
+	if err != nil {
+		log.WithField("withdrawalID", withdrawal.ID).WithError(err).Error("Failed to record withdrawal in ledger")
+		return err
+	}
+	return nil
+}
+
+// SettleTransfer pays the price of a funding bought on the secondary market from the buyer to the
+// seller. It fails with 10004 when the buyer does not have enough available balance.
+func (s *LenderWalletSvcImpl) SettleTransfer(ctx context.Context, transfer *repo.FundingTransfer) error {
+	_, err := s.apply(ctx, walletChange{
+		LenderID:        transfer.BuyerID,
+		TransactionType: enum.WalletTransferPurchase,
+		Amount:          transfer.Price,
+		Available:       -transfer.Price,
+		ReferenceID:     &transfer.ID,
+		ReferenceNumber: transfer.TransferCode,
+	})
+	if err != nil {
+		return err
+	}
+
+	_, err = s.apply(ctx, walletChange{
+		LenderID:        transfer.SellerID,
+		TransactionType: enum.WalletTransferSale,
+		Amount:          transfer.Price,
+		Available:       transfer.Price,
+		ReferenceID:     &transfer.ID,
+		ReferenceNumber: transfer.TransferCode,
+	})
+	if err != nil {
+		return err
+	}
+
+	err = s.LedgerSvc.RecordFundingTransferred(ctx, transfer)
+	if err != nil {
+		log.WithField("fundingTransferID", transfer.ID).WithError(err).Error("Failed to record funding transfer in ledger")
+		return err
+	}
+
+	return nil
+}
+
+// apply locks the lender wallet, opening it on its first movement, changes its balances and records
+// the movement with the balances after it
+func (s *LenderWalletSvcImpl) apply(ctx context.Context, change walletChange) (*repo.WalletTransaction, error) {
+	// balances must be committed or rolled back together with the record that moved them
+	if dbtxn.Find(ctx) == nil {
+		log.WithField("transactionType", change.TransactionType).Error("Wallet changed outside a transaction")
+		return nil, errors.New("wallet must be changed inside a transaction")
+	}
+
+	_, err := s.Repo.CreateIfNotExists(ctx, change.LenderID)
+	if err != nil {
+		log.WithField("lenderID", change.LenderID).WithError(err).Error("Failed to open lender wallet")
+		return nil, err
+	}
+
+	wallet, err := s.Repo.GetByLenderIDForUpdate(ctx, change.LenderID)
+	if err != nil || wallet == nil {
+		log.WithField("lenderID", change.LenderID).WithError(err).Error("Failed to lock lender wallet")
+		return nil, fmt.Errorf("lender wallet %d not found: %v", change.LenderID, err)
+	}
+
+	if wallet.AvailableBalance+change.Available < 0 {
+		log.WithFields(log.Fields{
+			"lenderID":         change.LenderID,
+			"transactionType":  change.TransactionType,
+			"amount":           change.Amount,
+			"availableBalance": wallet.AvailableBalance,
+		}).Warn("Insufficient wallet balance")
+		return nil, errors.New("10004")
+	}
+	if wallet.HeldBalance+change.Held < 0 {
+		log.WithFields(log.Fields{
+			"lenderID":        change.LenderID,
+			"transactionType": change.TransactionType,
+			"amount":          change.Amount,
+			"heldBalance":     wallet.HeldBalance,
+		}).Error("Wallet hold not found")
+		return nil, fmt.Errorf("lender %d has no hold of %s", change.LenderID, change.Amount)
+	}
+
+	wallet.AvailableBalance += change.Available
+	wallet.HeldBalance += change.Held
+	err = s.Repo.Update(ctx, wallet)
+	if err != nil {
+		log.WithField("lenderID", change.LenderID).WithError(err).Error("Failed to update lender wallet")
+		return nil, err
+	}
+
+	transaction := repo.WalletTransaction{
+		WalletID:              wallet.ID,
+		LenderID:              wallet.LenderID,
+		TransactionCode:       utils.GenerateAlphanumericCode(10),
+		TransactionType:       change.TransactionType,
+		Amount:                change.Amount,
+		ReferenceID:           change.ReferenceID,
+		ReferenceNumber:       change.ReferenceNumber,
+		AvailableBalanceAfter: wallet.AvailableBalance,
+		HeldBalanceAfter:      wallet.HeldBalance,
+		CreatedAt:             time.Now(),
+	}
+	transaction.ID, err = s.TransactionRepo.Create(ctx, &transaction)
+	if err != nil {
+		log.WithField("lenderID", change.LenderID).WithError(err).Error("Failed to create wallet transaction")
+		return nil, err
+	}
+
+	log.WithFields(log.Fields{
+		"lenderID":         change.LenderID,
+		"transactionType":  change.TransactionType,
+		"amount":           change.Amount,
+		"availableBalance": wallet.AvailableBalance,
+		"heldBalance":      wallet.HeldBalance,
+	}).Info("Lender wallet changed")
+	return &transaction, nil
+}
+
+func (s *LenderWalletSvcImpl) toTransactionResponseDTO(transaction repo.WalletTransaction) (*dto.WalletTransactionResponseDTO, error) {
+	var transactionRes dto.WalletTransactionResponseDTO
+	err := mapstructure.Decode(transaction, &transactionRes)
+	if err != nil {
+		log.WithField("transactionID", transaction.ID).WithError(err).Error("Failed to map wallet transaction to DTO")
+		return nil, err
+	}
+	transactionRes.CreatedAt = transaction.CreatedAt
+
+	return &transactionRes, nil
+}
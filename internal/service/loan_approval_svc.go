@@ -31,10 +31,15 @@ type (
 
 	LoanApprovalSvcImpl struct {
 		dig.In
-		Repo                 repo.LoanApprovalRepo
-		ApprovalDocumentRepo repo.ApprovalDocumentRepo
-		KafkaWriter          *kafka.Writer
-		Validator            validator.LoanApprovalValidatorImpl
+		Repo                   repo.LoanApprovalRepo
+		ApprovalDocumentRepo   repo.ApprovalDocumentRepo
+		ApprovalStepRepo       repo.ApprovalStepRepo
+		LoanRepo               repo.LoanRepo
+		ApprovalLevelSvc       ApprovalLevelSvc
+		DocumentRequirementSvc DocumentRequirementSvc
+		DocumentSvc            DocumentSvc
+		KafkaWriter            *kafka.Writer
+		Validator              validator.LoanApprovalValidatorImpl
 	}
 )
 
@@ -63,6 +68,23 @@ func (b *LoanApprovalSvcImpl) Create(ctx context.Context, loanRequest *dto.LoanA
 	// generate approval number
 	approval.ApprovalNumber = utils.GenerateAlphanumericCode(10)
 
+	// the approval chain is fixed when the loan is proposed, later threshold changes do not move it
+	loan, err := b.LoanRepo.GetByID(ctx, loanRequest.LoanID)
+	if err != nil {
+		logrus.Errorf("Error fetching loan with ID: %d: %v", loanRequest.LoanID, err)
+		return -1, errors.New("99999")
+	}
+	if loan == nil {
+		logrus.Warnf("Loan not found with ID: %d", loanRequest.LoanID)
+		return -1, errors.New("10001")
+	}
+
+	approval.RequiredLevel, err = b.ApprovalLevelSvc.RequiredLevel(ctx, loan.RequestAmount, loan.LoanGrade)
+	if err != nil {
+		logrus.Errorf("Error resolving required approval level for loan ID: %d: %v", loanRequest.LoanID, err)
+		return -1, errors.New("99999")
+	}
+
 	// initial status
 	approval.ApprovalStatus = enum.ApprovalPending
 	approval.CreatedAt = time.Now()
@@ -87,9 +109,19 @@ func (b *LoanApprovalSvcImpl) Update(ctx context.Context, approvalId int64, requ
 		return err
 	}
 
-	approval, err := b.Repo.GetByID(ctx, approvalId)
+	// start transactional
+	txnCtx := dbtxn.Begin(&ctx)
+	defer func() {
+		// Commit or Rollback transactional
+		if err := txnCtx.Commit(); err != nil {
+			logrus.Errorf("Error committing transaction: %v", err)
+		}
+	}()
+
+	// the row lock keeps two staff members from deciding the same level at once
+	approval, err := b.Repo.GetByIDForUpdate(ctx, approvalId)
 	if err != nil {
-		logrus.Errorf("Error fetching loan approval with ID: %d", approvalId)
+		logrus.Errorf("Error fetching loan approval with ID: %d: %v", approvalId, err)
 		return errors.New("99999")
 	}
 	if approval == nil {
@@ -103,18 +135,71 @@ func (b *LoanApprovalSvcImpl) Update(ctx context.Context, approvalId int64, requ
 		return errors.New("10003")
 	}
 
-	// start transactional
-	txnCtx := dbtxn.Begin(&ctx)
-	defer func() {
-		// Commit or Rollback transactional
-		if err := txnCtx.Commit(); err != nil {
-			logrus.Errorf("Error committing transaction: %v", err)
+	// a loan withdrawn by its borrower can not be decided anymore
+	loan, err := b.LoanRepo.GetByID(ctx, approval.LoanID)
+	if err != nil {
+		logrus.Errorf("Error fetching loan with ID: %d: %v", approval.LoanID, err)
+		return errors.New("99999")
+	}
+	if loan == nil {
+		logrus.Warnf("Loan not found with ID: %d", approval.LoanID)
+		return errors.New("10001")
+	}
+	if loan.LoanStatus != enum.Proposed {
+		logrus.Warnf("Loan ID: %d of loan approval ID: %d is %s, it can not be decided", loan.ID, approvalId, loan.LoanStatus)
+		return errors.New("10003")
+	}
+
+	// every level has to be decided by a different staff member
+	steps, err := b.ApprovalStepRepo.GetByApprovalID(ctx, approvalId)
+	if err != nil {
+		logrus.Errorf("Error fetching approval steps for loan approval ID: %d: %v", approvalId, err)
+		return errors.New("99999")
+	}
+	for _, step := range steps {
+		if step.StaffID == requestDTO.StaffID {
+			logrus.Warnf("Staff ID: %d already decided level %d of loan approval ID: %d", requestDTO.StaffID, step.Level, approvalId)
+			return errors.New("10014")
 		}
-	}()
+	}
+
+	err = b.resolveUploadedDocuments(ctx, approval, requestDTO.ApprovalDocuments)
+	if err != nil {
+		return err
+	}
+
+	if requestDTO.ApprovalStatus == enum.ApprovalApproved {
+		err = b.checkRequiredDocuments(ctx, approval, loan, requestDTO.ApprovalDocuments)
+		if err != nil {
+			return err
+		}
+	}
 
 	approvalDate := time.Now()
+	step := repo.ApprovalStep{
+		LoanApprovalID: approval.ID,
+		Level:          approval.ApprovedLevel + 1,
+		StaffID:        requestDTO.StaffID,
+		Decision:       requestDTO.ApprovalStatus,
+		DecidedAt:      approvalDate,
+	}
+
+	logrus.Infof("Recording level %d decision %s for loan approval ID: %d", step.Level, step.Decision, approvalId)
+	_, err = b.ApprovalStepRepo.Create(ctx, &step)
+	if err != nil {
+		logrus.Errorf("Error saving approval step for loan approval ID: %d: %v", approvalId, err)
+		txnCtx.AppendError(err)
+		return errors.New("99999")
+	}
+
+	// a rejection at any level ends the chain, an approval only ends it at the required level
+	if step.Decision == enum.ApprovalApproved {
+		approval.ApprovedLevel = step.Level
+	}
+	if step.Decision == enum.ApprovalRejected || approval.ApprovedLevel >= approval.RequiredLevel {
+		approval.ApprovalStatus = step.Decision
+	}
 	approval.StaffID = &requestDTO.StaffID
-	approval.ApprovalStatus = requestDTO.ApprovalStatus
 	approval.ApprovalDate = &approvalDate
 	approval.UpdatedAt = approvalDate
 
@@ -150,9 +235,15 @@ func (b *LoanApprovalSvcImpl) Update(ctx context.Context, approvalId int64, requ
 		}
 	}
 
+	// the loan only moves once the chain is decided
+	if approval.ApprovalStatus == enum.ApprovalPending {
+		logrus.Infof("Loan approval with ID: %d approved at level %d of %d", approvalId, approval.ApprovedLevel, approval.RequiredLevel)
+		return nil
+	}
+
 	// publish kafka loan update
 	logrus.Infof("Publishing loan update to Kafka for approval ID: %d", approvalId)
-	err = b.publishLoanApproval(ctx, approvalId, approval.ApprovalStatus, err)
+	err = b.publishLoanApproval(ctx, approval.LoanID, approval.ApprovalStatus, err)
 	if err != nil {
 		txnCtx.AppendError(err)
 		return errors.New("99999")
@@ -162,17 +253,70 @@ func (b *LoanApprovalSvcImpl) Update(ctx context.Context, approvalId int64, requ
 	return nil
 }
 
-func (b *LoanApprovalSvcImpl) publishLoanApproval(ctx context.Context, approvalId int64, approvalStatus enum.ApprovalStatus, err error) error {
+// resolveUploadedDocuments fills the documents referring to an upload from the document record, the
+// upload has to belong to the approval
+func (b *LoanApprovalSvcImpl) resolveUploadedDocuments(ctx context.Context, approval *repo.LoanApproval, documents []dto.ApprovalDocumentRequestDTO) error {
+	for i := range documents {
+		if documents[i].DocumentID == nil {
+			continue
+		}
+
+		document, err := b.DocumentSvc.GetLinked(ctx, *documents[i].DocumentID, enum.DocumentEntityLoanApproval, approval.ID)
+		if err != nil {
+			logrus.Warnf("Document ID: %d can not be attached to loan approval ID: %d", *documents[i].DocumentID, approval.ID)
+			return err
+		}
+
+		documents[i].FileURL = document.URL
+		if documents[i].DocumentType == "" {
+			documents[i].DocumentType = document.DocumentType
+		}
+	}
+
+	return nil
+}
+
+// checkRequiredDocuments refuses an approval while a mandatory document of the loan type is neither
+// attached to the approval yet nor sent with the decision, the error names every missing document
+func (b *LoanApprovalSvcImpl) checkRequiredDocuments(ctx context.Context, approval *repo.LoanApproval, loan *repo.Loan, documents []dto.ApprovalDocumentRequestDTO) error {
+	attached, err := b.ApprovalDocumentRepo.GetByApprovalID(ctx, approval.ID)
+	if err != nil {
+		logrus.Errorf("Error fetching documents for loan approval ID: %d: %v", approval.ID, err)
+		return errors.New("99999")
+	}
+
+	var documentTypes []string
+	for _, document := range attached {
+		documentTypes = append(documentTypes, document.DocumentType)
+	}
+	for _, document := range documents {
+		documentTypes = append(documentTypes, document.DocumentType)
+	}
+
+	missing, err := b.DocumentRequirementSvc.MissingDocuments(ctx, loan.LoanType, documentTypes)
+	if err != nil {
+		logrus.Errorf("Error checking required documents for loan approval ID: %d: %v", approval.ID, err)
+		return errors.New("99999")
+	}
+	if len(missing) > 0 {
+		logrus.Warnf("Loan approval ID: %d is missing required documents: %v", approval.ID, missing)
+		return &dto.DetailError{Code: "10015", Details: missing}
+	}
+
+	return nil
+}
+
+func (b *LoanApprovalSvcImpl) publishLoanApproval(ctx context.Context, loanID int64, approvalStatus enum.ApprovalStatus, err error) error {
 	// publish kafka for update loan
 	req := message2.UpdateLoanMessage{
-		LoanID:     approvalId,
+		LoanID:     loanID,
 		LoanStatus: enum.LoanStatus(approvalStatus),
 	}
 
-	logrus.Infof("Marshalling loan update message for approval ID: %d", approvalId)
+	logrus.Infof("Marshalling loan update message for loan ID: %d", loanID)
 	jsonData, err := json.Marshal(req)
 	if err != nil {
-		logrus.Errorf("Failed to marshal loan update message for approval ID: %d: %v", approvalId, err)
+		logrus.Errorf("Failed to marshal loan update message for loan ID: %d: %v", loanID, err)
 		return errors.New("99999")
 	}
 
@@ -189,7 +333,7 @@ func (b *LoanApprovalSvcImpl) publishLoanApproval(ctx context.Context, approvalI
 		return errors.New("99999")
 	}
 
-	logrus.Infof("Successfully published loan update for approval ID: %d", approvalId)
+	logrus.Infof("Successfully published loan update for loan ID: %d", loanID)
 	return nil
 }
 
@@ -228,6 +372,26 @@ func (b *LoanApprovalSvcImpl) GetAllPage(ctx context.Context, request models.Loa
 		approvalRes.UpdatedAt = approval.UpdatedAt
 		approvalRes.DeletedAt = approval.DeletedAt
 
+		var steps []repo.ApprovalStep
+		steps, err = b.ApprovalStepRepo.GetByApprovalID(ctx, approval.ID)
+		if err != nil {
+			logrus.Errorf("Error fetching approval steps for loan approval ID: %d: %v", approval.ID, err)
+			return nil, 0, errors.New("99999")
+		}
+
+		approvalRes.ApprovalSteps = []dto.ApprovalStepResponseDTO{}
+		for _, step := range steps {
+			var stepDto dto.ApprovalStepResponseDTO
+			err = mapstructure.Decode(step, &stepDto)
+			if err != nil {
+				logrus.Errorf("Error decoding approval step to DTO: %v", err)
+				return nil, 0, errors.New("99999")
+			}
+			stepDto.DecidedAt = step.DecidedAt
+
+			approvalRes.ApprovalSteps = append(approvalRes.ApprovalSteps, stepDto)
+		}
+
 		// Fetch documents if approval is approved
 		if approvalRes.ApprovalStatus == enum.ApprovalApproved {
 			logrus.Infof("Fetching documents for approved loan ID: %d", approval.ID)
@@ -33,6 +33,9 @@ type (
 		Repo          repo.LoanDisbursementRepo
 		LoanRepo      repo.LoanRepo
 		LoanDetailSvc LoanDetailSvc
+		FeePolicySvc  FeePolicySvc
+		AgreementSvc  AgreementSvc
+		SignatureSvc  SignatureSvc
 		KafkaWriter   *kafka.Writer
 		Validator     validator.LoanDisbursementValidatorImpl
 	}
@@ -43,7 +46,7 @@ func NewLoanDisbursementSvc(impl LoanDisbursementSvcImpl) LoanDisbursementSvc {
 }
 
 func (b *LoanDisbursementSvcImpl) Create(ctx context.Context, disbursementRequest *dto.LoanDisbursementRequestDTO) error {
-	log.Printf("Create loan disbursement: LoanID=%d, DisburseAmount=%f", disbursementRequest.LoanID, disbursementRequest.DisburseAmount)
+	log.Printf("Create loan disbursement: LoanID=%d, DisburseAmount=%s", disbursementRequest.LoanID, disbursementRequest.DisburseAmount)
 
 	// Validate request
 	err := b.Validator.ValidateCreate(disbursementRequest)
@@ -59,19 +62,57 @@ func (b *LoanDisbursementSvcImpl) Create(ctx context.Context, disbursementReques
 		return err
 	}
 
+	loan, err := b.LoanRepo.GetByID(ctx, disbursement.LoanID)
+	if err != nil || loan == nil {
+		log.Printf("Error retrieving loan %d for disbursement: %v", disbursement.LoanID, err)
+		return errors.New("99999")
+	}
+
+	// the origination fee is deducted from what the borrower receives, the borrower still owes the
+	// whole disbursed amount
+	disbursement.OriginationFee, err = b.FeePolicySvc.OriginationFee(ctx, loan.LoanType, disbursement.DisburseAmount)
+	if err != nil {
+		log.Printf("Error computing origination fee: %v", err)
+		return errors.New("99999")
+	}
+	disbursement.NetDisburseAmount = disbursement.DisburseAmount - disbursement.OriginationFee
+
 	disbursement.DisburseCode = utils.GenerateAlphanumericCode(10)
 	disbursement.DisbursementStatus = enum.LoanDisbursementPending
-	disbursement.AgreementURL = "http://google.com"
 	disbursement.CreatedAt = time.Now()
 	disbursement.UpdatedAt = time.Now()
 
-	_, err = b.Repo.Create(ctx, &disbursement)
+	// legal requires a real agreement before any disbursement, the borrower signs the generated
+	// document and the signed copy is attached when the disbursement is completed
+	agreement, err := b.AgreementSvc.GenerateLoanAgreement(ctx, loan, &disbursement)
+	if err != nil {
+		log.Printf("Error generating loan agreement: %v", err)
+		return err
+	}
+	disbursement.AgreementURL = agreement.DocumentURL
+
+	disbursement.ID, err = b.Repo.Create(ctx, &disbursement)
 	if err != nil {
 		log.Printf("Error creating loan disbursement in repo: %v", err)
 		return err
 	}
 
-	log.Printf("Loan disbursement created successfully: DisbursementCode=%s", disbursement.DisburseCode)
+	// the disbursement can only be completed once the borrower has signed through the provider
+	_, err = b.SignatureSvc.RequestSignature(ctx, loan, &disbursement, agreement)
+	if err != nil {
+		log.Printf("Error requesting agreement signature: %v", err)
+		return err
+	}
+
+	loan.AgreementLetterLink = &agreement.DocumentURL
+	err = b.LoanRepo.Update(ctx, loan)
+	if err != nil {
+		log.Printf("Error updating loan agreement letter link: %v", err)
+		return err
+	}
+
+	log.Printf("Loan disbursement created successfully: DisbursementCode=%s, OriginationFee=%s, NetDisburseAmount=%s",
+		disbursement.DisburseCode, disbursement.OriginationFee, disbursement.NetDisburseAmount)
 	return nil
 }
 
@@ -102,9 +143,22 @@ func (b *LoanDisbursementSvcImpl) Update(ctx context.Context, disbursementID int
 		return errors.New("10003")
 	}
 
+	if disbursementRequest.DisbursementStatus == enum.LoanDisbursementCompleted {
+		// the signed agreement is the generated one, the signature hash was verified against it
+		signature, err := b.SignatureSvc.GetValidSignature(ctx, disbursement.ID)
+		if err != nil {
+			log.Printf("Error retrieving agreement signature: %v", err)
+			return err
+		}
+		if signature == nil {
+			log.Printf("Agreement not signed: DisbursementID=%d", disbursementID)
+			return errors.New("10013")
+		}
+		disbursement.SignedAgreementURL = &disbursement.AgreementURL
+	}
+
 	disbursement.DisbursementStatus = disbursementRequest.DisbursementStatus
 	disbursement.StaffID = &disbursementRequest.StaffID
-	disbursement.SignedAgreementURL = &disbursementRequest.SignedAgreementURL
 
 	now := time.Now()
 	disbursement.DisburseDate = &now
@@ -0,0 +1,249 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	log "github.com/sirupsen/logrus"
+	"github.com/test/loan-service/internal/dto"
+	"github.com/test/loan-service/internal/enum"
+	repo "github.com/test/loan-service/internal/repository"
+	"github.com/test/loan-service/internal/service/validator"
+	"github.com/typical-go/typical-rest-server/pkg/dbtxn"
+	"go.uber.org/dig"
+	"time"
+)
+
+//go:generate mockgen -source=$GOFILE -destination=$PROJ/internal/generated/mock/mock_$GOPACKAGE/$GOFILE
+
+type (
+	LoanExpirySvc interface {
+		ExpireOverdueLoans(ctx context.Context) (int, error)
+	}
+
+	LoanExpirySvcImpl struct {
+		dig.In
+		LoanRepo         repo.LoanRepo
+		LoanFundingRepo  repo.LoanFundingRepo
+		FundingPolicySvc FundingPolicySvc
+		DisburseSvc      LoanDisbursementSvc
+		LedgerSvc        LedgerSvc
+		WalletSvc        LenderWalletSvc
+		MailSvc          EmailSvc
+		LoanValidator    validator.LoanValidatorImpl
+	}
+)
+
+func NewLoanExpirySvc(impl LoanExpirySvcImpl) LoanExpirySvc {
+	return &impl
+}
+
+// ExpireOverdueLoans closes the funding of every approved loan whose funding deadline has passed.
+// Loans allowed by the funding policy of their loan type are disbursed with the funded amount, the
+// others are expired. It returns the number of expired loans. Each loan is processed in its own
+// transaction so one failure does not block the others.
+func (s *LoanExpirySvcImpl) ExpireOverdueLoans(ctx context.Context) (int, error) {
+	loans, err := s.LoanRepo.GetApprovedPastDeadline(ctx, time.Now())
+	if err != nil {
+		log.WithError(err).Error("Failed to get loans past funding deadline")
+		return 0, errors.New("99999")
+	}
+
+	if len(loans) == 0 {
+		log.Info("No loan past funding deadline")
+		return 0, nil
+	}
+
+	expired, partiallyFunded := 0, 0
+	for _, loan := range loans {
+		status, fundings, err := s.closeFunding(ctx, loan.ID)
+		if err != nil {
+			log.WithField("loanID", loan.ID).WithError(err).Error("Failed to close loan funding")
+			continue
+		}
+		switch status {
+		case enum.Expired:
+			expired++
+		case enum.Invested:
+			partiallyFunded++
+		default:
+			continue
+		}
+
+		// lenders are notified only after the transaction has been committed
+		loan.LoanStatus = status
+		s.notifyLenders(ctx, loan, fundings)
+	}
+
+	log.WithFields(log.Fields{
+		"found":           len(loans),
+		"expired":         expired,
+		"partiallyFunded": partiallyFunded,
+	}).Info("Loan expiry finished")
+	return expired, nil
+}
+
+// closeFunding evaluates the funding policy of a loan past its deadline. When partial disbursement is
+// allowed the loan moves to invested, pending fundings fail and the disbursement is created with the
+// funded amount. Otherwise the loan moves to expired, invested fundings are refunded and pending
+// fundings fail. It returns the new loan status, empty when the loan no longer needs closing, and the
+// fundings whose lender must be notified.
+func (s *LoanExpirySvcImpl) closeFunding(ctx context.Context, loanID int64) (status enum.LoanStatus, fundings []repo.LoanFunding, err error) {
+	txnCtx := dbtxn.Begin(&ctx)
+	defer func() {
+		if commitErr := txnCtx.Commit(); commitErr != nil {
+			log.WithError(commitErr).Error("Transaction commit failed")
+			status, fundings, err = "", nil, commitErr
+		}
+	}()
+
+	// lock the loan so a funding process running at the same time can not invest into it
+	loan, err := s.LoanRepo.GetByIDForUpdate(ctx, loanID)
+	if err != nil || loan == nil {
+		txnCtx.AppendError(errors.New("loan not found"))
+		return "", nil, fmt.Errorf("loan %d not found: %v", loanID, err)
+	}
+
+	// the loan may have been fully funded since it was listed
+	if loan.LoanStatus != enum.Approved || loan.FundingDeadline == nil || !loan.FundingDeadline.Before(time.Now()) {
+		log.WithFields(log.Fields{
+			"loanID":     loan.ID,
+			"loanStatus": loan.LoanStatus,
+		}).Info("Loan no longer eligible for expiry, skipping")
+		return "", nil, nil
+	}
+
+	partialAllowed, err := s.FundingPolicySvc.IsPartialDisbursementAllowed(ctx, loan)
+	if err != nil {
+		txnCtx.AppendError(err)
+		return "", nil, err
+	}
+
+	status = enum.Expired
+	if partialAllowed {
+		status = enum.Invested
+	}
+
+	isValid := s.LoanValidator.ValidateTransitionStatus(loan.LoanStatus, status)
+	if !isValid {
+		log.WithFields(log.Fields{
+			"currentStatus": loan.LoanStatus,
+			"newStatus":     status,
+		}).Error("Invalid status transition")
+		txnCtx.AppendError(errors.New("invalid status transition"))
+		return "", nil, errors.New("invalid status transition")
+	}
+
+	loan.LoanStatus = status
+	loan.UpdatedAt = time.Now()
+	err = s.LoanRepo.Update(ctx, loan)
+	if err != nil {
+		txnCtx.AppendError(err)
+		return "", nil, err
+	}
+
+	loanFundings, err := s.LoanFundingRepo.GetByLoanID(ctx, loan.ID)
+	if err != nil {
+		txnCtx.AppendError(err)
+		return "", nil, err
+	}
+
+	fundings = []repo.LoanFunding{}
+	for _, funding := range loanFundings {
+		switch funding.Status {
+		case enum.LoanFundingInvested:
+			if partialAllowed {
+				// the lender stays in the loan, it will be disbursed with the funded amount
+				continue
+			}
+			// the lender money has been taken, give it back
+			funding.Status = enum.LoanFundingRefunded
+		case enum.LoanFundingPending:
+			funding.Status = enum.LoanFundingFailed
+		default:
+			continue
+		}
+		funding.UpdatedAt = time.Now()
+
+		err = s.LoanFundingRepo.Update(ctx, &funding)
+		if err != nil {
+			log.WithField("loanFundingID", funding.ID).WithError(err).Error("Failed to update loan funding")
+			txnCtx.AppendError(err)
+			return "", nil, err
+		}
+
+		if funding.Status == enum.LoanFundingRefunded {
+			err = s.WalletSvc.RefundFunding(ctx, &funding)
+			if err != nil {
+				log.WithField("loanFundingID", funding.ID).WithError(err).Error("Failed to refund lender wallet")
+				txnCtx.AppendError(err)
+				return "", nil, err
+			}
+
+			err = s.LedgerSvc.RecordFundingRefunded(ctx, &funding)
+			if err != nil {
+				log.WithField("loanFundingID", funding.ID).WithError(err).Error("Failed to record refund in ledger")
+				txnCtx.AppendError(err)
+				return "", nil, err
+			}
+		} else {
+			// the pending funding never got invested, its hold goes back to the lender
+			err = s.WalletSvc.ReleaseFunding(ctx, &funding)
+			if err != nil {
+				log.WithField("loanFundingID", funding.ID).WithError(err).Error("Failed to release wallet hold")
+				txnCtx.AppendError(err)
+				return "", nil, err
+			}
+		}
+		fundings = append(fundings, funding)
+	}
+
+	if partialAllowed {
+		// init disburse with the funded amount only
+		disburseRequest := dto.LoanDisbursementRequestDTO{
+			LoanID:         loan.ID,
+			DisburseAmount: loan.TotalInvestedAmount,
+		}
+		err = s.DisburseSvc.Create(ctx, &disburseRequest)
+		if err != nil {
+			log.WithField("loanID", loan.ID).WithError(err).Error("Failed to init disburse")
+			txnCtx.AppendError(err)
+			return "", nil, err
+		}
+	}
+
+	log.WithFields(log.Fields{
+		"loanID":     loan.ID,
+		"loanStatus": loan.LoanStatus,
+		"fundings":   len(fundings),
+	}).Info("Loan funding closed successfully")
+	return status, fundings, nil
+}
+
+func (s *LoanExpirySvcImpl) notifyLenders(ctx context.Context, loan repo.Loan, fundings []repo.LoanFunding) {
+	for _, funding := range fundings {
+		if funding.LenderEmail == "" {
+			continue
+		}
+
+		subject := fmt.Sprintf("Loan %s expired", loan.LoanCode)
+		body := fmt.Sprintf("Loan %s did not reach its funding target before %s and has expired. "+
+			"Your funding %s of %s has been %s.",
+			loan.LoanCode, loan.FundingDeadline.Format("2006-01-02"), funding.LoanOrderNumber, funding.InvestmentAmount, funding.Status)
+		if loan.LoanStatus == enum.Invested {
+			subject = fmt.Sprintf("Loan %s funding closed", loan.LoanCode)
+			body = fmt.Sprintf("Loan %s funding closed on %s and the loan will be disbursed with the funded amount. "+
+				"Your funding %s of %s was not processed in time and has been %s.",
+				loan.LoanCode, loan.FundingDeadline.Format("2006-01-02"), funding.LoanOrderNumber, funding.InvestmentAmount, funding.Status)
+		}
+		email := SendEmailInput{
+			To:      []string{funding.LenderEmail},
+			Subject: subject,
+			Body:    body,
+		}
+		err := s.MailSvc.SendEmail(ctx, email)
+		if err != nil {
+			log.WithField("loanFundingID", funding.ID).WithError(err).Warn("Failed to notify lender of loan expiry")
+		}
+	}
+}
@@ -29,6 +29,7 @@ type (
 		FundingProcess(context.Context, message2.FundingProcessMessage) error
 		GetByID(ctx context.Context, id int64) (*dto.LoanFundingResponseDTO, error)
 		GetByLenderID(ctx context.Context, lenderID int64) ([]dto.LoanFundingResponseDTO, error)
+		Cancel(ctx context.Context, id int64, request *dto.LoanFundingCancelRequestDTO) error
 	}
 
 	AsyncResultData struct {
@@ -38,12 +39,21 @@ type (
 
 	LoanFundingSvcImpl struct {
 		dig.In
-		Repo        repo.LoanFundingRepo
-		LoanRepo    repo.LoanRepo
-		DisburseSvc LoanDisbursementSvc
-		KafkaWriter *kafka.Writer
-		MailSvc     EmailSvc
-		Validator   validator.LoanFundingValidatorImpl
+		Repo             repo.LoanFundingRepo
+		LoanRepo         repo.LoanRepo
+		DisburseSvc      LoanDisbursementSvc
+		LedgerSvc        LedgerSvc
+		WalletSvc        LenderWalletSvc
+		FeePolicySvc     FeePolicySvc
+		FundingPolicySvc FundingPolicySvc
+		TaxSvc           TaxSvc
+		LimitSvc         InvestmentLimitSvc
+		LenderSvc        LenderSvc
+		KafkaWriter      *kafka.Writer
+		MailSvc          EmailSvc
+		AgreementSvc     AgreementSvc
+		Validator        validator.LoanFundingValidatorImpl
+		CancelValidator  validator.LoanFundingCancelValidatorImpl
 	}
 )
 
@@ -76,6 +86,14 @@ func (s *LoanFundingSvcImpl) Create(ctx context.Context, request *dto.LoanFundin
 		return errors.New("10003")
 	}
 
+	// only a verified lender may fund, and only a grade its classification and risk profile allow
+	var lender *repo.Lender
+	lender, err = s.LenderSvc.GetEligible(ctx, request.LenderID, loan.LoanGrade)
+	if err != nil {
+		logrus.Warnf("LenderID %d can not fund LoanID %d: %s", request.LenderID, loan.ID, err)
+		return err
+	}
+
 	// Create loan funding
 	var loanFunding repo.LoanFunding
 	err = mapstructure.Decode(request, &loanFunding)
@@ -86,18 +104,33 @@ func (s *LoanFundingSvcImpl) Create(ctx context.Context, request *dto.LoanFundin
 
 	loanFunding.LoanOrderNumber = utils.GenerateAlphanumericCode(10)
 	loanFunding.LenderID = request.LenderID
+	loanFunding.LenderEmail = lender.Email
 	loanFunding.Status = enum.LoanFundingPending
 	now := time.Now()
 	loanFunding.InvestmentDate = now
 	loanFunding.CreatedAt = now
 	loanFunding.UpdatedAt = now
 
+	// Check the ticket size and the concentration of the lender before anything is held
+	err = s.LimitSvc.ValidateTicket(ctx, loan, loanFunding.InvestmentAmount)
+	if err != nil {
+		return err
+	}
+	err = s.LimitSvc.ValidateConcentration(ctx, loan, &loanFunding)
+	if err != nil {
+		return err
+	}
+
 	// Log creation attempt
 	logrus.Infof("Creating loan funding for LoanID %d, LoanOrderNumber %s", loan.ID, loanFunding.LoanOrderNumber)
 
-	// Create initial loan funding
-	_, err = s.Repo.Create(ctx, &loanFunding)
+	// Create initial loan funding and hold the investment in the lender wallet
+	err = s.createWithHold(ctx, &loanFunding)
 	if err != nil {
+		if err.Error() == "10004" {
+			logrus.Warnf("Insufficient wallet balance for LenderID %d to fund LoanID %d", request.LenderID, request.LoanID)
+			return err
+		}
 		logrus.Errorf("Failed to create loan funding for LoanID %d: %v", request.LoanID, err)
 		return errors.New("99999")
 	}
@@ -109,6 +142,8 @@ func (s *LoanFundingSvcImpl) Create(ctx context.Context, request *dto.LoanFundin
 	err = s.publishFundingProcess(ctx, loanFunding)
 	if err != nil {
 		logrus.Errorf("Failed to publish funding process for LoanOrderNumber %s: %v", loanFunding.LoanOrderNumber, err)
+		// nothing would ever process the funding, undo it so a retry does not hold the money twice
+		s.failUnpublished(ctx, loanFunding.ID)
 		return errors.New("99999")
 	}
 
@@ -116,6 +151,75 @@ func (s *LoanFundingSvcImpl) Create(ctx context.Context, request *dto.LoanFundin
 	return nil
 }
 
+// createWithHold creates the pending funding and holds its investment in the same transaction, the
+// transaction is committed before the funding process is published
+func (s *LoanFundingSvcImpl) createWithHold(ctx context.Context, loanFunding *repo.LoanFunding) (err error) {
+	txnCtx := dbtxn.Begin(&ctx)
+	defer func() {
+		if commitErr := txnCtx.Commit(); commitErr != nil {
+			logrus.Errorf("Failed to commit transaction for LoanOrderNumber %s: %v", loanFunding.LoanOrderNumber, commitErr)
+			if err == nil {
+				err = commitErr
+			}
+		}
+	}()
+
+	loanFunding.ID, err = s.Repo.Create(ctx, loanFunding)
+	if err != nil {
+		txnCtx.AppendError(err)
+		return err
+	}
+
+	err = s.WalletSvc.HoldFunding(ctx, loanFunding)
+	if err != nil {
+		txnCtx.AppendError(err)
+		return err
+	}
+
+	return nil
+}
+
+// failUnpublished marks a funding whose process could not be published as failed and releases its hold.
+// A funding the process already picked up is left as it is, the process skips a funding marked failed.
+func (s *LoanFundingSvcImpl) failUnpublished(ctx context.Context, id int64) {
+	txnCtx := dbtxn.Begin(&ctx)
+	defer func() {
+		if commitErr := txnCtx.Commit(); commitErr != nil {
+			logrus.Errorf("Failed to commit failing of unpublished loan funding %d: %v", id, commitErr)
+		}
+	}()
+
+	loanFunding, err := s.Repo.GetByIDForUpdate(ctx, id)
+	if err != nil || loanFunding == nil {
+		logrus.Errorf("Failed to lock unpublished loan funding %d: %v", id, err)
+		txnCtx.AppendError(errors.New("loan funding not found"))
+		return
+	}
+
+	if loanFunding.Status != enum.LoanFundingPending {
+		logrus.Infof("Unpublished loan funding %d is already %s", id, loanFunding.Status)
+		return
+	}
+
+	loanFunding.Status = enum.LoanFundingFailed
+	loanFunding.UpdatedAt = time.Now()
+	err = s.Repo.Update(ctx, loanFunding)
+	if err != nil {
+		logrus.Errorf("Failed to update unpublished loan funding %d: %v", id, err)
+		txnCtx.AppendError(err)
+		return
+	}
+
+	err = s.WalletSvc.ReleaseFunding(ctx, loanFunding)
+	if err != nil {
+		logrus.Errorf("Failed to release wallet hold for LoanOrderNumber %s: %v", loanFunding.LoanOrderNumber, err)
+		txnCtx.AppendError(err)
+		return
+	}
+
+	logrus.Infof("Unpublished loan funding %d failed and its hold released", id)
+}
+
 func (s *LoanFundingSvcImpl) GetByID(ctx context.Context, id int64) (*dto.LoanFundingResponseDTO, error) {
 	// Get loan funding by ID
 	loanFunding, err := s.Repo.GetByID(ctx, id)
@@ -140,6 +244,7 @@ func (s *LoanFundingSvcImpl) GetByID(ctx context.Context, id int64) (*dto.LoanFu
 	loanFundingRes.CreatedAt = loanFunding.CreatedAt
 	loanFundingRes.UpdatedAt = loanFunding.UpdatedAt
 	loanFundingRes.DeletedAt = loanFunding.DeletedAt
+	loanFundingRes.InvestedAt = loanFunding.InvestedAt
 
 	logrus.Infof("Loan funding found for ID %d", id)
 	return &loanFundingRes, nil
@@ -164,6 +269,7 @@ func (s *LoanFundingSvcImpl) GetByLenderID(ctx context.Context, lenderID int64)
 		loanFundingRes.CreatedAt = loanFunding.CreatedAt
 		loanFundingRes.UpdatedAt = loanFunding.UpdatedAt
 		loanFundingRes.DeletedAt = loanFunding.DeletedAt
+		loanFundingRes.InvestedAt = loanFunding.InvestedAt
 
 		loanFundingResponses = append(loanFundingResponses, loanFundingRes)
 	}
@@ -172,7 +278,153 @@ func (s *LoanFundingSvcImpl) GetByLenderID(ctx context.Context, lenderID int64)
 	return loanFundingResponses, nil
 }
 
+// Cancel undoes a funding on request of its lender. A pending funding can always be cancelled and its
+// hold is released. An invested funding can only be cancelled within the cooling-off period of the loan
+// type and while the loan is still collecting funds, its investment is taken off the loan and refunded.
+func (s *LoanFundingSvcImpl) Cancel(ctx context.Context, id int64, request *dto.LoanFundingCancelRequestDTO) (err error) {
+	logrus.Infof("Cancelling loan funding %d for LenderID %d", id, request.LenderID)
+
+	err = s.CancelValidator.ValidateCreate(request)
+	if err != nil {
+		logrus.Warnf("Loan funding cancellation validation failed: %s", err)
+		return err
+	}
+
+	loanFunding, err := s.Repo.GetByID(ctx, id)
+	if err != nil || loanFunding == nil {
+		logrus.Warnf("Loan funding not found for ID %d: %v", id, err)
+		return errors.New("10001")
+	}
+
+	txnCtx := dbtxn.Begin(&ctx)
+	defer func() {
+		if commitErr := txnCtx.Commit(); commitErr != nil {
+			logrus.Errorf("Failed to commit cancellation of loan funding %d: %v", id, commitErr)
+			err = errors.New("99999")
+		}
+	}()
+
+	// lock the loan then the funding, the same order as the funding process
+	loan, err := s.LoanRepo.GetByIDForUpdate(ctx, loanFunding.LoanID)
+	if err != nil || loan == nil {
+		logrus.Errorf("Failed to lock loan for LoanID %d: %v", loanFunding.LoanID, err)
+		txnCtx.AppendError(errors.New("loan not found"))
+		return errors.New("99999")
+	}
+	loanFunding, err = s.Repo.GetByIDForUpdate(ctx, id)
+	if err != nil || loanFunding == nil {
+		logrus.Errorf("Failed to lock loan funding %d: %v", id, err)
+		txnCtx.AppendError(errors.New("loan funding not found"))
+		return errors.New("99999")
+	}
+
+	if loanFunding.LenderID != request.LenderID {
+		logrus.Warnf("Loan funding %d does not belong to LenderID %d", id, request.LenderID)
+		txnCtx.AppendError(errors.New("loan funding does not belong to the lender"))
+		return errors.New("10001")
+	}
+
+	now := time.Now()
+	switch loanFunding.Status {
+	case enum.LoanFundingPending:
+		// the funding process skips a funding that is no longer pending
+		loanFunding.Status = enum.LoanFundingCancelled
+		loanFunding.UpdatedAt = now
+		err = s.Repo.Update(ctx, loanFunding)
+		if err != nil {
+			logrus.Errorf("Failed to update loan funding %d: %v", id, err)
+			txnCtx.AppendError(err)
+			return errors.New("99999")
+		}
+
+		err = s.WalletSvc.ReleaseFunding(ctx, loanFunding)
+		if err != nil {
+			logrus.Errorf("Failed to release wallet hold for LoanOrderNumber %s: %v", loanFunding.LoanOrderNumber, err)
+			txnCtx.AppendError(err)
+			return errors.New("99999")
+		}
+	case enum.LoanFundingInvested:
+		if loan.LoanStatus != enum.Approved {
+			logrus.Warnf("Loan %d is %s, its fundings can not be cancelled anymore", loan.ID, loan.LoanStatus)
+			txnCtx.AppendError(errors.New("loan no longer collecting funds"))
+			return errors.New("10003")
+		}
+
+		coolingOff, err := s.FundingPolicySvc.CoolingOffPeriod(ctx, loan.LoanType)
+		if err != nil {
+			txnCtx.AppendError(err)
+			return errors.New("99999")
+		}
+		// fundings invested before invested_at was recorded fall back to the order date
+		investedAt := loanFunding.InvestmentDate
+		if loanFunding.InvestedAt != nil {
+			investedAt = *loanFunding.InvestedAt
+		}
+		if !now.Before(investedAt.Add(coolingOff)) {
+			logrus.Warnf("Cooling-off period of loan funding %d ended at %s", id, investedAt.Add(coolingOff))
+			txnCtx.AppendError(errors.New("cooling-off period ended"))
+			return errors.New("10020")
+		}
+
+		loan.TotalInvestedAmount = loan.TotalInvestedAmount - loanFunding.InvestmentAmount
+		loan.InvestorCount -= int64(1)
+		loan.UpdatedAt = now
+		err = s.LoanRepo.Update(ctx, loan)
+		if err != nil {
+			logrus.Errorf("Failed to update loan for LoanID %d: %v", loan.ID, err)
+			txnCtx.AppendError(err)
+			return errors.New("99999")
+		}
+
+		loanFunding.Status = enum.LoanFundingCancelled
+		loanFunding.UpdatedAt = now
+		err = s.Repo.Update(ctx, loanFunding)
+		if err != nil {
+			logrus.Errorf("Failed to update loan funding %d: %v", id, err)
+			txnCtx.AppendError(err)
+			return errors.New("99999")
+		}
+
+		// the investment has been captured into escrow, give it back
+		err = s.WalletSvc.RefundFunding(ctx, loanFunding)
+		if err != nil {
+			logrus.Errorf("Failed to refund lender wallet for LoanOrderNumber %s: %v", loanFunding.LoanOrderNumber, err)
+			txnCtx.AppendError(err)
+			return errors.New("99999")
+		}
+
+		err = s.LedgerSvc.RecordFundingRefunded(ctx, loanFunding)
+		if err != nil {
+			logrus.Errorf("Failed to record refund in ledger for LoanOrderNumber %s: %v", loanFunding.LoanOrderNumber, err)
+			txnCtx.AppendError(err)
+			return errors.New("99999")
+		}
+	default:
+		logrus.Warnf("Loan funding %d is %s and can not be cancelled", id, loanFunding.Status)
+		txnCtx.AppendError(errors.New("loan funding can not be cancelled"))
+		return errors.New("10003")
+	}
+
+	logrus.Infof("Loan funding %d cancelled successfully", id)
+	return nil
+}
+
+// FundingProcess invests a pending funding, the lender receives the funding agreement once the funding
+// is committed
 func (s *LoanFundingSvcImpl) FundingProcess(ctx context.Context, message message2.FundingProcessMessage) error {
+	agreementEmail, err := s.processFunding(ctx, message)
+	if agreementEmail != nil {
+		// the funding stays invested when the email fails, the agreement can still be downloaded
+		if emailErr := s.MailSvc.SendEmail(ctx, *agreementEmail); emailErr != nil {
+			logrus.Errorf("Failed to email funding agreement for LoanOrderNumber %s: %v", message.LoanOrderNumber, emailErr)
+		}
+	}
+	return err
+}
+
+// processFunding invests or fails the funding in one transaction. The agreement email of an invested
+// funding is only returned when the transaction has been committed.
+func (s *LoanFundingSvcImpl) processFunding(ctx context.Context, message message2.FundingProcessMessage) (agreementEmail *SendEmailInput, err error) {
 	var wg sync.WaitGroup
 	resultCh := make(chan AsyncResultData, 2) // Channel dengan buffer untuk 2 hasil
 
@@ -222,21 +474,39 @@ func (s *LoanFundingSvcImpl) FundingProcess(ctx context.Context, message message
 
 	defer func() {
 		// Commit atau Rollback transaksi
-		err := txnCtx.Commit()
-		if err != nil {
-			logrus.Errorf("Failed to commit transaction for LoanID %d: %v", loan.ID, err)
+		commitErr := txnCtx.Commit()
+		if commitErr != nil {
+			logrus.Errorf("Failed to commit transaction for LoanID %d: %v", message.LoanID, commitErr)
+		}
+		// a funding that is rolled back gets no agreement email
+		if commitErr != nil || dbtxn.Error(ctx) != nil {
+			agreementEmail = nil
 		}
 	}()
 
 	// Cek jika loanFunding atau loan tidak ditemukan
 	if loanFunding == nil {
 		logrus.Errorf("Loan funding process failed for LoanID %d: LoanFunding not found", message.LoanID)
-		return fmt.Errorf("loan funding process failed for ID %d", message.LoanID)
+		return nil, fmt.Errorf("loan funding process failed for ID %d", message.LoanID)
 	}
 	if loan == nil {
 		logrus.Errorf("Loan process failed for LoanID %d: Loan not found", message.LoanID)
-		return fmt.Errorf("loan process failed for ID %d", message.LoanID)
+		return nil, fmt.Errorf("loan process failed for ID %d", message.LoanID)
+	}
+
+	// lock the loan then the funding, the same order as a cancellation, so a funding cancelled
+	// meanwhile is seen here as no longer pending
+	lockedLoan, err := s.LoanRepo.GetByIDForUpdate(ctx, loan.ID)
+	if err != nil || lockedLoan == nil {
+		logrus.Errorf("Failed to lock loan for LoanID %d: %v", loan.ID, err)
+		return nil, fmt.Errorf("loan process failed for ID %d", message.LoanID)
+	}
+	lockedFunding, err := s.Repo.GetByIDForUpdate(ctx, loanFunding.ID)
+	if err != nil || lockedFunding == nil {
+		logrus.Errorf("Failed to lock loan funding for LoanOrderNumber %s: %v", loanFunding.LoanOrderNumber, err)
+		return nil, fmt.Errorf("loan funding process failed for ID %d", message.LoanID)
 	}
+	loan, loanFunding = lockedLoan, lockedFunding
 
 	isEligible := false
 
@@ -261,9 +531,38 @@ func (s *LoanFundingSvcImpl) FundingProcess(ctx context.Context, message message
 			// Update loan funding
 			loanFunding.Rate = loan.InvestmentPercentage
 			loanFunding.Interest = utils.CalculateInterest(loanFunding.InvestmentAmount, loanFunding.Rate, loan.Tenures)
-			loanFunding.ROI = loanFunding.InvestmentAmount + loanFunding.Interest
+			// the service fee percentage is fixed at investment, a later policy change does not
+			// change the ROI promised to the lender
+			loanFunding.ServiceFeePercentage, err = s.FeePolicySvc.ServiceFeePercentage(ctx, loan.LoanType)
+			if err != nil {
+				txnCtx.AppendError(err)
+				logrus.Errorf("Failed to get service fee for LoanOrderNumber %s: %v", loanFunding.LoanOrderNumber, err)
+			}
+			loanFunding.ServiceFee = utils.CalculatePercentage(loanFunding.Interest, loanFunding.ServiceFeePercentage)
+			// the tax is an estimate with the tax profile of today, the actual tax is withheld on every
+			// repayment
+			withholding, err := s.TaxSvc.Calculate(ctx, loanFunding.LenderID, loanFunding.Interest)
+			if err != nil {
+				txnCtx.AppendError(err)
+				logrus.Errorf("Failed to calculate withholding tax for LoanOrderNumber %s: %v", loanFunding.LoanOrderNumber, err)
+			} else {
+				loanFunding.WithholdingTax = withholding.TaxAmount
+			}
+			loanFunding.ROI = loanFunding.InvestmentAmount + loanFunding.Interest - loanFunding.ServiceFee - loanFunding.WithholdingTax
+			investedAt := time.Now()
 			loanFunding.Status = enum.LoanFundingInvested
-			loanFunding.UpdatedAt = time.Now()
+			// the cooling-off period starts here, not when the funding was ordered
+			loanFunding.InvestedAt = &investedAt
+			loanFunding.UpdatedAt = investedAt
+
+			// the agreement is generated by the platform from the amounts locked above
+			agreement, agreementFile, err := s.AgreementSvc.GenerateFundingAgreement(ctx, loan, loanFunding)
+			if err != nil {
+				txnCtx.AppendError(err)
+				logrus.Errorf("Failed to generate funding agreement for LoanOrderNumber %s: %v", loanFunding.LoanOrderNumber, err)
+			} else {
+				loanFunding.LenderAgreementURL = agreement.DocumentURL
+			}
 
 			err = s.Repo.Update(ctx, loanFunding)
 			if err != nil {
@@ -271,6 +570,19 @@ func (s *LoanFundingSvcImpl) FundingProcess(ctx context.Context, message message
 				logrus.Errorf("Failed to update loan funding for LoanOrderNumber %s: %v", loanFunding.LoanOrderNumber, err)
 			}
 
+			// take the held investment and move it from the lender wallet into escrow
+			err = s.WalletSvc.CaptureFunding(ctx, loanFunding)
+			if err != nil {
+				txnCtx.AppendError(err)
+				logrus.Errorf("Failed to capture wallet hold for LoanOrderNumber %s: %v", loanFunding.LoanOrderNumber, err)
+			}
+
+			err = s.LedgerSvc.RecordFundingInvested(ctx, loanFunding)
+			if err != nil {
+				txnCtx.AppendError(err)
+				logrus.Errorf("Failed to record ledger entry for LoanOrderNumber %s: %v", loanFunding.LoanOrderNumber, err)
+			}
+
 			if loan.LoanStatus == enum.Invested {
 				// init disburse
 				disburseRequest := dto.LoanDisbursementRequestDTO{
@@ -284,16 +596,23 @@ func (s *LoanFundingSvcImpl) FundingProcess(ctx context.Context, message message
 				}
 			}
 
-			// todo trigger email
-			email := SendEmailInput{
-				To:      []string{loanFunding.LenderEmail},
-				Subject: "aggrement",
-				Body:    "hello world",
+			if agreementFile != nil {
+				agreementEmail = &SendEmailInput{
+					To:      []string{loanFunding.LenderEmail},
+					Subject: fmt.Sprintf("Investment agreement %s", agreement.AgreementNumber),
+					Body: fmt.Sprintf("Your funding %s of %s in loan %s is invested. The expected return is %s, "+
+						"the investment agreement is attached and can also be downloaded from %s.",
+						loanFunding.LoanOrderNumber, loanFunding.InvestmentAmount, loan.LoanCode, loanFunding.ROI, agreement.DocumentURL),
+					Attachments: []EmailAttachment{{
+						FileName:    agreementFile.FileName,
+						ContentType: agreementFile.ContentType,
+						Content:     agreementFile.Content,
+					}},
+				}
 			}
-			_ = s.MailSvc.SendEmail(ctx, email)
 
-		} else {
-			// update loan funding to failed
+		} else if loanFunding.Status == enum.LoanFundingPending {
+			// update loan funding to failed, a funding already processed keeps its status and hold
 			loanFunding.Status = enum.LoanFundingFailed
 			loanFunding.UpdatedAt = time.Now()
 			err := s.Repo.Update(ctx, loanFunding)
@@ -301,38 +620,51 @@ func (s *LoanFundingSvcImpl) FundingProcess(ctx context.Context, message message
 				txnCtx.AppendError(err)
 				logrus.Errorf("Failed to update loan funding for LoanOrderNumber %s: %v", loanFunding.LoanOrderNumber, err)
 			}
+
+			// make the held investment available to the lender again
+			err = s.WalletSvc.ReleaseFunding(ctx, loanFunding)
+			if err != nil {
+				txnCtx.AppendError(err)
+				logrus.Errorf("Failed to release wallet hold for LoanOrderNumber %s: %v", loanFunding.LoanOrderNumber, err)
+			}
 		}
 	}()
 
 	// Cek status funding
 	if loanFunding.Status != enum.LoanFundingPending {
 		logrus.Infof("Loan funding for LoanID %d is not pending", message.LoanID)
-		return nil
+		return nil, nil
 	}
 
 	// Cek status loan
 	if loan.LoanStatus != enum.Approved {
 		logrus.Infof("Loan %d status is not approved, skipping funding process", loan.ID)
-		return nil
+		return nil, nil
 	}
 
 	// Cek funding deadline
 	if loan.FundingDeadline.Before(time.Now()) {
 		logrus.Warnf("Loan funding deadline for LoanID %d has passed", loan.ID)
-		return errors.New("loan already expired")
+		return nil, errors.New("loan already expired")
 	}
 
 	// Cek apakah total pembayaran melebihi jumlah yang diminta
 	if (loan.TotalInvestedAmount + loanFunding.InvestmentAmount) > loan.RequestAmount {
 		logrus.Warnf("Total repayment amount exceeds requested loan amount for LoanID %d", loan.ID)
-		return nil
+		return nil, nil
+	}
+
+	// Check the concentration again, other fundings of the lender may have been processed meanwhile
+	if err := s.LimitSvc.ValidateConcentration(ctx, loan, loanFunding); err != nil {
+		logrus.Warnf("Investment limit exceeded for LoanOrderNumber %s: %v", loanFunding.LoanOrderNumber, err)
+		return nil, nil
 	}
 
 	// passed all validation
 	isEligible = true
 
 	logrus.Infof("Funding process completed successfully for LoanID %d", loan.ID)
-	return nil
+	return nil, nil
 }
 
 func (b *LoanFundingSvcImpl) publishFundingProcess(ctx context.Context, funding repo.LoanFunding) error {
@@ -0,0 +1,267 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"github.com/mitchellh/mapstructure"
+	log "github.com/sirupsen/logrus"
+	"github.com/test/loan-service/internal/dto"
+	"github.com/test/loan-service/internal/enum"
+	repo "github.com/test/loan-service/internal/repository"
+	"github.com/test/loan-service/internal/service/validator"
+	"go.uber.org/dig"
+	"math"
+	"time"
+)
+
+//go:generate mockgen -source=$GOFILE -destination=$PROJ/internal/generated/mock/mock_$GOPACKAGE/$GOFILE
+
+type (
+	// LoanPricingSvc prices a loan by its grade, loan type and tenure. Every pricing row is a version
+	// referenced by the loans created with it, so a pricing in effect can only be ended, a new price is
+	// a new version.
+	LoanPricingSvc interface {
+		GetApplicable(ctx context.Context, grade enum.LoanGrade, loanType enum.LoanType, tenure int64) (*repo.LoanPricing, error)
+		GetByID(ctx context.Context, id int64) (*dto.LoanPricingResponseDTO, error)
+		GetAll(ctx context.Context, request repo.LoanPricingRequest) ([]dto.LoanPricingResponseDTO, error)
+		Create(ctx context.Context, request *dto.LoanPricingRequestDTO) (*dto.LoanPricingResponseDTO, error)
+		Update(ctx context.Context, id int64, request *dto.LoanPricingRequestDTO) (*dto.LoanPricingResponseDTO, error)
+		Delete(ctx context.Context, id int64) error
+	}
+
+	LoanPricingSvcImpl struct {
+		dig.In
+		Repo      repo.LoanPricingRepo
+		Validator validator.LoanPricingValidatorImpl
+	}
+)
+
+func NewLoanPricingSvc(impl LoanPricingSvcImpl) LoanPricingSvc {
+	return &impl
+}
+
+// GetApplicable returns the pricing in effect now, 10005 when no pricing covers the loan
+func (s *LoanPricingSvcImpl) GetApplicable(ctx context.Context, grade enum.LoanGrade, loanType enum.LoanType, tenure int64) (*repo.LoanPricing, error) {
+	pricing, err := s.Repo.GetApplicable(ctx, grade, loanType, tenure, time.Now())
+	if err != nil {
+		log.WithFields(log.Fields{
+			"loanGrade": grade,
+			"loanType":  loanType,
+			"tenure":    tenure,
+		}).WithError(err).Error("Failed to get loan pricing")
+		return nil, errors.New("99999")
+	}
+	if pricing == nil {
+		log.WithFields(log.Fields{
+			"loanGrade": grade,
+			"loanType":  loanType,
+			"tenure":    tenure,
+		}).Warn("No loan pricing in effect")
+		return nil, errors.New("10005")
+	}
+
+	return pricing, nil
+}
+
+func (s *LoanPricingSvcImpl) GetByID(ctx context.Context, id int64) (*dto.LoanPricingResponseDTO, error) {
+	pricing, err := s.Repo.GetByID(ctx, id)
+	if err != nil {
+		log.WithField("pricingID", id).WithError(err).Error("Failed to get loan pricing")
+		return nil, errors.New("99999")
+	}
+	if pricing == nil {
+		log.WithField("pricingID", id).Warn("Loan pricing not found")
+		return nil, errors.New("10001")
+	}
+
+	pricingRes, err := s.toResponseDTO(*pricing)
+	if err != nil {
+		return nil, errors.New("99999")
+	}
+
+	return pricingRes, nil
+}
+
+func (s *LoanPricingSvcImpl) GetAll(ctx context.Context, request repo.LoanPricingRequest) ([]dto.LoanPricingResponseDTO, error) {
+	pricings, err := s.Repo.GetAll(ctx, request)
+	if err != nil {
+		log.WithFields(log.Fields{
+			"loanGrade": request.LoanGrade,
+			"loanType":  request.LoanType,
+		}).WithError(err).Error("Failed to get loan pricings")
+		return nil, errors.New("99999")
+	}
+
+	pricingDTOs := []dto.LoanPricingResponseDTO{}
+	for _, pricing := range pricings {
+		pricingRes, err := s.toResponseDTO(pricing)
+		if err != nil {
+			return nil, errors.New("99999")
+		}
+		pricingDTOs = append(pricingDTOs, *pricingRes)
+	}
+
+	return pricingDTOs, nil
+}
+
+// Create adds a pricing version, it takes effect at ValidFrom or immediately when empty
+func (s *LoanPricingSvcImpl) Create(ctx context.Context, request *dto.LoanPricingRequestDTO) (*dto.LoanPricingResponseDTO, error) {
+	log.WithFields(log.Fields{
+		"loanGrade":    request.LoanGrade,
+		"loanType":     request.LoanType,
+		"borrowerRate": request.BorrowerRate,
+		"lenderYield":  request.LenderYield,
+	}).Info("Creating loan pricing")
+
+	err := s.Validator.ValidateCreate(request)
+	if err != nil {
+		log.WithField("loanGrade", request.LoanGrade).Errorf("Validation failed: %s", err)
+		return nil, err
+	}
+
+	now := time.Now()
+	pricing := repo.LoanPricing{
+		LoanGrade:    request.LoanGrade,
+		LoanType:     request.LoanType,
+		MinTenure:    request.MinTenure,
+		MaxTenure:    request.MaxTenure,
+		BorrowerRate: request.BorrowerRate,
+		LenderYield:  request.LenderYield,
+		ValidFrom:    now,
+		ValidUntil:   request.ValidUntil,
+		CreatedAt:    now,
+		UpdatedAt:    now,
+	}
+	if request.ValidFrom != nil {
+		pricing.ValidFrom = *request.ValidFrom
+	}
+
+	pricing.ID, err = s.Repo.Create(ctx, &pricing)
+	if err != nil {
+		log.WithField("loanGrade", request.LoanGrade).WithError(err).Error("Failed to create loan pricing")
+		return nil, errors.New("99999")
+	}
+
+	pricingRes, err := s.toResponseDTO(pricing)
+	if err != nil {
+		return nil, errors.New("99999")
+	}
+
+	log.WithField("pricingID", pricing.ID).Info("Loan pricing created successfully")
+	return pricingRes, nil
+}
+
+// Update changes a pricing that has not taken effect yet. Once in effect, loans may already carry it,
+// so only its end date can still be changed.
+func (s *LoanPricingSvcImpl) Update(ctx context.Context, id int64, request *dto.LoanPricingRequestDTO) (*dto.LoanPricingResponseDTO, error) {
+	log.WithFields(log.Fields{
+		"pricingID":    id,
+		"borrowerRate": request.BorrowerRate,
+		"lenderYield":  request.LenderYield,
+	}).Info("Updating loan pricing")
+
+	err := s.Validator.ValidateUpdate(request)
+	if err != nil {
+		log.WithField("pricingID", id).Errorf("Validation failed: %s", err)
+		return nil, err
+	}
+
+	pricing, err := s.Repo.GetByID(ctx, id)
+	if err != nil {
+		log.WithField("pricingID", id).WithError(err).Error("Failed to get loan pricing")
+		return nil, errors.New("99999")
+	}
+	if pricing == nil {
+		log.WithField("pricingID", id).Warn("Loan pricing not found")
+		return nil, errors.New("10001")
+	}
+
+	validFrom := pricing.ValidFrom
+	if request.ValidFrom != nil {
+		validFrom = *request.ValidFrom
+	}
+
+	if !pricing.ValidFrom.After(time.Now()) {
+		if request.LoanGrade != pricing.LoanGrade ||
+			request.LoanType != pricing.LoanType ||
+			request.MinTenure != pricing.MinTenure ||
+			request.MaxTenure != pricing.MaxTenure ||
+			request.BorrowerRate != pricing.BorrowerRate ||
+			request.LenderYield != pricing.LenderYield ||
+			!validFrom.Equal(pricing.ValidFrom) {
+			log.WithField("pricingID", id).Error("Loan pricing already in effect, only valid until can be changed")
+			return nil, errors.New("10003")
+		}
+	}
+	if request.ValidUntil != nil && !request.ValidUntil.After(validFrom) {
+		log.WithField("pricingID", id).Error("ValidUntil must be after ValidFrom")
+		return nil, errors.New("10003")
+	}
+
+	pricing.LoanGrade = request.LoanGrade
+	pricing.LoanType = request.LoanType
+	pricing.MinTenure = request.MinTenure
+	pricing.MaxTenure = request.MaxTenure
+	pricing.BorrowerRate = request.BorrowerRate
+	pricing.LenderYield = request.LenderYield
+	pricing.ValidFrom = validFrom
+	pricing.ValidUntil = request.ValidUntil
+	pricing.UpdatedAt = time.Now()
+	err = s.Repo.Update(ctx, pricing)
+	if err != nil {
+		log.WithField("pricingID", id).WithError(err).Error("Failed to update loan pricing")
+		return nil, errors.New("99999")
+	}
+
+	pricingRes, err := s.toResponseDTO(*pricing)
+	if err != nil {
+		return nil, errors.New("99999")
+	}
+
+	log.WithField("pricingID", id).Info("Loan pricing updated successfully")
+	return pricingRes, nil
+}
+
+// Delete removes a pricing that has not taken effect yet, a pricing in effect must be ended instead
+func (s *LoanPricingSvcImpl) Delete(ctx context.Context, id int64) error {
+	pricing, err := s.Repo.GetByID(ctx, id)
+	if err != nil {
+		log.WithField("pricingID", id).WithError(err).Error("Failed to get loan pricing")
+		return errors.New("99999")
+	}
+	if pricing == nil {
+		log.WithField("pricingID", id).Warn("Loan pricing not found")
+		return errors.New("10001")
+	}
+
+	if !pricing.ValidFrom.After(time.Now()) {
+		log.WithField("pricingID", id).Error("Loan pricing already in effect, it can not be deleted")
+		return errors.New("10003")
+	}
+
+	err = s.Repo.Delete(ctx, id)
+	if err != nil {
+		log.WithField("pricingID", id).WithError(err).Error("Failed to delete loan pricing")
+		return errors.New("99999")
+	}
+
+	log.WithField("pricingID", id).Info("Loan pricing deleted successfully")
+	return nil
+}
+
+func (s *LoanPricingSvcImpl) toResponseDTO(pricing repo.LoanPricing) (*dto.LoanPricingResponseDTO, error) {
+	var pricingRes dto.LoanPricingResponseDTO
+	err := mapstructure.Decode(pricing, &pricingRes)
+	if err != nil {
+		log.WithField("pricingID", pricing.ID).WithError(err).Error("Failed to map loan pricing to DTO")
+		return nil, err
+	}
+	// rates have two decimals, round away the float noise of the subtraction
+	pricingRes.Spread = math.Round((pricing.BorrowerRate-pricing.LenderYield)*100) / 100
+	pricingRes.ValidFrom = pricing.ValidFrom
+	pricingRes.ValidUntil = pricing.ValidUntil
+	pricingRes.CreatedAt = pricing.CreatedAt
+	pricingRes.UpdatedAt = pricing.UpdatedAt
+
+	return &pricingRes, nil
+}
@@ -0,0 +1,518 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"github.com/mitchellh/mapstructure"
+	"github.com/segmentio/kafka-go"
+	log "github.com/sirupsen/logrus"
+	"github.com/test/loan-service/internal/consts"
+	"github.com/test/loan-service/internal/dto"
+	"github.com/test/loan-service/internal/dto/message"
+	"github.com/test/loan-service/internal/enum"
+	"github.com/test/loan-service/internal/money"
+	repo "github.com/test/loan-service/internal/repository"
+	"github.com/test/loan-service/internal/service/validator"
+	"github.com/test/loan-service/internal/utils"
+	"github.com/typical-go/typical-rest-server/pkg/dbtxn"
+	"go.uber.org/dig"
+	"math"
+	"sort"
+	"time"
+)
+
+//go:generate mockgen -source=$GOFILE -destination=$PROJ/internal/generated/mock/mock_$GOPACKAGE/$GOFILE
+
+type (
+	LoanRepaymentSvc interface {
+		Create(ctx context.Context, loanID int64, request *dto.LoanRepaymentRequestDTO) (*dto.LoanRepaymentResponseDTO, error)
+		GetByLoanID(ctx context.Context, loanID int64) ([]dto.LoanRepaymentResponseDTO, error)
+	}
+
+	LoanRepaymentSvcImpl struct {
+		dig.In
+		Repo            repo.LoanRepaymentRepo
+		AllocationRepo  repo.RepaymentAllocationRepo
+		ScheduleRepo    repo.RepaymentScheduleRepo
+		LoanRepo        repo.LoanRepo
+		LoanFundingRepo repo.LoanFundingRepo
+		LedgerSvc       LedgerSvc
+		WalletSvc       LenderWalletSvc
+		TaxSvc          TaxSvc
+		KafkaWriter     *kafka.Writer
+		Validator       validator.LoanRepaymentValidatorImpl
+		LoanValidator   validator.LoanValidatorImpl
+	}
+)
+
+func NewLoanRepaymentSvc(impl LoanRepaymentSvcImpl) LoanRepaymentSvc {
+	return &impl
+}
+
+// Create records a borrower payment. The payment is applied to the installment due first
+// (interest before principal) and the resulting principal and interest are split across every
+// on going funding of the loan in proportion to its investment amount. Once the borrower has paid
+// the whole TotalRepaymentAmount the loan and its fundings are completed in the same transaction.
+func (s *LoanRepaymentSvcImpl) Create(ctx context.Context, loanID int64, request *dto.LoanRepaymentRequestDTO) (response *dto.LoanRepaymentResponseDTO, err error) {
+	log.WithFields(log.Fields{
+		"loanID":          loanID,
+		"amount":          request.Amount,
+		"referenceNumber": request.ReferenceNumber,
+	}).Info("Creating loan repayment")
+
+	err = s.Validator.ValidateCreate(request)
+	if err != nil {
+		log.WithField("loanID", loanID).Errorf("Validation failed: %s", err)
+		return nil, err
+	}
+
+	// Start transaction, the loan row stays locked until commit so payments are applied one by one
+	txnCtx := dbtxn.Begin(&ctx)
+	defer func() {
+		// a repayment that is rolled back has credited no wallet, it must not be reported as paid
+		if commitErr := txnCtx.Commit(); commitErr != nil {
+			log.WithError(commitErr).Error("Transaction commit failed")
+			response, err = nil, errors.New("99999")
+		}
+	}()
+
+	loan, err := s.LoanRepo.GetByIDForUpdate(ctx, loanID)
+	if err != nil || loan == nil {
+		log.WithField("loanID", loanID).WithError(err).Warn("Loan not found")
+		txnCtx.AppendError(errors.New("loan not found"))
+		return nil, errors.New("10001")
+	}
+
+	if loan.LoanStatus != enum.Disbursed {
+		log.WithFields(log.Fields{
+			"loanID":     loanID,
+			"loanStatus": loan.LoanStatus,
+		}).Warn("Loan is not disbursed, repayment rejected")
+		txnCtx.AppendError(errors.New("loan is not disbursed"))
+		return nil, errors.New("10003")
+	}
+
+	schedules, err := s.ScheduleRepo.GetByLoanID(ctx, loanID)
+	if err != nil {
+		log.WithField("loanID", loanID).WithError(err).Error("Failed to get repayment schedule")
+		txnCtx.AppendError(err)
+		return nil, errors.New("99999")
+	}
+
+	amount := request.Amount
+	outstanding := money.Zero
+	for _, schedule := range schedules {
+		outstanding += schedule.TotalAmount - schedule.PrincipalPaid - schedule.InterestPaid
+	}
+	if amount > outstanding {
+		log.WithFields(log.Fields{
+			"loanID":      loanID,
+			"amount":      amount,
+			"outstanding": outstanding,
+		}).Warn("Repayment amount exceeds outstanding amount")
+		txnCtx.AppendError(errors.New("repayment exceeds outstanding amount"))
+		return nil, errors.New("10003")
+	}
+
+	paymentDate := time.Now()
+	if request.PaymentDate != nil {
+		paymentDate = *request.PaymentDate
+	}
+
+	// apply payment to installments in due order, interest first then principal
+	remaining := amount
+	principalPaid, interestPaid := money.Zero, money.Zero
+	for i := range schedules {
+		schedule := &schedules[i]
+		if remaining <= 0 {
+			break
+		}
+		if schedule.Status == enum.RepaymentSchedulePaid {
+			continue
+		}
+
+		interest := money.Min(remaining, schedule.InterestAmount-schedule.InterestPaid)
+		remaining -= interest
+
+		principal := money.Min(remaining, schedule.PrincipalAmount-schedule.PrincipalPaid)
+		remaining -= principal
+
+		schedule.InterestPaid += interest
+		schedule.PrincipalPaid += principal
+		interestPaid += interest
+		principalPaid += principal
+
+		if schedule.InterestPaid >= schedule.InterestAmount && schedule.PrincipalPaid >= schedule.PrincipalAmount {
+			schedule.Status = enum.RepaymentSchedulePaid
+			schedule.PaidAt = &paymentDate
+		} else {
+			schedule.Status = enum.RepaymentSchedulePartiallyPaid
+		}
+
+		err = s.ScheduleRepo.Update(ctx, schedule)
+		if err != nil {
+			log.WithFields(log.Fields{
+				"loanID":            loanID,
+				"installmentNumber": schedule.InstallmentNumber,
+			}).WithError(err).Error("Failed to update repayment schedule")
+			txnCtx.AppendError(err)
+			return nil, errors.New("99999")
+		}
+	}
+
+	repayment := repo.LoanRepayment{
+		LoanID:          loanID,
+		RepaymentCode:   utils.GenerateAlphanumericCode(10),
+		ReferenceNumber: request.ReferenceNumber,
+		Amount:          amount,
+		PrincipalAmount: principalPaid,
+		InterestAmount:  interestPaid,
+		PaymentDate:     paymentDate,
+		CreatedAt:       time.Now(),
+		UpdatedAt:       time.Now(),
+	}
+	repayment.ID, err = s.Repo.Create(ctx, &repayment)
+	if err != nil {
+		log.WithField("loanID", loanID).WithError(err).Error("Failed to create loan repayment")
+		txnCtx.AppendError(err)
+		return nil, errors.New("99999")
+	}
+
+	// once every installment interest is paid the lenders get the rest of their interest, so rounding of
+	// the spread never leaves a lender short
+	interestSettled := true
+	for _, schedule := range schedules {
+		if schedule.InterestPaid < schedule.InterestAmount {
+			interestSettled = false
+			break
+		}
+	}
+
+	allocations, err := s.allocateToLenders(ctx, loan, &repayment, interestSettled)
+	if err != nil {
+		txnCtx.AppendError(err)
+		return nil, errors.New("99999")
+	}
+
+	err = s.LedgerSvc.RecordRepayment(ctx, &repayment, allocations)
+	if err != nil {
+		log.WithField("loanID", loanID).WithError(err).Error("Failed to record repayment in ledger")
+		txnCtx.AppendError(err)
+		return nil, errors.New("99999")
+	}
+
+	err = s.WalletSvc.CreditRepayment(ctx, allocations)
+	if err != nil {
+		log.WithField("loanID", loanID).WithError(err).Error("Failed to credit lender wallets")
+		txnCtx.AppendError(err)
+		return nil, errors.New("99999")
+	}
+
+	err = s.completeIfFullyRepaid(ctx, loan)
+	if err != nil {
+		txnCtx.AppendError(err)
+		return nil, errors.New("99999")
+	}
+
+	response, err = s.toResponseDTO(repayment, allocations)
+	if err != nil {
+		txnCtx.AppendError(err)
+		return nil, errors.New("99999")
+	}
+
+	log.WithFields(log.Fields{
+		"loanID":        loanID,
+		"repaymentCode": repayment.RepaymentCode,
+		"principal":     principalPaid,
+		"interest":      interestPaid,
+	}).Info("Loan repayment created successfully")
+	return response, nil
+}
+
+// allocateToLenders splits the repayment across the on going fundings of the loan. Fundings are
+// ordered by ID so rounding remainders always land on the same lenders for the same input. The borrower
+// pays interest at the loan rate while a lender earns its funding rate, the interest above the lender
+// yield is left to the platform as spread.
+func (s *LoanRepaymentSvcImpl) allocateToLenders(ctx context.Context, loan *repo.Loan, repayment *repo.LoanRepayment, interestSettled bool) ([]repo.RepaymentAllocation, error) {
+	loanFundings, err := s.LoanFundingRepo.GetByLoanID(ctx, repayment.LoanID)
+	if err != nil {
+		log.WithField("loanID", repayment.LoanID).WithError(err).Error("Failed to get loan fundings")
+		return nil, err
+	}
+
+	var fundings []repo.LoanFunding
+	for _, funding := range loanFundings {
+		if funding.Status == enum.LoanFundingOngoing {
+			fundings = append(fundings, funding)
+		}
+	}
+	if len(fundings) == 0 {
+		log.WithField("loanID", repayment.LoanID).Error("No on going funding found for loan")
+		return nil, errors.New("no on going funding found")
+	}
+	sort.Slice(fundings, func(i, j int) bool {
+		return fundings[i].ID < fundings[j].ID
+	})
+
+	// the weights are the outstanding principal, so a funding bought on the secondary market takes
+	// the share its seller gave up. Once the principal is repaid the remaining interest is the weight.
+	weights := make([]money.Amount, len(fundings))
+	for i, funding := range fundings {
+		weights[i] = outstandingPrincipal(funding)
+	}
+	if money.Sum(weights...).IsZero() {
+		for i, funding := range fundings {
+			weights[i] = funding.Interest - funding.InterestPaid
+		}
+	}
+	principalShares := repayment.PrincipalAmount.Allocate(weights)
+	interestShares := repayment.InterestAmount.Allocate(weights)
+
+	var allocations []repo.RepaymentAllocation
+	for i := range fundings {
+		funding := &fundings[i]
+		interest := lenderInterest(loan, *funding, interestShares[i], interestSettled)
+
+		// the platform service fee and the income tax are taken from the interest before it reaches
+		// the lender, the tax is calculated on the gross interest
+		serviceFee := utils.CalculatePercentage(interest, funding.ServiceFeePercentage)
+		withholding, err := s.TaxSvc.Calculate(ctx, funding.LenderID, interest)
+		if err != nil {
+			log.WithField("loanFundingID", funding.ID).WithError(err).Error("Failed to calculate withholding tax")
+			return nil, err
+		}
+		allocation := repo.RepaymentAllocation{
+			RepaymentID:     repayment.ID,
+			LoanFundingID:   funding.ID,
+			LenderID:        funding.LenderID,
+			PrincipalAmount: principalShares[i],
+			InterestAmount:  interest,
+			ServiceFee:      serviceFee,
+			WithholdingTax:  withholding.TaxAmount,
+			TotalAmount:     principalShares[i] + interest - serviceFee - withholding.TaxAmount,
+			CreatedAt:       time.Now(),
+		}
+		allocation.ID, err = s.AllocationRepo.Create(ctx, &allocation)
+		if err != nil {
+			log.WithFields(log.Fields{
+				"repaymentID":   repayment.ID,
+				"loanFundingID": funding.ID,
+			}).WithError(err).Error("Failed to create repayment allocation")
+			return nil, err
+		}
+
+		if withholding.TaxableAmount.IsPositive() {
+			withholding.RepaymentAllocationID = allocation.ID
+			withholding.RepaymentID = repayment.ID
+			withholding.LoanID = repayment.LoanID
+			withholding.LoanFundingID = funding.ID
+			withholding.WithheldAt = repayment.PaymentDate
+			err = s.TaxSvc.Withhold(ctx, withholding)
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		funding.CapitalAmountPaid += allocation.PrincipalAmount
+		funding.InterestPaid += allocation.InterestAmount
+		funding.TotalAmountPaid += allocation.PrincipalAmount + allocation.InterestAmount
+		funding.ServiceFeePaid += allocation.ServiceFee
+		funding.WithholdingTaxPaid += allocation.WithholdingTax
+		funding.UpdatedAt = time.Now()
+		err = s.LoanFundingRepo.Update(ctx, funding)
+		if err != nil {
+			log.WithField("loanFundingID", funding.ID).WithError(err).Error("Failed to update loan funding")
+			return nil, err
+		}
+
+		allocations = append(allocations, allocation)
+	}
+
+	return allocations, nil
+}
+
+// completeIfFullyRepaid moves the loan to completed and every on going funding to completed once
+// the cumulative repayments reach TotalRepaymentAmount, then publishes the loan completed event.
+func (s *LoanRepaymentSvcImpl) completeIfFullyRepaid(ctx context.Context, loan *repo.Loan) error {
+	totalRepaid, err := s.Repo.GetTotalAmountByLoanID(ctx, loan.ID)
+	if err != nil {
+		log.WithField("loanID", loan.ID).WithError(err).Error("Failed to get total repaid amount")
+		return err
+	}
+	if totalRepaid < loan.TotalRepaymentAmount {
+		return nil
+	}
+
+	// Validate status transition
+	isValid := s.LoanValidator.ValidateTransitionStatus(loan.LoanStatus, enum.Completed)
+	if !isValid {
+		log.WithFields(log.Fields{
+			"currentStatus": loan.LoanStatus,
+			"newStatus":     enum.Completed,
+		}).Error("Invalid status transition")
+		return errors.New("invalid status transition")
+	}
+
+	loan.LoanStatus = enum.Completed
+	loan.UpdatedAt = time.Now()
+	err = s.LoanRepo.Update(ctx, loan)
+	if err != nil {
+		log.WithField("loanID", loan.ID).WithError(err).Error("Failed to update loan")
+		return err
+	}
+
+	loanFundings, err := s.LoanFundingRepo.GetByLoanID(ctx, loan.ID)
+	if err != nil {
+		log.WithField("loanID", loan.ID).WithError(err).Error("Failed to get loan fundings")
+		return err
+	}
+
+	for _, funding := range loanFundings {
+		if funding.Status != enum.LoanFundingOngoing {
+			continue
+		}
+		// the borrower has paid everything, so the lender investment is completed as well
+		funding.Status = enum.LoanFundingCompleted
+		funding.UpdatedAt = time.Now()
+		err = s.LoanFundingRepo.Update(ctx, &funding)
+		if err != nil {
+			log.WithField("loanFundingID", funding.ID).WithError(err).Error("Failed to update loan funding")
+			return err
+		}
+	}
+
+	err = s.publishLoanCompleted(ctx, loan, totalRepaid)
+	if err != nil {
+		return err
+	}
+
+	log.WithFields(log.Fields{
+		"loanID":      loan.ID,
+		"totalRepaid": totalRepaid,
+	}).Info("Loan fully repaid and completed")
+	return nil
+}
+
+func (s *LoanRepaymentSvcImpl) publishLoanCompleted(ctx context.Context, loan *repo.Loan, totalRepaid money.Amount) error {
+	req := message.LoanCompletedMessage{
+		LoanID:               loan.ID,
+		LoanCode:             loan.LoanCode,
+		BorrowerID:           loan.BorrowerID,
+		TotalRepaymentAmount: loan.TotalRepaymentAmount,
+		TotalRepaidAmount:    totalRepaid,
+		CompletedAt:          loan.UpdatedAt,
+	}
+
+	jsonData, err := json.Marshal(req)
+	if err != nil {
+		log.Errorf("Failed to marshal loan completed message for loan ID: %d: %v", loan.ID, err)
+		return err
+	}
+
+	topic := string(consts.LoanCompletedTopic)
+	msg := kafka.Message{
+		Topic: topic,
+		Value: jsonData,
+	}
+
+	log.Infof("Sending loan completed message to Kafka topic: %s", topic)
+	err = s.KafkaWriter.WriteMessages(ctx, msg)
+	if err != nil {
+		log.Errorf("Failed to send loan completed message to Kafka topic: %s: %v", topic, err)
+		return err
+	}
+
+	return nil
+}
+
+func (s *LoanRepaymentSvcImpl) GetByLoanID(ctx context.Context, loanID int64) ([]dto.LoanRepaymentResponseDTO, error) {
+	log.WithField("loanID", loanID).Info("Fetching loan repayments")
+
+	loan, err := s.LoanRepo.GetByID(ctx, loanID)
+	if err != nil || loan == nil {
+		log.WithField("loanID", loanID).WithError(err).Warn("Loan not found")
+		return nil, errors.New("10001")
+	}
+
+	repayments, err := s.Repo.GetByLoanID(ctx, loanID)
+	if err != nil {
+		log.WithField("loanID", loanID).WithError(err).Error("Failed to get loan repayments")
+		return nil, errors.New("99999")
+	}
+
+	repaymentDTOs := []dto.LoanRepaymentResponseDTO{}
+	for _, repayment := range repayments {
+		var allocations []repo.RepaymentAllocation
+		allocations, err = s.AllocationRepo.GetByRepaymentID(ctx, repayment.ID)
+		if err != nil {
+			log.WithField("repaymentID", repayment.ID).WithError(err).Error("Failed to get repayment allocations")
+			return nil, errors.New("99999")
+		}
+
+		var repaymentRes *dto.LoanRepaymentResponseDTO
+		repaymentRes, err = s.toResponseDTO(repayment, allocations)
+		if err != nil {
+			return nil, errors.New("99999")
+		}
+		repaymentDTOs = append(repaymentDTOs, *repaymentRes)
+	}
+
+	log.WithFields(log.Fields{
+		"loanID":     loanID,
+		"repayments": len(repaymentDTOs),
+	}).Info("Loan repayments fetched successfully")
+	return repaymentDTOs, nil
+}
+
+func (s *LoanRepaymentSvcImpl) toResponseDTO(repayment repo.LoanRepayment, allocations []repo.RepaymentAllocation) (*dto.LoanRepaymentResponseDTO, error) {
+	var repaymentRes dto.LoanRepaymentResponseDTO
+	err := mapstructure.Decode(repayment, &repaymentRes)
+	if err != nil {
+		log.WithField("repaymentID", repayment.ID).WithError(err).Error("Failed to map repayment to DTO")
+		return nil, err
+	}
+	repaymentRes.PaymentDate = repayment.PaymentDate
+	repaymentRes.CreatedAt = repayment.CreatedAt
+	repaymentRes.UpdatedAt = repayment.UpdatedAt
+	repaymentRes.DeletedAt = repayment.DeletedAt
+
+	for _, allocation := range allocations {
+		var allocationRes dto.RepaymentAllocationResponseDTO
+		err = mapstructure.Decode(allocation, &allocationRes)
+		if err != nil {
+			log.WithField("repaymentID", repayment.ID).WithError(err).Error("Failed to map repayment allocation to DTO")
+			return nil, err
+		}
+		allocationRes.CreatedAt = allocation.CreatedAt
+		repaymentRes.Allocations = append(repaymentRes.Allocations, allocationRes)
+	}
+
+	return &repaymentRes, nil
+}
+
+// outstandingPrincipal returns the principal of the funding not yet repaid and not sold to another lender
+func outstandingPrincipal(funding repo.LoanFunding) money.Amount {
+	return funding.InvestmentAmount - funding.CapitalAmountPaid - funding.TransferredAmount
+}
+
+// lenderInterest returns the part of the interest share the funding is entitled to. The share paid at
+// the loan rate is scaled down to the funding rate and never exceeds the interest the funding has still
+// to receive, once the borrower interest is settled the funding gets whatever it has still to receive.
+func lenderInterest(loan *repo.Loan, funding repo.LoanFunding, share money.Amount, interestSettled bool) money.Amount {
+	remaining := funding.Interest - funding.InterestPaid
+	if !remaining.IsPositive() {
+		return money.Zero
+	}
+	if interestSettled {
+		return money.Min(share, remaining)
+	}
+
+	loanRateBasisPoints := int64(math.Round(loan.Rate * 100))
+	if loanRateBasisPoints <= 0 {
+		return money.Zero
+	}
+	// a funding never earns more than the borrower pays
+	fundingRateBasisPoints := int64(math.Round(math.Min(funding.Rate, loan.Rate) * 100))
+	return money.Min(share.MulRat(fundingRateBasisPoints, loanRateBasisPoints, money.Down), remaining)
+}
@@ -3,6 +3,7 @@ package service
 import (
 	"context"
 	"errors"
+	"fmt"
 	"github.com/mitchellh/mapstructure"
 	log "github.com/sirupsen/logrus"
 	"github.com/test/loan-service/internal/dto"
@@ -14,6 +15,7 @@ import (
 	"github.com/test/loan-service/internal/utils"
 	"github.com/typical-go/typical-rest-server/pkg/dbtxn"
 	"go.uber.org/dig"
+	"strings"
 	"time"
 )
 
@@ -25,16 +27,28 @@ type (
 		ApprovalLoan(ctx context.Context, request message.UpdateLoanMessage) error
 		DisburseLoan(ctx context.Context, request message.UpdateLoanMessage) error
 		GetByID(ctx context.Context, loanID int64) (*dto.LoanResponseDTO, error)
+		UpdatePartialFundingConsent(ctx context.Context, loanID int64, consent bool) error
+		Cancel(ctx context.Context, loanID int64, request *dto.LoanCancelRequestDTO) error
 		GetAllPage(ctx context.Context, request models.LoanRequest) ([]dto.LoanResponseDTO, int, error)
 	}
 
 	LoanSvcImpl struct {
 		dig.In
-		Repo            repo.LoanRepo
-		LoanFundingRepo repo.LoanFundingRepo
-		LoanDetailSvc   LoanDetailSvc
-		LoanApprovalSvc LoanApprovalSvc
-		LoanValidator   validator.LoanValidatorImpl
+		Repo                 repo.LoanRepo
+		LoanFundingRepo      repo.LoanFundingRepo
+		DisbursementRepo     repo.LoanDisbursementRepo
+		LoanDetailSvc        LoanDetailSvc
+		LoanApprovalSvc      LoanApprovalSvc
+		RepaymentScheduleSvc RepaymentScheduleSvc
+		LedgerSvc            LedgerSvc
+		CreditScoringSvc     CreditScoringSvc
+		LoanPricingSvc       LoanPricingSvc
+		AutoInvestSvc        AutoInvestSvc
+		WalletSvc            LenderWalletSvc
+		MailSvc              EmailSvc
+		BorrowerSvc          BorrowerSvc
+		LoanValidator        validator.LoanValidatorImpl
+		CancelValidator      validator.LoanCancelValidatorImpl
 	}
 )
 
@@ -43,8 +57,16 @@ func NewLoanSvc(impl LoanSvcImpl) LoanSvc {
 }
 
 func (b *LoanSvcImpl) Create(ctx context.Context, loanRequest *dto.LoanRequestDTO) (int64, error) {
+	// only borrowers with a verified KYC can apply
+	borrower, err := b.BorrowerSvc.GetVerified(ctx, loanRequest.BorrowerID)
+	if err != nil {
+		log.WithField("borrowerID", loanRequest.BorrowerID).Warnf("Borrower can not apply for a loan: %s", err)
+		return -1, err
+	}
+	fillBusinessDetail(&loanRequest.Detail, borrower)
+
 	// Validate request
-	err := b.LoanValidator.ValidateCreate(loanRequest)
+	err = b.LoanValidator.ValidateCreate(loanRequest)
 	if err != nil {
 		log.WithFields(log.Fields{
 			"borrowerID":    loanRequest.BorrowerID,
@@ -64,7 +86,33 @@ func (b *LoanSvcImpl) Create(ctx context.Context, loanRequest *dto.LoanRequestDT
 	loanCode := utils.GenerateAlphanumericCode(10)
 	loan.LoanCode = loanCode
 
-	loan.InvestmentPercentage = loan.Rate
+	// the grade is derived from the loan detail, clients can not choose it
+	creditScore, err := b.CreditScoringSvc.Evaluate(ctx, models.CreditScoringRequest{
+		RequestAmount: loanRequest.RequestAmount,
+		Tenures:       int64(loanRequest.Tenures),
+		Detail:        &loanRequest.Detail,
+	})
+	if err != nil {
+		log.WithFields(log.Fields{
+			"loanCode": loanCode,
+		}).WithError(err).Error("Failed to score loan request")
+		return -1, err
+	}
+	loan.LoanGrade = creditScore.Grade
+
+	// the borrower rate and lender yield come from the pricing of the grade, the difference is the
+	// platform spread
+	pricing, err := b.LoanPricingSvc.GetApplicable(ctx, loan.LoanGrade, loan.LoanType, loan.Tenures)
+	if err != nil {
+		log.WithFields(log.Fields{
+			"loanCode":  loanCode,
+			"loanGrade": loan.LoanGrade,
+		}).WithError(err).Error("Failed to price loan request")
+		return -1, err
+	}
+	loan.Rate = pricing.BorrowerRate
+	loan.InvestmentPercentage = pricing.LenderYield
+	loan.PricingID = &pricing.ID
 
 	// Set initial loan status
 	loan.LoanStatus = enum.Proposed
@@ -98,6 +146,16 @@ func (b *LoanSvcImpl) Create(ctx context.Context, loanRequest *dto.LoanRequestDT
 		return -1, errors.New("99999")
 	}
 
+	// Keep the contribution of every factor for audit
+	err = b.CreditScoringSvc.Save(ctx, id, creditScore)
+	if err != nil {
+		log.WithFields(log.Fields{
+			"loanID": id,
+		}).WithError(err).Error("Failed to save credit score")
+		txnCtx.AppendError(err)
+		return -1, errors.New("99999")
+	}
+
 	// Create initial approval
 	_, err = b.createInitialApproval(ctx, id)
 	if err != nil {
@@ -109,8 +167,10 @@ func (b *LoanSvcImpl) Create(ctx context.Context, loanRequest *dto.LoanRequestDT
 	}
 
 	log.WithFields(log.Fields{
-		"loanID":   id,
-		"loanCode": loanCode,
+		"loanID":    id,
+		"loanCode":  loanCode,
+		"loanGrade": loan.LoanGrade,
+		"pricingID": pricing.ID,
 	}).Info("Loan created successfully")
 	return id, nil
 }
@@ -151,28 +211,51 @@ func (b *LoanSvcImpl) ApprovalLoan(ctx context.Context, request message.UpdateLo
 
 	loan.UpdatedAt = time.Now()
 
-	// Start transaction to update loan
+	err = b.updateApproval(ctx, loan)
+	if err != nil {
+		return err
+	}
+
+	log.WithFields(log.Fields{
+		"loanID":    request.LoanID,
+		"newStatus": loan.LoanStatus,
+	}).Info("Loan updated successfully")
+
+	// the funding orders of the auto-invest rules need the approval committed, a failed run does not
+	// undo the approval, lenders can still fund the loan themselves
+	if loan.LoanStatus == enum.Approved {
+		err = b.AutoInvestSvc.Run(ctx, loan)
+		if err != nil {
+			log.WithFields(log.Fields{
+				"loanID": request.LoanID,
+			}).WithError(err).Error("Failed to run auto-invest rules")
+		}
+	}
+
+	return nil
+}
+
+// updateApproval stores the approval decision in its own transaction, committed before returning
+func (b *LoanSvcImpl) updateApproval(ctx context.Context, loan *repo.Loan) (err error) {
 	txnCtx := dbtxn.Begin(&ctx)
 	defer func() {
-		if err = txnCtx.Commit(); err != nil {
-			log.WithError(err).Error("Transaction commit failed")
-			txnCtx.AppendError(err)
+		if commitErr := txnCtx.Commit(); commitErr != nil {
+			log.WithError(commitErr).Error("Transaction commit failed")
+			if err == nil {
+				err = errors.New("99999")
+			}
 		}
 	}()
 
 	err = b.Repo.Update(ctx, loan)
 	if err != nil {
 		log.WithFields(log.Fields{
-			"loanID": request.LoanID,
+			"loanID": loan.ID,
 		}).WithError(err).Error("Failed to update loan")
 		txnCtx.AppendError(err)
 		return errors.New("99999")
 	}
 
-	log.WithFields(log.Fields{
-		"loanID":    request.LoanID,
-		"newStatus": loan.LoanStatus,
-	}).Info("Loan updated successfully")
 	return nil
 }
 
@@ -210,10 +293,13 @@ func (b *LoanSvcImpl) DisburseLoan(ctx context.Context, request message.UpdateLo
 
 	// change status
 	loan.LoanStatus = request.LoanStatus
+	// the borrower receives the funded amount, which is lower than the request amount when the loan
+	// is disbursed under a partial funding policy
+	principal := loan.TotalInvestedAmount
 	// calculate interest for borrower
-	loan.TotalInterest = utils.CalculateInterest(loan.RequestAmount, loan.Rate, loan.Tenures)
+	loan.TotalInterest = utils.CalculateInterest(principal, loan.Rate, loan.Tenures)
 	// calculate total repayment amount ( total amount which nedd borrower pay )
-	loan.TotalRepaymentAmount = loan.RequestAmount + loan.TotalInterest
+	loan.TotalRepaymentAmount = principal + loan.TotalInterest
 	loan.UpdatedAt = time.Now()
 
 	// Start transaction to update loan
@@ -246,6 +332,10 @@ func (b *LoanSvcImpl) DisburseLoan(ctx context.Context, request message.UpdateLo
 	}
 
 	for _, funding := range loanFunding {
+		// failed and refunded fundings never took part in the loan
+		if funding.Status != enum.LoanFundingInvested {
+			continue
+		}
 		// change funding status from invested to on going , becuase the loan already disbursed
 		funding.Status = enum.LoanFundingOngoing
 		funding.UpdatedAt = time.Now()
@@ -259,7 +349,35 @@ func (b *LoanSvcImpl) DisburseLoan(ctx context.Context, request message.UpdateLo
 		}
 	}
 
-	// TODO : generate repayment schedule borrower
+	// the disbursement holds the origination fee deducted from the payout
+	disbursement, err := b.DisbursementRepo.GetByLoanID(ctx, loan.ID)
+	if err != nil || disbursement == nil {
+		log.WithFields(log.Fields{
+			"loanID": request.LoanID,
+		}).WithError(err).Error("Failed to get loan disbursement")
+		txnCtx.AppendError(errors.New("loan disbursement not found"))
+		return errors.New("99999")
+	}
+
+	// pay the funded principal out to the borrower
+	err = b.LedgerSvc.RecordDisbursement(ctx, loan, disbursement)
+	if err != nil {
+		log.WithFields(log.Fields{
+			"loanID": request.LoanID,
+		}).WithError(err).Error("Failed to record disbursement in ledger")
+		txnCtx.AppendError(err)
+		return errors.New("99999")
+	}
+
+	// generate repayment schedule borrower, installments are due monthly starting from disbursement date
+	_, err = b.RepaymentScheduleSvc.Generate(ctx, loan, loan.UpdatedAt)
+	if err != nil {
+		log.WithFields(log.Fields{
+			"loanID": request.LoanID,
+		}).WithError(err).Error("Failed to generate repayment schedule")
+		txnCtx.AppendError(err)
+		return errors.New("99999")
+	}
 
 	log.WithFields(log.Fields{
 		"loanID":    request.LoanID,
@@ -268,6 +386,199 @@ func (b *LoanSvcImpl) DisburseLoan(ctx context.Context, request message.UpdateLo
 	return nil
 }
 
+// UpdatePartialFundingConsent records whether the borrower accepts a partially funded disbursement.
+// Consent can only change while the loan is still collecting funds.
+func (b *LoanSvcImpl) UpdatePartialFundingConsent(ctx context.Context, loanID int64, consent bool) error {
+	loan, err := b.Repo.GetByID(ctx, loanID)
+	if err != nil || loan == nil {
+		log.WithFields(log.Fields{
+			"loanID": loanID,
+		}).WithError(err).Warn("Loan not found")
+		return errors.New("10001")
+	}
+
+	if loan.LoanStatus != enum.Proposed && loan.LoanStatus != enum.Approved {
+		log.WithFields(log.Fields{
+			"loanID":     loanID,
+			"loanStatus": loan.LoanStatus,
+		}).Warn("Partial funding consent can not be changed anymore")
+		return errors.New("10003")
+	}
+
+	loan.PartialFundingConsent = consent
+	loan.UpdatedAt = time.Now()
+	err = b.Repo.Update(ctx, loan)
+	if err != nil {
+		log.WithFields(log.Fields{
+			"loanID": loanID,
+		}).WithError(err).Error("Failed to update loan")
+		return errors.New("99999")
+	}
+
+	log.WithFields(log.Fields{
+		"loanID":  loanID,
+		"consent": consent,
+	}).Info("Partial funding consent updated successfully")
+	return nil
+}
+
+// Cancel withdraws a loan on request of its borrower. A proposed loan is withdrawn before its approval is
+// decided. An approved loan is cancelled during funding, pending fundings fail and invested fundings are
+// refunded, and their lenders are notified once the cancellation is committed.
+func (b *LoanSvcImpl) Cancel(ctx context.Context, loanID int64, request *dto.LoanCancelRequestDTO) error {
+	log.WithFields(log.Fields{
+		"loanID":     loanID,
+		"borrowerID": request.BorrowerID,
+	}).Info("Cancelling loan")
+
+	err := b.CancelValidator.ValidateCreate(request)
+	if err != nil {
+		log.WithField("loanID", loanID).Errorf("Validation failed: %s", err)
+		return err
+	}
+
+	loan, fundings, err := b.cancelLoan(ctx, loanID, request)
+	if err != nil {
+		return err
+	}
+
+	// lenders are notified only after the transaction has been committed
+	b.notifyCancelledFundings(ctx, *loan, fundings)
+
+	log.WithFields(log.Fields{
+		"loanID":   loanID,
+		"fundings": len(fundings),
+	}).Info("Loan cancelled successfully")
+	return nil
+}
+
+// cancelLoan moves the loan to cancelled and releases its fundings in one transaction. It returns the
+// cancelled loan and the fundings whose lender must be notified.
+func (b *LoanSvcImpl) cancelLoan(ctx context.Context, loanID int64, request *dto.LoanCancelRequestDTO) (loan *repo.Loan, fundings []repo.LoanFunding, err error) {
+	txnCtx := dbtxn.Begin(&ctx)
+	defer func() {
+		if commitErr := txnCtx.Commit(); commitErr != nil {
+			log.WithError(commitErr).Error("Transaction commit failed")
+			loan, fundings, err = nil, nil, errors.New("99999")
+		}
+	}()
+
+	// lock the loan so no funding can be invested into it while it is cancelled
+	loan, err = b.Repo.GetByIDForUpdate(ctx, loanID)
+	if err != nil || loan == nil {
+		log.WithField("loanID", loanID).WithError(err).Warn("Loan not found")
+		txnCtx.AppendError(errors.New("loan not found"))
+		return nil, nil, errors.New("10001")
+	}
+
+	if loan.BorrowerID != request.BorrowerID {
+		log.WithFields(log.Fields{
+			"loanID":     loanID,
+			"borrowerID": request.BorrowerID,
+		}).Warn("Loan does not belong to the borrower")
+		txnCtx.AppendError(errors.New("loan does not belong to the borrower"))
+		return nil, nil, errors.New("10001")
+	}
+
+	isValid := b.LoanValidator.ValidateTransitionStatus(loan.LoanStatus, enum.Cancelled)
+	if !isValid {
+		log.WithFields(log.Fields{
+			"currentStatus": loan.LoanStatus,
+			"newStatus":     enum.Cancelled,
+		}).Warn("Loan can not be cancelled anymore")
+		txnCtx.AppendError(errors.New("invalid status transition"))
+		return nil, nil, errors.New("10003")
+	}
+
+	now := time.Now()
+	reason := strings.TrimSpace(request.Reason)
+	loan.LoanStatus = enum.Cancelled
+	loan.CancellationReason = &reason
+	loan.CancelledAt = &now
+	loan.UpdatedAt = now
+	err = b.Repo.Update(ctx, loan)
+	if err != nil {
+		log.WithField("loanID", loanID).WithError(err).Error("Failed to update loan")
+		txnCtx.AppendError(err)
+		return nil, nil, errors.New("99999")
+	}
+
+	loanFundings, err := b.LoanFundingRepo.GetByLoanID(ctx, loan.ID)
+	if err != nil {
+		log.WithField("loanID", loanID).WithError(err).Error("Failed to get loan fundings")
+		txnCtx.AppendError(err)
+		return nil, nil, errors.New("99999")
+	}
+
+	fundings = []repo.LoanFunding{}
+	for _, funding := range loanFundings {
+		switch funding.Status {
+		case enum.LoanFundingInvested:
+			// the lender money has been taken, give it back
+			funding.Status = enum.LoanFundingRefunded
+		case enum.LoanFundingPending:
+			funding.Status = enum.LoanFundingFailed
+		default:
+			continue
+		}
+		funding.UpdatedAt = now
+
+		err = b.LoanFundingRepo.Update(ctx, &funding)
+		if err != nil {
+			log.WithField("loanFundingID", funding.ID).WithError(err).Error("Failed to update loan funding")
+			txnCtx.AppendError(err)
+			return nil, nil, errors.New("99999")
+		}
+
+		if funding.Status == enum.LoanFundingRefunded {
+			err = b.WalletSvc.RefundFunding(ctx, &funding)
+			if err != nil {
+				log.WithField("loanFundingID", funding.ID).WithError(err).Error("Failed to refund lender wallet")
+				txnCtx.AppendError(err)
+				return nil, nil, errors.New("99999")
+			}
+
+			err = b.LedgerSvc.RecordFundingRefunded(ctx, &funding)
+			if err != nil {
+				log.WithField("loanFundingID", funding.ID).WithError(err).Error("Failed to record refund in ledger")
+				txnCtx.AppendError(err)
+				return nil, nil, errors.New("99999")
+			}
+		} else {
+			// the pending funding never got invested, its hold goes back to the lender
+			err = b.WalletSvc.ReleaseFunding(ctx, &funding)
+			if err != nil {
+				log.WithField("loanFundingID", funding.ID).WithError(err).Error("Failed to release wallet hold")
+				txnCtx.AppendError(err)
+				return nil, nil, errors.New("99999")
+			}
+		}
+		fundings = append(fundings, funding)
+	}
+
+	return loan, fundings, nil
+}
+
+func (b *LoanSvcImpl) notifyCancelledFundings(ctx context.Context, loan repo.Loan, fundings []repo.LoanFunding) {
+	for _, funding := range fundings {
+		if funding.LenderEmail == "" {
+			continue
+		}
+
+		email := SendEmailInput{
+			To:      []string{funding.LenderEmail},
+			Subject: fmt.Sprintf("Loan %s cancelled", loan.LoanCode),
+			Body: fmt.Sprintf("Loan %s has been cancelled by the borrower. "+
+				"Your funding %s of %s has been %s.",
+				loan.LoanCode, funding.LoanOrderNumber, funding.InvestmentAmount, funding.Status),
+		}
+		err := b.MailSvc.SendEmail(ctx, email)
+		if err != nil {
+			log.WithField("loanFundingID", funding.ID).WithError(err).Warn("Failed to notify lender of loan cancellation")
+		}
+	}
+}
+
 func (b *LoanSvcImpl) GetByID(ctx context.Context, loanID int64) (*dto.LoanResponseDTO, error) {
 	// Log request to get loan by ID
 	log.WithFields(log.Fields{
@@ -309,10 +620,19 @@ func (b *LoanSvcImpl) GetByID(ctx context.Context, loanID int64) (*dto.LoanRespo
 
 	// Set additional fields in loan response DTO
 	loanResponse.FundingDeadline = loan.FundingDeadline
+	loanResponse.CancelledAt = loan.CancelledAt
 	loanResponse.CreatedAt = loan.CreatedAt
 	loanResponse.UpdatedAt = loan.UpdatedAt
 	loanResponse.DeletedAt = loan.DeletedAt
 
+	loanResponse.CreditScore, err = b.CreditScoringSvc.GetByLoanID(ctx, loanID)
+	if err != nil {
+		log.WithFields(log.Fields{
+			"loanID": loanID,
+		}).WithError(err).Error("Failed to get credit score")
+		return nil, errors.New("99999")
+	}
+
 	// Log successful mapping to DTO
 	log.WithFields(log.Fields{
 		"loanID":   loanID,
@@ -408,6 +728,7 @@ func (b *LoanSvcImpl) GetAllPage(ctx context.Context, request models.LoanRequest
 			log.WithError(err).WithField("loanID", loan.ID).Error("Failed to map loan to response DTO")
 			return nil, 0, errors.New("99999")
 		}
+		loanDTO.CancelledAt = loan.CancelledAt
 		loanDTO.CreatedAt = loan.CreatedAt
 		loanDTO.UpdatedAt = loan.UpdatedAt
 		loanDTO.DeletedAt = loan.DeletedAt
@@ -432,3 +753,26 @@ func (b *LoanSvcImpl) GetAllPage(ctx context.Context, request models.LoanRequest
 
 	return loanDTOs, int(totalRecords), nil
 }
+
+// fillBusinessDetail takes the business fields left empty in the loan detail from the borrower profile,
+// the revenue, expense and purpose are always given per loan
+func fillBusinessDetail(detail *dto.LoanDetailRequestDTO, borrower *repo.Borrower) {
+	fill := func(field *string, value *string) {
+		if *field == "" && value != nil {
+			*field = *value
+		}
+	}
+
+	fill(&detail.BusinessName, borrower.BusinessName)
+	fill(&detail.BusinessType, borrower.BusinessType)
+	fill(&detail.BusinessAddress, borrower.BusinessAddress)
+	fill(&detail.BusinessPhoneNumber, borrower.BusinessPhoneNumber)
+	fill(&detail.BusinessEmail, borrower.BusinessEmail)
+	fill(&detail.BusinessRegistrationNumber, borrower.BusinessRegistrationNumber)
+	fill(&detail.BusinessOwnerName, borrower.BusinessOwnerName)
+	fill(&detail.BusinessDescription, borrower.BusinessDescription)
+	fill(&detail.BusinessSector, borrower.BusinessSector)
+	if detail.BusinessAge == 0 && borrower.BusinessEstablishedYear != nil {
+		detail.BusinessAge = int64(time.Now().Year()) - *borrower.BusinessEstablishedYear
+	}
+}
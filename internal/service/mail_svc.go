@@ -6,13 +6,21 @@ import (
 	"github.com/sirupsen/logrus"
 	"go.uber.org/dig"
 	"gopkg.in/gomail.v2"
+	"io"
 )
 
 type (
 	SendEmailInput struct {
-		To      []string
-		Subject string
-		Body    string
+		To          []string
+		Subject     string
+		Body        string
+		Attachments []EmailAttachment
+	}
+
+	EmailAttachment struct {
+		FileName    string
+		ContentType string
+		Content     []byte
 	}
 
 	EmailSvc interface {
@@ -37,6 +45,16 @@ func (s *EmailSvcImpl) SendEmail(ctx context.Context, input SendEmailInput) erro
 	message.SetHeader("To", input.To...)
 	message.SetHeader("Subject", input.Subject)
 	message.SetBody("text/plain", input.Body)
+	for _, attachment := range input.Attachments {
+		content := attachment.Content
+		message.Attach(attachment.FileName,
+			gomail.SetHeader(map[string][]string{"Content-Type": {attachment.ContentType}}),
+			gomail.SetCopyFunc(func(w io.Writer) error {
+				_, err := w.Write(content)
+				return err
+			}),
+		)
+	}
 
 	// Mencoba untuk mengirim email
 	if err := s.Mailer.DialAndSend(message); err != nil {
@@ -3,6 +3,8 @@ package models
 import (
 	"github.com/test/loan-service/internal/dto"
 	"github.com/test/loan-service/internal/enum"
+	"github.com/test/loan-service/internal/money"
+	"io"
 )
 
 type (
@@ -29,4 +31,120 @@ type (
 		Size   uint64
 		Status *enum.LoanStatus
 	}
+
+	LedgerPostingRequest struct {
+		AccountType enum.LedgerAccountType
+		OwnerID     int64 // lender ID or loan ID, ignored for platform accounts
+		Direction   enum.LedgerDirection
+		Amount      money.Amount
+	}
+
+	LedgerEntryRequest struct {
+		EntryType   enum.LedgerEntryType
+		ReferenceID int64
+		Description string
+		Postings    []LedgerPostingRequest
+	}
+
+	LedgerStatementRequest struct {
+		AccountID int64
+		Page      uint64
+		Size      uint64
+	}
+
+	CreditScoringRequest struct {
+		RequestAmount money.Amount
+		Tenures       int64
+		Detail        *dto.LoanDetailRequestDTO
+	}
+
+	CreditScoreFactorResult struct {
+		Factor          enum.CreditScoringFactor
+		Value           string
+		RuleID          *int64 // nil when no rule matched, the factor then scores zero
+		RuleDescription string
+		Points          int64
+	}
+
+	CreditScoreResult struct {
+		Score   int64
+		Grade   enum.LoanGrade
+		Factors []CreditScoreFactorResult
+	}
+
+	TaxCertificateRequest struct {
+		LenderID int64
+		Year     int
+		Format   enum.TaxCertificateFormat
+	}
+
+	// TaxCertificateFile is a rendered tax certificate ready to be downloaded
+	TaxCertificateFile struct {
+		FileName    string
+		ContentType string
+		Content     []byte
+	}
+
+	// LoanAgreementData fills the placeholders of a borrower agreement template
+	LoanAgreementData struct {
+		AgreementNumber            string
+		AgreementDate              string
+		TemplateVersion            int64
+		LoanCode                   string
+		BorrowerName               string
+		BorrowerID                 int64
+		BusinessName               string
+		BusinessRegistrationNumber string
+		BusinessAddress            string
+		LoanType                   enum.LoanType
+		LoanGrade                  enum.LoanGrade
+		LoanPurpose                string
+		PrincipalAmount            money.Amount
+		OriginationFee             money.Amount
+		NetDisburseAmount          money.Amount
+		Rate                       float64
+		Tenures                    int64
+		TotalInterest              money.Amount
+		TotalRepaymentAmount       money.Amount
+	}
+
+	// FundingAgreementData fills the placeholders of a lender agreement template
+	FundingAgreementData struct {
+		AgreementNumber      string
+		AgreementDate        string
+		TemplateVersion      int64
+		LoanCode             string
+		LoanOrderNumber      string
+		LenderID             int64
+		LenderEmail          string
+		InvestmentAmount     money.Amount
+		Rate                 float64
+		Tenures              int64
+		Interest             money.Amount
+		ServiceFeePercentage float64
+		ServiceFee           money.Amount
+		WithholdingTax       money.Amount
+		ROI                  money.Amount
+		BusinessName         string
+		BusinessSector       string
+		BusinessAge          int64
+		LoanType             enum.LoanType
+		LoanGrade            enum.LoanGrade
+		LoanPurpose          string
+		LoanAmount           money.Amount
+	}
+
+	// DocumentFile is a stored document ready to be downloaded
+	DocumentFile struct {
+		FileName    string
+		ContentType string
+		Content     []byte
+	}
+
+	// UploadFile is a file received from a client, not read yet
+	UploadFile struct {
+		FileName string
+		Size     int64
+		Content  io.Reader
+	}
 )
@@ -0,0 +1,147 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"github.com/mitchellh/mapstructure"
+	log "github.com/sirupsen/logrus"
+	"github.com/test/loan-service/internal/dto"
+	"github.com/test/loan-service/internal/enum"
+	"github.com/test/loan-service/internal/money"
+	repo "github.com/test/loan-service/internal/repository"
+	"go.uber.org/dig"
+	"time"
+)
+
+//go:generate mockgen -source=$GOFILE -destination=$PROJ/internal/generated/mock/mock_$GOPACKAGE/$GOFILE
+
+type (
+	RepaymentScheduleSvc interface {
+		Generate(ctx context.Context, loan *repo.Loan, startDate time.Time) ([]repo.RepaymentSchedule, error)
+		GetByLoanID(ctx context.Context, loanID int64) ([]dto.RepaymentScheduleResponseDTO, error)
+	}
+
+	RepaymentScheduleSvcImpl struct {
+		dig.In
+		Repo     repo.RepaymentScheduleRepo
+		LoanRepo repo.LoanRepo
+	}
+)
+
+func NewRepaymentScheduleSvc(impl RepaymentScheduleSvcImpl) RepaymentScheduleSvc {
+	return &impl
+}
+
+// Generate creates one installment per month of the loan tenure. Principal and interest are
+// split evenly, the last installment absorbs the rounding remainder so the schedule always sums
+// up to the loan principal and TotalInterest. It uses the transaction carried by ctx if any.
+func (s *RepaymentScheduleSvcImpl) Generate(ctx context.Context, loan *repo.Loan, startDate time.Time) ([]repo.RepaymentSchedule, error) {
+	log.WithFields(log.Fields{
+		"loanID":  loan.ID,
+		"tenures": loan.Tenures,
+	}).Info("Generating repayment schedule")
+
+	if loan.Tenures <= 0 {
+		log.WithField("loanID", loan.ID).Error("Loan tenures must be greater than zero")
+		return nil, errors.New("10003")
+	}
+
+	// principal is the disbursed amount, the funded amount for partially funded loans
+	principal := loan.TotalInvestedAmount
+	totalInterest := loan.TotalInterest
+	// installments are rounded down so the last one, which takes the remainder, is never negative
+	monthlyPrincipal := principal.Div(loan.Tenures, money.Down)
+	monthlyInterest := totalInterest.Div(loan.Tenures, money.Down)
+
+	var schedules []repo.RepaymentSchedule
+	allocatedPrincipal, allocatedInterest := money.Zero, money.Zero
+	for i := int64(1); i <= loan.Tenures; i++ {
+		installmentPrincipal := monthlyPrincipal
+		installmentInterest := monthlyInterest
+		if i == loan.Tenures {
+			// last installment takes whatever is left so nothing is lost to rounding
+			installmentPrincipal = principal - allocatedPrincipal
+			installmentInterest = totalInterest - allocatedInterest
+		}
+		allocatedPrincipal += installmentPrincipal
+		allocatedInterest += installmentInterest
+
+		schedule := repo.RepaymentSchedule{
+			LoanID:             loan.ID,
+			InstallmentNumber:  i,
+			DueDate:            addMonths(startDate, int(i)),
+			PrincipalAmount:    installmentPrincipal,
+			InterestAmount:     installmentInterest,
+			TotalAmount:        installmentPrincipal + installmentInterest,
+			OutstandingBalance: principal - allocatedPrincipal,
+			Status:             enum.RepaymentSchedulePending,
+		}
+
+		id, err := s.Repo.Create(ctx, &schedule)
+		if err != nil {
+			log.WithFields(log.Fields{
+				"loanID":            loan.ID,
+				"installmentNumber": i,
+			}).WithError(err).Error("Failed to create repayment schedule")
+			return nil, errors.New("99999")
+		}
+		schedule.ID = id
+		schedules = append(schedules, schedule)
+	}
+
+	log.WithFields(log.Fields{
+		"loanID":       loan.ID,
+		"installments": len(schedules),
+	}).Info("Repayment schedule generated successfully")
+	return schedules, nil
+}
+
+func (s *RepaymentScheduleSvcImpl) GetByLoanID(ctx context.Context, loanID int64) ([]dto.RepaymentScheduleResponseDTO, error) {
+	log.WithField("loanID", loanID).Info("Fetching repayment schedule")
+
+	loan, err := s.LoanRepo.GetByID(ctx, loanID)
+	if err != nil || loan == nil {
+		log.WithField("loanID", loanID).WithError(err).Warn("Loan not found")
+		return nil, errors.New("10001")
+	}
+
+	schedules, err := s.Repo.GetByLoanID(ctx, loanID)
+	if err != nil {
+		log.WithField("loanID", loanID).WithError(err).Error("Failed to get repayment schedule")
+		return nil, errors.New("99999")
+	}
+
+	scheduleDTOs := []dto.RepaymentScheduleResponseDTO{}
+	for _, schedule := range schedules {
+		var scheduleRes dto.RepaymentScheduleResponseDTO
+		err = mapstructure.Decode(schedule, &scheduleRes)
+		if err != nil {
+			log.WithField("loanID", loanID).WithError(err).Error("Failed to map repayment schedule to DTO")
+			return nil, errors.New("99999")
+		}
+		scheduleRes.DueDate = schedule.DueDate
+		scheduleRes.CreatedAt = schedule.CreatedAt
+		scheduleRes.UpdatedAt = schedule.UpdatedAt
+		scheduleRes.DeletedAt = schedule.DeletedAt
+
+		scheduleDTOs = append(scheduleDTOs, scheduleRes)
+	}
+
+	log.WithFields(log.Fields{
+		"loanID":       loanID,
+		"installments": len(scheduleDTOs),
+	}).Info("Repayment schedule fetched successfully")
+	return scheduleDTOs, nil
+}
+
+// addMonths moves t forward by the given number of months, clamping the day to the last day of the
+// target month so a loan disbursed on the 31st is due on the 28th, 29th or 30th of shorter months
+func addMonths(t time.Time, months int) time.Time {
+	firstOfMonth := time.Date(t.Year(), t.Month()+time.Month(months), 1, t.Hour(), t.Minute(), t.Second(), t.Nanosecond(), t.Location())
+	lastDay := firstOfMonth.AddDate(0, 1, -1).Day()
+	day := t.Day()
+	if day > lastDay {
+		day = lastDay
+	}
+	return firstOfMonth.AddDate(0, 0, day-1)
+}
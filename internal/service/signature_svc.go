@@ -0,0 +1,366 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"github.com/mitchellh/mapstructure"
+	log "github.com/sirupsen/logrus"
+	"github.com/test/loan-service/internal/dto"
+	"github.com/test/loan-service/internal/enum"
+	"github.com/test/loan-service/internal/esign"
+	repo "github.com/test/loan-service/internal/repository"
+	"github.com/test/loan-service/internal/service/validator"
+	"github.com/test/loan-service/internal/storage"
+	"github.com/typical-go/typical-rest-server/pkg/dbtxn"
+	"go.uber.org/dig"
+	"strconv"
+	"strings"
+	"time"
+)
+
+//go:generate mockgen -source=$GOFILE -destination=$PROJ/internal/generated/mock/mock_$GOPACKAGE/$GOFILE
+
+// signedAtTolerance is how far in the future a signing time may be, to allow for clock drift
+// between the provider and the platform
+const signedAtTolerance = 5 * time.Minute
+
+type (
+	// SignatureSvc collects the borrower signature on the agreement of a disbursement through the
+	// e-sign provider. A signature is accepted only when the provider reports the borrower of the
+	// loan as signer and the hash of the signed document matches the hash of the generated agreement.
+	SignatureSvc interface {
+		RequestSignature(ctx context.Context, loan *repo.Loan, disbursement *repo.LoanDisbursement, agreement *repo.LoanAgreement) (*repo.AgreementSignature, error)
+		Reissue(ctx context.Context, disbursementID int64) (*dto.AgreementSignatureResponseDTO, error)
+		Callback(ctx context.Context, request *dto.SignatureCallbackRequestDTO) (*dto.AgreementSignatureResponseDTO, error)
+		GetByDisbursementID(ctx context.Context, disbursementID int64) ([]dto.AgreementSignatureResponseDTO, error)
+		GetValidSignature(ctx context.Context, disbursementID int64) (*repo.AgreementSignature, error)
+	}
+
+	SignatureSvcImpl struct {
+		dig.In
+		Repo              repo.AgreementSignatureRepo
+		LoanRepo          repo.LoanRepo
+		DisbursementRepo  repo.LoanDisbursementRepo
+		LoanAgreementRepo repo.LoanAgreementRepo
+		DocumentStore     storage.DocumentStore
+		DocumentRepo      repo.DocumentRepo
+		URLSigner         *storage.URLSigner
+		Provider          esign.Provider
+		Validator         validator.SignatureCallbackValidatorImpl
+	}
+)
+
+func NewSignatureSvc(impl SignatureSvcImpl) SignatureSvc {
+	return &impl
+}
+
+// RequestSignature opens a signing request for the agreement of a disbursement at the provider,
+// the borrower of the loan is the expected signer
+func (s *SignatureSvcImpl) RequestSignature(ctx context.Context, loan *repo.Loan, disbursement *repo.LoanDisbursement, agreement *repo.LoanAgreement) (*repo.AgreementSignature, error) {
+	log.WithFields(log.Fields{
+		"disbursementID": disbursement.ID,
+		"agreementID":    agreement.ID,
+	}).Info("Requesting agreement signature")
+
+	documentHash, err := s.documentHash(ctx, agreement)
+	if err != nil {
+		log.WithField("agreementID", agreement.ID).WithError(err).Error("Failed to hash loan agreement")
+		return nil, errors.New("99999")
+	}
+
+	documentURL, err := s.downloadURL(ctx, agreement)
+	if err != nil {
+		log.WithField("agreementID", agreement.ID).WithError(err).Error("Failed to get download URL of loan agreement")
+		return nil, errors.New("99999")
+	}
+
+	session, err := s.Provider.RequestSignature(ctx, esign.SignatureRequest{
+		Reference:    agreement.AgreementNumber,
+		SignerID:     loan.BorrowerID,
+		DocumentURL:  documentURL,
+		DocumentHash: documentHash,
+	})
+	if err != nil {
+		log.WithField("agreementID", agreement.ID).WithError(err).Error("Failed to request signature at the provider")
+		return nil, errors.New("99999")
+	}
+
+	signature := repo.AgreementSignature{
+		LoanAgreementID:    agreement.ID,
+		LoanDisbursementID: disbursement.ID,
+		LoanID:             loan.ID,
+		SignerID:           loan.BorrowerID,
+		Provider:           s.Provider.Name(),
+		ProviderReference:  session.ProviderReference,
+		SigningURL:         session.SigningURL,
+		Status:             enum.SignatureRequested,
+		CreatedAt:          time.Now(),
+		UpdatedAt:          time.Now(),
+	}
+
+	signature.ID, err = s.Repo.Create(ctx, &signature)
+	if err != nil {
+		log.WithField("agreementID", agreement.ID).WithError(err).Error("Failed to create agreement signature")
+		return nil, errors.New("99999")
+	}
+
+	log.WithFields(log.Fields{
+		"signatureID":       signature.ID,
+		"providerReference": signature.ProviderReference,
+	}).Info("Agreement signature requested successfully")
+	return &signature, nil
+}
+
+// Reissue opens a new signing request for a pending disbursement, for example after a rejected
+// signature or when the borrower lost the signing link
+func (s *SignatureSvcImpl) Reissue(ctx context.Context, disbursementID int64) (*dto.AgreementSignatureResponseDTO, error) {
+	disbursement, err := s.DisbursementRepo.GetByID(ctx, disbursementID)
+	if err != nil {
+		log.WithField("disbursementID", disbursementID).WithError(err).Error("Failed to get loan disbursement")
+		return nil, errors.New("99999")
+	}
+	if disbursement == nil {
+		log.WithField("disbursementID", disbursementID).Error("Loan disbursement not found")
+		return nil, errors.New("10001")
+	}
+	if disbursement.DisbursementStatus != enum.LoanDisbursementPending {
+		log.WithField("disbursementID", disbursementID).Error("Signature can only be requested for a pending disbursement")
+		return nil, errors.New("10003")
+	}
+
+	valid, err := s.GetValidSignature(ctx, disbursementID)
+	if err != nil {
+		return nil, err
+	}
+	if valid != nil {
+		log.WithField("disbursementID", disbursementID).Error("Agreement is already signed")
+		return nil, errors.New("10003")
+	}
+
+	loan, err := s.LoanRepo.GetByID(ctx, disbursement.LoanID)
+	if err != nil || loan == nil {
+		log.WithField("loanID", disbursement.LoanID).WithError(err).Error("Failed to get loan")
+		return nil, errors.New("99999")
+	}
+
+	agreement, err := s.disbursementAgreement(ctx, disbursement)
+	if err != nil {
+		return nil, err
+	}
+
+	signature, err := s.RequestSignature(ctx, loan, disbursement, agreement)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.toResponseDTO(signature)
+}
+
+// Callback records the outcome reported by the provider. A callback the provider can not vouch for
+// changes nothing, a signature by someone else than the borrower or on another document is stored as
+// rejected.
+func (s *SignatureSvcImpl) Callback(ctx context.Context, request *dto.SignatureCallbackRequestDTO) (*dto.AgreementSignatureResponseDTO, error) {
+	log.WithFields(log.Fields{
+		"providerReference": request.ProviderReference,
+		"signerID":          request.SignerID,
+	}).Info("Processing signature callback")
+
+	err := s.Validator.ValidateCreate(request)
+	if err != nil {
+		log.WithField("providerReference", request.ProviderReference).Errorf("Validation failed: %s", err)
+		return nil, err
+	}
+
+	err = s.Provider.VerifyCallback(ctx, esign.Callback{
+		ProviderReference: request.ProviderReference,
+		SignerID:          request.SignerID,
+		SignedAt:          request.SignedAt,
+		DocumentHash:      request.DocumentHash,
+		Signature:         request.Signature,
+	})
+	if err != nil {
+		log.WithField("providerReference", request.ProviderReference).WithError(err).Error("Signature callback not verified by the provider")
+		return nil, errors.New("10012")
+	}
+
+	txnCtx := dbtxn.Begin(&ctx)
+	defer func() {
+		if err := txnCtx.Commit(); err != nil {
+			log.WithField("providerReference", request.ProviderReference).WithError(err).Error("Transaction commit failed")
+		}
+	}()
+
+	signature, err := s.Repo.GetByProviderReferenceForUpdate(ctx, s.Provider.Name(), request.ProviderReference)
+	if err != nil {
+		log.WithField("providerReference", request.ProviderReference).WithError(err).Error("Failed to get agreement signature")
+		txnCtx.AppendError(err)
+		return nil, errors.New("99999")
+	}
+	if signature == nil {
+		log.WithField("providerReference", request.ProviderReference).Error("Agreement signature not found")
+		return nil, errors.New("10001")
+	}
+	if signature.Status != enum.SignatureRequested {
+		log.WithFields(log.Fields{
+			"signatureID": signature.ID,
+			"status":      signature.Status,
+		}).Error("Agreement signature is not waiting for a callback")
+		return nil, errors.New("10012")
+	}
+
+	agreement, err := s.LoanAgreementRepo.GetByID(ctx, signature.LoanAgreementID)
+	if err != nil || agreement == nil {
+		log.WithField("agreementID", signature.LoanAgreementID).WithError(err).Error("Failed to get loan agreement")
+		txnCtx.AppendError(errors.New("loan agreement not found"))
+		return nil, errors.New("99999")
+	}
+
+	originalHash, err := s.documentHash(ctx, agreement)
+	if err != nil {
+		log.WithField("agreementID", agreement.ID).WithError(err).Error("Failed to hash loan agreement")
+		txnCtx.AppendError(err)
+		return nil, errors.New("99999")
+	}
+
+	signedBy := strconv.FormatInt(request.SignerID, 10)
+	signedAt := request.SignedAt
+	signedHash := strings.ToLower(request.DocumentHash)
+	signature.SignedBy = &signedBy
+	signature.SignedAt = &signedAt
+	signature.SignedDocumentHash = &signedHash
+
+	var reason string
+	switch {
+	case request.SignerID != signature.SignerID:
+		reason = "signer is not the borrower of the loan"
+	case signedHash != originalHash:
+		reason = "signed document does not match the generated agreement"
+	case signedAt.After(time.Now().Add(signedAtTolerance)):
+		reason = "signing time is in the future"
+	}
+
+	signature.Status = enum.SignatureSigned
+	if reason != "" {
+		signature.Status = enum.SignatureRejected
+		signature.RejectionReason = &reason
+	}
+
+	err = s.Repo.Update(ctx, signature)
+	if err != nil {
+		log.WithField("signatureID", signature.ID).WithError(err).Error("Failed to update agreement signature")
+		txnCtx.AppendError(err)
+		return nil, errors.New("99999")
+	}
+
+	if reason != "" {
+		// the rejection is kept, the disbursement needs a new signature request
+		log.WithFields(log.Fields{
+			"signatureID": signature.ID,
+			"reason":      reason,
+		}).Error("Agreement signature rejected")
+		return nil, errors.New("10012")
+	}
+
+	log.WithField("signatureID", signature.ID).Info("Agreement signed successfully")
+	return s.toResponseDTO(signature)
+}
+
+// GetByDisbursementID returns the signature requests of a disbursement, most recent first
+func (s *SignatureSvcImpl) GetByDisbursementID(ctx context.Context, disbursementID int64) ([]dto.AgreementSignatureResponseDTO, error) {
+	signatures, err := s.Repo.GetByDisbursementID(ctx, disbursementID)
+	if err != nil {
+		log.WithField("disbursementID", disbursementID).WithError(err).Error("Failed to get agreement signatures")
+		return nil, errors.New("99999")
+	}
+
+	signatureDTOs := []dto.AgreementSignatureResponseDTO{}
+	for _, signature := range signatures {
+		signatureRes, err := s.toResponseDTO(&signature)
+		if err != nil {
+			return nil, err
+		}
+		signatureDTOs = append(signatureDTOs, *signatureRes)
+	}
+
+	return signatureDTOs, nil
+}
+
+// GetValidSignature returns the accepted signature of a disbursement, nil when the agreement is not
+// signed yet
+func (s *SignatureSvcImpl) GetValidSignature(ctx context.Context, disbursementID int64) (*repo.AgreementSignature, error) {
+	signatures, err := s.Repo.GetByDisbursementID(ctx, disbursementID)
+	if err != nil {
+		log.WithField("disbursementID", disbursementID).WithError(err).Error("Failed to get agreement signatures")
+		return nil, errors.New("99999")
+	}
+
+	for _, signature := range signatures {
+		if signature.Status == enum.SignatureSigned {
+			return &signature, nil
+		}
+	}
+
+	return nil, nil
+}
+
+// disbursementAgreement returns the agreement generated for a disbursement
+func (s *SignatureSvcImpl) disbursementAgreement(ctx context.Context, disbursement *repo.LoanDisbursement) (*repo.LoanAgreement, error) {
+	agreements, err := s.LoanAgreementRepo.GetByLoanID(ctx, disbursement.LoanID)
+	if err != nil {
+		log.WithField("loanID", disbursement.LoanID).WithError(err).Error("Failed to get loan agreements")
+		return nil, errors.New("99999")
+	}
+
+	for _, agreement := range agreements {
+		if agreement.DocumentURL == disbursement.AgreementURL {
+			return &agreement, nil
+		}
+	}
+
+	log.WithField("disbursementID", disbursement.ID).Error("No loan agreement generated for the disbursement")
+	return nil, errors.New("10001")
+}
+
+// downloadURL returns a signed download URL of the agreement for the provider, agreements generated
+// before documents were recorded are still served by their store URL
+func (s *SignatureSvcImpl) downloadURL(ctx context.Context, agreement *repo.LoanAgreement) (string, error) {
+	document, err := s.DocumentRepo.GetByStorageKey(ctx, agreement.DocumentKey)
+	if err != nil {
+		return "", err
+	}
+	if document == nil {
+		return agreement.DocumentURL, nil
+	}
+
+	url, _ := s.URLSigner.Sign(document.ID, time.Now())
+	return url, nil
+}
+
+// documentHash returns the hash of the agreement as generated, agreements generated before hashing
+// are hashed from the document store
+func (s *SignatureSvcImpl) documentHash(ctx context.Context, agreement *repo.LoanAgreement) (string, error) {
+	if agreement.DocumentHash != nil {
+		return *agreement.DocumentHash, nil
+	}
+
+	content, err := s.DocumentStore.Get(ctx, agreement.DocumentKey)
+	if err != nil {
+		return "", err
+	}
+
+	return storage.Checksum(content), nil
+}
+
+func (s *SignatureSvcImpl) toResponseDTO(signature *repo.AgreementSignature) (*dto.AgreementSignatureResponseDTO, error) {
+	var signatureRes dto.AgreementSignatureResponseDTO
+	err := mapstructure.Decode(signature, &signatureRes)
+	if err != nil {
+		log.WithField("signatureID", signature.ID).WithError(err).Error("Failed to map agreement signature to DTO")
+		return nil, errors.New("99999")
+	}
+	signatureRes.SignedAt = signature.SignedAt
+	signatureRes.CreatedAt = signature.CreatedAt
+	signatureRes.UpdatedAt = signature.UpdatedAt
+
+	return &signatureRes, nil
+}
@@ -0,0 +1,404 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"github.com/mitchellh/mapstructure"
+	log "github.com/sirupsen/logrus"
+	"github.com/test/loan-service/internal/dto"
+	"github.com/test/loan-service/internal/enum"
+	"github.com/test/loan-service/internal/money"
+	"github.com/test/loan-service/internal/pdf"
+	repo "github.com/test/loan-service/internal/repository"
+	"github.com/test/loan-service/internal/service/models"
+	"github.com/test/loan-service/internal/service/validator"
+	"github.com/test/loan-service/internal/utils"
+	"go.uber.org/dig"
+	"strconv"
+	"strings"
+	"time"
+)
+
+//go:generate mockgen -source=$GOFILE -destination=$PROJ/internal/generated/mock/mock_$GOPACKAGE/$GOFILE
+
+type (
+	// TaxSvc withholds income tax from the interest paid to lenders. The rate depends on the lender
+	// type and on whether the lender has a tax ID, a lender without a tax profile is taxed as an
+	// individual without a tax ID. Every withholding is stored so a yearly certificate can be issued.
+	TaxSvc interface {
+		GetProfile(ctx context.Context, lenderID int64) (*dto.LenderTaxProfileResponseDTO, error)
+		UpdateProfile(ctx context.Context, request *dto.LenderTaxProfileRequestDTO) (*dto.LenderTaxProfileResponseDTO, error)
+		GetRates(ctx context.Context) ([]dto.WithholdingTaxRateResponseDTO, error)
+		UpdateRate(ctx context.Context, lenderType enum.LenderType, request *dto.WithholdingTaxRateRequestDTO) error
+		Calculate(ctx context.Context, lenderID int64, taxableAmount money.Amount) (*repo.TaxWithholding, error)
+		Withhold(ctx context.Context, withholding *repo.TaxWithholding) error
+		GetCertificate(ctx context.Context, request models.TaxCertificateRequest) (*models.TaxCertificateFile, error)
+	}
+
+	TaxSvcImpl struct {
+		dig.In
+		ProfileRepo      repo.LenderTaxProfileRepo
+		RateRepo         repo.WithholdingTaxRateRepo
+		WithholdingRepo  repo.TaxWithholdingRepo
+		LenderRepo       repo.LenderRepo
+		ProfileValidator validator.LenderTaxProfileValidatorImpl
+		RateValidator    validator.WithholdingTaxRateValidatorImpl
+	}
+)
+
+func NewTaxSvc(impl TaxSvcImpl) TaxSvc {
+	return &impl
+}
+
+// GetProfile returns the tax profile of a lender with the rate withheld from its interest
+func (s *TaxSvcImpl) GetProfile(ctx context.Context, lenderID int64) (*dto.LenderTaxProfileResponseDTO, error) {
+	profile, err := s.profile(ctx, lenderID)
+	if err != nil {
+		log.WithField("lenderID", lenderID).WithError(err).Error("Failed to get lender tax profile")
+		return nil, errors.New("99999")
+	}
+
+	return s.toProfileResponseDTO(ctx, profile)
+}
+
+// UpdateProfile sets the lender type and tax ID of a lender, it applies to the interest paid from now on
+func (s *TaxSvcImpl) UpdateProfile(ctx context.Context, request *dto.LenderTaxProfileRequestDTO) (*dto.LenderTaxProfileResponseDTO, error) {
+	log.WithFields(log.Fields{
+		"lenderID":   request.LenderID,
+		"lenderType": request.LenderType,
+	}).Info("Updating lender tax profile")
+
+	// an empty tax ID means the lender has none
+	if request.TaxID != nil {
+		taxID := strings.TrimSpace(*request.TaxID)
+		request.TaxID = &taxID
+		if taxID == "" {
+			request.TaxID = nil
+		}
+	}
+
+	err := s.ProfileValidator.ValidateUpdate(request)
+	if err != nil {
+		log.WithField("lenderID", request.LenderID).Errorf("Validation failed: %s", err)
+		return nil, err
+	}
+
+	lender, err := s.LenderRepo.GetByID(ctx, request.LenderID)
+	if err != nil {
+		log.WithField("lenderID", request.LenderID).WithError(err).Error("Failed to get lender")
+		return nil, errors.New("99999")
+	}
+	if lender == nil {
+		log.WithField("lenderID", request.LenderID).Warn("Lender not found")
+		return nil, errors.New("10001")
+	}
+
+	profile := repo.LenderTaxProfile{
+		LenderID:   request.LenderID,
+		LenderType: request.LenderType,
+		TaxID:      request.TaxID,
+	}
+
+	_, err = s.ProfileRepo.Upsert(ctx, &profile)
+	if err != nil {
+		log.WithField("lenderID", request.LenderID).WithError(err).Error("Failed to save lender tax profile")
+		return nil, errors.New("99999")
+	}
+
+	log.WithField("lenderID", request.LenderID).Info("Lender tax profile updated successfully")
+	return s.GetProfile(ctx, request.LenderID)
+}
+
+func (s *TaxSvcImpl) GetRates(ctx context.Context) ([]dto.WithholdingTaxRateResponseDTO, error) {
+	rates, err := s.RateRepo.GetAll(ctx)
+	if err != nil {
+		log.WithError(err).Error("Failed to get withholding tax rates")
+		return nil, errors.New("99999")
+	}
+
+	rateDTOs := []dto.WithholdingTaxRateResponseDTO{}
+	for _, rate := range rates {
+		var rateRes dto.WithholdingTaxRateResponseDTO
+		err = mapstructure.Decode(rate, &rateRes)
+		if err != nil {
+			log.WithField("lenderType", rate.LenderType).WithError(err).Error("Failed to map withholding tax rate to DTO")
+			return nil, errors.New("99999")
+		}
+		rateRes.CreatedAt = rate.CreatedAt
+		rateRes.UpdatedAt = rate.UpdatedAt
+		rateRes.DeletedAt = rate.DeletedAt
+
+		rateDTOs = append(rateDTOs, rateRes)
+	}
+
+	return rateDTOs, nil
+}
+
+func (s *TaxSvcImpl) UpdateRate(ctx context.Context, lenderType enum.LenderType, request *dto.WithholdingTaxRateRequestDTO) error {
+	log.WithFields(log.Fields{
+		"lenderType":  lenderType,
+		"rate":        request.Rate,
+		"noTaxIDRate": request.NoTaxIDRate,
+	}).Info("Updating withholding tax rate")
+
+	if !lenderType.IsValid() {
+		log.WithField("lenderType", lenderType).Error("Invalid LenderType")
+		return errors.New("10002")
+	}
+
+	err := s.RateValidator.ValidateUpdate(request)
+	if err != nil {
+		log.WithField("lenderType", lenderType).Errorf("Validation failed: %s", err)
+		return err
+	}
+
+	rate, err := s.RateRepo.GetByLenderType(ctx, lenderType)
+	if err != nil {
+		log.WithField("lenderType", lenderType).WithError(err).Error("Failed to get withholding tax rate")
+		return errors.New("99999")
+	}
+	if rate == nil {
+		log.WithField("lenderType", lenderType).Warn("Withholding tax rate not found")
+		return errors.New("10001")
+	}
+
+	rate.Rate = request.Rate
+	rate.NoTaxIDRate = request.NoTaxIDRate
+	err = s.RateRepo.Update(ctx, rate)
+	if err != nil {
+		log.WithField("lenderType", lenderType).WithError(err).Error("Failed to update withholding tax rate")
+		return errors.New("99999")
+	}
+
+	log.WithField("lenderType", lenderType).Info("Withholding tax rate updated successfully")
+	return nil
+}
+
+// Calculate returns the tax to withhold from interest earned by a lender, with the lender type and
+// tax ID it was based on. The result is not stored, Withhold stores it once it is linked to the
+// allocation it was taken from.
+func (s *TaxSvcImpl) Calculate(ctx context.Context, lenderID int64, taxableAmount money.Amount) (*repo.TaxWithholding, error) {
+	profile, err := s.profile(ctx, lenderID)
+	if err != nil {
+		log.WithField("lenderID", lenderID).WithError(err).Error("Failed to get lender tax profile")
+		return nil, err
+	}
+
+	rate, err := s.rate(ctx, profile)
+	if err != nil {
+		return nil, err
+	}
+
+	return &repo.TaxWithholding{
+		LenderID:      lenderID,
+		LenderType:    profile.LenderType,
+		TaxID:         profile.TaxID,
+		TaxableAmount: taxableAmount,
+		Rate:          rate,
+		TaxAmount:     utils.CalculatePercentage(taxableAmount, rate),
+	}, nil
+}
+
+// Withhold stores a calculated withholding, it must be linked to its repayment allocation
+func (s *TaxSvcImpl) Withhold(ctx context.Context, withholding *repo.TaxWithholding) error {
+	var err error
+	withholding.ID, err = s.WithholdingRepo.Create(ctx, withholding)
+	if err != nil {
+		log.WithFields(log.Fields{
+			"lenderID":              withholding.LenderID,
+			"repaymentAllocationID": withholding.RepaymentAllocationID,
+		}).WithError(err).Error("Failed to create tax withholding")
+		return err
+	}
+
+	return nil
+}
+
+// GetCertificate renders the tax withheld from a lender in a calendar year, one line per repayment
+// allocation. The lender type and tax ID of every line are the ones the tax was withheld with.
+func (s *TaxSvcImpl) GetCertificate(ctx context.Context, request models.TaxCertificateRequest) (*models.TaxCertificateFile, error) {
+	log.WithFields(log.Fields{
+		"lenderID": request.LenderID,
+		"year":     request.Year,
+		"format":   request.Format,
+	}).Info("Generating tax certificate")
+
+	if !request.Format.IsValid() {
+		log.WithField("format", request.Format).Error("Invalid TaxCertificateFormat")
+		return nil, errors.New("10002")
+	}
+	if request.Year < 2000 || request.Year > time.Now().Year() {
+		log.WithField("year", request.Year).Error("Tax certificate year out of range")
+		return nil, errors.New("10003")
+	}
+
+	from := time.Date(request.Year, time.January, 1, 0, 0, 0, 0, time.UTC)
+	withholdings, err := s.WithholdingRepo.GetByLenderID(ctx, request.LenderID, from, from.AddDate(1, 0, 0))
+	if err != nil {
+		log.WithField("lenderID", request.LenderID).WithError(err).Error("Failed to get tax withholdings")
+		return nil, errors.New("99999")
+	}
+
+	profile, err := s.profile(ctx, request.LenderID)
+	if err != nil {
+		log.WithField("lenderID", request.LenderID).WithError(err).Error("Failed to get lender tax profile")
+		return nil, errors.New("99999")
+	}
+
+	certificateNumber := fmt.Sprintf("WHT-%d-%06d", request.Year, request.LenderID)
+	file := models.TaxCertificateFile{
+		FileName: fmt.Sprintf("%s.%s", certificateNumber, request.Format),
+	}
+	switch request.Format {
+	case enum.TaxCertificateCSV:
+		file.ContentType = "text/csv"
+		file.Content, err = s.renderCSV(certificateNumber, withholdings)
+	case enum.TaxCertificatePDF:
+		file.ContentType = "application/pdf"
+		file.Content = s.renderPDF(certificateNumber, request.Year, profile, withholdings)
+	}
+	if err != nil {
+		log.WithField("lenderID", request.LenderID).WithError(err).Error("Failed to render tax certificate")
+		return nil, errors.New("99999")
+	}
+
+	log.WithFields(log.Fields{
+		"lenderID":     request.LenderID,
+		"year":         request.Year,
+		"withholdings": len(withholdings),
+	}).Info("Tax certificate generated successfully")
+	return &file, nil
+}
+
+func (s *TaxSvcImpl) renderCSV(certificateNumber string, withholdings []repo.TaxWithholding) ([]byte, error) {
+	var buf bytes.Buffer
+	writer := csv.NewWriter(&buf)
+
+	records := [][]string{{
+		"certificate_number", "lender_id", "lender_type", "tax_id", "withheld_at", "loan_id",
+		"loan_funding_id", "repayment_id", "taxable_amount", "rate", "tax_amount",
+	}}
+	for _, withholding := range withholdings {
+		records = append(records, []string{
+			certificateNumber,
+			strconv.FormatInt(withholding.LenderID, 10),
+			string(withholding.LenderType),
+			taxIDOrEmpty(withholding.TaxID),
+			withholding.WithheldAt.Format("2006-01-02"),
+			strconv.FormatInt(withholding.LoanID, 10),
+			strconv.FormatInt(withholding.LoanFundingID, 10),
+			strconv.FormatInt(withholding.RepaymentID, 10),
+			withholding.TaxableAmount.String(),
+			strconv.FormatFloat(withholding.Rate, 'f', -1, 64),
+			withholding.TaxAmount.String(),
+		})
+	}
+
+	err := writer.WriteAll(records)
+	if err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
+
+func (s *TaxSvcImpl) renderPDF(certificateNumber string, year int, profile *repo.LenderTaxProfile, withholdings []repo.TaxWithholding) []byte {
+	doc := pdf.New(fmt.Sprintf("Withholding Tax Certificate %s", certificateNumber))
+	doc.Heading("Withholding Tax Certificate")
+	doc.Space()
+	doc.Row("Certificate number", certificateNumber, 140)
+	doc.Row("Tax year", strconv.Itoa(year), 140)
+	doc.Row("Lender ID", strconv.FormatInt(profile.LenderID, 10), 140)
+	doc.Row("Lender type", string(profile.LenderType), 140)
+	doc.Row("Tax ID", taxIDOrEmpty(profile.TaxID), 140)
+	doc.Space()
+
+	var taxable, tax money.Amount
+	doc.Bold("Date         Loan ID   Funding ID   Taxable interest   Rate (%)   Tax withheld")
+	for _, withholding := range withholdings {
+		taxable += withholding.TaxableAmount
+		tax += withholding.TaxAmount
+		doc.Text(fmt.Sprintf("%-12s %-9d %-12d %-18s %-10s %s",
+			withholding.WithheldAt.Format("2006-01-02"),
+			withholding.LoanID,
+			withholding.LoanFundingID,
+			withholding.TaxableAmount,
+			strconv.FormatFloat(withholding.Rate, 'f', -1, 64),
+			withholding.TaxAmount))
+	}
+	if len(withholdings) == 0 {
+		doc.Text("No tax was withheld in this year.")
+	}
+	doc.Space()
+	doc.Row("Total taxable interest", taxable.String(), 140)
+	doc.Row("Total tax withheld", tax.String(), 140)
+	doc.Space()
+	doc.Text(fmt.Sprintf("Issued on %s. The tax was withheld from the interest paid to the lender and is "+
+		"remitted to the tax office by the platform.", time.Now().Format("2006-01-02")))
+
+	return doc.Bytes()
+}
+
+// profile returns the tax profile of the lender, a lender without one is an individual without a tax ID
+func (s *TaxSvcImpl) profile(ctx context.Context, lenderID int64) (*repo.LenderTaxProfile, error) {
+	profile, err := s.ProfileRepo.GetByLenderID(ctx, lenderID)
+	if err != nil {
+		return nil, err
+	}
+	if profile == nil {
+		return &repo.LenderTaxProfile{
+			LenderID:   lenderID,
+			LenderType: enum.LenderIndividual,
+		}, nil
+	}
+
+	return profile, nil
+}
+
+// rate returns the percentage withheld for the profile, zero when its lender type has no rate
+func (s *TaxSvcImpl) rate(ctx context.Context, profile *repo.LenderTaxProfile) (float64, error) {
+	rate, err := s.RateRepo.GetByLenderType(ctx, profile.LenderType)
+	if err != nil {
+		log.WithField("lenderType", profile.LenderType).WithError(err).Error("Failed to get withholding tax rate")
+		return 0, err
+	}
+	if rate == nil {
+		log.WithField("lenderType", profile.LenderType).Info("No withholding tax rate for lender type, no tax withheld")
+		return 0, nil
+	}
+
+	if profile.TaxID == nil {
+		return rate.NoTaxIDRate, nil
+	}
+	return rate.Rate, nil
+}
+
+func (s *TaxSvcImpl) toProfileResponseDTO(ctx context.Context, profile *repo.LenderTaxProfile) (*dto.LenderTaxProfileResponseDTO, error) {
+	rate, err := s.rate(ctx, profile)
+	if err != nil {
+		return nil, errors.New("99999")
+	}
+
+	profileRes := dto.LenderTaxProfileResponseDTO{
+		LenderID:           profile.LenderID,
+		LenderType:         profile.LenderType,
+		TaxID:              profile.TaxID,
+		WithholdingTaxRate: rate,
+	}
+	if profile.ID != 0 {
+		profileRes.CreatedAt = &profile.CreatedAt
+		profileRes.UpdatedAt = &profile.UpdatedAt
+	}
+
+	return &profileRes, nil
+}
+
+func taxIDOrEmpty(taxID *string) string {
+	if taxID == nil {
+		return ""
+	}
+	return *taxID
+}
@@ -0,0 +1,79 @@
+package validator
+
+import (
+	"errors"
+	"github.com/asaskevich/govalidator"
+	"github.com/mitchellh/mapstructure"
+	log "github.com/sirupsen/logrus"
+	"github.com/test/loan-service/internal/dto"
+	"github.com/test/loan-service/internal/enum"
+	"github.com/test/loan-service/internal/service/models"
+	"go.uber.org/dig"
+	"io"
+	"text/template"
+)
+
+type AgreementTemplateValidatorImpl struct {
+	dig.In
+}
+
+func NewAgreementTemplateValidator(impl AgreementTemplateValidatorImpl) CustomValidator {
+	return &impl
+}
+
+// ValidateCreate checks a template request, the body must be a valid template that only uses the
+// placeholders of its agreement type
+func (v AgreementTemplateValidatorImpl) ValidateCreate(data interface{}) error {
+
+	var request dto.AgreementTemplateRequestDTO
+	err := mapstructure.Decode(data, &request)
+	if err != nil {
+		return errors.New("99999")
+	}
+
+	ok, err := govalidator.ValidateStruct(request)
+	if !ok {
+		log.Errorf("Validation failed: %s", err)
+		return errors.New("10003")
+	}
+
+	if !request.AgreementType.IsValid() {
+		log.Errorf("Invalid AgreementType: %s", request.AgreementType)
+		return errors.New("10003")
+	}
+
+	tmpl, err := template.New(string(request.AgreementType)).Option("missingkey=error").Parse(request.Body)
+	if err != nil {
+		log.Errorf("Invalid template body: %s", err)
+		return errors.New("10003")
+	}
+
+	// executing against empty data catches placeholders the agreement type does not provide
+	err = tmpl.Execute(io.Discard, agreementSampleData(request.AgreementType))
+	if err != nil {
+		log.Errorf("Template body uses unknown placeholders: %s", err)
+		return errors.New("10003")
+	}
+
+	return nil
+}
+
+func (v AgreementTemplateValidatorImpl) ValidateUpdate(data interface{}) error {
+	//TODO implement me
+	panic("implement me")
+}
+
+func (v AgreementTemplateValidatorImpl) ValidateTransitionStatus(from interface{}, to interface{}) bool {
+	//TODO implement me
+	panic("implement me")
+}
+
+func agreementSampleData(agreementType enum.AgreementType) interface{} {
+	switch agreementType {
+	case enum.AgreementBorrower:
+		return models.LoanAgreementData{}
+	case enum.AgreementLender:
+		return models.FundingAgreementData{}
+	}
+	return nil
+}
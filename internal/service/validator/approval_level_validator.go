@@ -0,0 +1,56 @@
+package validator
+
+import (
+	"errors"
+	"github.com/asaskevich/govalidator"
+	"github.com/mitchellh/mapstructure"
+	log "github.com/sirupsen/logrus"
+	"github.com/test/loan-service/internal/dto"
+	"go.uber.org/dig"
+)
+
+type ApprovalLevelValidatorImpl struct {
+	dig.In
+}
+
+func NewApprovalLevelValidator(impl ApprovalLevelValidatorImpl) CustomValidator {
+	return &impl
+}
+
+func (a ApprovalLevelValidatorImpl) ValidateCreate(data interface{}) error {
+	//TODO implement me
+	panic("implement me")
+}
+
+func (a ApprovalLevelValidatorImpl) ValidateUpdate(data interface{}) error {
+
+	var level dto.ApprovalLevelRequestDTO
+	err := mapstructure.Decode(data, &level)
+	if err != nil {
+		return errors.New("99999")
+	}
+
+	ok, err := govalidator.ValidateStruct(level)
+	if !ok {
+		log.Errorf("Validation failed: %s", err)
+		return errors.New("10003")
+	}
+
+	// Zero means the level is required for every loan
+	if level.MinAmount.Sign() < 0 {
+		log.Errorf("MinAmount must not be negative")
+		return errors.New("10003")
+	}
+
+	if level.MinGrade != nil && !level.MinGrade.IsValid() {
+		log.Errorf("Invalid MinGrade: %s", *level.MinGrade)
+		return errors.New("10003")
+	}
+
+	return nil
+}
+
+func (a ApprovalLevelValidatorImpl) ValidateTransitionStatus(from interface{}, to interface{}) bool {
+	//TODO implement me
+	panic("implement me")
+}
@@ -0,0 +1,90 @@
+package validator
+
+import (
+	"errors"
+	"github.com/asaskevich/govalidator"
+	"github.com/mitchellh/mapstructure"
+	log "github.com/sirupsen/logrus"
+	"github.com/test/loan-service/internal/dto"
+	"go.uber.org/dig"
+	"strings"
+)
+
+type AutoInvestRuleValidatorImpl struct {
+	dig.In
+}
+
+func NewAutoInvestRuleValidator(impl AutoInvestRuleValidatorImpl) CustomValidator {
+	return &impl
+}
+
+// ValidateCreate checks a rule. The monthly budget must fit at least one order, otherwise the rule
+// could never invest.
+func (v AutoInvestRuleValidatorImpl) ValidateCreate(data interface{}) error {
+
+	var rule dto.AutoInvestRuleRequestDTO
+	err := mapstructure.Decode(data, &rule)
+	if err != nil {
+		return errors.New("99999")
+	}
+
+	ok, err := govalidator.ValidateStruct(rule)
+	if !ok {
+		log.Errorf("Validation failed: %s", err)
+		return errors.New("10003")
+	}
+
+	for _, grade := range rule.LoanGrades {
+		if !grade.IsValid() {
+			log.Errorf("Invalid loan grade: %s", grade)
+			return errors.New("10003")
+		}
+	}
+
+	for _, loanType := range rule.LoanTypes {
+		if !loanType.IsValid() {
+			log.Errorf("Invalid loan type: %s", loanType)
+			return errors.New("10003")
+		}
+	}
+
+	for _, sector := range rule.BusinessSectors {
+		if strings.TrimSpace(sector) == "" {
+			log.Errorf("Business sector must not be empty")
+			return errors.New("10003")
+		}
+	}
+
+	if rule.MinTenure < 0 || rule.MaxTenure < 0 ||
+		(rule.MaxTenure > 0 && rule.MaxTenure < rule.MinTenure) {
+		log.Errorf("Invalid tenure range %d - %d", rule.MinTenure, rule.MaxTenure)
+		return errors.New("10003")
+	}
+
+	if rule.MinRate < 0 {
+		log.Errorf("MinRate must not be negative")
+		return errors.New("10003")
+	}
+
+	if !rule.AmountPerLoan.IsPositive() {
+		log.Errorf("AmountPerLoan must be greater than zero")
+		return errors.New("10003")
+	}
+
+	if rule.MonthlyBudget < rule.AmountPerLoan {
+		log.Errorf("MonthlyBudget must be at least AmountPerLoan")
+		return errors.New("10003")
+	}
+
+	return nil
+}
+
+// ValidateUpdate checks a rule the same way as ValidateCreate
+func (v AutoInvestRuleValidatorImpl) ValidateUpdate(data interface{}) error {
+	return v.ValidateCreate(data)
+}
+
+func (v AutoInvestRuleValidatorImpl) ValidateTransitionStatus(from interface{}, to interface{}) bool {
+	//TODO implement me
+	panic("implement me")
+}
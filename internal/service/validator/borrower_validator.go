@@ -0,0 +1,79 @@
+package validator
+
+import (
+	"errors"
+	"github.com/asaskevich/govalidator"
+	"github.com/mitchellh/mapstructure"
+	log "github.com/sirupsen/logrus"
+	"github.com/test/loan-service/internal/dto"
+	"go.uber.org/dig"
+	"strings"
+	"time"
+)
+
+type BorrowerValidatorImpl struct {
+	dig.In
+}
+
+func NewBorrowerValidator(impl BorrowerValidatorImpl) CustomValidator {
+	return &impl
+}
+
+// ValidateCreate checks the profile of a new borrower, whether the national ID is already registered is
+// checked by the service
+func (v BorrowerValidatorImpl) ValidateCreate(data interface{}) error {
+
+	var request dto.BorrowerRequestDTO
+	err := mapstructure.Decode(data, &request)
+	if err != nil {
+		return errors.New("99999")
+	}
+
+	ok, err := govalidator.ValidateStruct(request)
+	if !ok {
+		log.Errorf("Validation failed: %s", err)
+		return errors.New("10003")
+	}
+
+	if strings.TrimSpace(request.FullName) == "" {
+		log.Errorf("FullName must be provided")
+		return errors.New("10003")
+	}
+
+	if request.DateOfBirth.IsZero() || request.DateOfBirth.After(time.Now()) {
+		log.Errorf("DateOfBirth must be a date in the past")
+		return errors.New("10003")
+	}
+
+	if request.BusinessEstablishedYear < 0 || request.BusinessEstablishedYear > int64(time.Now().Year()) {
+		log.Errorf("BusinessEstablishedYear must not be in the future")
+		return errors.New("10003")
+	}
+
+	return nil
+}
+
+func (v BorrowerValidatorImpl) ValidateUpdate(data interface{}) error {
+	err := v.ValidateCreate(data)
+	if err != nil {
+		return err
+	}
+
+	var request dto.BorrowerRequestDTO
+	err = mapstructure.Decode(data, &request)
+	if err != nil {
+		return errors.New("99999")
+	}
+
+	if request.BorrowerID <= 0 {
+		log.Errorf("BorrowerID must be greater than zero")
+		return errors.New("10003")
+	}
+
+	return nil
+}
+
+func (v BorrowerValidatorImpl) ValidateTransitionStatus(from interface{}, to interface{}) bool {
+	//TODO implement me
+	panic("implement me")
+}
@@ -0,0 +1,78 @@
+package validator
+
+import (
+	"errors"
+	"github.com/asaskevich/govalidator"
+	"github.com/mitchellh/mapstructure"
+	log "github.com/sirupsen/logrus"
+	"github.com/test/loan-service/internal/dto"
+	"go.uber.org/dig"
+)
+
+type CreditScoringValidatorImpl struct {
+	dig.In
+}
+
+func NewCreditScoringValidator(impl CreditScoringValidatorImpl) CustomValidator {
+	return &impl
+}
+
+// ValidateCreate checks a scoring rule. A numeric factor matches a value range with at least one
+// bound, the business sector factor matches a sector name.
+func (c CreditScoringValidatorImpl) ValidateCreate(data interface{}) error {
+
+	var rule dto.CreditScoringRuleRequestDTO
+	err := mapstructure.Decode(data, &rule)
+	if err != nil {
+		return errors.New("99999")
+	}
+
+	ok, err := govalidator.ValidateStruct(rule)
+	if !ok {
+		log.Errorf("Validation failed: %s", err)
+		return errors.New("10003")
+	}
+
+	if !rule.Factor.IsValid() {
+		log.Errorf("Invalid credit scoring factor: %s", rule.Factor)
+		return errors.New("10003")
+	}
+
+	if rule.Points < 0 {
+		log.Errorf("Points must be non-negative")
+		return errors.New("10003")
+	}
+
+	if !rule.Factor.IsNumeric() {
+		if rule.MinValue != nil || rule.MaxValue != nil {
+			log.Errorf("Rule of factor %s matches a name, not a value range", rule.Factor)
+			return errors.New("10003")
+		}
+		return nil
+	}
+
+	if rule.MatchValue != "" {
+		log.Errorf("Rule of factor %s matches a value range, not a name", rule.Factor)
+		return errors.New("10003")
+	}
+	if rule.MinValue == nil && rule.MaxValue == nil {
+		log.Errorf("Rule of factor %s needs a min or max value", rule.Factor)
+		return errors.New("10003")
+	}
+	if rule.MinValue != nil && rule.MaxValue != nil && *rule.MinValue >= *rule.MaxValue {
+		log.Errorf("MinValue must be lower than MaxValue")
+		return errors.New("10003")
+	}
+
+	return nil
+}
+
+// ValidateUpdate checks a scoring rule the same way as ValidateCreate
+func (c CreditScoringValidatorImpl) ValidateUpdate(data interface{}) error {
+	return c.ValidateCreate(data)
+}
+
+func (c CreditScoringValidatorImpl) ValidateTransitionStatus(from interface{}, to interface{}) bool {
+	//TODO implement me
+	panic("implement me")
+}
@@ -0,0 +1,63 @@
+package validator
+
+import (
+	"errors"
+	"github.com/asaskevich/govalidator"
+	"github.com/mitchellh/mapstructure"
+	log "github.com/sirupsen/logrus"
+	"github.com/test/loan-service/internal/dto"
+	"go.uber.org/dig"
+	"strings"
+)
+
+type DocumentRequirementValidatorImpl struct {
+	dig.In
+}
+
+func NewDocumentRequirementValidator(impl DocumentRequirementValidatorImpl) CustomValidator {
+	return &impl
+}
+
+// ValidateCreate checks a document requirement, the document type is matched as sent by the approval
+// team so it can not carry surrounding spaces
+func (d DocumentRequirementValidatorImpl) ValidateCreate(data interface{}) error {
+
+	var requirement dto.DocumentRequirementRequestDTO
+	err := mapstructure.Decode(data, &requirement)
+	if err != nil {
+		return errors.New("99999")
+	}
+
+	ok, err := govalidator.ValidateStruct(requirement)
+	if !ok {
+		log.Errorf("Validation failed: %s", err)
+		return errors.New("10003")
+	}
+
+	if !requirement.LoanType.IsValid() {
+		log.Errorf("Invalid LoanType: %s", requirement.LoanType)
+		return errors.New("10003")
+	}
+
+	if strings.TrimSpace(requirement.DocumentType) != requirement.DocumentType {
+		log.Errorf("DocumentType must not start or end with spaces")
+		return errors.New("10003")
+	}
+
+	if strings.TrimSpace(requirement.Name) == "" {
+		log.Errorf("Name must not be blank")
+		return errors.New("10003")
+	}
+
+	return nil
+}
+
+// ValidateUpdate checks a document requirement the same way as ValidateCreate
+func (d DocumentRequirementValidatorImpl) ValidateUpdate(data interface{}) error {
+	return d.ValidateCreate(data)
+}
+
+func (d DocumentRequirementValidatorImpl) ValidateTransitionStatus(from interface{}, to interface{}) bool {
+	//TODO implement me
+	panic("implement me")
+}
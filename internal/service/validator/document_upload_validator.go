@@ -0,0 +1,68 @@
+package validator
+
+import (
+	"errors"
+	"github.com/asaskevich/govalidator"
+	"github.com/mitchellh/mapstructure"
+	log "github.com/sirupsen/logrus"
+	"github.com/test/loan-service/internal/dto"
+	"go.uber.org/dig"
+	"strings"
+)
+
+type DocumentUploadValidatorImpl struct {
+	dig.In
+}
+
+func NewDocumentUploadValidator(impl DocumentUploadValidatorImpl) CustomValidator {
+	return &impl
+}
+
+// ValidateCreate checks what an upload is linked to, the file itself is checked against the upload
+// policy by the service
+func (d DocumentUploadValidatorImpl) ValidateCreate(data interface{}) error {
+
+	var upload dto.DocumentUploadRequestDTO
+	err := mapstructure.Decode(data, &upload)
+	if err != nil {
+		return errors.New("99999")
+	}
+
+	ok, err := govalidator.ValidateStruct(upload)
+	if !ok {
+		log.Errorf("Validation failed: %s", err)
+		return errors.New("10003")
+	}
+
+	if !upload.EntityType.IsValid() {
+		log.Errorf("Invalid EntityType: %s", upload.EntityType)
+		return errors.New("10003")
+	}
+
+	if upload.EntityID <= 0 {
+		log.Errorf("EntityID must be positive")
+		return errors.New("10003")
+	}
+
+	if strings.TrimSpace(upload.DocumentType) != upload.DocumentType {
+		log.Errorf("DocumentType must not start or end with spaces")
+		return errors.New("10003")
+	}
+
+	if upload.UploadedBy < 0 {
+		log.Errorf("UploadedBy must not be negative")
+		return errors.New("10003")
+	}
+
+	return nil
+}
+
+func (d DocumentUploadValidatorImpl) ValidateUpdate(data interface{}) error {
+	//TODO implement me
+	panic("implement me")
+}
+
+func (d DocumentUploadValidatorImpl) ValidateTransitionStatus(from interface{}, to interface{}) bool {
+	//TODO implement me
+	panic("implement me")
+}
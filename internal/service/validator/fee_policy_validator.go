@@ -0,0 +1,56 @@
+package validator
+
+import (
+	"errors"
+	"github.com/asaskevich/govalidator"
+	"github.com/mitchellh/mapstructure"
+	log "github.com/sirupsen/logrus"
+	"github.com/test/loan-service/internal/dto"
+	"go.uber.org/dig"
+)
+
+type FeePolicyValidatorImpl struct {
+	dig.In
+}
+
+func NewFeePolicyValidator(impl FeePolicyValidatorImpl) CustomValidator {
+	return &impl
+}
+
+func (f FeePolicyValidatorImpl) ValidateCreate(data interface{}) error {
+	//TODO implement me
+	panic("implement me")
+}
+
+func (f FeePolicyValidatorImpl) ValidateUpdate(data interface{}) error {
+
+	var policy dto.FeePolicyRequestDTO
+	err := mapstructure.Decode(data, &policy)
+	if err != nil {
+		return errors.New("99999")
+	}
+
+	ok, err := govalidator.ValidateStruct(policy)
+	if !ok {
+		log.Errorf("Validation failed: %s", err)
+		return errors.New("10003")
+	}
+
+	// Ensure that both fees are percentages, zero means the fee is not charged
+	if policy.OriginationFeePercentage < 0 || policy.OriginationFeePercentage >= 100 {
+		log.Errorf("OriginationFeePercentage must be at least zero and lower than 100")
+		return errors.New("10003")
+	}
+
+	if policy.ServiceFeePercentage < 0 || policy.ServiceFeePercentage > 100 {
+		log.Errorf("ServiceFeePercentage must be between zero and 100")
+		return errors.New("10003")
+	}
+
+	return nil
+}
+
+func (f FeePolicyValidatorImpl) ValidateTransitionStatus(from interface{}, to interface{}) bool {
+	//TODO implement me
+	panic("implement me")
+}
@@ -0,0 +1,52 @@
+package validator
+
+import (
+	"errors"
+	"github.com/asaskevich/govalidator"
+	"github.com/mitchellh/mapstructure"
+	log "github.com/sirupsen/logrus"
+	"github.com/test/loan-service/internal/dto"
+	"go.uber.org/dig"
+)
+
+type FundingListingCancelValidatorImpl struct {
+	dig.In
+}
+
+func NewFundingListingCancelValidator(impl FundingListingCancelValidatorImpl) CustomValidator {
+	return &impl
+}
+
+// ValidateCreate checks the shape of a cancellation, whether the listing can still be cancelled is
+// checked by the service
+func (v FundingListingCancelValidatorImpl) ValidateCreate(data interface{}) error {
+
+	var request dto.FundingListingCancelRequestDTO
+	err := mapstructure.Decode(data, &request)
+	if err != nil {
+		return errors.New("99999")
+	}
+
+	ok, err := govalidator.ValidateStruct(request)
+	if !ok {
+		log.Errorf("Validation failed: %s", err)
+		return errors.New("10003")
+	}
+
+	if request.LenderID <= 0 {
+		log.Errorf("LenderID must be greater than zero")
+		return errors.New("10003")
+	}
+
+	return nil
+}
+
+func (v FundingListingCancelValidatorImpl) ValidateUpdate(data interface{}) error {
+	//TODO implement me
+	panic("implement me")
+}
+
+func (v FundingListingCancelValidatorImpl) ValidateTransitionStatus(from interface{}, to interface{}) bool {
+	//TODO implement me
+	panic("implement me")
+}
@@ -0,0 +1,69 @@
+package validator
+
+import (
+	"errors"
+	"github.com/asaskevich/govalidator"
+	"github.com/mitchellh/mapstructure"
+	log "github.com/sirupsen/logrus"
+	"github.com/test/loan-service/internal/dto"
+	"go.uber.org/dig"
+)
+
+type FundingListingValidatorImpl struct {
+	dig.In
+}
+
+func NewFundingListingValidator(impl FundingListingValidatorImpl) CustomValidator {
+	return &impl
+}
+
+// ValidateCreate checks a listing request, whether the funding can be sold is checked by the service
+func (v FundingListingValidatorImpl) ValidateCreate(data interface{}) error {
+
+	var listing dto.FundingListingRequestDTO
+	err := mapstructure.Decode(data, &listing)
+	if err != nil {
+		return errors.New("99999")
+	}
+
+	ok, err := govalidator.ValidateStruct(listing)
+	if !ok {
+		log.Errorf("Validation failed: %s", err)
+		return errors.New("10003")
+	}
+
+	if !listing.PrincipalAmount.IsPositive() {
+		log.Errorf("PrincipalAmount must be greater than zero")
+		return errors.New("10003")
+	}
+
+	if !listing.Price.IsPositive() {
+		log.Errorf("Price must be greater than zero")
+		return errors.New("10003")
+	}
+
+	return nil
+}
+
+// ValidateUpdate checks a purchase request
+func (v FundingListingValidatorImpl) ValidateUpdate(data interface{}) error {
+
+	var purchase dto.FundingPurchaseRequestDTO
+	err := mapstructure.Decode(data, &purchase)
+	if err != nil {
+		return errors.New("99999")
+	}
+
+	ok, err := govalidator.ValidateStruct(purchase)
+	if !ok {
+		log.Errorf("Validation failed: %s", err)
+		return errors.New("10003")
+	}
+
+	return nil
+}
+
+func (v FundingListingValidatorImpl) ValidateTransitionStatus(from interface{}, to interface{}) bool {
+	//TODO implement me
+	panic("implement me")
+}
@@ -0,0 +1,57 @@
+package validator
+
+import (
+	"errors"
+	"github.com/asaskevich/govalidator"
+	"github.com/mitchellh/mapstructure"
+	log "github.com/sirupsen/logrus"
+	"github.com/test/loan-service/internal/dto"
+	"go.uber.org/dig"
+)
+
+type FundingPolicyValidatorImpl struct {
+	dig.In
+}
+
+func NewFundingPolicyValidator(impl FundingPolicyValidatorImpl) CustomValidator {
+	return &impl
+}
+
+func (f FundingPolicyValidatorImpl) ValidateCreate(data interface{}) error {
+	//TODO implement me
+	panic("implement me")
+}
+
+func (f FundingPolicyValidatorImpl) ValidateUpdate(data interface{}) error {
+
+	var policy dto.FundingPolicyRequestDTO
+	err := mapstructure.Decode(data, &policy)
+	if err != nil {
+		return errors.New("99999")
+	}
+
+	ok, err := govalidator.ValidateStruct(policy)
+	if !ok {
+		log.Errorf("Validation failed: %s", err)
+		return errors.New("10003")
+	}
+
+	// Ensure that MinFundedPercentage is a percentage, 100 means partial funding is never disbursed
+	if policy.MinFundedPercentage <= 0 || policy.MinFundedPercentage > 100 {
+		log.Errorf("MinFundedPercentage must be greater than zero and at most 100")
+		return errors.New("10003")
+	}
+
+	// zero means an invested funding can not be cancelled anymore
+	if policy.CoolingOffHours < 0 {
+		log.Errorf("CoolingOffHours must be non-negative")
+		return errors.New("10003")
+	}
+
+	return nil
+}
+
+func (f FundingPolicyValidatorImpl) ValidateTransitionStatus(from interface{}, to interface{}) bool {
+	//TODO implement me
+	panic("implement me")
+}
@@ -0,0 +1,68 @@
+package validator
+
+import (
+	"errors"
+	"github.com/asaskevich/govalidator"
+	"github.com/mitchellh/mapstructure"
+	log "github.com/sirupsen/logrus"
+	"github.com/test/loan-service/internal/dto"
+	"go.uber.org/dig"
+)
+
+type InvestmentLimitValidatorImpl struct {
+	dig.In
+}
+
+func NewInvestmentLimitValidator(impl InvestmentLimitValidatorImpl) CustomValidator {
+	return &impl
+}
+
+func (i InvestmentLimitValidatorImpl) ValidateCreate(data interface{}) error {
+	//TODO implement me
+	panic("implement me")
+}
+
+func (i InvestmentLimitValidatorImpl) ValidateUpdate(data interface{}) error {
+
+	var limit dto.InvestmentLimitRequestDTO
+	err := mapstructure.Decode(data, &limit)
+	if err != nil {
+		return errors.New("99999")
+	}
+
+	ok, err := govalidator.ValidateStruct(limit)
+	if !ok {
+		log.Errorf("Validation failed: %s", err)
+		return errors.New("10003")
+	}
+
+	// Ensure that a funding always has a positive minimum
+	if !limit.MinTicketAmount.IsPositive() {
+		log.Errorf("MinTicketAmount must be greater than zero")
+		return errors.New("10003")
+	}
+
+	if limit.TicketIncrement.Sign() < 0 {
+		log.Errorf("TicketIncrement must not be negative")
+		return errors.New("10003")
+	}
+
+	if limit.MaxLoanSharePercentage <= 0 || limit.MaxLoanSharePercentage > 100 {
+		log.Errorf("MaxLoanSharePercentage must be greater than zero and at most 100")
+		return errors.New("10003")
+	}
+
+	// Ensure that a lender can at least fund the minimum ticket when the exposure is limited
+	if limit.MaxLenderExposure.Sign() < 0 ||
+		(limit.MaxLenderExposure.IsPositive() && limit.MaxLenderExposure < limit.MinTicketAmount) {
+		log.Errorf("MaxLenderExposure must be zero or at least MinTicketAmount")
+		return errors.New("10003")
+	}
+
+	return nil
+}
+
+func (i InvestmentLimitValidatorImpl) ValidateTransitionStatus(from interface{}, to interface{}) bool {
+	//TODO implement me
+	panic("implement me")
+}
@@ -0,0 +1,55 @@
+package validator
+
+import (
+	"errors"
+	"github.com/asaskevich/govalidator"
+	"github.com/mitchellh/mapstructure"
+	log "github.com/sirupsen/logrus"
+	"github.com/test/loan-service/internal/dto"
+	"go.uber.org/dig"
+)
+
+type InvestorClassValidatorImpl struct {
+	dig.In
+}
+
+func NewInvestorClassValidator(impl InvestorClassValidatorImpl) CustomValidator {
+	return &impl
+}
+
+func (v InvestorClassValidatorImpl) ValidateCreate(data interface{}) error {
+	//TODO implement me
+	panic("implement me")
+}
+
+func (v InvestorClassValidatorImpl) ValidateUpdate(data interface{}) error {
+
+	var request dto.InvestorClassRequestDTO
+	err := mapstructure.Decode(data, &request)
+	if err != nil {
+		return errors.New("99999")
+	}
+
+	ok, err := govalidator.ValidateStruct(request)
+	if !ok {
+		log.Errorf("Validation failed: %s", err)
+		return errors.New("10003")
+	}
+
+	if request.StaffID <= 0 {
+		log.Errorf("StaffID must be greater than zero")
+		return errors.New("10003")
+	}
+
+	if !request.InvestorClass.IsValid() {
+		log.Errorf("Invalid InvestorClass: %s", request.InvestorClass)
+		return errors.New("10003")
+	}
+
+	return nil
+}
+
+func (v InvestorClassValidatorImpl) ValidateTransitionStatus(from interface{}, to interface{}) bool {
+	//TODO implement me
+	panic("implement me")
+}
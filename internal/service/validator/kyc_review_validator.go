@@ -0,0 +1,77 @@
+package validator
+
+import (
+	"errors"
+	"github.com/asaskevich/govalidator"
+	"github.com/mitchellh/mapstructure"
+	log "github.com/sirupsen/logrus"
+	"github.com/test/loan-service/internal/dto"
+	"github.com/test/loan-service/internal/enum"
+	"go.uber.org/dig"
+	"strings"
+)
+
+type KycReviewValidatorImpl struct {
+	dig.In
+}
+
+func NewKycReviewValidator(impl KycReviewValidatorImpl) CustomValidator {
+	return &impl
+}
+
+// ValidateCreate checks the shape of a KYC review, whether the KYC is waiting for a review is checked
+// with ValidateTransitionStatus
+func (v KycReviewValidatorImpl) ValidateCreate(data interface{}) error {
+
+	var request dto.KycReviewRequestDTO
+	err := mapstructure.Decode(data, &request)
+	if err != nil {
+		return errors.New("99999")
+	}
+
+	ok, err := govalidator.ValidateStruct(request)
+	if !ok {
+		log.Errorf("Validation failed: %s", err)
+		return errors.New("10003")
+	}
+
+	if request.StaffID <= 0 {
+		log.Errorf("StaffID must be greater than zero")
+		return errors.New("10003")
+	}
+
+	// staff can only decide on a KYC, submitting it is up to the owner
+	if request.KycStatus != enum.KycVerified && request.KycStatus != enum.KycRejected {
+		log.Errorf("KycStatus must be verified or rejected")
+		return errors.New("10003")
+	}
+
+	if request.KycStatus == enum.KycRejected && (request.Note == nil || strings.TrimSpace(*request.Note) == "") {
+		log.Errorf("Note must be provided when the KYC is rejected")
+		return errors.New("10003")
+	}
+
+	return nil
+}
+
+func (v KycReviewValidatorImpl) ValidateUpdate(data interface{}) error {
+	//TODO implement me
+	panic("implement me")
+}
+
+// ValidateTransitionStatus allows a KYC to be submitted while unverified or rejected, and to be
+// decided while pending. Any change of the identity puts a KYC back to unverified, which is done by
+// the service and not checked here.
+func (v KycReviewValidatorImpl) ValidateTransitionStatus(from interface{}, to interface{}) bool {
+	currentStatus := from.(enum.KycStatus)
+	changeStatus := to.(enum.KycStatus)
+
+	switch currentStatus {
+	case enum.KycUnverified, enum.KycRejected:
+		return changeStatus == enum.KycPending
+	case enum.KycPending:
+		return changeStatus == enum.KycVerified || changeStatus == enum.KycRejected
+	default:
+		return false
+	}
+}
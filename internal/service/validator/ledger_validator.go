@@ -0,0 +1,82 @@
+package validator
+
+import (
+	"errors"
+	"github.com/mitchellh/mapstructure"
+	log "github.com/sirupsen/logrus"
+	"github.com/test/loan-service/internal/enum"
+	"github.com/test/loan-service/internal/money"
+	"github.com/test/loan-service/internal/service/models"
+	"go.uber.org/dig"
+)
+
+type LedgerValidatorImpl struct {
+	dig.In
+}
+
+func NewLedgerValidator(impl LedgerValidatorImpl) CustomValidator {
+	return &impl
+}
+
+// ValidateCreate checks that a journal entry is balanced, every debit must be matched by credits of
+// the same total
+func (l LedgerValidatorImpl) ValidateCreate(data interface{}) error {
+
+	var entry models.LedgerEntryRequest
+	err := mapstructure.Decode(data, &entry)
+	if err != nil {
+		return errors.New("99999")
+	}
+
+	if !entry.EntryType.IsValid() {
+		log.Errorf("Invalid EntryType: %s", entry.EntryType)
+		return errors.New("10003")
+	}
+
+	// Ensure that the entry moves money between at least two accounts
+	if len(entry.Postings) < 2 {
+		log.Errorf("Entry must have at least two postings")
+		return errors.New("10003")
+	}
+
+	debit, credit := money.Zero, money.Zero
+	for _, posting := range entry.Postings {
+		if !posting.AccountType.IsValid() {
+			log.Errorf("Invalid AccountType: %s", posting.AccountType)
+			return errors.New("10003")
+		}
+
+		// Ensure that Amount is greater than zero, the direction carries the sign
+		if posting.Amount <= 0 {
+			log.Errorf("Posting amount must be greater than zero")
+			return errors.New("10003")
+		}
+
+		switch posting.Direction {
+		case enum.LedgerDebit:
+			debit += posting.Amount
+		case enum.LedgerCredit:
+			credit += posting.Amount
+		default:
+			log.Errorf("Invalid Direction: %s", posting.Direction)
+			return errors.New("10003")
+		}
+	}
+
+	if debit != credit {
+		log.Errorf("Entry is not balanced: debit %s, credit %s", debit, credit)
+		return errors.New("10003")
+	}
+
+	return nil
+}
+
+func (l LedgerValidatorImpl) ValidateUpdate(data interface{}) error {
+	//TODO implement me
+	panic("implement me")
+}
+
+func (l LedgerValidatorImpl) ValidateTransitionStatus(from interface{}, to interface{}) bool {
+	//TODO implement me
+	panic("implement me")
+}
@@ -0,0 +1,60 @@
+package validator
+
+import (
+	"errors"
+	"github.com/asaskevich/govalidator"
+	"github.com/mitchellh/mapstructure"
+	log "github.com/sirupsen/logrus"
+	"github.com/test/loan-service/internal/dto"
+	"go.uber.org/dig"
+	"strings"
+)
+
+type LenderTaxProfileValidatorImpl struct {
+	dig.In
+}
+
+func NewLenderTaxProfileValidator(impl LenderTaxProfileValidatorImpl) CustomValidator {
+	return &impl
+}
+
+func (l LenderTaxProfileValidatorImpl) ValidateCreate(data interface{}) error {
+	//TODO implement me
+	panic("implement me")
+}
+
+func (l LenderTaxProfileValidatorImpl) ValidateUpdate(data interface{}) error {
+
+	var profile dto.LenderTaxProfileRequestDTO
+	err := mapstructure.Decode(data, &profile)
+	if err != nil {
+		return errors.New("99999")
+	}
+
+	ok, err := govalidator.ValidateStruct(profile)
+	if !ok {
+		log.Errorf("Validation failed: %s", err)
+		return errors.New("10003")
+	}
+
+	if !profile.LenderType.IsValid() {
+		log.Errorf("Invalid LenderType: %s", profile.LenderType)
+		return errors.New("10003")
+	}
+
+	// A tax ID (NPWP) has 15 or 16 digits, the usual dots and dashes are allowed
+	if profile.TaxID != nil {
+		digits := strings.NewReplacer(".", "", "-", "").Replace(*profile.TaxID)
+		if !govalidator.IsNumeric(digits) || (len(digits) != 15 && len(digits) != 16) {
+			log.Errorf("TaxID must have 15 or 16 digits")
+			return errors.New("10003")
+		}
+	}
+
+	return nil
+}
+
+func (l LenderTaxProfileValidatorImpl) ValidateTransitionStatus(from interface{}, to interface{}) bool {
+	//TODO implement me
+	panic("implement me")
+}
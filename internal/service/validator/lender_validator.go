@@ -0,0 +1,86 @@
+package validator
+
+import (
+	"errors"
+	"github.com/asaskevich/govalidator"
+	"github.com/mitchellh/mapstructure"
+	log "github.com/sirupsen/logrus"
+	"github.com/test/loan-service/internal/dto"
+	"github.com/test/loan-service/internal/enum"
+	"go.uber.org/dig"
+	"strings"
+	"time"
+)
+
+type LenderValidatorImpl struct {
+	dig.In
+}
+
+func NewLenderValidator(impl LenderValidatorImpl) CustomValidator {
+	return &impl
+}
+
+// ValidateCreate checks the profile of a new lender, whether the national ID is already registered is
+// checked by the service and the tax ID by the tax profile
+func (v LenderValidatorImpl) ValidateCreate(data interface{}) error {
+
+	var request dto.LenderRequestDTO
+	err := mapstructure.Decode(data, &request)
+	if err != nil {
+		return errors.New("99999")
+	}
+
+	ok, err := govalidator.ValidateStruct(request)
+	if !ok {
+		log.Errorf("Validation failed: %s", err)
+		return errors.New("10003")
+	}
+
+	if strings.TrimSpace(request.FullName) == "" {
+		log.Errorf("FullName must be provided")
+		return errors.New("10003")
+	}
+
+	if !request.LenderType.IsValid() {
+		log.Errorf("Invalid LenderType: %s", request.LenderType)
+		return errors.New("10003")
+	}
+
+	// an institution has no date of birth
+	if request.LenderType == enum.LenderIndividual && request.DateOfBirth == nil {
+		log.Errorf("DateOfBirth must be provided for individual lenders")
+		return errors.New("10003")
+	}
+
+	if request.DateOfBirth != nil && request.DateOfBirth.After(time.Now()) {
+		log.Errorf("DateOfBirth must be a date in the past")
+		return errors.New("10003")
+	}
+
+	return nil
+}
+
+func (v LenderValidatorImpl) ValidateUpdate(data interface{}) error {
+	err := v.ValidateCreate(data)
+	if err != nil {
+		return err
+	}
+
+	var request dto.LenderRequestDTO
+	err = mapstructure.Decode(data, &request)
+	if err != nil {
+		return errors.New("99999")
+	}
+
+	if request.LenderID <= 0 {
+		log.Errorf("LenderID must be greater than zero")
+		return errors.New("10003")
+	}
+
+	return nil
+}
+
+func (v LenderValidatorImpl) ValidateTransitionStatus(from interface{}, to interface{}) bool {
+	//TODO implement me
+	panic("implement me")
+}
@@ -0,0 +1,51 @@
+package validator
+
+import (
+	"errors"
+	"github.com/asaskevich/govalidator"
+	"github.com/mitchellh/mapstructure"
+	log "github.com/sirupsen/logrus"
+	"github.com/test/loan-service/internal/dto"
+	"go.uber.org/dig"
+)
+
+type LenderWalletValidatorImpl struct {
+	dig.In
+}
+
+func NewLenderWalletValidator(impl LenderWalletValidatorImpl) CustomValidator {
+	return &impl
+}
+
+// ValidateCreate validates a top up
+func (l LenderWalletValidatorImpl) ValidateCreate(data interface{}) error {
+
+	var topUp dto.WalletTopUpRequestDTO
+	err := mapstructure.Decode(data, &topUp)
+	if err != nil {
+		return errors.New("99999")
+	}
+
+	ok, err := govalidator.ValidateStruct(topUp)
+	if !ok {
+		log.Errorf("Validation failed: %s", err)
+		return errors.New("10003")
+	}
+
+	if !topUp.Amount.IsPositive() {
+		log.Errorf("Top up amount must be greater than zero")
+		return errors.New("10003")
+	}
+
+	return nil
+}
+
+func (l LenderWalletValidatorImpl) ValidateUpdate(data interface{}) error {
+	//TODO implement me
+	panic("implement me")
+}
+
+func (l LenderWalletValidatorImpl) ValidateTransitionStatus(from interface{}, to interface{}) bool {
+	//TODO implement me
+	panic("implement me")
+}
@@ -0,0 +1,58 @@
+package validator
+
+import (
+	"errors"
+	"github.com/asaskevich/govalidator"
+	"github.com/mitchellh/mapstructure"
+	log "github.com/sirupsen/logrus"
+	"github.com/test/loan-service/internal/dto"
+	"go.uber.org/dig"
+	"strings"
+)
+
+type LoanCancelValidatorImpl struct {
+	dig.In
+}
+
+func NewLoanCancelValidator(impl LoanCancelValidatorImpl) CustomValidator {
+	return &impl
+}
+
+// ValidateCreate checks the shape of a cancellation, whether the loan can still be cancelled is checked
+// by the service
+func (v LoanCancelValidatorImpl) ValidateCreate(data interface{}) error {
+
+	var request dto.LoanCancelRequestDTO
+	err := mapstructure.Decode(data, &request)
+	if err != nil {
+		return errors.New("99999")
+	}
+
+	ok, err := govalidator.ValidateStruct(request)
+	if !ok {
+		log.Errorf("Validation failed: %s", err)
+		return errors.New("10003")
+	}
+
+	if request.BorrowerID <= 0 {
+		log.Errorf("BorrowerID must be greater than zero")
+		return errors.New("10003")
+	}
+
+	if strings.TrimSpace(request.Reason) == "" {
+		log.Errorf("Reason must be provided")
+		return errors.New("10003")
+	}
+
+	return nil
+}
+
+func (v LoanCancelValidatorImpl) ValidateUpdate(data interface{}) error {
+	//TODO implement me
+	panic("implement me")
+}
+
+func (v LoanCancelValidatorImpl) ValidateTransitionStatus(from interface{}, to interface{}) bool {
+	//TODO implement me
+	panic("implement me")
+}
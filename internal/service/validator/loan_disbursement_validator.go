@@ -75,11 +75,6 @@ func (l LoanDisbursementValidatorImpl) ValidateUpdate(data interface{}) error {
 		return errors.New("10003")
 	}
 
-	if disbursement.SignedAgreementURL == "" {
-		log.Printf("SignedAgreementURL must be provided")
-		return errors.New("10003")
-	}
-
 	log.Printf("Validation passed for update loan disbursement request: LoanID=%d", disbursement.LoanID)
 	return nil
 }
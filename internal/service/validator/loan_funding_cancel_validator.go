@@ -0,0 +1,52 @@
+package validator
+
+import (
+	"errors"
+	"github.com/asaskevich/govalidator"
+	"github.com/mitchellh/mapstructure"
+	log "github.com/sirupsen/logrus"
+	"github.com/test/loan-service/internal/dto"
+	"go.uber.org/dig"
+)
+
+type LoanFundingCancelValidatorImpl struct {
+	dig.In
+}
+
+func NewLoanFundingCancelValidator(impl LoanFundingCancelValidatorImpl) CustomValidator {
+	return &impl
+}
+
+// ValidateCreate checks the shape of a cancellation, whether the funding can still be cancelled is
+// checked by the service
+func (v LoanFundingCancelValidatorImpl) ValidateCreate(data interface{}) error {
+
+	var request dto.LoanFundingCancelRequestDTO
+	err := mapstructure.Decode(data, &request)
+	if err != nil {
+		return errors.New("99999")
+	}
+
+	ok, err := govalidator.ValidateStruct(request)
+	if !ok {
+		log.Errorf("Validation failed: %s", err)
+		return errors.New("10003")
+	}
+
+	if request.LenderID <= 0 {
+		log.Errorf("LenderID must be greater than zero")
+		return errors.New("10003")
+	}
+
+	return nil
+}
+
+func (v LoanFundingCancelValidatorImpl) ValidateUpdate(data interface{}) error {
+	//TODO implement me
+	panic("implement me")
+}
+
+func (v LoanFundingCancelValidatorImpl) ValidateTransitionStatus(from interface{}, to interface{}) bool {
+	//TODO implement me
+	panic("implement me")
+}
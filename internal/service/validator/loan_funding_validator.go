@@ -55,12 +55,6 @@ func (l LoanFundingValidatorImpl) ValidateCreate(data interface{}) error {
 		return errors.New("10003")
 	}
 
-	// Validate LenderAgreementURL if it's provided (URL validation)
-	if loanFunding.LenderAgreementURL != "" && !govalidator.IsURL(loanFunding.LenderAgreementURL) {
-		log.Errorf("LenderAgreementURL is invalid")
-		return errors.New("10003")
-	}
-
 	return nil
 
 }
@@ -0,0 +1,82 @@
+package validator
+
+import (
+	"errors"
+	"github.com/asaskevich/govalidator"
+	"github.com/mitchellh/mapstructure"
+	log "github.com/sirupsen/logrus"
+	"github.com/test/loan-service/internal/dto"
+	"go.uber.org/dig"
+	"time"
+)
+
+type LoanPricingValidatorImpl struct {
+	dig.In
+}
+
+func NewLoanPricingValidator(impl LoanPricingValidatorImpl) CustomValidator {
+	return &impl
+}
+
+// ValidateCreate checks a pricing. The lender yield can not exceed the borrower rate, otherwise the
+// platform would pay lenders more than it collects.
+func (v LoanPricingValidatorImpl) ValidateCreate(data interface{}) error {
+
+	var pricing dto.LoanPricingRequestDTO
+	err := mapstructure.Decode(data, &pricing)
+	if err != nil {
+		return errors.New("99999")
+	}
+
+	ok, err := govalidator.ValidateStruct(pricing)
+	if !ok {
+		log.Errorf("Validation failed: %s", err)
+		return errors.New("10003")
+	}
+
+	if !pricing.LoanGrade.IsValid() {
+		log.Errorf("Invalid loan grade: %s", pricing.LoanGrade)
+		return errors.New("10003")
+	}
+
+	if !pricing.LoanType.IsValid() {
+		log.Errorf("Invalid loan type: %s", pricing.LoanType)
+		return errors.New("10003")
+	}
+
+	if pricing.MinTenure <= 0 || pricing.MaxTenure < pricing.MinTenure {
+		log.Errorf("Invalid tenure band %d - %d", pricing.MinTenure, pricing.MaxTenure)
+		return errors.New("10003")
+	}
+
+	if pricing.BorrowerRate <= 0 || pricing.LenderYield <= 0 {
+		log.Errorf("BorrowerRate and LenderYield must be greater than zero")
+		return errors.New("10003")
+	}
+
+	if pricing.LenderYield > pricing.BorrowerRate {
+		log.Errorf("LenderYield must not exceed BorrowerRate")
+		return errors.New("10003")
+	}
+
+	validFrom := time.Now()
+	if pricing.ValidFrom != nil {
+		validFrom = *pricing.ValidFrom
+	}
+	if pricing.ValidUntil != nil && !pricing.ValidUntil.After(validFrom) {
+		log.Errorf("ValidUntil must be after ValidFrom")
+		return errors.New("10003")
+	}
+
+	return nil
+}
+
+// ValidateUpdate checks a pricing the same way as ValidateCreate
+func (v LoanPricingValidatorImpl) ValidateUpdate(data interface{}) error {
+	return v.ValidateCreate(data)
+}
+
+func (v LoanPricingValidatorImpl) ValidateTransitionStatus(from interface{}, to interface{}) bool {
+	//TODO implement me
+	panic("implement me")
+}
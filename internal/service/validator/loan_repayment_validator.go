@@ -0,0 +1,57 @@
+package validator
+
+import (
+	"errors"
+	"github.com/asaskevich/govalidator"
+	"github.com/mitchellh/mapstructure"
+	log "github.com/sirupsen/logrus"
+	"github.com/test/loan-service/internal/dto"
+	"go.uber.org/dig"
+)
+
+type LoanRepaymentValidatorImpl struct {
+	dig.In
+}
+
+func NewLoanRepaymentValidator(impl LoanRepaymentValidatorImpl) CustomValidator {
+	return &impl
+}
+
+func (l LoanRepaymentValidatorImpl) ValidateCreate(data interface{}) error {
+
+	var repayment dto.LoanRepaymentRequestDTO
+	err := mapstructure.Decode(data, &repayment)
+	if err != nil {
+		return errors.New("99999")
+	}
+
+	ok, err := govalidator.ValidateStruct(repayment)
+	if !ok {
+		log.Errorf("Validation failed: %s", err)
+		return errors.New("10003")
+	}
+
+	// Ensure that ReferenceNumber is not empty
+	if repayment.ReferenceNumber == "" {
+		log.Errorf("ReferenceNumber must be provided")
+		return errors.New("10003")
+	}
+
+	// Ensure that Amount is greater than zero
+	if repayment.Amount <= 0 {
+		log.Errorf("Amount must be greater than zero")
+		return errors.New("10003")
+	}
+
+	return nil
+}
+
+func (l LoanRepaymentValidatorImpl) ValidateUpdate(data interface{}) error {
+	//TODO implement me
+	panic("implement me")
+}
+
+func (l LoanRepaymentValidatorImpl) ValidateTransitionStatus(from interface{}, to interface{}) bool {
+	//TODO implement me
+	panic("implement me")
+}
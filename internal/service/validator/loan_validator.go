@@ -42,20 +42,21 @@ func (lv *LoanValidatorImpl) ValidateCreate(data interface{}) error {
 		return errors.New("10003")
 	}
 
-	if loan.LoanGrade == "" {
-		log.Error("LoanGrade must be provided")
+	// the loan grade is derived from these figures by credit scoring
+	if !loan.Detail.BusinessAnnualRevenue.IsPositive() {
+		log.Error("BusinessAnnualRevenue must be greater than zero")
 		return errors.New("10003")
 	}
 
-	if !loan.LoanType.IsValid() {
-		log.Error("Invalid LoanType")
+	if loan.Detail.BusinessExpense < 0 {
+		log.Error("BusinessExpense must be non-negative")
 		return errors.New("10003")
-
 	}
 
-	if loan.Rate < 0 {
-		log.Error("Rate must be non-negative")
+	if !loan.LoanType.IsValid() {
+		log.Error("Invalid LoanType")
 		return errors.New("10003")
+
 	}
 
 	if loan.Tenures <= 0 {
@@ -79,8 +80,8 @@ func (lv *LoanValidatorImpl) ValidateTransitionStatus(from interface{}, to inter
 
 	// Valid status transitions for loans
 	validTransitions := map[enum.LoanStatus][]enum.LoanStatus{
-		enum.Proposed:  {enum.Approved, enum.Rejected},
-		enum.Approved:  {enum.Invested},
+		enum.Proposed:  {enum.Approved, enum.Rejected, enum.Cancelled},
+		enum.Approved:  {enum.Invested, enum.Expired, enum.Cancelled},
 		enum.Invested:  {enum.Disbursed},
 		enum.Disbursed: {enum.Completed},
 	}
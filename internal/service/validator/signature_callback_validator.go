@@ -0,0 +1,58 @@
+package validator
+
+import (
+	"encoding/hex"
+	"errors"
+	"github.com/asaskevich/govalidator"
+	"github.com/mitchellh/mapstructure"
+	log "github.com/sirupsen/logrus"
+	"github.com/test/loan-service/internal/dto"
+	"go.uber.org/dig"
+)
+
+type SignatureCallbackValidatorImpl struct {
+	dig.In
+}
+
+func NewSignatureCallbackValidator(impl SignatureCallbackValidatorImpl) CustomValidator {
+	return &impl
+}
+
+// ValidateCreate checks the shape of a provider callback, whether the signature is valid is checked
+// by the service
+func (v SignatureCallbackValidatorImpl) ValidateCreate(data interface{}) error {
+
+	var callback dto.SignatureCallbackRequestDTO
+	err := mapstructure.Decode(data, &callback)
+	if err != nil {
+		return errors.New("99999")
+	}
+
+	ok, err := govalidator.ValidateStruct(callback)
+	if !ok {
+		log.Errorf("Validation failed: %s", err)
+		return errors.New("10003")
+	}
+
+	if callback.SignedAt.IsZero() {
+		log.Errorf("SignedAt must be provided")
+		return errors.New("10003")
+	}
+
+	if hash, err := hex.DecodeString(callback.DocumentHash); err != nil || len(hash) != 32 {
+		log.Errorf("DocumentHash must be a hex encoded SHA-256")
+		return errors.New("10003")
+	}
+
+	return nil
+}
+
+func (v SignatureCallbackValidatorImpl) ValidateUpdate(data interface{}) error {
+	//TODO implement me
+	panic("implement me")
+}
+
+func (v SignatureCallbackValidatorImpl) ValidateTransitionStatus(from interface{}, to interface{}) bool {
+	//TODO implement me
+	panic("implement me")
+}
@@ -0,0 +1,54 @@
+package validator
+
+import (
+	"errors"
+	"github.com/asaskevich/govalidator"
+	"github.com/mitchellh/mapstructure"
+	log "github.com/sirupsen/logrus"
+	"github.com/test/loan-service/internal/dto"
+	"go.uber.org/dig"
+)
+
+type SuitabilityValidatorImpl struct {
+	dig.In
+}
+
+func NewSuitabilityValidator(impl SuitabilityValidatorImpl) CustomValidator {
+	return &impl
+}
+
+// ValidateCreate checks that every answer of the suitability questionnaire is scored from 1 to 4
+func (v SuitabilityValidatorImpl) ValidateCreate(data interface{}) error {
+
+	var request dto.SuitabilityRequestDTO
+	err := mapstructure.Decode(data, &request)
+	if err != nil {
+		return errors.New("99999")
+	}
+
+	ok, err := govalidator.ValidateStruct(request)
+	if !ok {
+		log.Errorf("Validation failed: %s", err)
+		return errors.New("10003")
+	}
+
+	answers := []int64{request.InvestmentExperience, request.LossTolerance, request.InvestmentHorizon, request.IncomeStability}
+	for _, answer := range answers {
+		if answer < 1 || answer > 4 {
+			log.Errorf("Suitability answers must be between 1 and 4")
+			return errors.New("10003")
+		}
+	}
+
+	return nil
+}
+
+func (v SuitabilityValidatorImpl) ValidateUpdate(data interface{}) error {
+	//TODO implement me
+	panic("implement me")
+}
+
+func (v SuitabilityValidatorImpl) ValidateTransitionStatus(from interface{}, to interface{}) bool {
+	//TODO implement me
+	panic("implement me")
+}
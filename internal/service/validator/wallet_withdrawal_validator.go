@@ -0,0 +1,89 @@
+package validator
+
+import (
+	"errors"
+	"github.com/asaskevich/govalidator"
+	"github.com/mitchellh/mapstructure"
+	log "github.com/sirupsen/logrus"
+	"github.com/test/loan-service/internal/dto"
+	"github.com/test/loan-service/internal/enum"
+	"go.uber.org/dig"
+)
+
+type WalletWithdrawalValidatorImpl struct {
+	dig.In
+}
+
+func NewWalletWithdrawalValidator(impl WalletWithdrawalValidatorImpl) CustomValidator {
+	return &impl
+}
+
+func (w WalletWithdrawalValidatorImpl) ValidateCreate(data interface{}) error {
+
+	var withdrawal dto.WalletWithdrawalRequestDTO
+	err := mapstructure.Decode(data, &withdrawal)
+	if err != nil {
+		return errors.New("99999")
+	}
+
+	ok, err := govalidator.ValidateStruct(withdrawal)
+	if !ok {
+		log.Errorf("Validation failed: %s", err)
+		return errors.New("10003")
+	}
+
+	if !withdrawal.Amount.IsPositive() {
+		log.Errorf("Withdrawal amount must be greater than zero")
+		return errors.New("10003")
+	}
+
+	return nil
+}
+
+// ValidateUpdate validates the review of a withdrawal, a review either approves or rejects it
+func (w WalletWithdrawalValidatorImpl) ValidateUpdate(data interface{}) error {
+
+	var review dto.WalletWithdrawalReviewRequestDTO
+	err := mapstructure.Decode(data, &review)
+	if err != nil {
+		return errors.New("99999")
+	}
+
+	ok, err := govalidator.ValidateStruct(review)
+	if !ok {
+		log.Errorf("Validation failed: %s", err)
+		return errors.New("10003")
+	}
+
+	if review.Status != enum.WalletWithdrawalApproved && review.Status != enum.WalletWithdrawalRejected {
+		log.Errorf("Invalid withdrawal review status: %s", review.Status)
+		return errors.New("10003")
+	}
+
+	return nil
+}
+
+func (w WalletWithdrawalValidatorImpl) ValidateTransitionStatus(from interface{}, to interface{}) bool {
+
+	validTransitions := map[enum.WalletWithdrawalStatus][]enum.WalletWithdrawalStatus{
+		enum.WalletWithdrawalPending: {enum.WalletWithdrawalApproved, enum.WalletWithdrawalRejected},
+	}
+
+	currentStatus := from.(enum.WalletWithdrawalStatus)
+	changeStatus := to.(enum.WalletWithdrawalStatus)
+
+	allowedStatuses, ok := validTransitions[currentStatus]
+	if !ok {
+		log.Warnf("No valid transitions for current status: %s", currentStatus)
+		return false
+	}
+
+	for _, status := range allowedStatuses {
+		if status == changeStatus {
+			return true
+		}
+	}
+
+	log.Warnf("Invalid status transition from %s to %s", currentStatus, changeStatus)
+	return false
+}
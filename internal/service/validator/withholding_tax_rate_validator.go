@@ -0,0 +1,57 @@
+package validator
+
+import (
+	"errors"
+	"github.com/asaskevich/govalidator"
+	"github.com/mitchellh/mapstructure"
+	log "github.com/sirupsen/logrus"
+	"github.com/test/loan-service/internal/dto"
+	"go.uber.org/dig"
+)
+
+type WithholdingTaxRateValidatorImpl struct {
+	dig.In
+}
+
+func NewWithholdingTaxRateValidator(impl WithholdingTaxRateValidatorImpl) CustomValidator {
+	return &impl
+}
+
+func (w WithholdingTaxRateValidatorImpl) ValidateCreate(data interface{}) error {
+	//TODO implement me
+	panic("implement me")
+}
+
+func (w WithholdingTaxRateValidatorImpl) ValidateUpdate(data interface{}) error {
+
+	var rate dto.WithholdingTaxRateRequestDTO
+	err := mapstructure.Decode(data, &rate)
+	if err != nil {
+		return errors.New("99999")
+	}
+
+	ok, err := govalidator.ValidateStruct(rate)
+	if !ok {
+		log.Errorf("Validation failed: %s", err)
+		return errors.New("10003")
+	}
+
+	// Ensure that both rates are percentages, zero means no tax is withheld
+	if rate.Rate < 0 || rate.Rate > 100 || rate.NoTaxIDRate < 0 || rate.NoTaxIDRate > 100 {
+		log.Errorf("Rate and NoTaxIDRate must be between zero and 100")
+		return errors.New("10003")
+	}
+
+	// A lender without a tax ID is never taxed lower than a lender with one
+	if rate.NoTaxIDRate < rate.Rate {
+		log.Errorf("NoTaxIDRate must not be lower than Rate")
+		return errors.New("10003")
+	}
+
+	return nil
+}
+
+func (w WithholdingTaxRateValidatorImpl) ValidateTransitionStatus(from interface{}, to interface{}) bool {
+	//TODO implement me
+	panic("implement me")
+}
@@ -0,0 +1,208 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"github.com/mitchellh/mapstructure"
+	log "github.com/sirupsen/logrus"
+	"github.com/test/loan-service/internal/dto"
+	"github.com/test/loan-service/internal/enum"
+	repo "github.com/test/loan-service/internal/repository"
+	"github.com/test/loan-service/internal/service/validator"
+	"github.com/test/loan-service/internal/utils"
+	"github.com/typical-go/typical-rest-server/pkg/dbtxn"
+	"go.uber.org/dig"
+	"time"
+)
+
+//go:generate mockgen -source=$GOFILE -destination=$PROJ/internal/generated/mock/mock_$GOPACKAGE/$GOFILE
+
+type (
+	WalletWithdrawalSvc interface {
+		Create(ctx context.Context, request *dto.WalletWithdrawalRequestDTO) (*dto.WalletWithdrawalResponseDTO, error)
+		Review(ctx context.Context, id int64, request *dto.WalletWithdrawalReviewRequestDTO) (*dto.WalletWithdrawalResponseDTO, error)
+		GetAll(ctx context.Context, request repo.WalletWithdrawalRequest) ([]dto.WalletWithdrawalResponseDTO, error)
+	}
+
+	WalletWithdrawalSvcImpl struct {
+		dig.In
+		Repo      repo.WalletWithdrawalRepo
+		WalletSvc LenderWalletSvc
+		Validator validator.WalletWithdrawalValidatorImpl
+	}
+)
+
+func NewWalletWithdrawalSvc(impl WalletWithdrawalSvcImpl) WalletWithdrawalSvc {
+	return &impl
+}
+
+// Create requests a withdrawal, the amount is held in the lender wallet until staff review it
+func (s *WalletWithdrawalSvcImpl) Create(ctx context.Context, request *dto.WalletWithdrawalRequestDTO) (response *dto.WalletWithdrawalResponseDTO, err error) {
+	log.WithFields(log.Fields{
+		"lenderID": request.LenderID,
+		"amount":   request.Amount,
+	}).Info("Creating wallet withdrawal")
+
+	err = s.Validator.ValidateCreate(request)
+	if err != nil {
+		log.WithField("lenderID", request.LenderID).Errorf("Validation failed: %s", err)
+		return nil, err
+	}
+
+	txnCtx := dbtxn.Begin(&ctx)
+	defer func() {
+		if commitErr := txnCtx.Commit(); commitErr != nil {
+			log.WithError(commitErr).Error("Transaction commit failed")
+			response, err = nil, errors.New("99999")
+		}
+	}()
+
+	withdrawal := repo.WalletWithdrawal{
+		WithdrawalCode:    utils.GenerateAlphanumericCode(10),
+		LenderID:          request.LenderID,
+		Amount:            request.Amount,
+		BankName:          request.BankName,
+		BankAccountNumber: request.BankAccountNumber,
+		BankAccountName:   request.BankAccountName,
+		Status:            enum.WalletWithdrawalPending,
+		CreatedAt:         time.Now(),
+		UpdatedAt:         time.Now(),
+	}
+	withdrawal.ID, err = s.Repo.Create(ctx, &withdrawal)
+	if err != nil {
+		log.WithField("lenderID", request.LenderID).WithError(err).Error("Failed to create wallet withdrawal")
+		txnCtx.AppendError(err)
+		return nil, errors.New("99999")
+	}
+
+	err = s.WalletSvc.HoldWithdrawal(ctx, &withdrawal)
+	if err != nil {
+		txnCtx.AppendError(err)
+		if err.Error() == "10004" {
+			return nil, err
+		}
+		return nil, errors.New("99999")
+	}
+
+	withdrawalRes, err := s.toResponseDTO(withdrawal)
+	if err != nil {
+		txnCtx.AppendError(err)
+		return nil, errors.New("99999")
+	}
+
+	log.WithFields(log.Fields{
+		"lenderID":       request.LenderID,
+		"withdrawalCode": withdrawal.WithdrawalCode,
+	}).Info("Wallet withdrawal created successfully")
+	return withdrawalRes, nil
+}
+
+// Review approves a pending withdrawal, paying the held amount out, or rejects it, making the held
+// amount available again
+func (s *WalletWithdrawalSvcImpl) Review(ctx context.Context, id int64, request *dto.WalletWithdrawalReviewRequestDTO) (response *dto.WalletWithdrawalResponseDTO, err error) {
+	log.WithFields(log.Fields{
+		"withdrawalID": id,
+		"status":       request.Status,
+		"reviewedBy":   request.ReviewedBy,
+	}).Info("Reviewing wallet withdrawal")
+
+	err = s.Validator.ValidateUpdate(request)
+	if err != nil {
+		log.WithField("withdrawalID", id).Errorf("Validation failed: %s", err)
+		return nil, err
+	}
+
+	txnCtx := dbtxn.Begin(&ctx)
+	defer func() {
+		if commitErr := txnCtx.Commit(); commitErr != nil {
+			log.WithError(commitErr).Error("Transaction commit failed")
+			response, err = nil, errors.New("99999")
+		}
+	}()
+
+	withdrawal, err := s.Repo.GetByIDForUpdate(ctx, id)
+	if err != nil {
+		log.WithField("withdrawalID", id).WithError(err).Error("Failed to get wallet withdrawal")
+		txnCtx.AppendError(err)
+		return nil, errors.New("99999")
+	}
+	if withdrawal == nil {
+		log.WithField("withdrawalID", id).Warn("Wallet withdrawal not found")
+		txnCtx.AppendError(errors.New("withdrawal not found"))
+		return nil, errors.New("10001")
+	}
+
+	isValid := s.Validator.ValidateTransitionStatus(withdrawal.Status, request.Status)
+	if !isValid {
+		txnCtx.AppendError(errors.New("invalid status transition"))
+		return nil, errors.New("10003")
+	}
+
+	now := time.Now()
+	withdrawal.Status = request.Status
+	withdrawal.ReviewedBy = request.ReviewedBy
+	withdrawal.ReviewNote = request.ReviewNote
+	withdrawal.ReviewedAt = &now
+	withdrawal.UpdatedAt = now
+	err = s.Repo.Update(ctx, withdrawal)
+	if err != nil {
+		log.WithField("withdrawalID", id).WithError(err).Error("Failed to update wallet withdrawal")
+		txnCtx.AppendError(err)
+		return nil, errors.New("99999")
+	}
+
+	if withdrawal.Status == enum.WalletWithdrawalApproved {
+		err = s.WalletSvc.CompleteWithdrawal(ctx, withdrawal)
+	} else {
+		err = s.WalletSvc.ReleaseWithdrawal(ctx, withdrawal)
+	}
+	if err != nil {
+		txnCtx.AppendError(err)
+		return nil, errors.New("99999")
+	}
+
+	withdrawalRes, err := s.toResponseDTO(*withdrawal)
+	if err != nil {
+		txnCtx.AppendError(err)
+		return nil, errors.New("99999")
+	}
+
+	log.WithFields(log.Fields{
+		"withdrawalID": id,
+		"status":       withdrawal.Status,
+	}).Info("Wallet withdrawal reviewed successfully")
+	return withdrawalRes, nil
+}
+
+func (s *WalletWithdrawalSvcImpl) GetAll(ctx context.Context, request repo.WalletWithdrawalRequest) ([]dto.WalletWithdrawalResponseDTO, error) {
+	withdrawals, err := s.Repo.GetAll(ctx, request)
+	if err != nil {
+		log.WithField("status", request.Status).WithError(err).Error("Failed to get wallet withdrawals")
+		return nil, errors.New("99999")
+	}
+
+	withdrawalDTOs := []dto.WalletWithdrawalResponseDTO{}
+	for _, withdrawal := range withdrawals {
+		withdrawalRes, err := s.toResponseDTO(withdrawal)
+		if err != nil {
+			return nil, errors.New("99999")
+		}
+		withdrawalDTOs = append(withdrawalDTOs, *withdrawalRes)
+	}
+
+	return withdrawalDTOs, nil
+}
+
+func (s *WalletWithdrawalSvcImpl) toResponseDTO(withdrawal repo.WalletWithdrawal) (*dto.WalletWithdrawalResponseDTO, error) {
+	var withdrawalRes dto.WalletWithdrawalResponseDTO
+	err := mapstructure.Decode(withdrawal, &withdrawalRes)
+	if err != nil {
+		log.WithField("withdrawalID", withdrawal.ID).WithError(err).Error("Failed to map wallet withdrawal to DTO")
+		return nil, err
+	}
+	withdrawalRes.ReviewedAt = withdrawal.ReviewedAt
+	withdrawalRes.CreatedAt = withdrawal.CreatedAt
+	withdrawalRes.UpdatedAt = withdrawal.UpdatedAt
+
+	return &withdrawalRes, nil
+}
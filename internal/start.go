@@ -2,6 +2,7 @@ package internal
 
 import (
 	"github.com/test/loan-service/internal/handler/api"
+	"github.com/test/loan-service/internal/handler/job"
 	"github.com/test/loan-service/internal/handler/kafka"
 	"github.com/test/loan-service/internal/handler/middleware"
 	"net/http"
@@ -34,11 +35,69 @@ func Start(
 	if err = di.Invoke(api.NewLoanDisbursementHandler); err != nil {
 		return err
 	}
+	if err = di.Invoke(api.NewRepaymentHandler); err != nil {
+		return err
+	}
+	if err = di.Invoke(api.NewFundingPolicyHandler); err != nil {
+		return err
+	}
+	if err = di.Invoke(api.NewLedgerHandler); err != nil {
+		return err
+	}
+	if err = di.Invoke(api.NewLenderWalletHandler); err != nil {
+		return err
+	}
+	if err = di.Invoke(api.NewCreditScoringHandler); err != nil {
+		return err
+	}
+	if err = di.Invoke(api.NewLoanPricingHandler); err != nil {
+		return err
+	}
+	if err = di.Invoke(api.NewFeePolicyHandler); err != nil {
+		return err
+	}
+	if err = di.Invoke(api.NewTaxHandler); err != nil {
+		return err
+	}
+	if err = di.Invoke(api.NewInvestmentLimitHandler); err != nil {
+		return err
+	}
+	if err = di.Invoke(api.NewAutoInvestHandler); err != nil {
+		return err
+	}
+	if err = di.Invoke(api.NewFundingMarketHandler); err != nil {
+		return err
+	}
+	if err = di.Invoke(api.NewAgreementHandler); err != nil {
+		return err
+	}
+	if err = di.Invoke(api.NewSignatureHandler); err != nil {
+		return err
+	}
+	if err = di.Invoke(api.NewApprovalLevelHandler); err != nil {
+		return err
+	}
+	if err = di.Invoke(api.NewDocumentRequirementHandler); err != nil {
+		return err
+	}
+	if err = di.Invoke(api.NewDocumentHandler); err != nil {
+		return err
+	}
+	if err = di.Invoke(api.NewBorrowerHandler); err != nil {
+		return err
+	}
+	if err = di.Invoke(api.NewLenderHandler); err != nil {
+		return err
+	}
 
 	if err = di.Invoke(kafka.NewKafkaHandler); err != nil {
 		return err
 	}
 
+	if err = di.Invoke(job.NewLoanExpiryJob); err != nil {
+		return err
+	}
+
 	return e.StartServer(&http.Server{
 		Addr:         cfg.Address,
 		ReadTimeout:  cfg.ReadTimeout,
@@ -0,0 +1,86 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+// LocalStore keeps documents as files below a directory, the documents are served by the
+// application under the base URL
+type LocalStore struct {
+	dir     string
+	baseURL string
+}
+
+func NewLocalStore(dir string, baseURL string) *LocalStore {
+	return &LocalStore{
+		dir:     dir,
+		baseURL: strings.TrimRight(baseURL, "/"),
+	}
+}
+
+// Put writes the document and returns its URL. The file is written under a temporary name first so
+// a reader never sees half a document.
+func (s *LocalStore) Put(ctx context.Context, key string, content []byte) (string, error) {
+	filePath, err := s.filePath(key)
+	if err != nil {
+		return "", err
+	}
+
+	err = os.MkdirAll(filepath.Dir(filePath), 0o755)
+	if err != nil {
+		return "", fmt.Errorf("failed to create document directory: %v", err)
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(filePath), ".tmp-*")
+	if err != nil {
+		return "", fmt.Errorf("failed to create document file: %v", err)
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err = tmp.Write(content); err != nil {
+		tmp.Close()
+		return "", fmt.Errorf("failed to write document: %v", err)
+	}
+	if err = tmp.Close(); err != nil {
+		return "", fmt.Errorf("failed to write document: %v", err)
+	}
+	if err = os.Rename(tmp.Name(), filePath); err != nil {
+		return "", fmt.Errorf("failed to store document: %v", err)
+	}
+
+	return s.baseURL + "/" + path.Clean(key), nil
+}
+
+// Get reads a document, ErrNotFound when there is none under the key
+func (s *LocalStore) Get(ctx context.Context, key string) ([]byte, error) {
+	filePath, err := s.filePath(key)
+	if err != nil {
+		return nil, err
+	}
+
+	content, err := os.ReadFile(filePath)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, ErrNotFound
+		}
+		return nil, fmt.Errorf("failed to read document: %v", err)
+	}
+
+	return content, nil
+}
+
+// filePath maps the key into the directory, keys leaving the directory are rejected
+func (s *LocalStore) filePath(key string) (string, error) {
+	cleaned := path.Clean("/" + key)
+	if cleaned == "/" || cleaned != "/"+key {
+		return "", fmt.Errorf("invalid document key %q", key)
+	}
+
+	return filepath.Join(s.dir, filepath.FromSlash(cleaned)), nil
+}
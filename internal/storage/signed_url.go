@@ -0,0 +1,66 @@
+package storage
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var (
+	// ErrInvalidSignature is returned by Verify when the signature was not made for the document and expiry
+	ErrInvalidSignature = errors.New("invalid download signature")
+	// ErrExpired is returned by Verify when the download URL is past its expiry
+	ErrExpired = errors.New("download url expired")
+)
+
+// URLSigner makes download URLs of stored documents that are only valid for a limited time. The
+// signature is an HMAC-SHA256 of the document ID and the expiry, so a URL can not be reused for
+// another document or extended.
+type URLSigner struct {
+	secret  []byte
+	baseURL string
+	ttl     time.Duration
+}
+
+func NewURLSigner(secret string, baseURL string, ttl time.Duration) *URLSigner {
+	return &URLSigner{
+		secret:  []byte(secret),
+		baseURL: strings.TrimRight(baseURL, "/"),
+		ttl:     ttl,
+	}
+}
+
+// DocumentURL returns the URL of the document record, requesting it returns a fresh download URL
+func (s *URLSigner) DocumentURL(id int64) string {
+	return fmt.Sprintf("%s/%d", s.baseURL, id)
+}
+
+// Sign returns the download URL of the document and the time it expires at
+func (s *URLSigner) Sign(id int64, now time.Time) (string, time.Time) {
+	expiresAt := now.Add(s.ttl)
+	expires := expiresAt.Unix()
+	return fmt.Sprintf("%s/%d/download?expires=%d&signature=%s", s.baseURL, id, expires, s.signature(id, expires)), time.Unix(expires, 0)
+}
+
+// Verify checks the signature and expiry of a download URL of the document
+func (s *URLSigner) Verify(id int64, expires int64, signature string, now time.Time) error {
+	expected := s.signature(id, expires)
+	if !hmac.Equal([]byte(expected), []byte(strings.ToLower(signature))) {
+		return ErrInvalidSignature
+	}
+	if now.Unix() > expires {
+		return ErrExpired
+	}
+	return nil
+}
+
+func (s *URLSigner) signature(id int64, expires int64) string {
+	mac := hmac.New(sha256.New, s.secret)
+	mac.Write([]byte(strconv.FormatInt(id, 10) + ":" + strconv.FormatInt(expires, 10)))
+	return hex.EncodeToString(mac.Sum(nil))
+}
@@ -0,0 +1,28 @@
+package storage
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+)
+
+//go:generate mockgen -source=$GOFILE -destination=$PROJ/internal/generated/mock/mock_$GOPACKAGE/$GOFILE
+
+// ErrNotFound is returned by Get when no document is stored under the key
+var ErrNotFound = errors.New("document not found")
+
+// DocumentStore keeps generated documents such as agreements. The key is a slash separated path
+// chosen by the caller and unique to the document, Put returns the URL the document can be
+// downloaded from. Implementations can keep the documents on the local filesystem or in an object
+// storage.
+type DocumentStore interface {
+	Put(ctx context.Context, key string, content []byte) (string, error)
+	Get(ctx context.Context, key string) ([]byte, error)
+}
+
+// Checksum returns the SHA-256 of a document, hex encoded
+func Checksum(content []byte) string {
+	sum := sha256.Sum256(content)
+	return hex.EncodeToString(sum[:])
+}
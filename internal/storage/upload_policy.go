@@ -0,0 +1,21 @@
+package storage
+
+import "strings"
+
+// UploadPolicy limits the files that can be uploaded to the document store
+type UploadPolicy struct {
+	MaxSize      int64    // Maximum file size in bytes
+	ContentTypes []string // Content types accepted, matched against the type detected from the content
+}
+
+// AllowsContentType reports whether files of the content type can be uploaded, parameters such as
+// the charset are ignored
+func (p *UploadPolicy) AllowsContentType(contentType string) bool {
+	mediaType := strings.TrimSpace(strings.SplitN(contentType, ";", 2)[0])
+	for _, allowed := range p.ContentTypes {
+		if strings.EqualFold(strings.TrimSpace(allowed), mediaType) {
+			return true
+		}
+	}
+	return false
+}
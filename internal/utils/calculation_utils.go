@@ -1,9 +1,23 @@
 package utils
 
-func CalculateInterest(principal float64, annualRate float64, tenureMonths int64) float64 {
+import (
+	"github.com/test/loan-service/internal/money"
+	"math"
+)
+
+// CalculateInterest returns the simple interest of principal for annualRate percent over tenureMonths.
+// The rate is taken in basis points so the calculation stays exact, the result is rounded half up to
+// a sen.
+func CalculateInterest(principal money.Amount, annualRate float64, tenureMonths int64) money.Amount {
 	// Menghitung bunga tahunan berdasarkan bunga sederhana
-	tenureYears := float64(tenureMonths) / 12 // Konversi tenure dari bulan ke tahun
-	interest := principal * (annualRate / 100) * tenureYears
+	rateBasisPoints := int64(math.Round(annualRate * 100))
+	// principal * (rate / 100 / 100) * (tenure / 12), tenure dikonversi dari bulan ke tahun
+	return principal.MulRat(rateBasisPoints*tenureMonths, 100*100*12, money.HalfUp)
+}
 
-	return interest
+// CalculatePercentage returns percentage percent of amount, taken in basis points like
+// CalculateInterest and rounded half up to a sen.
+func CalculatePercentage(amount money.Amount, percentage float64) money.Amount {
+	basisPoints := int64(math.Round(percentage * 100))
+	return amount.MulRat(basisPoints, 100*100, money.HalfUp)
 }